@@ -0,0 +1,273 @@
+// Package client is a Go client of the soroban-rpc JSON-RPC API.
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/jhttp"
+)
+
+// NodeDegradedCode is the JSON-RPC error code returned by methods which depend on
+// up-to-date ledger state when the node is materially behind the network
+// (the call is retried, since the node is expected to catch up).
+const NodeDegradedCode = -32007
+
+const (
+	defaultMaxRetries   = 3
+	defaultRetryBackoff = 250 * time.Millisecond
+	maxRetryBackoff     = 5 * time.Second
+)
+
+// Options configure the client
+type Options struct {
+	// HTTPClient sends the requests (http.DefaultClient if nil)
+	HTTPClient *http.Client
+	// MaxRetries is the number of times a failed call is retried. Calls are only retried
+	// when the request couldn't be delivered (e.g. the connection was refused or the server
+	// was overloaded) or when the node was degraded. 0 selects the default (3), negative values disable retries.
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, which doubles after every retry (250ms if 0)
+	RetryBackoff time.Duration
+}
+
+// Client calls the methods of a soroban-rpc server. It's safe for concurrent use.
+type Client struct {
+	url          string
+	httpClient   *http.Client
+	maxRetries   int
+	retryBackoff time.Duration
+
+	mx  sync.RWMutex
+	cli *jrpc2.Client
+}
+
+// NewClient creates a client of the server at the given URL (e.g. http://localhost:8000),
+// options can be nil.
+func NewClient(url string, options *Options) *Client {
+	c := &Client{
+		url:          url,
+		maxRetries:   defaultMaxRetries,
+		retryBackoff: defaultRetryBackoff,
+	}
+	if options != nil {
+		c.httpClient = options.HTTPClient
+		if options.MaxRetries != 0 {
+			c.maxRetries = max(options.MaxRetries, 0)
+		}
+		if options.RetryBackoff != 0 {
+			c.retryBackoff = options.RetryBackoff
+		}
+	}
+	c.refreshClient(nil)
+	return c
+}
+
+// refreshClient replaces the underlying client (unless it was already replaced)
+func (c *Client) refreshClient(failed *jrpc2.Client) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	if c.cli != failed {
+		return
+	}
+	if c.cli != nil {
+		_ = c.cli.Close()
+	}
+	var channelOptions *jhttp.ChannelOptions
+	if c.httpClient != nil {
+		channelOptions = &jhttp.ChannelOptions{Client: c.httpClient}
+	}
+	c.cli = jrpc2.NewClient(jhttp.NewChannel(c.url, channelOptions), nil)
+}
+
+// Close releases the resources of the client
+func (c *Client) Close() error {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+	return c.cli.Close()
+}
+
+func (c *Client) call(ctx context.Context, method string, params, result any) error {
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		c.mx.RLock()
+		cli := c.cli
+		c.mx.RUnlock()
+		err := cli.CallResult(ctx, method, params, result)
+		if err == nil {
+			return nil
+		}
+		// The client cannot be used after failing to deliver a request (the failure is
+		// reported as an internal error), see https://github.com/creachadair/jrpc2/issues/118
+		undelivered := cli.IsStopped()
+		if undelivered {
+			c.refreshClient(cli)
+		}
+		if attempt >= c.maxRetries || !retryable(ctx, err, undelivered) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, maxRetryBackoff)
+	}
+}
+
+func retryable(ctx context.Context, err error, undelivered bool) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if undelivered {
+		return true
+	}
+	var rpcErr *jrpc2.Error
+	return errors.As(err, &rpcErr) && rpcErr.Code == NodeDegradedCode
+}
+
+func (c *Client) GetHealth(ctx context.Context) (GetHealthResponse, error) {
+	var result GetHealthResponse
+	err := c.call(ctx, "getHealth", nil, &result)
+	return result, err
+}
+
+func (c *Client) GetEvents(ctx context.Context, request GetEventsRequest) (GetEventsResponse, error) {
+	var result GetEventsResponse
+	err := c.call(ctx, "getEvents", request, &result)
+	return result, err
+}
+
+func (c *Client) GetEvent(ctx context.Context, id string) (GetEventResponse, error) {
+	var result GetEventResponse
+	err := c.call(ctx, "getEvent", GetEventRequest{ID: id}, &result)
+	return result, err
+}
+
+func (c *Client) GetNetwork(ctx context.Context) (GetNetworkResponse, error) {
+	var result GetNetworkResponse
+	err := c.call(ctx, "getNetwork", nil, &result)
+	return result, err
+}
+
+func (c *Client) GetVersionInfo(ctx context.Context) (GetVersionInfoResponse, error) {
+	var result GetVersionInfoResponse
+	err := c.call(ctx, "getVersionInfo", nil, &result)
+	return result, err
+}
+
+func (c *Client) GetLatestLedger(ctx context.Context) (GetLatestLedgerResponse, error) {
+	var result GetLatestLedgerResponse
+	err := c.call(ctx, "getLatestLedger", nil, &result)
+	return result, err
+}
+
+// GetLatestLedgerWithHeader obtains the latest ledger, including the details of its header.
+func (c *Client) GetLatestLedgerWithHeader(ctx context.Context) (GetLatestLedgerResponse, error) {
+	var result GetLatestLedgerResponse
+	err := c.call(ctx, "getLatestLedger", GetLatestLedgerRequest{IncludeHeader: true}, &result)
+	return result, err
+}
+
+// GetLedgerEntry obtains a single ledger entry.
+//
+// Deprecated: use GetLedgerEntries instead.
+func (c *Client) GetLedgerEntry(ctx context.Context, request GetLedgerEntryRequest) (GetLedgerEntryResponse, error) {
+	var result GetLedgerEntryResponse
+	err := c.call(ctx, "getLedgerEntry", request, &result)
+	return result, err
+}
+
+func (c *Client) GetLedgerEntries(ctx context.Context, request GetLedgerEntriesRequest) (GetLedgerEntriesResponse, error) {
+	var result GetLedgerEntriesResponse
+	err := c.call(ctx, "getLedgerEntries", request, &result)
+	return result, err
+}
+
+func (c *Client) GetContractEntries(ctx context.Context, request GetContractEntriesRequest) (GetContractEntriesResponse, error) {
+	var result GetContractEntriesResponse
+	err := c.call(ctx, "getContractEntries", request, &result)
+	return result, err
+}
+
+func (c *Client) GetTransaction(ctx context.Context, request GetTransactionRequest) (GetTransactionResponse, error) {
+	var result GetTransactionResponse
+	err := c.call(ctx, "getTransaction", request, &result)
+	return result, err
+}
+
+func (c *Client) GetTransactions(ctx context.Context, request GetTransactionsRequest) (GetTransactionsResponse, error) {
+	var result GetTransactionsResponse
+	err := c.call(ctx, "getTransactions", request, &result)
+	return result, err
+}
+
+func (c *Client) GetContractTransactions(ctx context.Context, request GetContractTransactionsRequest) (GetTransactionsResponse, error) {
+	var result GetTransactionsResponse
+	err := c.call(ctx, "getContractTransactions", request, &result)
+	return result, err
+}
+
+func (c *Client) SendTransaction(ctx context.Context, request SendTransactionRequest) (SendTransactionResponse, error) {
+	var result SendTransactionResponse
+	err := c.call(ctx, "sendTransaction", request, &result)
+	return result, err
+}
+
+func (c *Client) SimulateTransaction(ctx context.Context, request SimulateTransactionRequest) (SimulateTransactionResponse, error) {
+	var result SimulateTransactionResponse
+	err := c.call(ctx, "simulateTransaction", request, &result)
+	return result, err
+}
+
+func (c *Client) GetTokenMetadata(ctx context.Context, request GetTokenMetadataRequest) (GetTokenMetadataResponse, error) {
+	var result GetTokenMetadataResponse
+	err := c.call(ctx, "getTokenMetadata", request, &result)
+	return result, err
+}
+
+func (c *Client) GetFeeStats(ctx context.Context) (GetFeeStatsResponse, error) {
+	var result GetFeeStatsResponse
+	err := c.call(ctx, "getFeeStats", nil, &result)
+	return result, err
+}
+
+func (c *Client) GetFeeBump(ctx context.Context, request GetFeeBumpRequest) (GetFeeBumpResponse, error) {
+	var result GetFeeBumpResponse
+	err := c.call(ctx, "getFeeBump", request, &result)
+	return result, err
+}
+
+func (c *Client) GetSorobanConfig(ctx context.Context) (GetSorobanConfigResponse, error) {
+	var result GetSorobanConfigResponse
+	err := c.call(ctx, "getSorobanConfig", nil, &result)
+	return result, err
+}
+
+// PollTransaction calls getTransaction every interval until the transaction is found
+// (either successful or failed), the context is done or the call fails (returning the
+// latest response obtained).
+func (c *Client) PollTransaction(ctx context.Context, hash string, interval time.Duration) (GetTransactionResponse, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var last GetTransactionResponse
+	for {
+		response, err := c.GetTransaction(ctx, GetTransactionRequest{Hash: hash})
+		if err != nil {
+			return last, err
+		}
+		if response.Status != TransactionStatusNotFound {
+			return response, nil
+		}
+		last = response
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/handler"
+	"github.com/creachadair/jrpc2/jhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer serves the given methods, failing (with HTTP 503) the first unavailableRequests requests
+func newTestServer(t *testing.T, methods handler.Map, unavailableRequests int32) (*httptest.Server, *atomic.Int32) {
+	bridge := jhttp.NewBridge(methods, nil)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= unavailableRequests {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		bridge.ServeHTTP(w, r)
+	}))
+	t.Cleanup(func() {
+		server.Close()
+		bridge.Close()
+	})
+	return server, &requests
+}
+
+func TestClientCall(t *testing.T) {
+	server, requests := newTestServer(t, handler.Map{
+		"getLatestLedger": handler.New(func(context.Context) (map[string]any, error) {
+			return map[string]any{
+				"id":              "abcd",
+				"protocolVersion": 21,
+				"sequence":        1234,
+				"latestLedger":    1234,
+				// stringified, as sent by the server
+				"latestLedgerCloseTime": "1700000000",
+			}, nil
+		}),
+		"getTransaction": handler.New(func(context.Context, GetTransactionRequest) (GetTransactionResponse, error) {
+			return GetTransactionResponse{}, &jrpc2.Error{Code: -32602, Message: "invalid hash"}
+		}),
+	}, 2)
+	client := NewClient(server.URL, &Options{RetryBackoff: time.Millisecond})
+	defer client.Close()
+
+	// the unavailable responses are retried
+	ledger, err := client.GetLatestLedger(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "abcd", ledger.Hash)
+	assert.Equal(t, uint32(21), ledger.ProtocolVersion)
+	assert.Equal(t, uint32(1234), ledger.Sequence)
+	assert.Equal(t, int64(1700000000), ledger.LatestLedgerCloseTime)
+	assert.Equal(t, int32(3), requests.Load())
+
+	// errors returned by the server aren't
+	_, err = client.GetTransaction(context.Background(), GetTransactionRequest{Hash: "invalid"})
+	var rpcErr *jrpc2.Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, "invalid hash", rpcErr.Message)
+	assert.Equal(t, int32(4), requests.Load())
+}
+
+func TestClientRetriesDisabled(t *testing.T) {
+	server, requests := newTestServer(t, handler.Map{
+		"getNetwork": handler.New(func(context.Context) (GetNetworkResponse, error) {
+			return GetNetworkResponse{Passphrase: "passphrase"}, nil
+		}),
+	}, 1)
+	client := NewClient(server.URL, &Options{MaxRetries: -1})
+	defer client.Close()
+
+	_, err := client.GetNetwork(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, int32(1), requests.Load())
+
+	// the client recovers from the failure
+	network, err := client.GetNetwork(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "passphrase", network.Passphrase)
+}
+
+func TestPollTransaction(t *testing.T) {
+	var calls atomic.Int32
+	server, _ := newTestServer(t, handler.Map{
+		"getTransaction": handler.New(func(_ context.Context, request GetTransactionRequest) (GetTransactionResponse, error) {
+			if request.Hash != "abcd" || calls.Add(1) < 3 {
+				return GetTransactionResponse{Status: TransactionStatusNotFound}, nil
+			}
+			return GetTransactionResponse{Status: TransactionStatusSuccess, Ledger: 10}, nil
+		}),
+	}, 0)
+	client := NewClient(server.URL, nil)
+	defer client.Close()
+
+	response, err := client.PollTransaction(context.Background(), "abcd", time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, TransactionStatusSuccess, response.Status)
+	assert.Equal(t, uint32(10), response.Ledger)
+	assert.Equal(t, int32(3), calls.Load())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	response, err = client.PollTransaction(ctx, "unknown", time.Millisecond)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, TransactionStatusNotFound, response.Status)
+}
@@ -0,0 +1,754 @@
+package client
+
+// The types below mirror the request and response types of the server
+// (cmd/soroban-rpc/internal/methods), with which they are kept in sync by tests.
+
+const (
+	// TransactionStatusSuccess indicates the transaction was included in the ledger and
+	// it was executed without errors.
+	TransactionStatusSuccess = "SUCCESS"
+	// TransactionStatusNotFound indicates the transaction was not found in Soroban-RPC's
+	// transaction store.
+	TransactionStatusNotFound = "NOT_FOUND"
+	// TransactionStatusFailed indicates the transaction was included in the ledger and
+	// it was executed with an error.
+	TransactionStatusFailed = "FAILED"
+)
+
+// The XDR fields of transactions which can be selected through XDRFields
+const (
+	XDRFieldEnvelope         = "envelope"
+	XDRFieldResult           = "result"
+	XDRFieldResultMeta       = "resultMeta"
+	XDRFieldDiagnosticEvents = "diagnosticEvents"
+	// XDRFieldEvents is only supported by GetTransaction
+	XDRFieldEvents = "events"
+)
+
+const (
+	EventTypeSystem     = "system"
+	EventTypeContract   = "contract"
+	EventTypeDiagnostic = "diagnostic"
+)
+
+type LedgerRangeResponse struct {
+	// LatestLedger is the latest ledger stored in Soroban-RPC.
+	LatestLedger uint32 `json:"latestLedger"`
+	// LatestLedgerCloseTime is the unix timestamp of when the latest ledger was closed.
+	LatestLedgerCloseTime int64 `json:"latestLedgerCloseTime,string"`
+	// OldestLedger is the oldest ledger stored in Soroban-RPC.
+	OldestLedger uint32 `json:"oldestLedger"`
+	// OldestLedgerCloseTime is the unix timestamp of when the oldest ledger was closed.
+	OldestLedgerCloseTime int64 `json:"oldestLedgerCloseTime,string"`
+}
+
+type GetHealthResponse struct {
+	Status string `json:"status"`
+	LedgerRangeResponse
+	LedgerRetentionWindow uint32 `json:"ledgerRetentionWindow"`
+	// PreflightError is present when simulateTransaction is unavailable because the
+	// preflight library failed its self-test or doesn't support the network protocol.
+	PreflightError string `json:"preflightError,omitempty"`
+	// DatabaseSize is the size (in bytes) of the database
+	DatabaseSize uint64 `json:"databaseSize,omitempty"`
+	// IngestionLatency is the time (in seconds) elapsed since the latest ingested ledger closed
+	IngestionLatency int64 `json:"ingestionLatency"`
+	// CoreState is the state reported by captive core (e.g. "Synced!" or "Catching up")
+	CoreState string `json:"coreState,omitempty"`
+	// ProtocolVersion is the protocol version of the latest ledger
+	ProtocolVersion uint32 `json:"protocolVersion,omitempty"`
+	// ProtocolUpgrades reports the scheduled upgrades above the protocol version of the latest ledger
+	ProtocolUpgrades []ProtocolUpgradeStatus `json:"protocolUpgrades,omitempty"`
+	// Stores reports the ledgers held by each of the stores
+	Stores HealthStores `json:"stores"`
+}
+
+// ProtocolUpgradeStatus is a scheduled protocol upgrade
+type ProtocolUpgradeStatus struct {
+	Version uint32 `json:"version"`
+	// Time is the scheduled time (in unix seconds) of the upgrade
+	Time int64 `json:"time"`
+	// Supported tells whether the node will keep working after the upgrade without being redeployed
+	Supported bool `json:"supported"`
+	// Error explains why the protocol isn't supported
+	Error string `json:"error,omitempty"`
+}
+
+type HealthStores struct {
+	Ledgers LedgerRangeResponse  `json:"ledgers"`
+	Events  *LedgerRangeResponse `json:"events,omitempty"`
+}
+
+type GetEventsRequest struct {
+	StartLedger uint32        `json:"startLedger,omitempty"`
+	Filters     []EventFilter `json:"filters"`
+	// ContractEventsOnly restricts the results to the events of type contract emitted by successful
+	// contract calls, excluding system and diagnostic events (as well as the events of failed calls).
+	ContractEventsOnly bool `json:"contractEventsOnly,omitempty"`
+	// IncludeFailed includes the diagnostic events of failed transactions
+	IncludeFailed bool               `json:"includeFailed,omitempty"`
+	Pagination    *PaginationOptions `json:"pagination,omitempty"`
+}
+
+type EventFilter struct {
+	// EventType is a comma-separated list of event types (e.g. "contract,system")
+	EventType   string        `json:"type,omitempty"`
+	ContractIDs []string      `json:"contractIds,omitempty"`
+	Topics      []TopicFilter `json:"topics,omitempty"`
+}
+
+// TopicFilter is made of segments which are either a wildcard ("*" matching one
+// segment or, only as the last segment, "**" matching zero or more segments)
+// or a base64-encoded xdr.ScVal
+type TopicFilter []string
+
+type PaginationOptions struct {
+	Cursor string `json:"cursor,omitempty"`
+	Limit  uint   `json:"limit,omitempty"`
+}
+
+type EventInfo struct {
+	EventType                string   `json:"type"`
+	Ledger                   int32    `json:"ledger"`
+	LedgerClosedAt           string   `json:"ledgerClosedAt"`
+	ContractID               string   `json:"contractId"`
+	ID                       string   `json:"id"`
+	PagingToken              string   `json:"pagingToken"`
+	Topic                    []string `json:"topic"`
+	Value                    string   `json:"value"`
+	InSuccessfulContractCall bool     `json:"inSuccessfulContractCall"`
+	TransactionHash          string   `json:"txHash"`
+	// InSuccessfulTransaction is false for the diagnostic events of failed transactions
+	InSuccessfulTransaction bool `json:"inSuccessfulTransaction"`
+	// MatchedFilters are the (zero-based) indexes of the request filters matched by the event,
+	// only present when the request has several filters
+	MatchedFilters []int `json:"matchedFilters,omitempty"`
+}
+
+type GetEventsResponse struct {
+	Events []EventInfo `json:"events"`
+	// Cursor is the paging token of the last returned event, to be used as the cursor of the next page
+	Cursor string `json:"cursor,omitempty"`
+	// HasMore tells whether more events match the request after the returned ones
+	// (it may also be set, followed by an empty page, if the server stopped looking ahead)
+	HasMore bool `json:"hasMore"`
+	// EstimatedTotalCount estimates the amount of events matching the request from the start of the
+	// page until the latest ledger (including the returned ones), it's exact if HasMore is false
+	EstimatedTotalCount uint64 `json:"estimatedTotalCount"`
+	LedgerRangeResponse
+}
+
+type GetEventRequest struct {
+	// ID is the id (or, equivalently, the paging token) of the event, as returned by GetEvents
+	ID string `json:"id"`
+}
+
+type GetEventResponse struct {
+	Event EventInfo `json:"event"`
+	LedgerRangeResponse
+}
+
+type GetNetworkResponse struct {
+	FriendbotURL    string `json:"friendbotUrl,omitempty"`
+	Passphrase      string `json:"passphrase"`
+	ProtocolVersion int    `json:"protocolVersion"`
+}
+
+type GetVersionInfoResponse struct {
+	Version            string `json:"version"`
+	CommitHash         string `json:"commit_hash"`          //nolint:tagliatelle
+	BuildTimestamp     string `json:"build_time_stamp"`     //nolint:tagliatelle
+	CaptiveCoreVersion string `json:"captive_core_version"` //nolint:tagliatelle
+	ProtocolVersion    uint32 `json:"protocol_version"`     //nolint:tagliatelle
+	// Passphrase and FriendbotURL describe the network, like in getNetwork
+	Passphrase   string `json:"passphrase"`
+	FriendbotURL string `json:"friendbotUrl,omitempty"`
+}
+
+type GetLatestLedgerRequest struct {
+	// IncludeHeader adds the details of the ledger header to the response.
+	IncludeHeader bool `json:"includeHeader,omitempty"`
+}
+
+type GetLatestLedgerResponse struct {
+	// Hash of the latest ledger as a hex-encoded string
+	Hash string `json:"id"`
+	// Stellar Core protocol version associated with the ledger.
+	ProtocolVersion uint32 `json:"protocolVersion"`
+	// Sequence number of the latest ledger.
+	Sequence uint32 `json:"sequence"`
+	LedgerRangeResponse
+
+	// The fields below are only present if IncludeHeader is set in the request.
+
+	// CloseTime is the unix timestamp of when the ledger was closed.
+	CloseTime int64 `json:"closeTime,string,omitempty"`
+	// BaseFee is the base fee (in stroops) of the ledger.
+	BaseFee uint32 `json:"baseFee,omitempty"`
+	// BaseReserve is the base reserve (in stroops) of the ledger.
+	BaseReserve uint32 `json:"baseReserve,omitempty"`
+	// TotalCoins is the total number of stroops in existence.
+	TotalCoins int64 `json:"totalCoins,string,omitempty"`
+	// HeaderXDR is the LedgerHeader XDR value.
+	HeaderXDR string `json:"headerXdr,omitempty"`
+}
+
+type GetLedgerEntryRequest struct {
+	Key string `json:"key"`
+}
+
+type GetLedgerEntryResponse struct {
+	XDR                string `json:"xdr"`
+	LastModifiedLedger uint32 `json:"lastModifiedLedgerSeq"`
+	LedgerRangeResponse
+	// The ledger sequence until the entry is live, available for entries that have associated ttl ledger entries.
+	LiveUntilLedgerSeq *uint32 `json:"LiveUntilLedgerSeq,omitempty"` //nolint:tagliatelle
+}
+
+type GetLedgerEntriesRequest struct {
+	// Keys are base64-encoded xdr.LedgerKey values
+	Keys []string `json:"keys"`
+	// IncludeArchived includes the entries evicted into the hot archive and the archival status of all the entries
+	IncludeArchived bool `json:"includeArchived,omitempty"`
+}
+
+// The archival statuses of LedgerEntryResult
+const (
+	LedgerEntryStatusLive     = "live"
+	LedgerEntryStatusArchived = "archived"
+	LedgerEntryStatusEvicted  = "evicted"
+)
+
+type LedgerEntryResult struct {
+	// Original request key matching this LedgerEntryResult.
+	Key string `json:"key"`
+	// Ledger entry data encoded in base 64.
+	XDR string `json:"xdr"`
+	// Last modified ledger for this entry.
+	LastModifiedLedger uint32 `json:"lastModifiedLedgerSeq"`
+	// The ledger sequence until the entry is live, available for entries that have associated ttl ledger entries.
+	LiveUntilLedgerSeq *uint32 `json:"liveUntilLedgerSeq,omitempty"`
+	// ArchivalStatus is one of LedgerEntryStatusLive, LedgerEntryStatusArchived (the TTL has expired) or
+	// LedgerEntryStatusEvicted (the entry was moved to the hot archive), only present if IncludeArchived was requested.
+	ArchivalStatus string `json:"archivalStatus,omitempty"`
+}
+
+type GetLedgerEntriesResponse struct {
+	// All found ledger entries.
+	Entries []LedgerEntryResult `json:"entries"`
+	LedgerRangeResponse
+}
+
+type ContractEntriesPaginationOptions struct {
+	// Cursor is the key of the last entry of the previous page
+	Cursor string `json:"cursor,omitempty"`
+	Limit  uint   `json:"limit,omitempty"`
+}
+
+type GetContractEntriesRequest struct {
+	// ContractID is the (C...) address of the contract whose storage is listed
+	ContractID string                            `json:"contractId"`
+	Pagination *ContractEntriesPaginationOptions `json:"pagination,omitempty"`
+}
+
+type GetContractEntriesResponse struct {
+	// Entries are the contract data entries of the contract (including its instance), in storage order
+	Entries []LedgerEntryResult `json:"entries"`
+	LedgerRangeResponse
+	// Cursor is used for fetching the next page, it is empty once all the entries have been returned
+	Cursor string `json:"cursor,omitempty"`
+}
+
+type GetTransactionRequest struct {
+	// Hash is the hex-encoded hash of the transaction
+	Hash string `json:"hash"`
+	// XDRFields restricts the XDR fields included in the response (see XDRFieldEnvelope and the like),
+	// all of them are included if empty
+	XDRFields []string `json:"xdrFields,omitempty"`
+}
+
+type GetTransactionResponse struct {
+	// Status is one of: TransactionStatusSuccess, TransactionStatusNotFound or TransactionStatusFailed.
+	Status string `json:"status"`
+	LedgerRangeResponse
+
+	// The fields below are only present if Status is not TransactionStatusNotFound.
+
+	// ApplicationOrder is the index of the transaction among all the transactions
+	// for that ledger.
+	ApplicationOrder int32 `json:"applicationOrder,omitempty"`
+	// FeeBump indicates whether the transaction is a feebump transaction
+	FeeBump bool `json:"feeBump,omitempty"`
+	// FeeBumpDetails describes the inner transaction, only present if FeeBump is true.
+	FeeBumpDetails *FeeBumpDetails `json:"feeBumpDetails,omitempty"`
+	// EnvelopeXdr is the TransactionEnvelope XDR value.
+	EnvelopeXdr string `json:"envelopeXdr,omitempty"`
+	// ResultXdr is the TransactionResult XDR value.
+	ResultXdr string `json:"resultXdr,omitempty"`
+	// ResultMetaXdr is the TransactionMeta XDR value.
+	ResultMetaXdr string `json:"resultMetaXdr,omitempty"`
+
+	// Ledger is the sequence of the ledger which included the transaction.
+	Ledger uint32 `json:"ledger,omitempty"`
+	// LedgerCloseTime is the unix timestamp of when the transaction was included in the ledger.
+	LedgerCloseTime int64 `json:"createdAt,string,omitempty"`
+
+	// DiagnosticEventsXDR is a base64-encoded slice of xdr.DiagnosticEvent,
+	// present only if Status is equal to TransactionStatusFailed.
+	DiagnosticEventsXDR []string `json:"diagnosticEventsXdr,omitempty"`
+	// Events are the events (and the return value) of Soroban transactions, decoded from ResultMetaXdr.
+	Events *TransactionEvents `json:"events,omitempty"`
+
+	// PeerHint is the (unverified) inclusion of the transaction reported by a peer node.
+	// It is only present if Status is TransactionStatusNotFound.
+	PeerHint *PeerTransactionHint `json:"peerHint,omitempty"`
+	// PeerSubmissionStatus is the sendTransaction status reported by a peer node
+	// which submitted the transaction. It is only present if Status is TransactionStatusNotFound.
+	PeerSubmissionStatus string `json:"peerSubmissionStatus,omitempty"`
+}
+
+// PeerTransactionHint is the inclusion of a transaction, as ingested by a peer node
+type PeerTransactionHint struct {
+	// Status is either TransactionStatusSuccess or TransactionStatusFailed.
+	Status string `json:"status"`
+	// Ledger is the sequence of the ledger which included the transaction.
+	Ledger uint32 `json:"ledger"`
+	// LedgerCloseTime is the unix timestamp of when the transaction was included in the ledger.
+	LedgerCloseTime int64 `json:"createdAt,string"`
+}
+
+// FeeBumpDetails are the details of the inner transaction of a fee-bump transaction
+type FeeBumpDetails struct {
+	// FeeAccount is the account paying the fee of the fee-bump transaction.
+	FeeAccount string `json:"feeAccount"`
+	// InnerTransactionHash is the hex-encoded hash of the inner transaction.
+	InnerTransactionHash string `json:"innerTransactionHash"`
+	// InnerEnvelopeXdr is the TransactionEnvelope XDR value of the inner transaction.
+	InnerEnvelopeXdr string `json:"innerEnvelopeXdr,omitempty"`
+	// InnerResultXdr is the InnerTransactionResult XDR value,
+	// absent if the fee-bump failed before applying the inner transaction.
+	InnerResultXdr string `json:"innerResultXdr,omitempty"`
+}
+
+// TransactionEvents are the outputs of a Soroban transaction, separated from its TransactionMeta
+type TransactionEvents struct {
+	// ContractEventsXDR is a base64-encoded slice of the xdr.ContractEvent emitted by the transaction
+	ContractEventsXDR []string `json:"contractEventsXdr"`
+	// DiagnosticEventsXDR is a base64-encoded slice of the xdr.DiagnosticEvent emitted by the
+	// transaction (only if diagnostic events were enabled in the node's captive core)
+	DiagnosticEventsXDR []string `json:"diagnosticEventsXdr"`
+	// ReturnValueXDR is the xdr.ScVal returned by the invoked contract function
+	ReturnValueXDR string `json:"returnValueXdr,omitempty"`
+}
+
+type GetTransactionsRequest struct {
+	StartLedger uint32                         `json:"startLedger"`
+	Filters     *TransactionsFilters           `json:"filters,omitempty"`
+	Pagination  *TransactionsPaginationOptions `json:"pagination,omitempty"`
+	// XDRFields restricts the XDR fields included in the transactions (see XDRFieldEnvelope and the like),
+	// all of them are included if empty
+	XDRFields []string `json:"xdrFields,omitempty"`
+}
+
+// TransactionsFilters restricts the transactions returned by GetTransactions (unset filters match all transactions)
+type TransactionsFilters struct {
+	// Status is either "SUCCESS" or "FAILED"
+	Status string `json:"status,omitempty"`
+	// Soroban matches the Soroban transactions (if true) or the classic ones (if false)
+	Soroban *bool `json:"soroban,omitempty"`
+	// SourceAccount matches the transactions (or, for fee-bumps, the inner transactions) with this (G...) source account
+	SourceAccount string `json:"sourceAccount,omitempty"`
+	// ContractID matches the transactions which touched this (C...) contract, through their footprint or their events
+	ContractID string `json:"contractId,omitempty"`
+}
+
+type GetContractTransactionsRequest struct {
+	// ContractID is the (C...) address of the contract whose transactions are listed
+	ContractID  string                         `json:"contractId"`
+	StartLedger uint32                         `json:"startLedger"`
+	Pagination  *TransactionsPaginationOptions `json:"pagination,omitempty"`
+	// XDRFields restricts the XDR fields included in the transactions (see XDRFieldEnvelope and the like),
+	// all of them are included if empty
+	XDRFields []string `json:"xdrFields,omitempty"`
+}
+
+type TransactionsPaginationOptions struct {
+	Cursor string `json:"cursor,omitempty"`
+	Limit  uint   `json:"limit,omitempty"`
+}
+
+type TransactionInfo struct {
+	// Status is one of: TransactionStatusSuccess or TransactionStatusFailed.
+	Status string `json:"status"`
+	// ApplicationOrder is the index of the transaction among all the transactions
+	// for that ledger.
+	ApplicationOrder int32 `json:"applicationOrder"`
+	// FeeBump indicates whether the transaction is a feebump transaction
+	FeeBump bool `json:"feeBump"`
+	// EnvelopeXdr is the TransactionEnvelope XDR value.
+	EnvelopeXdr string `json:"envelopeXdr,omitempty"`
+	// ResultXdr is the TransactionResult XDR value.
+	ResultXdr string `json:"resultXdr,omitempty"`
+	// ResultMetaXdr is the TransactionMeta XDR value.
+	ResultMetaXdr string `json:"resultMetaXdr,omitempty"`
+	// DiagnosticEventsXDR is a base64-encoded slice of xdr.DiagnosticEvent,
+	// present only if transaction was not successful.
+	DiagnosticEventsXDR []string `json:"diagnosticEventsXdr,omitempty"`
+	// Ledger is the sequence of the ledger which included the transaction.
+	Ledger uint32 `json:"ledger"`
+	// LedgerCloseTime is the unix timestamp of when the transaction was included in the ledger.
+	LedgerCloseTime int64 `json:"createdAt"`
+}
+
+type GetTransactionsResponse struct {
+	Transactions []TransactionInfo `json:"transactions"`
+	LedgerRangeResponse
+	// Deprecated: use LatestLedgerCloseTime, this field will be removed in the next release.
+	LatestLedgerCloseTimestamp int64 `json:"latestLedgerCloseTimestamp"`
+	// Deprecated: use OldestLedgerCloseTime, this field will be removed in the next release.
+	OldestLedgerCloseTimestamp int64  `json:"oldestLedgerCloseTimestamp"`
+	Cursor                     string `json:"cursor"`
+}
+
+type SendTransactionRequest struct {
+	// Transaction is the base64 encoded transaction envelope.
+	Transaction string `json:"transaction"`
+}
+
+type SendTransactionResponse struct {
+	// ErrorResultXDR is a TransactionResult xdr string which contains details on why
+	// the transaction could not be accepted by stellar-core, present only if Status is "ERROR".
+	ErrorResultXDR string `json:"errorResultXdr,omitempty"`
+	// DiagnosticEventsXDR is a base64-encoded slice of xdr.DiagnosticEvent,
+	// present only if Status is "ERROR".
+	DiagnosticEventsXDR []string `json:"diagnosticEventsXdr,omitempty"`
+	// ErrorDiagnostics is a human-readable explanation of ErrorResultXDR and DiagnosticEventsXDR,
+	// present only if Status is "ERROR".
+	ErrorDiagnostics *TransactionErrorDiagnostics `json:"errorDiagnostics,omitempty"`
+	// Status is the status of the transaction submission returned by stellar-core,
+	// one of "PENDING", "DUPLICATE", "TRY_AGAIN_LATER" or "ERROR".
+	Status string `json:"status"`
+	// Hash is a hash of the transaction which can be used to look up whether
+	// the transaction was included in the ledger.
+	Hash string `json:"hash"`
+	// LatestLedger is the latest ledger known to Soroban-RPC at the time it handled
+	// the transaction submission request.
+	LatestLedger uint32 `json:"latestLedger"`
+	// LatestLedgerCloseTime is the unix timestamp of the close time of the latest ledger known to
+	// Soroban-RPC at the time it handled the transaction submission request.
+	LatestLedgerCloseTime int64 `json:"latestLedgerCloseTime,string"`
+}
+
+// TransactionErrorDiagnostics explains why stellar-core rejected a transaction
+type TransactionErrorDiagnostics struct {
+	// ResultCode is the name of the transaction result code (e.g. tx_bad_seq)
+	ResultCode string `json:"resultCode"`
+	// InnerResultCode is the result code of the inner transaction of a fee bump transaction
+	InnerResultCode string `json:"innerResultCode,omitempty"`
+	// FailedOperationIndex is the index of the first failing operation, if any
+	FailedOperationIndex *int `json:"failedOperationIndex,omitempty"`
+	// OperationResultCode is the result code of the failing operation (e.g. invoke_host_function_trapped)
+	OperationResultCode string `json:"operationResultCode,omitempty"`
+	// ContractErrors are the errors reported in the diagnostic events
+	ContractErrors []ContractErrorDiagnostic `json:"contractErrors,omitempty"`
+	// Message summarizes all the above
+	Message string `json:"message"`
+}
+
+type ContractErrorDiagnostic struct {
+	ContractID string `json:"contractId,omitempty"`
+	// Error is the error value (e.g. Error(Contract, #3))
+	Error   string `json:"error,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type SimulateTransactionRequest struct {
+	// Transaction is the base64 encoded transaction envelope.
+	Transaction    string          `json:"transaction"`
+	ResourceConfig *ResourceConfig `json:"resourceConfig,omitempty"`
+	// AuthMode is one of AuthModeEnforce or AuthModeRecord. By default, the
+	// authorizations are recorded if the operation has none, and enforced otherwise.
+	AuthMode string `json:"authMode,omitempty"`
+	// AutoRestore includes a ready-to-sign RestoreFootprint transaction in the restore preamble (if any).
+	AutoRestore bool `json:"autoRestore,omitempty"`
+}
+
+// The auth modes of SimulateTransactionRequest
+const (
+	AuthModeEnforce = "enforce"
+	AuthModeRecord  = "record"
+)
+
+type ResourceConfig struct {
+	InstructionLeeway uint64 `json:"instructionLeeway"`
+}
+
+type SimulateTransactionCost struct {
+	CPUInstructions uint64 `json:"cpuInsns,string"`
+	MemoryBytes     uint64 `json:"memBytes,string"`
+}
+
+type SimulateHostFunctionResult struct {
+	Auth []string `json:"auth"`
+	XDR  string   `json:"xdr"`
+}
+
+type RestorePreamble struct {
+	TransactionData string `json:"transactionData"` // SorobanTransactionData XDR in base64
+	MinResourceFee  int64  `json:"minResourceFee,string"`
+	// Transaction is the unsigned RestoreFootprint TransactionEnvelope XDR in base64, only present if
+	// AutoRestore was requested. It uses the sequence number of the simulated transaction, which must be
+	// submitted afterwards with the next sequence number.
+	Transaction string `json:"transaction,omitempty"`
+}
+
+type LedgerEntryChange struct {
+	// Type is one of "created", "updated" or "deleted"
+	Type   string  `json:"type"`
+	Key    string  `json:"key"`    // LedgerEntryKey in base64
+	Before *string `json:"before"` // LedgerEntry XDR in base64
+	After  *string `json:"after"`  // LedgerEntry XDR in base64
+}
+
+type SimulateTransactionResponse struct {
+	Error           string                        `json:"error,omitempty"`
+	TransactionData string                        `json:"transactionData,omitempty"` // SorobanTransactionData XDR in base64
+	MinResourceFee  int64                         `json:"minResourceFee,string,omitempty"`
+	Events          []string                      `json:"events,omitempty"`          // DiagnosticEvent XDR in base64
+	Results         []SimulateHostFunctionResult  `json:"results,omitempty"`         // an array of the individual host function call results
+	Cost            SimulateTransactionCost       `json:"cost,omitempty"`            // the effective cpu and memory cost of the invoked transaction execution.
+	RestorePreamble *RestorePreamble              `json:"restorePreamble,omitempty"` // If present, it indicates that a prior RestoreFootprint is required
+	StateChanges    []LedgerEntryChange           `json:"stateChanges,omitempty"`    // If present, it indicates how the state (ledger entries) will change as a result of the transaction execution.
+	Resources       *SimulateTransactionResources `json:"resources,omitempty"`       // If present, it breaks down the resources used by the transaction.
+	LatestLedger    uint32                        `json:"latestLedger"`
+}
+
+// SimulateTransactionResources breaks down the resources used by a simulated transaction
+type SimulateTransactionResources struct {
+	// Instructions is the number of CPU instructions reserved by the transaction data (including any leeway)
+	Instructions uint32 `json:"instructions"`
+	// ReadBytes is the number of bytes read from the ledger (including the written entries)
+	ReadBytes uint32 `json:"readBytes"`
+	// WriteBytes is the number of bytes written to the ledger
+	WriteBytes       uint32                     `json:"writeBytes"`
+	Footprint        SimulateFootprintResources `json:"footprint"`
+	ContractEvents   SimulateEventsResources    `json:"contractEvents"`
+	DiagnosticEvents SimulateEventsResources    `json:"diagnosticEvents"`
+	// ReturnValueBytes is the size of the value returned by the invoked host function
+	ReturnValueBytes int `json:"returnValueBytes"`
+}
+
+type SimulateFootprintResources struct {
+	ReadOnlyEntries  int `json:"readOnlyEntries"`
+	ReadWriteEntries int `json:"readWriteEntries"`
+	// Entries lists all the footprint entries, read-only entries first
+	Entries []FootprintEntryResources `json:"entries"`
+}
+
+type FootprintEntryResources struct {
+	// Key is the LedgerKey XDR in base64
+	Key string `json:"key"`
+	// Type is the type of the ledger entry (e.g. "contract_data")
+	Type string `json:"type"`
+	// Access is either "readOnly" or "readWrite"
+	Access string `json:"access"`
+	// Bytes is the size of the ledger entry before the transaction, 0 if it doesn't exist yet
+	Bytes int `json:"bytes"`
+}
+
+type SimulateEventsResources struct {
+	Count int `json:"count"`
+	Bytes int `json:"bytes"`
+}
+
+type GetTokenMetadataRequest struct {
+	ContractID string `json:"contractId"`
+}
+
+type GetTokenMetadataResponse struct {
+	ContractID string `json:"contractId"`
+	Name       string `json:"name"`
+	Symbol     string `json:"symbol"`
+	Decimals   uint32 `json:"decimals"`
+	LedgerRangeResponse
+}
+
+type GetFeeBumpRequest struct {
+	// Hash is the hash of a transaction previously submitted through the same node with sendTransaction.
+	// Either Hash or Transaction must be provided.
+	Hash string `json:"hash,omitempty"`
+	// Transaction is the base64 encoded envelope of the transaction to fee-bump
+	Transaction string `json:"transaction,omitempty"`
+	// FeeSource is the account paying the fee. When present, the (unsigned) fee-bump
+	// envelope is included in the response.
+	FeeSource string `json:"feeSource,omitempty"`
+	// Percentile of the recent inclusion fees to match (10, 20, ..., 90, 95 or 99), 90 by default
+	Percentile uint `json:"percentile,omitempty"`
+}
+
+type GetFeeBumpResponse struct {
+	// Hash is the hash of the inner transaction
+	Hash string `json:"hash"`
+	// InnerInclusionFee is the inclusion fee per operation currently offered by the transaction
+	InnerInclusionFee int64 `json:"innerInclusionFee,string"`
+	// InclusionFee is the recommended inclusion fee per operation of the fee-bump,
+	// which counts as an extra operation
+	InclusionFee int64 `json:"inclusionFee,string"`
+	// ResourceFee is the resource fee of Soroban transactions
+	ResourceFee int64 `json:"resourceFee,string,omitempty"`
+	// Fee is the recommended fee of the fee-bump transaction
+	Fee int64 `json:"fee,string"`
+	// ReplacementFee is the minimum fee of a fee-bump replacing the transaction while it's still
+	// queued by stellar-core
+	ReplacementFee int64 `json:"replacementFee,string"`
+	// FeeBumpTransactionXDR is the unsigned fee-bump envelope, present if FeeSource was provided
+	FeeBumpTransactionXDR string `json:"feeBumpTransactionXdr,omitempty"`
+	LedgerRangeResponse
+}
+
+// SorobanComputeConfig are the CPU and memory limits and fees
+type SorobanComputeConfig struct {
+	LedgerMaxInstructions           int64  `json:"ledgerMaxInstructions,string"`
+	TxMaxInstructions               int64  `json:"txMaxInstructions,string"`
+	FeeRatePerInstructionsIncrement int64  `json:"feeRatePerInstructionsIncrement,string"`
+	TxMemoryLimit                   uint32 `json:"txMemoryLimit"`
+}
+
+// SorobanLedgerCostConfig are the ledger access limits and fees
+type SorobanLedgerCostConfig struct {
+	LedgerMaxReadLedgerEntries     uint32 `json:"ledgerMaxReadLedgerEntries"`
+	LedgerMaxReadBytes             uint32 `json:"ledgerMaxReadBytes"`
+	LedgerMaxWriteLedgerEntries    uint32 `json:"ledgerMaxWriteLedgerEntries"`
+	LedgerMaxWriteBytes            uint32 `json:"ledgerMaxWriteBytes"`
+	TxMaxReadLedgerEntries         uint32 `json:"txMaxReadLedgerEntries"`
+	TxMaxReadBytes                 uint32 `json:"txMaxReadBytes"`
+	TxMaxWriteLedgerEntries        uint32 `json:"txMaxWriteLedgerEntries"`
+	TxMaxWriteBytes                uint32 `json:"txMaxWriteBytes"`
+	FeeReadLedgerEntry             int64  `json:"feeReadLedgerEntry,string"`
+	FeeWriteLedgerEntry            int64  `json:"feeWriteLedgerEntry,string"`
+	FeeRead1Kb                     int64  `json:"feeRead1Kb,string"`
+	BucketListTargetSizeBytes      int64  `json:"bucketListTargetSizeBytes,string"`
+	WriteFee1KbBucketListLow       int64  `json:"writeFee1KbBucketListLow,string"`
+	WriteFee1KbBucketListHigh      int64  `json:"writeFee1KbBucketListHigh,string"`
+	BucketListWriteFeeGrowthFactor uint32 `json:"bucketListWriteFeeGrowthFactor"`
+}
+
+// SorobanEventsConfig are the contract events limits and fees
+type SorobanEventsConfig struct {
+	TxMaxContractEventsSizeBytes uint32 `json:"txMaxContractEventsSizeBytes"`
+	FeeContractEvents1Kb         int64  `json:"feeContractEvents1Kb,string"`
+}
+
+// SorobanBandwidthConfig are the transaction size limits and fees
+type SorobanBandwidthConfig struct {
+	LedgerMaxTxsSizeBytes uint32 `json:"ledgerMaxTxsSizeBytes"`
+	TxMaxSizeBytes        uint32 `json:"txMaxSizeBytes"`
+	FeeTxSize1Kb          int64  `json:"feeTxSize1Kb,string"`
+}
+
+// SorobanStateArchivalConfig are the TTL and rent settings
+type SorobanStateArchivalConfig struct {
+	MaxEntryTTL                    uint32 `json:"maxEntryTtl"`
+	MinTemporaryTTL                uint32 `json:"minTemporaryTtl"`
+	MinPersistentTTL               uint32 `json:"minPersistentTtl"`
+	PersistentRentRateDenominator  int64  `json:"persistentRentRateDenominator,string"`
+	TempRentRateDenominator        int64  `json:"tempRentRateDenominator,string"`
+	MaxEntriesToArchive            uint32 `json:"maxEntriesToArchive"`
+	BucketListSizeWindowSampleSize uint32 `json:"bucketListSizeWindowSampleSize"`
+	BucketListWindowSamplePeriod   uint32 `json:"bucketListWindowSamplePeriod"`
+	EvictionScanSize               uint32 `json:"evictionScanSize"`
+	StartingEvictionScanLevel      uint32 `json:"startingEvictionScanLevel"`
+}
+
+type GetSorobanConfigResponse struct {
+	// ContractMaxSizeBytes is the maximum size of the contract code
+	ContractMaxSizeBytes       uint32                     `json:"contractMaxSizeBytes"`
+	ContractDataKeySizeBytes   uint32                     `json:"contractDataKeySizeBytes"`
+	ContractDataEntrySizeBytes uint32                     `json:"contractDataEntrySizeBytes"`
+	Compute                    SorobanComputeConfig       `json:"compute"`
+	LedgerCost                 SorobanLedgerCostConfig    `json:"ledgerCost"`
+	FeeHistorical1Kb           int64                      `json:"feeHistorical1Kb,string"`
+	Events                     SorobanEventsConfig        `json:"events"`
+	Bandwidth                  SorobanBandwidthConfig     `json:"bandwidth"`
+	StateArchival              SorobanStateArchivalConfig `json:"stateArchival"`
+	// LedgerMaxTxCount is the maximum number of Soroban transactions per ledger
+	LedgerMaxTxCount uint32 `json:"ledgerMaxTxCount"`
+	// AverageBucketListSize is the average of the bucket list size window, which the write fees are based on
+	AverageBucketListSize uint64 `json:"averageBucketListSize,string"`
+	// the settings were read at the latest ledger of the envelope
+	LedgerRangeResponse
+}
+
+type FeeDistribution struct {
+	Max              uint64 `json:"max,string"`
+	Min              uint64 `json:"min,string"`
+	Mode             uint64 `json:"mode,string"`
+	P10              uint64 `json:"p10,string"`
+	P20              uint64 `json:"p20,string"`
+	P30              uint64 `json:"p30,string"`
+	P40              uint64 `json:"p40,string"`
+	P50              uint64 `json:"p50,string"`
+	P60              uint64 `json:"p60,string"`
+	P70              uint64 `json:"p70,string"`
+	P80              uint64 `json:"p80,string"`
+	P90              uint64 `json:"p90,string"`
+	P95              uint64 `json:"p95,string"`
+	P99              uint64 `json:"p99,string"`
+	TransactionCount uint32 `json:"transactionCount,string"`
+	LedgerCount      uint32 `json:"ledgerCount"`
+}
+
+type GetFeeStatsResponse struct {
+	SorobanInclusionFee FeeDistribution `json:"sorobanInclusionFee"`
+	InclusionFee        FeeDistribution `json:"inclusionFee"`
+	LedgerRangeResponse
+}
+
+// SubscribeTransactionsRequest is the filter of a subscribeTransactions subscription, sent through
+// the WebSocket subscriptions endpoint (/ws). Empty filters select all the transactions.
+type SubscribeTransactionsRequest struct {
+	// Accounts matches the transactions with any of these (G...) accounts as the source
+	// of the transaction, of one of its operations or of the fee-bump.
+	Accounts []string `json:"accounts,omitempty"`
+	// Contracts matches the transactions invoking or emitting events from any of these (C...) contracts.
+	Contracts []string `json:"contracts,omitempty"`
+}
+
+// TransactionNotification is pushed (as the result of a transactionNotification) to
+// subscribeTransactions subscribers for every applied transaction.
+type TransactionNotification struct {
+	// Hash is the hex-encoded hash of the transaction
+	Hash string `json:"hash"`
+	// Status is one of: TransactionStatusSuccess or TransactionStatusFailed
+	Status string `json:"status"`
+	// ApplicationOrder is the index of the transaction among all the transactions
+	// for that ledger.
+	ApplicationOrder int32 `json:"applicationOrder"`
+	// FeeBump indicates whether the transaction is a feebump transaction
+	FeeBump bool `json:"feeBump"`
+	// EnvelopeXdr is the TransactionEnvelope XDR value.
+	EnvelopeXdr string `json:"envelopeXdr"`
+	// ResultXdr is the TransactionResult XDR value.
+	ResultXdr string `json:"resultXdr"`
+	// Ledger is the sequence of the ledger which included the transaction.
+	Ledger uint32 `json:"ledger"`
+	// LedgerCloseTime is the unix timestamp of when the transaction was included in the ledger.
+	LedgerCloseTime int64 `json:"createdAt,string"`
+}
+
+// LedgerNotification is pushed (as the result of a ledgerNotification) to
+// subscribeLedgers subscribers for every ingested ledger.
+type LedgerNotification struct {
+	// Sequence number of the ledger.
+	Sequence uint32 `json:"sequence"`
+	// Hash of the ledger as a hex-encoded string
+	Hash string `json:"hash"`
+	// LedgerCloseTime is the unix timestamp of when the ledger was closed.
+	LedgerCloseTime int64 `json:"closeTime,string"`
+	// TransactionCount is the number of transactions applied in the ledger
+	TransactionCount uint32 `json:"transactionCount"`
+	// OperationCount is the number of operations of the transactions applied in the ledger
+	OperationCount uint32 `json:"operationCount"`
+	// EventCount is the number of contract (not diagnostic) events emitted in the ledger
+	EventCount uint32 `json:"eventCount"`
+}
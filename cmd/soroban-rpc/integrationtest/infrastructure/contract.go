@@ -16,7 +16,7 @@ import (
 var testSalt = sha256.Sum256([]byte("a1"))
 
 func GetHelloWorldContract() []byte {
-	contractFile := path.Join(GetCurrentDirectory(), "../../../../../wasms/test_hello_world.wasm")
+	contractFile := path.Join(GetCurrentDirectory(), "../../../../wasms/test_hello_world.wasm")
 	ret, err := os.ReadFile(contractFile)
 	if err != nil {
 		str := fmt.Sprintf(
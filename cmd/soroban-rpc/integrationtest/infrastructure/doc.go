@@ -0,0 +1,22 @@
+// Package infrastructure runs end-to-end tests against a real network: a standalone
+// stellar-core (run with docker-compose) and a soroban-rpc server (run in-process, or in a
+// container when testing a released version).
+//
+// Besides the soroban-rpc integration tests, it can be used by downstream projects
+// (e.g. SDKs or indexers) to test against a real node:
+//
+//	func TestContract(t *testing.T) {
+//		test := infrastructure.NewTest(t, nil)
+//		account := test.CreateAccount("100")
+//		contractID, _ := test.DeployContract(wasm)
+//		op := infrastructure.CreateInvokeHostOperation(account.Address(), contractID, "hello", arg)
+//		response := test.SubmitOperations(account, op)
+//		latest, err := test.GetClient().GetLatestLedger(context.Background())
+//		...
+//	}
+//
+// The tests are skipped unless SOROBAN_RPC_INTEGRATION_TESTS_ENABLED is set and running them
+// requires docker-compose and a stellar-core binary (SOROBAN_RPC_INTEGRATION_TESTS_CAPTIVE_CORE_BIN).
+// FundAccount, CreateAccount, GetAccount, DeployContract, SubmitOperations, SubmitTransaction and
+// GetClient form the stable API of the package, expressed in terms of the client package.
+package infrastructure
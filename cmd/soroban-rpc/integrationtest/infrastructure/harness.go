@@ -0,0 +1,131 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/keypair"
+	proto "github.com/stellar/go/protocols/stellarcore"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/client"
+)
+
+const (
+	transactionPollInterval = time.Second
+	transactionTimeout      = time.Minute
+)
+
+// FundAccount creates the account with the given address, transferring the starting
+// balance (in XLM, e.g. "100") from the master account.
+func (i *Test) FundAccount(address string, startingBalance string) {
+	op := &txnbuild.CreateAccount{
+		Destination: address,
+		Amount:      startingBalance,
+	}
+	response := i.SubmitOperations(i.MasterKey(), op)
+	require.Equal(i.t, client.TransactionStatusSuccess, response.Status, "unable to fund account %s", address)
+}
+
+// CreateAccount creates a random account funded with the given starting balance (in XLM)
+func (i *Test) CreateAccount(startingBalance string) *keypair.Full {
+	kp := keypair.MustRandom()
+	i.FundAccount(kp.Address(), startingBalance)
+	return kp
+}
+
+// GetAccount loads the account with the given address (including its current sequence number)
+func (i *Test) GetAccount(address string) *txnbuild.SimpleAccount {
+	key, err := xdr.LedgerKey{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.LedgerKeyAccount{AccountId: xdr.MustAddress(address)},
+	}.MarshalBinaryBase64()
+	require.NoError(i.t, err)
+	response, err := i.client.GetLedgerEntries(context.Background(), client.GetLedgerEntriesRequest{
+		Keys: []string{key},
+	})
+	require.NoError(i.t, err)
+	require.Len(i.t, response.Entries, 1, "account %s not found", address)
+	var entry xdr.LedgerEntryData
+	require.NoError(i.t, xdr.SafeUnmarshalBase64(response.Entries[0].XDR, &entry))
+	account := txnbuild.NewSimpleAccount(address, int64(entry.MustAccount().SeqNum))
+	return &account
+}
+
+// DeployContract uploads the Wasm code and creates a contract instance out of it (deployed by
+// the master account), returning the contract ID and the hash of the code.
+func (i *Test) DeployContract(wasm []byte) ([32]byte, xdr.Hash) {
+	wasmHash := xdr.Hash(sha256.Sum256(wasm))
+	deployer := i.MasterKey().Address()
+	response := i.SubmitOperations(i.MasterKey(), CreateUploadWasmOperation(deployer, wasm))
+	require.Equal(i.t, client.TransactionStatusSuccess, response.Status, "unable to upload contract code")
+
+	// Use a random salt, so that the same code can be deployed several times
+	var salt xdr.Uint256
+	_, err := rand.Read(salt[:])
+	require.NoError(i.t, err)
+	response = i.SubmitOperations(i.MasterKey(), createCreateContractOperation(deployer, salt, wasmHash))
+	require.Equal(i.t, client.TransactionStatusSuccess, response.Status, "unable to create contract")
+	return getContractID(i.t, deployer, salt, StandaloneNetworkPassphrase), wasmHash
+}
+
+// SubmitOperations builds a transaction out of the operations (with the source
+// account as the transaction source), simulates it if it contains a Soroban operation,
+// and submits it with SubmitTransaction.
+func (i *Test) SubmitOperations(source *keypair.Full, ops ...txnbuild.Operation) client.GetTransactionResponse {
+	account := i.masterAccount
+	if source.Address() != i.MasterKey().Address() {
+		account = i.GetAccount(source.Address())
+	}
+	params := txnbuild.TransactionParams{
+		SourceAccount:        account,
+		IncrementSequenceNum: true,
+		Operations:           ops,
+		BaseFee:              txnbuild.MinBaseFee,
+		Preconditions: txnbuild.Preconditions{
+			TimeBounds: txnbuild.NewInfiniteTimeout(),
+		},
+	}
+	if len(ops) == 1 && isSorobanOperation(ops[0]) {
+		params = PreflightTransactionParams(i.t, i.rpcClient, params)
+	}
+	tx, err := txnbuild.NewTransaction(params)
+	require.NoError(i.t, err)
+	return i.SubmitTransaction(tx, source)
+}
+
+// SubmitTransaction signs the transaction, sends it and waits until it's included in a ledger,
+// returning the resulting getTransaction response (whose status is either SUCCESS or FAILED).
+// The test fails if the transaction is rejected or isn't included in a timely manner.
+func (i *Test) SubmitTransaction(tx *txnbuild.Transaction, signers ...*keypair.Full) client.GetTransactionResponse {
+	tx, err := tx.Sign(StandaloneNetworkPassphrase, signers...)
+	require.NoError(i.t, err)
+	envelope, err := tx.Base64()
+	require.NoError(i.t, err)
+	hash, err := tx.HashHex(StandaloneNetworkPassphrase)
+	require.NoError(i.t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), transactionTimeout)
+	defer cancel()
+	sent, err := i.client.SendTransaction(ctx, client.SendTransactionRequest{Transaction: envelope})
+	require.NoError(i.t, err)
+	require.Equal(i.t, proto.TXStatusPending, sent.Status, "transaction rejected (result: %s)", sent.ErrorResultXDR)
+
+	response, err := i.client.PollTransaction(ctx, hash, transactionPollInterval)
+	require.NoError(i.t, err, "transaction %s wasn't included in a ledger", hash)
+	return response
+}
+
+func isSorobanOperation(op txnbuild.Operation) bool {
+	switch op.(type) {
+	case *txnbuild.InvokeHostFunction, *txnbuild.ExtendFootprintTtl, *txnbuild.RestoreFootprint:
+		return true
+	default:
+		return false
+	}
+}
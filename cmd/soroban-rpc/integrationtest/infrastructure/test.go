@@ -28,6 +28,7 @@ import (
 	"github.com/stellar/go/txnbuild"
 	"github.com/stellar/go/xdr"
 
+	"github.com/stellar/soroban-rpc/client"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/methods"
@@ -101,6 +102,7 @@ type Test struct {
 	rpcContainerLogsCommand    *exec.Cmd
 
 	rpcClient  *Client
+	client     *client.Client
 	coreClient *stellarcore.Client
 
 	daemon *daemon.Daemon
@@ -165,6 +167,7 @@ func NewTest(t *testing.T, cfg *TestConfig) *Test {
 	}
 
 	i.rpcClient = NewClient(i.GetSorobanRPCURL(), nil)
+	i.client = client.NewClient(i.GetSorobanRPCURL(), nil)
 	if shouldWaitForRPC {
 		i.waitForRPC()
 	}
@@ -223,6 +226,11 @@ func (i *Test) GetRPCLient() *Client {
 	return i.rpcClient
 }
 
+// GetClient returns a client of the RPC server under test
+func (i *Test) GetClient() *client.Client {
+	return i.client
+}
+
 func (i *Test) MasterKey() *keypair.Full {
 	return keypair.Root(StandaloneNetworkPassphrase)
 }
@@ -349,10 +357,15 @@ func (i *Test) generateCaptiveCoreCfgForContainer() {
 
 	// Get old version of captive-core-integration-tests.cfg.tmpl
 	out, err := getOldVersionCaptiveCoreConfigVersion("docker", captiveCoreConfigTemplateFilename)
+	if err != nil {
+		// Try the directory before the package was made public
+		out, err = getOldVersionCaptiveCoreConfigVersion(
+			"../../internal/integrationtest/infrastructure/docker", captiveCoreConfigTemplateFilename)
+	}
 	if err != nil {
 		// Try the directory before the integration test refactoring
 		// TODO: remove this hack after protocol 22 is released
-		out, err = getOldVersionCaptiveCoreConfigVersion("../../test", captiveCoreConfigFilename)
+		out, err = getOldVersionCaptiveCoreConfigVersion("../../internal/test", captiveCoreConfigFilename)
 		outStr := strings.Replace(string(out), `ADDRESS="localhost"`, `ADDRESS="${CORE_HOST_PORT}"`, -1)
 		out = []byte(outStr)
 	}
@@ -539,6 +552,9 @@ func (i *Test) prepareShutdownHandlers() {
 		if i.rpcClient != nil {
 			i.rpcClient.Close()
 		}
+		if i.client != nil {
+			i.client.Close()
+		}
 		if i.areThereContainers() {
 			i.stopContainers()
 		}
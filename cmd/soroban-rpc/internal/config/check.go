@@ -0,0 +1,241 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Issue is a problem found when checking the configuration
+type Issue struct {
+	// Option is the name of the offending option ("" if the issue isn't specific to an option)
+	Option string
+	// Message describes the problem
+	Message string
+	// Warning is set for the problems which don't prevent the daemon from starting
+	Warning bool
+}
+
+func (i Issue) String() string {
+	severity := "error"
+	if i.Warning {
+		severity = "warning"
+	}
+	if i.Option == "" {
+		return fmt.Sprintf("%s: %s", severity, i.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", severity, i.Option, i.Message)
+}
+
+// HasErrors tells whether any of the issues (besides warnings) prevents the daemon from starting
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if !issue.Warning {
+			return true
+		}
+	}
+	return false
+}
+
+// Check validates the configuration, reporting all the problems found instead
+// of stopping at the first one. Besides the validation of every option, it
+// checks the endpoints, the paths (which must exist or be writable) and the
+// sanity of the retention windows.
+func (cfg *Config) Check() []Issue {
+	var issues []Issue
+	for _, option := range cfg.options() {
+		if option.Validate == nil {
+			continue
+		}
+		if err := option.Validate(option); err != nil {
+			var missingOptionErr missingRequiredOptionError
+			if errors.As(err, &missingOptionErr) {
+				issues = append(issues, Issue{Option: option.Name, Message: missingOptionErr.strErr})
+				continue
+			}
+			issues = append(issues, Issue{Option: option.Name, Message: err.Error()})
+		}
+	}
+	issues = append(issues, cfg.checkEndpoints()...)
+	issues = append(issues, cfg.checkPaths()...)
+	issues = append(issues, cfg.checkRetentionWindows()...)
+	return issues
+}
+
+func (cfg *Config) checkEndpoints() []Issue {
+	var issues []Issue
+	endpoints := map[string]string{}
+	for _, endpoint := range []struct {
+		option  string
+		address string
+	}{
+		{"endpoint", cfg.Endpoint},
+		{"admin-endpoint", cfg.AdminEndpoint},
+		{"peer-endpoint", cfg.PeerEndpoint},
+	} {
+		if endpoint.address == "" {
+			continue
+		}
+		if err := checkEndpoint(endpoint.address); err != nil {
+			issues = append(issues, Issue{Option: endpoint.option, Message: err.Error()})
+			continue
+		}
+		if other, ok := endpoints[endpoint.address]; ok {
+			issues = append(issues, Issue{
+				Option:  endpoint.option,
+				Message: fmt.Sprintf("%q is already used by %s", endpoint.address, other),
+			})
+			continue
+		}
+		endpoints[endpoint.address] = endpoint.option
+	}
+	if cfg.CaptiveCoreHTTPPort > 65535 {
+		issues = append(issues, Issue{
+			Option:  "stellar-captive-core-http-port",
+			Message: fmt.Sprintf("invalid port %d", cfg.CaptiveCoreHTTPPort),
+		})
+	}
+	return issues
+}
+
+func checkEndpoint(address string) error {
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint %q: %w", address, err)
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err == nil {
+		return nil
+	}
+	if _, err := net.LookupPort("tcp", port); err != nil {
+		return fmt.Errorf("invalid port %q in endpoint %q", port, address)
+	}
+	return nil
+}
+
+func (cfg *Config) checkPaths() []Issue {
+	var issues []Issue
+	addIssue := func(option string, err error) {
+		if err != nil {
+			issues = append(issues, Issue{Option: option, Message: err.Error()})
+		}
+	}
+	if cfg.StellarCoreBinaryPath != "" && !cfg.CoreBinaryAuto {
+		addIssue("stellar-core-binary-path", checkExecutable(cfg.StellarCoreBinaryPath))
+	}
+	for _, file := range []struct {
+		option string
+		path   string
+	}{
+		{"captive-core-config-path", cfg.CaptiveCoreConfigPath},
+		{"tls-cert-file", cfg.TLSCertFile},
+		{"tls-key-file", cfg.TLSKeyFile},
+		{"peer-tls-cert-file", cfg.PeerTLSCertFile},
+		{"peer-tls-key-file", cfg.PeerTLSKeyFile},
+		{"peer-tls-ca-file", cfg.PeerTLSCAFile},
+	} {
+		if file.path != "" {
+			addIssue(file.option, checkReadable(file.path))
+		}
+	}
+	for _, network := range cfg.Networks() {
+		addIssue("network-configs", checkReadable(network.ConfigPath))
+	}
+	if cfg.CaptiveCoreStoragePath != "" {
+		addIssue("captive-core-storage-path", checkWritableDir(cfg.CaptiveCoreStoragePath))
+	}
+	if cfg.SQLiteDBPath != "" && cfg.SQLiteDBPath != InMemoryDBPath {
+		addIssue("db-path", checkWritableFile(cfg.SQLiteDBPath))
+	}
+	if cfg.AccessLogPath != "" {
+		addIssue("access-log-path", checkWritableFile(cfg.AccessLogPath))
+	}
+	return issues
+}
+
+func checkExecutable(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() || info.Mode().Perm()&0o111 == 0 {
+		return fmt.Errorf("%s is not an executable file", path)
+	}
+	return nil
+}
+
+func checkReadable(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// checkWritableDir checks that files can be created in the directory
+func checkWritableDir(dir string) error {
+	info, err := os.Stat(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		// the directory will be created, as long as its closest existing ancestor is writable
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return err
+		}
+		return checkWritableDir(parent)
+	} else if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	file, err := os.CreateTemp(dir, ".soroban-rpc-check-*")
+	if err != nil {
+		return fmt.Errorf("%s is not writable: %w", dir, err)
+	}
+	_ = file.Close()
+	return os.Remove(file.Name())
+}
+
+// checkWritableFile checks that the file can be written (or created)
+func checkWritableFile(path string) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkWritableDir(filepath.Dir(path))
+	} else if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("%s is not writable: %w", path, err)
+	}
+	return file.Close()
+}
+
+func (cfg *Config) checkRetentionWindows() []Issue {
+	var issues []Issue
+	for _, window := range []struct {
+		option string
+		size   uint32
+	}{
+		{"classic-fee-stats-retention-window", cfg.ClassicFeeStatsLedgerRetentionWindow},
+		{"soroban-fee-stats-retention-window", cfg.SorobanFeeStatsLedgerRetentionWindow},
+	} {
+		if window.size > cfg.HistoryRetentionWindow {
+			issues = append(issues, Issue{
+				Option: window.option,
+				Message: fmt.Sprintf(
+					"the window (%d ledgers) is larger than history-retention-window (%d ledgers), "+
+						"so it will only be partially filled after restarts",
+					window.size, cfg.HistoryRetentionWindow,
+				),
+				Warning: true,
+			})
+		}
+	}
+	return issues
+}
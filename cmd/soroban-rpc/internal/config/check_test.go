@@ -0,0 +1,152 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validTestConfig(t *testing.T) *Config {
+	dir := t.TempDir()
+	coreBinary := filepath.Join(dir, "stellar-core")
+	require.NoError(t, os.WriteFile(coreBinary, []byte("#!/bin/sh\n"), 0o755))
+	coreConfig := filepath.Join(dir, "captive-core.cfg")
+	require.NoError(t, os.WriteFile(coreConfig, []byte{}, 0o600))
+
+	var cfg Config
+	require.NoError(t, cfg.loadDefaults())
+	cfg.NetworkPassphrase = "test passphrase"
+	cfg.HistoryArchiveURLs = []string{"http://localhost:1570"}
+	cfg.StellarCoreBinaryPath = coreBinary
+	cfg.CaptiveCoreConfigPath = coreConfig
+	cfg.CaptiveCoreStoragePath = filepath.Join(dir, "captive-core")
+	cfg.SQLiteDBPath = filepath.Join(dir, "soroban_rpc.sqlite")
+	return &cfg
+}
+
+func TestCheckValidConfig(t *testing.T) {
+	cfg := validTestConfig(t)
+	issues := cfg.Check()
+	assert.Empty(t, issues)
+	assert.False(t, HasErrors(issues))
+}
+
+func TestCheckReportsAllIssues(t *testing.T) {
+	cfg := validTestConfig(t)
+	cfg.NetworkPassphrase = ""
+	cfg.AdminEndpoint = cfg.Endpoint
+	cfg.PeerEndpoint = "localhost"
+	cfg.StellarCoreBinaryPath = cfg.CaptiveCoreConfigPath
+	cfg.TLSCertFile = filepath.Join(t.TempDir(), "missing.pem")
+	cfg.TLSKeyFile = cfg.TLSCertFile
+	cfg.SQLiteDBPath = t.TempDir()
+	cfg.SorobanFeeStatsLedgerRetentionWindow = cfg.HistoryRetentionWindow + 1
+
+	issues := cfg.Check()
+	options := map[string]Issue{}
+	for _, issue := range issues {
+		options[issue.Option] = issue
+	}
+	for _, option := range []string{
+		"network-passphrase",
+		"admin-endpoint",
+		"peer-endpoint",
+		"stellar-core-binary-path",
+		"tls-cert-file",
+		"tls-key-file",
+		"db-path",
+	} {
+		if assert.Contains(t, options, option) {
+			assert.False(t, options[option].Warning, option)
+		}
+	}
+	assert.Contains(t, options["admin-endpoint"].Message, "is already used by endpoint")
+	require.Contains(t, options, "soroban-fee-stats-retention-window")
+	assert.True(t, options["soroban-fee-stats-retention-window"].Warning)
+	assert.True(t, HasErrors(issues))
+}
+
+func TestCheckOnlyWarnings(t *testing.T) {
+	cfg := validTestConfig(t)
+	cfg.ClassicFeeStatsLedgerRetentionWindow = cfg.HistoryRetentionWindow + 1
+	issues := cfg.Check()
+	require.Len(t, issues, 1)
+	assert.False(t, HasErrors(issues))
+	assert.Equal(t,
+		"warning: classic-fee-stats-retention-window: the window (17281 ledgers) is larger than "+
+			"history-retention-window (17280 ledgers), so it will only be partially filled after restarts",
+		issues[0].String(),
+	)
+}
+
+func TestCheckInMemoryDB(t *testing.T) {
+	cfg := validTestConfig(t)
+	cfg.SQLiteDBPath = InMemoryDBPath
+	assert.Empty(t, cfg.Check())
+
+	cfg.HistoryRetentionWindow = MaxInMemoryHistoryRetentionWindow + 1
+	issues := cfg.Check()
+	require.Len(t, issues, 1)
+	assert.Equal(t,
+		"error: db-path: the history-retention-window of in-memory databases cannot exceed 17280 ledgers",
+		issues[0].String(),
+	)
+}
+
+func TestCheckMode(t *testing.T) {
+	cfg := validTestConfig(t)
+	cfg.Mode = ModeIngest
+	assert.Empty(t, cfg.Check())
+	assert.True(t, cfg.Ingests())
+	assert.False(t, cfg.Serves())
+
+	// captive core isn't run when serving only
+	cfg.Mode = ModeServe
+	cfg.StellarCoreBinaryPath = ""
+	cfg.CaptiveCoreConfigPath = ""
+	assert.Empty(t, cfg.Check())
+	assert.False(t, cfg.Ingests())
+	assert.True(t, cfg.Serves())
+	cfg.BootstrapSnapshotURL = "https://example.com/manifest.json"
+	issues := cfg.Check()
+	require.Len(t, issues, 1)
+	assert.Equal(t,
+		"error: bootstrap-snapshot-url: bootstrap-snapshot-url cannot be used in mode serve, the database is bootstrapped by the ingesting process",
+		issues[0].String(),
+	)
+	cfg.BootstrapSnapshotURL = ""
+
+	cfg.SQLiteDBPath = InMemoryDBPath
+	issues = cfg.Check()
+	require.Len(t, issues, 1)
+	assert.Equal(t,
+		"error: mode: mode serve cannot be used with an in-memory database, which cannot be shared",
+		issues[0].String(),
+	)
+
+	cfg.Mode = "foo"
+	issues = cfg.Check()
+	require.Len(t, issues, 3)
+	assert.Equal(t, `error: mode: invalid mode "foo", it must be "combined", "ingest" or "serve"`, issues[0].String())
+}
+
+func TestCheckCoreBinaryAuto(t *testing.T) {
+	cfg := validTestConfig(t)
+	cfg.CoreBinaryAuto = true
+	// the binary is downloaded on startup
+	cfg.StellarCoreBinaryPath = ""
+	issues := cfg.Check()
+	require.Len(t, issues, 2)
+	assert.Equal(t, "error: core-binary-url: core-binary-url is required when core-binary-auto is enabled", issues[0].String())
+	assert.Equal(t,
+		"error: core-binary-sha256: core-binary-sha256 must be a hex-encoded SHA-256 checksum when core-binary-auto is enabled",
+		issues[1].String(),
+	)
+
+	cfg.CoreBinaryURL = "https://example.com/stellar-core"
+	cfg.CoreBinarySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	assert.Empty(t, cfg.Check())
+}
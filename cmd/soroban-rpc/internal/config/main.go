@@ -17,59 +17,135 @@ type Config struct {
 	StellarCoreURL         string
 	CaptiveCoreStoragePath string
 	StellarCoreBinaryPath  string
+	CoreBinaryAuto         bool
+	CoreBinaryURL          string
+	CoreBinarySHA256       string
 	CaptiveCoreConfigPath  string
 	CaptiveCoreHTTPPort    uint
 
-	Endpoint                                       string
-	AdminEndpoint                                  string
-	CheckpointFrequency                            uint32
-	CoreRequestTimeout                             time.Duration
-	DefaultEventsLimit                             uint
-	DefaultTransactionsLimit                       uint
-	EventLedgerRetentionWindow                     uint32
-	FriendbotURL                                   string
-	HistoryArchiveURLs                             []string
-	HistoryArchiveUserAgent                        string
-	IngestionTimeout                               time.Duration
-	LogFormat                                      LogFormat
-	LogLevel                                       logrus.Level
-	MaxEventsLimit                                 uint
-	MaxTransactionsLimit                           uint
-	MaxHealthyLedgerLatency                        time.Duration
-	NetworkPassphrase                              string
-	PreflightWorkerCount                           uint
-	PreflightWorkerQueueSize                       uint
-	PreflightEnableDebug                           bool
-	SQLiteDBPath                                   string
-	HistoryRetentionWindow                         uint32
-	TransactionLedgerRetentionWindow               uint32
-	SorobanFeeStatsLedgerRetentionWindow           uint32
-	ClassicFeeStatsLedgerRetentionWindow           uint32
-	RequestBacklogGlobalQueueLimit                 uint
-	RequestBacklogGetHealthQueueLimit              uint
-	RequestBacklogGetEventsQueueLimit              uint
-	RequestBacklogGetNetworkQueueLimit             uint
-	RequestBacklogGetVersionInfoQueueLimit         uint
-	RequestBacklogGetLatestLedgerQueueLimit        uint
-	RequestBacklogGetLedgerEntriesQueueLimit       uint
-	RequestBacklogGetTransactionQueueLimit         uint
-	RequestBacklogGetTransactionsQueueLimit        uint
-	RequestBacklogSendTransactionQueueLimit        uint
-	RequestBacklogSimulateTransactionQueueLimit    uint
-	RequestBacklogGetFeeStatsTransactionQueueLimit uint
-	RequestExecutionWarningThreshold               time.Duration
-	MaxRequestExecutionDuration                    time.Duration
-	MaxGetHealthExecutionDuration                  time.Duration
-	MaxGetEventsExecutionDuration                  time.Duration
-	MaxGetNetworkExecutionDuration                 time.Duration
-	MaxGetVersionInfoExecutionDuration             time.Duration
-	MaxGetLatestLedgerExecutionDuration            time.Duration
-	MaxGetLedgerEntriesExecutionDuration           time.Duration
-	MaxGetTransactionExecutionDuration             time.Duration
-	MaxGetTransactionsExecutionDuration            time.Duration
-	MaxSendTransactionExecutionDuration            time.Duration
-	MaxSimulateTransactionExecutionDuration        time.Duration
-	MaxGetFeeStatsExecutionDuration                time.Duration
+	Mode                                            string
+	Endpoint                                        string
+	AdminEndpoint                                   string
+	AdminAPIToken                                   string
+	ProfileDir                                      string
+	ProfileTriggerRequestDuration                   time.Duration
+	ProfileTriggerHeapSize                          uint
+	TLSCertFile                                     string
+	TLSKeyFile                                      string
+	CheckpointFrequency                             uint32
+	CoreRequestTimeout                              time.Duration
+	DefaultEventsLimit                              uint
+	DefaultTransactionsLimit                        uint
+	DefaultContractEntriesLimit                     uint
+	EnableGraphQL                                   bool
+	EnableHorizonAPI                                bool
+	EnableSubscriptions                             bool
+	MaxSubscriptionConnections                      uint
+	MaxSubscriptionConnectionsPerClient             uint
+	EnableResponseCompression                       bool
+	EnableZstdResponseCompression                   bool
+	ResponseCompressionMinSize                      uint
+	CORSAllowedOrigins                              []string
+	CORSAllowedHeaders                              []string
+	CORSAllowedMethods                              []string
+	CORSMaxAge                                      time.Duration
+	EventLedgerRetentionWindow                      uint32
+	FriendbotURL                                    string
+	HistoryArchiveURLs                              []string
+	HistoryArchiveUserAgent                         string
+	IngestionTimeout                                time.Duration
+	ShutdownGracePeriod                             time.Duration
+	LogFormat                                       LogFormat
+	AccessLogPath                                   string
+	AccessLogSampleRatio                            float64
+	TracingOTLPEndpoint                             string
+	TracingOTLPHeaders                              []string
+	TracingSampleRatio                              float64
+	LogLevel                                        logrus.Level
+	MaxEventsLimit                                  uint
+	MaxEventsLedgerRange                            uint32
+	MaxEventFilters                                 uint
+	MaxEventFilterContractIDs                       uint
+	MaxEventFilterTopics                            uint
+	MaxLedgerEntriesKeys                            uint
+	MaxRequestSize                                  uint
+	MaxRequestParamsSize                            []string
+	MaxResponseSize                                 uint
+	MaxTransactionsLimit                            uint
+	MaxContractEntriesLimit                         uint
+	CircuitBreakerMaxLedgerLag                      time.Duration
+	MaxHealthyLedgerLatency                         time.Duration
+	SystemdWatchdogStallTimeout                     time.Duration
+	NetworkPassphrase                               string
+	NetworkConfigs                                  []string
+	ProtocolUpgradeSchedule                         []string
+	PreflightWorkerCount                            uint
+	PreflightWorkerQueueSize                        uint
+	PreflightEnableDebug                            bool
+	PeerEndpoint                                    string
+	PeerURLs                                        []string
+	PeerTLSCertFile                                 string
+	PeerTLSKeyFile                                  string
+	PeerTLSCAFile                                   string
+	PeerHintTTL                                     time.Duration
+	SQLiteDBPath                                    string
+	DBReadConnections                               uint
+	DBReadBusyTimeout                               time.Duration
+	BootstrapSnapshotURL                            string
+	BootstrapSnapshotChecksum                       string
+	SnapshotPublishURL                              string
+	SnapshotPublishInterval                         time.Duration
+	HistoryRetentionWindow                          uint32
+	TransactionLedgerRetentionWindow                uint32
+	SorobanFeeStatsLedgerRetentionWindow            uint32
+	ClassicFeeStatsLedgerRetentionWindow            uint32
+	RequestBacklogGlobalQueueLimit                  uint
+	RequestBacklogGetHealthQueueLimit               uint
+	RequestBacklogGetEventsQueueLimit               uint
+	RequestBacklogGetNetworkQueueLimit              uint
+	RequestBacklogGetVersionInfoQueueLimit          uint
+	RequestBacklogGetLatestLedgerQueueLimit         uint
+	RequestBacklogGetLedgerEntriesQueueLimit        uint
+	RequestBacklogGetTransactionQueueLimit          uint
+	RequestBacklogGetTransactionsQueueLimit         uint
+	RequestBacklogSendTransactionQueueLimit         uint
+	RequestBacklogSimulateTransactionQueueLimit     uint
+	RequestBacklogGetFeeStatsTransactionQueueLimit  uint
+	RequestBacklogGetTokenMetadataQueueLimit        uint
+	RequestBacklogGetFeeBumpQueueLimit              uint
+	RequestBacklogGetSorobanConfigQueueLimit        uint
+	RequestBacklogGetContractEntriesQueueLimit      uint
+	RequestBacklogGetContractTransactionsQueueLimit uint
+	RequestBacklogGetEventQueueLimit                uint
+	RequestExecutionWarningThreshold                time.Duration
+	RateLimitGlobalRequestsPerSecond                float64
+	RateLimitGlobalBurst                            uint
+	RateLimitClientRequestsPerSecond                float64
+	RateLimitClientBurst                            uint
+	RateLimitMethodWeights                          []string
+	RateLimitTrustForwardedFor                      bool
+	RateLimitTrustedProxies                         uint
+	MethodConcurrencyLimits                         []string
+	MethodQueueLimits                               []string
+	MethodQueueTimeout                              time.Duration
+	MaxRequestExecutionDuration                     time.Duration
+	MaxGetHealthExecutionDuration                   time.Duration
+	MaxGetEventsExecutionDuration                   time.Duration
+	MaxGetNetworkExecutionDuration                  time.Duration
+	MaxGetVersionInfoExecutionDuration              time.Duration
+	MaxGetLatestLedgerExecutionDuration             time.Duration
+	MaxGetLedgerEntriesExecutionDuration            time.Duration
+	MaxGetTransactionExecutionDuration              time.Duration
+	MaxGetTransactionsExecutionDuration             time.Duration
+	MaxSendTransactionExecutionDuration             time.Duration
+	MaxSimulateTransactionExecutionDuration         time.Duration
+	MaxGetFeeStatsExecutionDuration                 time.Duration
+	MaxGetTokenMetadataExecutionDuration            time.Duration
+	MaxGetFeeBumpExecutionDuration                  time.Duration
+	MaxGetSorobanConfigExecutionDuration            time.Duration
+	MaxGetContractEntriesExecutionDuration          time.Duration
+	MaxGetContractTransactionsExecutionDuration     time.Duration
+	MaxGetEventExecutionDuration                    time.Duration
 
 	// We memoize these, so they bind to pflags correctly
 	optionsCache *Options
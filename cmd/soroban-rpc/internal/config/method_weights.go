@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseMethodWeights parses a list of `method=weight` entries.
+func ParseMethodWeights(entries []string) (map[string]uint, error) {
+	return parseMethodValues(entries, "weight")
+}
+
+// ParseMethodLimits parses a list of `method=limit` entries.
+func ParseMethodLimits(entries []string) (map[string]uint, error) {
+	return parseMethodValues(entries, "limit")
+}
+
+func parseMethodValues(entries []string, valueName string) (map[string]uint, error) {
+	values := make(map[string]uint, len(entries))
+	for _, entry := range entries {
+		method, valueStr, found := strings.Cut(entry, "=")
+		method = strings.TrimSpace(method)
+		if !found || method == "" {
+			return nil, fmt.Errorf("invalid method %s %q, expected <method>=<%s>", valueName, entry, valueName)
+		}
+		value, err := strconv.ParseUint(strings.TrimSpace(valueStr), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid method %s %q: %w", valueName, entry, err)
+		}
+		values[method] = uint(value)
+	}
+	return values, nil
+}
@@ -0,0 +1,19 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMethodWeights(t *testing.T) {
+	weights, err := ParseMethodWeights([]string{"simulateTransaction=10", " getEvents = 2 "})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint{"simulateTransaction": 10, "getEvents": 2}, weights)
+
+	for _, entry := range []string{"simulateTransaction", "=1", "getEvents=-1", "getEvents=a"} {
+		_, err = ParseMethodWeights([]string{entry})
+		assert.Error(t, err, entry)
+	}
+}
@@ -0,0 +1,21 @@
+package config
+
+// The modes the daemon can run in (see the mode option)
+const (
+	// ModeCombined ingests the network and serves the API from the same process
+	ModeCombined = "combined"
+	// ModeIngest only ingests the network into the database, without serving the API
+	ModeIngest = "ingest"
+	// ModeServe only serves the API, from a database written by another process in ModeIngest
+	ModeServe = "serve"
+)
+
+// Ingests tells whether the daemon runs captive core and ingests the network into the database
+func (cfg *Config) Ingests() bool {
+	return cfg.Mode != ModeServe
+}
+
+// Serves tells whether the daemon serves the API
+func (cfg *Config) Serves() bool {
+	return cfg.Mode != ModeIngest
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var networkNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// NetworkConfig is an additional network served by the same process
+type NetworkConfig struct {
+	// Name of the network, used as the URL prefix of its requests
+	Name string
+	// ConfigPath is the path of the (TOML) configuration file of the network
+	ConfigPath string
+}
+
+func parseNetworkConfigs(values []string) ([]NetworkConfig, error) {
+	var networks []NetworkConfig
+	names := map[string]bool{}
+	for _, value := range values {
+		name, path, ok := strings.Cut(value, "=")
+		name, path = strings.TrimSpace(name), strings.TrimSpace(path)
+		if !ok || path == "" {
+			return nil, fmt.Errorf("%q must be a name=path pair", value)
+		}
+		if !networkNameRegexp.MatchString(name) {
+			return nil, fmt.Errorf("invalid network name %q (only lowercase letters, digits, '-' and '_' are allowed)", name)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("duplicated network name %q", name)
+		}
+		names[name] = true
+		networks = append(networks, NetworkConfig{Name: name, ConfigPath: path})
+	}
+	return networks, nil
+}
+
+// Networks returns the additional networks served by the same process
+func (cfg *Config) Networks() []NetworkConfig {
+	// the values were validated when checking the configuration
+	networks, _ := parseNetworkConfigs(cfg.NetworkConfigs)
+	return networks
+}
+
+// Load loads and validates the configuration of the network. Only the configuration file is used
+// (i.e. the environment variables and the command line flags of the process are ignored).
+func (n NetworkConfig) Load() (*Config, error) {
+	cfg := &Config{ConfigPath: n.ConfigPath}
+	noEnv := func(string) (string, bool) { return "", false }
+	if err := cfg.SetValues(noEnv); err != nil {
+		return nil, fmt.Errorf("could not load the configuration of network %s: %w", n.Name, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration of network %s: %w", n.Name, err)
+	}
+	if len(cfg.NetworkConfigs) > 0 {
+		return nil, fmt.Errorf("invalid configuration of network %s: additional networks cannot be nested", n.Name)
+	}
+	return cfg, nil
+}
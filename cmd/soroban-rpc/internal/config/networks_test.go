@@ -0,0 +1,58 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNetworkConfigs(t *testing.T) {
+	networks, err := parseNetworkConfigs([]string{"testnet=/etc/testnet.toml", " futurenet = /etc/futurenet.toml"})
+	require.NoError(t, err)
+	assert.Equal(t, []NetworkConfig{
+		{Name: "testnet", ConfigPath: "/etc/testnet.toml"},
+		{Name: "futurenet", ConfigPath: "/etc/futurenet.toml"},
+	}, networks)
+
+	for value, expectedErr := range map[string]string{
+		"testnet":                `"testnet" must be a name=path pair`,
+		"testnet=":               `"testnet=" must be a name=path pair`,
+		"Test/net=/etc/a.toml":   `invalid network name "Test/net"`,
+		"a=/etc/a.toml,a=/etc/b": `duplicated network name "a"`,
+	} {
+		_, err := parseNetworkConfigs(strings.Split(value, ","))
+		assert.ErrorContains(t, err, expectedErr, value)
+	}
+}
+
+func TestNetworkConfigLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "futurenet.toml")
+	contents := `
+NETWORK_PASSPHRASE = "Test SDF Future Network ; October 2022"
+HISTORY_ARCHIVE_URLS = ["https://history-futurenet.stellar.org"]
+STELLAR_CORE_BINARY_PATH = "/usr/bin/stellar-core"
+CAPTIVE_CORE_CONFIG_PATH = "/etc/futurenet-core.cfg"
+DB_PATH = "/var/lib/soroban-rpc/futurenet.sqlite"
+`
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+	// the environment of the process is ignored
+	t.Setenv("NETWORK_PASSPHRASE", "Test SDF Network ; September 2015")
+
+	cfg, err := NetworkConfig{Name: "futurenet", ConfigPath: path}.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "Test SDF Future Network ; October 2022", cfg.NetworkPassphrase)
+	assert.Equal(t, "/var/lib/soroban-rpc/futurenet.sqlite", cfg.SQLiteDBPath)
+
+	nested := contents + `NETWORK_CONFIGS = ["testnet=/etc/testnet.toml"]` + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(nested), 0o600))
+	_, err = NetworkConfig{Name: "futurenet", ConfigPath: path}.Load()
+	require.ErrorContains(t, err, "additional networks cannot be nested")
+
+	_, err = NetworkConfig{Name: "futurenet", ConfigPath: filepath.Join(dir, "missing.toml")}.Load()
+	require.ErrorContains(t, err, "could not load the configuration of network futurenet")
+}
@@ -2,23 +2,34 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
 	"reflect"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/stellar/go/network"
 	"github.com/stellar/go/support/strutils"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/tracing"
 )
 
 const (
 	// OneDayOfLedgers is (roughly) a 24 hour window of ledgers.
 	OneDayOfLedgers = 17280
 
+	// InMemoryDBPath is the db-path selecting an ephemeral, in-memory database
+	InMemoryDBPath = ":memory:"
+	// MaxInMemoryHistoryRetentionWindow bounds the history retention window of in-memory databases
+	MaxInMemoryHistoryRetentionWindow = OneDayOfLedgers
+
 	defaultHTTPEndpoint = "localhost:8000"
 )
 
@@ -46,6 +57,31 @@ func (cfg *Config) options() Options {
 			ConfigKey:    &cfg.Strict,
 			DefaultValue: false,
 		},
+		{
+			Name: "mode",
+			Usage: fmt.Sprintf("%q (default) ingests the network and serves the API, %q only ingests into the database"+
+				" (without listening on the endpoint) and %q only serves the API from the database shared with an"+
+				" ingesting process, following its progress (stellar-core-url must point to the stellar-core of"+
+				" the ingesting process). Upgrade the ingesting process before the serving ones, so that it applies the database migrations",
+				ModeCombined, ModeIngest, ModeServe),
+			ConfigKey:    &cfg.Mode,
+			DefaultValue: ModeCombined,
+			Validate: func(option *Option) error {
+				switch cfg.Mode {
+				case ModeCombined, ModeIngest:
+					return nil
+				case ModeServe:
+					if cfg.SQLiteDBPath == InMemoryDBPath {
+						return fmt.Errorf("%s %s cannot be used with an in-memory database, which cannot be shared",
+							option.Name, ModeServe)
+					}
+					return nil
+				default:
+					return fmt.Errorf("invalid %s %q, it must be %q, %q or %q",
+						option.Name, cfg.Mode, ModeCombined, ModeIngest, ModeServe)
+				}
+			},
+		},
 		{
 			Name:         "endpoint",
 			Usage:        "Endpoint to listen and serve on",
@@ -54,9 +90,140 @@ func (cfg *Config) options() Options {
 		},
 		{
 			Name:      "admin-endpoint",
-			Usage:     "Admin endpoint to listen and serve on. WARNING: this should not be accessible from the Internet and does not use TLS. \"\" (default) disables the admin server",
+			Usage:     "Admin endpoint to listen and serve on. WARNING: this should not be accessible from the Internet. \"\" (default) disables the admin server",
 			ConfigKey: &cfg.AdminEndpoint,
 		},
+		{
+			Name:      "admin-api-token",
+			Usage:     "Bearer token required by the state-changing admin API endpoints (e.g. /reingest), which are disabled when \"\" (default)",
+			ConfigKey: &cfg.AdminAPIToken,
+		},
+		{
+			Name:      "profile-dir",
+			Usage:     "Directory where the profiles captured through the admin API (at /profiles) or triggered automatically are written. When \"\" (default), the captured profiles are returned by the admin API instead",
+			ConfigKey: &cfg.ProfileDir,
+		},
+		{
+			Name:         "profile-trigger-request-duration",
+			Usage:        "Automatically capture profiles into profile-dir when a JSON RPC request takes longer than this (0, the default, disables it)",
+			ConfigKey:    &cfg.ProfileTriggerRequestDuration,
+			DefaultValue: time.Duration(0),
+			Validate: func(_ *Option) error {
+				if cfg.ProfileTriggerRequestDuration > 0 && cfg.ProfileDir == "" {
+					return fmt.Errorf("profile-trigger-request-duration requires profile-dir")
+				}
+				return nil
+			},
+		},
+		{
+			Name:         "profile-trigger-heap-size",
+			Usage:        "Automatically capture profiles into profile-dir when the heap in use grows over this amount of bytes (0, the default, disables it)",
+			ConfigKey:    &cfg.ProfileTriggerHeapSize,
+			DefaultValue: uint(0),
+			Validate: func(_ *Option) error {
+				if cfg.ProfileTriggerHeapSize > 0 && cfg.ProfileDir == "" {
+					return fmt.Errorf("profile-trigger-heap-size requires profile-dir")
+				}
+				return nil
+			},
+		},
+		{
+			Name:      "tls-cert-file",
+			Usage:     "TLS certificate file. When set (together with tls-key-file) the JSON RPC and admin endpoints are served over HTTPS. The certificate is reloaded when the files change",
+			ConfigKey: &cfg.TLSCertFile,
+			Validate: func(_ *Option) error {
+				if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+					return fmt.Errorf("tls-cert-file and tls-key-file must be set together")
+				}
+				return nil
+			},
+		},
+		{
+			Name:      "tls-key-file",
+			Usage:     "private key file of the TLS certificate",
+			ConfigKey: &cfg.TLSKeyFile,
+		},
+		{
+			Name:         "enable-graphql",
+			Usage:        "Enable the GraphQL query endpoint (served at /graphql), an alternative read surface over the JSON RPC methods",
+			ConfigKey:    &cfg.EnableGraphQL,
+			DefaultValue: false,
+		},
+		{
+			Name:         "enable-horizon-api",
+			Usage:        "Enable the Horizon-compatible REST endpoints (served at /horizon) for transactions, accounts and ledgers",
+			ConfigKey:    &cfg.EnableHorizonAPI,
+			DefaultValue: false,
+		},
+		{
+			Name:         "enable-subscriptions",
+			Usage:        "Enable the WebSocket subscriptions endpoint (served at /ws), which pushes the ingested ledgers and their applied transactions",
+			ConfigKey:    &cfg.EnableSubscriptions,
+			DefaultValue: false,
+		},
+		{
+			Name:         "max-subscription-connections",
+			Usage:        "Maximum number of concurrent WebSocket connections to the subscriptions endpoint",
+			ConfigKey:    &cfg.MaxSubscriptionConnections,
+			DefaultValue: uint(100),
+			Validate:     positive,
+		},
+		{
+			Name:         "max-subscription-connections-per-client",
+			Usage:        "Maximum number of concurrent WebSocket connections to the subscriptions endpoint from a single client IP",
+			ConfigKey:    &cfg.MaxSubscriptionConnectionsPerClient,
+			DefaultValue: uint(10),
+			Validate:     positive,
+		},
+		{
+			Name:         "enable-response-compression",
+			Usage:        "Compress (using gzip) the responses of clients accepting it",
+			ConfigKey:    &cfg.EnableResponseCompression,
+			DefaultValue: false,
+		},
+		{
+			Name:         "enable-zstd-response-compression",
+			Usage:        "Also offer zstd compression (preferred over gzip when accepted by the client). Requires enable-response-compression",
+			ConfigKey:    &cfg.EnableZstdResponseCompression,
+			DefaultValue: false,
+		},
+		{
+			Name:         "response-compression-min-size",
+			Usage:        "Minimum size (in bytes) of the responses to compress",
+			ConfigKey:    &cfg.ResponseCompressionMinSize,
+			DefaultValue: uint(1024),
+		},
+		{
+			Name:      "cors-allowed-origins",
+			Usage:     "comma-separated list of the origins allowed to perform cross-origin requests. Origins can contain a \"*\" wildcard (e.g. https://*.example.com). All origins are allowed when empty (default)",
+			ConfigKey: &cfg.CORSAllowedOrigins,
+			Validate: func(_ *Option) error {
+				for _, origin := range cfg.CORSAllowedOrigins {
+					if strings.Count(origin, "*") > 1 {
+						return fmt.Errorf("invalid cors-allowed-origins entry %q: only one wildcard is allowed", origin)
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Name:         "cors-allowed-headers",
+			Usage:        "comma-separated list of the headers allowed in cross-origin requests",
+			ConfigKey:    &cfg.CORSAllowedHeaders,
+			DefaultValue: []string{"*"},
+		},
+		{
+			Name:         "cors-allowed-methods",
+			Usage:        "comma-separated list of the HTTP methods allowed in cross-origin requests",
+			ConfigKey:    &cfg.CORSAllowedMethods,
+			DefaultValue: []string{"GET", "PUT", "POST", "PATCH", "DELETE", "HEAD", "OPTIONS"},
+		},
+		{
+			Name:         "cors-max-age",
+			Usage:        "how long browsers can cache the results of cross-origin preflight requests (0, the default, lets browsers decide)",
+			ConfigKey:    &cfg.CORSMaxAge,
+			DefaultValue: time.Duration(0),
+		},
 		{
 			Name:      "stellar-core-url",
 			Usage:     "URL used to query Stellar Core (local captive core by default)",
@@ -139,18 +306,109 @@ func (cfg *Config) options() Options {
 				return cfg.LogFormat.String()
 			},
 		},
+		{
+			Name:      "access-log-path",
+			Usage:     "path of the file to write a (JSON) access log entry to for every JSON RPC call, separately from the application logs. \"\" (default) disables the access log",
+			ConfigKey: &cfg.AccessLogPath,
+		},
+		{
+			Name:         "access-log-sample-ratio",
+			Usage:        "fraction (between 0 and 1) of the HTTP requests which are recorded in the access log",
+			ConfigKey:    &cfg.AccessLogSampleRatio,
+			DefaultValue: float64(1),
+			Validate: func(_ *Option) error {
+				if cfg.AccessLogSampleRatio < 0 || cfg.AccessLogSampleRatio > 1 {
+					return fmt.Errorf("access-log-sample-ratio must be between 0 and 1, got %v", cfg.AccessLogSampleRatio)
+				}
+				return nil
+			},
+		},
+		{
+			Name:      "tracing-otlp-endpoint",
+			Usage:     "URL of the OTLP/HTTP collector to export OpenTelemetry traces to (e.g. http://localhost:4318). \"\" (default) disables tracing",
+			ConfigKey: &cfg.TracingOTLPEndpoint,
+		},
+		{
+			Name:      "tracing-otlp-headers",
+			Usage:     "comma-separated list of <key>=<value> headers sent along with the exported traces (e.g. for authentication)",
+			ConfigKey: &cfg.TracingOTLPHeaders,
+			Validate: func(_ *Option) error {
+				_, err := tracing.ParseHeaders(cfg.TracingOTLPHeaders)
+				return err
+			},
+		},
+		{
+			Name:         "tracing-sample-ratio",
+			Usage:        "fraction (between 0 and 1) of the traces which are sampled and exported",
+			ConfigKey:    &cfg.TracingSampleRatio,
+			DefaultValue: float64(1),
+			Validate: func(_ *Option) error {
+				if cfg.TracingSampleRatio < 0 || cfg.TracingSampleRatio > 1 {
+					return fmt.Errorf("tracing-sample-ratio must be between 0 and 1, got %v", cfg.TracingSampleRatio)
+				}
+				return nil
+			},
+		},
 		{
 			Name:         "stellar-core-binary-path",
 			Usage:        "path to stellar core binary",
 			ConfigKey:    &cfg.StellarCoreBinaryPath,
 			DefaultValue: defaultStellarCoreBinaryPath,
-			Validate:     required,
+			Validate: func(option *Option) error {
+				if cfg.CoreBinaryAuto || !cfg.Ingests() {
+					// the binary is downloaded on startup, or captive core isn't run at all
+					return nil
+				}
+				return required(option)
+			},
+		},
+		{
+			Name:         "core-binary-auto",
+			Usage:        "download the stellar-core build pinned by core-binary-url and core-binary-sha256 on startup (into the captive core storage path), instead of using stellar-core-binary-path",
+			ConfigKey:    &cfg.CoreBinaryAuto,
+			DefaultValue: false,
+		},
+		{
+			Name:      "core-binary-url",
+			Usage:     "URL of the stellar-core binary downloaded when core-binary-auto is enabled",
+			ConfigKey: &cfg.CoreBinaryURL,
+			Validate: func(option *Option) error {
+				if !cfg.CoreBinaryAuto {
+					return nil
+				}
+				if cfg.CoreBinaryURL == "" {
+					return fmt.Errorf("%s is required when core-binary-auto is enabled", option.Name)
+				}
+				if _, err := url.ParseRequestURI(cfg.CoreBinaryURL); err != nil {
+					return fmt.Errorf("invalid %s: %w", option.Name, err)
+				}
+				return nil
+			},
+		},
+		{
+			Name:      "core-binary-sha256",
+			Usage:     "hex-encoded SHA-256 checksum of the stellar-core binary downloaded when core-binary-auto is enabled",
+			ConfigKey: &cfg.CoreBinarySHA256,
+			Validate: func(option *Option) error {
+				if !cfg.CoreBinaryAuto {
+					return nil
+				}
+				if checksum, err := hex.DecodeString(cfg.CoreBinarySHA256); err != nil || len(checksum) != sha256.Size {
+					return fmt.Errorf("%s must be a hex-encoded SHA-256 checksum when core-binary-auto is enabled", option.Name)
+				}
+				return nil
+			},
 		},
 		{
 			Name:      "captive-core-config-path",
 			Usage:     "path to additional configuration for the Stellar Core configuration file used by captive core. It must, at least, include enough details to define a quorum set",
 			ConfigKey: &cfg.CaptiveCoreConfigPath,
-			Validate:  required,
+			Validate: func(option *Option) error {
+				if !cfg.Ingests() {
+					return nil
+				}
+				return required(option)
+			},
 		},
 		{
 			Name:      "captive-core-storage-path",
@@ -198,17 +456,137 @@ func (cfg *Config) options() Options {
 			Validate:  required,
 		},
 		{
-			Name:         "db-path",
-			Usage:        "SQLite DB path",
+			Name: "network-configs",
+			Usage: "comma-separated list of additional networks served by the same process, as name=path pairs (e.g. futurenet=/etc/soroban-rpc/futurenet.toml)." +
+				" Every network runs its own captive core and database (configured by the file) and its requests are served under the /<name> URL prefix of the endpoint",
+			ConfigKey: &cfg.NetworkConfigs,
+			Validate: func(option *Option) error {
+				_, err := parseNetworkConfigs(cfg.NetworkConfigs)
+				if err != nil {
+					return fmt.Errorf("invalid %s: %w", option.Name, err)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "protocol-upgrade-schedule",
+			Usage: "comma-separated list of the network protocol upgrades scheduled by the validators, as version@time pairs" +
+				" (e.g. 22@2024-09-01T17:00:00Z, with RFC3339 times). getHealth reports whether the upcoming upgrades are supported" +
+				" and a warning is logged on startup if they aren't",
+			ConfigKey: &cfg.ProtocolUpgradeSchedule,
+			Validate: func(option *Option) error {
+				_, err := parseProtocolUpgradeSchedule(cfg.ProtocolUpgradeSchedule)
+				if err != nil {
+					return fmt.Errorf("invalid %s: %w", option.Name, err)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "db-path",
+			Usage: fmt.Sprintf(
+				"SQLite DB path, %q selects an ephemeral in-memory database (lost on exit, for development and CI)"+
+					" whose history-retention-window cannot exceed %d ledgers",
+				InMemoryDBPath, MaxInMemoryHistoryRetentionWindow),
 			ConfigKey:    &cfg.SQLiteDBPath,
 			DefaultValue: "soroban_rpc.sqlite",
+			Validate: func(_ *Option) error {
+				if cfg.SQLiteDBPath == InMemoryDBPath && cfg.HistoryRetentionWindow > MaxInMemoryHistoryRetentionWindow {
+					return fmt.Errorf(
+						"the history-retention-window of in-memory databases cannot exceed %d ledgers",
+						MaxInMemoryHistoryRetentionWindow,
+					)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "db-read-connections",
+			Usage: "Number of read-only database connections kept open to serve the requests, which are separate from the connection" +
+				" used by ingestion (additional connections are opened under load)",
+			ConfigKey:    &cfg.DBReadConnections,
+			DefaultValue: uint(runtime.NumCPU()),
+			Validate:     positive,
+		},
+		{
+			Name: "db-read-busy-timeout",
+			Usage: "Maximum time a database read waits for the locks held by ingestion (e.g. while the write-ahead log is" +
+				" checkpointed after a ledger is committed) before being retried",
+			ConfigKey:    &cfg.DBReadBusyTimeout,
+			DefaultValue: 5 * time.Second,
+			Validate: func(option *Option) error {
+				if cfg.DBReadBusyTimeout <= 0 {
+					return fmt.Errorf("%s must be positive", option.Name)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "bootstrap-snapshot-url",
+			Usage: "Database snapshot (a file path, or an HTTP(S), s3:// or gcs:// URL) used to initialize the database when it doesn't exist," +
+				" so that only the ledgers closed since the snapshot are ingested from the network. It can also be the manifest.json" +
+				" of the snapshots published by another node (see snapshot-publish-url)",
+			ConfigKey: &cfg.BootstrapSnapshotURL,
+			Validate: func(option *Option) error {
+				if cfg.BootstrapSnapshotURL != "" && cfg.SQLiteDBPath == InMemoryDBPath {
+					return fmt.Errorf("%s cannot be used with an in-memory database", option.Name)
+				}
+				if cfg.BootstrapSnapshotURL != "" && !cfg.Ingests() {
+					return fmt.Errorf("%s cannot be used in mode %s, the database is bootstrapped by the ingesting process",
+						option.Name, cfg.Mode)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "bootstrap-snapshot-checksum",
+			Usage: "hex-encoded SHA-256 checksum of the bootstrap snapshot. When empty, it is read from the manifest or from the file" +
+				" published alongside the snapshot (the snapshot URL followed by .sha256)",
+			ConfigKey: &cfg.BootstrapSnapshotChecksum,
+		},
+		{
+			Name: "snapshot-publish-url",
+			Usage: "Location (a directory path, or an s3:// or gcs:// URL) where a snapshot of the database is periodically published," +
+				" together with a manifest (manifest.json) from which new nodes can be bootstrapped with bootstrap-snapshot-url",
+			ConfigKey: &cfg.SnapshotPublishURL,
+			Validate: func(option *Option) error {
+				if cfg.SnapshotPublishURL != "" && cfg.SQLiteDBPath == InMemoryDBPath {
+					return fmt.Errorf("%s cannot be used with an in-memory database", option.Name)
+				}
+				return nil
+			},
+		},
+		{
+			Name:         "snapshot-publish-interval",
+			Usage:        "Interval between the publications of the database snapshots (see snapshot-publish-url)",
+			ConfigKey:    &cfg.SnapshotPublishInterval,
+			DefaultValue: 24 * time.Hour,
+			Validate: func(option *Option) error {
+				if cfg.SnapshotPublishInterval <= 0 {
+					return fmt.Errorf("%s must be positive", option.Name)
+				}
+				return nil
+			},
 		},
 		{
 			Name:         "ingestion-timeout",
-			Usage:        "Ingestion Timeout when bootstrapping data (checkpoint and in-memory initialization) and preparing ledger reads",
+			Usage:        "Ingestion Timeout when bootstrapping data (snapshot download, checkpoint and in-memory initialization) and preparing ledger reads",
 			ConfigKey:    &cfg.IngestionTimeout,
 			DefaultValue: 50 * time.Minute,
 		},
+		{
+			Name: "shutdown-grace-period",
+			Usage: "Maximum time given to the in-flight requests (including simulations) to complete when shutting down." +
+				" New requests are refused in the meantime, and the requests still running afterwards are aborted",
+			ConfigKey:    &cfg.ShutdownGracePeriod,
+			DefaultValue: 10 * time.Second,
+			Validate: func(option *Option) error {
+				if cfg.ShutdownGracePeriod <= 0 {
+					return fmt.Errorf("%s must be positive", option.Name)
+				}
+				return nil
+			},
+		},
 		{
 			Name:         "checkpoint-frequency",
 			Usage:        "establishes how many ledgers exist between checkpoints, do NOT change this unless you really know what you are doing",
@@ -267,6 +645,58 @@ func (cfg *Config) options() Options {
 			ConfigKey:    &cfg.MaxEventsLimit,
 			DefaultValue: uint(10000),
 		},
+		{
+			Name:         "max-events-ledger-range",
+			Usage:        "Maximum width of the ledger range (from startLedger to the latest ledger) scanned by a single getEvents request. 0 (default) means unlimited",
+			ConfigKey:    &cfg.MaxEventsLedgerRange,
+			DefaultValue: uint32(0),
+		},
+		{
+			Name:         "max-event-filters",
+			Usage:        "Maximum amount of filters in a single getEvents request. 0 means unlimited",
+			ConfigKey:    &cfg.MaxEventFilters,
+			DefaultValue: uint(5),
+		},
+		{
+			Name:         "max-event-filter-contract-ids",
+			Usage:        "Maximum amount of contract ids in a single getEvents filter. 0 means unlimited",
+			ConfigKey:    &cfg.MaxEventFilterContractIDs,
+			DefaultValue: uint(5),
+		},
+		{
+			Name:         "max-event-filter-topics",
+			Usage:        "Maximum amount of topics in a single getEvents filter. 0 means unlimited",
+			ConfigKey:    &cfg.MaxEventFilterTopics,
+			DefaultValue: uint(5),
+		},
+		{
+			Name:         "max-ledger-entries-keys",
+			Usage:        "Maximum amount of keys in a single getLedgerEntries request. 0 means unlimited",
+			ConfigKey:    &cfg.MaxLedgerEntriesKeys,
+			DefaultValue: uint(200),
+		},
+		{
+			Name:         "max-request-size",
+			Usage:        "Maximum size (in bytes) of HTTP request bodies",
+			ConfigKey:    &cfg.MaxRequestSize,
+			DefaultValue: uint(512 * 1024),
+			Validate:     positive,
+		},
+		{
+			Name:      "max-request-params-size",
+			Usage:     "comma-separated list of <method>=<bytes> entries establishing the maximum size of the (JSON encoded) parameters of each method, e.g. simulateTransaction=65536",
+			ConfigKey: &cfg.MaxRequestParamsSize,
+			Validate: func(_ *Option) error {
+				_, err := ParseMethodLimits(cfg.MaxRequestParamsSize)
+				return err
+			},
+		},
+		{
+			Name:         "max-response-size",
+			Usage:        "Maximum size (in bytes) of the results materialized by a single getEvents, getLedgerEntries or getTransactions request, beyond which the request fails asking to narrow the query. 0 means unlimited",
+			ConfigKey:    &cfg.MaxResponseSize,
+			DefaultValue: uint(32 * 1024 * 1024),
+		},
 		{
 			Name:         "default-events-limit",
 			Usage:        "Default cap on the amount of events included in a single getEvents response",
@@ -305,6 +735,28 @@ func (cfg *Config) options() Options {
 				return nil
 			},
 		},
+		{
+			Name:         "max-contract-entries-limit",
+			Usage:        "Maximum amount of entries allowed in a single getContractEntries response",
+			ConfigKey:    &cfg.MaxContractEntriesLimit,
+			DefaultValue: uint(200),
+		},
+		{
+			Name:         "default-contract-entries-limit",
+			Usage:        "Default cap on the amount of entries included in a single getContractEntries response",
+			ConfigKey:    &cfg.DefaultContractEntriesLimit,
+			DefaultValue: uint(50),
+			Validate: func(_ *Option) error {
+				if cfg.DefaultContractEntriesLimit > cfg.MaxContractEntriesLimit {
+					return fmt.Errorf(
+						"default-contract-entries-limit (%v) cannot exceed max-contract-entries-limit (%v)",
+						cfg.DefaultContractEntriesLimit,
+						cfg.MaxContractEntriesLimit,
+					)
+				}
+				return nil
+			},
+		},
 		{
 			Name: "max-healthy-ledger-latency",
 			Usage: "maximum ledger latency (i.e. time elapsed since the last known ledger closing time) considered to be healthy" +
@@ -312,6 +764,25 @@ func (cfg *Config) options() Options {
 			ConfigKey:    &cfg.MaxHealthyLedgerLatency,
 			DefaultValue: 30 * time.Second,
 		},
+		{
+			Name:         "circuit-breaker-max-ledger-lag",
+			Usage:        "maximum time elapsed since the latest ingested ledger closed before sendTransaction and simulateTransaction fail fast, reporting the node as degraded (0 disables the circuit breaker)",
+			ConfigKey:    &cfg.CircuitBreakerMaxLedgerLag,
+			DefaultValue: time.Minute,
+		},
+		{
+			Name: "systemd-watchdog-stall-timeout",
+			Usage: "when running as a systemd service with a watchdog (WatchdogSec), time without any ledger being ingested" +
+				" after which the watchdog stops being notified (letting systemd restart the node)",
+			ConfigKey:    &cfg.SystemdWatchdogStallTimeout,
+			DefaultValue: 5 * time.Minute,
+			Validate: func(option *Option) error {
+				if cfg.SystemdWatchdogStallTimeout <= 0 {
+					return fmt.Errorf("%s must be positive", option.Name)
+				}
+				return nil
+			},
+		},
 		{
 			Name:         "preflight-worker-count",
 			Usage:        "Number of workers (read goroutines) used to compute preflights for the simulateTransaction endpoint. Defaults to the number of CPUs.",
@@ -332,6 +803,138 @@ func (cfg *Config) options() Options {
 			ConfigKey:    &cfg.PreflightEnableDebug,
 			DefaultValue: true,
 		},
+		{
+			Name:         "rate-limit-global-rps",
+			Usage:        "Maximum number of requests per second accepted across all clients (0 disables the global rate limit)",
+			ConfigKey:    &cfg.RateLimitGlobalRequestsPerSecond,
+			DefaultValue: float64(0),
+			Validate:     nonNegative,
+		},
+		{
+			Name:         "rate-limit-global-burst",
+			Usage:        "Maximum burst of requests accepted across all clients (defaults to the global rate)",
+			ConfigKey:    &cfg.RateLimitGlobalBurst,
+			DefaultValue: uint(0),
+		},
+		{
+			Name:         "rate-limit-client-rps",
+			Usage:        "Maximum number of requests per second accepted from each client IP (0 disables the per-client rate limit)",
+			ConfigKey:    &cfg.RateLimitClientRequestsPerSecond,
+			DefaultValue: float64(0),
+			Validate:     nonNegative,
+		},
+		{
+			Name:         "rate-limit-client-burst",
+			Usage:        "Maximum burst of requests accepted from each client IP (defaults to the per-client rate)",
+			ConfigKey:    &cfg.RateLimitClientBurst,
+			DefaultValue: uint(0),
+		},
+		{
+			Name:      "rate-limit-method-weights",
+			Usage:     "comma-separated list of <method>=<weight> entries establishing how many requests each call to a method accounts for when rate limiting (1 by default), e.g. simulateTransaction=10",
+			ConfigKey: &cfg.RateLimitMethodWeights,
+			Validate: func(_ *Option) error {
+				_, err := ParseMethodWeights(cfg.RateLimitMethodWeights)
+				return err
+			},
+		},
+		{
+			Name:         "rate-limit-trust-forwarded-for",
+			Usage:        "Identify clients (when rate limiting and in the access log) through the X-Forwarded-For header. Only enable it when running behind a trusted proxy",
+			ConfigKey:    &cfg.RateLimitTrustForwardedFor,
+			DefaultValue: false,
+		},
+		{
+			Name:         "rate-limit-trusted-proxies",
+			Usage:        "Number of trusted proxies in front of Soroban-RPC appending to the X-Forwarded-For header, when identifying clients through it. Clients are identified by the entry appended by the outermost trusted proxy, since the entries to its left can be forged",
+			ConfigKey:    &cfg.RateLimitTrustedProxies,
+			DefaultValue: uint(1),
+			Validate: func(option *Option) error {
+				if cfg.RateLimitTrustForwardedFor && cfg.RateLimitTrustedProxies == 0 {
+					return fmt.Errorf("%s must be positive when rate-limit-trust-forwarded-for is enabled", option.Name)
+				}
+				return nil
+			},
+		},
+		{
+			Name:      "method-concurrency-limits",
+			Usage:     "comma-separated list of <method>=<limit> entries establishing the maximum number of concurrent calls to each method, e.g. simulateTransaction=20. Calls beyond the limit wait for the method's queue",
+			ConfigKey: &cfg.MethodConcurrencyLimits,
+			Validate: func(_ *Option) error {
+				_, err := ParseMethodLimits(cfg.MethodConcurrencyLimits)
+				return err
+			},
+		},
+		{
+			Name:      "method-queue-limits",
+			Usage:     "comma-separated list of <method>=<limit> entries establishing the maximum number of calls waiting for each concurrency-limited method (0 by default, rejecting the calls right away), e.g. simulateTransaction=100",
+			ConfigKey: &cfg.MethodQueueLimits,
+			Validate: func(_ *Option) error {
+				limits, err := ParseMethodLimits(cfg.MethodQueueLimits)
+				if err != nil {
+					return err
+				}
+				concurrencyLimits, err := ParseMethodLimits(cfg.MethodConcurrencyLimits)
+				if err != nil {
+					return err
+				}
+				for method := range limits {
+					if _, ok := concurrencyLimits[method]; !ok {
+						return fmt.Errorf("method %s has a queue limit but no concurrency limit", method)
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Name:         "method-queue-timeout",
+			Usage:        "Maximum amount of time calls wait in the queue of concurrency-limited methods before being rejected",
+			ConfigKey:    &cfg.MethodQueueTimeout,
+			DefaultValue: 5 * time.Second,
+		},
+		{
+			Name:      "peer-endpoint",
+			Usage:     "Endpoint on which to receive (mTLS-authenticated) transaction hints from peer Soroban-RPC nodes. \"\" (default) disables peer gossip",
+			ConfigKey: &cfg.PeerEndpoint,
+			Validate: func(_ *Option) error {
+				if cfg.PeerEndpoint == "" {
+					if len(cfg.PeerURLs) > 0 {
+						return fmt.Errorf("peer-urls requires peer-endpoint to be set")
+					}
+					return nil
+				}
+				if cfg.PeerTLSCertFile == "" || cfg.PeerTLSKeyFile == "" || cfg.PeerTLSCAFile == "" {
+					return fmt.Errorf("peer-endpoint requires peer-tls-cert-file, peer-tls-key-file and peer-tls-ca-file to be set")
+				}
+				return nil
+			},
+		},
+		{
+			Name:      "peer-urls",
+			Usage:     "comma-separated list of the (https) peer endpoint URLs of the Soroban-RPC nodes to share transaction hints with",
+			ConfigKey: &cfg.PeerURLs,
+		},
+		{
+			Name:      "peer-tls-cert-file",
+			Usage:     "TLS certificate presented to peer nodes, both when receiving and when sending transaction hints",
+			ConfigKey: &cfg.PeerTLSCertFile,
+		},
+		{
+			Name:      "peer-tls-key-file",
+			Usage:     "private key of the TLS certificate presented to peer nodes",
+			ConfigKey: &cfg.PeerTLSKeyFile,
+		},
+		{
+			Name:      "peer-tls-ca-file",
+			Usage:     "certificate authority used to authenticate peer nodes",
+			ConfigKey: &cfg.PeerTLSCAFile,
+		},
+		{
+			Name:         "peer-hint-ttl",
+			Usage:        "period of time during which transaction hints received from peers are kept",
+			ConfigKey:    &cfg.PeerHintTTL,
+			DefaultValue: 5 * time.Minute,
+		},
 		{
 			TomlKey:      strutils.KebabToConstantCase("request-backlog-global-queue-limit"),
 			Usage:        "Maximum number of outstanding requests",
@@ -416,6 +1019,48 @@ func (cfg *Config) options() Options {
 			DefaultValue: uint(100),
 			Validate:     positive,
 		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-token-metadata-queue-limit"),
+			Usage:        "Maximum number of outstanding GetTokenMetadata requests",
+			ConfigKey:    &cfg.RequestBacklogGetTokenMetadataQueueLimit,
+			DefaultValue: uint(100),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-fee-bump-queue-limit"),
+			Usage:        "Maximum number of outstanding GetFeeBump requests",
+			ConfigKey:    &cfg.RequestBacklogGetFeeBumpQueueLimit,
+			DefaultValue: uint(100),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-soroban-config-queue-limit"),
+			Usage:        "Maximum number of outstanding GetSorobanConfig requests",
+			ConfigKey:    &cfg.RequestBacklogGetSorobanConfigQueueLimit,
+			DefaultValue: uint(100),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-contract-entries-queue-limit"),
+			Usage:        "Maximum number of outstanding GetContractEntries requests",
+			ConfigKey:    &cfg.RequestBacklogGetContractEntriesQueueLimit,
+			DefaultValue: uint(100),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-contract-transactions-queue-limit"),
+			Usage:        "Maximum number of outstanding GetContractTransactions requests",
+			ConfigKey:    &cfg.RequestBacklogGetContractTransactionsQueueLimit,
+			DefaultValue: uint(1000),
+			Validate:     positive,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("request-backlog-get-event-queue-limit"),
+			Usage:        "Maximum number of outstanding GetEvent requests",
+			ConfigKey:    &cfg.RequestBacklogGetEventQueueLimit,
+			DefaultValue: uint(100),
+			Validate:     positive,
+		},
 		{
 			TomlKey:      strutils.KebabToConstantCase("request-execution-warning-threshold"),
 			Usage:        "The request execution warning threshold is the predetermined maximum duration of time that a request can take to be processed before a warning would be generated",
@@ -494,6 +1139,42 @@ func (cfg *Config) options() Options {
 			ConfigKey:    &cfg.MaxGetFeeStatsExecutionDuration,
 			DefaultValue: 5 * time.Second,
 		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-token-metadata-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getTokenMetadata request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetTokenMetadataExecutionDuration,
+			DefaultValue: 15 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-fee-bump-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getFeeBump request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetFeeBumpExecutionDuration,
+			DefaultValue: 5 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-soroban-config-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getSorobanConfig request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetSorobanConfigExecutionDuration,
+			DefaultValue: 5 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-contract-entries-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getContractEntries request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetContractEntriesExecutionDuration,
+			DefaultValue: 5 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-contract-transactions-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getContractTransactions request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetContractTransactionsExecutionDuration,
+			DefaultValue: 5 * time.Second,
+		},
+		{
+			TomlKey:      strutils.KebabToConstantCase("max-get-event-execution-duration"),
+			Usage:        "The maximum duration of time allowed for processing a getEvent request. When that time elapses, the rpc server would return -32001 and abort the request's execution",
+			ConfigKey:    &cfg.MaxGetEventExecutionDuration,
+			DefaultValue: 5 * time.Second,
+		},
 	}
 	return *cfg.optionsCache
 }
@@ -559,3 +1240,19 @@ func positive(option *Option) error {
 	}
 	return nil
 }
+
+func nonNegative(option *Option) error {
+	switch v := option.ConfigKey.(type) {
+	case *float32, *float64:
+		if reflect.ValueOf(v).Elem().Float() < 0 {
+			return fmt.Errorf("%s cannot be negative", option.Name)
+		}
+	case *int, *int8, *int16, *int32, *int64:
+		if reflect.ValueOf(v).Elem().Int() < 0 {
+			return fmt.Errorf("%s cannot be negative", option.Name)
+		}
+	default:
+		return fmt.Errorf("%s is not a signed number", option.Name)
+	}
+	return nil
+}
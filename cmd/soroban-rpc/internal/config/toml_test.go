@@ -115,6 +115,8 @@ func TestRoundTrip(t *testing.T) {
 			*option.ConfigKey.(*uint) = 42
 		case *uint32:
 			*option.ConfigKey.(*uint32) = 32
+		case *float64:
+			*option.ConfigKey.(*float64) = 1.5
 		case *time.Duration:
 			*option.ConfigKey.(*time.Duration) = 5 * time.Second
 		case *[]string:
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProtocolUpgrade is a network protocol upgrade scheduled by the validators
+type ProtocolUpgrade struct {
+	// Version is the ledger protocol version after the upgrade
+	Version uint32
+	// Time is when the upgrade is scheduled to take place
+	Time time.Time
+}
+
+func parseProtocolUpgradeSchedule(values []string) ([]ProtocolUpgrade, error) {
+	var upgrades []ProtocolUpgrade
+	versions := map[uint32]bool{}
+	for _, value := range values {
+		versionStr, timeStr, ok := strings.Cut(value, "@")
+		versionStr, timeStr = strings.TrimSpace(versionStr), strings.TrimSpace(timeStr)
+		if !ok || timeStr == "" {
+			return nil, fmt.Errorf("%q must be a version@time pair", value)
+		}
+		version, err := strconv.ParseUint(versionStr, 10, 32)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("invalid protocol version %q", versionStr)
+		}
+		upgradeTime, err := time.Parse(time.RFC3339, timeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid upgrade time %q (it must be in RFC3339 format)", timeStr)
+		}
+		if versions[uint32(version)] {
+			return nil, fmt.Errorf("duplicated protocol version %d", version)
+		}
+		versions[uint32(version)] = true
+		upgrades = append(upgrades, ProtocolUpgrade{Version: uint32(version), Time: upgradeTime})
+	}
+	sort.Slice(upgrades, func(i, j int) bool {
+		return upgrades[i].Version < upgrades[j].Version
+	})
+	for i := 1; i < len(upgrades); i++ {
+		if !upgrades[i].Time.After(upgrades[i-1].Time) {
+			return nil, fmt.Errorf(
+				"the upgrade to protocol %d must be scheduled after the upgrade to protocol %d",
+				upgrades[i].Version, upgrades[i-1].Version,
+			)
+		}
+	}
+	return upgrades, nil
+}
+
+// ProtocolUpgrades returns the scheduled protocol upgrades, sorted by version
+func (cfg *Config) ProtocolUpgrades() []ProtocolUpgrade {
+	// the values were validated when checking the configuration
+	upgrades, _ := parseProtocolUpgradeSchedule(cfg.ProtocolUpgradeSchedule)
+	return upgrades
+}
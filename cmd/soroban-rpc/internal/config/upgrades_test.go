@@ -0,0 +1,34 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProtocolUpgradeSchedule(t *testing.T) {
+	upgrades, err := parseProtocolUpgradeSchedule([]string{
+		"23@2025-01-15T17:00:00Z",
+		" 22 @ 2024-09-01T17:00:00Z",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []ProtocolUpgrade{
+		{Version: 22, Time: time.Date(2024, 9, 1, 17, 0, 0, 0, time.UTC)},
+		{Version: 23, Time: time.Date(2025, 1, 15, 17, 0, 0, 0, time.UTC)},
+	}, upgrades)
+
+	for value, expectedErr := range map[string]string{
+		"22":                       `"22" must be a version@time pair`,
+		"v22@2024-09-01T17:00:00Z": `invalid protocol version "v22"`,
+		"0@2024-09-01T17:00:00Z":   `invalid protocol version "0"`,
+		"22@2024-09-01":            `invalid upgrade time "2024-09-01"`,
+		"22@2024-09-01T17:00:00Z,22@2024-10-01T17:00:00Z": "duplicated protocol version 22",
+		"22@2024-09-01T17:00:00Z,23@2024-08-01T17:00:00Z": "the upgrade to protocol 23 must be scheduled after the upgrade to protocol 22",
+	} {
+		_, err := parseProtocolUpgradeSchedule(strings.Split(value, ","))
+		assert.ErrorContains(t, err, expectedErr, value)
+	}
+}
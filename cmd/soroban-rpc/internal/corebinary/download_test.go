@@ -0,0 +1,93 @@
+package corebinary
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownload(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho v21.0.0\n")
+	checksum := sha256.Sum256(binary)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "soroban-rpc/corebinary", r.Header.Get("User-Agent"))
+		if r.URL.Path != "/stellar-core" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(binary)
+	}))
+	defer server.Close()
+
+	cfg := DownloadConfig{
+		URL:       server.URL + "/stellar-core",
+		SHA256:    hex.EncodeToString(checksum[:]),
+		Dir:       t.TempDir(),
+		UserAgent: "soroban-rpc/corebinary",
+	}
+	binaryPath, err := Download(context.Background(), cfg)
+	require.NoError(t, err)
+	contents, err := os.ReadFile(binaryPath)
+	require.NoError(t, err)
+	assert.Equal(t, binary, contents)
+	info, err := os.Stat(binaryPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+	assert.Equal(t, 1, requests)
+
+	// the binary is reused
+	_, err = Download(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	// the binary is downloaded again if it got corrupted
+	require.NoError(t, os.WriteFile(binaryPath, []byte("corrupted"), 0o755))
+	_, err = Download(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+	contents, err = os.ReadFile(binaryPath)
+	require.NoError(t, err)
+	assert.Equal(t, binary, contents)
+}
+
+func TestDownloadChecksumMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("tampered"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	checksum := sha256.Sum256([]byte("original"))
+	_, err := Download(context.Background(), DownloadConfig{
+		URL:    server.URL,
+		SHA256: hex.EncodeToString(checksum[:]),
+		Dir:    dir,
+	})
+	require.ErrorContains(t, err, "checksum mismatch")
+	// nothing is left behind
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestDownloadNotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	checksum := sha256.Sum256([]byte("original"))
+	_, err := Download(context.Background(), DownloadConfig{
+		URL:    server.URL,
+		SHA256: hex.EncodeToString(checksum[:]),
+		Dir:    t.TempDir(),
+	})
+	require.ErrorContains(t, err, "unexpected status 404 Not Found")
+}
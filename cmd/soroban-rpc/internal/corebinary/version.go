@@ -0,0 +1,109 @@
+// Package corebinary verifies that the stellar-core binary used by captive core
+// is compatible with the network and downloads pinned (checksum-verified) builds.
+package corebinary
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/stellar/go/historyarchive"
+)
+
+// MinSupportedProtocolVersion is the first protocol version supporting Soroban,
+// which captive core must support in order to emit the Soroban metadata
+const MinSupportedProtocolVersion = 20
+
+const ledgerProtocolVersionPrefix = "ledger protocol version:"
+
+// Info describes a stellar-core binary
+type Info struct {
+	// Version is the stellar-core version (e.g. v21.0.0)
+	Version string
+	// LedgerProtocolVersion is the maximum ledger protocol version supported by the binary
+	LedgerProtocolVersion uint32
+}
+
+// GetInfo runs `stellar-core version` to obtain the version of the binary
+func GetInfo(ctx context.Context, binaryPath string) (Info, error) {
+	cmd := exec.CommandContext(ctx, binaryPath, "version")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return Info{}, fmt.Errorf("could not run %s version: %w (%s)", binaryPath, err, strings.TrimSpace(stderr.String()))
+	}
+	info, err := parseVersionOutput(out)
+	if err != nil {
+		return Info{}, fmt.Errorf("unexpected output of %s version: %w", binaryPath, err)
+	}
+	return info, nil
+}
+
+// parseVersionOutput parses the output of `stellar-core version`, which starts with the version
+// followed by the supported ledger protocol version (and the versions of the Soroban hosts):
+//
+//	v21.0.0
+//	ledger protocol version: 21
+//	rust version: rustc 1.77.0
+//	...
+func parseVersionOutput(out []byte) (Info, error) {
+	var info Info
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if info.Version == "" {
+			info.Version = line
+			continue
+		}
+		if protocol, ok := strings.CutPrefix(line, ledgerProtocolVersionPrefix); ok {
+			version, err := strconv.ParseUint(strings.TrimSpace(protocol), 10, 32)
+			if err != nil {
+				return Info{}, fmt.Errorf("invalid ledger protocol version %q", strings.TrimSpace(protocol))
+			}
+			info.LedgerProtocolVersion = uint32(version)
+			return info, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Info{}, err
+	}
+	return Info{}, fmt.Errorf("missing %q", ledgerProtocolVersionPrefix)
+}
+
+// CheckProtocolVersion verifies that the binary supports the protocol version of the network
+func (info Info) CheckProtocolVersion(networkProtocolVersion uint32) error {
+	if info.LedgerProtocolVersion < MinSupportedProtocolVersion {
+		return fmt.Errorf(
+			"stellar-core %s only supports up to protocol %d, but at least protocol %d is required, please upgrade stellar-core",
+			info.Version, info.LedgerProtocolVersion, MinSupportedProtocolVersion,
+		)
+	}
+	if info.LedgerProtocolVersion < networkProtocolVersion {
+		return fmt.Errorf(
+			"stellar-core %s only supports up to protocol %d, but the network is on protocol %d, please upgrade stellar-core",
+			info.Version, info.LedgerProtocolVersion, networkProtocolVersion,
+		)
+	}
+	return nil
+}
+
+// GetNetworkProtocolVersion obtains the protocol version of the latest ledger published in the history archive
+func GetNetworkProtocolVersion(archive historyarchive.ArchiveInterface) (uint32, error) {
+	has, err := archive.GetRootHAS()
+	if err != nil {
+		return 0, fmt.Errorf("could not get the root history archive state: %w", err)
+	}
+	header, err := archive.GetLedgerHeader(has.CurrentLedger)
+	if err != nil {
+		return 0, fmt.Errorf("could not get the header of ledger %d: %w", has.CurrentLedger, err)
+	}
+	return uint32(header.Header.LedgerVersion), nil
+}
@@ -0,0 +1,70 @@
+package corebinary
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/historyarchive"
+	"github.com/stellar/go/xdr"
+)
+
+const versionOutput = `v21.0.0
+ledger protocol version: 21
+rust version: rustc 1.77.0 (aedd173a2 2024-03-17)
+soroban-env-host:
+    curr:
+        package version: 21.0.1
+`
+
+func TestParseVersionOutput(t *testing.T) {
+	info, err := parseVersionOutput([]byte(versionOutput))
+	require.NoError(t, err)
+	assert.Equal(t, Info{Version: "v21.0.0", LedgerProtocolVersion: 21}, info)
+
+	_, err = parseVersionOutput([]byte("v21.0.0\n"))
+	require.ErrorContains(t, err, `missing "ledger protocol version:"`)
+
+	_, err = parseVersionOutput([]byte("v21.0.0\nledger protocol version: abc\n"))
+	require.ErrorContains(t, err, `invalid ledger protocol version "abc"`)
+}
+
+func TestGetInfo(t *testing.T) {
+	binaryPath := filepath.Join(t.TempDir(), "stellar-core")
+	script := "#!/bin/sh\n[ \"$1\" = version ] || exit 1\ncat <<EOF\n" + versionOutput + "EOF\n"
+	require.NoError(t, os.WriteFile(binaryPath, []byte(script), 0o755))
+	info, err := GetInfo(context.Background(), binaryPath)
+	require.NoError(t, err)
+	assert.Equal(t, Info{Version: "v21.0.0", LedgerProtocolVersion: 21}, info)
+
+	_, err = GetInfo(context.Background(), filepath.Join(t.TempDir(), "missing"))
+	require.ErrorContains(t, err, "could not run")
+}
+
+func TestCheckProtocolVersion(t *testing.T) {
+	info := Info{Version: "v21.0.0", LedgerProtocolVersion: 21}
+	require.NoError(t, info.CheckProtocolVersion(20))
+	require.NoError(t, info.CheckProtocolVersion(21))
+	require.EqualError(t, info.CheckProtocolVersion(22),
+		"stellar-core v21.0.0 only supports up to protocol 21, but the network is on protocol 22, please upgrade stellar-core")
+
+	info = Info{Version: "v19.14.0", LedgerProtocolVersion: 19}
+	require.EqualError(t, info.CheckProtocolVersion(0),
+		"stellar-core v19.14.0 only supports up to protocol 19, but at least protocol 20 is required, please upgrade stellar-core")
+}
+
+func TestGetNetworkProtocolVersion(t *testing.T) {
+	archive := &historyarchive.MockArchive{}
+	archive.On("GetRootHAS").Return(historyarchive.HistoryArchiveState{CurrentLedger: 127}, nil)
+	archive.On("GetLedgerHeader", uint32(127)).Return(xdr.LedgerHeaderHistoryEntry{
+		Header: xdr.LedgerHeader{LedgerSeq: 127, LedgerVersion: 21},
+	}, nil)
+	version, err := GetNetworkProtocolVersion(archive)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(21), version)
+	archive.AssertExpectations(t)
+}
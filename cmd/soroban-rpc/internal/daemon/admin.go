@@ -0,0 +1,27 @@
+package daemon
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// statuses of the jobs started through the admin API
+const (
+	jobStatusRunning = "running"
+	jobStatusDone    = "done"
+	jobStatusFailed  = "failed"
+)
+
+// requireBearerToken only lets the requests authenticated with the given token through
+func requireBearerToken(token string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		provided, found := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
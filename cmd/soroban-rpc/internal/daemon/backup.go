@@ -0,0 +1,116 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	supportlog "github.com/stellar/go/support/log"
+)
+
+type backupFn func(ctx context.Context, target string) error
+
+// backupRunner runs the database backups requested through the admin API, one at a time
+type backupRunner struct {
+	ctx    context.Context
+	logger *supportlog.Entry
+	backup backupFn
+	lock   sync.Mutex
+	// job is the latest job (if any)
+	job *backupJob
+}
+
+type backupJob struct {
+	Target     string     `json:"target"`
+	Status     string     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+type backupRequest struct {
+	Target string `json:"target"`
+}
+
+// start starts a job backing up the database to the given target, unless a job is already running
+func (b *backupRunner) start(target string) (backupJob, int, error) {
+	if target == "" {
+		return backupJob{}, http.StatusBadRequest, errors.New("the backup target is missing")
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.job != nil && b.job.Status == jobStatusRunning {
+		return backupJob{}, http.StatusConflict, fmt.Errorf(
+			"the database is being backed up to %s already", b.job.Target,
+		)
+	}
+	b.job = &backupJob{
+		Target:    target,
+		Status:    jobStatusRunning,
+		StartedAt: time.Now(),
+	}
+	logger := b.logger.WithField("target", target)
+	logger.Info("backing up database")
+	go func(job *backupJob) {
+		err := b.backup(b.ctx, target)
+		b.lock.Lock()
+		defer b.lock.Unlock()
+		finishedAt := time.Now()
+		job.FinishedAt = &finishedAt
+		if err != nil {
+			job.Status = jobStatusFailed
+			job.Error = err.Error()
+			logger.WithError(err).Error("could not back up database")
+			return
+		}
+		job.Status = jobStatusDone
+		logger.WithField("duration", finishedAt.Sub(job.StartedAt).Seconds()).Info("backed up database")
+	}(b.job)
+	return *b.job, http.StatusAccepted, nil
+}
+
+func (b *backupRunner) status() (backupJob, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.job == nil {
+		return backupJob{}, false
+	}
+	return *b.job, true
+}
+
+// ServeHTTP reports the progress of the latest backup job (GET) or starts
+// a new one (POST), e.g. {"target": "s3://bucket/soroban-rpc.sqlite"}
+func (b *backupRunner) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var job backupJob
+	status := http.StatusOK
+	switch req.Method {
+	case http.MethodGet:
+		var ok bool
+		if job, ok = b.status(); !ok {
+			http.Error(w, "no backup was requested", http.StatusNotFound)
+			return
+		}
+	case http.MethodPost:
+		var request backupRequest
+		if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+		var err error
+		if job, status, err = b.start(request.Target); err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(job)
+}
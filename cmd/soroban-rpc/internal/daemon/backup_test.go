@@ -0,0 +1,82 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	supportlog "github.com/stellar/go/support/log"
+)
+
+func doBackupRequest(t *testing.T, handler http.Handler, method string, body string) (int, backupJob) {
+	req := httptest.NewRequest(method, "/backup", strings.NewReader(body))
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	var job backupJob
+	if res.Code == http.StatusOK || res.Code == http.StatusAccepted {
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &job))
+	}
+	return res.Code, job
+}
+
+func TestBackup(t *testing.T) {
+	proceed := make(chan struct{})
+	var backedUp string
+	handler := &backupRunner{
+		ctx:    context.Background(),
+		logger: supportlog.New(),
+		backup: func(_ context.Context, target string) error {
+			<-proceed
+			backedUp = target
+			return nil
+		},
+	}
+
+	code, _ := doBackupRequest(t, handler, http.MethodGet, "")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = doBackupRequest(t, handler, http.MethodPost, `{}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, job := doBackupRequest(t, handler, http.MethodPost, `{"target": "s3://bucket/backup.sqlite"}`)
+	require.Equal(t, http.StatusAccepted, code)
+	assert.Equal(t, "s3://bucket/backup.sqlite", job.Target)
+	assert.Equal(t, jobStatusRunning, job.Status)
+
+	// only one job at a time
+	code, _ = doBackupRequest(t, handler, http.MethodPost, `{"target": "/tmp/backup.sqlite"}`)
+	assert.Equal(t, http.StatusConflict, code)
+
+	close(proceed)
+	assert.Eventually(t, func() bool {
+		_, job = doBackupRequest(t, handler, http.MethodGet, "")
+		return job.Status == jobStatusDone
+	}, time.Second, 10*time.Millisecond)
+	assert.NotNil(t, job.FinishedAt)
+	assert.Equal(t, "s3://bucket/backup.sqlite", backedUp)
+}
+
+func TestBackupFailure(t *testing.T) {
+	handler := &backupRunner{
+		ctx:    context.Background(),
+		logger: supportlog.New(),
+		backup: func(context.Context, string) error {
+			return errors.New("boom")
+		},
+	}
+	code, _ := doBackupRequest(t, handler, http.MethodPost, `{"target": "/tmp/backup.sqlite"}`)
+	require.Equal(t, http.StatusAccepted, code)
+	var job backupJob
+	assert.Eventually(t, func() bool {
+		_, job = doBackupRequest(t, handler, http.MethodGet, "")
+		return job.Status == jobStatusFailed
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "boom", job.Error)
+}
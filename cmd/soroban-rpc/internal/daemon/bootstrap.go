@@ -0,0 +1,50 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/storage"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// bootstrapFromSnapshot initializes the database from the configured snapshot when
+// it doesn't exist yet, so that only the ledgers closed since the snapshot need to be
+// ingested (instead of filling the ledger entries from a checkpoint and then
+// catching up). Existing databases are left untouched.
+func bootstrapFromSnapshot(ctx context.Context, cfg *config.Config, logger *supportlog.Entry) error {
+	if cfg.BootstrapSnapshotURL == "" {
+		return nil
+	}
+	if _, err := os.Stat(cfg.SQLiteDBPath); err == nil {
+		logger.Info("the database exists already, skipping the bootstrap from a snapshot")
+		return nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	logger.WithField("snapshot", cfg.BootstrapSnapshotURL).Info("bootstrapping the database from a snapshot")
+	startTime := time.Now()
+	latestLedger, err := db.RestoreSnapshot(
+		ctx,
+		cfg.BootstrapSnapshotURL,
+		cfg.SQLiteDBPath,
+		cfg.BootstrapSnapshotChecksum,
+		cfg.NetworkPassphrase,
+		storage.ConnectOptions{UserAgent: cfg.HistoryArchiveUserAgent},
+	)
+	if err != nil {
+		return err
+	}
+	logger.WithFields(supportlog.F{
+		"latest_ledger": latestLedger,
+		"duration":      time.Since(startTime).Seconds(),
+	}).Info("bootstrapped the database from a snapshot, the later ledgers will be ingested from the network")
+	return nil
+}
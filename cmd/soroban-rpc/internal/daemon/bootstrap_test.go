@@ -0,0 +1,59 @@
+package daemon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	supportlog "github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+func TestBootstrapFromSnapshot(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// publish a snapshot of a database ingested up to ledger 5
+	source, err := db.OpenSQLiteDB(filepath.Join(dir, "source.sqlite"))
+	require.NoError(t, err)
+	writer := db.NewReadWriter(supportlog.New(), source, interfaces.MakeNoOpDeamon(), 10, 10, "passphrase")
+	tx, err := writer.NewTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit(5))
+	snapshotPath := filepath.Join(dir, "snapshot.sqlite")
+	require.NoError(t, source.Backup(ctx, snapshotPath))
+	require.NoError(t, source.Close())
+	content, err := os.ReadFile(snapshotPath)
+	require.NoError(t, err)
+	checksum := sha256.Sum256(content)
+
+	cfg := &config.Config{
+		SQLiteDBPath:              filepath.Join(dir, "soroban_rpc.sqlite"),
+		BootstrapSnapshotURL:      "file://" + snapshotPath,
+		BootstrapSnapshotChecksum: hex.EncodeToString(checksum[:]),
+	}
+	require.NoError(t, bootstrapFromSnapshot(ctx, cfg, supportlog.New()))
+	restored, err := db.OpenSQLiteDB(cfg.SQLiteDBPath)
+	require.NoError(t, err)
+	latestLedger, err := db.NewLedgerEntryReader(restored).GetLatestLedgerSequence(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(5), latestLedger)
+	require.NoError(t, restored.Close())
+
+	// existing databases are left untouched
+	cfg.BootstrapSnapshotChecksum = hex.EncodeToString(make([]byte, sha256.Size))
+	require.NoError(t, bootstrapFromSnapshot(ctx, cfg, supportlog.New()))
+
+	cfg.SQLiteDBPath = filepath.Join(dir, "other.sqlite")
+	require.ErrorContains(t, bootstrapFromSnapshot(ctx, cfg, supportlog.New()), "checksum mismatch")
+	assert.NoFileExists(t, cfg.SQLiteDBPath)
+}
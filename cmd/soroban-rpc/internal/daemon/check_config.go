@@ -0,0 +1,68 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stellar/go/historyarchive"
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/support/storage"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
+)
+
+const historyArchiveCheckTimeout = 10 * time.Second
+
+// CheckConfig checks the configuration (see config.Config.Check) along with the
+// resources it references which can be verified without starting the daemon:
+// the captive core configuration (including its quorum set) and the
+// reachability of the history archives.
+func CheckConfig(ctx context.Context, cfg *config.Config) []config.Issue {
+	issues := cfg.Check()
+	if cfg.CaptiveCoreConfigPath != "" && !hasIssue(issues, "captive-core-config-path") {
+		_, err := ledgerbackend.NewCaptiveCoreTomlFromFile(cfg.CaptiveCoreConfigPath, captiveCoreTomlParams(cfg))
+		if err != nil {
+			issues = append(issues, config.Issue{
+				Option:  "captive-core-config-path",
+				Message: fmt.Sprintf("invalid captive core configuration: %v", err),
+			})
+		}
+	}
+	for _, url := range cfg.HistoryArchiveURLs {
+		if err := checkHistoryArchive(ctx, cfg, url); err != nil {
+			issues = append(issues, config.Issue{
+				Option:  "history-archive-urls",
+				Message: fmt.Sprintf("history archive %s is not reachable: %v", url, err),
+			})
+		}
+	}
+	return issues
+}
+
+func hasIssue(issues []config.Issue, option string) bool {
+	for _, issue := range issues {
+		if issue.Option == option {
+			return true
+		}
+	}
+	return false
+}
+
+func checkHistoryArchive(ctx context.Context, cfg *config.Config, url string) error {
+	ctx, cancel := context.WithTimeout(ctx, historyArchiveCheckTimeout)
+	defer cancel()
+	archive, err := historyarchive.Connect(url, historyarchive.ArchiveOptions{
+		NetworkPassphrase:   cfg.NetworkPassphrase,
+		CheckpointFrequency: cfg.CheckpointFrequency,
+		ConnectOptions: storage.ConnectOptions{
+			Context:   ctx,
+			UserAgent: cfg.HistoryArchiveUserAgent,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = archive.GetRootHAS()
+	return err
+}
@@ -0,0 +1,63 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/stellar/go/historyarchive"
+	supportlog "github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/corebinary"
+)
+
+const (
+	coreBinaryDownloadTimeout = 10 * time.Minute
+	coreBinaryVersionTimeout  = 30 * time.Second
+)
+
+// prepareCoreBinary downloads the pinned stellar-core build (when core-binary-auto is enabled)
+// and verifies that stellar-core supports the protocol of the network, so that version
+// mismatches are reported on startup instead of crashing captive core during catchup.
+func prepareCoreBinary(cfg *config.Config, logger *supportlog.Entry, archive historyarchive.ArchiveInterface) error {
+	if cfg.CoreBinaryAuto {
+		ctx, cancel := context.WithTimeout(context.Background(), coreBinaryDownloadTimeout)
+		defer cancel()
+		logger.WithField("url", cfg.CoreBinaryURL).Info("obtaining pinned stellar-core build")
+		binaryPath, err := corebinary.Download(ctx, corebinary.DownloadConfig{
+			URL:       cfg.CoreBinaryURL,
+			SHA256:    cfg.CoreBinarySHA256,
+			Dir:       filepath.Join(cfg.CaptiveCoreStoragePath, "bin"),
+			UserAgent: cfg.ExtendedUserAgent("corebinary"),
+		})
+		if err != nil {
+			return err
+		}
+		cfg.StellarCoreBinaryPath = binaryPath
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), coreBinaryVersionTimeout)
+	defer cancel()
+	info, err := corebinary.GetInfo(ctx, cfg.StellarCoreBinaryPath)
+	if err != nil {
+		return err
+	}
+	logger.WithFields(supportlog.F{
+		"path":             cfg.StellarCoreBinaryPath,
+		"version":          info.Version,
+		"protocol_version": info.LedgerProtocolVersion,
+	}).Info("using stellar-core")
+
+	networkProtocolVersion, err := corebinary.GetNetworkProtocolVersion(archive)
+	if err != nil {
+		// don't prevent starting when the archives are temporarily unavailable
+		logger.WithError(err).Warn("could not obtain the protocol version of the network, skipping the stellar-core compatibility check")
+		return info.CheckProtocolVersion(0)
+	}
+	if err := info.CheckProtocolVersion(networkProtocolVersion); err != nil {
+		return fmt.Errorf("incompatible stellar-core binary %s: %w", cfg.StellarCoreBinaryPath, err)
+	}
+	return nil
+}
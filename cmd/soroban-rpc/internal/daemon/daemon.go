@@ -2,6 +2,7 @@ package daemon
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"net"
 	"net/http"
@@ -13,6 +14,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/go-chi/chi"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
@@ -29,16 +31,23 @@ import (
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/events"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/feewindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/gossip"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/graphql"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/horizon"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ingest"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/methods"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/preflight"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/subscriptions"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/tracing"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/util"
 )
 
 const (
-	prometheusNamespace                   = "soroban_rpc"
-	maxLedgerEntryWriteBatchSize          = 150
-	defaultReadTimeout                    = 5 * time.Second
-	defaultShutdownGracePeriod            = 10 * time.Second
+	prometheusNamespace          = "soroban_rpc"
+	maxLedgerEntryWriteBatchSize = 150
+	defaultReadTimeout           = 5 * time.Second
+	// defaultShutdownTimeout bounds the shutdown of the auxiliary servers and the flushing of traces
+	defaultShutdownTimeout                = 10 * time.Second
 	inMemoryInitializationLedgerLogPeriod = 1_000_000
 )
 
@@ -46,6 +55,9 @@ type Daemon struct {
 	core                *ledgerbackend.CaptiveStellarCore
 	coreClient          *CoreClientWithMetrics
 	ingestService       *ingest.Service
+	follower            *ingest.Follower
+	gossipNode          *gossip.Node
+	subscriptionHub     *subscriptions.Hub
 	db                  *db.DB
 	jsonRPCHandler      *internal.Handler
 	logger              *supportlog.Entry
@@ -58,6 +70,16 @@ type Daemon struct {
 	closeError          error
 	done                chan struct{}
 	metricsRegistry     *prometheus.Registry
+	shutdownTracing     func(context.Context) error
+	shutdownGracePeriod time.Duration
+	accessLogFile       *os.File
+	stopAdminJobs       context.CancelFunc
+	snapshotPublisher   *snapshotPublisher
+	startup             startupProgress
+	systemd             *systemdSupervisor
+	httpHandler         *chi.Mux
+	// networks are the additional networks served by the daemon
+	networks []*Daemon
 }
 
 func (d *Daemon) GetDB() *db.DB {
@@ -65,24 +87,53 @@ func (d *Daemon) GetDB() *db.DB {
 }
 
 func (d *Daemon) GetEndpointAddrs() (net.TCPAddr, *net.TCPAddr) {
-	//nolint:forcetypeassert
-	addr := d.listener.Addr().(*net.TCPAddr)
+	var addr net.TCPAddr
+	if d.listener != nil {
+		//nolint:forcetypeassert
+		addr = *d.listener.Addr().(*net.TCPAddr)
+	}
 	var adminAddr *net.TCPAddr
 	if d.adminListener != nil {
 		//nolint:forcetypeassert
 		adminAddr = d.adminListener.Addr().(*net.TCPAddr)
 	}
-	return *addr, adminAddr
+	return addr, adminAddr
 }
 
+// close shuts the daemon down in dependency order: new requests are refused and the in-flight ones
+// (and their simulations) are drained first, then the ledger being ingested is committed, and only
+// then captive core and the database are torn down.
 func (d *Daemon) close() {
-	shutdownCtx, shutdownRelease := context.WithTimeout(context.Background(), defaultShutdownGracePeriod)
+	shutdownCtx, shutdownRelease := context.WithTimeout(context.Background(), defaultShutdownTimeout)
 	defer shutdownRelease()
 	var closeErrors []error
 
-	if err := d.server.Shutdown(shutdownCtx); err != nil {
-		d.logger.WithError(err).Error("error during Soroban JSON RPC server Shutdown")
-		closeErrors = append(closeErrors, err)
+	if d.systemd != nil {
+		d.systemd.stopping()
+	}
+
+	if d.server != nil {
+		d.logger.WithField("grace_period", d.shutdownGracePeriod.String()).
+			Info("shutting down, draining in-flight requests")
+		drainCtx, drainRelease := context.WithTimeout(context.Background(), d.shutdownGracePeriod)
+		err := d.server.Shutdown(drainCtx)
+		drainRelease()
+		if errors.Is(err, context.DeadlineExceeded) {
+			d.logger.Warn("shutdown grace period elapsed, aborting the requests still in flight")
+			err = d.server.Close()
+		}
+		if err != nil {
+			d.logger.WithError(err).Error("error during Soroban JSON RPC server Shutdown")
+			closeErrors = append(closeErrors, err)
+		}
+	}
+	if d.subscriptionHub != nil {
+		d.subscriptionHub.Close()
+	}
+	for _, network := range d.networks {
+		if err := network.Close(); err != nil {
+			closeErrors = append(closeErrors, err)
+		}
 	}
 	if d.adminServer != nil {
 		if err := d.adminServer.Shutdown(shutdownCtx); err != nil {
@@ -90,21 +141,61 @@ func (d *Daemon) close() {
 			closeErrors = append(closeErrors, err)
 		}
 	}
+	if d.jsonRPCHandler != nil {
+		d.jsonRPCHandler.Close()
+	}
+	if d.preflightWorkerPool != nil {
+		// waits for the queued simulations, which read from the database
+		d.preflightWorkerPool.Close()
+	}
 
-	if err := d.ingestService.Close(); err != nil {
-		d.logger.WithError(err).Error("error closing ingestion service")
+	if err := d.gossipNode.Close(); err != nil {
+		d.logger.WithError(err).Error("error closing peer gossip node")
 		closeErrors = append(closeErrors, err)
 	}
-	if err := d.core.Close(); err != nil {
-		d.logger.WithError(err).Error("error closing captive core")
-		closeErrors = append(closeErrors, err)
+	if d.follower != nil {
+		if err := d.follower.Close(); err != nil {
+			d.logger.WithError(err).Error("error closing ledger follower")
+			closeErrors = append(closeErrors, err)
+		}
+	}
+	if d.ingestService != nil {
+		// the ledger being ingested (if any) is committed before the ingestion service stops
+		if err := d.ingestService.Close(); err != nil {
+			d.logger.WithError(err).Error("error closing ingestion service")
+			closeErrors = append(closeErrors, err)
+		}
+	}
+	if d.core != nil {
+		if err := d.core.Close(); err != nil {
+			d.logger.WithError(err).Error("error closing captive core")
+			closeErrors = append(closeErrors, err)
+		}
+	}
+	if d.stopAdminJobs != nil {
+		d.stopAdminJobs()
+	}
+	if d.snapshotPublisher != nil {
+		// let the snapshot being published (if any) complete before closing the database
+		<-d.snapshotPublisher.done
 	}
-	d.jsonRPCHandler.Close()
 	if err := d.db.Close(); err != nil {
 		d.logger.WithError(err).Error("Error closing db")
 		closeErrors = append(closeErrors, err)
 	}
-	d.preflightWorkerPool.Close()
+	if d.accessLogFile != nil {
+		if err := d.accessLogFile.Close(); err != nil {
+			d.logger.WithError(err).Error("error closing access log")
+			closeErrors = append(closeErrors, err)
+		}
+	}
+	// the tracer provider is only shut down by the main daemon
+	if d.shutdownTracing != nil {
+		if err := d.shutdownTracing(shutdownCtx); err != nil {
+			d.logger.WithError(err).Error("error flushing traces")
+			closeErrors = append(closeErrors, err)
+		}
+	}
 	d.closeError = errors.Join(closeErrors...)
 	close(d.done)
 }
@@ -114,9 +205,8 @@ func (d *Daemon) Close() error {
 	return d.closeError
 }
 
-// newCaptiveCore creates a new captive core backend instance and returns it.
-func newCaptiveCore(cfg *config.Config, logger *supportlog.Entry) (*ledgerbackend.CaptiveStellarCore, error) {
-	captiveCoreTomlParams := ledgerbackend.CaptiveCoreTomlParams{
+func captiveCoreTomlParams(cfg *config.Config) ledgerbackend.CaptiveCoreTomlParams {
+	return ledgerbackend.CaptiveCoreTomlParams{
 		HTTPPort:                           &cfg.CaptiveCoreHTTPPort,
 		HistoryArchiveURLs:                 cfg.HistoryArchiveURLs,
 		NetworkPassphrase:                  cfg.NetworkPassphrase,
@@ -126,7 +216,11 @@ func newCaptiveCore(cfg *config.Config, logger *supportlog.Entry) (*ledgerbacken
 		EnforceSorobanTransactionMetaExtV1: true,
 		CoreBinaryPath:                     cfg.StellarCoreBinaryPath,
 	}
-	captiveCoreToml, err := ledgerbackend.NewCaptiveCoreTomlFromFile(cfg.CaptiveCoreConfigPath, captiveCoreTomlParams)
+}
+
+// newCaptiveCore creates a new captive core backend instance and returns it.
+func newCaptiveCore(cfg *config.Config, logger *supportlog.Entry, coreLogger *supportlog.Entry) (*ledgerbackend.CaptiveStellarCore, error) {
+	captiveCoreToml, err := ledgerbackend.NewCaptiveCoreTomlFromFile(cfg.CaptiveCoreConfigPath, captiveCoreTomlParams(cfg))
 	if err != nil {
 		logger.WithError(err).Fatal("Invalid captive core toml")
 	}
@@ -137,7 +231,7 @@ func newCaptiveCore(cfg *config.Config, logger *supportlog.Entry) (*ledgerbacken
 		NetworkPassphrase:   cfg.NetworkPassphrase,
 		HistoryArchiveURLs:  cfg.HistoryArchiveURLs,
 		CheckpointFrequency: cfg.CheckpointFrequency,
-		Log:                 logger.WithField("subservice", "stellar-core"),
+		Log:                 coreLogger,
 		Toml:                captiveCoreToml,
 		UserAgent:           cfg.ExtendedUserAgent("captivecore"),
 		UseDB:               true,
@@ -145,85 +239,350 @@ func newCaptiveCore(cfg *config.Config, logger *supportlog.Entry) (*ledgerbacken
 	return ledgerbackend.NewCaptive(captiveConfig)
 }
 
+func newArchivePool(cfg *config.Config, logger *supportlog.Entry) (historyarchive.ArchiveInterface, error) {
+	return historyarchive.NewArchivePool(
+		cfg.HistoryArchiveURLs,
+		historyarchive.ArchiveOptions{
+			Logger:              logger,
+			NetworkPassphrase:   cfg.NetworkPassphrase,
+			CheckpointFrequency: cfg.CheckpointFrequency,
+			ConnectOptions: storage.ConnectOptions{
+				Context:   context.Background(),
+				UserAgent: cfg.HistoryArchiveUserAgent,
+			},
+		},
+	)
+}
+
 func MustNew(cfg *config.Config, logger *supportlog.Entry) *Daemon {
-	logger.SetLevel(cfg.LogLevel)
+	networks := cfg.Networks()
+	if err := checkNetworkNames(networks); err != nil {
+		logger.WithError(err).Fatal("invalid network configuration")
+	}
+	daemon := mustNew(cfg, logger, "")
+	// the tracer provider is global, so it's shared by all the networks, whose
+	// spans are told apart through their network attribute (the headers were
+	// validated when parsing the configuration)
+	tracingHeaders, _ := tracing.ParseHeaders(cfg.TracingOTLPHeaders)
+	shutdownTracing, err := tracing.Setup(tracing.Config{
+		OTLPEndpointURL: cfg.TracingOTLPEndpoint,
+		OTLPHeaders:     tracingHeaders,
+		SampleRatio:     cfg.TracingSampleRatio,
+		Version:         config.Version,
+	})
+	if err != nil {
+		daemon.logger.WithError(err).Fatal("could not set up tracing")
+	}
+	daemon.shutdownTracing = shutdownTracing
+	for _, network := range networks {
+		networkCfg, err := network.Load()
+		if err != nil {
+			daemon.logger.WithError(err).Fatal("could not load network configuration")
+		}
+		networkDaemon := mustNew(networkCfg, logger.WithField("network", network.Name), network.Name)
+		daemon.networks = append(daemon.networks, networkDaemon)
+		if daemon.httpHandler == nil || networkDaemon.httpHandler == nil {
+			// the network isn't served (see the mode option)
+			continue
+		}
+		// the requests of the network are routed to its handler, with the prefix stripped
+		daemon.httpHandler.Mount("/"+network.Name, networkDaemon.httpHandler)
+	}
+	return daemon
+}
+
+// mustNew creates the daemon of a network. Additional networks (i.e. with a name) don't listen on
+// their endpoint, their requests are served by the main daemon under the network prefix.
+func mustNew(cfg *config.Config, logger *supportlog.Entry, network string) *Daemon {
 	if cfg.LogFormat == config.LogFormatJSON {
 		logger.UseJSONFormatter()
 	}
+	levels := newLogLevels(logger, cfg.LogLevel)
+	logger = levels.global
 
 	logger.WithFields(supportlog.F{
 		"version": config.Version,
 		"commit":  config.CommitHash,
+		"mode":    cfg.Mode,
 	}).Info("starting Soroban RPC")
 
-	core, err := newCaptiveCore(cfg, logger)
-	if err != nil {
-		logger.WithError(err).Fatal("could not create captive core")
-	}
-
 	if len(cfg.HistoryArchiveURLs) == 0 {
 		logger.Fatal("no history archives URLs were provided")
 	}
 
-	historyArchive, err := historyarchive.NewArchivePool(
-		cfg.HistoryArchiveURLs,
-		historyarchive.ArchiveOptions{
-			Logger:              logger,
-			NetworkPassphrase:   cfg.NetworkPassphrase,
-			CheckpointFrequency: cfg.CheckpointFrequency,
-			ConnectOptions: storage.ConnectOptions{
-				Context:   context.Background(),
-				UserAgent: cfg.HistoryArchiveUserAgent,
-			},
-		},
+	var (
+		historyArchive historyarchive.ArchiveInterface
+		core           *ledgerbackend.CaptiveStellarCore
+		err            error
 	)
+	if cfg.Ingests() {
+		historyArchive, err = newArchivePool(cfg, logger)
+		if err != nil {
+			logger.WithError(err).Fatal("could not connect to history archive")
+		}
+
+		if err := prepareCoreBinary(cfg, logger, historyArchive); err != nil {
+			logger.WithError(err).Fatal("could not prepare the stellar-core binary")
+		}
+
+		core, err = newCaptiveCore(cfg, logger, levels.subsystem("stellar-core"))
+		if err != nil {
+			logger.WithError(err).Fatal("could not create captive core")
+		}
+	}
+
+	bootstrapCtx, cancelBootstrap := context.WithTimeout(context.Background(), cfg.IngestionTimeout)
+	err = bootstrapFromSnapshot(bootstrapCtx, cfg, logger)
+	cancelBootstrap()
 	if err != nil {
-		logger.WithError(err).Fatal("could not connect to history archive")
+		logger.WithError(err).Fatal("could not bootstrap the database from a snapshot")
 	}
 
 	metricsRegistry := prometheus.NewRegistry()
-	dbConn, err := db.OpenSQLiteDBWithPrometheusMetrics(cfg.SQLiteDBPath, prometheusNamespace, "db", metricsRegistry)
+	dbConn, err := db.OpenSQLiteDBWithPrometheusMetrics(cfg.SQLiteDBPath, prometheusNamespace, "db", metricsRegistry,
+		db.ReadPoolOptions{
+			IdleConnections: int(cfg.DBReadConnections),
+			BusyTimeout:     cfg.DBReadBusyTimeout,
+		})
 	if err != nil {
 		logger.WithError(err).Fatal("could not open database")
 	}
+	if cfg.SQLiteDBPath == config.InMemoryDBPath {
+		logger.Warn("using an in-memory database, its contents will be lost on exit")
+	}
 
 	daemon := &Daemon{
-		logger:          logger,
-		core:            core,
-		db:              dbConn,
-		done:            make(chan struct{}),
-		metricsRegistry: metricsRegistry,
+		logger:              logger,
+		core:                core,
+		shutdownGracePeriod: cfg.ShutdownGracePeriod,
+		db:                  dbConn,
+		done:                make(chan struct{}),
+		metricsRegistry:     metricsRegistry,
 		coreClient: newCoreClientWithMetrics(stellarcore.Client{
 			URL:  cfg.StellarCoreURL,
 			HTTP: &http.Client{Timeout: cfg.CoreRequestTimeout},
 		}, metricsRegistry),
 	}
 
-	feewindows, eventStore := daemon.mustInitializeStorage(cfg)
+	readiness := readinessProbe{
+		progress:         &daemon.startup,
+		ledgerRange:      db.NewLedgerReader(dbConn).GetLedgerRange,
+		coreClient:       daemon.coreClient,
+		maxLedgerLatency: cfg.MaxHealthyLedgerLatency,
+	}
+
+	dbLogger := levels.subsystem("db")
+	var tlsConfig *tls.Config
+	if cfg.TLSCertFile != "" {
+		reloader, err := util.NewCertificateReloader(cfg.TLSCertFile, cfg.TLSKeyFile, logger)
+		if err != nil {
+			logger.WithError(err).Fatal("could not load TLS certificate")
+		}
+		tlsConfig = reloader.TLSConfig()
+	}
+	var jobsCtx context.Context
+	jobsCtx, daemon.stopAdminJobs = context.WithCancel(context.Background())
+	profiler := &profiler{
+		ctx:                      jobsCtx,
+		logger:                   levels.subsystem("profiles"),
+		dir:                      cfg.ProfileDir,
+		requestDurationThreshold: cfg.ProfileTriggerRequestDuration,
+	}
+	if cfg.ProfileDir != "" {
+		if err := os.MkdirAll(cfg.ProfileDir, 0o755); err != nil {
+			logger.WithError(err).Fatal("could not create profile directory")
+		}
+	}
+	if cfg.ProfileTriggerHeapSize > 0 {
+		go profiler.watchHeap(uint64(cfg.ProfileTriggerHeapSize))
+	}
+	if cfg.SnapshotPublishURL != "" {
+		daemon.snapshotPublisher = newSnapshotPublisher(
+			levels.subsystem("snapshots"),
+			cfg.SnapshotPublishInterval,
+			metricsRegistry,
+			func(ctx context.Context) (db.SnapshotManifest, error) {
+				return db.PublishSnapshot(ctx, dbConn, cfg.SnapshotPublishURL, cfg.NetworkPassphrase,
+					storage.ConnectOptions{UserAgent: cfg.HistoryArchiveUserAgent})
+			},
+		)
+		go daemon.snapshotPublisher.run(jobsCtx)
+	}
+	if cfg.AdminEndpoint != "" {
+		adminMux := supporthttp.NewMux(logger)
+		adminMux.HandleFunc("/debug/pprof/", pprof.Index)
+		adminMux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		adminMux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		adminMux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		adminMux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		// add the entry points for:
+		// goroutine, threadcreate, heap, allocs, block, mutex
+		for _, profile := range runtimePprof.Profiles() {
+			adminMux.Handle("/debug/pprof/"+profile.Name(), pprof.Handler(profile.Name()))
+		}
+		adminMux.Handle("/metrics", promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{}))
+		adminMux.Handle("/log-level", levels)
+		adminMux.HandleFunc("/health/live", serveLiveness)
+		adminMux.Handle("/health/ready", readiness)
+		statusPage := &statusPage{
+			readiness:             readiness.state,
+			ledgerRange:           db.NewLedgerReader(dbConn).GetLedgerRange,
+			gatherer:              metricsRegistry,
+			requestDurationMetric: prometheusNamespace + "_json_rpc_method_request_duration_seconds",
+		}
+		go statusPage.run(jobsCtx)
+		adminMux.Handle("/status", statusPage)
+		if cfg.AdminAPIToken != "" {
+			if cfg.Ingests() {
+				// only the ingesting process writes to the database
+				reingester := &reingester{
+					ctx:         jobsCtx,
+					logger:      levels.subsystem("reingest"),
+					ledgerRange: db.NewLedgerReader(dbConn).GetLedgerRange,
+					reingest: func(ctx context.Context, start, end uint32, progress db.ReingestProgressFn) error {
+						return db.ReingestTransactions(ctx, dbLogger, dbConn, cfg.NetworkPassphrase, start, end, progress)
+					},
+				}
+				adminMux.Handle("/reingest", requireBearerToken(cfg.AdminAPIToken, reingester))
+			}
+			backupRunner := &backupRunner{
+				ctx:    jobsCtx,
+				logger: levels.subsystem("backup"),
+				backup: func(ctx context.Context, target string) error {
+					return db.BackupTo(ctx, dbConn, target, storage.ConnectOptions{UserAgent: cfg.HistoryArchiveUserAgent})
+				},
+			}
+			adminMux.Handle("/backup", requireBearerToken(cfg.AdminAPIToken, backupRunner))
+			adminMux.Handle("/profiles", requireBearerToken(cfg.AdminAPIToken, profiler))
+		}
+		daemon.adminListener, err = net.Listen("tcp", cfg.AdminEndpoint)
+		if err != nil {
+			daemon.logger.WithError(err).WithField("endpoint", cfg.Endpoint).Fatal("cannot listen on admin endpoint")
+		}
+		daemon.adminServer = &http.Server{Handler: adminMux, TLSConfig: tlsConfig}
+		// serve the admin endpoint right away, so that the startup progress can be probed
+		daemon.serveAdmin()
+	}
+
+	systemdNotifier, err := newSystemdNotifier(os.Getenv)
+	if err != nil {
+		logger.WithError(err).Fatal("could not set up systemd notifications")
+	}
+	if systemdNotifier != nil && network == "" {
+		daemon.systemd = &systemdSupervisor{
+			notifier:     systemdNotifier,
+			readiness:    readiness.state,
+			stallTimeout: cfg.SystemdWatchdogStallTimeout,
+			logger:       levels.subsystem("systemd"),
+		}
+		// start right away, so that the watchdog is notified during the startup initialization
+		util.UnrecoverablePanicGroup.Log(logger).Go(func() {
+			daemon.systemd.run(daemon.done)
+		})
+	}
+
+	feewindows, eventStore, latestLedger := daemon.mustInitializeStorage(cfg)
 
 	onIngestionRetry := func(err error, dur time.Duration) {
 		logger.WithError(err).Error("could not run ingestion. Retrying")
 	}
 
-	ingestService := ingest.NewService(ingest.Config{
-		Logger: logger,
-		DB: db.NewReadWriter(
-			logger,
-			dbConn,
-			daemon,
-			maxLedgerEntryWriteBatchSize,
-			cfg.HistoryRetentionWindow,
-			cfg.NetworkPassphrase,
-		),
-		EventStore:        eventStore,
-		NetworkPassPhrase: cfg.NetworkPassphrase,
-		Archive:           historyArchive,
-		LedgerBackend:     core,
-		Timeout:           cfg.IngestionTimeout,
-		OnIngestionRetry:  onIngestionRetry,
-		Daemon:            daemon,
-		FeeWindows:        feewindows,
-	})
+	var gossipNode *gossip.Node
+	if cfg.PeerEndpoint != "" {
+		gossipNode, err = gossip.NewNode(gossip.Config{
+			ListenEndpoint: cfg.PeerEndpoint,
+			PeerURLs:       cfg.PeerURLs,
+			CertFile:       cfg.PeerTLSCertFile,
+			KeyFile:        cfg.PeerTLSKeyFile,
+			CAFile:         cfg.PeerTLSCAFile,
+			HintTTL:        cfg.PeerHintTTL,
+			Logger:         levels.subsystem("gossip"),
+		})
+		if err != nil {
+			logger.WithError(err).Fatal("could not create peer gossip node")
+		}
+		daemon.gossipNode = gossipNode
+	}
+	var (
+		circuitBreaker    *ingest.CircuitBreaker
+		nodeHealthChecker methods.NodeHealthChecker
+	)
+	if cfg.CircuitBreakerMaxLedgerLag > 0 {
+		circuitBreaker = ingest.NewCircuitBreaker(cfg.CircuitBreakerMaxLedgerLag, daemon, levels.subsystem("circuit-breaker"))
+		nodeHealthChecker = circuitBreaker
+	}
+	var subscriptionHub *subscriptions.Hub
+	if cfg.EnableSubscriptions && cfg.Serves() {
+		var trustedProxies uint
+		if cfg.RateLimitTrustForwardedFor {
+			trustedProxies = cfg.RateLimitTrustedProxies
+		}
+		subscriptionHub = subscriptions.NewHub(subscriptions.Config{
+			NetworkPassphrase:       cfg.NetworkPassphrase,
+			MaxConnections:          cfg.MaxSubscriptionConnections,
+			MaxConnectionsPerClient: cfg.MaxSubscriptionConnectionsPerClient,
+			AllowedOrigins:          cfg.CORSAllowedOrigins,
+			TrustedProxies:          trustedProxies,
+			Daemon:                  daemon,
+			Logger:                  levels.subsystem("subscriptions"),
+		})
+		daemon.subscriptionHub = subscriptionHub
+	}
+	onLedgerIngested := func(lcm xdr.LedgerCloseMeta) {
+		if circuitBreaker != nil {
+			circuitBreaker.OnLedgerIngested(lcm)
+		}
+		if subscriptionHub != nil {
+			subscriptionHub.OnLedgerIngested(lcm)
+		}
+		if gossipNode == nil {
+			return
+		}
+		hints, err := gossip.InclusionHints(cfg.NetworkPassphrase, lcm)
+		if err != nil {
+			logger.WithError(err).Warn("could not build transaction inclusion hints")
+			return
+		}
+		gossipNode.Publish(hints...)
+	}
+
+	if cfg.Ingests() {
+		daemon.ingestService = ingest.NewService(ingest.Config{
+			Logger: levels.subsystem("ingest"),
+			DB: db.NewReadWriter(
+				dbLogger,
+				dbConn,
+				daemon,
+				maxLedgerEntryWriteBatchSize,
+				cfg.HistoryRetentionWindow,
+				cfg.NetworkPassphrase,
+			),
+			EventStore:        eventStore,
+			NetworkPassPhrase: cfg.NetworkPassphrase,
+			Archive:           historyArchive,
+			LedgerBackend:     core,
+			Timeout:           cfg.IngestionTimeout,
+			OnIngestionRetry:  onIngestionRetry,
+			OnLedgerIngested:  onLedgerIngested,
+			Daemon:            daemon,
+			FeeWindows:        feewindows,
+		})
+	} else {
+		// the ledgers are ingested by another process sharing the database
+		daemon.follower = ingest.NewFollower(ingest.FollowerConfig{
+			Logger:           levels.subsystem("ingest"),
+			DB:               dbConn,
+			EventStore:       eventStore,
+			FeeWindows:       feewindows,
+			LatestLedger:     latestLedger,
+			OnLedgerIngested: onLedgerIngested,
+			Daemon:           daemon,
+		})
+	}
+	if !cfg.Serves() {
+		daemon.registerMetrics()
+		return daemon
+	}
 
 	ledgerEntryReader := db.NewLedgerEntryReader(dbConn)
 	preflightWorkerPool := preflight.NewPreflightWorkerPool(
@@ -234,27 +593,78 @@ func MustNew(cfg *config.Config, logger *supportlog.Entry) *Daemon {
 			EnableDebug:       cfg.PreflightEnableDebug,
 			LedgerEntryReader: ledgerEntryReader,
 			NetworkPassphrase: cfg.NetworkPassphrase,
-			Logger:            logger,
+			Logger:            levels.subsystem("preflight"),
 		},
 	)
+	for _, upgrade := range cfg.ProtocolUpgrades() {
+		if err := preflightWorkerPool.CheckCompatibility(upgrade.Version); err != nil {
+			logger.WithError(err).
+				WithField("protocol_version", upgrade.Version).
+				WithField("upgrade_time", upgrade.Time.String()).
+				Warn("simulateTransaction won't be available after the scheduled protocol upgrade, upgrade soroban-rpc before it")
+		}
+	}
 
+	var accessLogger *supportlog.Entry
+	if cfg.AccessLogPath != "" {
+		accessLogFile, err := os.OpenFile(cfg.AccessLogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			logger.WithError(err).Fatal("could not open access log")
+		}
+		daemon.accessLogFile = accessLogFile
+		accessLogger = supportlog.New()
+		accessLogger.SetOutput(accessLogFile)
+		accessLogger.SetLevel(supportlog.InfoLevel)
+		accessLogger.UseJSONFormatter()
+	}
+
+	var subscriptionHandler http.Handler
+	if subscriptionHub != nil {
+		subscriptionHandler = subscriptionHub
+	}
+	var requestDurationObserver func(time.Duration)
+	if cfg.ProfileTriggerRequestDuration > 0 {
+		requestDurationObserver = profiler.observeRequestDuration
+	}
 	jsonRPCHandler := internal.NewJSONRPCHandler(cfg, internal.HandlerParams{
 		Daemon:            daemon,
 		EventStore:        eventStore,
 		FeeStatWindows:    feewindows,
-		Logger:            logger,
+		Logger:            levels.subsystem("jsonrpc"),
 		LedgerReader:      db.NewLedgerReader(dbConn),
 		LedgerEntryReader: db.NewLedgerEntryReader(dbConn),
-		TransactionReader: db.NewTransactionReader(logger, dbConn, cfg.NetworkPassphrase),
+		TransactionReader: db.NewTransactionReader(dbLogger, dbConn.Readers(), cfg.NetworkPassphrase),
 		PreflightGetter:   preflightWorkerPool,
+		PreflightChecker:  preflightWorkerPool,
+		TransactionHints:  gossipNode,
+		NodeHealthChecker: nodeHealthChecker,
+		AccessLogger:      accessLogger,
+		DatabaseSizer:     dbConn,
+		// the WebSocket connections are rate-limited like the JSON RPC requests
+		SubscriptionHandler: subscriptionHandler,
+		// capture profiles of the slow requests
+		RequestDurationObserver: requestDurationObserver,
 	})
 
 	httpHandler := supporthttp.NewAPIMux(logger)
 	httpHandler.Handle("/", jsonRPCHandler)
+	if jsonRPCHandler.GraphQLHandler != nil {
+		httpHandler.Handle(graphql.Path, jsonRPCHandler.GraphQLHandler)
+	}
+	if jsonRPCHandler.HorizonHandler != nil {
+		httpHandler.Mount(horizon.Path, jsonRPCHandler.HorizonHandler)
+	}
+	if jsonRPCHandler.SubscriptionHandler != nil {
+		httpHandler.Handle(subscriptions.Path, jsonRPCHandler.SubscriptionHandler)
+	}
 
 	daemon.preflightWorkerPool = preflightWorkerPool
-	daemon.ingestService = ingestService
 	daemon.jsonRPCHandler = &jsonRPCHandler
+	daemon.httpHandler = httpHandler
+	if network != "" {
+		daemon.registerMetrics()
+		return daemon
+	}
 
 	// Use a separate listener in order to obtain the actual TCP port
 	// when using dynamic ports during testing (e.g. endpoint="localhost:0")
@@ -265,32 +675,15 @@ func MustNew(cfg *config.Config, logger *supportlog.Entry) *Daemon {
 	daemon.server = &http.Server{
 		Handler:     httpHandler,
 		ReadTimeout: defaultReadTimeout,
-	}
-	if cfg.AdminEndpoint != "" {
-		adminMux := supporthttp.NewMux(logger)
-		adminMux.HandleFunc("/debug/pprof/", pprof.Index)
-		adminMux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
-		adminMux.HandleFunc("/debug/pprof/profile", pprof.Profile)
-		adminMux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
-		adminMux.HandleFunc("/debug/pprof/trace", pprof.Trace)
-		// add the entry points for:
-		// goroutine, threadcreate, heap, allocs, block, mutex
-		for _, profile := range runtimePprof.Profiles() {
-			adminMux.Handle("/debug/pprof/"+profile.Name(), pprof.Handler(profile.Name()))
-		}
-		adminMux.Handle("/metrics", promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{}))
-		daemon.adminListener, err = net.Listen("tcp", cfg.AdminEndpoint)
-		if err != nil {
-			daemon.logger.WithError(err).WithField("endpoint", cfg.Endpoint).Fatal("cannot listen on admin endpoint")
-		}
-		daemon.adminServer = &http.Server{Handler: adminMux}
+		TLSConfig:   tlsConfig,
 	}
 	daemon.registerMetrics()
 	return daemon
 }
 
-// mustInitializeStorage initializes the storage using what was on the DB
-func (d *Daemon) mustInitializeStorage(cfg *config.Config) (*feewindow.FeeWindows, *events.MemoryStore) {
+// mustInitializeStorage initializes the storage using what was on the DB,
+// returning the latest ledger loaded into it (0 if none)
+func (d *Daemon) mustInitializeStorage(cfg *config.Config) (*feewindow.FeeWindows, *events.MemoryStore, uint32) {
 	eventStore := events.NewMemoryStore(
 		d,
 		cfg.NetworkPassphrase,
@@ -302,9 +695,21 @@ func (d *Daemon) mustInitializeStorage(cfg *config.Config) (*feewindow.FeeWindow
 	defer cancelReadTxMeta()
 	var initialSeq uint32
 	var currentSeq uint32
-	dataMigrations, err := db.BuildMigrations(readTxMetaCtx, d.logger, d.db, cfg)
+	// only the ingesting process writes to the database, so it alone runs the data migrations
+	var dataMigrations db.Migration
+	if cfg.Ingests() {
+		var err error
+		dataMigrations, err = db.BuildMigrations(readTxMetaCtx, d.logger, d.db, cfg)
+		if err != nil {
+			d.logger.WithError(err).Fatal("could not build migrations")
+		}
+	}
+	ledgerRange, err := db.NewLedgerReader(d.db).GetLedgerRange(readTxMetaCtx)
 	if err != nil {
-		d.logger.WithError(err).Fatal("could not build migrations")
+		d.logger.WithError(err).Fatal("could not obtain the ledger range from the database")
+	}
+	if ledgerRange.FirstLedger.Sequence != 0 {
+		d.startup.start(ledgerRange.LastLedger.Sequence - ledgerRange.FirstLedger.Sequence + 1)
 	}
 	// NOTE: We could optimize this to avoid unnecessary ingestion calls
 	//       (the range of txmetas can be larger than the individual store retention windows)
@@ -329,18 +734,21 @@ func (d *Daemon) mustInitializeStorage(cfg *config.Config) (*feewindow.FeeWindow
 		}
 		// TODO: clean up once we remove the in-memory storage.
 		//       (we should only stream over the required range)
-		if r := dataMigrations.ApplicableRange(); r.IsLedgerIncluded(currentSeq) {
+		if dataMigrations != nil && dataMigrations.ApplicableRange().IsLedgerIncluded(currentSeq) {
 			if err := dataMigrations.Apply(readTxMetaCtx, txmeta); err != nil {
 				d.logger.WithError(err).Fatal("could not run migrations")
 			}
 		}
+		d.startup.ledgerApplied()
 		return nil
 	})
 	if err != nil {
 		d.logger.WithError(err).Fatal("could not obtain txmeta cache from the database")
 	}
-	if err := dataMigrations.Commit(readTxMetaCtx); err != nil {
-		d.logger.WithError(err).Fatal("could not commit data migrations")
+	if dataMigrations != nil {
+		if err := dataMigrations.Commit(readTxMetaCtx); err != nil {
+			d.logger.WithError(err).Fatal("could not commit data migrations")
+		}
 	}
 
 	if currentSeq != 0 {
@@ -348,36 +756,60 @@ func (d *Daemon) mustInitializeStorage(cfg *config.Config) (*feewindow.FeeWindow
 			"seq": currentSeq,
 		}).Info("finished initializing in-memory store")
 	}
+	d.startup.finish()
 
-	return feewindows, eventStore
+	return feewindows, eventStore, currentSeq
 }
 
-func (d *Daemon) Run() {
-	d.logger.WithFields(supportlog.F{
-		"addr": d.listener.Addr().String(),
-	}).Info("starting HTTP server")
+// serve serves over HTTPS when the server is configured with TLS
+func serve(server *http.Server, listener net.Listener) error {
+	if server.TLSConfig != nil {
+		// the certificate is provided by the TLS configuration
+		return server.ServeTLS(listener, "", "")
+	}
+	return server.Serve(listener)
+}
 
-	panicGroup := util.UnrecoverablePanicGroup.Log(d.logger)
-	panicGroup.Go(func() {
-		if err := d.server.Serve(d.listener); !errors.Is(err, http.ErrServerClosed) {
-			d.logger.WithError(err).Fatal("soroban JSON RPC server encountered fatal error")
+func (d *Daemon) serveAdmin() {
+	d.logger.WithFields(supportlog.F{
+		"addr": d.adminListener.Addr().String(),
+	}).Info("starting Admin HTTP server")
+	util.UnrecoverablePanicGroup.Log(d.logger).Go(func() {
+		if err := serve(d.adminServer, d.adminListener); !errors.Is(err, http.ErrServerClosed) {
+			d.logger.WithError(err).Error("soroban admin server encountered fatal error")
 		}
 	})
+}
 
-	if d.adminServer != nil {
+func (d *Daemon) startGossip() {
+	if d.gossipNode != nil {
+		d.logger.WithFields(supportlog.F{
+			"addr": d.gossipNode.Addr().String(),
+		}).Info("starting peer gossip node")
+		d.gossipNode.Start()
+	}
+}
+
+func (d *Daemon) Run() {
+	if d.server != nil {
 		d.logger.WithFields(supportlog.F{
-			"addr": d.adminListener.Addr().String(),
-		}).Info("starting Admin HTTP server")
+			"addr": d.listener.Addr().String(),
+		}).Info("starting HTTP server")
+
+		panicGroup := util.UnrecoverablePanicGroup.Log(d.logger)
 		panicGroup.Go(func() {
-			if err := d.adminServer.Serve(d.adminListener); !errors.Is(err, http.ErrServerClosed) {
-				d.logger.WithError(err).Error("soroban admin server encountered fatal error")
+			if err := serve(d.server, d.listener); !errors.Is(err, http.ErrServerClosed) {
+				d.logger.WithError(err).Fatal("soroban JSON RPC server encountered fatal error")
 			}
 		})
 	}
 
-	// Shutdown gracefully when we receive an interrupt signal.
-	// First server.Shutdown closes all open listeners, then closes all idle connections.
-	// Finally, it waits a grace period (10s here) for connections to return to idle and then shut down.
+	d.startGossip()
+	for _, network := range d.networks {
+		network.startGossip()
+	}
+
+	// Shutdown gracefully when we receive an interrupt signal (see close())
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
 
@@ -0,0 +1,208 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	supportlog "github.com/stellar/go/support/log"
+)
+
+// logLevels manages the loggers of the daemon and its subsystems, whose
+// levels can be changed at runtime. The loggers only filter the entries,
+// forwarding them to the sink logger provided to the daemon (which determines
+// the format and destination of the output).
+type logLevels struct {
+	sink       *supportlog.Entry
+	global     *supportlog.Entry
+	lock       sync.Mutex
+	level      logrus.Level
+	subsystems map[string]*subsystemLogger
+}
+
+type subsystemLogger struct {
+	logger *supportlog.Entry
+	// level is nil when following the global level
+	level *logrus.Level
+}
+
+// forwardHook forwards log entries to the sink logger
+type forwardHook struct {
+	sink *supportlog.Entry
+}
+
+func (h forwardHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h forwardHook) Fire(entry *logrus.Entry) error {
+	logger := h.sink.WithFields(supportlog.F(entry.Data))
+	switch entry.Level {
+	case logrus.PanicLevel:
+		logger.Panic(entry.Message)
+	case logrus.FatalLevel:
+		logger.Fatal(entry.Message)
+	case logrus.ErrorLevel:
+		logger.Error(entry.Message)
+	case logrus.WarnLevel:
+		logger.Warn(entry.Message)
+	case logrus.InfoLevel:
+		logger.Info(entry.Message)
+	default:
+		logger.Debug(entry.Message)
+	}
+	return nil
+}
+
+func newLogLevels(sink *supportlog.Entry, level logrus.Level) *logLevels {
+	// the sink logs everything, the filtering is done by the forwarding loggers
+	sink.SetLevel(logrus.TraceLevel)
+	l := &logLevels{
+		sink:       sink,
+		level:      level,
+		subsystems: map[string]*subsystemLogger{},
+	}
+	l.global = l.newForwardingLogger(level)
+	return l
+}
+
+func (l *logLevels) newForwardingLogger(level logrus.Level) *supportlog.Entry {
+	logger := supportlog.New()
+	logger.SetOutput(io.Discard)
+	logger.SetLevel(level)
+	logger.AddHook(forwardHook{sink: l.sink})
+	return logger
+}
+
+// subsystem returns the logger of a subsystem, which follows the global
+// level unless a level is set for it.
+func (l *logLevels) subsystem(name string) *supportlog.Entry {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if s, ok := l.subsystems[name]; ok {
+		return s.logger.WithField("subservice", name)
+	}
+	logger := l.newForwardingLogger(l.level)
+	l.subsystems[name] = &subsystemLogger{logger: logger}
+	return logger.WithField("subservice", name)
+}
+
+// setLevels changes the global level (if not nil) and the levels of the given
+// subsystems. A nil subsystem level makes the subsystem follow the global level.
+func (l *logLevels) setLevels(global *logrus.Level, subsystems map[string]*logrus.Level) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for name := range subsystems {
+		if _, ok := l.subsystems[name]; !ok {
+			return fmt.Errorf("unknown subsystem %q", name)
+		}
+	}
+	if global != nil {
+		l.level = *global
+		l.global.SetLevel(*global)
+	}
+	for name, level := range subsystems {
+		l.subsystems[name].level = level
+	}
+	for _, s := range l.subsystems {
+		if s.level != nil {
+			s.logger.SetLevel(*s.level)
+		} else {
+			s.logger.SetLevel(l.level)
+		}
+	}
+	return nil
+}
+
+type logLevelsState struct {
+	Level      string            `json:"level"`
+	Subsystems map[string]string `json:"subsystems"`
+}
+
+func (l *logLevels) state() logLevelsState {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	state := logLevelsState{
+		Level:      l.level.String(),
+		Subsystems: make(map[string]string, len(l.subsystems)),
+	}
+	for name, s := range l.subsystems {
+		level := l.level
+		if s.level != nil {
+			level = *s.level
+		}
+		state.Subsystems[name] = level.String()
+	}
+	return state
+}
+
+type setLogLevelsRequest struct {
+	Level string `json:"level,omitempty"`
+	// Subsystems maps subsystems to their level, "" makes them follow the global level
+	Subsystems map[string]string `json:"subsystems,omitempty"`
+}
+
+func parseLogLevel(level string) (logrus.Level, error) {
+	parsed, err := logrus.ParseLevel(level)
+	if err != nil {
+		return 0, fmt.Errorf("invalid log level %q", level)
+	}
+	return parsed, nil
+}
+
+// ServeHTTP reports the log levels (GET) or changes them (POST), e.g.
+// {"level": "info", "subsystems": {"ingest": "debug"}}
+func (l *logLevels) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var request setLogLevelsRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+		var global *logrus.Level
+		if request.Level != "" {
+			level, err := parseLogLevel(request.Level)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			global = &level
+		}
+		subsystems := make(map[string]*logrus.Level, len(request.Subsystems))
+		names := make([]string, 0, len(request.Subsystems))
+		for name := range request.Subsystems {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			subsystems[name] = nil
+			if request.Subsystems[name] == "" {
+				continue
+			}
+			level, err := parseLogLevel(request.Subsystems[name])
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			subsystems[name] = &level
+		}
+		if err := l.setLevels(global, subsystems); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		l.global.WithField("levels", request).Info("changed log levels")
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(l.state())
+}
@@ -0,0 +1,83 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	supportlog "github.com/stellar/go/support/log"
+)
+
+func logLevelsRequest(t *testing.T, levels *logLevels, method string, body string) (int, logLevelsState) {
+	req := httptest.NewRequest(method, "/log-level", strings.NewReader(body))
+	res := httptest.NewRecorder()
+	levels.ServeHTTP(res, req)
+	var state logLevelsState
+	if res.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &state))
+	}
+	return res.Code, state
+}
+
+func TestLogLevels(t *testing.T) {
+	var out bytes.Buffer
+	sink := supportlog.New()
+	sink.SetOutput(&out)
+	levels := newLogLevels(sink, logrus.InfoLevel)
+	ingestLogger := levels.subsystem("ingest")
+	dbLogger := levels.subsystem("db")
+
+	levels.global.Info("global info")
+	levels.global.Debug("global debug")
+	ingestLogger.Debug("ingest debug")
+	assert.Contains(t, out.String(), "global info")
+	assert.NotContains(t, out.String(), "debug")
+	out.Reset()
+
+	code, state := logLevelsRequest(t, levels, http.MethodPost, `{"subsystems": {"ingest": "debug"}}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, logLevelsState{
+		Level:      "info",
+		Subsystems: map[string]string{"ingest": "debug", "db": "info"},
+	}, state)
+	out.Reset()
+	levels.global.Debug("global debug")
+	dbLogger.Debug("db debug")
+	ingestLogger.WithField("ledger", 10).Debug("ingest debug")
+	assert.NotContains(t, out.String(), "global debug")
+	assert.NotContains(t, out.String(), "db debug")
+	assert.Contains(t, out.String(), "ingest debug")
+	assert.Contains(t, out.String(), "ledger=10")
+	assert.Contains(t, out.String(), "subservice=ingest")
+	out.Reset()
+
+	// the global level applies to the subsystems without a level
+	code, state = logLevelsRequest(t, levels, http.MethodPost, `{"level": "error", "subsystems": {"ingest": ""}}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, logLevelsState{
+		Level:      "error",
+		Subsystems: map[string]string{"ingest": "error", "db": "error"},
+	}, state)
+	ingestLogger.Warn("ingest warning")
+	dbLogger.Error("db error")
+	assert.NotContains(t, out.String(), "ingest warning")
+	assert.Contains(t, out.String(), "db error")
+
+	code, getState := logLevelsRequest(t, levels, http.MethodGet, "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, state, getState)
+
+	code, _ = logLevelsRequest(t, levels, http.MethodPost, `{"level": "verbose"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = logLevelsRequest(t, levels, http.MethodPost, `{"subsystems": {"foo": "debug"}}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = logLevelsRequest(t, levels, http.MethodDelete, "")
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+}
@@ -0,0 +1,33 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/graphql"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/horizon"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/subscriptions"
+)
+
+// reservedNetworkNames are the paths served by the endpoint of every network,
+// which cannot be used as the prefixes of additional networks
+func reservedNetworkNames() map[string]bool {
+	names := map[string]bool{}
+	for _, path := range []string{graphql.Path, horizon.Path, subscriptions.Path} {
+		names[strings.Trim(path, "/")] = true
+	}
+	return names
+}
+
+// checkNetworkNames verifies that the prefixes of the additional networks don't
+// collide with the paths served by the endpoint
+func checkNetworkNames(networks []config.NetworkConfig) error {
+	reserved := reservedNetworkNames()
+	for _, network := range networks {
+		if reserved[network.Name] {
+			return fmt.Errorf("network name %q is reserved", network.Name)
+		}
+	}
+	return nil
+}
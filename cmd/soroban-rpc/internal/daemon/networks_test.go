@@ -0,0 +1,21 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
+)
+
+func TestCheckNetworkNames(t *testing.T) {
+	require.NoError(t, checkNetworkNames([]config.NetworkConfig{
+		{Name: "testnet", ConfigPath: "/etc/testnet.toml"},
+		{Name: "futurenet", ConfigPath: "/etc/futurenet.toml"},
+	}))
+	for _, name := range []string{"graphql", "horizon", "ws"} {
+		err := checkNetworkNames([]config.NetworkConfig{{Name: name, ConfigPath: "/etc/a.toml"}})
+		assert.EqualError(t, err, `network name "`+name+`" is reserved`)
+	}
+}
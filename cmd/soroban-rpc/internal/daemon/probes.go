@@ -0,0 +1,142 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+)
+
+const (
+	readinessStatusInitializing = "initializing"
+	readinessStatusCatchingUp   = "catching-up"
+	readinessStatusReady        = "ready"
+
+	readinessCoreInfoTimeout = 2 * time.Second
+)
+
+// startupProgress tracks the initialization of the in-memory stores and the
+// data migrations, which replay the ledgers stored in the database
+type startupProgress struct {
+	lock           sync.Mutex
+	done           bool
+	appliedLedgers uint32
+	totalLedgers   uint32
+}
+
+func (p *startupProgress) start(totalLedgers uint32) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.totalLedgers = totalLedgers
+}
+
+func (p *startupProgress) ledgerApplied() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.appliedLedgers++
+}
+
+func (p *startupProgress) finish() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.done = true
+}
+
+type startupProgressState struct {
+	Done           bool    `json:"done"`
+	AppliedLedgers uint32  `json:"appliedLedgers"`
+	TotalLedgers   uint32  `json:"totalLedgers"`
+	Percent        float64 `json:"percent"`
+}
+
+func (p *startupProgress) state() startupProgressState {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	state := startupProgressState{
+		Done:           p.done,
+		AppliedLedgers: p.appliedLedgers,
+		TotalLedgers:   p.totalLedgers,
+		Percent:        100,
+	}
+	if !p.done && p.totalLedgers > 0 {
+		state.Percent = 100 * float64(p.appliedLedgers) / float64(p.totalLedgers)
+	}
+	return state
+}
+
+// readinessProbe reports whether the node can serve traffic: the startup
+// initialization must be finished and the latest ingested ledger must be recent
+// (i.e. captive core caught up with the network).
+type readinessProbe struct {
+	progress         *startupProgress
+	ledgerRange      func(ctx context.Context) (ledgerbucketwindow.LedgerRange, error)
+	coreClient       interfaces.CoreClient
+	maxLedgerLatency time.Duration
+}
+
+type readinessState struct {
+	Status                string               `json:"status"`
+	Startup               startupProgressState `json:"startup"`
+	LatestLedger          uint32               `json:"latestLedger,omitempty"`
+	LatestLedgerCloseTime int64                `json:"latestLedgerCloseTime,omitempty"`
+	CoreState             string               `json:"coreState,omitempty"`
+	Reason                string               `json:"reason,omitempty"`
+}
+
+func (p readinessProbe) state(ctx context.Context) readinessState {
+	state := readinessState{
+		Status:  readinessStatusReady,
+		Startup: p.progress.state(),
+	}
+	coreCtx, cancel := context.WithTimeout(ctx, readinessCoreInfoTimeout)
+	defer cancel()
+	if info, err := p.coreClient.Info(coreCtx); err == nil {
+		state.CoreState = info.Info.State
+	}
+	if !state.Startup.Done {
+		state.Status = readinessStatusInitializing
+		state.Reason = "replaying the ledgers stored in the database"
+		return state
+	}
+	ledgerRange, err := p.ledgerRange(ctx)
+	if err != nil {
+		state.Status = readinessStatusCatchingUp
+		state.Reason = fmt.Sprintf("could not obtain the latest ledger: %v", err)
+		return state
+	}
+	if ledgerRange.LastLedger.Sequence == 0 {
+		state.Status = readinessStatusCatchingUp
+		state.Reason = "no ledgers were ingested yet"
+		return state
+	}
+	state.LatestLedger = ledgerRange.LastLedger.Sequence
+	state.LatestLedgerCloseTime = ledgerRange.LastLedger.CloseTime
+	latency := time.Since(time.Unix(ledgerRange.LastLedger.CloseTime, 0))
+	if latency > p.maxLedgerLatency {
+		state.Status = readinessStatusCatchingUp
+		state.Reason = fmt.Sprintf("latency (%s) since last known ledger closed is too high (>%s)",
+			latency.Round(time.Second), p.maxLedgerLatency)
+	}
+	return state
+}
+
+// ServeHTTP responds with 200 when the node is ready and with 503 otherwise
+func (p readinessProbe) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	state := p.state(r.Context())
+	w.Header().Set("Content-Type", "application/json")
+	if state.Status != readinessStatusReady {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(state)
+}
+
+// serveLiveness responds with 200 as long as the daemon is able to serve requests
+func serveLiveness(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"status":"alive"}` + "\n"))
+}
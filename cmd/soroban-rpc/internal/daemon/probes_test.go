@@ -0,0 +1,96 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	proto "github.com/stellar/go/protocols/stellarcore"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+)
+
+type fakeCoreClient struct {
+	state string
+}
+
+func (c fakeCoreClient) Info(context.Context) (*proto.InfoResponse, error) {
+	if c.state == "" {
+		return nil, errors.New("core is unreachable")
+	}
+	var info proto.InfoResponse
+	info.Info.State = c.state
+	return &info, nil
+}
+
+func (c fakeCoreClient) SubmitTransaction(context.Context, string) (*proto.TXResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func probeReadiness(t *testing.T, probe readinessProbe) (int, readinessState) {
+	res := httptest.NewRecorder()
+	probe.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	var state readinessState
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &state))
+	return res.Code, state
+}
+
+func TestReadinessProbe(t *testing.T) {
+	var progress startupProgress
+	var latestLedger ledgerbucketwindow.LedgerInfo
+	probe := readinessProbe{
+		progress: &progress,
+		ledgerRange: func(context.Context) (ledgerbucketwindow.LedgerRange, error) {
+			return ledgerbucketwindow.LedgerRange{LastLedger: latestLedger}, nil
+		},
+		coreClient:       fakeCoreClient{state: "Catching up"},
+		maxLedgerLatency: time.Minute,
+	}
+
+	progress.start(4)
+	progress.ledgerApplied()
+	code, state := probeReadiness(t, probe)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, readinessStatusInitializing, state.Status)
+	assert.Equal(t, uint32(1), state.Startup.AppliedLedgers)
+	assert.Equal(t, uint32(4), state.Startup.TotalLedgers)
+	assert.InDelta(t, 25, state.Startup.Percent, 0.001)
+	assert.Equal(t, "Catching up", state.CoreState)
+
+	progress.finish()
+	code, state = probeReadiness(t, probe)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, readinessStatusCatchingUp, state.Status)
+	assert.Equal(t, "no ledgers were ingested yet", state.Reason)
+	assert.InDelta(t, 100, state.Startup.Percent, 0.001)
+
+	latestLedger = ledgerbucketwindow.LedgerInfo{Sequence: 10, CloseTime: time.Now().Add(-time.Hour).Unix()}
+	code, state = probeReadiness(t, probe)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, readinessStatusCatchingUp, state.Status)
+	assert.Equal(t, uint32(10), state.LatestLedger)
+	assert.Contains(t, state.Reason, "since last known ledger closed is too high")
+
+	latestLedger = ledgerbucketwindow.LedgerInfo{Sequence: 11, CloseTime: time.Now().Unix()}
+	probe.coreClient = fakeCoreClient{state: "Synced!"}
+	code, state = probeReadiness(t, probe)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, readinessStatusReady, state.Status)
+	assert.Equal(t, uint32(11), state.LatestLedger)
+	assert.Equal(t, "Synced!", state.CoreState)
+	assert.Empty(t, state.Reason)
+}
+
+func TestLivenessProbe(t *testing.T) {
+	res := httptest.NewRecorder()
+	serveLiveness(res, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.JSONEq(t, `{"status": "alive"}`, res.Body.String())
+}
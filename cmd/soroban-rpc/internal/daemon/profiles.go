@@ -0,0 +1,306 @@
+package daemon
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	runtimePprof "runtime/pprof"
+	"sync"
+	"time"
+
+	supportlog "github.com/stellar/go/support/log"
+)
+
+const (
+	cpuProfile               = "cpu"
+	defaultProfileDuration   = 30 * time.Second
+	maxProfileDuration       = 5 * time.Minute
+	profileTriggerCooldown   = 15 * time.Minute
+	heapTriggerCheckInterval = 10 * time.Second
+)
+
+var defaultProfiles = []string{cpuProfile, "heap", "goroutine"}
+
+// profiler captures the profiles requested through the admin API (or triggered automatically), one capture at a time
+type profiler struct {
+	ctx    context.Context
+	logger *supportlog.Entry
+	// dir is where the profiles are written, when empty they are returned in the response
+	dir string
+	// requestDurationThreshold triggers a capture when a request takes longer (when positive)
+	requestDurationThreshold time.Duration
+	lock                     sync.Mutex
+	// job is the latest capture (if any)
+	job           *profileJob
+	lastTriggered time.Time
+}
+
+type profileJob struct {
+	Profiles []string `json:"profiles"`
+	Duration string   `json:"duration"`
+	// Trigger is the reason of the automatic captures
+	Trigger    string     `json:"trigger,omitempty"`
+	Status     string     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	Files      []string   `json:"files,omitempty"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+type profileRequest struct {
+	// Duration of the CPU profile, the other profiles are snapshots taken at its end
+	Duration string   `json:"duration,omitempty"`
+	Profiles []string `json:"profiles,omitempty"`
+}
+
+func (r profileRequest) parse() (time.Duration, []string, error) {
+	duration := defaultProfileDuration
+	if r.Duration != "" {
+		var err error
+		if duration, err = time.ParseDuration(r.Duration); err != nil {
+			return 0, nil, fmt.Errorf("invalid duration: %w", err)
+		}
+		if duration <= 0 || duration > maxProfileDuration {
+			return 0, nil, fmt.Errorf("the duration must be positive and at most %s", maxProfileDuration)
+		}
+	}
+	profiles := r.Profiles
+	if len(profiles) == 0 {
+		profiles = defaultProfiles
+	}
+	for _, profile := range profiles {
+		if profile != cpuProfile && runtimePprof.Lookup(profile) == nil {
+			return 0, nil, fmt.Errorf("unknown profile %q", profile)
+		}
+	}
+	return duration, profiles, nil
+}
+
+// start registers a new job, unless a capture is running already
+func (p *profiler) start(duration time.Duration, profiles []string, trigger string) (*profileJob, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.job != nil && p.job.Status == jobStatusRunning {
+		return nil, errors.New("profiles are being captured already")
+	}
+	p.job = &profileJob{
+		Profiles:  profiles,
+		Duration:  duration.String(),
+		Trigger:   trigger,
+		Status:    jobStatusRunning,
+		StartedAt: time.Now(),
+	}
+	return p.job, nil
+}
+
+func (p *profiler) finish(job *profileJob, files []string, err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	job.Files = files
+	logger := p.logger.WithField("profiles", job.Profiles)
+	if err != nil {
+		job.Status = jobStatusFailed
+		job.Error = err.Error()
+		logger.WithError(err).Error("could not capture profiles")
+		return
+	}
+	job.Status = jobStatusDone
+	logger.WithField("files", files).Info("captured profiles")
+}
+
+// capture writes the profiles, the CPU one lasting for the given duration
+func capture(ctx context.Context, duration time.Duration, profiles []string, create func(name string) (io.WriteCloser, error)) error {
+	for _, profile := range profiles {
+		if profile != cpuProfile {
+			continue
+		}
+		w, err := create(cpuProfile)
+		if err != nil {
+			return err
+		}
+		if err := runtimePprof.StartCPUProfile(w); err != nil {
+			w.Close()
+			return fmt.Errorf("could not start CPU profile: %w", err)
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(duration):
+		}
+		runtimePprof.StopCPUProfile()
+		if err := w.Close(); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	for _, profile := range profiles {
+		if profile == cpuProfile {
+			continue
+		}
+		if profile == "heap" {
+			// get up-to-date statistics
+			runtime.GC()
+		}
+		w, err := create(profile)
+		if err != nil {
+			return err
+		}
+		err = runtimePprof.Lookup(profile).WriteTo(w, 0)
+		if closeErr := w.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("could not write %s profile: %w", profile, err)
+		}
+	}
+	return nil
+}
+
+// captureToDir captures the profiles into files of the profiles directory
+func (p *profiler) captureToDir(job *profileJob, duration time.Duration) {
+	prefix := job.StartedAt.UTC().Format("20060102T150405")
+	var files []string
+	err := capture(p.ctx, duration, job.Profiles, func(name string) (io.WriteCloser, error) {
+		path := filepath.Join(p.dir, prefix+"-"+name+".pprof")
+		files = append(files, path)
+		return os.Create(path)
+	})
+	p.finish(job, files, err)
+}
+
+type zipEntry struct {
+	io.Writer
+}
+
+func (zipEntry) Close() error {
+	return nil
+}
+
+// captureToZip captures the profiles into a zip archive
+func (p *profiler) captureToZip(ctx context.Context, job *profileJob, duration time.Duration) ([]byte, error) {
+	var archive bytes.Buffer
+	zipWriter := zip.NewWriter(&archive)
+	err := capture(ctx, duration, job.Profiles, func(name string) (io.WriteCloser, error) {
+		w, err := zipWriter.Create(name + ".pprof")
+		return zipEntry{w}, err
+	})
+	if err == nil {
+		err = zipWriter.Close()
+	}
+	p.finish(job, nil, err)
+	return archive.Bytes(), err
+}
+
+// trigger starts capturing the default profiles into the profiles directory,
+// unless a capture is running or the previous one was triggered recently
+func (p *profiler) trigger(reason string) {
+	p.lock.Lock()
+	recent := !p.lastTriggered.IsZero() && time.Since(p.lastTriggered) < profileTriggerCooldown
+	if !recent {
+		p.lastTriggered = time.Now()
+	}
+	p.lock.Unlock()
+	if recent {
+		return
+	}
+	job, err := p.start(defaultProfileDuration, defaultProfiles, reason)
+	if err != nil {
+		return
+	}
+	p.logger.WithField("trigger", reason).Warn("capturing profiles")
+	go p.captureToDir(job, defaultProfileDuration)
+}
+
+// observeRequestDuration triggers a capture when a request takes longer than the threshold
+func (p *profiler) observeRequestDuration(duration time.Duration) {
+	if duration > p.requestDurationThreshold {
+		p.trigger(fmt.Sprintf("request duration %s exceeded %s", duration, p.requestDurationThreshold))
+	}
+}
+
+// watchHeap triggers a capture when the heap grows over the threshold (in bytes)
+func (p *profiler) watchHeap(threshold uint64) {
+	ticker := time.NewTicker(heapTriggerCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			var stats runtime.MemStats
+			runtime.ReadMemStats(&stats)
+			if stats.HeapInuse > threshold {
+				p.trigger(fmt.Sprintf("heap size %d exceeded %d bytes", stats.HeapInuse, threshold))
+			}
+		}
+	}
+}
+
+// ServeHTTP reports the progress of the latest capture (GET) or starts
+// a new one (POST), e.g. {"duration": "30s", "profiles": ["cpu", "heap", "goroutine"]}.
+// When no profiles directory is configured, the POST request waits for the capture
+// and responds with a zip archive of the profiles.
+func (p *profiler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		p.lock.Lock()
+		var job profileJob
+		found := p.job != nil
+		if found {
+			job = *p.job
+		}
+		p.lock.Unlock()
+		if !found {
+			http.Error(w, "no profiles were requested", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(job)
+	case http.MethodPost:
+		var request profileRequest
+		if err := json.NewDecoder(req.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+			http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+		duration, profiles, err := request.parse()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		job, err := p.start(duration, profiles, "")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if p.dir != "" {
+			status := *job
+			go p.captureToDir(job, duration)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusAccepted)
+			_ = json.NewEncoder(w).Encode(status)
+			return
+		}
+		archive, err := p.captureToZip(req.Context(), job, duration)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", `attachment; filename="profiles.zip"`)
+		_, _ = w.Write(archive)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,137 @@
+package daemon
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	supportlog "github.com/stellar/go/support/log"
+)
+
+func doProfileRequest(handler http.Handler, method string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/profiles", strings.NewReader(body))
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	return res
+}
+
+func TestProfilesToDirectory(t *testing.T) {
+	handler := &profiler{
+		ctx:    context.Background(),
+		logger: supportlog.New(),
+		dir:    t.TempDir(),
+	}
+	res := doProfileRequest(handler, http.MethodGet, "")
+	assert.Equal(t, http.StatusNotFound, res.Code)
+
+	res = doProfileRequest(handler, http.MethodPost, `{"duration": "200ms", "profiles": ["cpu", "goroutine"]}`)
+	require.Equal(t, http.StatusAccepted, res.Code)
+	var job profileJob
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &job))
+	assert.Equal(t, jobStatusRunning, job.Status)
+	assert.Equal(t, "200ms", job.Duration)
+
+	// only one capture at a time
+	res = doProfileRequest(handler, http.MethodPost, `{}`)
+	assert.Equal(t, http.StatusConflict, res.Code)
+
+	assert.Eventually(t, func() bool {
+		res = doProfileRequest(handler, http.MethodGet, "")
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &job))
+		return job.Status != jobStatusRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, jobStatusDone, job.Status, job.Error)
+	require.Len(t, job.Files, 2)
+	for _, file := range job.Files {
+		info, err := os.Stat(file)
+		require.NoError(t, err)
+		assert.Positive(t, info.Size())
+	}
+}
+
+func TestProfilesInResponse(t *testing.T) {
+	handler := &profiler{
+		ctx:    context.Background(),
+		logger: supportlog.New(),
+	}
+	res := doProfileRequest(handler, http.MethodPost, `{"duration": "10ms", "profiles": ["heap", "goroutine"]}`)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	assert.Equal(t, "application/zip", res.Header().Get("Content-Type"))
+	archive, err := zip.NewReader(bytes.NewReader(res.Body.Bytes()), int64(res.Body.Len()))
+	require.NoError(t, err)
+	var names []string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	assert.Equal(t, []string{"heap.pprof", "goroutine.pprof"}, names)
+
+	res = doProfileRequest(handler, http.MethodGet, "")
+	var job profileJob
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &job))
+	assert.Equal(t, jobStatusDone, job.Status)
+}
+
+func TestProfilesInvalidRequests(t *testing.T) {
+	handler := &profiler{
+		ctx:    context.Background(),
+		logger: supportlog.New(),
+	}
+	for _, body := range []string{
+		`not json`,
+		`{"duration": "forever"}`,
+		`{"duration": "1h"}`,
+		`{"duration": "-1s"}`,
+		`{"profiles": ["everything"]}`,
+	} {
+		res := doProfileRequest(handler, http.MethodPost, body)
+		assert.Equal(t, http.StatusBadRequest, res.Code, body)
+	}
+	res := doProfileRequest(handler, http.MethodDelete, "")
+	assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+}
+
+func TestProfilesTrigger(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := &profiler{
+		ctx:                      ctx,
+		logger:                   supportlog.New(),
+		dir:                      t.TempDir(),
+		requestDurationThreshold: time.Second,
+	}
+	handler.observeRequestDuration(time.Millisecond)
+	res := doProfileRequest(handler, http.MethodGet, "")
+	assert.Equal(t, http.StatusNotFound, res.Code)
+
+	handler.observeRequestDuration(2 * time.Second)
+	res = doProfileRequest(handler, http.MethodGet, "")
+	require.Equal(t, http.StatusOK, res.Code)
+	var job profileJob
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &job))
+	assert.Equal(t, jobStatusRunning, job.Status)
+	assert.Contains(t, job.Trigger, "request duration 2s exceeded 1s")
+	triggeredAt := job.StartedAt
+
+	// stop the capture
+	cancel()
+	assert.Eventually(t, func() bool {
+		res = doProfileRequest(handler, http.MethodGet, "")
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &job))
+		return job.Status != jobStatusRunning
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the trigger is in cooldown
+	handler.observeRequestDuration(2 * time.Second)
+	res = doProfileRequest(handler, http.MethodGet, "")
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &job))
+	assert.Equal(t, triggeredAt, job.StartedAt)
+}
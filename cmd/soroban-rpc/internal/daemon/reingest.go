@@ -0,0 +1,134 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	supportlog "github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+)
+
+type reingestFn func(ctx context.Context, start, end uint32, progress db.ReingestProgressFn) error
+
+// reingester runs the reingestion jobs requested through the admin API, one at a time
+type reingester struct {
+	ctx         context.Context
+	logger      *supportlog.Entry
+	ledgerRange func(ctx context.Context) (ledgerbucketwindow.LedgerRange, error)
+	reingest    reingestFn
+	lock        sync.Mutex
+	// job is the latest job (if any)
+	job *reingestJob
+}
+
+type reingestJob struct {
+	StartLedger       uint32     `json:"startLedger"`
+	EndLedger         uint32     `json:"endLedger"`
+	TotalLedgers      uint32     `json:"totalLedgers"`
+	ReingestedLedgers uint32     `json:"reingestedLedgers"`
+	Status            string     `json:"status"`
+	Error             string     `json:"error,omitempty"`
+	StartedAt         time.Time  `json:"startedAt"`
+	FinishedAt        *time.Time `json:"finishedAt,omitempty"`
+}
+
+type reingestRequest struct {
+	StartLedger uint32 `json:"startLedger"`
+	EndLedger   uint32 `json:"endLedger"`
+}
+
+// start starts a job reingesting the given ledger range, unless a job is already running
+func (r *reingester) start(start, end uint32) (reingestJob, int, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.job != nil && r.job.Status == jobStatusRunning {
+		return reingestJob{}, http.StatusConflict, fmt.Errorf(
+			"ledgers [%d, %d] are being reingested already", r.job.StartLedger, r.job.EndLedger,
+		)
+	}
+	ledgerRange, err := r.ledgerRange(r.ctx)
+	if err != nil {
+		return reingestJob{}, http.StatusInternalServerError, err
+	}
+	err = db.CheckStoredLedgerRange(ledgerRange.FirstLedger.Sequence, ledgerRange.LastLedger.Sequence, start, end)
+	if err != nil {
+		return reingestJob{}, http.StatusBadRequest, err
+	}
+	r.job = &reingestJob{
+		StartLedger:  start,
+		EndLedger:    end,
+		TotalLedgers: end - start + 1,
+		Status:       jobStatusRunning,
+		StartedAt:    time.Now(),
+	}
+	logger := r.logger.WithField("start", start).WithField("end", end)
+	logger.Info("reingesting ledgers")
+	go func(job *reingestJob) {
+		err := r.reingest(r.ctx, start, end, func(ledgerSeq uint32) {
+			r.lock.Lock()
+			job.ReingestedLedgers = ledgerSeq - start + 1
+			r.lock.Unlock()
+		})
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		finishedAt := time.Now()
+		job.FinishedAt = &finishedAt
+		if err != nil {
+			job.Status = jobStatusFailed
+			job.Error = err.Error()
+			logger.WithError(err).Error("could not reingest ledgers")
+			return
+		}
+		job.Status = jobStatusDone
+		logger.WithField("duration", finishedAt.Sub(job.StartedAt).Seconds()).Info("reingested ledgers")
+	}(r.job)
+	return *r.job, http.StatusAccepted, nil
+}
+
+func (r *reingester) status() (reingestJob, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.job == nil {
+		return reingestJob{}, false
+	}
+	return *r.job, true
+}
+
+// ServeHTTP reports the progress of the latest reingestion job (GET) or starts
+// a new one (POST), e.g. {"startLedger": 1000, "endLedger": 2000}
+func (r *reingester) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var job reingestJob
+	status := http.StatusOK
+	switch req.Method {
+	case http.MethodGet:
+		var ok bool
+		if job, ok = r.status(); !ok {
+			http.Error(w, "no reingestion was requested", http.StatusNotFound)
+			return
+		}
+	case http.MethodPost:
+		var request reingestRequest
+		if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+			return
+		}
+		var err error
+		if job, status, err = r.start(request.StartLedger, request.EndLedger); err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(job)
+}
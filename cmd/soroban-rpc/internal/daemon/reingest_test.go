@@ -0,0 +1,118 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	supportlog "github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+)
+
+func doReingestRequest(t *testing.T, handler http.Handler, method string, body string) (int, reingestJob) {
+	req := httptest.NewRequest(method, "/reingest", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer secret")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	var job reingestJob
+	if res.Code == http.StatusOK || res.Code == http.StatusAccepted {
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &job))
+	}
+	return res.Code, job
+}
+
+func newTestReingester(reingest reingestFn) http.Handler {
+	r := &reingester{
+		ctx:    context.Background(),
+		logger: supportlog.New(),
+		ledgerRange: func(context.Context) (ledgerbucketwindow.LedgerRange, error) {
+			return ledgerbucketwindow.LedgerRange{
+				FirstLedger: ledgerbucketwindow.LedgerInfo{Sequence: 100},
+				LastLedger:  ledgerbucketwindow.LedgerInfo{Sequence: 1000},
+			}, nil
+		},
+		reingest: reingest,
+	}
+	return requireBearerToken("secret", r)
+}
+
+func TestReingest(t *testing.T) {
+	proceed := make(chan struct{})
+	handler := newTestReingester(func(_ context.Context, start, end uint32, progress db.ReingestProgressFn) error {
+		progress(start + 9)
+		<-proceed
+		progress(end)
+		return nil
+	})
+
+	code, _ := doReingestRequest(t, handler, http.MethodGet, "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, job := doReingestRequest(t, handler, http.MethodPost, `{"startLedger": 200, "endLedger": 299}`)
+	require.Equal(t, http.StatusAccepted, code)
+	assert.Equal(t, uint32(200), job.StartLedger)
+	assert.Equal(t, uint32(299), job.EndLedger)
+	assert.Equal(t, uint32(100), job.TotalLedgers)
+	assert.Equal(t, jobStatusRunning, job.Status)
+
+	assert.Eventually(t, func() bool {
+		_, job = doReingestRequest(t, handler, http.MethodGet, "")
+		return job.ReingestedLedgers == 10
+	}, time.Second, 10*time.Millisecond)
+
+	// only one job at a time
+	code, _ = doReingestRequest(t, handler, http.MethodPost, `{"startLedger": 200, "endLedger": 299}`)
+	assert.Equal(t, http.StatusConflict, code)
+
+	close(proceed)
+	assert.Eventually(t, func() bool {
+		_, job = doReingestRequest(t, handler, http.MethodGet, "")
+		return job.Status == jobStatusDone
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, uint32(100), job.ReingestedLedgers)
+	assert.NotNil(t, job.FinishedAt)
+}
+
+func TestReingestFailure(t *testing.T) {
+	handler := newTestReingester(func(context.Context, uint32, uint32, db.ReingestProgressFn) error {
+		return errors.New("boom")
+	})
+
+	code, _ := doReingestRequest(t, handler, http.MethodPost, `{"startLedger": 50, "endLedger": 299}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = doReingestRequest(t, handler, http.MethodPost, `{"startLedger": 300, "endLedger": 299}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = doReingestRequest(t, handler, http.MethodPost, `{"startLedger": 200, "endLedger": 299}`)
+	require.Equal(t, http.StatusAccepted, code)
+	var job reingestJob
+	assert.Eventually(t, func() bool {
+		_, job = doReingestRequest(t, handler, http.MethodGet, "")
+		return job.Status == jobStatusFailed
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "boom", job.Error)
+}
+
+func TestReingestRequiresToken(t *testing.T) {
+	handler := newTestReingester(nil)
+	for _, authorization := range []string{"", "secret", "Bearer wrong"} {
+		req := httptest.NewRequest(http.MethodGet, "/reingest", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusUnauthorized, res.Code, authorization)
+		assert.Equal(t, "Bearer", res.Header().Get("WWW-Authenticate"))
+	}
+}
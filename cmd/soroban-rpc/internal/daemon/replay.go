@@ -0,0 +1,75 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+
+	supportlog "github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/events"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/feewindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ingest"
+)
+
+// Replay ingests the given ledger range (obtained through captive core) into
+// the configured database, without serving any requests.
+func Replay(ctx context.Context, cfg *config.Config, logger *supportlog.Entry, start, end uint32) error {
+	if cfg.LogFormat == config.LogFormatJSON {
+		logger.UseJSONFormatter()
+	}
+	levels := newLogLevels(logger, cfg.LogLevel)
+	logger = levels.global
+
+	if len(cfg.HistoryArchiveURLs) == 0 {
+		return fmt.Errorf("no history archives URLs were provided")
+	}
+	historyArchive, err := newArchivePool(cfg, logger)
+	if err != nil {
+		return fmt.Errorf("could not connect to history archive: %w", err)
+	}
+	if err := prepareCoreBinary(cfg, logger, historyArchive); err != nil {
+		return fmt.Errorf("could not prepare the stellar-core binary: %w", err)
+	}
+	core, err := newCaptiveCore(cfg, logger, levels.subsystem("stellar-core"))
+	if err != nil {
+		return fmt.Errorf("could not create captive core: %w", err)
+	}
+	defer func() {
+		if err := core.Close(); err != nil {
+			logger.WithError(err).Error("error closing captive core")
+		}
+	}()
+	dbConn, err := db.OpenSQLiteDB(cfg.SQLiteDBPath)
+	if err != nil {
+		return fmt.Errorf("could not open database: %w", err)
+	}
+	defer func() {
+		if err := dbConn.Close(); err != nil {
+			logger.WithError(err).Error("error closing database")
+		}
+	}()
+
+	// the in-memory stores are only required by the ingestion pipeline
+	daemon := interfaces.MakeNoOpDeamon()
+	return ingest.Replay(ctx, ingest.Config{
+		Logger: levels.subsystem("ingest"),
+		DB: db.NewReadWriter(
+			levels.subsystem("db"),
+			dbConn,
+			daemon,
+			maxLedgerEntryWriteBatchSize,
+			cfg.HistoryRetentionWindow,
+			cfg.NetworkPassphrase,
+		),
+		EventStore:        events.NewMemoryStore(daemon, cfg.NetworkPassphrase, 1),
+		FeeWindows:        feewindow.NewFeeWindows(1, 1, cfg.NetworkPassphrase),
+		NetworkPassPhrase: cfg.NetworkPassphrase,
+		Archive:           historyArchive,
+		LedgerBackend:     core,
+		Timeout:           cfg.IngestionTimeout,
+		Daemon:            daemon,
+	}, start, end)
+}
@@ -0,0 +1,88 @@
+package daemon
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	supportlog "github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+type publishSnapshotFn func(ctx context.Context) (db.SnapshotManifest, error)
+
+// snapshotPublisher periodically publishes a snapshot of the database, from which new
+// nodes can be bootstrapped (see bootstrapFromSnapshot)
+type snapshotPublisher struct {
+	logger   *supportlog.Entry
+	interval time.Duration
+	publish  publishSnapshotFn
+	// latestLedgerMetric is the latest ledger of the last published snapshot
+	latestLedgerMetric prometheus.Gauge
+	errorsMetric       prometheus.Counter
+	durationMetric     prometheus.Summary
+	// done is closed once run returns
+	done chan struct{}
+}
+
+func newSnapshotPublisher(
+	logger *supportlog.Entry, interval time.Duration, registry *prometheus.Registry, publish publishSnapshotFn,
+) *snapshotPublisher {
+	publisher := &snapshotPublisher{
+		logger:   logger,
+		interval: interval,
+		publish:  publish,
+		latestLedgerMetric: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: prometheusNamespace, Subsystem: "snapshots", Name: "latest_ledger",
+			Help: "latest ledger of the last published database snapshot",
+		}),
+		errorsMetric: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: prometheusNamespace, Subsystem: "snapshots", Name: "publish_errors",
+			Help: "number of database snapshots which could not be published",
+		}),
+		durationMetric: prometheus.NewSummary(prometheus.SummaryOpts{
+			Namespace: prometheusNamespace, Subsystem: "snapshots", Name: "publish_duration_seconds",
+			Help:       "duration of the publication of the database snapshots",
+			Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+		}),
+		done: make(chan struct{}),
+	}
+	registry.MustRegister(publisher.latestLedgerMetric, publisher.errorsMetric, publisher.durationMetric)
+	return publisher
+}
+
+func (p *snapshotPublisher) publishOnce(ctx context.Context) {
+	startTime := time.Now()
+	manifest, err := p.publish(ctx)
+	if err != nil {
+		p.errorsMetric.Inc()
+		p.logger.WithError(err).Error("could not publish a database snapshot")
+		return
+	}
+	duration := time.Since(startTime)
+	p.durationMetric.Observe(duration.Seconds())
+	p.latestLedgerMetric.Set(float64(manifest.LatestLedger))
+	p.logger.WithFields(supportlog.F{
+		"snapshot":      manifest.Snapshot,
+		"latest_ledger": manifest.LatestLedger,
+		"size":          manifest.Size,
+		"duration":      duration.Seconds(),
+	}).Info("published a database snapshot")
+}
+
+// run publishes a snapshot every interval, until the context is done
+func (p *snapshotPublisher) run(ctx context.Context) {
+	defer close(p.done)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.publishOnce(ctx)
+		}
+	}
+}
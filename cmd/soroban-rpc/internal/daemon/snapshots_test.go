@@ -0,0 +1,48 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	supportlog "github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+func TestSnapshotPublisher(t *testing.T) {
+	published := make(chan struct{}, 10)
+	calls := 0
+	publisher := newSnapshotPublisher(
+		supportlog.New(),
+		time.Millisecond,
+		prometheus.NewRegistry(),
+		func(context.Context) (db.SnapshotManifest, error) {
+			calls++
+			defer func() { published <- struct{}{} }()
+			if calls == 1 {
+				return db.SnapshotManifest{}, errors.New("unavailable")
+			}
+			return db.SnapshotManifest{LatestLedger: uint32(100 * calls)}, nil
+		},
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	go publisher.run(ctx)
+	<-published
+	<-published
+	cancel()
+	select {
+	case <-publisher.done:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "the publisher didn't stop")
+	}
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(publisher.errorsMetric))
+	assert.GreaterOrEqual(t, testutil.ToFloat64(publisher.latestLedgerMetric), float64(200))
+}
@@ -0,0 +1,250 @@
+package daemon
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+)
+
+const (
+	statusSampleInterval = time.Minute
+	// statusSamples is the number of samples kept for the traffic rates (i.e. they are computed over 5 minutes)
+	statusSamples = 5
+)
+
+// methodTraffic are the cumulative counts of the JSON RPC requests of a method
+type methodTraffic struct {
+	requests        uint64
+	errors          uint64
+	durationSeconds float64
+}
+
+type trafficSample struct {
+	at      time.Time
+	methods map[string]methodTraffic
+}
+
+// statusPage serves an HTML page summarizing the health of the node
+type statusPage struct {
+	readiness   func(ctx context.Context) readinessState
+	ledgerRange func(ctx context.Context) (ledgerbucketwindow.LedgerRange, error)
+	gatherer    prometheus.Gatherer
+	// requestDurationMetric is the histogram of the JSON RPC request durations, by endpoint and status
+	requestDurationMetric string
+
+	lock    sync.Mutex
+	samples []trafficSample
+}
+
+// traffic reads the cumulative JSON RPC request counts from the metrics
+func (s *statusPage) traffic() map[string]methodTraffic {
+	methods := map[string]methodTraffic{}
+	families, err := s.gatherer.Gather()
+	if err != nil {
+		return methods
+	}
+	for _, family := range families {
+		if family.GetName() != s.requestDurationMetric {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			var endpoint, status string
+			for _, label := range metric.GetLabel() {
+				switch label.GetName() {
+				case "endpoint":
+					endpoint = label.GetValue()
+				case "status":
+					status = label.GetValue()
+				}
+			}
+			traffic := methods[endpoint]
+			traffic.requests += metric.GetHistogram().GetSampleCount()
+			traffic.durationSeconds += metric.GetHistogram().GetSampleSum()
+			if status != "ok" {
+				traffic.errors += metric.GetHistogram().GetSampleCount()
+			}
+			methods[endpoint] = traffic
+		}
+	}
+	return methods
+}
+
+// sample records the current request counts, so that the page shows recent rates
+func (s *statusPage) sample(now time.Time) {
+	sample := trafficSample{at: now, methods: s.traffic()}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.samples = append(s.samples, sample)
+	if len(s.samples) > statusSamples {
+		s.samples = s.samples[len(s.samples)-statusSamples:]
+	}
+}
+
+func (s *statusPage) run(ctx context.Context) {
+	s.sample(time.Now())
+	ticker := time.NewTicker(statusSampleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.sample(now)
+		}
+	}
+}
+
+type methodStatus struct {
+	Method            string
+	RequestsPerMinute float64
+	ErrorPercent      float64
+	MeanDuration      time.Duration
+}
+
+type statusPageData struct {
+	Readiness      readinessState
+	CoreState      string
+	IngestionLag   time.Duration
+	LedgerRange    ledgerbucketwindow.LedgerRange
+	LedgerRangeErr error
+	Window         time.Duration
+	Methods        []methodStatus
+	Requests       float64
+	ErrorPercent   float64
+	GeneratedAt    time.Time
+}
+
+func percent(part, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(part) / float64(total)
+}
+
+// methodStatuses computes the request rates since the oldest sample
+func (s *statusPage) methodStatuses(now time.Time) ([]methodStatus, time.Duration, methodTraffic) {
+	current := s.traffic()
+	s.lock.Lock()
+	var oldest trafficSample
+	if len(s.samples) > 0 {
+		oldest = s.samples[0]
+	}
+	s.lock.Unlock()
+	window := now.Sub(oldest.at)
+	if oldest.at.IsZero() || window <= 0 {
+		// no samples yet, report the counts since startup
+		oldest = trafficSample{methods: map[string]methodTraffic{}}
+		window = 0
+	}
+
+	var statuses []methodStatus
+	var total methodTraffic
+	for method, traffic := range current {
+		previous := oldest.methods[method]
+		delta := methodTraffic{
+			requests:        traffic.requests - previous.requests,
+			errors:          traffic.errors - previous.errors,
+			durationSeconds: traffic.durationSeconds - previous.durationSeconds,
+		}
+		total.requests += delta.requests
+		total.errors += delta.errors
+		status := methodStatus{
+			Method:            method,
+			RequestsPerMinute: float64(delta.requests),
+			ErrorPercent:      percent(delta.errors, delta.requests),
+		}
+		if window > 0 {
+			status.RequestsPerMinute = float64(delta.requests) / window.Minutes()
+		}
+		if delta.requests > 0 {
+			status.MeanDuration = time.Duration(delta.durationSeconds / float64(delta.requests) * float64(time.Second))
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Method < statuses[j].Method
+	})
+	return statuses, window, total
+}
+
+func (s *statusPage) data(ctx context.Context, now time.Time) statusPageData {
+	data := statusPageData{
+		Readiness:   s.readiness(ctx),
+		GeneratedAt: now.UTC(),
+	}
+	data.CoreState = data.Readiness.CoreState
+	if data.CoreState == "" {
+		data.CoreState = "unreachable"
+	}
+	data.LedgerRange, data.LedgerRangeErr = s.ledgerRange(ctx)
+	if data.LedgerRangeErr == nil && data.LedgerRange.LastLedger.Sequence != 0 {
+		data.IngestionLag = now.Sub(time.Unix(data.LedgerRange.LastLedger.CloseTime, 0)).Round(time.Second)
+	}
+	var total methodTraffic
+	data.Methods, data.Window, total = s.methodStatuses(now)
+	data.Window = data.Window.Round(time.Second)
+	data.Requests = float64(total.requests)
+	data.ErrorPercent = percent(total.errors, total.requests)
+	return data
+}
+
+var statusPageTemplate = template.Must(template.New("status").Funcs(template.FuncMap{
+	"unix": func(timestamp int64) string {
+		return time.Unix(timestamp, 0).UTC().Format(time.RFC3339)
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="10">
+<title>Soroban RPC status</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.8em; text-align: left; }
+.ready { color: green; }
+.unready { color: #c00; }
+</style>
+</head>
+<body>
+<h1>Soroban RPC status</h1>
+<h2>Node</h2>
+<table>
+<tr><th>Status</th><td class="{{if eq .Readiness.Status "ready"}}ready{{else}}unready{{end}}">{{.Readiness.Status}}{{with .Readiness.Reason}} ({{.}}){{end}}</td></tr>
+{{if not .Readiness.Startup.Done}}<tr><th>Startup</th><td>{{printf "%.1f" .Readiness.Startup.Percent}}% ({{.Readiness.Startup.AppliedLedgers}}/{{.Readiness.Startup.TotalLedgers}} ledgers)</td></tr>
+{{end}}<tr><th>Captive core</th><td>{{.CoreState}}</td></tr>
+<tr><th>Ingestion lag</th><td>{{if .LedgerRange.LastLedger.Sequence}}{{.IngestionLag}}{{else}}no ledgers were ingested yet{{end}}</td></tr>
+</table>
+<h2>Retention window</h2>
+{{if .LedgerRangeErr}}<p class="unready">could not obtain the ledger range: {{.LedgerRangeErr}}</p>
+{{else}}<table>
+<tr><th></th><th>Ledger</th><th>Close time</th></tr>
+<tr><th>Oldest</th><td>{{.LedgerRange.FirstLedger.Sequence}}</td><td>{{unix .LedgerRange.FirstLedger.CloseTime}}</td></tr>
+<tr><th>Latest</th><td>{{.LedgerRange.LastLedger.Sequence}}</td><td>{{unix .LedgerRange.LastLedger.CloseTime}}</td></tr>
+</table>
+{{end}}<h2>JSON RPC traffic</h2>
+<p>{{if .Window}}Over the last {{.Window}}{{else}}Since startup{{end}}: {{printf "%.0f" .Requests}} requests, {{printf "%.2f" .ErrorPercent}}% errors.</p>
+<table>
+<tr><th>Method</th><th>Requests/minute</th><th>Errors</th><th>Mean duration</th></tr>
+{{range .Methods}}<tr><td>{{.Method}}</td><td>{{printf "%.1f" .RequestsPerMinute}}</td><td>{{printf "%.2f" .ErrorPercent}}%</td><td>{{.MeanDuration}}</td></tr>
+{{else}}<tr><td colspan="4">no requests</td></tr>
+{{end}}</table>
+<p>Generated at {{.GeneratedAt.Format "2006-01-02T15:04:05Z07:00"}}. The metrics are served at <a href="/metrics">/metrics</a>.</p>
+</body>
+</html>
+`))
+
+// ServeHTTP renders the status page
+func (s *statusPage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := statusPageTemplate.Execute(w, s.data(r.Context(), time.Now())); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
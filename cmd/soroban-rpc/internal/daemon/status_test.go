@@ -0,0 +1,91 @@
+package daemon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+)
+
+func TestStatusPage(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	durations := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "request_duration_seconds",
+	}, []string{"endpoint", "status"})
+	registry.MustRegister(durations)
+	now := time.Now()
+	page := &statusPage{
+		readiness: func(context.Context) readinessState {
+			return readinessState{Status: readinessStatusReady, Startup: startupProgressState{Done: true}, CoreState: "Synced!"}
+		},
+		ledgerRange: func(context.Context) (ledgerbucketwindow.LedgerRange, error) {
+			return ledgerbucketwindow.LedgerRange{
+				FirstLedger: ledgerbucketwindow.LedgerInfo{Sequence: 10, CloseTime: now.Add(-time.Hour).Unix()},
+				LastLedger:  ledgerbucketwindow.LedgerInfo{Sequence: 730, CloseTime: now.Add(-6 * time.Second).Unix()},
+			}, nil
+		},
+		gatherer:              registry,
+		requestDurationMetric: "request_duration_seconds",
+	}
+
+	// requests before the oldest sample aren't counted
+	durations.WithLabelValues("getHealth", "ok").Observe(1)
+	page.sample(now.Add(-2 * time.Minute))
+	for range 3 {
+		durations.WithLabelValues("getEvents", "ok").Observe(0.5)
+	}
+	durations.WithLabelValues("getEvents", "invalid_parameters").Observe(0.1)
+
+	data := page.data(context.Background(), now)
+	assert.Equal(t, "Synced!", data.CoreState)
+	assert.Equal(t, 2*time.Minute, data.Window)
+	assert.InDelta(t, 6*time.Second, data.IngestionLag, float64(time.Second))
+	assert.Equal(t, uint32(10), data.LedgerRange.FirstLedger.Sequence)
+	assert.Equal(t, float64(4), data.Requests)
+	assert.Equal(t, float64(25), data.ErrorPercent)
+	require.Len(t, data.Methods, 2)
+	assert.Equal(t, methodStatus{
+		Method:            "getEvents",
+		RequestsPerMinute: 2,
+		ErrorPercent:      25,
+		MeanDuration:      400 * time.Millisecond,
+	}, data.Methods[0])
+	assert.Equal(t, methodStatus{Method: "getHealth"}, data.Methods[1])
+
+	res := httptest.NewRecorder()
+	page.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/status", nil))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "text/html; charset=utf-8", res.Header().Get("Content-Type"))
+	body := res.Body.String()
+	assert.Contains(t, body, "<td>getEvents</td>")
+	assert.Contains(t, body, "Synced!")
+	assert.Contains(t, body, "<td>730</td>")
+}
+
+func TestStatusPageWithoutSamples(t *testing.T) {
+	page := &statusPage{
+		readiness: func(context.Context) readinessState {
+			return readinessState{Status: readinessStatusInitializing, Startup: startupProgressState{TotalLedgers: 4}}
+		},
+		ledgerRange: func(context.Context) (ledgerbucketwindow.LedgerRange, error) {
+			return ledgerbucketwindow.LedgerRange{}, nil
+		},
+		gatherer:              prometheus.NewRegistry(),
+		requestDurationMetric: "request_duration_seconds",
+	}
+	res := httptest.NewRecorder()
+	page.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/status", nil))
+	require.Equal(t, http.StatusOK, res.Code)
+	body := res.Body.String()
+	assert.Contains(t, body, "unreachable")
+	assert.Contains(t, body, "no ledgers were ingested yet")
+	assert.Contains(t, body, "Since startup")
+	assert.Contains(t, body, "no requests")
+}
@@ -0,0 +1,153 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	supportlog "github.com/stellar/go/support/log"
+)
+
+const (
+	systemdStateReady    = "READY=1"
+	systemdStateStopping = "STOPPING=1"
+	systemdStateWatchdog = "WATCHDOG=1"
+
+	// how often the readiness is checked when the watchdog is disabled
+	systemdReadinessCheckPeriod = time.Second
+)
+
+// systemdNotifier sends notifications to the service manager through the
+// sd_notify(3) protocol, used when running as a Type=notify systemd service
+type systemdNotifier struct {
+	socketAddr *net.UnixAddr
+	// watchdogInterval is the watchdog timeout (WatchdogSec), 0 if the watchdog is disabled
+	watchdogInterval time.Duration
+}
+
+// newSystemdNotifier returns nil when the daemon wasn't started by systemd (i.e. NOTIFY_SOCKET isn't set)
+func newSystemdNotifier(getenv func(string) string) (*systemdNotifier, error) {
+	socket := getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil, nil
+	}
+	// Abstract socket addresses start with '@' (see unix(7))
+	if strings.HasPrefix(socket, "@") {
+		socket = "\x00" + socket[1:]
+	}
+	notifier := &systemdNotifier{socketAddr: &net.UnixAddr{Name: socket, Net: "unixgram"}}
+
+	if pid := getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		// the watchdog is meant for another process
+		return notifier, nil
+	}
+	if usec := getenv("WATCHDOG_USEC"); usec != "" {
+		value, err := strconv.ParseUint(usec, 10, 63)
+		if err != nil || value == 0 {
+			return nil, fmt.Errorf("invalid WATCHDOG_USEC value %q", usec)
+		}
+		notifier.watchdogInterval = time.Duration(value) * time.Microsecond
+	}
+	return notifier, nil
+}
+
+func (n *systemdNotifier) notify(states ...string) error {
+	conn, err := net.DialUnix(n.socketAddr.Net, nil, n.socketAddr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(strings.Join(states, "\n")))
+	return err
+}
+
+// systemdSupervisor tells systemd when the node is ready (i.e. the startup initialization
+// finished and captive core caught up with the network) and notifies the watchdog
+// while ingestion is progressing, so that systemd restarts a wedged node.
+type systemdSupervisor struct {
+	notifier     *systemdNotifier
+	readiness    func(ctx context.Context) readinessState
+	stallTimeout time.Duration
+	logger       *supportlog.Entry
+
+	ready bool
+	// progress increases whenever a ledger is applied (either during startup or ingestion)
+	progress         uint32
+	lastProgressTime time.Time
+	stalled          bool
+}
+
+func (s *systemdSupervisor) period() time.Duration {
+	if s.notifier.watchdogInterval > 0 {
+		// notify the watchdog twice per interval, as recommended by sd_watchdog_enabled(3)
+		return s.notifier.watchdogInterval / 2
+	}
+	return systemdReadinessCheckPeriod
+}
+
+func (s *systemdSupervisor) run(done <-chan struct{}) {
+	ticker := time.NewTicker(s.period())
+	defer ticker.Stop()
+	for {
+		s.check(context.Background(), time.Now())
+		if s.ready && s.notifier.watchdogInterval == 0 {
+			// nothing else to do
+			return
+		}
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *systemdSupervisor) check(ctx context.Context, now time.Time) {
+	state := s.readiness(ctx)
+	var states []string
+	if !s.ready {
+		if state.Status == readinessStatusReady {
+			s.ready = true
+			s.logger.WithField("latest_ledger", state.LatestLedger).Info("notifying systemd that the node is ready")
+			states = append(states, systemdStateReady, "STATUS=ready")
+		} else {
+			states = append(states, "STATUS="+state.Status+": "+state.Reason)
+		}
+	}
+
+	if s.notifier.watchdogInterval > 0 {
+		progress := state.Startup.AppliedLedgers + state.LatestLedger
+		if s.lastProgressTime.IsZero() || progress != s.progress {
+			s.progress = progress
+			s.lastProgressTime = now
+		}
+		if stalledFor := now.Sub(s.lastProgressTime); stalledFor < s.stallTimeout {
+			if s.stalled {
+				s.logger.Info("ingestion is progressing again, resuming systemd watchdog notifications")
+			}
+			s.stalled = false
+			states = append(states, systemdStateWatchdog)
+		} else if !s.stalled {
+			s.stalled = true
+			s.logger.WithField("stalled_for", stalledFor.String()).
+				Error("ingestion is stalled, suspending systemd watchdog notifications")
+		}
+	}
+
+	if len(states) == 0 {
+		return
+	}
+	if err := s.notifier.notify(states...); err != nil {
+		s.logger.WithError(err).Warn("could not notify systemd")
+	}
+}
+
+func (s *systemdSupervisor) stopping() {
+	if err := s.notifier.notify(systemdStateStopping); err != nil {
+		s.logger.WithError(err).Warn("could not notify systemd")
+	}
+}
@@ -0,0 +1,151 @@
+package daemon
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	supportlog "github.com/stellar/go/support/log"
+)
+
+func listenNotifySocket(t *testing.T) (string, *net.UnixConn) {
+	socket := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return socket, conn
+}
+
+func readNotification(t *testing.T, conn *net.UnixConn) string {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	return string(buf[:n])
+}
+
+func assertNoNotification(t *testing.T, conn *net.UnixConn) {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(10*time.Millisecond)))
+	_, err := conn.Read(make([]byte, 1024))
+	var netErr net.Error
+	require.ErrorAs(t, err, &netErr)
+	assert.True(t, netErr.Timeout())
+}
+
+func TestNewSystemdNotifier(t *testing.T) {
+	env := map[string]string{}
+	getenv := func(key string) string { return env[key] }
+
+	notifier, err := newSystemdNotifier(getenv)
+	require.NoError(t, err)
+	assert.Nil(t, notifier)
+
+	env["NOTIFY_SOCKET"] = "@notify"
+	notifier, err = newSystemdNotifier(getenv)
+	require.NoError(t, err)
+	assert.Equal(t, "\x00notify", notifier.socketAddr.Name)
+	assert.Zero(t, notifier.watchdogInterval)
+
+	env["WATCHDOG_USEC"] = "30000000"
+	notifier, err = newSystemdNotifier(getenv)
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, notifier.watchdogInterval)
+
+	// the watchdog is meant for another process
+	env["WATCHDOG_PID"] = strconv.Itoa(os.Getpid() + 1)
+	notifier, err = newSystemdNotifier(getenv)
+	require.NoError(t, err)
+	assert.Zero(t, notifier.watchdogInterval)
+
+	env["WATCHDOG_PID"] = strconv.Itoa(os.Getpid())
+	env["WATCHDOG_USEC"] = "invalid"
+	_, err = newSystemdNotifier(getenv)
+	require.ErrorContains(t, err, "invalid WATCHDOG_USEC")
+}
+
+func TestSystemdSupervisor(t *testing.T) {
+	socket, conn := listenNotifySocket(t)
+	state := readinessState{
+		Status:  readinessStatusInitializing,
+		Reason:  "replaying the ledgers stored in the database",
+		Startup: startupProgressState{AppliedLedgers: 1},
+	}
+	supervisor := &systemdSupervisor{
+		notifier: &systemdNotifier{
+			socketAddr:       &net.UnixAddr{Name: socket, Net: "unixgram"},
+			watchdogInterval: 10 * time.Second,
+		},
+		readiness:    func(context.Context) readinessState { return state },
+		stallTimeout: time.Minute,
+		logger:       supportlog.New(),
+	}
+	assert.Equal(t, 5*time.Second, supervisor.period())
+	ctx := context.Background()
+	now := time.Now()
+
+	supervisor.check(ctx, now)
+	assert.Equal(t, "STATUS=initializing: replaying the ledgers stored in the database\nWATCHDOG=1", readNotification(t, conn))
+
+	state = readinessState{
+		Status:       readinessStatusReady,
+		Startup:      startupProgressState{Done: true, AppliedLedgers: 1},
+		LatestLedger: 10,
+	}
+	now = now.Add(5 * time.Second)
+	supervisor.check(ctx, now)
+	assert.Equal(t, "READY=1\nSTATUS=ready\nWATCHDOG=1", readNotification(t, conn))
+
+	// the readiness is only notified once
+	now = now.Add(5 * time.Second)
+	supervisor.check(ctx, now)
+	assert.Equal(t, "WATCHDOG=1", readNotification(t, conn))
+
+	// no ledgers are ingested for longer than the stall timeout
+	now = now.Add(time.Minute)
+	supervisor.check(ctx, now)
+	assertNoNotification(t, conn)
+	assert.True(t, supervisor.stalled)
+
+	// ingestion resumes
+	state.LatestLedger++
+	now = now.Add(5 * time.Second)
+	supervisor.check(ctx, now)
+	assert.Equal(t, "WATCHDOG=1", readNotification(t, conn))
+	assert.False(t, supervisor.stalled)
+
+	supervisor.stopping()
+	assert.Equal(t, "STOPPING=1", readNotification(t, conn))
+}
+
+func TestSystemdSupervisorWithoutWatchdog(t *testing.T) {
+	socket, conn := listenNotifySocket(t)
+	supervisor := &systemdSupervisor{
+		notifier: &systemdNotifier{socketAddr: &net.UnixAddr{Name: socket, Net: "unixgram"}},
+		readiness: func(context.Context) readinessState {
+			return readinessState{Status: readinessStatusReady, Startup: startupProgressState{Done: true}}
+		},
+		stallTimeout: time.Minute,
+		logger:       supportlog.New(),
+	}
+	done := make(chan struct{})
+	defer close(done)
+	returned := make(chan struct{})
+	go func() {
+		supervisor.run(done)
+		close(returned)
+	}()
+	assert.Equal(t, "READY=1\nSTATUS=ready", readNotification(t, conn))
+	// there is nothing to do after notifying the readiness
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("the supervisor didn't return")
+	}
+}
@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/stellar/go/support/storage"
+)
+
+// Backup writes a consistent snapshot of the database to a new file, using
+// SQLite's online backup API (so that ingestion can carry on meanwhile).
+func (d *DB) Backup(ctx context.Context, targetPath string) error {
+	if _, err := os.Stat(targetPath); err == nil {
+		return fmt.Errorf("%s already exists", targetPath)
+	}
+	// write to a temporary file first, so that the target only exists once complete
+	partialPath := targetPath + ".partial"
+	if err := d.backup(ctx, partialPath); err != nil {
+		_ = os.Remove(partialPath)
+		return err
+	}
+	return os.Rename(partialPath, targetPath)
+}
+
+func (d *DB) backup(ctx context.Context, targetPath string) error {
+	target, err := sql.Open("sqlite3", targetPath)
+	if err != nil {
+		return err
+	}
+	defer target.Close()
+	targetConn, err := target.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer targetConn.Close()
+	sourceConn, err := d.sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer sourceConn.Close()
+
+	return targetConn.Raw(func(targetDriverConn interface{}) error {
+		return sourceConn.Raw(func(sourceDriverConn interface{}) error {
+			targetSQLiteConn, ok := targetDriverConn.(*sqlite3.SQLiteConn)
+			if !ok {
+				return errors.New("the backup target is not a SQLite database")
+			}
+			sourceSQLiteConn, ok := sourceDriverConn.(*sqlite3.SQLiteConn)
+			if !ok {
+				return errors.New("the database is not a SQLite database")
+			}
+			backup, err := targetSQLiteConn.Backup("main", sourceSQLiteConn, "main")
+			if err != nil {
+				return err
+			}
+			// Copy all the pages in a single step, so that the snapshot is consistent.
+			// (the backup would restart on every write if copied incrementally)
+			if _, err := backup.Step(-1); err != nil {
+				_ = backup.Close()
+				return err
+			}
+			return backup.Finish()
+		})
+	})
+}
+
+// BackupTo writes a snapshot of the database to the target, which is either
+// a file path or an object storage URL (e.g. s3://bucket/path/backup.sqlite
+// or gcs://bucket/path/backup.sqlite).
+func BackupTo(ctx context.Context, d *DB, target string, options storage.ConnectOptions) error {
+	parsed, err := url.Parse(target)
+	if err != nil || parsed.Scheme == "" || parsed.Scheme == "file" {
+		if err == nil && parsed.Scheme == "file" {
+			target = path.Join(parsed.Host, parsed.Path)
+		}
+		return d.Backup(ctx, target)
+	}
+
+	dir, name := path.Split(parsed.Path)
+	if name == "" {
+		return fmt.Errorf("the backup URL %s must include a file name", target)
+	}
+	parsed.Path = dir
+	options.Context = ctx
+	backend, err := storage.ConnectBackend(parsed.String(), options)
+	if err != nil {
+		return fmt.Errorf("could not connect to %s: %w", parsed.String(), err)
+	}
+	defer backend.Close()
+
+	tmpDir, err := os.MkdirTemp("", "soroban-rpc-backup-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	localPath := filepath.Join(tmpDir, name)
+	if err := d.Backup(ctx, localPath); err != nil {
+		return err
+	}
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	// PutFile closes the file
+	if err := backend.PutFile(name, file); err != nil {
+		return fmt.Errorf("could not upload the backup to %s: %w", target, err)
+	}
+	return nil
+}
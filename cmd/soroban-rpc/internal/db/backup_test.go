@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/storage"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func TestBackup(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.TODO()
+
+	writer := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 10, 1000, passphrase)
+	write, err := writer.NewTx(ctx)
+	require.NoError(t, err)
+	for sequence := uint32(1); sequence <= 20; sequence++ {
+		require.NoError(t, write.LedgerWriter().InsertLedger(createLedger(sequence)))
+	}
+	require.NoError(t, write.Commit(20))
+
+	dir := t.TempDir()
+	for _, target := range []string{
+		filepath.Join(dir, "backup.sqlite"),
+		"file://" + filepath.Join(dir, "url-backup.sqlite"),
+	} {
+		require.NoError(t, BackupTo(ctx, db, target, storage.ConnectOptions{}))
+	}
+
+	for _, name := range []string{"backup.sqlite", "url-backup.sqlite"} {
+		backup, err := OpenSQLiteDB(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assertLedgerRange(t, NewLedgerReader(backup), 1, 20)
+		latestLedger, err := NewLedgerEntryReader(backup).GetLatestLedgerSequence(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(20), latestLedger)
+		require.NoError(t, backup.Close())
+	}
+
+	err = db.Backup(ctx, filepath.Join(dir, "backup.sqlite"))
+	require.ErrorContains(t, err, "already exists")
+	assert.NoFileExists(t, filepath.Join(dir, "backup.sqlite.partial"))
+
+	err = BackupTo(ctx, db, "s3://bucket/", storage.ConnectOptions{})
+	require.EqualError(t, err, "the backup URL s3://bucket/ must include a file name")
+}
+
+func TestBackupReadOnly(t *testing.T) {
+	ctx := context.TODO()
+	dir := t.TempDir()
+	// a database of an older version
+	source, err := OpenSQLiteDBWithoutMigrations(filepath.Join(dir, "db.sqlite"))
+	require.NoError(t, err)
+	_, err = migrate.ExecMax(source.sqlDB, "sqlite3", schemaMigrationSource(), migrate.Up, 2)
+	require.NoError(t, err)
+	require.NoError(t, source.Close())
+
+	source, err = OpenSQLiteDBReadOnly(filepath.Join(dir, "db.sqlite"))
+	require.NoError(t, err)
+	defer source.Close()
+	require.NoError(t, source.Backup(ctx, filepath.Join(dir, "backup.sqlite")))
+	// neither the source nor the backup are migrated
+	require.ErrorIs(t, CheckSchema(ctx, source), ErrSchemaBehind)
+	backup, err := OpenSQLiteDBReadOnly(filepath.Join(dir, "backup.sqlite"))
+	require.NoError(t, err)
+	defer backup.Close()
+	err = CheckSchema(ctx, backup)
+	require.ErrorIs(t, err, ErrSchemaBehind)
+	assert.Equal(t, CheckSchema(ctx, source).Error(), err.Error())
+}
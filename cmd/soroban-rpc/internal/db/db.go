@@ -6,8 +6,10 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"os"
 	"strconv"
 	"sync"
+	"sync/atomic"
 
 	sq "github.com/Masterminds/squirrel"
 	_ "github.com/mattn/go-sqlite3"
@@ -18,6 +20,7 @@ import (
 	"github.com/stellar/go/support/log"
 	"github.com/stellar/go/xdr"
 
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
 )
 
@@ -41,6 +44,9 @@ type WriteTx interface {
 	LedgerEntryWriter() LedgerEntryWriter
 	LedgerWriter() LedgerWriter
 
+	// Trim removes the ledgers and transactions which fall out of the retention window.
+	// Commit trims too, unless Trim was already called for the committed ledger.
+	Trim(ledgerSeq uint32) error
 	Commit(ledgerSeq uint32) error
 	Rollback() error
 }
@@ -52,55 +58,207 @@ type dbCache struct {
 }
 
 type DB struct {
+	// the embedded session is the one of the writer (ingestion and migrations)
 	db.SessionInterface
-	cache *dbCache
+	// readers is the session of the readers, backed by their own pool of read-only connections
+	// for on-disk databases (see ReadPoolOptions) and by the writer session otherwise
+	readers db.SessionInterface
+	// readPool is the pool of read-only connections (nil for in-memory databases)
+	readPool *db.Session
+	cache    *dbCache
+	// sqlDB is the underlying connection pool, used to access the SQLite driver
+	sqlDB *sql.DB
+	// keepAlive is held by in-memory databases, which are discarded when their last connection closes
+	keepAlive *sql.Conn
 }
 
-func openSQLiteDB(dbFilePath string) (*db.Session, error) {
+// inMemoryDBCount makes the names of the in-memory databases unique
+var inMemoryDBCount atomic.Uint64
+
+func openSQLiteDB(dbFilePath string) (*db.Session, *sql.Conn, error) {
+	session, keepAlive, err := openSQLiteSession(dbFilePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = runSQLMigrations(session.DB.DB, "sqlite3"); err != nil {
+		err = errors.Join(err, closeSQLiteSession(session, keepAlive))
+		return nil, nil, fmt.Errorf("could not run SQL migrations: %w", err)
+	}
+	return session, keepAlive, nil
+}
+
+func openSQLiteSession(dbFilePath string) (*db.Session, *sql.Conn, error) {
+	if dbFilePath == config.InMemoryDBPath {
+		return openInMemorySQLiteSession()
+	}
 	// 1. Use Write-Ahead Logging (WAL).
 	// 2. Disable WAL auto-checkpointing (we will do the checkpointing ourselves with wal_checkpoint pragmas
 	//    after every write transaction).
 	// 3. Use synchronous=NORMAL, which is faster and still safe in WAL mode.
 	session, err := db.Open("sqlite3", fmt.Sprintf("file:%s?_journal_mode=WAL&_wal_autocheckpoint=0&_synchronous=NORMAL", dbFilePath))
 	if err != nil {
-		return nil, fmt.Errorf("open failed: %w", err)
+		return nil, nil, fmt.Errorf("open failed: %w", err)
 	}
+	return session, nil, nil
+}
 
-	if err = runSQLMigrations(session.DB.DB, "sqlite3"); err != nil {
+// openInMemorySQLiteSession opens a new database in memory, shared by the connections of the pool
+// through the memdb VFS (which, unlike the shared cache, keeps the usual locking between connections).
+func openInMemorySQLiteSession() (*db.Session, *sql.Conn, error) {
+	name := fmt.Sprintf("/soroban-rpc-%d", inMemoryDBCount.Add(1))
+	session, err := db.Open("sqlite3", fmt.Sprintf("file:%s?vfs=memdb&_synchronous=OFF", name))
+	if err != nil {
+		return nil, nil, fmt.Errorf("open failed: %w", err)
+	}
+	keepAlive, err := session.DB.DB.Conn(context.Background())
+	if err != nil {
 		_ = session.Close()
-		return nil, fmt.Errorf("could not run SQL migrations: %w", err)
+		return nil, nil, fmt.Errorf("open failed: %w", err)
+	}
+	return session, keepAlive, nil
+}
+
+func closeSQLiteSession(session *db.Session, keepAlive *sql.Conn) error {
+	var err error
+	if keepAlive != nil {
+		err = keepAlive.Close()
 	}
-	return session, nil
+	return errors.Join(err, session.Close())
 }
 
-func OpenSQLiteDBWithPrometheusMetrics(dbFilePath string, namespace string, sub db.Subservice, registry *prometheus.Registry) (*DB, error) {
-	session, err := openSQLiteDB(dbFilePath)
+// OpenSQLiteDBWithPrometheusMetrics opens the database like OpenSQLiteDB, with the given read pool
+// options, registering the metrics of the writer (and read pool) connections
+func OpenSQLiteDBWithPrometheusMetrics(
+	dbFilePath string,
+	namespace string,
+	sub db.Subservice,
+	registry *prometheus.Registry,
+	readPool ReadPoolOptions,
+) (*DB, error) {
+	session, keepAlive, err := openSQLiteDB(dbFilePath)
 	if err != nil {
 		return nil, err
 	}
-	result := DB{
-		SessionInterface: db.RegisterMetrics(session, namespace, sub, registry),
-		cache: &dbCache{
-			ledgerEntries: newTransactionalCache(),
-		},
-	}
-	return &result, nil
+	writer := newTracedSession(db.RegisterMetrics(session, namespace, sub, registry))
+	return newDB(dbFilePath, session, writer, keepAlive, readPool, func(readers *db.Session) db.SessionInterface {
+		return db.RegisterMetrics(readers, namespace, sub+"_readers", registry)
+	})
 }
 
+// OpenSQLiteDB opens the database (applying the pending schema migrations),
+// config.InMemoryDBPath opens a new in-memory database
 func OpenSQLiteDB(dbFilePath string) (*DB, error) {
-	session, err := openSQLiteDB(dbFilePath)
+	session, keepAlive, err := openSQLiteDB(dbFilePath)
+	if err != nil {
+		return nil, err
+	}
+	return newDB(dbFilePath, session, newTracedSession(session), keepAlive, DefaultReadPoolOptions, nil)
+}
+
+// OpenSQLiteDBWithoutMigrations opens the database without applying the pending
+// schema migrations (see MigrateUp and MigrateDown)
+func OpenSQLiteDBWithoutMigrations(dbFilePath string) (*DB, error) {
+	session, keepAlive, err := openSQLiteSession(dbFilePath)
 	if err != nil {
 		return nil, err
 	}
+	return newDB(dbFilePath, session, newTracedSession(session), keepAlive, DefaultReadPoolOptions, nil)
+}
+
+// OpenSQLiteDBReadOnly opens an existing on-disk database in read-only mode, without applying the
+// pending schema migrations (see CheckSchema). It is meant for inspecting a database which may be
+// in use by a daemon (possibly of another version), which must be left untouched.
+func OpenSQLiteDBReadOnly(dbFilePath string) (*DB, error) {
+	if dbFilePath == config.InMemoryDBPath {
+		return nil, errors.New("in-memory databases cannot be opened read-only")
+	}
+	// opening a missing file would create an (empty) database
+	if _, err := os.Stat(dbFilePath); err != nil {
+		return nil, fmt.Errorf("open failed: %w", err)
+	}
+	session, err := db.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro", dbFilePath))
+	if err != nil {
+		return nil, fmt.Errorf("open failed: %w", err)
+	}
+	return newDB(dbFilePath, session, newTracedSession(session), nil, DefaultReadPoolOptions, nil)
+}
+
+// newDB builds the database from the session of the writer, opening the read pool of
+// on-disk databases (which can be decorated with decorateReaders, when not nil)
+func newDB(
+	dbFilePath string,
+	session *db.Session,
+	writer db.SessionInterface,
+	keepAlive *sql.Conn,
+	readPool ReadPoolOptions,
+	decorateReaders func(*db.Session) db.SessionInterface,
+) (*DB, error) {
 	result := DB{
-		SessionInterface: session,
+		SessionInterface: writer,
+		// in-memory databases are read through the connections of the writer
+		readers: writer,
 		cache: &dbCache{
 			ledgerEntries: newTransactionalCache(),
 		},
+		sqlDB:     session.DB.DB,
+		keepAlive: keepAlive,
+	}
+	if dbFilePath == config.InMemoryDBPath {
+		return &result, nil
 	}
+	readers, err := openSQLiteReadPool(dbFilePath, readPool)
+	if err != nil {
+		return nil, errors.Join(err, closeSQLiteSession(session, keepAlive))
+	}
+	result.readPool = readers
+	var decorated db.SessionInterface = readers
+	if decorateReaders != nil {
+		decorated = decorateReaders(readers)
+	}
+	result.readers = newTracedSession(newBusyRetrySession(decorated))
 	return &result, nil
 }
 
+// Readers returns the session to use for reading from the database (see ReadPoolOptions)
+func (d *DB) Readers() db.SessionInterface {
+	return d.readers
+}
+
+// Close closes the database (discarding it when it's in memory)
+func (d *DB) Close() error {
+	var err error
+	if d.readPool != nil {
+		// the readers are closed first, so that the writer can remove the write-ahead log
+		err = d.readPool.Close()
+	}
+	if d.keepAlive != nil {
+		err = errors.Join(err, d.keepAlive.Close())
+	}
+	return errors.Join(err, d.SessionInterface.Close())
+}
+
+// ReloadCache brings the cache up to date after another process (sharing the database) committed
+// the given ledger. The cache is otherwise only updated by the commits of this process, the cached
+// ledger entries are dropped since they may have been modified.
+func (d *DB) ReloadCache(latestLedgerSeq uint32) {
+	d.cache.Lock()
+	defer d.cache.Unlock()
+	d.cache.latestLedgerSeq = latestLedgerSeq
+	d.cache.ledgerEntries = newTransactionalCache()
+}
+
+// Size returns the size (in bytes) of the database, excluding its write-ahead log
+func (d *DB) Size(ctx context.Context) (uint64, error) {
+	var pageCount, pageSize uint64
+	if err := d.GetRaw(ctx, &pageCount, "PRAGMA page_count"); err != nil {
+		return 0, err
+	}
+	if err := d.GetRaw(ctx, &pageSize, "PRAGMA page_size"); err != nil {
+		return 0, err
+	}
+	return pageCount * pageSize, nil
+}
+
 func getMetaBool(ctx context.Context, q db.SessionInterface, key string) (bool, error) {
 	valueStr, err := getMetaValue(ctx, q, key)
 	if err != nil {
@@ -116,6 +274,27 @@ func setMetaBool(ctx context.Context, q db.SessionInterface, key string, value b
 	return err
 }
 
+func getMetaUint32(ctx context.Context, q db.SessionInterface, key string) (uint32, error) {
+	valueStr, err := getMetaValue(ctx, q, key)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseUint(valueStr, 10, 32)
+	return uint32(value), err
+}
+
+func setMetaValue(ctx context.Context, q db.SessionInterface, key string, value string) error {
+	query := sq.Replace(metaTableName).
+		Values(key, value)
+	_, err := q.Exec(ctx, query)
+	return err
+}
+
+func deleteMetaValue(ctx context.Context, q db.SessionInterface, key string) error {
+	_, err := q.Exec(ctx, sq.Delete(metaTableName).Where(sq.Eq{"key": key}))
+	return err
+}
+
 func getMetaValue(ctx context.Context, q db.SessionInterface, key string) (string, error) {
 	sql := sq.Select("value").From(metaTableName).Where(sq.Eq{"key": key})
 	var results []string
@@ -236,11 +415,13 @@ func (rw *readWriter) NewTx(ctx context.Context) (WriteTx, error) {
 		tx:                    txSession,
 		stmtCache:             stmtCache,
 		ledgerRetentionWindow: rw.ledgerRetentionWindow,
+		trimmedLedgerSeq:      new(uint32),
 		ledgerWriter:          ledgerWriter{stmtCache: stmtCache},
 		ledgerEntryWriter: ledgerEntryWriter{
 			stmtCache:               stmtCache,
 			buffer:                  xdr.NewEncodingBuffer(),
 			keyToEntryBatch:         make(map[string]*xdr.LedgerEntry, rw.maxBatchSize),
+			archivedEntryBatch:      make(map[string]xdr.LedgerEntry),
 			ledgerEntryCacheWriteTx: db.cache.ledgerEntries.newWriteTx(rw.maxBatchSize),
 			maxBatchSize:            rw.maxBatchSize,
 		},
@@ -267,6 +448,7 @@ type writeTx struct {
 	ledgerWriter          ledgerWriter
 	txWriter              transactionHandler
 	ledgerRetentionWindow uint32
+	trimmedLedgerSeq      *uint32
 }
 
 func (w writeTx) LedgerEntryWriter() LedgerEntryWriter {
@@ -281,17 +463,27 @@ func (w writeTx) TransactionWriter() TransactionWriter {
 	return &w.txWriter
 }
 
-func (w writeTx) Commit(ledgerSeq uint32) error {
-	if err := w.ledgerEntryWriter.flush(); err != nil {
-		return err
-	}
-
+func (w writeTx) Trim(ledgerSeq uint32) error {
 	if err := w.ledgerWriter.trimLedgers(ledgerSeq, w.ledgerRetentionWindow); err != nil {
 		return err
 	}
 	if err := w.txWriter.trimTransactions(ledgerSeq, w.ledgerRetentionWindow); err != nil {
 		return err
 	}
+	*w.trimmedLedgerSeq = ledgerSeq
+	return nil
+}
+
+func (w writeTx) Commit(ledgerSeq uint32) error {
+	if err := w.ledgerEntryWriter.flush(); err != nil {
+		return err
+	}
+
+	if *w.trimmedLedgerSeq != ledgerSeq {
+		if err := w.Trim(ledgerSeq); err != nil {
+			return err
+		}
+	}
 
 	_, err := sq.Replace(metaTableName).
 		Values(latestLedgerSequenceMetaKey, strconv.FormatUint(uint64(ledgerSeq), 10)).
@@ -333,7 +525,12 @@ func (w writeTx) Rollback() error {
 }
 
 func runSQLMigrations(db *sql.DB, dialect string) error {
-	m := &migrate.AssetMigrationSource{
+	_, err := migrate.ExecMax(db, dialect, schemaMigrationSource(), migrate.Up, 0)
+	return err
+}
+
+func schemaMigrationSource() migrate.MigrationSource {
+	return &migrate.AssetMigrationSource{
 		Asset: sqlMigrations.ReadFile,
 		AssetDir: func() func(string) ([]string, error) {
 			return func(path string) ([]string, error) {
@@ -351,6 +548,4 @@ func runSQLMigrations(db *sql.DB, dialect string) error {
 		}(),
 		Dir: "sqlmigrations",
 	}
-	_, err := migrate.ExecMax(db, dialect, m, migrate.Up, 0)
-	return err
 }
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func TestInMemoryDB(t *testing.T) {
+	ctx := context.TODO()
+	db, err := OpenSQLiteDB(config.InMemoryDBPath)
+	require.NoError(t, err)
+	other, err := OpenSQLiteDB(config.InMemoryDBPath)
+	require.NoError(t, err)
+	defer other.Close()
+
+	writer := NewReadWriter(log.DefaultLogger, db, interfaces.MakeNoOpDeamon(), 10, 10, passphrase)
+	write, err := writer.NewTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, write.LedgerWriter().InsertLedger(txMeta(1, true)))
+	// reads (from other connections) wait for the write transaction to finish
+	readDone := make(chan error)
+	go func() {
+		_, err := NewLedgerReader(db).GetLedgerRange(ctx)
+		readDone <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, write.Commit(101))
+	require.NoError(t, <-readDone)
+
+	ledgerRange, err := NewLedgerReader(db).GetLedgerRange(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(101), ledgerRange.LastLedger.Sequence)
+
+	// the in-memory databases are independent, and discarded when closed
+	_, err = NewLedgerEntryReader(other).GetLatestLedgerSequence(ctx)
+	require.ErrorIs(t, err, ErrEmptyDB)
+	snapshotPath := path.Join(t.TempDir(), "snapshot.sqlite")
+	require.NoError(t, db.Backup(ctx, snapshotPath))
+	require.NoError(t, db.Close())
+
+	snapshot, err := OpenSQLiteDB(snapshotPath)
+	require.NoError(t, err)
+	defer snapshot.Close()
+	ledgerRange, err = NewLedgerReader(snapshot).GetLedgerRange(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(101), ledgerRange.LastLedger.Sequence)
+}
+
+func TestOpenSQLiteDBReadOnly(t *testing.T) {
+	ctx := context.TODO()
+	dbPath := path.Join(t.TempDir(), "db.sqlite")
+	// a missing database isn't created
+	_, err := OpenSQLiteDBReadOnly(dbPath)
+	require.ErrorIs(t, err, os.ErrNotExist)
+	_, err = os.Stat(dbPath)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	// a database of an older version isn't migrated
+	db, err := OpenSQLiteDBWithoutMigrations(dbPath)
+	require.NoError(t, err)
+	_, err = migrate.ExecMax(db.sqlDB, "sqlite3", schemaMigrationSource(), migrate.Up, 2)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	db, err = OpenSQLiteDBReadOnly(dbPath)
+	require.NoError(t, err)
+	defer db.Close()
+	require.ErrorIs(t, CheckSchema(ctx, db), ErrSchemaBehind)
+	_, err = db.ExecRaw(ctx, "DELETE FROM metadata")
+	require.ErrorContains(t, err, "readonly database")
+}
@@ -6,7 +6,10 @@ import (
 
 	sq "github.com/Masterminds/squirrel"
 
+	"github.com/stellar/go/support/db"
 	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
 )
 
 const (
@@ -18,6 +21,8 @@ type StreamLedgerFn func(xdr.LedgerCloseMeta) error
 type LedgerReader interface {
 	GetLedger(ctx context.Context, sequence uint32) (xdr.LedgerCloseMeta, bool, error)
 	StreamAllLedgers(ctx context.Context, f StreamLedgerFn) error
+	StreamLedgerRange(ctx context.Context, start, end uint32, f StreamLedgerFn) error
+	GetLedgerRange(ctx context.Context) (ledgerbucketwindow.LedgerRange, error)
 }
 
 type LedgerWriter interface {
@@ -35,7 +40,31 @@ func NewLedgerReader(db *DB) LedgerReader {
 // StreamAllLedgers runs f over all the ledgers in the database (until f errors or signals it's done).
 func (r ledgerReader) StreamAllLedgers(ctx context.Context, f StreamLedgerFn) error {
 	sql := sq.Select("meta").From(ledgerCloseMetaTableName).OrderBy("sequence asc")
-	q, err := r.db.Query(ctx, sql)
+	q, err := r.db.readers.Query(ctx, sql)
+	if err != nil {
+		return err
+	}
+	defer q.Close()
+	for q.Next() {
+		var closeMeta xdr.LedgerCloseMeta
+		if err = q.Scan(&closeMeta); err != nil {
+			return err
+		}
+		if err = f(closeMeta); err != nil {
+			return err
+		}
+	}
+	return q.Err()
+}
+
+// StreamLedgerRange runs f over the ledgers of the given range (both ends included)
+// stored in the database (until f errors or signals it's done).
+func (r ledgerReader) StreamLedgerRange(ctx context.Context, start, end uint32, f StreamLedgerFn) error {
+	sql := sq.Select("meta").
+		From(ledgerCloseMetaTableName).
+		Where(sq.And{sq.GtOrEq{"sequence": start}, sq.LtOrEq{"sequence": end}}).
+		OrderBy("sequence asc")
+	q, err := r.db.readers.Query(ctx, sql)
 	if err != nil {
 		return err
 	}
@@ -56,7 +85,7 @@ func (r ledgerReader) StreamAllLedgers(ctx context.Context, f StreamLedgerFn) er
 func (r ledgerReader) GetLedger(ctx context.Context, sequence uint32) (xdr.LedgerCloseMeta, bool, error) {
 	sql := sq.Select("meta").From(ledgerCloseMetaTableName).Where(sq.Eq{"sequence": sequence})
 	var results []xdr.LedgerCloseMeta
-	if err := r.db.Select(ctx, &results, sql); err != nil {
+	if err := r.db.readers.Select(ctx, &results, sql); err != nil {
 		return xdr.LedgerCloseMeta{}, false, err
 	}
 	switch len(results) {
@@ -69,6 +98,64 @@ func (r ledgerReader) GetLedger(ctx context.Context, sequence uint32) (xdr.Ledge
 	}
 }
 
+// GetLedgerRange pulls the min/max ledger sequence numbers from the database.
+func (r ledgerReader) GetLedgerRange(ctx context.Context) (ledgerbucketwindow.LedgerRange, error) {
+	return getLedgerRange(ctx, r.db.readers)
+}
+
+// getLedgerRange pulls the min/max ledger sequence numbers (and their close
+// times) from the ledger_close_meta table.
+func getLedgerRange(ctx context.Context, session db.SessionInterface) (ledgerbucketwindow.LedgerRange, error) {
+	var ledgerRange ledgerbucketwindow.LedgerRange
+
+	//
+	// We use subqueries alongside a UNION ALL stitch in order to select the min
+	// and max from the ledger table in a single query and get around sqlite's
+	// limitations with parentheses (see https://stackoverflow.com/a/22609948).
+	//
+	newestQ := sq.
+		Select("m1.meta").
+		FromSelect(
+			sq.
+				Select("meta").
+				From(ledgerCloseMetaTableName).
+				OrderBy("sequence ASC").
+				Limit(1),
+			"m1",
+		)
+	sql, args, err := sq.
+		Select("m2.meta").
+		FromSelect(
+			sq.
+				Select("meta").
+				From(ledgerCloseMetaTableName).
+				OrderBy("sequence DESC").
+				Limit(1),
+			"m2",
+		).ToSql()
+	if err != nil {
+		return ledgerRange, fmt.Errorf("couldn't build ledger range query: %w", err)
+	}
+
+	var lcms []xdr.LedgerCloseMeta
+	if err = session.Select(ctx, &lcms, newestQ.Suffix("UNION ALL "+sql, args...)); err != nil {
+		return ledgerRange, fmt.Errorf("couldn't query ledger range: %w", err)
+	} else if len(lcms) < 2 {
+		// There is almost certainly a row, but we want to avoid a race condition
+		// with ingestion as well as support test cases from an empty DB, so we need
+		// to sanity check that there is in fact a result. Note that no ledgers in
+		// the database isn't an error, it's just an empty range.
+		return ledgerRange, nil
+	}
+
+	lcm1, lcm2 := lcms[0], lcms[1]
+	ledgerRange.FirstLedger.Sequence = lcm1.LedgerSequence()
+	ledgerRange.FirstLedger.CloseTime = lcm1.LedgerCloseTime()
+	ledgerRange.LastLedger.Sequence = lcm2.LedgerSequence()
+	ledgerRange.LastLedger.CloseTime = lcm2.LedgerCloseTime()
+	return ledgerRange, nil
+}
+
 type ledgerWriter struct {
 	stmtCache *sq.StmtCache
 }
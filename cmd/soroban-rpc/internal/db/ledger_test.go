@@ -104,6 +104,43 @@ func TestLedgers(t *testing.T) {
 	assert.NoError(t, tx.Commit(ledgerSequence))
 
 	assertLedgerRange(t, reader, 8, 12)
+
+	var streamed []uint32
+	err = reader.StreamLedgerRange(context.Background(), 5, 10, func(lcm xdr.LedgerCloseMeta) error {
+		streamed = append(streamed, lcm.LedgerSequence())
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{8, 9, 10}, streamed)
+
+	// trimming explicitly before committing
+	ledgerSequence = uint32(13)
+	tx, err = NewReadWriter(logger, db, daemon, 150, 3, passphrase).NewTx(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, tx.LedgerWriter().InsertLedger(createLedger(ledgerSequence)))
+	assert.NoError(t, tx.Trim(ledgerSequence))
+	assert.NoError(t, tx.Commit(ledgerSequence))
+
+	assertLedgerRange(t, reader, 11, 13)
+}
+
+func TestDBSize(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.Background()
+	initialSize, err := db.Size(ctx)
+	require.NoError(t, err)
+	assert.Positive(t, initialSize)
+
+	tx, err := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 150, 1000, passphrase).NewTx(ctx)
+	require.NoError(t, err)
+	for ledgerSequence := uint32(1); ledgerSequence <= 100; ledgerSequence++ {
+		require.NoError(t, tx.LedgerWriter().InsertLedger(createLedger(ledgerSequence)))
+	}
+	require.NoError(t, tx.Commit(100))
+
+	size, err := db.Size(ctx)
+	require.NoError(t, err)
+	assert.Greater(t, size, initialSize)
 }
 
 func NewTestDB(tb testing.TB) *DB {
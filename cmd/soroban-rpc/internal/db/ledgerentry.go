@@ -14,7 +14,8 @@ import (
 )
 
 const (
-	ledgerEntriesTableName = "ledger_entries"
+	ledgerEntriesTableName         = "ledger_entries"
+	archivedLedgerEntriesTableName = "archived_ledger_entries"
 )
 
 type LedgerEntryReader interface {
@@ -32,12 +33,20 @@ type LedgerKeyAndEntry struct {
 type LedgerEntryReadTx interface {
 	GetLatestLedgerSequence() (uint32, error)
 	GetLedgerEntries(keys ...xdr.LedgerKey) ([]LedgerKeyAndEntry, error)
+	// GetContractDataEntries returns up to limit contract data entries of a contract, in storage order,
+	// starting after the cursor key (or from the first entry if the cursor is nil)
+	GetContractDataEntries(contract xdr.ScAddress, cursor *xdr.LedgerKey, limit uint) ([]LedgerKeyAndEntry, error)
+	// GetArchivedLedgerEntries returns the persistent entries which have been evicted into the hot archive
+	GetArchivedLedgerEntries(keys ...xdr.LedgerKey) ([]LedgerKeyAndEntry, error)
 	Done() error
 }
 
 type LedgerEntryWriter interface {
 	UpsertLedgerEntry(entry xdr.LedgerEntry) error
 	DeleteLedgerEntry(key xdr.LedgerKey) error
+	// ArchiveLedgerEntry stores a persistent entry evicted into the hot archive, which is removed
+	// from the archived entries once it is upserted again (i.e. restored)
+	ArchiveLedgerEntry(entry xdr.LedgerEntry) error
 }
 
 type ledgerEntryWriter struct {
@@ -45,6 +54,7 @@ type ledgerEntryWriter struct {
 	buffer    *xdr.EncodingBuffer
 	// nil entries imply deletion
 	keyToEntryBatch         map[string]*xdr.LedgerEntry
+	archivedEntryBatch      map[string]xdr.LedgerEntry
 	ledgerEntryCacheWriteTx transactionalCacheWriteTx
 	maxBatchSize            int
 }
@@ -75,17 +85,54 @@ func (l ledgerEntryWriter) DeleteLedgerEntry(key xdr.LedgerKey) error {
 	return l.maybeFlush()
 }
 
+func (l ledgerEntryWriter) ArchiveLedgerEntry(entry xdr.LedgerEntry) error {
+	key, err := entry.LedgerKey()
+	if err != nil {
+		return fmt.Errorf("could not get ledger key from entry: %w", err)
+	}
+	encodedKey, err := encodeLedgerKey(l.buffer, key)
+	if err != nil {
+		return err
+	}
+	l.archivedEntryBatch[encodedKey] = entry
+	return l.maybeFlush()
+}
+
 func (l ledgerEntryWriter) maybeFlush() error {
-	if len(l.keyToEntryBatch) >= l.maxBatchSize {
+	if len(l.keyToEntryBatch)+len(l.archivedEntryBatch) >= l.maxBatchSize {
 		return l.flush()
 	}
 	return nil
 }
 
+// flushArchivedEntries stores the archived entries of the batch, which must happen before
+// flushing any restoration
+func (l ledgerEntryWriter) flushArchivedEntries() error {
+	if len(l.archivedEntryBatch) == 0 {
+		return nil
+	}
+	insertSQL := sq.StatementBuilder.RunWith(l.stmtCache).Replace(archivedLedgerEntriesTableName)
+	for key, entry := range l.archivedEntryBatch {
+		encodedEntry, err := l.buffer.UnsafeMarshalBinary(&entry)
+		if err != nil {
+			return err
+		}
+		insertSQL = insertSQL.Values(key, string(encodedEntry))
+		delete(l.archivedEntryBatch, key)
+	}
+	_, err := insertSQL.Exec()
+	return err
+}
+
 func (l ledgerEntryWriter) flush() error {
+	if err := l.flushArchivedEntries(); err != nil {
+		return err
+	}
 	upsertCount := 0
 	upsertSQL := sq.StatementBuilder.RunWith(l.stmtCache).Replace(ledgerEntriesTableName)
 	deleteKeys := make([]string, 0, len(l.keyToEntryBatch))
+	// the upserted entries which may have been restored from the hot archive
+	restoredKeys := make([]string, 0, len(l.keyToEntryBatch))
 
 	upsertCacheUpdates := make(map[string]*string, len(l.keyToEntryBatch))
 	for key, entry := range l.keyToEntryBatch {
@@ -98,6 +145,9 @@ func (l ledgerEntryWriter) flush() error {
 			encodedEntryStr := string(encodedEntry)
 			upsertSQL = upsertSQL.Values(key, encodedEntryStr)
 			upsertCount += 1
+			if entry.Data.Type == xdr.LedgerEntryTypeContractData || entry.Data.Type == xdr.LedgerEntryTypeContractCode {
+				restoredKeys = append(restoredKeys, key)
+			}
 			// Only cache Config entries for now
 			if entry.Data.Type == xdr.LedgerEntryTypeConfigSetting {
 				upsertCacheUpdates[key] = &encodedEntryStr
@@ -122,6 +172,13 @@ func (l ledgerEntryWriter) flush() error {
 		}
 	}
 
+	if len(restoredKeys) > 0 {
+		restoreSQL := sq.StatementBuilder.RunWith(l.stmtCache).Delete(archivedLedgerEntriesTableName).Where(sq.Eq{"key": restoredKeys})
+		if _, err := restoreSQL.Exec(); err != nil {
+			return err
+		}
+	}
+
 	if len(deleteKeys) > 0 {
 		deleteSQL := sq.StatementBuilder.RunWith(l.stmtCache).Delete(ledgerEntriesTableName).Where(sq.Eq{"key": deleteKeys})
 		if _, err := deleteSQL.Exec(); err != nil {
@@ -326,6 +383,164 @@ func (l *ledgerEntryReadTx) GetLedgerEntries(keys ...xdr.LedgerKey) ([]LedgerKey
 	return result, nil
 }
 
+// contractDataKeyRange returns the range [start, end) of the (compressed) keys of the contract data
+// entries of a contract, which share the same prefix: the entry type followed by the contract address.
+func contractDataKeyRange(contract xdr.ScAddress) (string, string, error) {
+	contractBytes, err := contract.MarshalBinary()
+	if err != nil {
+		return "", "", err
+	}
+	start := append([]byte{byte(xdr.LedgerEntryTypeContractData)}, contractBytes...)
+	end := append([]byte{}, start...)
+	// increment the prefix to obtain the first key following all the keys sharing it
+	// (the address type discriminant ensures that not all the bytes can be 0xff)
+	for i := len(end) - 1; i >= 0; i-- {
+		end[i]++
+		if end[i] != 0 {
+			break
+		}
+	}
+	return string(start), string(end), nil
+}
+
+func (l *ledgerEntryReadTx) GetContractDataEntries(contract xdr.ScAddress, cursor *xdr.LedgerKey, limit uint) ([]LedgerKeyAndEntry, error) {
+	start, end, err := contractDataKeyRange(contract)
+	if err != nil {
+		return nil, err
+	}
+	// The keys are compared bytewise, so the entries of the contract can be
+	// obtained through a range scan of the primary key index
+	var startCondition sq.Sqlizer = sq.GtOrEq{"key": start}
+	if cursor != nil {
+		encodedCursor, err := encodeLedgerKey(l.buffer, *cursor)
+		if err != nil {
+			return nil, err
+		}
+		startCondition = sq.Gt{"key": encodedCursor}
+	}
+	builder := sq.StatementBuilder
+	if l.stmtCache != nil {
+		builder = builder.RunWith(l.stmtCache)
+	} else {
+		builder = builder.RunWith(l.tx.GetTx())
+	}
+	sql := builder.Select("entry").From(ledgerEntriesTableName).
+		Where(sq.And{startCondition, sq.Lt{"key": end}}).
+		OrderBy("key").
+		Limit(uint64(limit))
+	q, err := sql.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer q.Close()
+	var entries []xdr.LedgerEntry
+	for q.Next() {
+		var encodedEntry string
+		if err = q.Scan(&encodedEntry); err != nil {
+			return nil, err
+		}
+		var entry xdr.LedgerEntry
+		if err := xdr.SafeUnmarshal([]byte(encodedEntry), &entry); err != nil {
+			return nil, fmt.Errorf("cannot decode ledger entry from DB: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := q.Err(); err != nil {
+		return nil, err
+	}
+
+	keys := make([]xdr.LedgerKey, 0, len(entries))
+	encodedTTLKeys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		key, err := entry.LedgerKey()
+		if err != nil {
+			return nil, err
+		}
+		ttlKey, err := entryKeyToTTLEntryKey(key)
+		if err != nil {
+			return nil, err
+		}
+		encodedTTLKey, err := encodeLedgerKey(l.buffer, ttlKey)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		encodedTTLKeys = append(encodedTTLKeys, encodedTTLKey)
+	}
+	rawTTLEntries, err := l.getRawLedgerEntries(encodedTTLKeys...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]LedgerKeyAndEntry, 0, len(entries))
+	for i, entry := range entries {
+		encodedTTLEntry, ok := rawTTLEntries[encodedTTLKeys[i]]
+		if !ok {
+			// missing ttl key. This should not happen.
+			return nil, errors.New("missing ttl key entry")
+		}
+		var ttlEntry xdr.LedgerEntry
+		if err := xdr.SafeUnmarshal([]byte(encodedTTLEntry), &ttlEntry); err != nil {
+			return nil, fmt.Errorf("cannot decode TTL ledger entry from DB: %w", err)
+		}
+		liveUntilSeq := uint32(ttlEntry.Data.Ttl.LiveUntilLedgerSeq)
+		result = append(result, LedgerKeyAndEntry{keys[i], entry, &liveUntilSeq})
+	}
+	return result, nil
+}
+
+func (l *ledgerEntryReadTx) GetArchivedLedgerEntries(keys ...xdr.LedgerKey) ([]LedgerKeyAndEntry, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	encodedKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		encodedKey, err := encodeLedgerKey(l.buffer, key)
+		if err != nil {
+			return nil, err
+		}
+		encodedKeys = append(encodedKeys, encodedKey)
+	}
+	builder := sq.StatementBuilder
+	if l.stmtCache != nil {
+		builder = builder.RunWith(l.stmtCache)
+	} else {
+		builder = builder.RunWith(l.tx.GetTx())
+	}
+	sql := builder.Select("key", "entry").From(archivedLedgerEntriesTableName).Where(sq.Eq{"key": encodedKeys})
+	q, err := sql.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer q.Close()
+	encodedEntries := make(map[string]string, len(keys))
+	for q.Next() {
+		var key, entry string
+		if err = q.Scan(&key, &entry); err != nil {
+			return nil, err
+		}
+		encodedEntries[key] = entry
+	}
+	if err := q.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]LedgerKeyAndEntry, 0, len(encodedEntries))
+	for i, key := range keys {
+		encodedEntry, ok := encodedEntries[encodedKeys[i]]
+		if !ok {
+			continue
+		}
+		var entry xdr.LedgerEntry
+		if err := xdr.SafeUnmarshal([]byte(encodedEntry), &entry); err != nil {
+			return nil, fmt.Errorf("cannot decode archived ledger entry from DB: %w", err)
+		}
+		// archived entries have no TTL
+		result = append(result, LedgerKeyAndEntry{key, entry, nil})
+	}
+	return result, nil
+}
+
 func (l ledgerEntryReadTx) Done() error {
 	// Since it's a read-only transaction, we don't
 	// care whether we commit it or roll it back as long as we close it
@@ -341,12 +556,12 @@ func NewLedgerEntryReader(db *DB) LedgerEntryReader {
 }
 
 func (r ledgerEntryReader) GetLatestLedgerSequence(ctx context.Context) (uint32, error) {
-	return getLatestLedgerSequence(ctx, r.db, r.db.cache)
+	return getLatestLedgerSequence(ctx, r.db.readers, r.db.cache)
 }
 
 // NewCachedTx() caches all accessed ledger entries and select statements. If many ledger entries are accessed, it will grow without bounds.
 func (r ledgerEntryReader) NewCachedTx(ctx context.Context) (LedgerEntryReadTx, error) {
-	txSession := r.db.Clone()
+	txSession := r.db.readers.Clone()
 	// We need to copy the cached ledger entries locally when we start the transaction
 	// since otherwise we would break the consistency between the transaction and the cache.
 
@@ -370,7 +585,7 @@ func (r ledgerEntryReader) NewCachedTx(ctx context.Context) (LedgerEntryReadTx,
 }
 
 func (r ledgerEntryReader) NewTx(ctx context.Context) (LedgerEntryReadTx, error) {
-	txSession := r.db.Clone()
+	txSession := r.db.readers.Clone()
 	if err := txSession.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
 		return nil, err
 	}
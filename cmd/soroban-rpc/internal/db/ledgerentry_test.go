@@ -209,6 +209,118 @@ func getTTLLedgerEntry(key xdr.LedgerKey) xdr.LedgerEntry {
 	return expLegerEntry
 }
 
+func TestGetContractDataEntries(t *testing.T) {
+	db := NewTestDB(t)
+	tx, err := makeReadWriter(db, 150, 15).NewTx(context.Background())
+	require.NoError(t, err)
+	writer := tx.LedgerEntryWriter()
+
+	contract := xdr.ScAddress{
+		Type:       xdr.ScAddressTypeScAddressTypeContract,
+		ContractId: &xdr.Hash{0xca, 0xfe},
+	}
+	// a contract whose address immediately follows the one above
+	nextContract := xdr.ScAddress{
+		Type:       xdr.ScAddressTypeScAddressTypeContract,
+		ContractId: &xdr.Hash{0xca, 0xfe, 0x01},
+	}
+	var keys []xdr.LedgerKey
+	for i, address := range []xdr.ScAddress{contract, contract, contract, nextContract} {
+		value := xdr.Uint32(i)
+		key, entry := getContractDataLedgerEntry(t, xdr.ContractDataEntry{
+			Contract:   address,
+			Key:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &value},
+			Durability: xdr.ContractDataDurabilityPersistent,
+			Val:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &value},
+		})
+		require.NoError(t, writer.UpsertLedgerEntry(entry))
+		ttlKey, err := entryKeyToTTLEntryKey(key)
+		require.NoError(t, err)
+		require.NoError(t, writer.UpsertLedgerEntry(getTTLLedgerEntry(ttlKey)))
+		keys = append(keys, key)
+	}
+	require.NoError(t, tx.Commit(23))
+
+	readTx, err := NewLedgerEntryReader(db).NewTx(context.Background())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, readTx.Done())
+	}()
+
+	firstPage, err := readTx.GetContractDataEntries(contract, nil, 2)
+	require.NoError(t, err)
+	require.Len(t, firstPage, 2)
+	secondPage, err := readTx.GetContractDataEntries(contract, &firstPage[1].Key, 2)
+	require.NoError(t, err)
+	require.Len(t, secondPage, 1)
+
+	var obtainedKeys []xdr.LedgerKey
+	for _, keyAndEntry := range append(firstPage, secondPage...) {
+		assert.Equal(t, contract, keyAndEntry.Entry.Data.ContractData.Contract)
+		require.NotNil(t, keyAndEntry.LiveUntilLedgerSeq)
+		assert.Equal(t, uint32(100), *keyAndEntry.LiveUntilLedgerSeq)
+		obtainedKeys = append(obtainedKeys, keyAndEntry.Key)
+	}
+	assert.ElementsMatch(t, keys[:3], obtainedKeys)
+
+	otherEntries, err := readTx.GetContractDataEntries(nextContract, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, otherEntries, 1)
+	assert.Equal(t, keys[3], otherEntries[0].Key)
+}
+
+func TestArchivedLedgerEntries(t *testing.T) {
+	db := NewTestDB(t)
+	four := xdr.Uint32(4)
+	key, entry := getContractDataLedgerEntry(t, xdr.ContractDataEntry{
+		Contract: xdr.ScAddress{
+			Type:       xdr.ScAddressTypeScAddressTypeContract,
+			ContractId: &xdr.Hash{0xca, 0xfe},
+		},
+		Key:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &four},
+		Durability: xdr.ContractDataDurabilityPersistent,
+		Val:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &four},
+	})
+	getArchivedEntries := func() []LedgerKeyAndEntry {
+		readTx, err := NewLedgerEntryReader(db).NewTx(context.Background())
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, readTx.Done())
+		}()
+		archived, err := readTx.GetArchivedLedgerEntries(key)
+		require.NoError(t, err)
+		return archived
+	}
+
+	// evict the entry
+	tx, err := makeReadWriter(db, 150, 15).NewTx(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, tx.LedgerEntryWriter().DeleteLedgerEntry(key))
+	require.NoError(t, tx.LedgerEntryWriter().ArchiveLedgerEntry(entry))
+	require.NoError(t, tx.Commit(23))
+
+	present, _, _, _ := getLedgerEntryAndLatestLedgerSequence(t, db, key)
+	assert.False(t, present)
+	archived := getArchivedEntries()
+	require.Len(t, archived, 1)
+	assert.Equal(t, key, archived[0].Key)
+	assert.Equal(t, entry, archived[0].Entry)
+	assert.Nil(t, archived[0].LiveUntilLedgerSeq)
+
+	// restore it
+	tx, err = makeReadWriter(db, 150, 15).NewTx(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, tx.LedgerEntryWriter().UpsertLedgerEntry(entry))
+	ttlKey, err := entryKeyToTTLEntryKey(key)
+	require.NoError(t, err)
+	require.NoError(t, tx.LedgerEntryWriter().UpsertLedgerEntry(getTTLLedgerEntry(ttlKey)))
+	require.NoError(t, tx.Commit(24))
+
+	present, _, _, _ = getLedgerEntryAndLatestLedgerSequence(t, db, key)
+	assert.True(t, present)
+	assert.Empty(t, getArchivedEntries())
+}
+
 // Make sure that (multiple, simultaneous) read transactions can happen while a write-transaction is ongoing,
 // and write is only visible once the transaction is committed
 func TestReadTxsDuringWriteTx(t *testing.T) {
@@ -4,6 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	migrate "github.com/rubenv/sql-migrate"
 
 	"github.com/stellar/go/support/log"
 	"github.com/stellar/go/xdr"
@@ -48,13 +53,15 @@ type MigrationApplier interface {
 }
 
 type migrationApplierFactory interface {
-	New(db *DB, latestLedger uint32) (MigrationApplier, error)
+	// New creates the applier of the migration. lastMigratedLedger is the last ledger
+	// migrated before the migration was interrupted (0 when the migration starts).
+	New(db *DB, latestLedger uint32, lastMigratedLedger uint32) (MigrationApplier, error)
 }
 
-type migrationApplierFactoryF func(db *DB, latestLedger uint32) (MigrationApplier, error)
+type migrationApplierFactoryF func(db *DB, latestLedger uint32, lastMigratedLedger uint32) (MigrationApplier, error)
 
-func (m migrationApplierFactoryF) New(db *DB, latestLedger uint32) (MigrationApplier, error) {
-	return m(db, latestLedger)
+func (m migrationApplierFactoryF) New(db *DB, latestLedger uint32, lastMigratedLedger uint32) (MigrationApplier, error) {
+	return m(db, latestLedger, lastMigratedLedger)
 }
 
 type Migration interface {
@@ -108,25 +115,47 @@ func (mm multiMigration) Rollback(ctx context.Context) error {
 	return err
 }
 
+// dataMigrationCommitInterval is the number of ledgers after which the progress
+// of data migrations is committed, so that they can be resumed after an interruption
+const dataMigrationCommitInterval = 1000
+
+func dataMigrationDoneMetaKey(name string) string {
+	return "Migration" + name + "Done"
+}
+
+func dataMigrationProgressMetaKey(name string) string {
+	return "Migration" + name + "LastLedger"
+}
+
 // guardedMigration is a db data migration whose application is guarded by a boolean in the meta table
-// (after the migration is applied the boolean is set to true, so that the migration is not applied again)
+// (after the migration is applied the boolean is set to true, so that the migration is not applied again).
+// The last migrated ledger is committed periodically, so that interrupted migrations are resumed.
 type guardedMigration struct {
-	guardMetaKey    string
-	db              *DB
-	migration       MigrationApplier
-	alreadyMigrated bool
+	name               string
+	db                 *DB
+	factory            migrationApplierFactory
+	migration          MigrationApplier
+	latestLedger       uint32
+	lastMigratedLedger uint32
+	uncommittedLedgers uint32
+	alreadyMigrated    bool
 }
 
 func newGuardedDataMigration(ctx context.Context, uniqueMigrationName string, factory migrationApplierFactory, db *DB) (Migration, error) {
 	migrationDB := &DB{
 		cache:            db.cache,
 		SessionInterface: db.SessionInterface.Clone(),
+		sqlDB:            db.sqlDB,
 	}
 	if err := migrationDB.Begin(ctx); err != nil {
 		return nil, err
 	}
-	metaKey := "Migration" + uniqueMigrationName + "Done"
-	previouslyMigrated, err := getMetaBool(ctx, migrationDB, metaKey)
+	previouslyMigrated, err := getMetaBool(ctx, migrationDB, dataMigrationDoneMetaKey(uniqueMigrationName))
+	if err != nil && !errors.Is(err, ErrEmptyDB) {
+		err = errors.Join(err, migrationDB.Rollback())
+		return nil, err
+	}
+	lastMigratedLedger, err := getMetaUint32(ctx, migrationDB, dataMigrationProgressMetaKey(uniqueMigrationName))
 	if err != nil && !errors.Is(err, ErrEmptyDB) {
 		err = errors.Join(err, migrationDB.Rollback())
 		return nil, err
@@ -136,16 +165,19 @@ func newGuardedDataMigration(ctx context.Context, uniqueMigrationName string, fa
 		err = errors.Join(err, migrationDB.Rollback())
 		return nil, fmt.Errorf("failed to get latest ledger sequence: %w", err)
 	}
-	applier, err := factory.New(migrationDB, latestLedger)
+	applier, err := factory.New(migrationDB, latestLedger, lastMigratedLedger)
 	if err != nil {
 		err = errors.Join(err, migrationDB.Rollback())
 		return nil, err
 	}
 	guardedMigration := &guardedMigration{
-		guardMetaKey:    metaKey,
-		db:              migrationDB,
-		migration:       applier,
-		alreadyMigrated: previouslyMigrated,
+		name:               uniqueMigrationName,
+		db:                 migrationDB,
+		factory:            factory,
+		migration:          applier,
+		latestLedger:       latestLedger,
+		lastMigratedLedger: lastMigratedLedger,
+		alreadyMigrated:    previouslyMigrated,
 	}
 	return guardedMigration, nil
 }
@@ -156,7 +188,33 @@ func (g *guardedMigration) Apply(ctx context.Context, meta xdr.LedgerCloseMeta)
 		// but, just in case.
 		return nil
 	}
-	return g.migration.Apply(ctx, meta)
+	if err := g.migration.Apply(ctx, meta); err != nil {
+		return err
+	}
+	g.lastMigratedLedger = meta.LedgerSequence()
+	g.uncommittedLedgers++
+	if g.uncommittedLedgers < dataMigrationCommitInterval {
+		return nil
+	}
+	return g.commitProgress(ctx)
+}
+
+// commitProgress commits the ledgers migrated so far and carries on in a new transaction
+func (g *guardedMigration) commitProgress(ctx context.Context) error {
+	err := setMetaValue(ctx, g.db, dataMigrationProgressMetaKey(g.name), strconv.FormatUint(uint64(g.lastMigratedLedger), 10))
+	if err != nil {
+		return err
+	}
+	if err := g.db.Commit(); err != nil {
+		return err
+	}
+	g.uncommittedLedgers = 0
+	if err := g.db.Begin(ctx); err != nil {
+		return err
+	}
+	// the applier is bound to the previous transaction
+	g.migration, err = g.factory.New(g.db, g.latestLedger, g.lastMigratedLedger)
+	return err
 }
 
 func (g *guardedMigration) ApplicableRange() *LedgerSeqRange {
@@ -170,7 +228,7 @@ func (g *guardedMigration) Commit(ctx context.Context) error {
 	if g.alreadyMigrated {
 		return nil
 	}
-	err := setMetaBool(ctx, g.db, g.guardMetaKey, true)
+	err := setMetaBool(ctx, g.db, dataMigrationDoneMetaKey(g.name), true)
 	if err != nil {
 		return errors.Join(err, g.Rollback(ctx))
 	}
@@ -181,18 +239,227 @@ func (g *guardedMigration) Rollback(ctx context.Context) error {
 	return g.db.Rollback()
 }
 
+// dataMigration is a data backfill which requires the schema changes of a schema migration
+type dataMigration struct {
+	name string
+	// schemaMigrationID is the ID of the schema migration the data migration depends on
+	// (the data migration is undone together with it)
+	schemaMigrationID string
+	newFactory        func(ctx context.Context, logger *log.Entry, cfg *config.Config) migrationApplierFactory
+}
+
+// dataMigrations are applied in order, after all the schema migrations
+var dataMigrations = []dataMigration{
+	{
+		name:              "TransactionsTable",
+		schemaMigrationID: "02_transactions.sql",
+		newFactory: func(ctx context.Context, logger *log.Entry, cfg *config.Config) migrationApplierFactory {
+			return newTransactionTableMigration(ctx, logger, cfg.HistoryRetentionWindow, cfg.NetworkPassphrase)
+		},
+	},
+	// Add other migrations here
+}
+
 func BuildMigrations(ctx context.Context, logger *log.Entry, db *DB, cfg *config.Config) (Migration, error) {
-	migrationName := "TransactionsTable"
-	factory := newTransactionTableMigration(
-		ctx,
-		logger.WithField("migration", migrationName),
-		cfg.HistoryRetentionWindow,
-		cfg.NetworkPassphrase,
-	)
-	m, err := newGuardedDataMigration(ctx, migrationName, factory, db)
+	appliedSchemaMigrations, err := getAppliedSchemaMigrations(db)
 	if err != nil {
-		return nil, fmt.Errorf("creating guarded transaction migration: %w", err)
+		return nil, err
 	}
-	// Add other migrations here
-	return multiMigration{m}, nil
+	var migrations multiMigration
+	for _, dm := range dataMigrations {
+		if _, ok := appliedSchemaMigrations[dm.schemaMigrationID]; !ok {
+			continue
+		}
+		factory := dm.newFactory(ctx, logger.WithField("migration", dm.name), cfg)
+		m, err := newGuardedDataMigration(ctx, dm.name, factory, db)
+		if err != nil {
+			return nil, errors.Join(
+				fmt.Errorf("creating guarded %s migration: %w", dm.name, err),
+				migrations.Rollback(ctx),
+			)
+		}
+		migrations = append(migrations, m)
+	}
+	return migrations, nil
+}
+
+const (
+	MigrationKindSchema = "schema"
+	MigrationKindData   = "data"
+)
+
+// MigrationStatus is the status of a schema or data migration
+type MigrationStatus struct {
+	ID   string
+	Kind string
+	// AppliedAt is only known for schema migrations
+	AppliedAt *time.Time
+	Applied   bool
+	// LastMigratedLedger is the progress of a pending data migration which was interrupted
+	LastMigratedLedger uint32
+}
+
+var (
+	// ErrSchemaBehind is returned by CheckSchema when schema migrations of this version are pending
+	ErrSchemaBehind = errors.New("the database schema is behind this version")
+	// ErrSchemaAhead is returned by CheckSchema when the database has schema migrations unknown to
+	// this version (i.e. it's owned by a newer version)
+	ErrSchemaAhead = errors.New("the database schema is ahead of this version")
+)
+
+// CheckSchema checks, without modifying the database (unlike MigrationStatuses, it can be called
+// on read-only databases), that its schema is the one of this version
+func CheckSchema(ctx context.Context, db *DB) error {
+	schemaMigrations, err := schemaMigrationSource().FindMigrations()
+	if err != nil {
+		return err
+	}
+	var tables []string
+	if err := db.SelectRaw(ctx, &tables,
+		"SELECT name FROM sqlite_master WHERE type = 'table' AND name = 'gorp_migrations'"); err != nil {
+		return err
+	}
+	var applied []string
+	if len(tables) > 0 {
+		if err := db.SelectRaw(ctx, &applied, "SELECT id FROM gorp_migrations"); err != nil {
+			return err
+		}
+	}
+	known := make(map[string]bool, len(schemaMigrations))
+	for _, m := range schemaMigrations {
+		known[m.Id] = true
+	}
+	appliedSet := make(map[string]bool, len(applied))
+	var unknown []string
+	for _, id := range applied {
+		appliedSet[id] = true
+		if !known[id] {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("%w (unknown migrations: %s)", ErrSchemaAhead, strings.Join(unknown, ", "))
+	}
+	var pending []string
+	for _, m := range schemaMigrations {
+		if !appliedSet[m.Id] {
+			pending = append(pending, m.Id)
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w (pending migrations: %s)", ErrSchemaBehind, strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+// MigrationStatuses returns the status of the known migrations, in application order
+func MigrationStatuses(ctx context.Context, db *DB) ([]MigrationStatus, error) {
+	schemaMigrations, err := schemaMigrationSource().FindMigrations()
+	if err != nil {
+		return nil, err
+	}
+	records, err := migrate.GetMigrationRecords(db.sqlDB, "sqlite3")
+	if err != nil {
+		return nil, err
+	}
+	appliedAt := make(map[string]time.Time, len(records))
+	for _, record := range records {
+		appliedAt[record.Id] = record.AppliedAt
+	}
+	result := make([]MigrationStatus, 0, len(schemaMigrations)+len(dataMigrations))
+	for _, m := range schemaMigrations {
+		status := MigrationStatus{ID: m.Id, Kind: MigrationKindSchema}
+		if t, ok := appliedAt[m.Id]; ok {
+			status.Applied = true
+			status.AppliedAt = &t
+		}
+		result = append(result, status)
+	}
+	for _, dm := range dataMigrations {
+		status := MigrationStatus{ID: dm.name, Kind: MigrationKindData}
+		// the metadata table doesn't exist until the first schema migration is applied
+		if _, ok := appliedAt[dm.schemaMigrationID]; ok {
+			status.Applied, err = getMetaBool(ctx, db, dataMigrationDoneMetaKey(dm.name))
+			if err != nil && !errors.Is(err, ErrEmptyDB) {
+				return nil, err
+			}
+			status.LastMigratedLedger, err = getMetaUint32(ctx, db, dataMigrationProgressMetaKey(dm.name))
+			if err != nil && !errors.Is(err, ErrEmptyDB) {
+				return nil, err
+			}
+		}
+		result = append(result, status)
+	}
+	return result, nil
+}
+
+// MigrateUp applies the pending schema migrations and then the pending data migrations
+// (resuming the interrupted ones), returning the number of applied schema migrations
+func MigrateUp(ctx context.Context, logger *log.Entry, db *DB, cfg *config.Config) (int, error) {
+	applied, err := migrate.ExecMax(db.sqlDB, "sqlite3", schemaMigrationSource(), migrate.Up, 0)
+	if err != nil {
+		return applied, fmt.Errorf("could not apply schema migrations: %w", err)
+	}
+	migrations, err := BuildMigrations(ctx, logger, db, cfg)
+	if err != nil {
+		return applied, err
+	}
+	if r := migrations.ApplicableRange(); r != nil && r.firstLedgerSeq <= r.lastLedgerSeq {
+		err = NewLedgerReader(db).StreamLedgerRange(ctx, r.firstLedgerSeq, r.lastLedgerSeq,
+			func(meta xdr.LedgerCloseMeta) error {
+				return migrations.Apply(ctx, meta)
+			},
+		)
+		if err != nil {
+			return applied, errors.Join(fmt.Errorf("could not apply data migrations: %w", err), migrations.Rollback(ctx))
+		}
+	}
+	return applied, migrations.Commit(ctx)
+}
+
+// MigrateDown undoes the given number of schema migrations (together with the
+// data migrations depending on them), returning the number of undone schema migrations
+func MigrateDown(ctx context.Context, db *DB, steps int) (int, error) {
+	if steps <= 0 {
+		return 0, fmt.Errorf("the number of migrations to undo must be positive")
+	}
+	undone, err := migrate.ExecMax(db.sqlDB, "sqlite3", schemaMigrationSource(), migrate.Down, steps)
+	if err != nil {
+		return undone, fmt.Errorf("could not undo schema migrations: %w", err)
+	}
+	appliedSchemaMigrations, err := getAppliedSchemaMigrations(db)
+	if err != nil {
+		return undone, err
+	}
+	if _, ok := appliedSchemaMigrations[firstSchemaMigrationID]; !ok {
+		// the metadata table is gone
+		return undone, nil
+	}
+	for _, dm := range dataMigrations {
+		if _, ok := appliedSchemaMigrations[dm.schemaMigrationID]; ok {
+			continue
+		}
+		// make sure the data migration is applied again with its schema migration
+		for _, key := range []string{dataMigrationDoneMetaKey(dm.name), dataMigrationProgressMetaKey(dm.name)} {
+			if err := deleteMetaValue(ctx, db, key); err != nil {
+				return undone, err
+			}
+		}
+	}
+	return undone, nil
+}
+
+// firstSchemaMigrationID creates the metadata table
+const firstSchemaMigrationID = "01_init.sql"
+
+func getAppliedSchemaMigrations(db *DB) (map[string]struct{}, error) {
+	records, err := migrate.GetMigrationRecords(db.sqlDB, "sqlite3")
+	if err != nil {
+		return nil, fmt.Errorf("could not obtain the applied schema migrations: %w", err)
+	}
+	result := make(map[string]struct{}, len(records))
+	for _, record := range records {
+		result[record.Id] = struct{}{}
+	}
+	return result, nil
 }
@@ -0,0 +1,184 @@
+package db
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func migrationStatusesByID(t *testing.T, db *DB) map[string]MigrationStatus {
+	statuses, err := MigrationStatuses(context.Background(), db)
+	require.NoError(t, err)
+	result := map[string]MigrationStatus{}
+	for _, status := range statuses {
+		result[status.ID] = status
+	}
+	return result
+}
+
+func TestMigrateUpResumesDataMigration(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.TODO()
+	cfg := &config.Config{HistoryRetentionWindow: 100, NetworkPassphrase: passphrase}
+
+	// store ledgers 101-105 without indexing their transactions
+	writer := NewReadWriter(log.DefaultLogger, db, interfaces.MakeNoOpDeamon(), 10, 100, passphrase)
+	write, err := writer.NewTx(ctx)
+	require.NoError(t, err)
+	for acctSeq := uint32(1); acctSeq <= 5; acctSeq++ {
+		require.NoError(t, write.LedgerWriter().InsertLedger(txMeta(acctSeq, true)))
+	}
+	require.NoError(t, write.Commit(105))
+
+	// the migration was interrupted after migrating ledger 103
+	require.NoError(t, setMetaValue(ctx, db, dataMigrationProgressMetaKey("TransactionsTable"), "103"))
+	status := migrationStatusesByID(t, db)["TransactionsTable"]
+	assert.False(t, status.Applied)
+	assert.Equal(t, uint32(103), status.LastMigratedLedger)
+
+	applied, err := MigrateUp(ctx, log.DefaultLogger, db, cfg)
+	require.NoError(t, err)
+	assert.Zero(t, applied)
+
+	reader := NewTransactionReader(log.DefaultLogger, db, passphrase)
+	for acctSeq := uint32(4); acctSeq <= 5; acctSeq++ {
+		_, _, err = reader.GetTransaction(ctx, txHash(acctSeq))
+		require.NoError(t, err)
+	}
+	// the ledgers migrated before the interruption aren't migrated again
+	_, _, err = reader.GetTransaction(ctx, txHash(3))
+	require.ErrorIs(t, err, ErrNoTransaction)
+
+	status = migrationStatusesByID(t, db)["TransactionsTable"]
+	assert.True(t, status.Applied)
+}
+
+func TestTransactionFiltersMigrationReindexesTransactions(t *testing.T) {
+	dbPath := path.Join(t.TempDir(), "db.sqlite")
+	db, err := OpenSQLiteDBWithoutMigrations(dbPath)
+	require.NoError(t, err)
+	defer db.Close()
+	ctx := context.TODO()
+	cfg := &config.Config{HistoryRetentionWindow: 100, NetworkPassphrase: passphrase}
+
+	// a database whose transactions were indexed before the filter columns existed
+	applied, err := migrate.ExecMax(db.sqlDB, "sqlite3", schemaMigrationSource(), migrate.Up, 2)
+	require.NoError(t, err)
+	require.Equal(t, 2, applied)
+	// (the retention window of the writer avoids trimming the tables of later migrations)
+	writer := NewReadWriter(log.DefaultLogger, db, interfaces.MakeNoOpDeamon(), 10, 1000, passphrase)
+	write, err := writer.NewTx(ctx)
+	require.NoError(t, err)
+	for acctSeq := uint32(1); acctSeq <= 3; acctSeq++ {
+		require.NoError(t, write.LedgerWriter().InsertLedger(txMeta(acctSeq, acctSeq != 2)))
+	}
+	require.NoError(t, write.Commit(103))
+	require.NoError(t, setMetaBool(ctx, db, dataMigrationDoneMetaKey("TransactionsTable"), true))
+
+	applied, err = MigrateUp(ctx, log.DefaultLogger, db, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 3, applied)
+	assert.True(t, migrationStatusesByID(t, db)["TransactionsTable"].Applied)
+
+	failed := false
+	positions, err := NewTransactionReader(log.DefaultLogger, db, passphrase).GetTransactionPositions(
+		ctx, TransactionFilter{Successful: &failed}, TransactionPosition{}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []TransactionPosition{{LedgerSequence: 102, ApplicationOrder: 1}}, positions)
+}
+
+func TestMigrateDown(t *testing.T) {
+	dbPath := path.Join(t.TempDir(), "db.sqlite")
+	db, err := OpenSQLiteDBWithoutMigrations(dbPath)
+	require.NoError(t, err)
+	defer db.Close()
+	ctx := context.TODO()
+	cfg := &config.Config{HistoryRetentionWindow: 100, NetworkPassphrase: passphrase}
+
+	statuses, err := MigrationStatuses(ctx, db)
+	require.NoError(t, err)
+	require.Len(t, statuses, 6)
+	for _, status := range statuses {
+		assert.False(t, status.Applied, status.ID)
+	}
+
+	applied, err := MigrateUp(ctx, log.DefaultLogger, db, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 5, applied)
+	statuses, err = MigrationStatuses(ctx, db)
+	require.NoError(t, err)
+	assert.Equal(t, "01_init.sql", statuses[0].ID)
+	assert.Equal(t, MigrationKindSchema, statuses[0].Kind)
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.Equal(t, "TransactionsTable", statuses[5].ID)
+	assert.Equal(t, MigrationKindData, statuses[5].Kind)
+	for _, status := range statuses {
+		assert.True(t, status.Applied, status.ID)
+	}
+
+	// undoing the transactions table undoes its data migration
+	undone, err := MigrateDown(ctx, db, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 4, undone)
+	byID := migrationStatusesByID(t, db)
+	assert.True(t, byID["01_init.sql"].Applied)
+	assert.False(t, byID["02_transactions.sql"].Applied)
+	assert.False(t, byID["TransactionsTable"].Applied)
+	done, err := getMetaBool(ctx, db, dataMigrationDoneMetaKey("TransactionsTable"))
+	require.ErrorIs(t, err, ErrEmptyDB)
+	assert.False(t, done)
+
+	applied, err = MigrateUp(ctx, log.DefaultLogger, db, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 4, applied)
+	assert.True(t, migrationStatusesByID(t, db)["TransactionsTable"].Applied)
+
+	undone, err = MigrateDown(ctx, db, 6)
+	require.NoError(t, err)
+	assert.Equal(t, 5, undone)
+	for _, status := range migrationStatusesByID(t, db) {
+		assert.False(t, status.Applied, status.ID)
+	}
+
+	_, err = MigrateDown(ctx, db, 0)
+	require.EqualError(t, err, "the number of migrations to undo must be positive")
+}
+
+func TestCheckSchema(t *testing.T) {
+	ctx := context.TODO()
+	db, err := OpenSQLiteDBWithoutMigrations(path.Join(t.TempDir(), "db.sqlite"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.ErrorIs(t, CheckSchema(ctx, db), ErrSchemaBehind)
+	_, err = migrate.ExecMax(db.sqlDB, "sqlite3", schemaMigrationSource(), migrate.Up, 2)
+	require.NoError(t, err)
+	err = CheckSchema(ctx, db)
+	require.ErrorIs(t, err, ErrSchemaBehind)
+	assert.ErrorContains(t, err, "03_transaction_filters.sql")
+
+	_, err = migrate.ExecMax(db.sqlDB, "sqlite3", schemaMigrationSource(), migrate.Up, 0)
+	require.NoError(t, err)
+	require.NoError(t, CheckSchema(ctx, db))
+
+	// a migration of a newer version
+	_, err = db.ExecRaw(ctx, "INSERT INTO gorp_migrations (id, applied_at) VALUES ('99_future.sql', CURRENT_TIMESTAMP)")
+	require.NoError(t, err)
+	err = CheckSchema(ctx, db)
+	require.ErrorIs(t, err, ErrSchemaAhead)
+	assert.ErrorContains(t, err, "99_future.sql")
+
+	report, err := Verify(ctx, db, passphrase)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.ProblemCount)
+	assert.Contains(t, report.Problems[0], "ahead of this version")
+}
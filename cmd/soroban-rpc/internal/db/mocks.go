@@ -1,8 +1,11 @@
 package db
 
 import (
+	"bytes"
 	"context"
 	"io"
+	"slices"
+	"sort"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -49,6 +52,16 @@ func (txn *mockTransactionHandler) InsertTransactions(lcm xdr.LedgerCloseMeta) e
 		h := tx.Result.TransactionHash.HexString()
 		txn.txs[h] = tx
 		txn.txHashToMeta[h] = &lcm
+		// like the real store, fee-bump transactions can be looked up by their inner hash
+		if tx.Envelope.IsFeeBump() {
+			hash, err := innerTransactionHash(tx.Envelope, txn.passphrase)
+			if err != nil {
+				return err
+			}
+			innerHash := hash.HexString()
+			txn.txs[innerHash] = tx
+			txn.txHashToMeta[innerHash] = &lcm
+		}
 	}
 
 	if lcmSeq := lcm.LedgerSequence(); lcmSeq < txn.ledgerRange.FirstLedger.Sequence ||
@@ -81,6 +94,60 @@ func (txn *mockTransactionHandler) GetTransaction(ctx context.Context, hash xdr.
 	}
 }
 
+func (txn *mockTransactionHandler) GetTransactionPositions(
+	ctx context.Context, filter TransactionFilter, start TransactionPosition, limit uint,
+) ([]TransactionPosition, error) {
+	sequences := make([]uint32, 0, len(txn.ledgerSeqToMeta))
+	for sequence := range txn.ledgerSeqToMeta {
+		if sequence >= start.LedgerSequence {
+			sequences = append(sequences, sequence)
+		}
+	}
+	sort.Slice(sequences, func(i, j int) bool { return sequences[i] < sequences[j] })
+
+	var positions []TransactionPosition
+	for _, sequence := range sequences {
+		reader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(txn.passphrase, *txn.ledgerSeqToMeta[sequence])
+		if err != nil {
+			return nil, err
+		}
+		for uint(len(positions)) < limit {
+			tx, err := reader.Read()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+			position := TransactionPosition{LedgerSequence: sequence, ApplicationOrder: int32(tx.Index)}
+			if sequence == start.LedgerSequence && position.ApplicationOrder < start.ApplicationOrder {
+				continue
+			}
+			if filter.matches(tx) {
+				positions = append(positions, position)
+			}
+		}
+	}
+	return positions, nil
+}
+
+// matches is the in-memory equivalent of the filtering done by the database
+func (f TransactionFilter) matches(tx ingest.LedgerTransaction) bool {
+	if f.Successful != nil && *f.Successful != tx.Result.Successful() {
+		return false
+	}
+	if f.Soroban != nil && *f.Soroban != isSorobanTransaction(tx.Envelope) {
+		return false
+	}
+	if f.SourceAccount != nil && !bytes.Equal(f.SourceAccount.Ed25519[:], sourceAccountKey(tx.Envelope)) {
+		return false
+	}
+	if f.ContractID != nil {
+		contracts, err := touchedContracts(tx)
+		return err == nil && slices.Contains(contracts, *f.ContractID)
+	}
+	return true
+}
+
 func (txn *mockTransactionHandler) RegisterMetrics(_, _ prometheus.Observer) {}
 
 type mockLedgerReader struct {
@@ -105,6 +172,14 @@ func (m *mockLedgerReader) StreamAllLedgers(ctx context.Context, f StreamLedgerF
 	return nil
 }
 
+func (m *mockLedgerReader) StreamLedgerRange(ctx context.Context, start, end uint32, f StreamLedgerFn) error {
+	return nil
+}
+
+func (m *mockLedgerReader) GetLedgerRange(ctx context.Context) (ledgerbucketwindow.LedgerRange, error) {
+	return m.txn.ledgerRange, nil
+}
+
 var (
 	_ TransactionReader = &mockTransactionHandler{}
 	_ TransactionWriter = &mockTransactionHandler{}
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/stellar/go/support/db"
+)
+
+const (
+	// busyRetries is the number of times a read failing because the database is busy is retried
+	busyRetries              = 3
+	busyRetryInitialInterval = 10 * time.Millisecond
+)
+
+// ReadPoolOptions configures the pool of read-only connections serving the readers
+// (ledgers, ledger entries and transactions), which is separate from the connections
+// used by the writer (ingestion and migrations)
+type ReadPoolOptions struct {
+	// IdleConnections is the number of connections kept open between the requests
+	IdleConnections int
+	// BusyTimeout is how long a read waits for a lock held by the writer
+	// (e.g. while the write-ahead log is checkpointed after a commit) before failing
+	BusyTimeout time.Duration
+}
+
+// DefaultReadPoolOptions are the options of the databases opened with OpenSQLiteDB
+var DefaultReadPoolOptions = ReadPoolOptions{
+	IdleConnections: 2,
+	BusyTimeout:     5 * time.Second,
+}
+
+// openSQLiteReadPool opens the pool of read-only connections of an on-disk database.
+//
+// The number of open connections isn't bounded: a request can check out a second connection
+// while holding one (e.g. simulations read the ledgers in the middle of their ledger entry
+// transaction), which would deadlock a bounded pool under load. The concurrency of the requests
+// is bounded by the request limiters instead.
+func openSQLiteReadPool(dbFilePath string, options ReadPoolOptions) (*db.Session, error) {
+	// the journal mode is persistent, so the readers use the write-ahead log of the writer
+	session, err := db.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro&_busy_timeout=%d",
+		dbFilePath, options.BusyTimeout.Milliseconds()))
+	if err != nil {
+		return nil, fmt.Errorf("open read pool failed: %w", err)
+	}
+	session.DB.SetMaxIdleConns(options.IdleConnections)
+	return session, nil
+}
+
+func isBusy(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) &&
+		(sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked)
+}
+
+// retryBusy runs f, retrying it with an exponential backoff while the database is busy
+func retryBusy(ctx context.Context, f func() error) error {
+	exponential := backoff.NewExponentialBackOff()
+	exponential.InitialInterval = busyRetryInitialInterval
+	retries := backoff.WithContext(backoff.WithMaxRetries(exponential, busyRetries), ctx)
+	return backoff.Retry(func() error {
+		err := f()
+		if err != nil && !isBusy(err) {
+			return backoff.Permanent(err)
+		}
+		return err
+	}, retries)
+}
+
+// busyRetrySession decorates the sessions of the read pool, retrying the connection checkouts
+// and reads which fail because the database is busy beyond the busy timeout
+// (rather than failing the request)
+type busyRetrySession struct {
+	db.SessionInterface
+}
+
+func newBusyRetrySession(session db.SessionInterface) db.SessionInterface {
+	return busyRetrySession{SessionInterface: session}
+}
+
+func (s busyRetrySession) Clone() db.SessionInterface {
+	return newBusyRetrySession(s.SessionInterface.Clone())
+}
+
+func (s busyRetrySession) BeginTx(ctx context.Context, opts *sql.TxOptions) error {
+	return retryBusy(ctx, func() error {
+		return s.SessionInterface.BeginTx(ctx, opts)
+	})
+}
+
+func (s busyRetrySession) Get(ctx context.Context, dest interface{}, query sq.Sqlizer) error {
+	return retryBusy(ctx, func() error {
+		return s.SessionInterface.Get(ctx, dest, query)
+	})
+}
+
+func (s busyRetrySession) GetRaw(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return retryBusy(ctx, func() error {
+		return s.SessionInterface.GetRaw(ctx, dest, query, args...)
+	})
+}
+
+func (s busyRetrySession) Select(ctx context.Context, dest interface{}, query sq.Sqlizer) error {
+	return retryBusy(ctx, func() error {
+		return s.SessionInterface.Select(ctx, dest, query)
+	})
+}
+
+func (s busyRetrySession) SelectRaw(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return retryBusy(ctx, func() error {
+		return s.SessionInterface.SelectRaw(ctx, dest, query, args...)
+	})
+}
+
+func (s busyRetrySession) Query(ctx context.Context, query sq.Sqlizer) (*db.Rows, error) {
+	var rows *db.Rows
+	err := retryBusy(ctx, func() (err error) {
+		rows, err = s.SessionInterface.Query(ctx, query)
+		return err
+	})
+	return rows, err
+}
+
+func (s busyRetrySession) QueryRaw(ctx context.Context, query string, args ...interface{}) (*db.Rows, error) {
+	var rows *db.Rows
+	err := retryBusy(ctx, func() (err error) {
+		rows, err = s.SessionInterface.QueryRaw(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
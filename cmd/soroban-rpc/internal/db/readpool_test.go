@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func TestReadPool(t *testing.T) {
+	ctx := context.Background()
+	db := NewTestDB(t)
+	writer := NewReadWriter(log.DefaultLogger, db, interfaces.MakeNoOpDeamon(), 10, 10, passphrase)
+
+	write, err := writer.NewTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, write.LedgerWriter().InsertLedger(createLedger(1)))
+	require.NoError(t, write.Commit(1))
+
+	// the readers don't wait for the write transaction in progress, they read the committed ledgers
+	write, err = writer.NewTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, write.LedgerWriter().InsertLedger(createLedger(2)))
+	readCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	assertLedgerRange(t, NewLedgerReader(db), 1, 1)
+	readTx, err := NewLedgerEntryReader(db).NewTx(readCtx)
+	require.NoError(t, err)
+	latestLedger, err := readTx.GetLatestLedgerSequence()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), latestLedger)
+	require.NoError(t, write.Commit(2))
+	require.NoError(t, readTx.Done())
+	assertLedgerRange(t, NewLedgerReader(db), 1, 2)
+
+	// the connections of the readers are read-only
+	_, err = db.Readers().ExecRaw(ctx, "DELETE FROM ledger_close_meta")
+	require.Error(t, err)
+	assertLedgerRange(t, NewLedgerReader(db), 1, 2)
+}
+
+func TestRetryBusy(t *testing.T) {
+	ctx := context.Background()
+	busy := sqlite3.Error{Code: sqlite3.ErrBusy}
+
+	calls := 0
+	require.NoError(t, retryBusy(ctx, func() error {
+		calls++
+		if calls < 3 {
+			return busy
+		}
+		return nil
+	}))
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	require.ErrorIs(t, retryBusy(ctx, func() error {
+		calls++
+		return busy
+	}), busy)
+	assert.Equal(t, busyRetries+1, calls)
+
+	// the other errors aren't retried
+	calls = 0
+	other := errors.New("other")
+	require.ErrorIs(t, retryBusy(ctx, func() error {
+		calls++
+		return other
+	}), other)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+)
+
+// reingestBatchSize is the number of ledgers reingested in each database transaction
+const reingestBatchSize = 100
+
+// ReingestProgressFn is invoked with the last reingested ledger after every batch
+type ReingestProgressFn func(ledgerSeq uint32)
+
+// ReingestTransactions rebuilds the transaction lookups of a ledger range (both ends
+// included) from the ledgers stored in the database. Since events are served from the
+// stored ledgers, the range must be within the retention window of the database.
+func ReingestTransactions(
+	ctx context.Context,
+	logger *log.Entry,
+	dbConn *DB,
+	passphrase string,
+	start, end uint32,
+	progress ReingestProgressFn,
+) error {
+	ledgerRange, err := getLedgerRange(ctx, dbConn)
+	if err != nil {
+		return err
+	}
+	if err := CheckStoredLedgerRange(ledgerRange.FirstLedger.Sequence, ledgerRange.LastLedger.Sequence, start, end); err != nil {
+		return err
+	}
+	for batchStart := start; ; batchStart += reingestBatchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batchEnd := end
+		if end-batchStart >= reingestBatchSize {
+			batchEnd = batchStart + reingestBatchSize - 1
+		}
+		if err := reingestTransactionBatch(ctx, logger, dbConn, passphrase, batchStart, batchEnd); err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(batchEnd)
+		}
+		if batchEnd == end {
+			return nil
+		}
+	}
+}
+
+// CheckStoredLedgerRange checks that the ledger range (both ends included) is valid
+// and within the range of ledgers stored in the database.
+func CheckStoredLedgerRange(firstStored, lastStored, start, end uint32) error {
+	if start == 0 || start > end {
+		return fmt.Errorf("invalid ledger range [%d, %d]", start, end)
+	}
+	if firstStored == 0 {
+		return fmt.Errorf("no ledgers are stored in the database")
+	}
+	if start < firstStored || end > lastStored {
+		return fmt.Errorf(
+			"ledger range [%d, %d] is not within the ledgers stored in the database [%d, %d]",
+			start, end, firstStored, lastStored,
+		)
+	}
+	return nil
+}
+
+func reingestTransactionBatch(
+	ctx context.Context,
+	logger *log.Entry,
+	dbConn *DB,
+	passphrase string,
+	start, end uint32,
+) error {
+	session := dbConn.Clone()
+	if err := session.Begin(ctx); err != nil {
+		return err
+	}
+	// rolling back a committed transaction is a no-op
+	defer func() { _ = session.Rollback() }()
+
+	inRange := sq.And{sq.GtOrEq{"sequence": start}, sq.LtOrEq{"sequence": end}}
+	var lcms []xdr.LedgerCloseMeta
+	query := sq.Select("meta").From(ledgerCloseMetaTableName).Where(inRange).OrderBy("sequence asc")
+	if err := session.Select(ctx, &lcms, query); err != nil {
+		return err
+	}
+	if uint32(len(lcms)) != end-start+1 {
+		return fmt.Errorf("ledgers [%d, %d] are no longer stored in the database", start, end)
+	}
+
+	stmtCache := sq.NewStmtCache(session.GetTx())
+	for _, table := range []string{transactionTableName, transactionContractsTableName} {
+		_, err := sq.Delete(table).
+			Where(sq.And{sq.GtOrEq{"ledger_sequence": start}, sq.LtOrEq{"ledger_sequence": end}}).
+			RunWith(stmtCache).
+			Exec()
+		if err != nil {
+			return err
+		}
+	}
+	txWriter := transactionHandler{
+		log:        logger,
+		db:         session,
+		stmtCache:  stmtCache,
+		passphrase: passphrase,
+	}
+	for _, lcm := range lcms {
+		if err := txWriter.InsertTransactions(lcm); err != nil {
+			return err
+		}
+	}
+	return session.Commit()
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func TestReingestTransactions(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.TODO()
+	logger := log.DefaultLogger
+
+	// store the ledgers without their transaction lookups
+	writer := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 10, 1000, passphrase)
+	write, err := writer.NewTx(ctx)
+	require.NoError(t, err)
+	var lcms []xdr.LedgerCloseMeta
+	for acctSeq := uint32(1); acctSeq <= 250; acctSeq++ {
+		lcm := txMeta(acctSeq, true)
+		lcms = append(lcms, lcm)
+		require.NoError(t, write.LedgerWriter().InsertLedger(lcm))
+	}
+	require.NoError(t, write.Commit(lcms[len(lcms)-1].LedgerSequence()))
+
+	reader := NewTransactionReader(logger, db, passphrase)
+	_, _, err = reader.GetTransaction(ctx, lcms[0].TransactionHash(0))
+	require.ErrorIs(t, err, ErrNoTransaction)
+
+	// reingesting twice must be fine
+	for i := 0; i < 2; i++ {
+		var progress []uint32
+		err = ReingestTransactions(ctx, logger, db, passphrase, 101, 350, func(ledgerSeq uint32) {
+			progress = append(progress, ledgerSeq)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []uint32{200, 300, 350}, progress)
+	}
+
+	for _, lcm := range lcms {
+		tx, _, err := reader.GetTransaction(ctx, lcm.TransactionHash(0))
+		require.NoError(t, err)
+		assert.Equal(t, lcm.LedgerSequence(), tx.Ledger.Sequence)
+	}
+}
+
+func TestReingestTransactionsOutsideOfStoredRange(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.TODO()
+	logger := log.DefaultLogger
+
+	err := ReingestTransactions(ctx, logger, db, passphrase, 101, 102, nil)
+	require.EqualError(t, err, "no ledgers are stored in the database")
+
+	writer := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 10, 1000, passphrase)
+	write, err := writer.NewTx(ctx)
+	require.NoError(t, err)
+	for acctSeq := uint32(1); acctSeq <= 10; acctSeq++ {
+		require.NoError(t, write.LedgerWriter().InsertLedger(txMeta(acctSeq, true)))
+	}
+	require.NoError(t, write.Commit(110))
+
+	err = ReingestTransactions(ctx, logger, db, passphrase, 100, 105, nil)
+	require.EqualError(t, err, "ledger range [100, 105] is not within the ledgers stored in the database [101, 110]")
+	err = ReingestTransactions(ctx, logger, db, passphrase, 105, 104, nil)
+	require.EqualError(t, err, "invalid ledger range [105, 104]")
+}
@@ -0,0 +1,360 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	migrate "github.com/rubenv/sql-migrate"
+
+	"github.com/stellar/go/support/storage"
+)
+
+// SnapshotChecksumSuffix is appended to the name of a snapshot to obtain the name of the file
+// holding its SHA-256 checksum (in the format of sha256sum)
+const SnapshotChecksumSuffix = ".sha256"
+
+// SnapshotManifestName is the name of the manifest describing the latest snapshot published by PublishSnapshot
+const SnapshotManifestName = "manifest.json"
+
+// SnapshotManifest describes a published database snapshot
+type SnapshotManifest struct {
+	// Snapshot is the name of the snapshot file, in the same location as the manifest
+	Snapshot string `json:"snapshot"`
+	// Checksum is the hex-encoded SHA-256 checksum of the snapshot
+	Checksum          string    `json:"sha256"`
+	Size              int64     `json:"size"`
+	LatestLedger      uint32    `json:"latestLedger"`
+	SchemaVersion     string    `json:"schemaVersion"`
+	NetworkPassphrase string    `json:"networkPassphrase"`
+	CreatedAt         time.Time `json:"createdAt"`
+}
+
+// sqliteHeader is the start of all SQLite database files
+var sqliteHeader = []byte("SQLite format 3\x00")
+
+// connectSnapshotBackend returns the storage holding the snapshot at source (a file path, an
+// HTTP(S) URL or an object storage URL) and the name of the snapshot in that storage.
+func connectSnapshotBackend(ctx context.Context, source string, options storage.ConnectOptions) (storage.Storage, string, error) {
+	parsed, err := url.Parse(source)
+	if err != nil || parsed.Scheme == "" {
+		dir, name := filepath.Split(source)
+		return storage.NewFilesystemStorage(dir), name, nil
+	}
+	dir, name := path.Split(parsed.Path)
+	if name == "" {
+		return nil, "", fmt.Errorf("the snapshot URL %s must include a file name", source)
+	}
+	parsed.Path = dir
+	options.Context = ctx
+	backend, err := storage.ConnectBackend(parsed.String(), options)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not connect to %s: %w", parsed.String(), err)
+	}
+	return backend, name, nil
+}
+
+// parseSnapshotChecksum parses a hex-encoded SHA-256 checksum, optionally followed by
+// the file name (as output by sha256sum)
+func parseSnapshotChecksum(checksum string) ([]byte, error) {
+	fields := strings.Fields(checksum)
+	if len(fields) == 0 {
+		return nil, errors.New("empty checksum")
+	}
+	result, err := hex.DecodeString(fields[0])
+	if err != nil || len(result) != sha256.Size {
+		return nil, fmt.Errorf("invalid SHA-256 checksum %q", fields[0])
+	}
+	return result, nil
+}
+
+func readSnapshotChecksum(backend storage.Storage, name string) ([]byte, error) {
+	reader, err := backend.GetFile(name + SnapshotChecksumSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch the checksum of the snapshot: %w", err)
+	}
+	defer reader.Close()
+	// the checksum file only holds a line
+	content, err := io.ReadAll(io.LimitReader(reader, 1024))
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch the checksum of the snapshot: %w", err)
+	}
+	return parseSnapshotChecksum(string(content))
+}
+
+func readSnapshotManifest(backend storage.Storage, name string, networkPassphrase string) (SnapshotManifest, error) {
+	reader, err := backend.GetFile(name)
+	if err != nil {
+		return SnapshotManifest{}, fmt.Errorf("could not fetch the snapshot manifest: %w", err)
+	}
+	defer reader.Close()
+	var manifest SnapshotManifest
+	if err := json.NewDecoder(reader).Decode(&manifest); err != nil {
+		return SnapshotManifest{}, fmt.Errorf("invalid snapshot manifest: %w", err)
+	}
+	if manifest.Snapshot == "" || path.Base(manifest.Snapshot) != manifest.Snapshot {
+		return SnapshotManifest{}, fmt.Errorf("invalid snapshot name %q in the manifest", manifest.Snapshot)
+	}
+	if manifest.NetworkPassphrase != networkPassphrase {
+		return SnapshotManifest{}, fmt.Errorf(
+			"the snapshot belongs to another network (%q)", manifest.NetworkPassphrase,
+		)
+	}
+	schemaMigrations, err := schemaMigrationSource().FindMigrations()
+	if err != nil {
+		return SnapshotManifest{}, err
+	}
+	for _, m := range schemaMigrations {
+		if m.Id == manifest.SchemaVersion {
+			return manifest, nil
+		}
+	}
+	return SnapshotManifest{}, fmt.Errorf(
+		"unknown schema version %q, the snapshot was published by a more recent version", manifest.SchemaVersion,
+	)
+}
+
+func downloadSnapshot(backend storage.Storage, name string, targetPath string, expectedChecksum []byte) error {
+	reader, err := backend.GetFile(name)
+	if err != nil {
+		return fmt.Errorf("could not fetch the snapshot: %w", err)
+	}
+	defer reader.Close()
+	file, err := os.OpenFile(targetPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(file, hash), reader); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("could not download the snapshot: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if checksum := hash.Sum(nil); !bytes.Equal(checksum, expectedChecksum) {
+		return fmt.Errorf("checksum mismatch: the snapshot has checksum %x, expected %x", checksum, expectedChecksum)
+	}
+	return nil
+}
+
+// checkSnapshot makes sure that the snapshot is a database with ingested ledgers
+// (migrating it to the current schema if needed), and returns its latest ledger
+func checkSnapshot(ctx context.Context, snapshotPath string) (uint32, error) {
+	// check the header first, since opening other files as databases is slow to fail
+	file, err := os.Open(snapshotPath)
+	if err != nil {
+		return 0, err
+	}
+	header := make([]byte, len(sqliteHeader))
+	_, err = io.ReadFull(file, header)
+	_ = file.Close()
+	if err != nil || !bytes.Equal(header, sqliteHeader) {
+		return 0, errors.New("the snapshot is not a valid database: not a SQLite file")
+	}
+	snapshot, err := OpenSQLiteDB(snapshotPath)
+	if err != nil {
+		return 0, fmt.Errorf("the snapshot is not a valid database: %w", err)
+	}
+	defer snapshot.Close()
+	latestLedger, err := NewLedgerEntryReader(snapshot).GetLatestLedgerSequence(ctx)
+	if errors.Is(err, ErrEmptyDB) {
+		return 0, errors.New("the snapshot doesn't contain any ledger")
+	}
+	return latestLedger, err
+}
+
+// RestoreSnapshot initializes the database at targetPath from the database snapshot (as written by
+// BackupTo) at source, which is either a file path or a URL (HTTP(S) or object storage). The source can
+// also be the manifest of a snapshot published by PublishSnapshot, for the given network. The snapshot
+// must match the given hex-encoded SHA-256 checksum which, when empty, is read from the manifest or from
+// the checksum file published alongside the snapshot. It returns the latest ledger of the snapshot.
+func RestoreSnapshot(
+	ctx context.Context,
+	source string,
+	targetPath string,
+	checksum string,
+	networkPassphrase string,
+	options storage.ConnectOptions,
+) (uint32, error) {
+	if _, err := os.Stat(targetPath); err == nil {
+		return 0, fmt.Errorf("%s already exists", targetPath)
+	}
+	backend, name, err := connectSnapshotBackend(ctx, source, options)
+	if err != nil {
+		return 0, err
+	}
+	defer backend.Close()
+
+	if strings.HasSuffix(name, ".json") {
+		manifest, err := readSnapshotManifest(backend, name, networkPassphrase)
+		if err != nil {
+			return 0, err
+		}
+		name = manifest.Snapshot
+		if checksum == "" {
+			checksum = manifest.Checksum
+		}
+	}
+
+	var expectedChecksum []byte
+	if checksum != "" {
+		expectedChecksum, err = parseSnapshotChecksum(checksum)
+	} else {
+		expectedChecksum, err = readSnapshotChecksum(backend, name)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// download to a temporary file first, so that the target only exists once verified
+	partialPath := targetPath + ".partial"
+	removePartial := func() {
+		for _, suffix := range []string{"", "-wal", "-shm"} {
+			_ = os.Remove(partialPath + suffix)
+		}
+	}
+	// remove the files left over by an interrupted restoration
+	removePartial()
+	latestLedger, err := func() (uint32, error) {
+		if err := downloadSnapshot(backend, name, partialPath, expectedChecksum); err != nil {
+			return 0, err
+		}
+		return checkSnapshot(ctx, partialPath)
+	}()
+	if err != nil {
+		removePartial()
+		return 0, err
+	}
+	return latestLedger, os.Rename(partialPath, targetPath)
+}
+
+// schemaVersion returns the latest schema migration applied to the database
+func schemaVersion(d *DB) (string, error) {
+	records, err := migrate.GetMigrationRecords(d.sqlDB, "sqlite3")
+	if err != nil {
+		return "", fmt.Errorf("could not obtain the applied schema migrations: %w", err)
+	}
+	if len(records) == 0 {
+		return "", errors.New("no schema migrations were applied")
+	}
+	return records[len(records)-1].Id, nil
+}
+
+func describeSnapshot(ctx context.Context, snapshotPath string) (SnapshotManifest, error) {
+	file, err := os.Open(snapshotPath)
+	if err != nil {
+		return SnapshotManifest{}, err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return SnapshotManifest{}, err
+	}
+	snapshot, err := OpenSQLiteDBWithoutMigrations(snapshotPath)
+	if err != nil {
+		return SnapshotManifest{}, err
+	}
+	defer snapshot.Close()
+	latestLedger, err := NewLedgerEntryReader(snapshot).GetLatestLedgerSequence(ctx)
+	if errors.Is(err, ErrEmptyDB) {
+		return SnapshotManifest{}, errors.New("the database doesn't contain any ledger yet")
+	} else if err != nil {
+		return SnapshotManifest{}, err
+	}
+	version, err := schemaVersion(snapshot)
+	if err != nil {
+		return SnapshotManifest{}, err
+	}
+	return SnapshotManifest{
+		Checksum:      hex.EncodeToString(hash.Sum(nil)),
+		Size:          size,
+		LatestLedger:  latestLedger,
+		SchemaVersion: version,
+	}, nil
+}
+
+func putFile(backend storage.Storage, name string, localPath string) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	// PutFile closes the file
+	if err := backend.PutFile(name, file); err != nil {
+		return fmt.Errorf("could not upload %s: %w", name, err)
+	}
+	return nil
+}
+
+// PublishSnapshot writes a snapshot of the database to target (a directory path or an
+// object storage URL), together with its checksum file and a manifest describing it. The
+// snapshots are named after their latest ledger and the manifest (which can be used to bootstrap
+// new nodes, see RestoreSnapshot) is uploaded last, so that it always refers to a complete snapshot.
+func PublishSnapshot(
+	ctx context.Context, d *DB, target string, networkPassphrase string, options storage.ConnectOptions,
+) (SnapshotManifest, error) {
+	var backend storage.Storage
+	parsed, err := url.Parse(target)
+	if err != nil || parsed.Scheme == "" {
+		backend = storage.NewFilesystemStorage(target)
+	} else {
+		options.Context = ctx
+		backend, err = storage.ConnectBackend(target, options)
+		if err != nil {
+			return SnapshotManifest{}, fmt.Errorf("could not connect to %s: %w", target, err)
+		}
+	}
+	defer backend.Close()
+
+	tmpDir, err := os.MkdirTemp("", "soroban-rpc-snapshot-")
+	if err != nil {
+		return SnapshotManifest{}, err
+	}
+	defer os.RemoveAll(tmpDir)
+	localPath := filepath.Join(tmpDir, "snapshot.sqlite")
+	if err := d.Backup(ctx, localPath); err != nil {
+		return SnapshotManifest{}, err
+	}
+	manifest, err := describeSnapshot(ctx, localPath)
+	if err != nil {
+		return SnapshotManifest{}, err
+	}
+	manifest.Snapshot = fmt.Sprintf("soroban-rpc-%d.sqlite", manifest.LatestLedger)
+	manifest.NetworkPassphrase = networkPassphrase
+	manifest.CreatedAt = time.Now().UTC()
+
+	checksumPath := filepath.Join(tmpDir, "snapshot.sqlite"+SnapshotChecksumSuffix)
+	if err := os.WriteFile(checksumPath, []byte(manifest.Checksum+"  "+manifest.Snapshot+"\n"), 0o600); err != nil {
+		return SnapshotManifest{}, err
+	}
+	manifestContent, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return SnapshotManifest{}, err
+	}
+	manifestPath := filepath.Join(tmpDir, SnapshotManifestName)
+	if err := os.WriteFile(manifestPath, manifestContent, 0o600); err != nil {
+		return SnapshotManifest{}, err
+	}
+	for _, file := range []struct{ name, localPath string }{
+		{manifest.Snapshot, localPath},
+		{manifest.Snapshot + SnapshotChecksumSuffix, checksumPath},
+		{SnapshotManifestName, manifestPath},
+	} {
+		if err := putFile(backend, file.name, file.localPath); err != nil {
+			return SnapshotManifest{}, err
+		}
+	}
+	return manifest, nil
+}
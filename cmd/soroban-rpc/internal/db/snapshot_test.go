@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/storage"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func TestRestoreSnapshot(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.TODO()
+
+	writer := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 10, 1000, passphrase)
+	write, err := writer.NewTx(ctx)
+	require.NoError(t, err)
+	for sequence := uint32(1); sequence <= 20; sequence++ {
+		require.NoError(t, write.LedgerWriter().InsertLedger(createLedger(sequence)))
+	}
+	require.NoError(t, write.Commit(20))
+
+	snapshotDir := t.TempDir()
+	snapshotPath := filepath.Join(snapshotDir, "snapshot.sqlite")
+	require.NoError(t, db.Backup(ctx, snapshotPath))
+	content, err := os.ReadFile(snapshotPath)
+	require.NoError(t, err)
+	checksum := sha256.Sum256(content)
+	checksumHex := hex.EncodeToString(checksum[:])
+	require.NoError(t, os.WriteFile(snapshotPath+SnapshotChecksumSuffix, []byte(checksumHex+"  snapshot.sqlite\n"), 0o600))
+	server := httptest.NewServer(http.FileServer(http.Dir(snapshotDir)))
+	defer server.Close()
+
+	dir := t.TempDir()
+	for i, source := range []string{
+		snapshotPath,
+		"file://" + snapshotPath,
+		server.URL + "/snapshot.sqlite",
+	} {
+		target := filepath.Join(dir, hex.EncodeToString([]byte{byte(i)})+".sqlite")
+		latestLedger, err := RestoreSnapshot(ctx, source, target, "", passphrase, storage.ConnectOptions{})
+		require.NoError(t, err, source)
+		assert.Equal(t, uint32(20), latestLedger)
+		assert.NoFileExists(t, target+".partial")
+
+		restored, err := OpenSQLiteDB(target)
+		require.NoError(t, err)
+		assertLedgerRange(t, NewLedgerReader(restored), 1, 20)
+		require.NoError(t, restored.Close())
+	}
+
+	target := filepath.Join(dir, "explicit.sqlite")
+	_, err = RestoreSnapshot(ctx, snapshotPath, target, checksumHex, passphrase, storage.ConnectOptions{})
+	require.NoError(t, err)
+	_, err = RestoreSnapshot(ctx, snapshotPath, target, checksumHex, passphrase, storage.ConnectOptions{})
+	require.ErrorContains(t, err, "already exists")
+
+	target = filepath.Join(dir, "mismatch.sqlite")
+	_, err = RestoreSnapshot(ctx, snapshotPath, target, hex.EncodeToString(make([]byte, 32)), passphrase, storage.ConnectOptions{})
+	require.ErrorContains(t, err, "checksum mismatch")
+	assert.NoFileExists(t, target)
+	assert.NoFileExists(t, target+".partial")
+
+	_, err = RestoreSnapshot(ctx, snapshotPath, target, "foo", passphrase, storage.ConnectOptions{})
+	require.ErrorContains(t, err, "invalid SHA-256 checksum")
+
+	require.NoError(t, os.Remove(snapshotPath+SnapshotChecksumSuffix))
+	_, err = RestoreSnapshot(ctx, server.URL+"/snapshot.sqlite", target, "", passphrase, storage.ConnectOptions{})
+	require.ErrorContains(t, err, "could not fetch the checksum of the snapshot")
+	assert.NoFileExists(t, target)
+
+	// the snapshot must be a database
+	notADatabase := filepath.Join(snapshotDir, "foo.sqlite")
+	require.NoError(t, os.WriteFile(notADatabase, []byte("foo"), 0o600))
+	fooChecksum := sha256.Sum256([]byte("foo"))
+	_, err = RestoreSnapshot(ctx, notADatabase, target, hex.EncodeToString(fooChecksum[:]), passphrase, storage.ConnectOptions{})
+	require.ErrorContains(t, err, "the snapshot is not a valid database")
+	assert.NoFileExists(t, target)
+}
+
+func TestPublishSnapshot(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.TODO()
+
+	_, err := PublishSnapshot(ctx, db, t.TempDir(), passphrase, storage.ConnectOptions{})
+	require.ErrorContains(t, err, "the database doesn't contain any ledger yet")
+
+	writer := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 10, 1000, passphrase)
+	write, err := writer.NewTx(ctx)
+	require.NoError(t, err)
+	for sequence := uint32(1); sequence <= 20; sequence++ {
+		require.NoError(t, write.LedgerWriter().InsertLedger(createLedger(sequence)))
+	}
+	require.NoError(t, write.Commit(20))
+
+	publishDir := filepath.Join(t.TempDir(), "snapshots")
+	manifest, err := PublishSnapshot(ctx, db, publishDir, passphrase, storage.ConnectOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "soroban-rpc-20.sqlite", manifest.Snapshot)
+	assert.Equal(t, uint32(20), manifest.LatestLedger)
+	assert.Equal(t, passphrase, manifest.NetworkPassphrase)
+	schemaMigrations, err := schemaMigrationSource().FindMigrations()
+	require.NoError(t, err)
+	assert.Equal(t, schemaMigrations[len(schemaMigrations)-1].Id, manifest.SchemaVersion)
+
+	content, err := os.ReadFile(filepath.Join(publishDir, manifest.Snapshot))
+	require.NoError(t, err)
+	checksum := sha256.Sum256(content)
+	assert.Equal(t, hex.EncodeToString(checksum[:]), manifest.Checksum)
+	assert.Equal(t, int64(len(content)), manifest.Size)
+	checksumFile, err := os.ReadFile(filepath.Join(publishDir, manifest.Snapshot+SnapshotChecksumSuffix))
+	require.NoError(t, err)
+	assert.Equal(t, manifest.Checksum+"  soroban-rpc-20.sqlite\n", string(checksumFile))
+	manifestContent, err := os.ReadFile(filepath.Join(publishDir, SnapshotManifestName))
+	require.NoError(t, err)
+	var published SnapshotManifest
+	require.NoError(t, json.Unmarshal(manifestContent, &published))
+	assert.Equal(t, manifest, published)
+
+	// new nodes can be bootstrapped from the manifest
+	dir := t.TempDir()
+	manifestPath := filepath.Join(publishDir, SnapshotManifestName)
+	latestLedger, err := RestoreSnapshot(
+		ctx, manifestPath, filepath.Join(dir, "restored.sqlite"), "", passphrase, storage.ConnectOptions{},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(20), latestLedger)
+
+	_, err = RestoreSnapshot(
+		ctx, manifestPath, filepath.Join(dir, "other.sqlite"), "", "other network", storage.ConnectOptions{},
+	)
+	require.ErrorContains(t, err, "the snapshot belongs to another network")
+
+	published.SchemaVersion = "99_future.sql"
+	manifestContent, err = json.Marshal(published)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(manifestPath, manifestContent, 0o600))
+	_, err = RestoreSnapshot(
+		ctx, manifestPath, filepath.Join(dir, "other.sqlite"), "", passphrase, storage.ConnectOptions{},
+	)
+	require.ErrorContains(t, err, `unknown schema version "99_future.sql"`)
+	assert.NoFileExists(t, filepath.Join(dir, "other.sqlite"))
+}
@@ -17,6 +17,6 @@ CREATE TABLE ledger_close_meta (
 );
 
 -- +migrate Down
-drop table ledger_entries cascade;
-drop table ledger_entries_meta cascade;
-drop table ledger_close_meta cascade;
+DROP TABLE ledger_close_meta;
+DROP TABLE metadata;
+DROP TABLE ledger_entries;
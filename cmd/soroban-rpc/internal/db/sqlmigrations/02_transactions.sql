@@ -10,4 +10,4 @@ CREATE TABLE transactions (
 CREATE INDEX index_ledger_sequence ON transactions(ledger_sequence);
 
 -- +migrate Down
-drop table transactions cascade;
+DROP TABLE transactions;
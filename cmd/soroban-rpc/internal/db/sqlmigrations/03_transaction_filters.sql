@@ -0,0 +1,20 @@
+-- +migrate Up
+
+-- columns to filter transactions without decoding their ledger close meta
+ALTER TABLE transactions ADD COLUMN successful BOOLEAN;
+ALTER TABLE transactions ADD COLUMN soroban BOOLEAN;
+ALTER TABLE transactions ADD COLUMN source_account BLOB; -- 32-byte ed25519 public key
+
+CREATE INDEX index_transactions_order ON transactions(ledger_sequence, application_order, successful, soroban);
+CREATE INDEX index_transactions_source_account ON transactions(source_account, ledger_sequence, application_order);
+
+-- populate the new columns by running the TransactionsTable data migration again
+-- (it truncates the table and indexes the transactions of the retention window)
+DELETE FROM metadata WHERE key IN ('MigrationTransactionsTableDone', 'MigrationTransactionsTableLastLedger');
+
+-- +migrate Down
+DROP INDEX index_transactions_source_account;
+DROP INDEX index_transactions_order;
+ALTER TABLE transactions DROP COLUMN source_account;
+ALTER TABLE transactions DROP COLUMN soroban;
+ALTER TABLE transactions DROP COLUMN successful;
@@ -0,0 +1,11 @@
+-- +migrate Up
+
+-- persistent ledger entries evicted from the live state into the hot archive
+-- (using the same compressed keys as ledger_entries), until they are restored
+CREATE TABLE archived_ledger_entries (
+    key BLOB NOT NULL PRIMARY KEY,
+    entry BLOB NOT NULL
+);
+
+-- +migrate Down
+DROP TABLE archived_ledger_entries;
@@ -0,0 +1,20 @@
+-- +migrate Up
+
+-- contracts touched by each transaction (through its footprint or its events),
+-- to find the transactions of a contract without decoding their ledger close meta
+CREATE TABLE transaction_contracts (
+    contract_id BLOB NOT NULL, -- 32-byte contract hash
+    ledger_sequence INTEGER NOT NULL,
+    application_order INTEGER NOT NULL,
+    PRIMARY KEY (contract_id, ledger_sequence, application_order)
+);
+
+CREATE INDEX index_transaction_contracts_ledger_sequence ON transaction_contracts(ledger_sequence);
+
+-- populate the new table by running the TransactionsTable data migration again
+-- (it truncates the tables and indexes the transactions of the retention window)
+DELETE FROM metadata WHERE key IN ('MigrationTransactionsTableDone', 'MigrationTransactionsTableLastLedger');
+
+-- +migrate Down
+DROP INDEX index_transaction_contracts_ledger_sequence;
+DROP TABLE transaction_contracts;
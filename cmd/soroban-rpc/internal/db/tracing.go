@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/stellar/go/support/db"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/tracing"
+)
+
+// tracedSession decorates a session, creating a span for every query
+type tracedSession struct {
+	db.SessionInterface
+}
+
+func newTracedSession(session db.SessionInterface) db.SessionInterface {
+	return tracedSession{SessionInterface: session}
+}
+
+func startQuerySpan(ctx context.Context, operation string, query string, sqlizer sq.Sqlizer) (context.Context, trace.Span) {
+	ctx, span := tracing.Tracer().Start(ctx, "db."+operation, trace.WithSpanKind(trace.SpanKindClient))
+	if span.IsRecording() {
+		if sqlizer != nil {
+			query, _, _ = sqlizer.ToSql()
+		}
+		span.SetAttributes(semconv.DBSystemSqlite, attribute.String("db.statement", query))
+	}
+	return ctx, span
+}
+
+func (s tracedSession) Clone() db.SessionInterface {
+	return newTracedSession(s.SessionInterface.Clone())
+}
+
+func (s tracedSession) Get(ctx context.Context, dest interface{}, query sq.Sqlizer) (err error) {
+	ctx, span := startQuerySpan(ctx, "get", "", query)
+	defer func() { tracing.End(span, err) }()
+	return s.SessionInterface.Get(ctx, dest, query)
+}
+
+func (s tracedSession) GetRaw(ctx context.Context, dest interface{}, query string, args ...interface{}) (err error) {
+	ctx, span := startQuerySpan(ctx, "get", query, nil)
+	defer func() { tracing.End(span, err) }()
+	return s.SessionInterface.GetRaw(ctx, dest, query, args...)
+}
+
+func (s tracedSession) Select(ctx context.Context, dest interface{}, query sq.Sqlizer) (err error) {
+	ctx, span := startQuerySpan(ctx, "select", "", query)
+	defer func() { tracing.End(span, err) }()
+	return s.SessionInterface.Select(ctx, dest, query)
+}
+
+func (s tracedSession) SelectRaw(ctx context.Context, dest interface{}, query string, args ...interface{}) (err error) {
+	ctx, span := startQuerySpan(ctx, "select", query, nil)
+	defer func() { tracing.End(span, err) }()
+	return s.SessionInterface.SelectRaw(ctx, dest, query, args...)
+}
+
+func (s tracedSession) Query(ctx context.Context, query sq.Sqlizer) (_ *db.Rows, err error) {
+	ctx, span := startQuerySpan(ctx, "query", "", query)
+	defer func() { tracing.End(span, err) }()
+	return s.SessionInterface.Query(ctx, query)
+}
+
+func (s tracedSession) QueryRaw(ctx context.Context, query string, args ...interface{}) (_ *db.Rows, err error) {
+	ctx, span := startQuerySpan(ctx, "query", query, nil)
+	defer func() { tracing.End(span, err) }()
+	return s.SessionInterface.QueryRaw(ctx, query, args...)
+}
+
+func (s tracedSession) Exec(ctx context.Context, query sq.Sqlizer) (_ sql.Result, err error) {
+	ctx, span := startQuerySpan(ctx, "exec", "", query)
+	defer func() { tracing.End(span, err) }()
+	return s.SessionInterface.Exec(ctx, query)
+}
+
+func (s tracedSession) ExecRaw(ctx context.Context, query string, args ...interface{}) (_ sql.Result, err error) {
+	ctx, span := startQuerySpan(ctx, "exec", query, nil)
+	defer func() { tracing.End(span, err) }()
+	return s.SessionInterface.ExecRaw(ctx, query, args...)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/network"
 	"github.com/stellar/go/support/db"
 	"github.com/stellar/go/support/log"
 	"github.com/stellar/go/xdr"
@@ -18,7 +19,13 @@ import (
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
 )
 
-const transactionTableName = "transactions"
+const (
+	transactionTableName          = "transactions"
+	transactionContractsTableName = "transaction_contracts"
+	// maxContractsInsertRows bounds the rows inserted into the transaction contracts table by a
+	// single statement, to stay within the maximum number of parameters of SQLite statements
+	maxContractsInsertRows = 1000
+)
 
 var ErrNoTransaction = errors.New("no transaction with this hash exists")
 
@@ -39,10 +46,28 @@ type TransactionWriter interface {
 	RegisterMetrics(ingest, count prometheus.Observer)
 }
 
+// TransactionFilter selects transactions by their indexed columns. Unset fields match all transactions.
+type TransactionFilter struct {
+	Successful    *bool
+	Soroban       *bool
+	SourceAccount *xdr.AccountId
+	// ContractID matches the transactions which touched the contract (see touchedContracts)
+	ContractID *xdr.Hash
+}
+
+// TransactionPosition locates a transaction in the ledgers.
+type TransactionPosition struct {
+	LedgerSequence   uint32 `db:"ledger_sequence"`
+	ApplicationOrder int32  `db:"application_order"`
+}
+
 // TransactionReader provides all the public ways to read from the DB.
 type TransactionReader interface {
 	GetTransaction(ctx context.Context, hash xdr.Hash) (Transaction, ledgerbucketwindow.LedgerRange, error)
 	GetLedgerRange(ctx context.Context) (ledgerbucketwindow.LedgerRange, error)
+	// GetTransactionPositions returns, in order, the positions of (at most limit) transactions matching
+	// the filter, starting at the given position (included).
+	GetTransactionPositions(ctx context.Context, filter TransactionFilter, start TransactionPosition, limit uint) ([]TransactionPosition, error)
 }
 
 type transactionHandler struct {
@@ -88,31 +113,64 @@ func (txn *transactionHandler) InsertTransactions(lcm xdr.LedgerCloseMeta) error
 	}
 
 	transactions := make(map[xdr.Hash]ingest.LedgerTransaction, txCount)
+	newContractsQuery := func() sq.InsertBuilder {
+		return sq.Insert(transactionContractsTableName).
+			Columns("contract_id", "ledger_sequence", "application_order")
+	}
+	contractsQuery, contractRows := newContractsQuery(), 0
 	for i := 0; i < txCount; i++ {
 		tx, err := reader.Read()
 		if err != nil {
 			return fmt.Errorf("failed reading tx %d: %w", i, err)
 		}
+		contracts, err := touchedContracts(tx)
+		if err != nil {
+			return fmt.Errorf("failed reading the contracts of tx %d: %w", i, err)
+		}
+		for _, contract := range contracts {
+			contractsQuery = contractsQuery.Values(contract[:], lcm.LedgerSequence(), tx.Index)
+			if contractRows++; contractRows == maxContractsInsertRows {
+				if _, err := contractsQuery.RunWith(txn.stmtCache).Exec(); err != nil {
+					return err
+				}
+				contractsQuery, contractRows = newContractsQuery(), 0
+			}
+		}
 
 		// For fee-bump transactions, we store lookup entries for both the outer
 		// and inner hashes.
 		if tx.Envelope.IsFeeBump() {
-			transactions[tx.Result.InnerHash()] = tx
+			innerHash, err := innerTransactionHash(tx.Envelope, txn.passphrase)
+			if err != nil {
+				return fmt.Errorf("failed hashing the inner transaction of tx %d: %w", i, err)
+			}
+			transactions[innerHash] = tx
 		}
 		transactions[tx.Result.TransactionHash] = tx
 	}
 
 	query := sq.Insert(transactionTableName).
-		Columns("hash", "ledger_sequence", "application_order")
+		Columns("hash", "ledger_sequence", "application_order", "successful", "soroban", "source_account")
 	for hash, tx := range transactions {
-		query = query.Values(hash[:], lcm.LedgerSequence(), tx.Index)
+		query = query.Values(
+			hash[:], lcm.LedgerSequence(), tx.Index,
+			tx.Result.Successful(), isSorobanTransaction(tx.Envelope), sourceAccountKey(tx.Envelope),
+		)
+	}
+	if _, err = query.RunWith(txn.stmtCache).Exec(); err != nil {
+		return err
+	}
+	if contractRows > 0 {
+		if _, err = contractsQuery.RunWith(txn.stmtCache).Exec(); err != nil {
+			return err
+		}
 	}
-	_, err = query.RunWith(txn.stmtCache).Exec()
 
-	L.WithField("duration", time.Since(start)).
+	L.WithField("ledger", lcm.LedgerSequence()).
+		WithField("duration", time.Since(start).Seconds()).
 		Infof("Ingested %d transaction lookups", len(transactions))
 
-	return err
+	return nil
 }
 
 func (txn *transactionHandler) RegisterMetrics(ingest, count prometheus.Observer) {
@@ -127,67 +185,127 @@ func (txn *transactionHandler) trimTransactions(latestLedgerSeq uint32, retentio
 	}
 
 	cutoff := latestLedgerSeq + 1 - retentionWindow
-	_, err := sq.StatementBuilder.
-		RunWith(txn.stmtCache).
-		Delete(transactionTableName).
-		Where(sq.Lt{"ledger_sequence": cutoff}).
-		Exec()
-	return err
+	for _, table := range []string{transactionTableName, transactionContractsTableName} {
+		_, err := sq.StatementBuilder.
+			RunWith(txn.stmtCache).
+			Delete(table).
+			Where(sq.Lt{"ledger_sequence": cutoff}).
+			Exec()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // GetLedgerRange pulls the min/max ledger sequence numbers from the database.
 func (txn *transactionHandler) GetLedgerRange(ctx context.Context) (ledgerbucketwindow.LedgerRange, error) {
-	var ledgerRange ledgerbucketwindow.LedgerRange
-
-	//
-	// We use subqueries alongside a UNION ALL stitch in order to select the min
-	// and max from the ledger table in a single query and get around sqlite's
-	// limitations with parentheses (see https://stackoverflow.com/a/22609948).
-	//
-	newestQ := sq.
-		Select("m1.meta").
-		FromSelect(
-			sq.
-				Select("meta").
-				From(ledgerCloseMetaTableName).
-				OrderBy("sequence ASC").
-				Limit(1),
-			"m1",
-		)
-	sql, args, err := sq.
-		Select("m2.meta").
-		FromSelect(
-			sq.
-				Select("meta").
-				From(ledgerCloseMetaTableName).
-				OrderBy("sequence DESC").
-				Limit(1),
-			"m2",
-		).ToSql()
+	ledgerRange, err := getLedgerRange(ctx, txn.db)
 	if err != nil {
-		return ledgerRange, fmt.Errorf("couldn't build ledger range query: %w", err)
+		return ledgerRange, err
+	}
+	txn.log.Debugf("Database ledger range: [%d, %d]",
+		ledgerRange.FirstLedger.Sequence, ledgerRange.LastLedger.Sequence)
+	return ledgerRange, nil
+}
+
+// isSorobanTransaction tells whether the transaction invokes a host function or
+// handles the TTLs of ledger entries.
+func isSorobanTransaction(envelope xdr.TransactionEnvelope) bool {
+	for _, op := range envelope.Operations() {
+		switch op.Body.Type {
+		case xdr.OperationTypeInvokeHostFunction, xdr.OperationTypeExtendFootprintTtl, xdr.OperationTypeRestoreFootprint:
+			return true
+		}
 	}
+	return false
+}
+
+// innerTransactionHash computes the hash of the inner transaction of a fee-bump
+// out of its envelope (the result only includes it if the inner transaction was applied).
+func innerTransactionHash(envelope xdr.TransactionEnvelope, passphrase string) (xdr.Hash, error) {
+	return network.HashTransaction(envelope.FeeBump.Tx.InnerTx.MustV1().Tx, passphrase)
+}
+
+// sourceAccountKey is the ed25519 public key of the (inner, for fee-bumps) source account
+// of the transaction, muxed accounts are reduced to their underlying account.
+func sourceAccountKey(envelope xdr.TransactionEnvelope) []byte {
+	accountID := envelope.SourceAccount().ToAccountId()
+	return accountID.Ed25519[:]
+}
 
-	var lcms []xdr.LedgerCloseMeta
-	if err = txn.db.Select(ctx, &lcms, newestQ.Suffix("UNION ALL "+sql, args...)); err != nil {
-		return ledgerRange, fmt.Errorf("couldn't query ledger range: %w", err)
-	} else if len(lcms) < 2 {
-		// There is almost certainly a row, but we want to avoid a race condition
-		// with ingestion as well as support test cases from an empty DB, so we need
-		// to sanity check that there is in fact a result. Note that no ledgers in
-		// the database isn't an error, it's just an empty range.
-		return ledgerRange, nil
+// touchedContracts returns the (distinct) contracts whose storage is in the footprint
+// of the transaction or which emitted its events, in order of appearance.
+func touchedContracts(tx ingest.LedgerTransaction) ([]xdr.Hash, error) {
+	var contracts []xdr.Hash
+	seen := map[xdr.Hash]bool{}
+	add := func(contractID *xdr.Hash) {
+		if contractID != nil && !seen[*contractID] {
+			seen[*contractID] = true
+			contracts = append(contracts, *contractID)
+		}
+	}
+	if sorobanData, ok := sorobanTransactionData(tx.Envelope); ok {
+		footprint := sorobanData.Resources.Footprint
+		for _, keys := range [][]xdr.LedgerKey{footprint.ReadOnly, footprint.ReadWrite} {
+			for _, key := range keys {
+				if key.Type == xdr.LedgerEntryTypeContractData {
+					add(key.ContractData.Contract.ContractId)
+				}
+			}
+		}
+	}
+	events, err := tx.GetDiagnosticEvents()
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		add(event.Event.ContractId)
 	}
+	return contracts, nil
+}
 
-	lcm1, lcm2 := lcms[0], lcms[1]
-	ledgerRange.FirstLedger.Sequence = lcm1.LedgerSequence()
-	ledgerRange.FirstLedger.CloseTime = lcm1.LedgerCloseTime()
-	ledgerRange.LastLedger.Sequence = lcm2.LedgerSequence()
-	ledgerRange.LastLedger.CloseTime = lcm2.LedgerCloseTime()
+// sorobanTransactionData returns the Soroban resources of the (inner, for fee-bumps) transaction
+func sorobanTransactionData(envelope xdr.TransactionEnvelope) (xdr.SorobanTransactionData, bool) {
+	switch envelope.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		return envelope.V1.Tx.Ext.GetSorobanData()
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		return envelope.FeeBump.Tx.InnerTx.V1.Tx.Ext.GetSorobanData()
+	default:
+		return xdr.SorobanTransactionData{}, false
+	}
+}
 
-	txn.log.Debugf("Database ledger range: [%d, %d]",
-		ledgerRange.FirstLedger.Sequence, ledgerRange.LastLedger.Sequence)
-	return ledgerRange, nil
+func (txn *transactionHandler) GetTransactionPositions(
+	ctx context.Context, filter TransactionFilter, start TransactionPosition, limit uint,
+) ([]TransactionPosition, error) {
+	query := sq.Select("ledger_sequence", "application_order").
+		// the hashes of fee-bumps are stored twice (outer and inner)
+		Distinct().
+		From(transactionTableName).
+		Where(sq.Expr("(ledger_sequence, application_order) >= (?, ?)", start.LedgerSequence, start.ApplicationOrder)).
+		OrderBy("ledger_sequence", "application_order").
+		Limit(uint64(limit))
+	if filter.Successful != nil {
+		query = query.Where(sq.Eq{"successful": *filter.Successful})
+	}
+	if filter.Soroban != nil {
+		query = query.Where(sq.Eq{"soroban": *filter.Soroban})
+	}
+	if filter.SourceAccount != nil {
+		query = query.Where(sq.Eq{"source_account": filter.SourceAccount.Ed25519[:]})
+	}
+	if filter.ContractID != nil {
+		query = query.
+			Join(transactionContractsTableName + " USING (ledger_sequence, application_order)").
+			Where(sq.Eq{"contract_id": filter.ContractID[:]})
+	}
+	var positions []TransactionPosition
+	if err := txn.db.Select(ctx, &positions, query); err != nil {
+		return nil, fmt.Errorf("db read failed for transaction positions: %w", err)
+	}
+	return positions, nil
 }
 
 // GetTransaction conforms to the interface in
@@ -218,7 +336,8 @@ func (txn *transactionHandler) GetTransaction(ctx context.Context, hash xdr.Hash
 
 	txn.log.
 		WithField("txhash", hex.EncodeToString(hash[:])).
-		WithField("duration", time.Since(start)).
+		WithField("ledger", lcm.LedgerSequence()).
+		WithField("duration", time.Since(start).Seconds()).
 		Debugf("Fetched and encoded transaction from ledger %d", lcm.LedgerSequence())
 
 	return tx, ledgerRange, nil
@@ -326,7 +445,7 @@ func (t *transactionTableMigration) Apply(ctx context.Context, meta xdr.LedgerCl
 }
 
 func newTransactionTableMigration(ctx context.Context, logger *log.Entry, retentionWindow uint32, passphrase string) migrationApplierFactory {
-	return migrationApplierFactoryF(func(db *DB, latestLedger uint32) (MigrationApplier, error) {
+	return migrationApplierFactoryF(func(db *DB, latestLedger uint32, lastMigratedLedger uint32) (MigrationApplier, error) {
 		firstLedgerToMigrate := uint32(2)
 		writer := &transactionHandler{
 			log:        logger,
@@ -337,14 +456,20 @@ func newTransactionTableMigration(ctx context.Context, logger *log.Entry, retent
 		if latestLedger > retentionWindow {
 			firstLedgerToMigrate = latestLedger - retentionWindow
 		}
-		// Truncate the table, since it may contain data, causing insert conflicts later on.
-		// (the migration was shipped after the actual transactions table change)
-		// FIXME: this can be simply replaced by an upper limit in the ledgers to migrate
-		//        but ... it can't be done until https://github.com/stellar/soroban-rpc/issues/208
-		//        is addressed
-		_, err := db.Exec(ctx, sq.Delete(transactionTableName))
-		if err != nil {
-			return nil, fmt.Errorf("couldn't delete table %q: %w", transactionTableName, err)
+		if lastMigratedLedger != 0 {
+			// resume the interrupted migration
+			firstLedgerToMigrate = max(firstLedgerToMigrate, lastMigratedLedger+1)
+		} else {
+			// Truncate the table, since it may contain data, causing insert conflicts later on.
+			// (the migration was shipped after the actual transactions table change)
+			// FIXME: this can be simply replaced by an upper limit in the ledgers to migrate
+			//        but ... it can't be done until https://github.com/stellar/soroban-rpc/issues/208
+			//        is addressed
+			for _, table := range []string{transactionTableName, transactionContractsTableName} {
+				if _, err := db.Exec(ctx, sq.Delete(table)); err != nil {
+					return nil, fmt.Errorf("couldn't delete table %q: %w", table, err)
+				}
+			}
 		}
 		migration := transactionTableMigration{
 			firstLedger: firstLedgerToMigrate,
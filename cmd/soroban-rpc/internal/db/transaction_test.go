@@ -71,6 +71,142 @@ func TestTransactionFound(t *testing.T) {
 	}
 }
 
+func TestFeeBumpTransactionFoundByInnerHash(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.TODO()
+	log := log.DefaultLogger
+
+	writer := NewReadWriter(log, db, interfaces.MakeNoOpDeamon(), 10, 10, passphrase)
+	write, err := writer.NewTx(ctx)
+	require.NoError(t, err)
+	lcm := txMetaFeeBump(1234)
+	require.NoError(t, write.LedgerWriter().InsertLedger(lcm))
+	require.NoError(t, write.TransactionWriter().InsertTransactions(lcm))
+	require.NoError(t, write.Commit(lcm.LedgerSequence()))
+
+	expectedEnvelope, err := lcm.TransactionEnvelopes()[0].MarshalBinary()
+	require.NoError(t, err)
+	reader := NewTransactionReader(log, db, passphrase)
+	outerHash := lcm.TransactionHash(0)
+	innerHash := txHash(1234)
+	require.NotEqual(t, outerHash, innerHash)
+	for _, hash := range []xdr.Hash{outerHash, innerHash} {
+		tx, _, err := reader.GetTransaction(ctx, hash)
+		require.NoError(t, err, "failed to find txhash %s in db", hex.EncodeToString(hash[:]))
+		assert.True(t, tx.FeeBump)
+		assert.Equal(t, expectedEnvelope, tx.Envelope)
+	}
+}
+
+func TestGetTransactionPositions(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.TODO()
+	log := log.DefaultLogger
+
+	writer := NewReadWriter(log, db, interfaces.MakeNoOpDeamon(), 10, 10, passphrase)
+	write, err := writer.NewTx(ctx)
+	require.NoError(t, err)
+	lcms := []xdr.LedgerCloseMeta{
+		txMeta(1234, true),
+		txMeta(1235, false),
+		txMetaFeeBump(1236),
+		txMeta(1237, false),
+	}
+	for _, lcm := range lcms {
+		require.NoError(t, write.LedgerWriter().InsertLedger(lcm))
+		require.NoError(t, write.TransactionWriter().InsertTransactions(lcm))
+	}
+	require.NoError(t, write.Commit(lcms[len(lcms)-1].LedgerSequence()))
+	reader := NewTransactionReader(log, db, passphrase)
+
+	successful, failed, soroban, classic := true, false, true, false
+	source := xdr.MustMuxedAddress("MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK").ToAccountId()
+	other := xdr.MustAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")
+	for _, testCase := range []struct {
+		name     string
+		filter   TransactionFilter
+		start    TransactionPosition
+		limit    uint
+		expected []uint32
+	}{
+		// the fee-bump is only returned once, even though it's indexed by both its hashes
+		{"all", TransactionFilter{}, TransactionPosition{}, 10, []uint32{1334, 1335, 1336, 1337}},
+		{"limit", TransactionFilter{}, TransactionPosition{}, 2, []uint32{1334, 1335}},
+		{"start", TransactionFilter{}, TransactionPosition{LedgerSequence: 1335, ApplicationOrder: 2}, 10, []uint32{1336, 1337}},
+		{"successful", TransactionFilter{Successful: &successful}, TransactionPosition{}, 10, []uint32{1334, 1336}},
+		{"failed", TransactionFilter{Successful: &failed}, TransactionPosition{}, 10, []uint32{1335, 1337}},
+		{"soroban", TransactionFilter{Soroban: &soroban}, TransactionPosition{}, 10, nil},
+		{"classic", TransactionFilter{Soroban: &classic}, TransactionPosition{}, 10, []uint32{1334, 1335, 1336, 1337}},
+		{"source account", TransactionFilter{SourceAccount: &source}, TransactionPosition{LedgerSequence: 1336}, 10, []uint32{1336, 1337}},
+		{"other source account", TransactionFilter{SourceAccount: &other}, TransactionPosition{}, 10, nil},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			positions, err := reader.GetTransactionPositions(ctx, testCase.filter, testCase.start, testCase.limit)
+			require.NoError(t, err)
+			var ledgers []uint32
+			for _, position := range positions {
+				assert.EqualValues(t, 1, position.ApplicationOrder)
+				ledgers = append(ledgers, position.LedgerSequence)
+			}
+			assert.Equal(t, testCase.expected, ledgers)
+		})
+	}
+}
+
+func TestGetTransactionPositionsByContract(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.TODO()
+	log := log.DefaultLogger
+
+	contractA, contractB, contractC := xdr.Hash{0xa}, xdr.Hash{0xb}, xdr.Hash{0xc}
+	writer := NewReadWriter(log, db, interfaces.MakeNoOpDeamon(), 10, 10, passphrase)
+	write, err := writer.NewTx(ctx)
+	require.NoError(t, err)
+	lcms := []xdr.LedgerCloseMeta{
+		txMetaWithContracts(1234, contractA, contractA),
+		txMeta(1235, true),
+		txMetaWithContracts(1236, contractA, contractB),
+		txMetaWithContracts(1237, contractB, contractB),
+	}
+	for _, lcm := range lcms {
+		require.NoError(t, write.LedgerWriter().InsertLedger(lcm))
+		require.NoError(t, write.TransactionWriter().InsertTransactions(lcm))
+	}
+	require.NoError(t, write.Commit(lcms[len(lcms)-1].LedgerSequence()))
+	reader := NewTransactionReader(log, db, passphrase)
+	positionLedgers := func(contractID xdr.Hash, start TransactionPosition, limit uint) []uint32 {
+		positions, err := reader.GetTransactionPositions(ctx, TransactionFilter{ContractID: &contractID}, start, limit)
+		require.NoError(t, err)
+		var ledgers []uint32
+		for _, position := range positions {
+			ledgers = append(ledgers, position.LedgerSequence)
+		}
+		return ledgers
+	}
+
+	// the contracts are found through the footprints (A in 1336) and the events (B in 1336),
+	// each transaction is only returned once even if the contract is in both (A in 1334)
+	assert.Equal(t, []uint32{1334, 1336}, positionLedgers(contractA, TransactionPosition{}, 10))
+	assert.Equal(t, []uint32{1336, 1337}, positionLedgers(contractB, TransactionPosition{}, 10))
+	assert.Equal(t, []uint32{1337}, positionLedgers(contractB, TransactionPosition{LedgerSequence: 1337}, 10))
+	assert.Equal(t, []uint32{1336}, positionLedgers(contractB, TransactionPosition{}, 1))
+	assert.Empty(t, positionLedgers(contractC, TransactionPosition{}, 10))
+
+	// the contracts are combined with the other filters
+	successful := false
+	positions, err := reader.GetTransactionPositions(
+		ctx, TransactionFilter{ContractID: &contractA, Successful: &successful}, TransactionPosition{}, 10)
+	require.NoError(t, err)
+	assert.Empty(t, positions)
+
+	// the contracts of the transactions outside of the retention window are trimmed
+	write, err = NewReadWriter(log, db, interfaces.MakeNoOpDeamon(), 10, 2, passphrase).NewTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, write.Trim(1337))
+	require.NoError(t, write.Commit(1337))
+	assert.Equal(t, []uint32{1336}, positionLedgers(contractA, TransactionPosition{}, 10))
+}
+
 func BenchmarkTransactionFetch(b *testing.B) {
 	db := NewTestDB(b)
 	ctx := context.TODO()
@@ -201,6 +337,87 @@ func txMeta(acctSeq uint32, successful bool) xdr.LedgerCloseMeta {
 	}
 }
 
+func txMetaFeeBump(acctSeq uint32) xdr.LedgerCloseMeta {
+	meta := txMeta(acctSeq, true)
+	envelope, err := xdr.NewTransactionEnvelope(xdr.EnvelopeTypeEnvelopeTypeTxFeeBump, xdr.FeeBumpTransactionEnvelope{
+		Tx: xdr.FeeBumpTransaction{
+			FeeSource: xdr.MustMuxedAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"),
+			Fee:       200,
+			InnerTx: xdr.FeeBumpTransactionInnerTx{
+				Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+				V1:   txEnvelope(acctSeq).V1,
+			},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	hash, err := network.HashTransactionInEnvelope(envelope, passphrase)
+	if err != nil {
+		panic(err)
+	}
+
+	opResults := []xdr.OperationResult{}
+	meta.V1.TxProcessing[0].Result = xdr.TransactionResultPair{
+		TransactionHash: hash,
+		Result: xdr.TransactionResult{
+			FeeCharged: 200,
+			Result: xdr.TransactionResultResult{
+				Code: xdr.TransactionResultCodeTxFeeBumpInnerSuccess,
+				InnerResultPair: &xdr.InnerTransactionResultPair{
+					TransactionHash: txHash(acctSeq),
+					Result: xdr.InnerTransactionResult{
+						FeeCharged: 100,
+						Result: xdr.InnerTransactionResultResult{
+							Code:    xdr.TransactionResultCodeTxSuccess,
+							Results: &opResults,
+						},
+					},
+				},
+			},
+		},
+	}
+	(*meta.V1.TxSet.V1TxSet.Phases[0].V0Components)[0].TxsMaybeDiscountedFee.Txs[0] = envelope
+	return meta
+}
+
+// txMetaWithContracts is a successful Soroban transaction with the instance of footprintContract
+// in its footprint, emitting an event of eventContract
+func txMetaWithContracts(acctSeq uint32, footprintContract, eventContract xdr.Hash) xdr.LedgerCloseMeta {
+	meta := txMeta(acctSeq, true)
+	envelope := txEnvelope(acctSeq)
+	envelope.V1.Tx.Ext = xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{
+		Resources: xdr.SorobanResources{Footprint: xdr.LedgerFootprint{
+			ReadOnly: []xdr.LedgerKey{{
+				Type: xdr.LedgerEntryTypeContractData,
+				ContractData: &xdr.LedgerKeyContractData{
+					Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &footprintContract},
+					Key:        xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+					Durability: xdr.ContractDataDurabilityPersistent,
+				},
+			}},
+		}},
+	}}
+	hash, err := network.HashTransactionInEnvelope(envelope, passphrase)
+	if err != nil {
+		panic(err)
+	}
+	meta.V1.TxProcessing[0].Result.TransactionHash = hash
+	meta.V1.TxProcessing[0].TxApplyProcessing.V3.SorobanMeta = &xdr.SorobanTransactionMeta{
+		Events: []xdr.ContractEvent{{
+			ContractId: &eventContract,
+			Type:       xdr.ContractEventTypeContract,
+			Body: xdr.ContractEventBody{
+				V:  0,
+				V0: &xdr.ContractEventV0{Data: xdr.ScVal{Type: xdr.ScValTypeScvVoid}},
+			},
+		}},
+		ReturnValue: xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+	}
+	(*meta.V1.TxSet.V1TxSet.Phases[0].V0Components)[0].TxsMaybeDiscountedFee.Txs[0] = envelope
+	return meta
+}
+
 func ledgerCloseTime(ledgerSequence uint32) int64 {
 	return int64(ledgerSequence)*25 + 100
 }
@@ -0,0 +1,220 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/xdr"
+)
+
+// maxReportedProblems bounds the number of problems described in a verification report
+const maxReportedProblems = 100
+
+// VerificationReport summarizes the integrity checks of the database
+type VerificationReport struct {
+	FirstLedger  uint32
+	LastLedger   uint32
+	Ledgers      uint32
+	Transactions uint64
+	// ProblemCount is the number of problems found, only the first ones are described in Problems
+	ProblemCount int
+	Problems     []string
+}
+
+func (r *VerificationReport) addProblem(format string, args ...interface{}) {
+	r.ProblemCount++
+	if len(r.Problems) < maxReportedProblems {
+		r.Problems = append(r.Problems, fmt.Sprintf(format, args...))
+	}
+}
+
+// Verify checks the integrity of the database: the stored ledgers must form
+// a contiguous and correctly hashed chain of decodable ledger close metas, and
+// the transaction lookups must match the transactions of the stored ledgers.
+// Events aren't stored separately (they are extracted from the ledger close metas).
+// A schema other than the one of this version is reported as a problem, without checking
+// the contents of the database.
+func Verify(ctx context.Context, dbConn *DB, passphrase string) (VerificationReport, error) {
+	var report VerificationReport
+	if err := CheckSchema(ctx, dbConn); errors.Is(err, ErrSchemaBehind) || errors.Is(err, ErrSchemaAhead) {
+		report.addProblem("%v", err)
+		return report, nil
+	} else if err != nil {
+		return report, err
+	}
+	query := sq.Select("sequence", "meta").From(ledgerCloseMetaTableName).OrderBy("sequence asc")
+	rows, err := dbConn.Query(ctx, query)
+	if err != nil {
+		return report, err
+	}
+	defer rows.Close()
+
+	var previous *xdr.LedgerCloseMeta
+	for rows.Next() {
+		var (
+			sequence uint32
+			meta     []byte
+		)
+		if err := rows.Scan(&sequence, &meta); err != nil {
+			return report, err
+		}
+		report.Ledgers++
+		if report.FirstLedger == 0 {
+			report.FirstLedger = sequence
+		}
+		if report.Ledgers > 1 && sequence != report.LastLedger+1 {
+			report.addProblem("ledgers [%d, %d] are missing", report.LastLedger+1, sequence-1)
+		}
+		report.LastLedger = sequence
+
+		var lcm xdr.LedgerCloseMeta
+		if err := lcm.UnmarshalBinary(meta); err != nil {
+			report.addProblem("ledger %d: cannot decode ledger close meta: %v", sequence, err)
+			previous = nil
+			continue
+		}
+		verifyLedger(&report, sequence, lcm, previous)
+		count, err := verifyTransactions(ctx, &report, dbConn, passphrase, lcm)
+		if err != nil {
+			return report, err
+		}
+		report.Transactions += count
+		previous = &lcm
+	}
+	if err := rows.Err(); err != nil {
+		return report, err
+	}
+
+	if err := verifyOrphanedTransactions(ctx, &report, dbConn); err != nil {
+		return report, err
+	}
+	latestLedger, err := getLatestLedgerSequence(ctx, dbConn, dbConn.cache)
+	switch {
+	case errors.Is(err, ErrEmptyDB):
+		if report.Ledgers > 0 {
+			report.addProblem("the latest ledger sequence is missing")
+		}
+	case err != nil:
+		return report, err
+	case latestLedger != report.LastLedger:
+		report.addProblem("the latest ledger sequence (%d) doesn't match the last stored ledger (%d)",
+			latestLedger, report.LastLedger)
+	}
+	return report, nil
+}
+
+func verifyLedger(report *VerificationReport, sequence uint32, lcm xdr.LedgerCloseMeta, previous *xdr.LedgerCloseMeta) {
+	if lcm.LedgerSequence() != sequence {
+		report.addProblem("ledger %d: the ledger close meta is for ledger %d", sequence, lcm.LedgerSequence())
+	}
+	header := lcm.LedgerHeaderHistoryEntry()
+	headerBytes, err := header.Header.MarshalBinary()
+	if err != nil {
+		report.addProblem("ledger %d: cannot encode ledger header: %v", sequence, err)
+		return
+	}
+	if hash := xdr.Hash(sha256.Sum256(headerBytes)); hash != header.Hash {
+		report.addProblem("ledger %d: hash mismatch, the header hashes to %s but %s is stored",
+			sequence, hash.HexString(), header.Hash.HexString())
+	}
+	if previous != nil && previous.LedgerSequence()+1 == sequence && previous.LedgerHash() != lcm.PreviousLedgerHash() {
+		report.addProblem("ledger %d: the previous ledger hash (%s) doesn't match the hash of ledger %d (%s)",
+			sequence, lcm.PreviousLedgerHash().HexString(), previous.LedgerSequence(), previous.LedgerHash().HexString())
+	}
+}
+
+// verifyTransactions checks that the lookups of the ledger match its transactions,
+// returning the number of transactions in the ledger
+func verifyTransactions(
+	ctx context.Context,
+	report *VerificationReport,
+	dbConn *DB,
+	passphrase string,
+	lcm xdr.LedgerCloseMeta,
+) (uint64, error) {
+	sequence := lcm.LedgerSequence()
+	expected := map[xdr.Hash]uint32{}
+	reader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(passphrase, lcm)
+	if err != nil {
+		report.addProblem("ledger %d: cannot read transactions: %v", sequence, err)
+		return 0, nil
+	}
+	var count uint64
+	for {
+		tx, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			report.addProblem("ledger %d: cannot read transaction: %v", sequence, err)
+			return count, nil
+		}
+		count++
+		if tx.Envelope.IsFeeBump() {
+			innerHash, err := innerTransactionHash(tx.Envelope, passphrase)
+			if err != nil {
+				report.addProblem("ledger %d: cannot hash inner transaction: %v", sequence, err)
+				return count, nil
+			}
+			expected[innerHash] = tx.Index
+		}
+		expected[tx.Result.TransactionHash] = tx.Index
+	}
+
+	var lookups []struct {
+		Hash             []byte `db:"hash"`
+		ApplicationOrder uint32 `db:"application_order"`
+	}
+	query := sq.Select("hash", "application_order").
+		From(transactionTableName).
+		Where(sq.Eq{"ledger_sequence": sequence})
+	if err := dbConn.Select(ctx, &lookups, query); err != nil {
+		return count, err
+	}
+	found := make(map[xdr.Hash]bool, len(lookups))
+	for _, lookup := range lookups {
+		var hash xdr.Hash
+		if len(lookup.Hash) != len(hash) {
+			report.addProblem("ledger %d: invalid transaction hash %x", sequence, lookup.Hash)
+			continue
+		}
+		copy(hash[:], lookup.Hash)
+		found[hash] = true
+		order, ok := expected[hash]
+		switch {
+		case !ok:
+			report.addProblem("ledger %d: transaction %s is not in the ledger", sequence, hash.HexString())
+		case order != lookup.ApplicationOrder:
+			report.addProblem("ledger %d: transaction %s is stored with application order %d instead of %d",
+				sequence, hash.HexString(), lookup.ApplicationOrder, order)
+		}
+	}
+	for hash := range expected {
+		if !found[hash] {
+			report.addProblem("ledger %d: transaction %s is missing", sequence, hash.HexString())
+		}
+	}
+	return count, nil
+}
+
+// verifyOrphanedTransactions checks that all the transaction lookups belong to stored ledgers
+func verifyOrphanedTransactions(ctx context.Context, report *VerificationReport, dbConn *DB) error {
+	var orphanedLedgers []uint32
+	query := sq.Select("DISTINCT t.ledger_sequence").
+		From(transactionTableName + " t").
+		LeftJoin(ledgerCloseMetaTableName + " l ON t.ledger_sequence = l.sequence").
+		Where(sq.Eq{"l.sequence": nil}).
+		OrderBy("t.ledger_sequence asc")
+	if err := dbConn.Select(ctx, &orphanedLedgers, query); err != nil {
+		return err
+	}
+	for _, sequence := range orphanedLedgers {
+		report.addProblem("transactions of ledger %d are stored without the ledger", sequence)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+// hashedLedgerChain returns ledgers with transactions whose hashes are consistent
+func hashedLedgerChain(t *testing.T, count uint32) []xdr.LedgerCloseMeta {
+	var lcms []xdr.LedgerCloseMeta
+	var previousHash xdr.Hash
+	for acctSeq := uint32(1); acctSeq <= count; acctSeq++ {
+		lcm := txMeta(acctSeq, true)
+		header := &lcm.V1.LedgerHeader
+		header.Header.PreviousLedgerHash = previousHash
+		headerBytes, err := header.Header.MarshalBinary()
+		require.NoError(t, err)
+		header.Hash = sha256.Sum256(headerBytes)
+		previousHash = header.Hash
+		lcms = append(lcms, lcm)
+	}
+	return lcms
+}
+
+func TestVerify(t *testing.T) {
+	db := NewTestDB(t)
+	ctx := context.TODO()
+
+	report, err := Verify(ctx, db, passphrase)
+	require.NoError(t, err)
+	assert.Zero(t, report.ProblemCount)
+
+	lcms := hashedLedgerChain(t, 10)
+	writer := NewReadWriter(logger, db, interfaces.MakeNoOpDeamon(), 10, 1000, passphrase)
+	write, err := writer.NewTx(ctx)
+	require.NoError(t, err)
+	for _, lcm := range lcms {
+		require.NoError(t, write.LedgerWriter().InsertLedger(lcm))
+		require.NoError(t, write.TransactionWriter().InsertTransactions(lcm))
+	}
+	require.NoError(t, write.Commit(lcms[len(lcms)-1].LedgerSequence()))
+
+	report, err = Verify(ctx, db, passphrase)
+	require.NoError(t, err)
+	assert.Equal(t, VerificationReport{
+		FirstLedger:  101,
+		LastLedger:   110,
+		Ledgers:      10,
+		Transactions: 10,
+	}, report)
+
+	// corrupt the database
+	_, err = db.Exec(ctx, sq.Delete(ledgerCloseMetaTableName).Where(sq.Eq{"sequence": 105}))
+	require.NoError(t, err)
+	_, err = db.Exec(ctx, sq.Update(ledgerCloseMetaTableName).Set("meta", []byte{1, 2, 3}).Where(sq.Eq{"sequence": 107}))
+	require.NoError(t, err)
+	_, err = db.Exec(ctx, sq.Delete(transactionTableName).Where(sq.Eq{"ledger_sequence": 102}))
+	require.NoError(t, err)
+	tamperedLedger := lcms[8]
+	tamperedLedger.V1.LedgerHeader.Header.ScpValue.CloseTime++
+	_, err = db.Exec(ctx, sq.Update(ledgerCloseMetaTableName).Set("meta", tamperedLedger).Where(sq.Eq{"sequence": 109}))
+	require.NoError(t, err)
+
+	report, err = Verify(ctx, db, passphrase)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(9), report.Ledgers)
+	tamperedHash := lcms[8].LedgerHash()
+	headerBytes, err := tamperedLedger.V1.LedgerHeader.Header.MarshalBinary()
+	require.NoError(t, err)
+	actualHash := xdr.Hash(sha256.Sum256(headerBytes))
+	assert.Equal(t, []string{
+		"ledger 102: transaction " + lcms[1].TransactionHash(0).HexString() + " is missing",
+		"ledgers [105, 105] are missing",
+		"ledger 107: cannot decode ledger close meta: decoding Int: xdr:DecodeInt: unexpected EOF while decoding 4 bytes - read: '[1 2 3]'",
+		"ledger 109: hash mismatch, the header hashes to " + actualHash.HexString() + " but " + tamperedHash.HexString() + " is stored",
+		"transactions of ledger 105 are stored without the ledger",
+	}, report.Problems)
+	assert.Equal(t, 5, report.ProblemCount)
+}
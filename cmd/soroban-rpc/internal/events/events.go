@@ -23,6 +23,11 @@ type event struct {
 	// intentionally stored as a pointer to save memory
 	// (amortized as soon as there are two events in a transaction)
 	txHash *xdr.Hash
+	// contractEvent is set for events of type contract emitted by successful contract calls,
+	// so that they can be selected without decoding the events
+	contractEvent bool
+	// failedTx is set for the (diagnostic) events of failed transactions
+	failedTx bool
 }
 
 func (e event) cursor(ledgerSeq uint32) Cursor {
@@ -92,9 +97,25 @@ type Range struct {
 	// ClampEnd indicates whether End should be clamped down
 	// to the latest ledger available if End is too high.
 	ClampEnd bool
+	// ContractEventsOnly restricts the range to events of type contract emitted
+	// by successful contract calls (i.e. excluding system and diagnostic events).
+	ContractEventsOnly bool
+	// IncludeFailed includes the diagnostic events of failed transactions,
+	// which are excluded otherwise.
+	IncludeFailed bool
 }
 
-type ScanFunction func(xdr.DiagnosticEvent, Cursor, int64, *xdr.Hash) bool
+// selects tells whether the event is part of the range (other than by its cursor)
+func (r Range) selects(e event) bool {
+	if r.ContractEventsOnly && !e.contractEvent {
+		return false
+	}
+	return r.IncludeFailed || !e.failedTx
+}
+
+// ScanFunction is applied on the scanned events, along with their cursor, ledger close time,
+// and the hash and success of their transaction.
+type ScanFunction func(event xdr.DiagnosticEvent, cursor Cursor, ledgerCloseTimestamp int64, txHash *xdr.Hash, txSuccessful bool) bool
 
 // Scan applies f on all the events occurring in the given range.
 // The events are processed in sorted ascending Cursor order.
@@ -134,12 +155,15 @@ func (m *MemoryStore) Scan(eventRange Range, f ScanFunction) (lastLedgerInWindow
 			if eventRange.End.Cmp(cur) <= 0 {
 				return
 			}
+			if !eventRange.selects(event) {
+				continue
+			}
 			var diagnosticEvent xdr.DiagnosticEvent
 			err = xdr.SafeUnmarshal(event.diagnosticEventXDR, &diagnosticEvent)
 			if err != nil {
 				return
 			}
-			if !f(diagnosticEvent, cur, timestamp, event.txHash) {
+			if !f(diagnosticEvent, cur, timestamp, event.txHash, !event.failedTx) {
 				return
 			}
 		}
@@ -147,6 +171,44 @@ func (m *MemoryStore) Scan(eventRange Range, f ScanFunction) (lastLedgerInWindow
 	return
 }
 
+// Count returns the amount of events in the given range. It's cheaper
+// than Scan, since the events aren't decoded.
+func (m *MemoryStore) Count(eventRange Range) (uint64, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if err := m.validateRange(&eventRange); err != nil {
+		return 0, err
+	}
+
+	var count uint64
+	firstLedgerInWindow := m.eventsByLedger.Get(0).LedgerSeq
+	for i := eventRange.Start.Ledger - firstLedgerInWindow; i < m.eventsByLedger.Len(); i++ {
+		bucket := m.eventsByLedger.Get(i)
+		if bucket.LedgerSeq > eventRange.End.Ledger {
+			break
+		}
+		events := bucket.BucketContent
+		if bucket.LedgerSeq == eventRange.Start.Ledger {
+			events = seek(events, eventRange.Start)
+		}
+		if bucket.LedgerSeq == eventRange.End.Ledger {
+			// exclude the events from the end of the range onwards
+			events = events[:len(events)-len(seek(events, eventRange.End))]
+		}
+		if !eventRange.ContractEventsOnly && eventRange.IncludeFailed {
+			count += uint64(len(events))
+			continue
+		}
+		for _, event := range events {
+			if eventRange.selects(event) {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
 // validateRange checks if the range falls within the bounds
 // of the events in the memory store.
 // validateRange should be called with the read lock.
@@ -244,10 +306,9 @@ func readEvents(networkPassphrase string, ledgerCloseMeta xdr.LedgerCloseMeta) (
 			return
 		}
 
-		if !tx.Result.Successful() {
-			continue
-		}
-
+		// the diagnostic events of failed transactions are kept too, since they
+		// explain the failure, but they are only scanned on request
+		failed := !tx.Result.Successful()
 		txEvents, err := tx.GetDiagnosticEvents()
 		if err != nil {
 			return nil, err
@@ -263,6 +324,8 @@ func readEvents(networkPassphrase string, ledgerCloseMeta xdr.LedgerCloseMeta) (
 				txIndex:            tx.Index,
 				eventIndex:         uint32(index),
 				txHash:             &txHash,
+				contractEvent:      !failed && e.InSuccessfulContractCall && e.Event.Type == xdr.ContractEventTypeContract,
+				failedTx:           failed,
 			})
 		}
 	}
@@ -86,7 +86,7 @@ func eventsAreEqual(t *testing.T, a, b []event) {
 
 func TestScanRangeValidation(t *testing.T) {
 	m := NewMemoryStore(interfaces.MakeNoOpDeamon(), "unit-tests", 4)
-	assertNoCalls := func(xdr.DiagnosticEvent, Cursor, int64, *xdr.Hash) bool {
+	assertNoCalls := func(xdr.DiagnosticEvent, Cursor, int64, *xdr.Hash, bool) bool {
 		t.Fatalf("unexpected call")
 		return true
 	}
@@ -373,7 +373,7 @@ func TestScan(t *testing.T) {
 			m := createStore(t)
 			var events []event
 			iterateAll := true
-			f := func(contractEvent xdr.DiagnosticEvent, cursor Cursor, ledgerCloseTimestamp int64, hash *xdr.Hash) bool {
+			f := func(contractEvent xdr.DiagnosticEvent, cursor Cursor, ledgerCloseTimestamp int64, hash *xdr.Hash, _ bool) bool {
 				require.Equal(t, ledgerCloseTime(cursor.Ledger), ledgerCloseTimestamp)
 				diagnosticEventXDR, err := contractEvent.MarshalBinary()
 				require.NoError(t, err)
@@ -389,6 +389,9 @@ func TestScan(t *testing.T) {
 			require.NoError(t, err)
 			require.Equal(t, uint32(8), latest)
 			eventsAreEqual(t, testCase.expected, events)
+			count, err := m.Count(input)
+			require.NoError(t, err)
+			require.Equal(t, uint64(len(testCase.expected)), count)
 			metric, err := m.eventsDurationMetric.MetricVec.GetMetricWith(prometheus.Labels{
 				"operation": "scan",
 			})
@@ -406,3 +409,78 @@ func TestScan(t *testing.T) {
 		}
 	}
 }
+
+func TestScanContractEventsOnly(t *testing.T) {
+	m := NewMemoryStore(interfaces.MakeNoOpDeamon(), "unit-tests", 4)
+	bucketContent := append([]event{}, ledger8Events...)
+	bucketContent[1].contractEvent = true
+	bucketContent[3].contractEvent = true
+	m.eventsByLedger.Append(ledgerbucketwindow.LedgerBucket[[]event]{
+		LedgerSeq:            8,
+		LedgerCloseTimestamp: ledger8CloseTime,
+		BucketContent:        bucketContent,
+	})
+	eventRange := Range{
+		Start:              MinCursor,
+		ClampStart:         true,
+		End:                MaxCursor,
+		ClampEnd:           true,
+		ContractEventsOnly: true,
+	}
+
+	var cursors []Cursor
+	_, err := m.Scan(eventRange, func(_ xdr.DiagnosticEvent, cursor Cursor, _ int64, _ *xdr.Hash, _ bool) bool {
+		cursors = append(cursors, cursor)
+		return true
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Cursor{bucketContent[1].cursor(8), bucketContent[3].cursor(8)}, cursors)
+
+	count, err := m.Count(eventRange)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), count)
+}
+
+func TestScanIncludeFailed(t *testing.T) {
+	m := NewMemoryStore(interfaces.MakeNoOpDeamon(), "unit-tests", 4)
+	bucketContent := append([]event{}, ledger8Events...)
+	bucketContent[0].failedTx = true
+	bucketContent[2].failedTx = true
+	m.eventsByLedger.Append(ledgerbucketwindow.LedgerBucket[[]event]{
+		LedgerSeq:            8,
+		LedgerCloseTimestamp: ledger8CloseTime,
+		BucketContent:        bucketContent,
+	})
+	eventRange := Range{
+		Start:      MinCursor,
+		ClampStart: true,
+		End:        MaxCursor,
+		ClampEnd:   true,
+	}
+
+	scan := func(eventRange Range) ([]Cursor, []bool) {
+		var cursors []Cursor
+		var successful []bool
+		_, err := m.Scan(eventRange, func(_ xdr.DiagnosticEvent, cursor Cursor, _ int64, _ *xdr.Hash, txSuccessful bool) bool {
+			cursors = append(cursors, cursor)
+			successful = append(successful, txSuccessful)
+			return true
+		})
+		require.NoError(t, err)
+		return cursors, successful
+	}
+	cursors, _ := scan(eventRange)
+	require.Len(t, cursors, len(bucketContent)-2)
+	require.NotContains(t, cursors, bucketContent[0].cursor(8))
+	count, err := m.Count(eventRange)
+	require.NoError(t, err)
+	require.Equal(t, uint64(len(bucketContent)-2), count)
+
+	eventRange.IncludeFailed = true
+	cursors, successful := scan(eventRange)
+	require.Len(t, cursors, len(bucketContent))
+	require.Equal(t, []bool{false, true, false, true, true}, successful)
+	count, err = m.Count(eventRange)
+	require.NoError(t, err)
+	require.Equal(t, uint64(len(bucketContent)), count)
+}
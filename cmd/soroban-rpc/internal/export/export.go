@@ -0,0 +1,310 @@
+// Package export exports the events and transactions stored in the database
+// to files (CSV or Parquet) with decoded columns, for offline analysis.
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/events"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/methods"
+)
+
+// Options select what is exported
+type Options struct {
+	StartLedger uint32
+	EndLedger   uint32
+	// Format is either FormatCSV or FormatParquet
+	Format string
+	// Filters select the exported events (as in getEvents), while transactions
+	// are exported when any of their events matches. No filters select everything.
+	Filters []methods.EventFilter
+}
+
+// Valid checks the options against the range of ledgers stored in the database
+func (o Options) Valid(ctx context.Context, reader db.LedgerReader) error {
+	ledgerRange, err := reader.GetLedgerRange(ctx)
+	if err != nil && !errors.Is(err, db.ErrEmptyDB) {
+		return err
+	}
+	err = db.CheckStoredLedgerRange(
+		ledgerRange.FirstLedger.Sequence, ledgerRange.LastLedger.Sequence, o.StartLedger, o.EndLedger,
+	)
+	if err != nil {
+		return err
+	}
+	for i, filter := range o.Filters {
+		if err := filter.Valid(methods.RequestLimits{}); err != nil {
+			return fmt.Errorf("filter %d invalid: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (o Options) matches(event xdr.DiagnosticEvent) bool {
+	if len(o.Filters) == 0 {
+		return true
+	}
+	for _, filter := range o.Filters {
+		if filter.Matches(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewFilter builds an event filter out of the (comma-separated) event types,
+// the contract IDs and the topics, whose segments are comma-separated and
+// either a wildcard (*) or a base64-encoded ScVal
+func NewFilter(eventTypes string, contractIDs []string, topics []string) (methods.EventFilter, error) {
+	request := map[string]interface{}{}
+	if eventTypes != "" {
+		request["type"] = eventTypes
+	}
+	if len(contractIDs) > 0 {
+		request["contractIds"] = contractIDs
+	}
+	var topicFilters [][]string
+	for _, topic := range topics {
+		topicFilters = append(topicFilters, strings.Split(topic, ","))
+	}
+	if len(topicFilters) > 0 {
+		request["topics"] = topicFilters
+	}
+	encoded, err := json.Marshal(request)
+	if err != nil {
+		return methods.EventFilter{}, err
+	}
+	var filter methods.EventFilter
+	if err := json.Unmarshal(encoded, &filter); err != nil {
+		return methods.EventFilter{}, fmt.Errorf("invalid filter: %w", err)
+	}
+	return filter, filter.Valid(methods.RequestLimits{})
+}
+
+type EventRow struct {
+	Ledger                   uint32    `parquet:"ledger"`
+	LedgerClosedAt           time.Time `parquet:"ledger_closed_at,timestamp(millisecond)"`
+	TransactionHash          string    `parquet:"transaction_hash"`
+	ID                       string    `parquet:"id"`
+	Type                     string    `parquet:"type"`
+	ContractID               string    `parquet:"contract_id"`
+	Topic                    []string  `parquet:"topic,list"`
+	Value                    string    `parquet:"value"`
+	TopicXDR                 []string  `parquet:"topic_xdr,list"`
+	ValueXDR                 string    `parquet:"value_xdr"`
+	InSuccessfulContractCall bool      `parquet:"in_successful_contract_call"`
+}
+
+type TransactionRow struct {
+	Ledger           uint32    `parquet:"ledger"`
+	LedgerClosedAt   time.Time `parquet:"ledger_closed_at,timestamp(millisecond)"`
+	ApplicationOrder uint32    `parquet:"application_order"`
+	Hash             string    `parquet:"hash"`
+	Successful       bool      `parquet:"successful"`
+	ResultCode       string    `parquet:"result_code"`
+	SourceAccount    string    `parquet:"source_account"`
+	FeeBump          bool      `parquet:"fee_bump"`
+	FeeAccount       string    `parquet:"fee_account"`
+	MaxFee           int64     `parquet:"max_fee"`
+	FeeCharged       int64     `parquet:"fee_charged"`
+	OperationCount   uint32    `parquet:"operation_count"`
+	EventCount       uint32    `parquet:"event_count"`
+	EnvelopeXDR      string    `parquet:"envelope_xdr"`
+	ResultXDR        string    `parquet:"result_xdr"`
+}
+
+var eventTypes = map[xdr.ContractEventType]string{
+	xdr.ContractEventTypeSystem:     methods.EventTypeSystem,
+	xdr.ContractEventTypeContract:   methods.EventTypeContract,
+	xdr.ContractEventTypeDiagnostic: methods.EventTypeDiagnostic,
+}
+
+// Events exports the events of the successful transactions (like getEvents)
+// in the ledger range, returning the number of exported events
+func Events(ctx context.Context, reader db.LedgerReader, passphrase string, options Options, w io.Writer) (int, error) {
+	rows, err := newRowWriter[EventRow](options.Format, w)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	exportEvents := func(ledger uint32, closedAt time.Time, tx ingest.LedgerTransaction) error {
+		if !tx.Result.Successful() {
+			return nil
+		}
+		txEvents, err := tx.GetDiagnosticEvents()
+		if err != nil {
+			return err
+		}
+		for index, event := range txEvents {
+			if !options.matches(event) {
+				continue
+			}
+			cursor := events.Cursor{Ledger: ledger, Tx: tx.Index, Event: uint32(index)}
+			row, err := newEventRow(event, cursor, closedAt, tx.Result.TransactionHash)
+			if err != nil {
+				return err
+			}
+			if err := rows.Write(row); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	}
+	if err := streamTransactions(ctx, reader, passphrase, options, exportEvents); err != nil {
+		return count, err
+	}
+	return count, rows.Close()
+}
+
+// Transactions exports the transactions in the ledger range, returning the number of exported transactions
+func Transactions(ctx context.Context, reader db.LedgerReader, passphrase string, options Options, w io.Writer) (int, error) {
+	rows, err := newRowWriter[TransactionRow](options.Format, w)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	exportTransaction := func(ledger uint32, closedAt time.Time, tx ingest.LedgerTransaction) error {
+		txEvents, err := tx.GetDiagnosticEvents()
+		if err != nil {
+			return err
+		}
+		if len(options.Filters) > 0 && !anyMatches(options, txEvents) {
+			return nil
+		}
+		row, err := newTransactionRow(ledger, closedAt, tx, len(txEvents))
+		if err != nil {
+			return err
+		}
+		if err := rows.Write(row); err != nil {
+			return err
+		}
+		count++
+		return nil
+	}
+	if err := streamTransactions(ctx, reader, passphrase, options, exportTransaction); err != nil {
+		return count, err
+	}
+	return count, rows.Close()
+}
+
+func anyMatches(options Options, txEvents []xdr.DiagnosticEvent) bool {
+	for _, event := range txEvents {
+		if options.matches(event) {
+			return true
+		}
+	}
+	return false
+}
+
+func streamTransactions(
+	ctx context.Context,
+	reader db.LedgerReader,
+	passphrase string,
+	options Options,
+	f func(ledger uint32, closedAt time.Time, tx ingest.LedgerTransaction) error,
+) error {
+	if err := options.Valid(ctx, reader); err != nil {
+		return err
+	}
+	return reader.StreamLedgerRange(ctx, options.StartLedger, options.EndLedger, func(lcm xdr.LedgerCloseMeta) error {
+		closedAt := time.Unix(lcm.LedgerCloseTime(), 0).UTC()
+		txReader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(passphrase, lcm)
+		if err != nil {
+			return fmt.Errorf("could not read transactions of ledger %d: %w", lcm.LedgerSequence(), err)
+		}
+		defer txReader.Close()
+		for {
+			tx, err := txReader.Read()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("could not read transaction of ledger %d: %w", lcm.LedgerSequence(), err)
+			}
+			if err := f(lcm.LedgerSequence(), closedAt, tx); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+func newEventRow(event xdr.DiagnosticEvent, cursor events.Cursor, closedAt time.Time, txHash xdr.Hash) (EventRow, error) {
+	v0, ok := event.Event.Body.GetV0()
+	if !ok {
+		return EventRow{}, errors.New("unknown event version")
+	}
+	eventType, ok := eventTypes[event.Event.Type]
+	if !ok {
+		return EventRow{}, fmt.Errorf("unknown XDR ContractEventType type: %d", event.Event.Type)
+	}
+	row := EventRow{
+		Ledger:                   cursor.Ledger,
+		LedgerClosedAt:           closedAt,
+		TransactionHash:          txHash.HexString(),
+		ID:                       cursor.String(),
+		Type:                     eventType,
+		Topic:                    make([]string, 0, len(v0.Topics)),
+		Value:                    v0.Data.String(),
+		TopicXDR:                 make([]string, 0, len(v0.Topics)),
+		InSuccessfulContractCall: event.InSuccessfulContractCall,
+	}
+	for _, segment := range v0.Topics {
+		encoded, err := xdr.MarshalBase64(segment)
+		if err != nil {
+			return EventRow{}, err
+		}
+		row.Topic = append(row.Topic, segment.String())
+		row.TopicXDR = append(row.TopicXDR, encoded)
+	}
+	var err error
+	if row.ValueXDR, err = xdr.MarshalBase64(v0.Data); err != nil {
+		return EventRow{}, err
+	}
+	if event.Event.ContractId != nil {
+		row.ContractID = strkey.MustEncode(strkey.VersionByteContract, (*event.Event.ContractId)[:])
+	}
+	return row, nil
+}
+
+func newTransactionRow(ledger uint32, closedAt time.Time, tx ingest.LedgerTransaction, eventCount int) (TransactionRow, error) {
+	sourceAccount := tx.Envelope.SourceAccount()
+	row := TransactionRow{
+		Ledger:           ledger,
+		LedgerClosedAt:   closedAt,
+		ApplicationOrder: tx.Index,
+		Hash:             tx.Result.TransactionHash.HexString(),
+		Successful:       tx.Result.Successful(),
+		ResultCode:       tx.Result.Result.Result.Code.String(),
+		SourceAccount:    sourceAccount.Address(),
+		FeeBump:          tx.Envelope.IsFeeBump(),
+		MaxFee:           int64(tx.Envelope.Fee()),
+		FeeCharged:       int64(tx.Result.Result.FeeCharged),
+		OperationCount:   uint32(len(tx.Envelope.Operations())),
+		EventCount:       uint32(eventCount),
+	}
+	if row.FeeBump {
+		feeAccount := tx.Envelope.FeeBumpAccount()
+		row.FeeAccount = feeAccount.Address()
+		row.MaxFee = tx.Envelope.FeeBumpFee()
+	}
+	var err error
+	if row.EnvelopeXDR, err = xdr.MarshalBase64(tx.Envelope); err != nil {
+		return TransactionRow{}, err
+	}
+	if row.ResultXDR, err = xdr.MarshalBase64(tx.Result.Result); err != nil {
+		return TransactionRow{}, err
+	}
+	return row, nil
+}
@@ -0,0 +1,207 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/methods"
+)
+
+const passphrase = "unit-tests"
+
+var contractID = xdr.Hash{0x1, 0x2}
+
+func contractEvent(topic string, value uint32) xdr.ContractEvent {
+	symbol := xdr.ScSymbol(topic)
+	data := xdr.Uint32(value)
+	return xdr.ContractEvent{
+		ContractId: &contractID,
+		Type:       xdr.ContractEventTypeContract,
+		Body: xdr.ContractEventBody{
+			V: 0,
+			V0: &xdr.ContractEventV0{
+				Topics: []xdr.ScVal{{Type: xdr.ScValTypeScvSymbol, Sym: &symbol}},
+				Data:   xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &data},
+			},
+		},
+	}
+}
+
+// ledgerWithEvents returns a ledger with a transaction emitting the given events
+func ledgerWithEvents(t *testing.T, sequence uint32, events ...xdr.ContractEvent) xdr.LedgerCloseMeta {
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: xdr.MustMuxedAddress(keypair.MustRandom().Address()),
+				Fee:           100,
+				Operations: []xdr.Operation{{
+					Body: xdr.OperationBody{
+						Type: xdr.OperationTypeInvokeHostFunction,
+						InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+							HostFunction: xdr.HostFunction{
+								Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+								InvokeContract: &xdr.InvokeContractArgs{
+									ContractAddress: xdr.ScAddress{
+										Type:       xdr.ScAddressTypeScAddressTypeContract,
+										ContractId: &contractID,
+									},
+									FunctionName: "foo",
+								},
+							},
+						},
+					},
+				}},
+			},
+		},
+	}
+	txHash, err := network.HashTransactionInEnvelope(envelope, passphrase)
+	require.NoError(t, err)
+	components := []xdr.TxSetComponent{{
+		Type: xdr.TxSetComponentTypeTxsetCompTxsMaybeDiscountedFee,
+		TxsMaybeDiscountedFee: &xdr.TxSetComponentTxsMaybeDiscountedFee{
+			Txs: []xdr.TransactionEnvelope{envelope},
+		},
+	}}
+	return xdr.LedgerCloseMeta{
+		V: 1,
+		V1: &xdr.LedgerCloseMetaV1{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+				Header: xdr.LedgerHeader{
+					ScpValue:  xdr.StellarValue{CloseTime: xdr.TimePoint(1000 + sequence)},
+					LedgerSeq: xdr.Uint32(sequence),
+				},
+			},
+			TxSet: xdr.GeneralizedTransactionSet{
+				V: 1,
+				V1TxSet: &xdr.TransactionSetV1{
+					Phases: []xdr.TransactionPhase{{V: 0, V0Components: &components}},
+				},
+			},
+			TxProcessing: []xdr.TransactionResultMeta{{
+				TxApplyProcessing: xdr.TransactionMeta{
+					V:          3,
+					Operations: &[]xdr.OperationMeta{},
+					V3: &xdr.TransactionMetaV3{
+						SorobanMeta: &xdr.SorobanTransactionMeta{
+							Events:      events,
+							ReturnValue: xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+						},
+					},
+				},
+				Result: xdr.TransactionResultPair{
+					TransactionHash: txHash,
+					Result: xdr.TransactionResult{
+						FeeCharged: 42,
+						Result: xdr.TransactionResultResult{
+							Code:    xdr.TransactionResultCodeTxSuccess,
+							Results: &[]xdr.OperationResult{},
+						},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func newTestReader(t *testing.T) db.LedgerReader {
+	dbConn, err := db.OpenSQLiteDB(filepath.Join(t.TempDir(), "db.sqlite"))
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, dbConn.Close()) })
+
+	writer := db.NewReadWriter(log.DefaultLogger, dbConn, interfaces.MakeNoOpDeamon(), 10, 1000, passphrase)
+	write, err := writer.NewTx(context.Background())
+	require.NoError(t, err)
+	for sequence := uint32(1); sequence <= 3; sequence++ {
+		lcm := ledgerWithEvents(t, sequence, contractEvent("transfer", sequence), contractEvent("mint", sequence))
+		require.NoError(t, write.LedgerWriter().InsertLedger(lcm))
+	}
+	require.NoError(t, write.Commit(3))
+	return db.NewLedgerReader(dbConn)
+}
+
+func TestExportEventsCSV(t *testing.T) {
+	reader := newTestReader(t)
+	// the transfer symbol
+	filter, err := NewFilter("contract", nil, []string{"AAAADwAAAAh0cmFuc2Zlcg=="})
+	require.NoError(t, err)
+	options := Options{
+		StartLedger: 2,
+		EndLedger:   3,
+		Format:      FormatCSV,
+		Filters:     []methods.EventFilter{filter},
+	}
+
+	var out bytes.Buffer
+	count, err := Events(context.Background(), reader, passphrase, options, &out)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	records, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, []string{
+		"ledger", "ledger_closed_at", "transaction_hash", "id", "type", "contract_id",
+		"topic", "value", "topic_xdr", "value_xdr", "in_successful_contract_call",
+	}, records[0])
+	assert.Equal(t, "2", records[1][0])
+	assert.Equal(t, "1970-01-01T00:16:42Z", records[1][1])
+	assert.Equal(t, "contract", records[1][4])
+	assert.Equal(t, `["transfer"]`, records[1][6])
+	assert.Equal(t, "2", records[1][7])
+	assert.Equal(t, `["AAAADwAAAAh0cmFuc2Zlcg=="]`, records[1][8])
+	assert.Equal(t, "true", records[1][10])
+	assert.Equal(t, "3", records[2][0])
+}
+
+func TestExportTransactionsParquet(t *testing.T) {
+	reader := newTestReader(t)
+	options := Options{StartLedger: 1, EndLedger: 3, Format: FormatParquet}
+
+	var out bytes.Buffer
+	count, err := Transactions(context.Background(), reader, passphrase, options, &out)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	rows, err := parquet.Read[TransactionRow](bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, uint32(1), rows[0].Ledger)
+	assert.Equal(t, time.Unix(1001, 0).UTC(), rows[0].LedgerClosedAt.UTC())
+	assert.True(t, rows[0].Successful)
+	assert.Equal(t, int64(100), rows[0].MaxFee)
+	assert.Equal(t, int64(42), rows[0].FeeCharged)
+	assert.Equal(t, uint32(1), rows[0].OperationCount)
+	assert.Equal(t, uint32(2), rows[0].EventCount)
+	assert.Equal(t, uint32(3), rows[2].Ledger)
+}
+
+func TestExportInvalidOptions(t *testing.T) {
+	reader := newTestReader(t)
+	var out bytes.Buffer
+	_, err := Events(context.Background(), reader, passphrase, Options{StartLedger: 2, EndLedger: 5, Format: FormatCSV}, &out)
+	require.EqualError(t, err, "ledger range [2, 5] is not within the ledgers stored in the database [1, 3]")
+	_, err = Events(context.Background(), reader, passphrase, Options{StartLedger: 1, EndLedger: 3, Format: "json"}, &out)
+	require.EqualError(t, err, `unknown format "json", it must be "csv" or "parquet"`)
+	_, err = NewFilter("", nil, []string{"*,invalid"})
+	require.Error(t, err)
+	_, err = NewFilter("", []string{"invalid"}, nil)
+	require.EqualError(t, err, "contract ID 1 invalid")
+	_, err = NewFilter("transfer", nil, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,122 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+const (
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+)
+
+// rowWriter writes the exported rows, whose columns are the fields of T
+// (named after their parquet tags)
+type rowWriter[T any] interface {
+	Write(row T) error
+	// Close flushes the rows (it doesn't close the underlying writer)
+	Close() error
+}
+
+func newRowWriter[T any](format string, w io.Writer) (rowWriter[T], error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter[T](w), nil
+	case FormatParquet:
+		return &parquetWriter[T]{writer: parquet.NewGenericWriter[T](w)}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q, it must be %q or %q", format, FormatCSV, FormatParquet)
+	}
+}
+
+type parquetWriter[T any] struct {
+	writer *parquet.GenericWriter[T]
+}
+
+func (p *parquetWriter[T]) Write(row T) error {
+	_, err := p.writer.Write([]T{row})
+	return err
+}
+
+func (p *parquetWriter[T]) Close() error {
+	return p.writer.Close()
+}
+
+type csvWriter[T any] struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func newCSVWriter[T any](w io.Writer) *csvWriter[T] {
+	return &csvWriter[T]{writer: csv.NewWriter(w)}
+}
+
+func (c *csvWriter[T]) writeHeader() error {
+	c.headerWritten = true
+	rowType := reflect.TypeOf((*T)(nil)).Elem()
+	header := make([]string, 0, rowType.NumField())
+	for i := 0; i < rowType.NumField(); i++ {
+		name, _, _ := strings.Cut(rowType.Field(i).Tag.Get("parquet"), ",")
+		header = append(header, name)
+	}
+	return c.writer.Write(header)
+}
+
+func (c *csvWriter[T]) Write(row T) error {
+	if !c.headerWritten {
+		if err := c.writeHeader(); err != nil {
+			return err
+		}
+	}
+	value := reflect.ValueOf(row)
+	record := make([]string, 0, value.NumField())
+	for i := 0; i < value.NumField(); i++ {
+		record = append(record, formatCSVValue(value.Field(i)))
+	}
+	return c.writer.Write(record)
+}
+
+func (c *csvWriter[T]) Close() error {
+	// write the header even if there are no rows
+	if !c.headerWritten {
+		if err := c.writeHeader(); err != nil {
+			return err
+		}
+	}
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func formatCSVValue(value reflect.Value) string {
+	if t, ok := value.Interface().(time.Time); ok {
+		return t.UTC().Format(time.RFC3339)
+	}
+	//nolint:exhaustive
+	switch value.Kind() {
+	case reflect.String:
+		return value.String()
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10)
+	case reflect.Slice:
+		// lists (e.g. topics) are encoded as JSON arrays
+		encoded, err := json.Marshal(value.Interface())
+		if err != nil {
+			return err.Error()
+		}
+		return string(encoded)
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
@@ -0,0 +1,133 @@
+package gossip
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	// HintKindInclusion hints that a transaction was included in a ledger.
+	HintKindInclusion = "inclusion"
+	// HintKindSubmission hints that a transaction was submitted to the network.
+	HintKindSubmission = "submission"
+)
+
+// Hint is a piece of information about a transaction, as observed by a node.
+type Hint struct {
+	// Hash is the hex-encoded transaction hash
+	Hash string `json:"hash"`
+	Kind string `json:"kind"`
+	// Status is the getTransaction status for inclusions and the
+	// sendTransaction status for submissions.
+	Status          string `json:"status"`
+	Ledger          uint32 `json:"ledger,omitempty"`
+	LedgerCloseTime int64  `json:"ledgerCloseTime,omitempty"`
+}
+
+// InclusionHints builds the inclusion hints of all the transactions in a ledger.
+// Fee-bump transactions get hints for both their outer and inner hashes.
+func InclusionHints(networkPassphrase string, lcm xdr.LedgerCloseMeta) ([]Hint, error) {
+	reader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(networkPassphrase, lcm)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var hints []Hint
+	for {
+		tx, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		hint := Hint{
+			Hash:            tx.Result.TransactionHash.HexString(),
+			Kind:            HintKindInclusion,
+			Status:          "FAILED",
+			Ledger:          lcm.LedgerSequence(),
+			LedgerCloseTime: lcm.LedgerCloseTime(),
+		}
+		if tx.Result.Successful() {
+			hint.Status = "SUCCESS"
+		}
+		hints = append(hints, hint)
+		if tx.Envelope.IsFeeBump() {
+			// the result lacks the inner hash if the inner transaction wasn't applied
+			innerHash, err := network.HashTransaction(tx.Envelope.FeeBump.Tx.InnerTx.MustV1().Tx, networkPassphrase)
+			if err != nil {
+				return nil, err
+			}
+			hint.Hash = xdr.Hash(innerHash).HexString()
+			hints = append(hints, hint)
+		}
+	}
+	return hints, nil
+}
+
+type storedHint struct {
+	Hint
+	expiresAt time.Time
+}
+
+// hintStore keeps the hints received from peers for a limited amount of time.
+type hintStore struct {
+	lock     sync.Mutex
+	hints    map[string]storedHint
+	ttl      time.Duration
+	maxHints int
+}
+
+func newHintStore(ttl time.Duration, maxHints int) *hintStore {
+	return &hintStore{
+		hints:    map[string]storedHint{},
+		ttl:      ttl,
+		maxHints: maxHints,
+	}
+}
+
+func (s *hintStore) add(now time.Time, hints ...Hint) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, hint := range hints {
+		existing, ok := s.hints[hint.Hash]
+		if ok && existing.Kind == HintKindInclusion && hint.Kind != HintKindInclusion && now.Before(existing.expiresAt) {
+			// inclusions are final, don't override them with submission statuses
+			continue
+		}
+		if !ok && len(s.hints) >= s.maxHints {
+			s.evictExpired(now)
+			if len(s.hints) >= s.maxHints {
+				continue
+			}
+		}
+		s.hints[hint.Hash] = storedHint{Hint: hint, expiresAt: now.Add(s.ttl)}
+	}
+}
+
+func (s *hintStore) evictExpired(now time.Time) {
+	for hash, hint := range s.hints {
+		if !now.Before(hint.expiresAt) {
+			delete(s.hints, hash)
+		}
+	}
+}
+
+func (s *hintStore) get(now time.Time, hash string) (Hint, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	hint, ok := s.hints[hash]
+	if !ok {
+		return Hint{}, false
+	}
+	if !now.Before(hint.expiresAt) {
+		delete(s.hints, hash)
+		return Hint{}, false
+	}
+	return hint.Hint, true
+}
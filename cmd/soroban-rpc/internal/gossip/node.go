@@ -0,0 +1,242 @@
+package gossip
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/support/log"
+)
+
+const (
+	hintsPath          = "/v1/hints"
+	maxHintsBodySize   = 4 * 1024 * 1024
+	maxStoredHints     = 100_000
+	maxHintsPerBatch   = 1000
+	hintQueueCapacity  = 10_000
+	batchFlushInterval = 500 * time.Millisecond
+	peerRequestTimeout = 2 * time.Second
+)
+
+// Config configures a gossip node.
+type Config struct {
+	// ListenEndpoint is the address on which to receive hints from peers
+	ListenEndpoint string
+	// PeerURLs are the (https) URLs of the peers' listen endpoints
+	PeerURLs []string
+	// CertFile and KeyFile are the certificate presented to peers, both when
+	// serving and when pushing hints.
+	CertFile string
+	KeyFile  string
+	// CAFile is the certificate authority used to authenticate the peers
+	CAFile string
+	// HintTTL is the period of time during which hints received from peers are kept
+	HintTTL time.Duration
+	Logger  *log.Entry
+}
+
+// Node shares transaction hints with its peers over mutually-authenticated TLS.
+//
+// All the methods are safe to call on a nil Node (i.e. when gossip is disabled).
+type Node struct {
+	logger   *log.Entry
+	store    *hintStore
+	peerURLs []string
+	client   *http.Client
+	listener net.Listener
+	server   *http.Server
+	queue    chan Hint
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+func loadTLSConfigs(cfg Config) (*tls.Config, *tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not load peer certificate: %w", err)
+	}
+	caPEM, err := os.ReadFile(cfg.CAFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read peer CA file: %w", err)
+	}
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caPEM) {
+		return nil, nil, fmt.Errorf("no certificates found in peer CA file %s", cfg.CAFile)
+	}
+	serverTLS := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    caPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}
+	clientTLS := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      caPool,
+		MinVersion:   tls.VersionTLS12,
+	}
+	return serverTLS, clientTLS, nil
+}
+
+// NewNode creates a gossip node, listening on the configured endpoint.
+func NewNode(cfg Config) (*Node, error) {
+	serverTLS, clientTLS, err := loadTLSConfigs(cfg)
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", cfg.ListenEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("could not listen on peer endpoint: %w", err)
+	}
+	node := &Node{
+		logger:   cfg.Logger,
+		store:    newHintStore(cfg.HintTTL, maxStoredHints),
+		peerURLs: cfg.PeerURLs,
+		client: &http.Client{
+			Timeout:   peerRequestTimeout,
+			Transport: &http.Transport{TLSClientConfig: clientTLS},
+		},
+		listener: tls.NewListener(listener, serverTLS),
+		queue:    make(chan Hint, hintQueueCapacity),
+		done:     make(chan struct{}),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(hintsPath, node.receiveHints)
+	node.server = &http.Server{Handler: mux, ReadHeaderTimeout: peerRequestTimeout}
+	return node, nil
+}
+
+// Addr returns the address the node is listening on.
+func (n *Node) Addr() net.Addr {
+	return n.listener.Addr()
+}
+
+// Start starts serving peers and pushing hints to them.
+func (n *Node) Start() {
+	if n == nil {
+		return
+	}
+	n.wg.Add(2)
+	go func() {
+		defer n.wg.Done()
+		if err := n.server.Serve(n.listener); !errors.Is(err, http.ErrServerClosed) {
+			n.logger.WithError(err).Error("gossip server encountered an error")
+		}
+	}()
+	go func() {
+		defer n.wg.Done()
+		n.pushLoop()
+	}()
+}
+
+// Close stops the node.
+func (n *Node) Close() error {
+	if n == nil {
+		return nil
+	}
+	close(n.done)
+	ctx, cancel := context.WithTimeout(context.Background(), peerRequestTimeout)
+	defer cancel()
+	err := n.server.Shutdown(ctx)
+	n.wg.Wait()
+	return err
+}
+
+// Publish queues hints to be pushed to the peers. Hints are dropped if the
+// queue is full.
+func (n *Node) Publish(hints ...Hint) {
+	if n == nil {
+		return
+	}
+	for _, hint := range hints {
+		select {
+		case n.queue <- hint:
+		default:
+			n.logger.Debug("gossip queue is full, dropping hint")
+			return
+		}
+	}
+}
+
+// Lookup returns the latest hint received from the peers about a transaction.
+func (n *Node) Lookup(hash string) (Hint, bool) {
+	if n == nil {
+		return Hint{}, false
+	}
+	return n.store.get(time.Now(), strings.ToLower(hash))
+}
+
+func (n *Node) receiveHints(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var hints []Hint
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxHintsBodySize)).Decode(&hints); err != nil {
+		http.Error(w, "invalid hints: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	for i := range hints {
+		hints[i].Hash = strings.ToLower(hints[i].Hash)
+	}
+	n.store.add(time.Now(), hints...)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (n *Node) pushLoop() {
+	ticker := time.NewTicker(batchFlushInterval)
+	defer ticker.Stop()
+	batch := make([]Hint, 0, maxHintsPerBatch)
+	for {
+		select {
+		case <-n.done:
+			return
+		case hint := <-n.queue:
+			batch = append(batch, hint)
+			if len(batch) < maxHintsPerBatch {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		n.push(batch)
+		batch = batch[:0]
+	}
+}
+
+func (n *Node) push(hints []Hint) {
+	body, err := json.Marshal(hints)
+	if err != nil {
+		n.logger.WithError(err).Error("could not encode hints")
+		return
+	}
+	var wg sync.WaitGroup
+	for _, peerURL := range n.peerURLs {
+		wg.Add(1)
+		go func(peerURL string) {
+			defer wg.Done()
+			url := strings.TrimSuffix(peerURL, "/") + hintsPath
+			resp, err := n.client.Post(url, "application/json", bytes.NewReader(body))
+			if err != nil {
+				n.logger.WithError(err).WithField("peer", peerURL).Warn("could not push hints to peer")
+				return
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != http.StatusNoContent {
+				n.logger.WithField("peer", peerURL).Warnf("peer rejected hints with status %d", resp.StatusCode)
+			}
+		}(peerURL)
+	}
+	wg.Wait()
+}
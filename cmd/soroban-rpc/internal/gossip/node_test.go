@@ -0,0 +1,163 @@
+package gossip
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	file string
+}
+
+func writePEM(t *testing.T, path string, blockType string, der []byte) {
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+}
+
+func newTestCA(t *testing.T, dir string, name string) testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	file := filepath.Join(dir, name+"-ca.pem")
+	writePEM(t, file, "CERTIFICATE", der)
+	return testCA{cert: cert, key: key, file: file}
+}
+
+// issue creates a certificate (valid both for clients and for 127.0.0.1) signed by the CA
+func (ca testCA) issue(t *testing.T, dir string, name string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	certFile := filepath.Join(dir, name+".pem")
+	keyFile := filepath.Join(dir, name+"-key.pem")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile
+}
+
+func newTestNode(t *testing.T, ca testCA, name string, peerURLs ...string) *Node {
+	certFile, keyFile := ca.issue(t, t.TempDir(), name)
+	node, err := NewNode(Config{
+		ListenEndpoint: "127.0.0.1:0",
+		PeerURLs:       peerURLs,
+		CertFile:       certFile,
+		KeyFile:        keyFile,
+		CAFile:         ca.file,
+		HintTTL:        time.Minute,
+		Logger:         log.DefaultLogger,
+	})
+	require.NoError(t, err)
+	node.Start()
+	t.Cleanup(func() {
+		require.NoError(t, node.Close())
+	})
+	return node
+}
+
+func TestNodeGossip(t *testing.T) {
+	ca := newTestCA(t, t.TempDir(), "test")
+	receiver := newTestNode(t, ca, "receiver")
+	sender := newTestNode(t, ca, "sender", "https://"+receiver.Addr().String())
+
+	sender.Publish(Hint{Hash: "AB", Kind: HintKindSubmission, Status: "PENDING"})
+	require.Eventually(t, func() bool {
+		_, ok := receiver.Lookup("ab")
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+	hint, _ := receiver.Lookup("ab")
+	assert.Equal(t, "PENDING", hint.Status)
+
+	sender.Publish(Hint{Hash: "ab", Kind: HintKindInclusion, Status: "SUCCESS", Ledger: 10})
+	require.Eventually(t, func() bool {
+		hint, _ := receiver.Lookup("ab")
+		return hint.Kind == HintKindInclusion
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// submissions don't override inclusions
+	sender.Publish(Hint{Hash: "ab", Kind: HintKindSubmission, Status: "DUPLICATE"})
+	sender.Publish(Hint{Hash: "cd", Kind: HintKindSubmission, Status: "PENDING"})
+	require.Eventually(t, func() bool {
+		_, ok := receiver.Lookup("cd")
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+	hint, _ = receiver.Lookup("ab")
+	assert.Equal(t, Hint{Hash: "ab", Kind: HintKindInclusion, Status: "SUCCESS", Ledger: 10}, hint)
+}
+
+func TestNodeRejectsUnauthenticatedPeers(t *testing.T) {
+	ca := newTestCA(t, t.TempDir(), "test")
+	receiver := newTestNode(t, ca, "receiver")
+
+	// peers must present a certificate
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		InsecureSkipVerify: true, //nolint:gosec
+	}}}
+	_, err := client.Post("https://"+receiver.Addr().String()+hintsPath, "application/json", strings.NewReader("[]"))
+	require.Error(t, err)
+
+	// signed by the configured authority
+	otherCA := newTestCA(t, t.TempDir(), "other")
+	attacker := newTestNode(t, otherCA, "attacker", "https://"+receiver.Addr().String())
+	attacker.Publish(Hint{Hash: "ab", Kind: HintKindInclusion, Status: "SUCCESS"})
+	time.Sleep(2 * batchFlushInterval)
+	_, ok := receiver.Lookup("ab")
+	assert.False(t, ok)
+}
+
+func TestHintStoreExpiration(t *testing.T) {
+	store := newHintStore(time.Minute, 2)
+	now := time.Now()
+	store.add(now, Hint{Hash: "a"}, Hint{Hash: "b"}, Hint{Hash: "c"})
+	_, ok := store.get(now, "c")
+	assert.False(t, ok, "the store is bounded")
+
+	_, ok = store.get(now.Add(time.Minute), "a")
+	assert.False(t, ok, "hints expire")
+
+	store.add(now.Add(time.Minute), Hint{Hash: "c"})
+	_, ok = store.get(now.Add(time.Minute), "c")
+	assert.True(t, ok, "expired hints are evicted to make room")
+}
@@ -0,0 +1,323 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/methods"
+)
+
+// maxJoinsPerQuery bounds the amount of additional calls which a single query
+// can trigger through joined fields.
+const maxJoinsPerQuery = 200
+
+// Caller performs the JSON-RPC calls used to resolve the queries.
+type Caller interface {
+	CallResult(ctx context.Context, method string, params, result any) error
+}
+
+type rootField struct {
+	method   string
+	response reflect.Type
+}
+
+// rootFields maps the top-level query fields to the JSON-RPC methods
+// resolving them, and to the types of their responses (which determine the
+// fields that can be selected). The field arguments are passed as the method
+// parameters.
+var rootFields = map[string]rootField{
+	"health":               {"getHealth", reflect.TypeOf(methods.HealthCheckResult{})},
+	"network":              {"getNetwork", reflect.TypeOf(methods.GetNetworkResponse{})},
+	"versionInfo":          {"getVersionInfo", reflect.TypeOf(methods.GetVersionInfoResponse{})},
+	"latestLedger":         {"getLatestLedger", reflect.TypeOf(methods.GetLatestLedgerResponse{})},
+	"feeStats":             {"getFeeStats", reflect.TypeOf(methods.GetFeeStatsResult{})},
+	"sorobanConfig":        {"getSorobanConfig", reflect.TypeOf(methods.GetSorobanConfigResponse{})},
+	"ledgerEntries":        {"getLedgerEntries", reflect.TypeOf(methods.GetLedgerEntriesResponse{})},
+	"contractEntries":      {"getContractEntries", reflect.TypeOf(methods.GetContractEntriesResponse{})},
+	"transaction":          {"getTransaction", reflect.TypeOf(methods.GetTransactionResponse{})},
+	"transactions":         {"getTransactions", reflect.TypeOf(methods.GetTransactionsResponse{})},
+	"contractTransactions": {"getContractTransactions", reflect.TypeOf(methods.GetTransactionsResponse{})},
+	"events":               {"getEvents", reflect.TypeOf(methods.GetEventsResponse{})},
+	"event":                {"getEvent", reflect.TypeOf(methods.GetEventResponse{})},
+}
+
+// join is a field which can be selected on the objects returned by the
+// methods above without being part of the JSON-RPC response. It is resolved
+// by issuing an additional call, keyed by a field of the enclosing object.
+type join struct {
+	keyField string
+	method   string
+	param    string
+	response reflect.Type
+}
+
+var joins = map[string]join{
+	// events can be joined to the transaction which emitted them
+	"transaction": {
+		keyField: "txHash",
+		method:   "getTransaction",
+		param:    "hash",
+		response: reflect.TypeOf(methods.GetTransactionResponse{}),
+	},
+}
+
+// fieldError is an error resolving a nested field, located by its path
+// (relative to the root field).
+type fieldError struct {
+	path    []string
+	message string
+}
+
+func (e *fieldError) Error() string {
+	return e.message
+}
+
+// Error is a GraphQL error, as included in the response.
+type Error struct {
+	Message string   `json:"message"`
+	Path    []string `json:"path,omitempty"`
+}
+
+// Response is a GraphQL response.
+type Response struct {
+	Data   *Object `json:"data"`
+	Errors []Error `json:"errors,omitempty"`
+}
+
+// Object is a JSON object which preserves the order of its fields, as
+// mandated for GraphQL responses.
+type Object struct {
+	keys   []string
+	values map[string]any
+}
+
+func newObject() *Object {
+	return &Object{values: map[string]any{}}
+}
+
+// Set sets the value of a field.
+func (o *Object) Set(key string, value any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// Get obtains the value of a field.
+func (o *Object) Get(key string) (any, bool) {
+	value, ok := o.values[key]
+	return value, ok
+}
+
+func (o *Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		keyJSON, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(keyJSON)
+		buf.WriteByte(':')
+		valueJSON, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(valueJSON)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Executor resolves queries by calling the underlying JSON-RPC methods and
+// projecting their responses onto the requested selection sets.
+type Executor struct {
+	caller Caller
+}
+
+// NewExecutor creates a new query executor.
+func NewExecutor(caller Caller) *Executor {
+	return &Executor{caller: caller}
+}
+
+type execution struct {
+	ctx       context.Context
+	caller    Caller
+	variables map[string]any
+	joins     int
+}
+
+// Execute runs a parsed query. Errors resolving a root field don't prevent
+// resolving the rest of them.
+func (e *Executor) Execute(ctx context.Context, fields []Field, variables map[string]any) Response {
+	exec := &execution{ctx: ctx, caller: e.caller, variables: variables}
+	response := Response{Data: newObject()}
+	for _, field := range fields {
+		key := field.ResponseKey()
+		value, err := exec.resolveRoot(field)
+		if err != nil {
+			path := []string{key}
+			var fieldErr *fieldError
+			if errors.As(err, &fieldErr) {
+				path = append(path, fieldErr.path...)
+			}
+			response.Errors = append(response.Errors, Error{Message: err.Error(), Path: path})
+			response.Data.Set(key, nil)
+			continue
+		}
+		response.Data.Set(key, value)
+	}
+	return response
+}
+
+func (e *execution) resolveRoot(field Field) (any, error) {
+	if field.Name == "__typename" {
+		return "Query", nil
+	}
+	root, ok := rootFields[field.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q", field.Name)
+	}
+	params := e.arguments(field.Arguments)
+	return e.call(root.method, params, field.SelectionSet, root.response, nil)
+}
+
+func (e *execution) call(
+	method string, params map[string]any, selectionSet []Field, response reflect.Type, path []string,
+) (any, error) {
+	var raw json.RawMessage
+	var callParams any
+	if len(params) > 0 {
+		callParams = params
+	}
+	if err := e.caller.CallResult(e.ctx, method, callParams, &raw); err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	// preserve the precision of large integers
+	decoder.UseNumber()
+	var result any
+	if err := decoder.Decode(&result); err != nil {
+		return nil, fmt.Errorf("could not decode %s response: %w", method, err)
+	}
+	return e.project(result, selectionSet, response, path)
+}
+
+func (e *execution) arguments(args map[string]any) map[string]any {
+	if len(args) == 0 {
+		return nil
+	}
+	result := make(map[string]any, len(args))
+	for name, value := range args {
+		result[name] = e.resolveVariables(value)
+	}
+	return result
+}
+
+func (e *execution) resolveVariables(value any) any {
+	switch v := value.(type) {
+	case Variable:
+		return e.variables[string(v)]
+	case []any:
+		resolved := make([]any, len(v))
+		for i, item := range v {
+			resolved[i] = e.resolveVariables(item)
+		}
+		return resolved
+	case map[string]any:
+		resolved := make(map[string]any, len(v))
+		for key, item := range v {
+			resolved[key] = e.resolveVariables(item)
+		}
+		return resolved
+	default:
+		return value
+	}
+}
+
+// project keeps the fields of value which are present in the selection set (in
+// the order of the selection set). A missing selection set selects the full value.
+// The fields are validated against typ, the Go type value was encoded from.
+func (e *execution) project(value any, selectionSet []Field, typ reflect.Type, path []string) (any, error) {
+	if len(selectionSet) == 0 {
+		return value, nil
+	}
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			projected, err := e.project(item, selectionSet, typ, path)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = projected
+		}
+		return result, nil
+	case map[string]any:
+		known := fieldsOf(typ)
+		result := newObject()
+		for _, field := range selectionSet {
+			projected, err := e.projectField(v, field, known, appendPath(path, field.ResponseKey()))
+			if err != nil {
+				return nil, err
+			}
+			result.Set(field.ResponseKey(), projected)
+		}
+		return result, nil
+	default:
+		return nil, &fieldError{path: path, message: "cannot select fields on scalar value"}
+	}
+}
+
+// projectField resolves a field of object. known holds the fields which the
+// object can have (nil if they aren't known), so that fields omitted from the
+// response resolve to null while fields which are neither known nor joins are
+// reported as errors.
+func (e *execution) projectField(
+	object map[string]any, field Field, known map[string]reflect.Type, path []string,
+) (any, error) {
+	fieldType, isKnown := known[field.Name]
+	if isKnown {
+		return e.project(object[field.Name], field.SelectionSet, fieldType, path)
+	}
+	j, isJoin := joins[field.Name]
+	if isJoin && known != nil {
+		// joins only apply to the objects holding their key
+		_, isJoin = known[j.keyField]
+	}
+	if !isJoin {
+		if known == nil {
+			return e.project(object[field.Name], field.SelectionSet, nil, path)
+		}
+		return nil, &fieldError{path: path, message: fmt.Sprintf("unknown field %q", field.Name)}
+	}
+	key, ok := object[j.keyField].(string)
+	if !ok {
+		// the object has nothing to join to
+		return nil, nil
+	}
+	if e.joins >= maxJoinsPerQuery {
+		return nil, &fieldError{path: path, message: fmt.Sprintf("too many joined fields in query (max %d)", maxJoinsPerQuery)}
+	}
+	e.joins++
+	params := e.arguments(field.Arguments)
+	if params == nil {
+		params = map[string]any{}
+	}
+	params[j.param] = key
+	return e.call(j.method, params, field.SelectionSet, j.response, path)
+}
+
+func appendPath(path []string, key string) []string {
+	result := make([]string, 0, len(path)+1)
+	return append(append(result, path...), key)
+}
@@ -0,0 +1,104 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type call struct {
+	method string
+	params any
+}
+
+type fakeCaller struct {
+	calls     []call
+	responses map[string]string
+}
+
+func (f *fakeCaller) CallResult(ctx context.Context, method string, params, result any) error {
+	f.calls = append(f.calls, call{method, params})
+	response, ok := f.responses[method]
+	if !ok {
+		return errors.New("method not found")
+	}
+	return json.Unmarshal([]byte(response), result)
+}
+
+func TestExecute(t *testing.T) {
+	caller := &fakeCaller{responses: map[string]string{
+		"getEvents":      `{"events": [{"id": "1", "topic": ["AAAA"], "txHash": "abcd"}], "latestLedger": 12345678901234567890}`,
+		"getTransaction": `{"status": "SUCCESS", "ledger": 10}`,
+	}}
+	fields, err := ParseQuery(`query($start: Int) {
+		events(startLedger: $start) {
+			latestLedger
+			events { topic id transaction { status } }
+		}
+		unknown
+	}`)
+	require.NoError(t, err)
+	response := NewExecutor(caller).Execute(context.Background(), fields, map[string]any{"start": 10})
+
+	encoded, err := json.Marshal(response)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"data": {
+			"events": {
+				"latestLedger": 12345678901234567890,
+				"events": [{"topic": ["AAAA"], "id": "1", "transaction": {"status": "SUCCESS"}}]
+			},
+			"unknown": null
+		},
+		"errors": [{"message": "unknown field \"unknown\"", "path": ["unknown"]}]
+	}`, string(encoded))
+	// the order of the selection set is preserved
+	assert.Contains(t, string(encoded), `{"topic":["AAAA"],"id":"1","transaction":{"status":"SUCCESS"}}`)
+
+	assert.Equal(t, []call{
+		{"getEvents", map[string]any{"startLedger": 10}},
+		{"getTransaction", map[string]any{"hash": "abcd"}},
+	}, caller.calls)
+}
+
+func TestExecuteFieldErrors(t *testing.T) {
+	caller := &fakeCaller{responses: map[string]string{
+		"getEvents":  `{"events": [{"id": "1"}], "latestLedger": 10}`,
+		"getHealth":  `{"status": "healthy"}`,
+		"getNetwork": `{"passphrase": "test"}`,
+	}}
+	fields, err := ParseQuery(`{
+		events { cursor events { id txHash transaction { status } } }
+		health { status transaction { status } }
+		network { passphrase friendbotUrl bogus }
+	}`)
+	require.NoError(t, err)
+	response := NewExecutor(caller).Execute(context.Background(), fields, nil)
+
+	encoded, err := json.Marshal(response)
+	require.NoError(t, err)
+	// fields omitted from the responses and joins without a key resolve to null
+	assert.JSONEq(t, `{
+		"data": {
+			"events": {"cursor": null, "events": [{"id": "1", "txHash": null, "transaction": null}]},
+			"health": null,
+			"network": null
+		},
+		"errors": [
+			{"message": "unknown field \"transaction\"", "path": ["health", "transaction"]},
+			{"message": "unknown field \"bogus\"", "path": ["network", "bogus"]}
+		]
+	}`, string(encoded))
+	assert.Equal(t, []call{{"getEvents", nil}, {"getHealth", nil}, {"getNetwork", nil}}, caller.calls)
+}
+
+func TestExecuteCallError(t *testing.T) {
+	fields, err := ParseQuery(`{ health { status } }`)
+	require.NoError(t, err)
+	response := NewExecutor(&fakeCaller{}).Execute(context.Background(), fields, nil)
+	assert.Equal(t, []Error{{Message: "method not found", Path: []string{"health"}}}, response.Errors)
+}
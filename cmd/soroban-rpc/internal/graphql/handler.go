@@ -0,0 +1,58 @@
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/stellar/go/support/log"
+)
+
+// Path is where the GraphQL endpoint is served
+const Path = "/graphql"
+
+// Request is a GraphQL request, as sent over HTTP.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// NewHTTPHandler returns an HTTP handler serving GraphQL queries, both as POST
+// requests with a JSON body and as GET requests with URL query parameters.
+func NewHTTPHandler(executor *Executor, logger *log.Entry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request Request
+		switch r.Method {
+		case http.MethodPost:
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				http.Error(w, "invalid GraphQL request body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		case http.MethodGet:
+			request.Query = r.URL.Query().Get("query")
+			if variables := r.URL.Query().Get("variables"); variables != "" {
+				if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+					http.Error(w, "invalid GraphQL variables: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var response Response
+		fields, err := ParseQuery(request.Query)
+		if err != nil {
+			response.Errors = []Error{{Message: err.Error()}}
+		} else {
+			response = executor.Execute(r.Context(), fields, request.Variables)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			logger.WithError(err).Warn("could not write GraphQL response")
+		}
+	})
+}
@@ -0,0 +1,360 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Field is a field selection of a GraphQL query, e.g.
+// `alias: name(arg: value) { subfields }`
+type Field struct {
+	Alias        string
+	Name         string
+	Arguments    map[string]any
+	SelectionSet []Field
+}
+
+// ResponseKey is the key used for the field in the response.
+func (f Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// Variable is a reference to a query variable (i.e. `$name`) used as an
+// argument value, it is resolved at execution time.
+type Variable string
+
+// ParseQuery parses a GraphQL query document and returns the selection set of
+// its (single) query operation.
+//
+// Only the subset of the language required for read queries is supported:
+// an optional `query` keyword (with optional name and variable definitions),
+// nested selection sets, aliases and arguments. Fragments, directives,
+// mutations and subscriptions are not supported.
+func ParseQuery(query string) ([]Field, error) {
+	p := &parser{input: query}
+	p.skipIgnored()
+	if p.peekName() {
+		keyword := p.name()
+		if keyword != "query" {
+			return nil, p.errorf("unsupported operation %q", keyword)
+		}
+		p.skipIgnored()
+		if p.peekName() {
+			p.name()
+			p.skipIgnored()
+		}
+		if p.peek() == '(' {
+			if err := p.skipVariableDefinitions(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	fields, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	p.skipIgnored()
+	if !p.eof() {
+		return nil, p.errorf("unexpected %q after query", p.peek())
+	}
+	return fields, nil
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("syntax error at position %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+// skipIgnored skips whitespace, commas and comments (which are all
+// insignificant in GraphQL).
+func (p *parser) skipIgnored() {
+	for !p.eof() {
+		c := p.peek()
+		switch {
+		case c == '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.pos++
+			}
+		case c == ',' || unicode.IsSpace(rune(c)):
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) expect(c byte) error {
+	p.skipIgnored()
+	if p.peek() != c {
+		if p.eof() {
+			return p.errorf("expected %q, found end of query", c)
+		}
+		return p.errorf("expected %q, found %q", c, p.peek())
+	}
+	p.pos++
+	return nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameContinue(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
+
+func (p *parser) peekName() bool {
+	return !p.eof() && isNameStart(p.peek())
+}
+
+func (p *parser) name() string {
+	start := p.pos
+	for !p.eof() && isNameContinue(p.peek()) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *parser) skipVariableDefinitions() error {
+	// Variable types aren't enforced, we just skip over the definitions
+	depth := 0
+	for !p.eof() {
+		switch p.peek() {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				p.pos++
+				p.skipIgnored()
+				return nil
+			}
+		}
+		p.pos++
+	}
+	return p.errorf("unterminated variable definitions")
+}
+
+func (p *parser) selectionSet() ([]Field, error) {
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+	var fields []Field
+	for {
+		p.skipIgnored()
+		if p.peek() == '}' {
+			p.pos++
+			break
+		}
+		if p.peek() == '.' {
+			return nil, p.errorf("fragments are not supported")
+		}
+		field, err := p.field()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) == 0 {
+		return nil, p.errorf("empty selection set")
+	}
+	return fields, nil
+}
+
+func (p *parser) field() (Field, error) {
+	var field Field
+	if !p.peekName() {
+		if p.eof() {
+			return field, p.errorf("expected field name, found end of query")
+		}
+		return field, p.errorf("expected field name, found %q", p.peek())
+	}
+	field.Name = p.name()
+	p.skipIgnored()
+	if p.peek() == ':' {
+		p.pos++
+		p.skipIgnored()
+		if !p.peekName() {
+			return field, p.errorf("expected field name after alias %q", field.Name)
+		}
+		field.Alias = field.Name
+		field.Name = p.name()
+		p.skipIgnored()
+	}
+	if p.peek() == '(' {
+		args, err := p.arguments()
+		if err != nil {
+			return field, err
+		}
+		field.Arguments = args
+		p.skipIgnored()
+	}
+	if p.peek() == '@' {
+		return field, p.errorf("directives are not supported")
+	}
+	if p.peek() == '{' {
+		selectionSet, err := p.selectionSet()
+		if err != nil {
+			return field, err
+		}
+		field.SelectionSet = selectionSet
+	}
+	return field, nil
+}
+
+func (p *parser) arguments() (map[string]any, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	args := map[string]any{}
+	for {
+		p.skipIgnored()
+		if p.peek() == ')' {
+			p.pos++
+			return args, nil
+		}
+		if !p.peekName() {
+			return nil, p.errorf("expected argument name")
+		}
+		name := p.name()
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		args[name] = value
+	}
+}
+
+func (p *parser) value() (any, error) {
+	p.skipIgnored()
+	c := p.peek()
+	switch {
+	case c == '$':
+		p.pos++
+		if !p.peekName() {
+			return nil, p.errorf("expected variable name")
+		}
+		return Variable(p.name()), nil
+	case c == '"':
+		return p.stringValue()
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.numberValue()
+	case c == '[':
+		p.pos++
+		list := []any{}
+		for {
+			p.skipIgnored()
+			if p.peek() == ']' {
+				p.pos++
+				return list, nil
+			}
+			if p.eof() {
+				return nil, p.errorf("unterminated list")
+			}
+			item, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+	case c == '{':
+		p.pos++
+		object := map[string]any{}
+		for {
+			p.skipIgnored()
+			if p.peek() == '}' {
+				p.pos++
+				return object, nil
+			}
+			if !p.peekName() {
+				return nil, p.errorf("expected object field name")
+			}
+			name := p.name()
+			if err := p.expect(':'); err != nil {
+				return nil, err
+			}
+			item, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			object[name] = item
+		}
+	case isNameStart(c):
+		switch name := p.name(); name {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		default:
+			// enum values are passed through as strings
+			return name, nil
+		}
+	case p.eof():
+		return nil, p.errorf("expected value, found end of query")
+	default:
+		return nil, p.errorf("unexpected %q", c)
+	}
+}
+
+func (p *parser) stringValue() (string, error) {
+	start := p.pos
+	p.pos++
+	for !p.eof() {
+		switch p.peek() {
+		case '\\':
+			p.pos += 2
+			continue
+		case '"':
+			p.pos++
+			s, err := strconv.Unquote(p.input[start:p.pos])
+			if err != nil {
+				return "", p.errorf("invalid string %s", p.input[start:p.pos])
+			}
+			return s, nil
+		}
+		p.pos++
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *parser) numberValue() (any, error) {
+	start := p.pos
+	p.pos++
+	for !p.eof() && strings.IndexByte("0123456789.eE+-", p.peek()) >= 0 {
+		p.pos++
+	}
+	literal := p.input[start:p.pos]
+	if i, err := strconv.ParseInt(literal, 10, 64); err == nil {
+		return i, nil
+	}
+	f, err := strconv.ParseFloat(literal, 64)
+	if err != nil {
+		return nil, p.errorf("invalid number %q", literal)
+	}
+	return f, nil
+}
@@ -0,0 +1,71 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	fields, err := ParseQuery(`
+		query Events($start: Int) {
+			# comments are ignored
+			events(startLedger: $start, filters: [{type: "contract", topics: [["*"]]}], pagination: {limit: 10}) {
+				id
+				tx: transaction { status }
+			}
+			latestLedger { sequence }
+		}`)
+	require.NoError(t, err)
+	assert.Equal(t, []Field{
+		{
+			Name: "events",
+			Arguments: map[string]any{
+				"startLedger": Variable("start"),
+				"filters": []any{
+					map[string]any{"type": "contract", "topics": []any{[]any{"*"}}},
+				},
+				"pagination": map[string]any{"limit": int64(10)},
+			},
+			SelectionSet: []Field{
+				{Name: "id"},
+				{Alias: "tx", Name: "transaction", SelectionSet: []Field{{Name: "status"}}},
+			},
+		},
+		{Name: "latestLedger", SelectionSet: []Field{{Name: "sequence"}}},
+	}, fields)
+}
+
+func TestParseQueryShorthand(t *testing.T) {
+	fields, err := ParseQuery(`{ transaction(hash: "ab\"c", flag: true, other: null, n: -1.5) }`)
+	require.NoError(t, err)
+	assert.Equal(t, []Field{
+		{
+			Name: "transaction",
+			Arguments: map[string]any{
+				"hash":  `ab"c`,
+				"flag":  true,
+				"other": nil,
+				"n":     -1.5,
+			},
+		},
+	}, fields)
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		"",
+		"{",
+		"{}",
+		"mutation { foo }",
+		"{ foo(bar: ) }",
+		"{ foo(bar: \"baz) }",
+		"{ ...fragment }",
+		"{ foo @include(if: true) }",
+		"{ foo } bar",
+	} {
+		_, err := ParseQuery(query)
+		assert.Error(t, err, query)
+	}
+}
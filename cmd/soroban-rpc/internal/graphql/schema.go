@@ -0,0 +1,54 @@
+package graphql
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+var marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// fieldsOf returns the JSON fields of a response type, mapped to their types.
+// It returns nil for types whose shape isn't known statically (e.g. maps,
+// interfaces or types with a custom JSON encoding), on which any field can be
+// selected.
+func fieldsOf(typ reflect.Type) map[string]reflect.Type {
+	typ = elemType(typ)
+	if typ == nil || typ.Kind() != reflect.Struct || typ.Implements(marshalerType) ||
+		reflect.PointerTo(typ).Implements(marshalerType) {
+		return nil
+	}
+	fields := map[string]reflect.Type{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			// the fields of embedded structs are promoted
+			for embeddedName, embeddedType := range fieldsOf(field.Type) {
+				fields[embeddedName] = embeddedType
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// elemType strips the pointers and slices around a type, since selection sets
+// apply to the individual elements of lists.
+func elemType(typ reflect.Type) reflect.Type {
+	for typ != nil && (typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array) {
+		typ = typ.Elem()
+	}
+	return typ
+}
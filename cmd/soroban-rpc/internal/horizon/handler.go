@@ -0,0 +1,167 @@
+package horizon
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// Path is the prefix of the Horizon-compatible endpoints
+const Path = "/horizon"
+
+const problemHost = "https://stellar.org/horizon-errors/"
+
+// Limiter applies the limits of the equivalent JSON RPC method to a request, returning a function
+// releasing the resources acquired for it. The rejections should be Horizon problems (see problem.P).
+type Limiter func(ctx context.Context, method string) (func(), error)
+
+type handler struct {
+	logger            *log.Entry
+	limiter           Limiter
+	problems          *problem.Problem
+	ledgerReader      db.LedgerReader
+	ledgerEntryReader db.LedgerEntryReader
+	transactionReader db.TransactionReader
+	networkPassphrase string
+}
+
+// NewHTTPHandler returns an HTTP handler serving a subset of the Horizon REST API (the transaction,
+// account and ledger resources), backed by the RPC stores. Errors are rendered as Horizon problems.
+// The (optional) limiter is applied to the requests of the resources served by a JSON RPC method.
+func NewHTTPHandler(
+	logger *log.Entry,
+	ledgerReader db.LedgerReader,
+	ledgerEntryReader db.LedgerEntryReader,
+	transactionReader db.TransactionReader,
+	networkPassphrase string,
+	limiter Limiter,
+) http.Handler {
+	h := handler{
+		logger:            logger,
+		limiter:           limiter,
+		problems:          problem.New(problemHost, logger, problem.LogUnknownErrors),
+		ledgerReader:      ledgerReader,
+		ledgerEntryReader: ledgerEntryReader,
+		transactionReader: transactionReader,
+		networkPassphrase: networkPassphrase,
+	}
+	router := chi.NewRouter()
+	router.Get("/transactions/{hash}", h.limited("getTransaction", h.getTransaction))
+	router.Get("/accounts/{id}", h.limited("getLedgerEntries", h.getAccount))
+	// no JSON RPC method serves single ledgers, so they are limited like a call to an unweighted method
+	router.Get("/ledgers/{sequence}", h.limited("", h.getLedger))
+	router.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		h.problems.Render(r.Context(), w, problem.NotFound)
+	})
+	return router
+}
+
+func (h handler) limited(method string, next http.HandlerFunc) http.HandlerFunc {
+	if h.limiter == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		release, err := h.limiter(r.Context(), method)
+		if err != nil {
+			h.problems.Render(r.Context(), w, err)
+			return
+		}
+		defer release()
+		next(w, r)
+	}
+}
+
+func (h handler) render(ctx context.Context, w http.ResponseWriter, resource any, err error) {
+	if err != nil {
+		h.problems.Render(ctx, w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/hal+json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(resource); err != nil {
+		h.logger.WithError(err).Warn("could not write Horizon response")
+	}
+}
+
+func (h handler) getTransaction(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	hashBytes, err := hex.DecodeString(chi.URLParam(r, "hash"))
+	if err != nil || len(hashBytes) != len(xdr.Hash{}) {
+		h.problems.Render(ctx, w, problem.MakeInvalidFieldProblem("tx_id", errors.New("invalid hash format")))
+		return
+	}
+	var hash xdr.Hash
+	copy(hash[:], hashBytes)
+
+	tx, _, err := h.transactionReader.GetTransaction(ctx, hash)
+	if errors.Is(err, db.ErrNoTransaction) {
+		h.problems.Render(ctx, w, problem.NotFound)
+		return
+	}
+	if err != nil {
+		h.problems.Render(ctx, w, err)
+		return
+	}
+	resource, err := newTransactionResource(hash, tx, h.networkPassphrase)
+	h.render(ctx, w, resource, err)
+}
+
+func (h handler) getAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, err := xdr.AddressToAccountId(chi.URLParam(r, "id"))
+	if err != nil {
+		h.problems.Render(ctx, w, problem.MakeInvalidFieldProblem("account_id", errors.New("invalid account id")))
+		return
+	}
+	key := xdr.LedgerKey{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.LedgerKeyAccount{AccountId: accountID}}
+
+	tx, err := h.ledgerEntryReader.NewTx(ctx)
+	if err != nil {
+		h.problems.Render(ctx, w, err)
+		return
+	}
+	defer func() {
+		_ = tx.Done()
+	}()
+	present, entry, _, err := db.GetLedgerEntry(tx, key)
+	if err != nil {
+		h.problems.Render(ctx, w, err)
+		return
+	}
+	if !present {
+		h.problems.Render(ctx, w, problem.NotFound)
+		return
+	}
+	h.render(ctx, w, newAccountResource(entry), nil)
+}
+
+func (h handler) getLedger(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sequence, err := strconv.ParseUint(chi.URLParam(r, "sequence"), 10, 32)
+	if err != nil {
+		h.problems.Render(ctx, w, problem.MakeInvalidFieldProblem("ledger_id", errors.New("ledger sequence must be a number")))
+		return
+	}
+	ledger, present, err := h.ledgerReader.GetLedger(ctx, uint32(sequence))
+	if err != nil {
+		h.problems.Render(ctx, w, err)
+		return
+	}
+	if !present {
+		// the ledger is either in the future or outside the retention window
+		h.problems.Render(ctx, w, problem.NotFound)
+		return
+	}
+	resource, err := newLedgerResource(ledger, h.networkPassphrase)
+	h.render(ctx, w, resource, err)
+}
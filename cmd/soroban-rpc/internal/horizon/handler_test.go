@@ -0,0 +1,372 @@
+package horizon
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	protocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+)
+
+type fakeTransactionReader struct {
+	db.TransactionReader
+	transactions map[xdr.Hash]db.Transaction
+}
+
+func (r fakeTransactionReader) GetTransaction(ctx context.Context, hash xdr.Hash) (db.Transaction, ledgerbucketwindow.LedgerRange, error) {
+	tx, ok := r.transactions[hash]
+	if !ok {
+		return db.Transaction{}, ledgerbucketwindow.LedgerRange{}, db.ErrNoTransaction
+	}
+	return tx, ledgerbucketwindow.LedgerRange{}, nil
+}
+
+type fakeLedgerReader struct {
+	db.LedgerReader
+	ledgers map[uint32]xdr.LedgerCloseMeta
+}
+
+func (r fakeLedgerReader) GetLedger(ctx context.Context, sequence uint32) (xdr.LedgerCloseMeta, bool, error) {
+	ledger, ok := r.ledgers[sequence]
+	return ledger, ok, nil
+}
+
+type fakeLedgerEntryReader struct {
+	db.LedgerEntryReader
+	entries []xdr.LedgerEntry
+}
+
+func (r fakeLedgerEntryReader) NewTx(ctx context.Context) (db.LedgerEntryReadTx, error) {
+	return fakeLedgerEntryReadTx{entries: r.entries}, nil
+}
+
+type fakeLedgerEntryReadTx struct {
+	db.LedgerEntryReadTx
+	entries []xdr.LedgerEntry
+}
+
+func (tx fakeLedgerEntryReadTx) GetLedgerEntries(keys ...xdr.LedgerKey) ([]db.LedgerKeyAndEntry, error) {
+	var result []db.LedgerKeyAndEntry
+	for _, key := range keys {
+		for _, entry := range tx.entries {
+			if entryKey, err := entry.LedgerKey(); err == nil && entryKey.Equals(key) {
+				result = append(result, db.LedgerKeyAndEntry{Key: key, Entry: entry})
+			}
+		}
+	}
+	return result, nil
+}
+
+func (tx fakeLedgerEntryReadTx) Done() error {
+	return nil
+}
+
+func get(t *testing.T, handler http.Handler, path string, resource any) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	if resource != nil {
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.Equal(t, "application/hal+json; charset=utf-8", recorder.Header().Get("Content-Type"))
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), resource))
+	}
+	return recorder
+}
+
+func TestHandler(t *testing.T) {
+	source := keypair.MustRandom()
+	sourceAccountID := xdr.MustAddress(source.Address())
+	memo := "hello"
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: sourceAccountID.ToMuxedAccount(),
+				Fee:           200,
+				SeqNum:        42,
+				Memo:          xdr.MemoText(memo),
+				Operations: []xdr.Operation{
+					{Body: xdr.OperationBody{Type: xdr.OperationTypeBumpSequence, BumpSequenceOp: &xdr.BumpSequenceOp{BumpTo: 43}}},
+				},
+			},
+			Signatures: []xdr.DecoratedSignature{{Signature: []byte{1, 2, 3}}},
+		},
+	}
+	envelopeXDR, err := envelope.MarshalBinary()
+	require.NoError(t, err)
+	resultXDR, err := xdr.TransactionResult{
+		FeeCharged: 100,
+		Result: xdr.TransactionResultResult{
+			Code:    xdr.TransactionResultCodeTxSuccess,
+			Results: &[]xdr.OperationResult{},
+		},
+	}.MarshalBinary()
+	require.NoError(t, err)
+	hash, err := network.HashTransactionInEnvelope(envelope, network.TestNetworkPassphrase)
+	require.NoError(t, err)
+
+	accountEntry := xdr.LedgerEntry{
+		LastModifiedLedgerSeq: 90,
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeAccount,
+			Account: &xdr.AccountEntry{
+				AccountId:  sourceAccountID,
+				Balance:    123_4567890,
+				SeqNum:     42,
+				Thresholds: xdr.Thresholds{1, 2, 3, 4},
+				Flags:      xdr.Uint32(xdr.AccountFlagsAuthRequiredFlag),
+				HomeDomain: "example.com",
+			},
+		},
+	}
+
+	ledger := xdr.LedgerCloseMeta{
+		V: 1,
+		V1: &xdr.LedgerCloseMetaV1{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+				Hash: xdr.Hash{9},
+				Header: xdr.LedgerHeader{
+					LedgerVersion:      21,
+					PreviousLedgerHash: xdr.Hash{8},
+					ScpValue:           xdr.StellarValue{CloseTime: 1000},
+					LedgerSeq:          100,
+					TotalCoins:         1000_0000000,
+					BaseFee:            100,
+					BaseReserve:        5000000,
+				},
+			},
+			TxSet: xdr.GeneralizedTransactionSet{
+				V:       1,
+				V1TxSet: &xdr.TransactionSetV1{Phases: []xdr.TransactionPhase{}},
+			},
+		},
+	}
+
+	handler := NewHTTPHandler(
+		log.DefaultLogger,
+		fakeLedgerReader{ledgers: map[uint32]xdr.LedgerCloseMeta{100: ledger}},
+		fakeLedgerEntryReader{entries: []xdr.LedgerEntry{accountEntry}},
+		fakeTransactionReader{transactions: map[xdr.Hash]db.Transaction{
+			hash: {
+				Envelope:         envelopeXDR,
+				Result:           resultXDR,
+				Meta:             []byte{},
+				ApplicationOrder: 1,
+				Successful:       true,
+				Ledger:           ledgerbucketwindow.LedgerInfo{Sequence: 100, CloseTime: 1000},
+			},
+		}},
+		network.TestNetworkPassphrase,
+		nil,
+	)
+
+	t.Run("transaction", func(t *testing.T) {
+		var resource protocol.Transaction
+		get(t, handler, "/transactions/"+hex.EncodeToString(hash[:]), &resource)
+		assert.Equal(t, hex.EncodeToString(hash[:]), resource.Hash)
+		assert.Equal(t, "429496733696", resource.PT)
+		assert.True(t, resource.Successful)
+		assert.Equal(t, int32(100), resource.Ledger)
+		assert.Equal(t, int64(1000), resource.LedgerCloseTime.Unix())
+		assert.Equal(t, source.Address(), resource.Account)
+		assert.Equal(t, source.Address(), resource.FeeAccount)
+		assert.Equal(t, int64(42), resource.AccountSequence)
+		assert.Equal(t, int64(200), resource.MaxFee)
+		assert.Equal(t, int64(100), resource.FeeCharged)
+		assert.Equal(t, int32(1), resource.OperationCount)
+		assert.Equal(t, "text", resource.MemoType)
+		assert.Equal(t, memo, resource.Memo)
+		assert.Equal(t, []string{"AQID"}, resource.Signatures)
+		assert.Nil(t, resource.FeeBumpTransaction)
+	})
+
+	t.Run("account", func(t *testing.T) {
+		var resource protocol.Account
+		get(t, handler, "/accounts/"+source.Address(), &resource)
+		assert.Equal(t, source.Address(), resource.AccountID)
+		assert.Equal(t, int64(42), resource.Sequence)
+		assert.Equal(t, "example.com", resource.HomeDomain)
+		assert.Equal(t, uint32(90), resource.LastModifiedLedger)
+		assert.Equal(t, protocol.AccountThresholds{LowThreshold: 2, MedThreshold: 3, HighThreshold: 4}, resource.Thresholds)
+		assert.True(t, resource.Flags.AuthRequired)
+		assert.False(t, resource.Flags.AuthRevocable)
+		require.Len(t, resource.Balances, 1)
+		assert.Equal(t, "native", resource.Balances[0].Type)
+		assert.Equal(t, "123.4567890", resource.Balances[0].Balance)
+		require.Len(t, resource.Signers, 1)
+		assert.Equal(t, source.Address(), resource.Signers[0].Key)
+		assert.Equal(t, int32(1), resource.Signers[0].Weight)
+	})
+
+	t.Run("ledger", func(t *testing.T) {
+		var resource protocol.Ledger
+		get(t, handler, "/ledgers/100", &resource)
+		ledgerHash := xdr.Hash{9}
+		assert.Equal(t, hex.EncodeToString(ledgerHash[:]), resource.Hash)
+		assert.Equal(t, int32(100), resource.Sequence)
+		assert.Equal(t, "1000.0000000", resource.TotalCoins)
+		assert.Equal(t, int32(100), resource.BaseFee)
+		assert.Equal(t, int32(21), resource.ProtocolVersion)
+		assert.Equal(t, int32(0), resource.SuccessfulTransactionCount)
+		assert.Equal(t, int64(1000), resource.ClosedAt.Unix())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for path, status := range map[string]int{
+			"/transactions/" + hex.EncodeToString(make([]byte, 32)): http.StatusNotFound,
+			"/transactions/foo":                           http.StatusBadRequest,
+			"/accounts/" + keypair.MustRandom().Address(): http.StatusNotFound,
+			"/accounts/foo":                               http.StatusBadRequest,
+			"/ledgers/101":                                http.StatusNotFound,
+			"/ledgers/foo":                                http.StatusBadRequest,
+			"/operations/1":                               http.StatusNotFound,
+		} {
+			recorder := get(t, handler, path, nil)
+			assert.Equal(t, status, recorder.Code, path)
+			assert.Equal(t, "application/problem+json; charset=utf-8", recorder.Header().Get("Content-Type"), path)
+		}
+	})
+}
+
+func TestHandlerLimiter(t *testing.T) {
+	var methods []string
+	released := 0
+	limiter := func(ctx context.Context, method string) (func(), error) {
+		methods = append(methods, method)
+		if method == "getLedgerEntries" {
+			return nil, problem.P{Type: "server_over_capacity", Title: "Server Over Capacity", Status: http.StatusServiceUnavailable}
+		}
+		return func() { released++ }, nil
+	}
+	handler := NewHTTPHandler(
+		log.DefaultLogger,
+		fakeLedgerReader{},
+		fakeLedgerEntryReader{},
+		fakeTransactionReader{},
+		network.TestNetworkPassphrase,
+		limiter,
+	)
+
+	recorder := get(t, handler, "/transactions/"+hex.EncodeToString(make([]byte, 32)), nil)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	recorder = get(t, handler, "/ledgers/101", nil)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, 2, released)
+
+	recorder = get(t, handler, "/accounts/"+keypair.MustRandom().Address(), nil)
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	var rejection problem.P
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rejection))
+	assert.Equal(t, problemHost+"server_over_capacity", rejection.Type)
+	assert.Equal(t, []string{"getTransaction", "", "getLedgerEntries"}, methods)
+}
+
+func TestFeeBumpTransaction(t *testing.T) {
+	source := xdr.MustAddress(keypair.MustRandom().Address())
+	feeSource := xdr.MustAddress(keypair.MustRandom().Address())
+	innerTx := xdr.Transaction{
+		SourceAccount: source.ToMuxedAccount(),
+		Fee:           100,
+		SeqNum:        42,
+		Operations: []xdr.Operation{
+			{Body: xdr.OperationBody{Type: xdr.OperationTypeBumpSequence, BumpSequenceOp: &xdr.BumpSequenceOp{BumpTo: 43}}},
+		},
+	}
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTxFeeBump,
+		FeeBump: &xdr.FeeBumpTransactionEnvelope{
+			Tx: xdr.FeeBumpTransaction{
+				FeeSource: feeSource.ToMuxedAccount(),
+				Fee:       300,
+				InnerTx: xdr.FeeBumpTransactionInnerTx{
+					Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+					V1: &xdr.TransactionV1Envelope{
+						Tx:         innerTx,
+						Signatures: []xdr.DecoratedSignature{{Signature: []byte{1}}},
+					},
+				},
+			},
+			Signatures: []xdr.DecoratedSignature{{Signature: []byte{2}}},
+		},
+	}
+	envelopeXDR, err := envelope.MarshalBinary()
+	require.NoError(t, err)
+	resultXDR, err := xdr.TransactionResult{
+		FeeCharged: 200,
+		Result: xdr.TransactionResultResult{
+			Code: xdr.TransactionResultCodeTxFeeBumpInnerSuccess,
+			InnerResultPair: &xdr.InnerTransactionResultPair{
+				Result: xdr.InnerTransactionResult{
+					Result: xdr.InnerTransactionResultResult{
+						Code:    xdr.TransactionResultCodeTxSuccess,
+						Results: &[]xdr.OperationResult{},
+					},
+				},
+			},
+		},
+	}.MarshalBinary()
+	require.NoError(t, err)
+	outerHash, err := network.HashTransactionInEnvelope(envelope, network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	innerHash, err := network.HashTransaction(innerTx, network.TestNetworkPassphrase)
+	require.NoError(t, err)
+
+	tx := db.Transaction{
+		Envelope:         envelopeXDR,
+		Result:           resultXDR,
+		Meta:             []byte{},
+		ApplicationOrder: 1,
+		Successful:       true,
+		FeeBump:          true,
+		Ledger:           ledgerbucketwindow.LedgerInfo{Sequence: 100, CloseTime: 1000},
+	}
+	// the transactions can be looked up by their inner hash
+	handler := NewHTTPHandler(
+		log.DefaultLogger,
+		fakeLedgerReader{},
+		fakeLedgerEntryReader{},
+		fakeTransactionReader{transactions: map[xdr.Hash]db.Transaction{outerHash: tx, innerHash: tx}},
+		network.TestNetworkPassphrase,
+		nil,
+	)
+	outer, inner := hex.EncodeToString(outerHash[:]), hex.EncodeToString(innerHash[:])
+
+	var resource protocol.Transaction
+	get(t, handler, "/transactions/"+outer, &resource)
+	assert.Equal(t, outer, resource.ID)
+	assert.Equal(t, outer, resource.Hash)
+	assert.Equal(t, int64(300), resource.MaxFee)
+	assert.Equal(t, feeSource.Address(), resource.FeeAccount)
+	assert.Equal(t, []string{"Ag=="}, resource.Signatures)
+	require.NotNil(t, resource.FeeBumpTransaction)
+	assert.Equal(t, outer, resource.FeeBumpTransaction.Hash)
+	require.NotNil(t, resource.InnerTransaction)
+	assert.Equal(t, inner, resource.InnerTransaction.Hash)
+	assert.Equal(t, int64(100), resource.InnerTransaction.MaxFee)
+
+	resource = protocol.Transaction{}
+	get(t, handler, "/transactions/"+inner, &resource)
+	assert.Equal(t, inner, resource.ID)
+	assert.Equal(t, inner, resource.Hash)
+	assert.Equal(t, int64(100), resource.MaxFee)
+	assert.Equal(t, feeSource.Address(), resource.FeeAccount)
+	assert.Equal(t, []string{"AQ=="}, resource.Signatures)
+	require.NotNil(t, resource.FeeBumpTransaction)
+	assert.Equal(t, outer, resource.FeeBumpTransaction.Hash)
+	assert.Equal(t, []string{"Ag=="}, resource.FeeBumpTransaction.Signatures)
+	require.NotNil(t, resource.InnerTransaction)
+	assert.Equal(t, inner, resource.InnerTransaction.Hash)
+}
@@ -0,0 +1,251 @@
+package horizon
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/network"
+	protocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/base"
+	"github.com/stellar/go/toid"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+func encodeSignatures(signatures []xdr.DecoratedSignature) []string {
+	result := make([]string, 0, len(signatures))
+	for _, signature := range signatures {
+		result = append(result, base64.StdEncoding.EncodeToString(signature.Signature))
+	}
+	return result
+}
+
+// setMemo fills the memo fields the way Horizon does
+func setMemo(resource *protocol.Transaction, memo xdr.Memo) {
+	switch memo.Type {
+	case xdr.MemoTypeMemoNone:
+		resource.MemoType = "none"
+	case xdr.MemoTypeMemoText:
+		resource.MemoType = "text"
+		resource.Memo = memo.MustText()
+		resource.MemoBytes = base64.StdEncoding.EncodeToString([]byte(memo.MustText()))
+	case xdr.MemoTypeMemoId:
+		resource.MemoType = "id"
+		resource.Memo = strconv.FormatUint(uint64(memo.MustId()), 10)
+	case xdr.MemoTypeMemoHash:
+		resource.MemoType = "hash"
+		hash := memo.MustHash()
+		resource.Memo = base64.StdEncoding.EncodeToString(hash[:])
+	case xdr.MemoTypeMemoReturn:
+		resource.MemoType = "return"
+		hash := memo.MustRetHash()
+		resource.Memo = base64.StdEncoding.EncodeToString(hash[:])
+	}
+}
+
+// newTransactionResource converts a stored transaction into a Horizon transaction resource.
+// The fee meta isn't stored by the RPC, so fee_meta_xdr is always empty. Like in Horizon, a
+// fee-bump transaction requested by its inner hash is described by its inner transaction
+// (hash, max fee and signatures).
+func newTransactionResource(requestedHash xdr.Hash, tx db.Transaction, networkPassphrase string) (protocol.Transaction, error) {
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshal(tx.Envelope, &envelope); err != nil {
+		return protocol.Transaction{}, err
+	}
+	var result xdr.TransactionResult
+	if err := xdr.SafeUnmarshal(tx.Result, &result); err != nil {
+		return protocol.Transaction{}, err
+	}
+	hash, err := network.HashTransactionInEnvelope(envelope, networkPassphrase)
+	if err != nil {
+		return protocol.Transaction{}, err
+	}
+
+	resource := protocol.Transaction{
+		ID:              hex.EncodeToString(hash[:]),
+		PT:              toid.New(int32(tx.Ledger.Sequence), tx.ApplicationOrder, 0).String(),
+		Successful:      tx.Successful,
+		Hash:            hex.EncodeToString(hash[:]),
+		Ledger:          int32(tx.Ledger.Sequence),
+		LedgerCloseTime: time.Unix(tx.Ledger.CloseTime, 0).UTC(),
+		AccountSequence: envelope.SeqNum(),
+		FeeCharged:      int64(result.FeeCharged),
+		MaxFee:          int64(envelope.Fee()),
+		OperationCount:  int32(len(envelope.Operations())),
+		EnvelopeXdr:     base64.StdEncoding.EncodeToString(tx.Envelope),
+		ResultXdr:       base64.StdEncoding.EncodeToString(tx.Result),
+		ResultMetaXdr:   base64.StdEncoding.EncodeToString(tx.Meta),
+		Signatures:      encodeSignatures(envelope.Signatures()),
+	}
+	source := envelope.SourceAccount()
+	resource.Account = source.ToAccountId().Address()
+	if source.Type == xdr.CryptoKeyTypeKeyTypeMuxedEd25519 {
+		resource.AccountMuxed = source.Address()
+		resource.AccountMuxedID = uint64(source.Med25519.Id)
+	}
+	resource.FeeAccount, resource.FeeAccountMuxed, resource.FeeAccountMuxedID =
+		resource.Account, resource.AccountMuxed, resource.AccountMuxedID
+	setMemo(&resource, envelope.Memo())
+
+	if envelope.IsFeeBump() {
+		feeAccount := envelope.FeeBumpAccount()
+		resource.FeeAccount = feeAccount.ToAccountId().Address()
+		resource.FeeAccountMuxed, resource.FeeAccountMuxedID = "", 0
+		if feeAccount.Type == xdr.CryptoKeyTypeKeyTypeMuxedEd25519 {
+			resource.FeeAccountMuxed = feeAccount.Address()
+			resource.FeeAccountMuxedID = uint64(feeAccount.Med25519.Id)
+		}
+		resource.MaxFee = envelope.FeeBumpFee()
+		resource.Signatures = encodeSignatures(envelope.FeeBumpSignatures())
+		innerHash, err := network.HashTransaction(envelope.FeeBump.Tx.InnerTx.V1.Tx, networkPassphrase)
+		if err != nil {
+			return protocol.Transaction{}, err
+		}
+		resource.FeeBumpTransaction = &protocol.FeeBumpTransaction{
+			Hash:       resource.Hash,
+			Signatures: resource.Signatures,
+		}
+		resource.InnerTransaction = &protocol.InnerTransaction{
+			Hash:       hex.EncodeToString(innerHash[:]),
+			Signatures: encodeSignatures(envelope.Signatures()),
+			MaxFee:     int64(envelope.Fee()),
+		}
+		if requestedHash == innerHash {
+			resource.ID = resource.InnerTransaction.Hash
+			resource.Hash = resource.InnerTransaction.Hash
+			resource.MaxFee = resource.InnerTransaction.MaxFee
+			resource.Signatures = resource.InnerTransaction.Signatures
+		}
+	}
+	return resource, nil
+}
+
+// newAccountResource converts an account entry into a Horizon account resource. Only the native
+// balance is included, since the trustlines and data entries of an account cannot be enumerated.
+func newAccountResource(entry xdr.LedgerEntry) protocol.Account {
+	account := entry.Data.MustAccount()
+	address := account.AccountId.Address()
+	liabilities := account.Liabilities()
+	resource := protocol.Account{
+		ID:                 address,
+		AccountID:          address,
+		PT:                 address,
+		Sequence:           int64(account.SeqNum),
+		SequenceLedger:     uint32(account.SeqLedger()),
+		SubentryCount:      int32(account.NumSubEntries),
+		HomeDomain:         string(account.HomeDomain),
+		LastModifiedLedger: uint32(entry.LastModifiedLedgerSeq),
+		Thresholds: protocol.AccountThresholds{
+			LowThreshold:  account.ThresholdLow(),
+			MedThreshold:  account.ThresholdMedium(),
+			HighThreshold: account.ThresholdHigh(),
+		},
+		Flags: protocol.AccountFlags{
+			AuthRequired:        xdr.AccountFlags(account.Flags).IsAuthRequired(),
+			AuthRevocable:       xdr.AccountFlags(account.Flags).IsAuthRevocable(),
+			AuthImmutable:       xdr.AccountFlags(account.Flags).IsAuthImmutable(),
+			AuthClawbackEnabled: xdr.AccountFlags(account.Flags).IsAuthClawbackEnabled(),
+		},
+		Balances: []protocol.Balance{
+			{
+				Balance:            amount.String(account.Balance),
+				BuyingLiabilities:  amount.String(liabilities.Buying),
+				SellingLiabilities: amount.String(liabilities.Selling),
+				Asset:              base.Asset{Type: "native"},
+			},
+		},
+		Data:          map[string]string{},
+		NumSponsoring: uint32(account.NumSponsoring()),
+		NumSponsored:  uint32(account.NumSponsored()),
+	}
+	if seqTime := account.SeqTime(); seqTime != 0 {
+		resource.SequenceTime = strconv.FormatUint(uint64(seqTime), 10)
+	}
+	if account.InflationDest != nil {
+		resource.InflationDestination = account.InflationDest.Address()
+	}
+	if sponsor := entry.SponsoringID(); sponsor != nil {
+		resource.Sponsor = sponsor.Address()
+	}
+
+	sponsors := account.SponsorPerSigner()
+	for _, signer := range account.Signers {
+		key := signer.Key.Address()
+		resourceSigner := protocol.Signer{
+			Weight: int32(signer.Weight),
+			Key:    key,
+			Type:   protocol.MustKeyTypeFromAddress(key),
+		}
+		if sponsor, ok := sponsors[key]; ok {
+			resourceSigner.Sponsor = sponsor.Address()
+		}
+		resource.Signers = append(resource.Signers, resourceSigner)
+	}
+	// like Horizon, the master key is listed last
+	resource.Signers = append(resource.Signers, protocol.Signer{
+		Weight: int32(account.MasterKeyWeight()),
+		Key:    address,
+		Type:   protocol.MustKeyTypeFromAddress(address),
+	})
+	return resource
+}
+
+// newLedgerResource converts a ledger close meta into a Horizon ledger resource
+func newLedgerResource(ledger xdr.LedgerCloseMeta, networkPassphrase string) (protocol.Ledger, error) {
+	reader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(networkPassphrase, ledger)
+	if err != nil {
+		return protocol.Ledger{}, err
+	}
+	defer reader.Close()
+	var successfulCount, failedCount, operationCount, txSetOperationCount int32
+	for {
+		tx, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return protocol.Ledger{}, err
+		}
+		count := int32(len(tx.Envelope.Operations()))
+		txSetOperationCount += count
+		if tx.Result.Successful() {
+			successfulCount++
+			operationCount += count
+		} else {
+			failedCount++
+		}
+	}
+
+	headerEntry := ledger.LedgerHeaderHistoryEntry()
+	header := headerEntry.Header
+	headerXDR, err := xdr.MarshalBase64(header)
+	if err != nil {
+		return protocol.Ledger{}, err
+	}
+	return protocol.Ledger{
+		ID:                         hex.EncodeToString(headerEntry.Hash[:]),
+		PT:                         toid.New(int32(header.LedgerSeq), 0, 0).String(),
+		Hash:                       hex.EncodeToString(headerEntry.Hash[:]),
+		PrevHash:                   hex.EncodeToString(header.PreviousLedgerHash[:]),
+		Sequence:                   int32(header.LedgerSeq),
+		SuccessfulTransactionCount: successfulCount,
+		FailedTransactionCount:     &failedCount,
+		OperationCount:             operationCount,
+		TxSetOperationCount:        &txSetOperationCount,
+		ClosedAt:                   time.Unix(int64(header.ScpValue.CloseTime), 0).UTC(),
+		TotalCoins:                 amount.String(header.TotalCoins),
+		FeePool:                    amount.String(header.FeePool),
+		BaseFee:                    int32(header.BaseFee),
+		BaseReserve:                int32(header.BaseReserve),
+		MaxTxSetSize:               int32(header.MaxTxSetSize),
+		ProtocolVersion:            int32(header.LedgerVersion),
+		HeaderXDR:                  headerXDR,
+	}, nil
+}
@@ -0,0 +1,90 @@
+package ingest
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+// CircuitBreaker monitors the ingestion lag (i.e. how long ago the latest
+// ingested ledger closed) and trips when it grows beyond a threshold, which
+// happens when captive core falls out of sync or ingestion stalls.
+type CircuitBreaker struct {
+	maxLag      time.Duration
+	logger      *log.Entry
+	openGauge   prometheus.Gauge
+	now         func() time.Time
+	lock        sync.Mutex
+	lastClose   time.Time
+	lastLedger  uint32
+	open        bool
+	initialized bool
+}
+
+// NewCircuitBreaker creates a circuit breaker which trips when the latest
+// ingested ledger closed more than maxLag ago.
+func NewCircuitBreaker(maxLag time.Duration, daemon interfaces.Daemon, logger *log.Entry) *CircuitBreaker {
+	openGauge := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: daemon.MetricsNamespace(), Subsystem: "ingest", Name: "circuit_breaker_open",
+		Help: "1 when the node is degraded (too far behind the network) and failing fast on submissions and simulations, 0 otherwise",
+	})
+	daemon.MetricsRegistry().MustRegister(openGauge)
+	return &CircuitBreaker{
+		maxLag:    maxLag,
+		logger:    logger,
+		openGauge: openGauge,
+		now:       time.Now,
+	}
+}
+
+// OnLedgerIngested records the close time of an ingested ledger
+func (b *CircuitBreaker) OnLedgerIngested(lcm xdr.LedgerCloseMeta) {
+	closeTime := time.Unix(int64(lcm.LedgerHeaderHistoryEntry().Header.ScpValue.CloseTime), 0)
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.lastClose = closeTime
+	b.lastLedger = lcm.LedgerSequence()
+	b.initialized = true
+}
+
+// CheckHealth returns an error while the node is degraded
+func (b *CircuitBreaker) CheckHealth() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	var err error
+	if !b.initialized {
+		err = fmt.Errorf("node degraded: no ledgers were ingested yet")
+	} else if lag := b.now().Sub(b.lastClose); lag > b.maxLag {
+		err = fmt.Errorf("node degraded: latest ingested ledger (%d) closed %s ago (>%s)",
+			b.lastLedger, lag.Round(time.Second), b.maxLag)
+	}
+	b.setOpen(err)
+	return err
+}
+
+func (b *CircuitBreaker) setOpen(err error) {
+	open := err != nil
+	if open == b.open {
+		return
+	}
+	b.open = open
+	if open {
+		b.openGauge.Set(1)
+		// the breaker starts open, only log (re)opening after having been closed
+		if b.logger != nil && b.initialized {
+			b.logger.WithError(err).Warn("circuit breaker tripped, failing fast on submissions and simulations")
+		}
+		return
+	}
+	b.openGauge.Set(0)
+	if b.logger != nil {
+		b.logger.Info("circuit breaker reset, the node caught up with the network")
+	}
+}
@@ -0,0 +1,49 @@
+package ingest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func ledgerClosedAt(sequence uint32, closeTime time.Time) xdr.LedgerCloseMeta {
+	return xdr.LedgerCloseMeta{
+		V: 1,
+		V1: &xdr.LedgerCloseMetaV1{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+				Header: xdr.LedgerHeader{
+					LedgerSeq: xdr.Uint32(sequence),
+					ScpValue:  xdr.StellarValue{CloseTime: xdr.TimePoint(closeTime.Unix())},
+				},
+			},
+		},
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Unix(1000000, 0)
+	breaker := NewCircuitBreaker(time.Minute, interfaces.MakeNoOpDeamon(), nil)
+	breaker.now = func() time.Time { return now }
+
+	// the breaker is open until the first ledger is ingested
+	require.EqualError(t, breaker.CheckHealth(), "node degraded: no ledgers were ingested yet")
+
+	breaker.OnLedgerIngested(ledgerClosedAt(10, now.Add(-5*time.Second)))
+	require.NoError(t, breaker.CheckHealth())
+
+	// ingestion stalls
+	now = now.Add(2 * time.Minute)
+	require.EqualError(t, breaker.CheckHealth(), "node degraded: latest ingested ledger (10) closed 2m5s ago (>1m0s)")
+	assert.True(t, breaker.open)
+
+	// and catches up
+	breaker.OnLedgerIngested(ledgerClosedAt(30, now))
+	require.NoError(t, breaker.CheckHealth())
+	assert.False(t, breaker.open)
+}
@@ -0,0 +1,147 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/events"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/feewindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/util"
+)
+
+const defaultFollowerPollInterval = time.Second
+
+type FollowerConfig struct {
+	Logger *log.Entry
+	// DB is the database written by the ingesting process
+	DB         *db.DB
+	EventStore *events.MemoryStore
+	FeeWindows *feewindow.FeeWindows
+	// LatestLedger is the latest ledger already loaded into the in-memory stores (0 if none)
+	LatestLedger uint32
+	// PollInterval (optional) is the interval between the checks for new ledgers
+	PollInterval time.Duration
+	// OnLedgerIngested (optional) is invoked after each ledger is followed
+	OnLedgerIngested func(xdr.LedgerCloseMeta)
+	Daemon           interfaces.Daemon
+}
+
+// Follower tracks the progress of an ingesting process sharing the database (in --mode=serve):
+// it loads the ledgers committed by the ingesting process into the in-memory stores and
+// brings the database cache up to date, without ingesting anything itself.
+type Follower struct {
+	logger             *log.Entry
+	db                 *db.DB
+	ledgerReader       db.LedgerReader
+	ledgerEntryReader  db.LedgerEntryReader
+	eventStore         *events.MemoryStore
+	feeWindows         *feewindow.FeeWindows
+	onLedgerIngested   func(xdr.LedgerCloseMeta)
+	latestLedger       uint32
+	latestLedgerMetric prometheus.Gauge
+	done               context.CancelFunc
+	wg                 sync.WaitGroup
+}
+
+func NewFollower(cfg FollowerConfig) *Follower {
+	follower := newFollower(cfg)
+	pollInterval := cfg.PollInterval
+	if pollInterval == 0 {
+		pollInterval = defaultFollowerPollInterval
+	}
+	ctx, done := context.WithCancel(context.Background())
+	follower.done = done
+	follower.wg.Add(1)
+	util.UnrecoverablePanicGroup.Log(cfg.Logger).Go(func() {
+		defer follower.wg.Done()
+		follower.run(ctx, pollInterval)
+	})
+	return follower
+}
+
+func newFollower(cfg FollowerConfig) *Follower {
+	latestLedgerMetric := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: cfg.Daemon.MetricsNamespace(), Subsystem: "ingest", Name: "followed_latest_ledger",
+		Help: "sequence number of the latest ledger committed by the ingesting process and loaded by this serving instance",
+	})
+	cfg.Daemon.MetricsRegistry().MustRegister(latestLedgerMetric)
+	latestLedgerMetric.Set(float64(cfg.LatestLedger))
+	return &Follower{
+		logger:             cfg.Logger,
+		db:                 cfg.DB,
+		ledgerReader:       db.NewLedgerReader(cfg.DB),
+		ledgerEntryReader:  db.NewLedgerEntryReader(cfg.DB),
+		eventStore:         cfg.EventStore,
+		feeWindows:         cfg.FeeWindows,
+		onLedgerIngested:   cfg.OnLedgerIngested,
+		latestLedger:       cfg.LatestLedger,
+		latestLedgerMetric: latestLedgerMetric,
+	}
+}
+
+func (f *Follower) Close() error {
+	f.done()
+	f.wg.Wait()
+	return nil
+}
+
+func (f *Follower) run(ctx context.Context, pollInterval time.Duration) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := f.follow(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			// the next poll retries from the latest ledger which was followed
+			f.logger.WithError(err).Warn("could not follow the ingested ledgers")
+		}
+	}
+}
+
+// follow loads the ledgers committed to the database since the previous call
+func (f *Follower) follow(ctx context.Context) error {
+	// this reads the latest ledger from the database, not from the cache
+	latestLedger, err := f.ledgerEntryReader.GetLatestLedgerSequence(ctx)
+	if errors.Is(err, db.ErrEmptyDB) {
+		// the ingesting process didn't commit any ledger yet
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if latestLedger <= f.latestLedger {
+		return nil
+	}
+	return f.ledgerReader.StreamLedgerRange(ctx, f.latestLedger+1, latestLedger, func(lcm xdr.LedgerCloseMeta) error {
+		sequence := lcm.LedgerSequence()
+		if f.latestLedger != 0 && sequence != f.latestLedger+1 {
+			f.logger.WithField("from", f.latestLedger+1).WithField("to", sequence-1).
+				Warn("could not follow ledgers, they were trimmed from the database before being loaded")
+		}
+		if err := f.eventStore.IngestEvents(lcm); err != nil {
+			return err
+		}
+		if err := f.feeWindows.IngestFees(lcm); err != nil {
+			return err
+		}
+		f.db.ReloadCache(sequence)
+		f.latestLedger = sequence
+		f.latestLedgerMetric.Set(float64(sequence))
+		if f.onLedgerIngested != nil {
+			f.onLedgerIngested(lcm)
+		}
+		return nil
+	})
+}
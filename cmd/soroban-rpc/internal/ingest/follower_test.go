@@ -0,0 +1,83 @@
+package ingest
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/network"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/events"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/feewindow"
+)
+
+func TestFollower(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "db.sqlite")
+	daemon := interfaces.MakeNoOpDeamon()
+
+	// the ingesting process and the serving one have their own connection to the database
+	ingesterDB, err := db.OpenSQLiteDB(dbPath)
+	require.NoError(t, err)
+	defer ingesterDB.Close()
+	readWriter := db.NewReadWriter(supportlog.New(), ingesterDB, daemon, 10, 1000, network.TestNetworkPassphrase)
+	commit := func(sequences ...uint32) {
+		write, err := readWriter.NewTx(ctx)
+		require.NoError(t, err)
+		for _, sequence := range sequences {
+			require.NoError(t, write.LedgerWriter().InsertLedger(emptyLedger(sequence)))
+		}
+		require.NoError(t, write.Commit(sequences[len(sequences)-1]))
+	}
+	serverDB, err := db.OpenSQLiteDB(dbPath)
+	require.NoError(t, err)
+	defer serverDB.Close()
+
+	var followed []uint32
+	follower := newFollower(FollowerConfig{
+		Logger:     supportlog.New(),
+		DB:         serverDB,
+		EventStore: events.NewMemoryStore(daemon, network.TestNetworkPassphrase, 100),
+		FeeWindows: feewindow.NewFeeWindows(10, 10, network.TestNetworkPassphrase),
+		OnLedgerIngested: func(lcm xdr.LedgerCloseMeta) {
+			followed = append(followed, lcm.LedgerSequence())
+		},
+		Daemon: daemon,
+	})
+	latestCachedLedger := func() uint32 {
+		tx, err := db.NewLedgerEntryReader(serverDB).NewTx(ctx)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, tx.Done())
+		}()
+		latestLedger, err := tx.GetLatestLedgerSequence()
+		require.NoError(t, err)
+		return latestLedger
+	}
+
+	// nothing was ingested yet
+	require.NoError(t, follower.follow(ctx))
+	assert.Empty(t, followed)
+
+	commit(10, 11)
+	require.NoError(t, follower.follow(ctx))
+	assert.Equal(t, []uint32{10, 11}, followed)
+	assert.Equal(t, uint32(11), latestCachedLedger())
+
+	commit(12)
+	commit(13)
+	require.NoError(t, follower.follow(ctx))
+	assert.Equal(t, []uint32{10, 11, 12, 13}, followed)
+	assert.Equal(t, uint32(13), latestCachedLedger())
+
+	// the ledgers are only followed once
+	require.NoError(t, follower.follow(ctx))
+	assert.Len(t, followed, 4)
+}
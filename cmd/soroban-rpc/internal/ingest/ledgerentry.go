@@ -75,6 +75,40 @@ func (s *Service) ingestTempLedgerEntryEvictions(
 	return ctx.Err()
 }
 
+// ingestPersistentLedgerEntryEvictions keeps the persistent entries evicted into the hot archive, so
+// that they can still be queried. Their removal from the live state is ingested as a ledger entry change.
+func (s *Service) ingestPersistentLedgerEntryEvictions(
+	ctx context.Context,
+	evictedPersistentLedgerEntries []xdr.LedgerEntry,
+	tx db.WriteTx,
+) error {
+	startTime := time.Now()
+	writer := tx.LedgerEntryWriter()
+	counts := map[string]int{}
+
+	for _, entry := range evictedPersistentLedgerEntries {
+		if entry.Data.Type == xdr.LedgerEntryTypeTtl {
+			// the hot archive doesn't keep TTLs
+			continue
+		}
+		if err := writer.ArchiveLedgerEntry(entry); err != nil {
+			return err
+		}
+		counts["archived_"+entry.Data.Type.String()]++
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+
+	for archivalType, count := range counts {
+		s.metrics.ledgerStatsMetric.
+			With(prometheus.Labels{"type": archivalType}).Add(float64(count))
+	}
+	s.metrics.ingestionDurationMetric.
+		With(prometheus.Labels{"type": "archived_persistent_ledger_entries"}).Observe(time.Since(startTime).Seconds())
+	return ctx.Err()
+}
+
 func ingestLedgerEntryChange(writer db.LedgerEntryWriter, change ingest.Change) error {
 	if change.Post == nil {
 		ledgerKey, err := xdr.GetLedgerKeyFromData(change.Pre.Data)
@@ -51,6 +51,11 @@ func (m MockTx) TransactionWriter() db.TransactionWriter {
 	return args.Get(0).(db.TransactionWriter)
 }
 
+func (m MockTx) Trim(ledgerSeq uint32) error {
+	args := m.Called(ledgerSeq)
+	return args.Error(0)
+}
+
 func (m MockTx) Commit(ledgerSeq uint32) error {
 	args := m.Called(ledgerSeq)
 	return args.Error(0)
@@ -75,6 +80,11 @@ func (m MockLedgerEntryWriter) DeleteLedgerEntry(key xdr.LedgerKey) error {
 	return args.Error(0)
 }
 
+func (m MockLedgerEntryWriter) ArchiveLedgerEntry(entry xdr.LedgerEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
 type MockLedgerWriter struct {
 	mock.Mock
 }
@@ -0,0 +1,78 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+
+	backends "github.com/stellar/go/ingest/ledgerbackend"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// Replay ingests the ledgers of the given range (both ends included) into the
+// database once and returns, instead of following the network like the service.
+//
+// When the database is empty, the ledger entries are baselined from the latest
+// checkpoint preceding the range first (and the ledgers in between are ingested as well).
+// Otherwise, the range must continue the ledgers already stored in the database
+// (the ones which are already stored are skipped).
+func Replay(ctx context.Context, cfg Config, start, end uint32) error {
+	if start == 0 || start > end {
+		return fmt.Errorf("invalid ledger range [%d, %d]", start, end)
+	}
+	s := newService(cfg)
+	next, err := s.prepareReplay(ctx, cfg, start, end)
+	if err != nil {
+		return err
+	}
+	if next > end {
+		s.logger.Infof("ledgers [%d, %d] are already stored in the database, there is nothing to replay", start, end)
+		return nil
+	}
+	for sequence := next; sequence <= end; sequence++ {
+		if err := s.ingest(ctx, sequence); err != nil {
+			return fmt.Errorf("could not replay ledger %d: %w", sequence, err)
+		}
+	}
+	s.logger.Infof("replayed ledgers [%d, %d]", next, end)
+	return nil
+}
+
+// prepareReplay prepares the ledger backend (and fills the ledger entries of
+// empty databases), returning the first ledger to ingest
+func (s *Service) prepareReplay(ctx context.Context, cfg Config, start, end uint32) (uint32, error) {
+	latestLedger, err := s.db.GetLatestLedgerSequence(ctx)
+	switch {
+	case err == db.ErrEmptyDB:
+		// the ledger entries of the range can only be obtained by applying the
+		// ledger changes on top of a checkpoint
+		checkpointLedger := cfg.Archive.GetCheckpointManager().PrevCheckpoint(start - 1)
+		if checkpointLedger >= start {
+			return 0, fmt.Errorf(
+				"the database is empty, the first ledger which can be replayed is %d", checkpointLedger+1,
+			)
+		}
+		if checkpointLedger+1 < start {
+			s.logger.Infof("the database is empty, ledgers [%d, %d] will be replayed on top of checkpoint %d",
+				checkpointLedger+1, start-1, checkpointLedger)
+		}
+		err = s.fillEntriesFromCheckpoint(ctx, cfg.Archive, checkpointLedger, backends.BoundedRange(checkpointLedger, end))
+		if err != nil {
+			return 0, err
+		}
+		return checkpointLedger + 1, nil
+	case err != nil:
+		return 0, err
+	case start > latestLedger+1:
+		return 0, fmt.Errorf(
+			"the range must continue the ledgers stored in the database, ledgers [%d, %d] would be missing",
+			latestLedger+1, start-1,
+		)
+	case end <= latestLedger:
+		return end + 1, nil
+	}
+	prepareRangeCtx, cancelPrepareRange := context.WithTimeout(ctx, s.timeout)
+	defer cancelPrepareRange()
+	err = s.ledgerBackend.PrepareRange(prepareRangeCtx, backends.BoundedRange(latestLedger+1, end))
+	return latestLedger + 1, err
+}
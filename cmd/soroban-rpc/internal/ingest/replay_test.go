@@ -0,0 +1,86 @@
+package ingest
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/network"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/events"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/feewindow"
+)
+
+func emptyLedger(sequence uint32) xdr.LedgerCloseMeta {
+	return xdr.LedgerCloseMeta{
+		V: 1,
+		V1: &xdr.LedgerCloseMetaV1{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+				Header: xdr.LedgerHeader{LedgerSeq: xdr.Uint32(sequence)},
+			},
+			TxSet: xdr.GeneralizedTransactionSet{
+				V:       1,
+				V1TxSet: &xdr.TransactionSetV1{},
+			},
+		},
+	}
+}
+
+func TestReplay(t *testing.T) {
+	ctx := context.Background()
+	dbConn, err := db.OpenSQLiteDB(filepath.Join(t.TempDir(), "db.sqlite"))
+	require.NoError(t, err)
+	defer dbConn.Close()
+	daemon := interfaces.MakeNoOpDeamon()
+	readWriter := db.NewReadWriter(supportlog.New(), dbConn, daemon, 10, 1000, network.TestNetworkPassphrase)
+
+	// the database contains ledger 10
+	write, err := readWriter.NewTx(ctx)
+	require.NoError(t, err)
+	require.NoError(t, write.LedgerWriter().InsertLedger(emptyLedger(10)))
+	require.NoError(t, write.Commit(10))
+
+	backend := &ledgerbackend.MockDatabaseBackend{}
+	cfg := Config{
+		Logger:            supportlog.New(),
+		DB:                readWriter,
+		EventStore:        events.NewMemoryStore(daemon, network.TestNetworkPassphrase, 100),
+		FeeWindows:        feewindow.NewFeeWindows(1, 1, network.TestNetworkPassphrase),
+		LedgerBackend:     backend,
+		Daemon:            daemon,
+		NetworkPassPhrase: network.TestNetworkPassphrase,
+	}
+
+	err = Replay(ctx, cfg, 12, 15)
+	require.EqualError(t, err,
+		"the range must continue the ledgers stored in the database, ledgers [11, 11] would be missing")
+
+	backend.On("PrepareRange", mock.Anything, ledgerbackend.BoundedRange(11, 13)).Return(nil).Once()
+	for sequence := uint32(11); sequence <= 13; sequence++ {
+		backend.On("GetLedger", mock.Anything, sequence).Return(emptyLedger(sequence), nil).Once()
+	}
+	// the already stored ledgers are skipped
+	require.NoError(t, Replay(ctx, cfg, 9, 13))
+	backend.AssertExpectations(t)
+
+	latestLedger, err := readWriter.GetLatestLedgerSequence(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(13), latestLedger)
+	ledgerRange, err := db.NewLedgerReader(dbConn).GetLedgerRange(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(10), ledgerRange.FirstLedger.Sequence)
+	assert.Equal(t, uint32(13), ledgerRange.LastLedger.Sequence)
+
+	// nothing to do
+	require.NoError(t, Replay(ctx, cfg, 11, 13))
+	require.EqualError(t, Replay(ctx, cfg, 13, 11), "invalid ledger range [13, 11]")
+}
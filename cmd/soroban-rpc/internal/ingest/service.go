@@ -9,6 +9,8 @@ import (
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/stellar/go/historyarchive"
 	"github.com/stellar/go/ingest"
@@ -21,11 +23,15 @@ import (
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/events"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/feewindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/tracing"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/util"
 )
 
 const (
 	ledgerEntryBaselineProgressLogPeriod = 10000
+	// defaultSlowLedgerThreshold is slightly below the target ledger close time of the network,
+	// ingesting ledgers slower than that leads the node to fall behind
+	defaultSlowLedgerThreshold = 4 * time.Second
 )
 
 var errEmptyArchives = fmt.Errorf("cannot start ingestion without history archives, wait until first history archives are published")
@@ -40,7 +46,12 @@ type Config struct {
 	LedgerBackend     backends.LedgerBackend
 	Timeout           time.Duration
 	OnIngestionRetry  backoff.Notify
-	Daemon            interfaces.Daemon
+	// OnLedgerIngested (optional) is invoked after each ledger is committed
+	OnLedgerIngested func(xdr.LedgerCloseMeta)
+	// SlowLedgerThreshold (optional) is the ingestion duration above which
+	// the per-stage breakdown of a ledger is logged as a warning
+	SlowLedgerThreshold time.Duration
+	Daemon              interfaces.Daemon
 }
 
 func NewService(cfg Config) *Service {
@@ -64,6 +75,17 @@ func newService(cfg Config) *Service {
 		Help: "sequence number of the latest ledger ingested by this ingesting instance",
 	})
 
+	// ledgerCloseTimeLagMetric measures how far behind the network the ingestion is
+	ledgerCloseTimeLagMetric := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: cfg.Daemon.MetricsNamespace(), Subsystem: "ingest", Name: "ledger_close_time_lag_seconds",
+		Help: "seconds elapsed between the close time of the latest ledger and the end of its ingestion",
+	})
+	// latestLedgerCloseTimeMetric is the close time of the latest ingested ledger
+	latestLedgerCloseTimeMetric := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: cfg.Daemon.MetricsNamespace(), Subsystem: "ingest", Name: "local_latest_ledger_close_time_seconds",
+		Help: "close time (unix timestamp) of the latest ledger ingested by this ingesting instance",
+	})
+
 	// ledgerStatsMetric is a metric which measures statistics on all ledger entries ingested by soroban rpc
 	ledgerStatsMetric := prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -76,20 +98,31 @@ func newService(cfg Config) *Service {
 	cfg.Daemon.MetricsRegistry().MustRegister(
 		ingestionDurationMetric,
 		latestLedgerMetric,
+		ledgerCloseTimeLagMetric,
+		latestLedgerCloseTimeMetric,
 		ledgerStatsMetric)
 
+	slowLedgerThreshold := cfg.SlowLedgerThreshold
+	if slowLedgerThreshold == 0 {
+		slowLedgerThreshold = defaultSlowLedgerThreshold
+	}
+
 	service := &Service{
-		logger:            cfg.Logger,
-		db:                cfg.DB,
-		eventStore:        cfg.EventStore,
-		feeWindows:        cfg.FeeWindows,
-		ledgerBackend:     cfg.LedgerBackend,
-		networkPassPhrase: cfg.NetworkPassPhrase,
-		timeout:           cfg.Timeout,
+		logger:              cfg.Logger,
+		db:                  cfg.DB,
+		eventStore:          cfg.EventStore,
+		feeWindows:          cfg.FeeWindows,
+		ledgerBackend:       cfg.LedgerBackend,
+		networkPassPhrase:   cfg.NetworkPassPhrase,
+		timeout:             cfg.Timeout,
+		onLedgerIngested:    cfg.OnLedgerIngested,
+		slowLedgerThreshold: slowLedgerThreshold,
 		metrics: Metrics{
-			ingestionDurationMetric: ingestionDurationMetric,
-			latestLedgerMetric:      latestLedgerMetric,
-			ledgerStatsMetric:       ledgerStatsMetric,
+			ingestionDurationMetric:     ingestionDurationMetric,
+			latestLedgerMetric:          latestLedgerMetric,
+			ledgerCloseTimeLagMetric:    ledgerCloseTimeLagMetric,
+			latestLedgerCloseTimeMetric: latestLedgerCloseTimeMetric,
+			ledgerStatsMetric:           ledgerStatsMetric,
 		},
 	}
 
@@ -125,9 +158,38 @@ func startService(service *Service, cfg Config) {
 }
 
 type Metrics struct {
-	ingestionDurationMetric *prometheus.SummaryVec
-	latestLedgerMetric      prometheus.Gauge
-	ledgerStatsMetric       *prometheus.CounterVec
+	ingestionDurationMetric     *prometheus.SummaryVec
+	latestLedgerMetric          prometheus.Gauge
+	ledgerCloseTimeLagMetric    prometheus.Gauge
+	latestLedgerCloseTimeMetric prometheus.Gauge
+	ledgerStatsMetric           *prometheus.CounterVec
+}
+
+// stageDuration is the time taken by one of the stages of the ingestion of a ledger
+type stageDuration struct {
+	stage    string
+	duration time.Duration
+}
+
+// ingestionStages times the stages of the ingestion of a ledger, in order
+type ingestionStages struct {
+	metric    *prometheus.SummaryVec
+	durations []stageDuration
+}
+
+// record observes the time elapsed since start as the duration of the given stage
+func (s *ingestionStages) record(stage string, start time.Time) {
+	duration := time.Since(start)
+	s.durations = append(s.durations, stageDuration{stage: stage, duration: duration})
+	s.metric.With(prometheus.Labels{"type": stage}).Observe(duration.Seconds())
+}
+
+func (s *ingestionStages) fields() log.F {
+	fields := log.F{}
+	for _, d := range s.durations {
+		fields[d.stage+"_duration"] = d.duration.Seconds()
+	}
+	return fields
 }
 
 type Service struct {
@@ -138,9 +200,12 @@ type Service struct {
 	ledgerBackend     backends.LedgerBackend
 	timeout           time.Duration
 	networkPassPhrase string
-	done              context.CancelFunc
-	wg                sync.WaitGroup
-	metrics           Metrics
+	onLedgerIngested  func(xdr.LedgerCloseMeta)
+	// slowLedgerThreshold is the ingestion duration above which a ledger is logged as slow
+	slowLedgerThreshold time.Duration
+	done                context.CancelFunc
+	wg                  sync.WaitGroup
+	metrics             Metrics
 }
 
 func (s *Service) Close() error {
@@ -163,6 +228,10 @@ func (s *Service) run(ctx context.Context, archive historyarchive.ArchiveInterfa
 	}
 
 	for ; ; nextLedgerSeq++ {
+		// stop between ledgers when the service is closed
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if err := s.ingest(ctx, nextLedgerSeq); err != nil {
 			return err
 		}
@@ -188,7 +257,7 @@ func (s *Service) maybeFillEntriesFromCheckpoint(ctx context.Context, archive hi
 		s.logger.Infof("found an empty database, creating ledger-entry baseline from the most recent checkpoint (%d). This can take up to 30 minutes, depending on the network", checkpointLedger)
 		panicGroup := util.UnrecoverablePanicGroup.Log(s.logger)
 		panicGroup.Go(func() {
-			checkPointFillErr <- s.fillEntriesFromCheckpoint(ctx, archive, checkpointLedger)
+			checkPointFillErr <- s.fillEntriesFromCheckpoint(ctx, archive, checkpointLedger, backends.UnboundedRange(checkpointLedger))
 		})
 		return checkpointLedger + 1, checkPointFillErr, nil
 	} else if err != nil {
@@ -204,7 +273,14 @@ func (s *Service) maybeFillEntriesFromCheckpoint(ctx context.Context, archive hi
 	}
 }
 
-func (s *Service) fillEntriesFromCheckpoint(ctx context.Context, archive historyarchive.ArchiveInterface, checkpointLedger uint32) error {
+// fillEntriesFromCheckpoint fills the ledger entries of the checkpoint, while preparing
+// the given range of the ledger backend (which must start with the checkpoint)
+func (s *Service) fillEntriesFromCheckpoint(
+	ctx context.Context,
+	archive historyarchive.ArchiveInterface,
+	checkpointLedger uint32,
+	ledgerRange backends.Range,
+) error {
 	var cancel context.CancelFunc
 	ctx, cancel = context.WithTimeout(ctx, s.timeout)
 	defer cancel()
@@ -221,7 +297,7 @@ func (s *Service) fillEntriesFromCheckpoint(ctx context.Context, archive history
 
 	prepareRangeErr := make(chan error, 1)
 	go func() {
-		prepareRangeErr <- s.ledgerBackend.PrepareRange(ctx, backends.UnboundedRange(checkpointLedger))
+		prepareRangeErr <- s.ledgerBackend.PrepareRange(ctx, ledgerRange)
 	}()
 
 	transactionCommitted := false
@@ -262,12 +338,25 @@ func (s *Service) fillEntriesFromCheckpoint(ctx context.Context, archive history
 	return nil
 }
 
-func (s *Service) ingest(ctx context.Context, sequence uint32) error {
-	s.logger.Infof("Ingesting ledger %d", sequence)
-	ledgerCloseMeta, err := s.ledgerBackend.GetLedger(ctx, sequence)
+func (s *Service) ingest(ctx context.Context, sequence uint32) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "ingest.ledger",
+		trace.WithAttributes(attribute.Int64("ledger", int64(sequence)), tracing.NetworkAttribute(s.networkPassPhrase)))
+	defer func() { tracing.End(span, err) }()
+	s.logger.WithField("ledger", sequence).Infof("Ingesting ledger %d", sequence)
+	stages := &ingestionStages{metric: s.metrics.ingestionDurationMetric}
+	fetchStartTime := time.Now()
+	getLedgerCtx, getLedgerSpan := tracing.Tracer().Start(ctx, "ingest.getLedger")
+	ledgerCloseMeta, err := s.ledgerBackend.GetLedger(getLedgerCtx, sequence)
+	tracing.End(getLedgerSpan, err)
 	if err != nil {
 		return err
 	}
+	// Note that fetching the meta blocks until the ledger is closed when ingestion is caught up,
+	// so it is not counted in the total ingestion duration
+	stages.record("meta_fetch", fetchStartTime)
+	// Once fetched, the ledger is fully ingested and committed even if the service is being closed,
+	// so that shutting down never leaves the ledger half-applied to the in-memory stores
+	ctx = context.WithoutCancel(ctx)
 
 	startTime := time.Now()
 	reader, err := ingest.NewLedgerChangeReaderFromLedgerCloseMeta(s.networkPassPhrase, ledgerCloseMeta)
@@ -284,7 +373,11 @@ func (s *Service) ingest(ctx context.Context, sequence uint32) error {
 		}
 	}()
 
-	if err := s.ingestLedgerEntryChanges(ctx, reader, tx, 0); err != nil {
+	entriesStartTime := time.Now()
+	entriesCtx, entriesSpan := tracing.Tracer().Start(ctx, "ingest.ledgerEntryChanges")
+	err = s.ingestLedgerEntryChanges(entriesCtx, reader, tx, 0)
+	tracing.End(entriesSpan, err)
+	if err != nil {
 		return err
 	}
 
@@ -301,49 +394,90 @@ func (s *Service) ingest(ctx context.Context, sequence uint32) error {
 	if err := s.ingestTempLedgerEntryEvictions(ctx, evictedTempLedgerKeys, tx); err != nil {
 		return err
 	}
+	evictedPersistentLedgerEntries, err := ledgerCloseMeta.EvictedPersistentLedgerEntries()
+	if err != nil {
+		return err
+	}
+	if err := s.ingestPersistentLedgerEntryEvictions(ctx, evictedPersistentLedgerEntries, tx); err != nil {
+		return err
+	}
+	stages.record("ledger_entries", entriesStartTime)
 
-	if err := s.ingestLedgerCloseMeta(tx, ledgerCloseMeta); err != nil {
+	_, metaSpan := tracing.Tracer().Start(ctx, "ingest.ledgerCloseMeta")
+	err = s.ingestLedgerCloseMeta(tx, ledgerCloseMeta, stages)
+	tracing.End(metaSpan, err)
+	if err != nil {
+		return err
+	}
+
+	trimStartTime := time.Now()
+	_, trimSpan := tracing.Tracer().Start(ctx, "ingest.trim")
+	err = tx.Trim(sequence)
+	tracing.End(trimSpan, err)
+	if err != nil {
 		return err
 	}
+	stages.record("trim", trimStartTime)
 
-	if err := tx.Commit(sequence); err != nil {
+	commitStartTime := time.Now()
+	_, commitSpan := tracing.Tracer().Start(ctx, "ingest.commit")
+	err = tx.Commit(sequence)
+	tracing.End(commitSpan, err)
+	if err != nil {
 		return err
 	}
-	s.logger.
-		WithField("duration", time.Since(startTime).Seconds()).
-		Debugf("Ingested ledger %d", sequence)
+	stages.record("commit", commitStartTime)
+	if s.onLedgerIngested != nil {
+		s.onLedgerIngested(ledgerCloseMeta)
+	}
+
+	duration := time.Since(startTime)
+	closeTime := time.Unix(int64(ledgerCloseMeta.LedgerHeaderHistoryEntry().Header.ScpValue.CloseTime), 0)
+	closeTimeLag := time.Since(closeTime)
+	logger := s.logger.
+		WithField("ledger", sequence).
+		WithField("duration", duration.Seconds()).
+		WithField("close_time_lag", closeTimeLag.Seconds()).
+		WithFields(stages.fields())
+	if duration > s.slowLedgerThreshold {
+		logger.Warnf("Slow ingestion of ledger %d", sequence)
+	} else {
+		logger.Debugf("Ingested ledger %d", sequence)
+	}
 
 	s.metrics.ingestionDurationMetric.
 		With(prometheus.Labels{"type": "total"}).
-		Observe(time.Since(startTime).Seconds())
+		Observe(duration.Seconds())
 	s.metrics.latestLedgerMetric.Set(float64(sequence))
+	s.metrics.latestLedgerCloseTimeMetric.Set(float64(closeTime.Unix()))
+	s.metrics.ledgerCloseTimeLagMetric.Set(closeTimeLag.Seconds())
 	return nil
 }
 
-func (s *Service) ingestLedgerCloseMeta(tx db.WriteTx, ledgerCloseMeta xdr.LedgerCloseMeta) error {
+func (s *Service) ingestLedgerCloseMeta(tx db.WriteTx, ledgerCloseMeta xdr.LedgerCloseMeta, stages *ingestionStages) error {
 	startTime := time.Now()
 	if err := tx.LedgerWriter().InsertLedger(ledgerCloseMeta); err != nil {
 		return err
 	}
-	s.metrics.ingestionDurationMetric.
-		With(prometheus.Labels{"type": "ledger_close_meta"}).
-		Observe(time.Since(startTime).Seconds())
+	stages.record("ledger_close_meta", startTime)
 
 	startTime = time.Now()
 	if err := tx.TransactionWriter().InsertTransactions(ledgerCloseMeta); err != nil {
 		return err
 	}
-	s.metrics.ingestionDurationMetric.
-		With(prometheus.Labels{"type": "transactions"}).
-		Observe(time.Since(startTime).Seconds())
+	stages.record("transactions", startTime)
 
+	startTime = time.Now()
 	if err := s.eventStore.IngestEvents(ledgerCloseMeta); err != nil {
 		return err
 	}
+	stages.record("events", startTime)
 
+	startTime = time.Now()
 	if err := s.feeWindows.IngestFees(ledgerCloseMeta); err != nil {
 		return err
 	}
+	stages.record("fees", startTime)
 
 	return nil
 }
@@ -8,7 +8,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/stellar/go/ingest/ledgerbackend"
 	"github.com/stellar/go/network"
@@ -66,14 +68,18 @@ func TestIngestion(t *testing.T) {
 	mockLedgerBackend := &ledgerbackend.MockDatabaseBackend{}
 
 	daemon := interfaces.MakeNoOpDeamon()
+	logger := supportlog.New()
+	logs := logger.StartTest(logrus.WarnLevel)
 	config := Config{
-		Logger:            supportlog.New(),
+		Logger:            logger,
 		DB:                mockDB,
 		EventStore:        events.NewMemoryStore(daemon, network.TestNetworkPassphrase, 1),
 		FeeWindows:        feewindow.NewFeeWindows(1, 1, network.TestNetworkPassphrase),
 		LedgerBackend:     mockLedgerBackend,
 		Daemon:            daemon,
 		NetworkPassPhrase: network.TestNetworkPassphrase,
+		// make sure the breakdown of the ledger is logged
+		SlowLedgerThreshold: time.Nanosecond,
 	}
 	sequence := uint32(3)
 	service := newService(config)
@@ -82,10 +88,11 @@ func TestIngestion(t *testing.T) {
 	mockLedgerWriter := &MockLedgerWriter{}
 	mockTxWriter := &MockTransactionWriter{}
 	ctx := context.Background()
-	mockDB.On("NewTx", ctx).Return(mockTx, nil).Once()
+	mockDB.On("NewTx", mock.Anything).Return(mockTx, nil).Once()
+	mockTx.On("Trim", sequence).Return(nil).Once()
 	mockTx.On("Commit", sequence).Return(nil).Once()
 	mockTx.On("Rollback").Return(nil).Once()
-	mockTx.On("LedgerEntryWriter").Return(mockLedgerEntryWriter).Twice()
+	mockTx.On("LedgerEntryWriter").Return(mockLedgerEntryWriter).Times(3)
 	mockTx.On("LedgerWriter").Return(mockLedgerWriter).Once()
 	mockTx.On("TransactionWriter").Return(mockTxWriter).Once()
 
@@ -243,7 +250,7 @@ func TestIngestion(t *testing.T) {
 			EvictedPersistentLedgerEntries: []xdr.LedgerEntry{evictedPersistentLedgerEntry},
 		},
 	}
-	mockLedgerBackend.On("GetLedger", ctx, sequence).Return(ledger, nil).Once()
+	mockLedgerBackend.On("GetLedger", mock.Anything, sequence).Return(ledger, nil).Once()
 	mockLedgerEntryWriter.On("UpsertLedgerEntry", operationChanges[1].MustUpdated()).
 		Return(nil).Once()
 	evictedPresistentLedgerKey, err := evictedPersistentLedgerEntry.LedgerKey()
@@ -252,13 +259,75 @@ func TestIngestion(t *testing.T) {
 		Return(nil).Once()
 	mockLedgerEntryWriter.On("DeleteLedgerEntry", evictedTempLedgerKey).
 		Return(nil).Once()
+	mockLedgerEntryWriter.On("ArchiveLedgerEntry", evictedPersistentLedgerEntry).
+		Return(nil).Once()
 	mockLedgerWriter.On("InsertLedger", ledger).Return(nil).Once()
 	mockTxWriter.On("InsertTransactions", ledger).Return(nil).Once()
 	assert.NoError(t, service.ingest(ctx, sequence))
 
+	entries := logs()
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "Slow ingestion of ledger 3", entries[0].Message)
+		for _, stage := range []string{
+			"meta_fetch", "ledger_entries", "ledger_close_meta", "transactions", "events", "fees", "trim", "commit",
+		} {
+			assert.Contains(t, entries[0].Data, stage+"_duration")
+		}
+		assert.Contains(t, entries[0].Data, "close_time_lag")
+	}
+
 	mockDB.AssertExpectations(t)
 	mockTx.AssertExpectations(t)
 	mockLedgerEntryWriter.AssertExpectations(t)
 	mockLedgerWriter.AssertExpectations(t)
 	mockLedgerBackend.AssertExpectations(t)
 }
+
+func TestIngestionCommitsFetchedLedgerWhenClosed(t *testing.T) {
+	mockDB := &MockDB{}
+	mockLedgerBackend := &ledgerbackend.MockDatabaseBackend{}
+	daemon := interfaces.MakeNoOpDeamon()
+	service := newService(Config{
+		Logger:            supportlog.New(),
+		DB:                mockDB,
+		EventStore:        events.NewMemoryStore(daemon, network.TestNetworkPassphrase, 1),
+		FeeWindows:        feewindow.NewFeeWindows(1, 1, network.TestNetworkPassphrase),
+		LedgerBackend:     mockLedgerBackend,
+		Daemon:            daemon,
+		NetworkPassPhrase: network.TestNetworkPassphrase,
+	})
+	sequence := uint32(3)
+	ledger := xdr.LedgerCloseMeta{
+		V: 1,
+		V1: &xdr.LedgerCloseMetaV1{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+				Header: xdr.LedgerHeader{LedgerSeq: xdr.Uint32(sequence)},
+			},
+			TxSet: xdr.GeneralizedTransactionSet{
+				V:       1,
+				V1TxSet: &xdr.TransactionSetV1{Phases: []xdr.TransactionPhase{}},
+			},
+		},
+	}
+	mockTx := &MockTx{}
+	mockLedgerWriter := &MockLedgerWriter{}
+	mockTxWriter := &MockTransactionWriter{}
+	ctx, cancel := context.WithCancel(context.Background())
+	// the service is closed while the ledger is being fetched
+	mockLedgerBackend.On("GetLedger", mock.Anything, sequence).
+		Run(func(mock.Arguments) { cancel() }).
+		Return(ledger, nil).Once()
+	mockDB.On("NewTx", mock.Anything).Return(mockTx, nil).Once()
+	mockTx.On("LedgerEntryWriter").Return(&MockLedgerEntryWriter{}).Maybe()
+	mockTx.On("LedgerWriter").Return(mockLedgerWriter).Once()
+	mockTx.On("TransactionWriter").Return(mockTxWriter).Once()
+	mockTx.On("Trim", sequence).Return(nil).Once()
+	mockTx.On("Commit", sequence).Return(nil).Once()
+	mockTx.On("Rollback").Return(nil).Once()
+	mockLedgerWriter.On("InsertLedger", ledger).Return(nil).Once()
+	mockTxWriter.On("InsertTransactions", ledger).Return(nil).Once()
+
+	assert.NoError(t, service.ingest(ctx, sequence))
+	mockTx.AssertExpectations(t)
+	mockLedgerBackend.AssertExpectations(t)
+}
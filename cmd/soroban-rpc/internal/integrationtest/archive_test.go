@@ -10,7 +10,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
-	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/integrationtest/infrastructure"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/integrationtest/infrastructure"
 )
 
 func TestArchiveUserAgent(t *testing.T) {
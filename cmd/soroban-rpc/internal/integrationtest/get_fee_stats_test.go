@@ -10,7 +10,7 @@ import (
 	"github.com/stellar/go/txnbuild"
 	"github.com/stellar/go/xdr"
 
-	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/integrationtest/infrastructure"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/integrationtest/infrastructure"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/methods"
 )
 
@@ -78,7 +78,7 @@ func TestGetFeeStats(t *testing.T) {
 			TransactionCount: 1,
 			LedgerCount:      result.InclusionFee.LedgerCount,
 		},
-		LatestLedger: result.LatestLedger,
+		LedgerRangeResponse: result.LedgerRangeResponse,
 	}
 	assert.Equal(t, expectedResult, result)
 
@@ -6,8 +6,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/integrationtest/infrastructure"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
-	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/integrationtest/infrastructure"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/methods"
 )
 
@@ -29,4 +29,6 @@ func TestGetVersionInfoSucceeds(t *testing.T) {
 	assert.Equal(t, "commitHash", result.CommitHash)
 	assert.Equal(t, test.GetProtocolVersion(), result.ProtocolVersion)
 	assert.NotEmpty(t, result.CaptiveCoreVersion)
+	assert.Equal(t, infrastructure.StandaloneNetworkPassphrase, result.Passphrase)
+	assert.Equal(t, infrastructure.FriendbotURL, result.FriendbotURL)
 }
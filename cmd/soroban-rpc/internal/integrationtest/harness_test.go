@@ -0,0 +1,41 @@
+package integrationtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/client"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/integrationtest/infrastructure"
+)
+
+func TestHarnessDeployAndInvokeContract(t *testing.T) {
+	test := infrastructure.NewTest(t, nil)
+
+	account := test.CreateAccount("100")
+	contractID, wasmHash := test.DeployContract(infrastructure.GetHelloWorldContract())
+	// The same code can be deployed more than once
+	otherContractID, otherWasmHash := test.DeployContract(infrastructure.GetHelloWorldContract())
+	assert.NotEqual(t, contractID, otherContractID)
+	assert.Equal(t, wasmHash, otherWasmHash)
+
+	world := xdr.ScSymbol("world")
+	op := infrastructure.CreateInvokeHostOperation(account.Address(), contractID, "hello", xdr.ScVal{
+		Type: xdr.ScValTypeScvSymbol,
+		Sym:  &world,
+	})
+	response := test.SubmitOperations(account, op)
+	require.Equal(t, client.TransactionStatusSuccess, response.Status)
+
+	latest, err := test.GetClient().GetLatestLedger(context.Background())
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, latest.Sequence, response.Ledger)
+
+	accountAfter := test.GetAccount(account.Address())
+	assert.Equal(t, account.Address(), accountAfter.AccountID)
+	assert.NotZero(t, accountAfter.Sequence)
+}
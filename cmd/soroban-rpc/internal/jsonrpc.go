@@ -3,6 +3,7 @@ package internal
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -11,31 +12,40 @@ import (
 	"github.com/creachadair/jrpc2"
 	"github.com/creachadair/jrpc2/handler"
 	"github.com/creachadair/jrpc2/jhttp"
+	"github.com/creachadair/jrpc2/server"
 	"github.com/go-chi/chi/middleware"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/cors"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/render/problem"
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/events"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/feewindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/graphql"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/horizon"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/methods"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/network"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/tracing"
 )
 
-// maxHTTPRequestSize defines the largest request size that the http handler
-// would be willing to accept before dropping the request. The implementation
-// uses the default MaxBytesHandler to limit the request size.
-const maxHTTPRequestSize = 512 * 1024 // half a megabyte
-
 // Handler is the HTTP handler which serves the Soroban JSON RPC responses
 type Handler struct {
 	bridge jhttp.Bridge
+	local  *server.Local
 	logger *log.Entry
 	http.Handler
+	// GraphQLHandler serves GraphQL queries, it is nil unless enabled in the configuration
+	GraphQLHandler http.Handler
+	// HorizonHandler serves the Horizon-compatible REST endpoints, it is nil unless enabled in the configuration
+	HorizonHandler http.Handler
+	// SubscriptionHandler serves the WebSocket subscriptions (see HandlerParams), it is nil unless enabled in the configuration
+	SubscriptionHandler http.Handler
 }
 
 // Close closes all the resources held by the Handler instances.
@@ -44,6 +54,11 @@ func (h Handler) Close() {
 	if err := h.bridge.Close(); err != nil {
 		h.logger.WithError(err).Warn("could not close bridge")
 	}
+	if h.local != nil {
+		if err := h.local.Close(); err != nil {
+			h.logger.WithError(err).Warn("could not close GraphQL server")
+		}
+	}
 }
 
 type HandlerParams struct {
@@ -54,10 +69,21 @@ type HandlerParams struct {
 	LedgerReader      db.LedgerReader
 	Logger            *log.Entry
 	PreflightGetter   methods.PreflightGetter
+	PreflightChecker  methods.PreflightCompatibilityChecker
+	TransactionHints  methods.TransactionHints
+	NodeHealthChecker methods.NodeHealthChecker
+	AccessLogger      *log.Entry
+	DatabaseSizer     methods.DatabaseSizer
 	Daemon            interfaces.Daemon
+	// RequestDurationObserver (optional) is told the duration of every request
+	RequestDurationObserver func(time.Duration)
+	// SubscriptionHandler (optional) serves the WebSocket subscriptions, it is rate-limited like the HTTP endpoints
+	SubscriptionHandler http.Handler
 }
 
-func decorateHandlers(daemon interfaces.Daemon, logger *log.Entry, m handler.Map) handler.Map {
+func decorateHandlers(
+	daemon interfaces.Daemon, logger *log.Entry, networkPassphrase string, observeDuration func(time.Duration), m handler.Map,
+) handler.Map {
 	requestMetric := prometheus.NewSummaryVec(prometheus.SummaryOpts{
 		Namespace:  daemon.MetricsNamespace(),
 		Subsystem:  "json_rpc",
@@ -65,16 +91,55 @@ func decorateHandlers(daemon interfaces.Daemon, logger *log.Entry, m handler.Map
 		Help:       "JSON RPC request duration",
 		Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
 	}, []string{"endpoint", "status"})
+	// unlike the summary above, histograms can be aggregated across nodes
+	durationHistogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: daemon.MetricsNamespace(),
+		Subsystem: "json_rpc",
+		Name:      "method_request_duration_seconds",
+		Help:      "JSON RPC request duration, by method and outcome",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 15),
+	}, []string{"endpoint", "status"})
+	requestSizeHistogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: daemon.MetricsNamespace(),
+		Subsystem: "json_rpc",
+		Name:      "method_request_size_bytes",
+		Help:      "JSON RPC request parameters size, by method and outcome",
+		Buckets:   prometheus.ExponentialBuckets(64, 4, 10),
+	}, []string{"endpoint", "status"})
+	responseSizeHistogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: daemon.MetricsNamespace(),
+		Subsystem: "json_rpc",
+		Name:      "method_response_size_bytes",
+		Help:      "JSON RPC response result size, by method and outcome",
+		Buckets:   prometheus.ExponentialBuckets(64, 4, 10),
+	}, []string{"endpoint", "status"})
+	inflightGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: daemon.MetricsNamespace(),
+		Subsystem: "json_rpc",
+		Name:      "method_inflight_requests",
+		Help:      "Number of JSON RPC requests being executed, by method",
+	}, []string{"endpoint"})
 	decorated := handler.Map{}
 	for endpoint, h := range m {
 		// create copy of h so it can be used in closure bleow
 		h := h
+		inflight := inflightGauge.WithLabelValues(endpoint)
 		decorated[endpoint] = handler.New(func(ctx context.Context, r *jrpc2.Request) (interface{}, error) {
 			reqID := strconv.FormatUint(middleware.NextRequestID(), 10)
 			logRequest(logger, reqID, r)
+			ctx, span := tracing.Tracer().Start(ctx, "jsonrpc."+r.Method(),
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.RPCSystemKey.String("jsonrpc"),
+					semconv.RPCMethod(r.Method()),
+					tracing.NetworkAttribute(networkPassphrase),
+				))
+			inflight.Inc()
 			startTime := time.Now()
 			result, err := h(ctx, r)
 			duration := time.Since(startTime)
+			inflight.Dec()
+			tracing.End(span, err)
 			label := prometheus.Labels{"endpoint": r.Method(), "status": "ok"}
 			simulateTransactionResponse, ok := result.(methods.SimulateTransactionResponse)
 			if ok && simulateTransactionResponse.Error != "" {
@@ -86,12 +151,23 @@ func decorateHandlers(daemon interfaces.Daemon, logger *log.Entry, m handler.Map
 					label["status"] = status
 				}
 			}
+			var responseBytes []byte
+			if err == nil {
+				// the encoding error (if any) is reported by the JSON RPC server
+				responseBytes, _ = json.Marshal(result)
+			}
 			requestMetric.With(label).Observe(duration.Seconds())
-			logResponse(logger, reqID, duration, label["status"], result)
+			durationHistogram.With(label).Observe(duration.Seconds())
+			requestSizeHistogram.With(label).Observe(float64(len(r.ParamString())))
+			responseSizeHistogram.With(label).Observe(float64(len(responseBytes)))
+			logResponse(logger, reqID, r, duration, label["status"], responseBytes)
+			if observeDuration != nil {
+				observeDuration(duration)
+			}
 			return result, err
 		})
 	}
-	daemon.MetricsRegistry().MustRegister(requestMetric)
+	daemon.MetricsRegistry().MustRegister(requestMetric, durationHistogram, requestSizeHistogram, responseSizeHistogram, inflightGauge)
 	return decorated
 }
 
@@ -109,26 +185,38 @@ func logRequest(logger *log.Entry, reqID string, req *jrpc2.Request) {
 	logger.Debug("starting JSONRPC request params")
 }
 
-func logResponse(logger *log.Entry, reqID string, duration time.Duration, status string, response any) {
+func logResponse(logger *log.Entry, reqID string, req *jrpc2.Request, duration time.Duration, status string, responseBytes []byte) {
 	logger = logger.WithFields(log.F{
 		"subsys":   "jsonrpc",
 		"req":      reqID,
-		"duration": duration.String(),
-		"json_req": reqID,
+		"json_req": req.ID(),
+		"method":   req.Method(),
+		"duration": duration.Seconds(),
 		"status":   status,
 	})
 	logger.Info("finished JSONRPC request")
 
-	if status == "ok" {
-		responseBytes, err := json.Marshal(response)
-		if err == nil {
-			// the result is useful but can be really verbose, let's only print it with debug level
-			logger = logger.WithField("result", string(responseBytes))
-			logger.Debug("finished JSONRPC request result")
-		}
+	if status == "ok" && responseBytes != nil {
+		// the result is useful but can be really verbose, let's only print it with debug level
+		logger = logger.WithField("result", string(responseBytes))
+		logger.Debug("finished JSONRPC request result")
 	}
 }
 
+func corsOptions(cfg *config.Config) cors.Options {
+	options := cors.Options{
+		AllowedOrigins: cfg.CORSAllowedOrigins,
+		AllowedHeaders: cfg.CORSAllowedHeaders,
+		AllowedMethods: cfg.CORSAllowedMethods,
+		MaxAge:         int(cfg.CORSMaxAge.Seconds()),
+	}
+	if len(cfg.CORSAllowedOrigins) == 0 {
+		// no allowlist, echo back any origin
+		options.AllowOriginRequestFunc = func(*http.Request, string) bool { return true }
+	}
+	return options
+}
+
 // NewJSONRPCHandler constructs a Handler instance
 func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 	bridgeOptions := jhttp.BridgeOptions{
@@ -138,7 +226,17 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 	}
 
 	retentionWindow := cfg.HistoryRetentionWindow
+	requestLimits := methods.RequestLimits{
+		MaxEventsLedgerRange: cfg.MaxEventsLedgerRange,
+		MaxEventFilters:      cfg.MaxEventFilters,
+		MaxEventContractIDs:  cfg.MaxEventFilterContractIDs,
+		MaxEventTopics:       cfg.MaxEventFilterTopics,
+		MaxLedgerEntriesKeys: cfg.MaxLedgerEntriesKeys,
+	}
+	// the limits are validated when loading the configuration
+	paramsSizeLimits, _ := config.ParseMethodLimits(cfg.MaxRequestParamsSize)
 
+	submittedTransactions := methods.NewSubmittedTransactions()
 	handlers := []struct {
 		methodName           string
 		underlyingHandler    jrpc2.Handler
@@ -149,7 +247,9 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 		{
 			methodName: "getHealth",
 			underlyingHandler: methods.NewHealthCheck(
-				retentionWindow, params.TransactionReader, cfg.MaxHealthyLedgerLatency),
+				retentionWindow, params.TransactionReader, params.LedgerReader,
+				params.PreflightChecker, cfg.MaxHealthyLedgerLatency,
+				params.Daemon, params.EventStore, params.DatabaseSizer, cfg.ProtocolUpgrades()),
 			longName:             "get_health",
 			queueLimit:           cfg.RequestBacklogGetHealthQueueLimit,
 			requestDurationLimit: cfg.MaxGetHealthExecutionDuration,
@@ -157,11 +257,18 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 		{
 			methodName: "getEvents",
 			underlyingHandler: methods.NewGetEventsHandler(
-				params.EventStore, cfg.MaxEventsLimit, cfg.DefaultEventsLimit),
+				params.EventStore, cfg.MaxEventsLimit, cfg.DefaultEventsLimit, requestLimits),
 			longName:             "get_events",
 			queueLimit:           cfg.RequestBacklogGetEventsQueueLimit,
 			requestDurationLimit: cfg.MaxGetEventsExecutionDuration,
 		},
+		{
+			methodName:           "getEvent",
+			underlyingHandler:    methods.NewGetEventHandler(params.EventStore),
+			longName:             "get_event",
+			queueLimit:           cfg.RequestBacklogGetEventQueueLimit,
+			requestDurationLimit: cfg.MaxGetEventExecutionDuration,
+		},
 		{
 			methodName:           "getNetwork",
 			underlyingHandler:    methods.NewGetNetworkHandler(params.Daemon, cfg.NetworkPassphrase, cfg.FriendbotURL),
@@ -171,7 +278,7 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 		},
 		{
 			methodName:           "getVersionInfo",
-			underlyingHandler:    methods.NewGetVersionInfoHandler(params.Logger, params.LedgerEntryReader, params.LedgerReader, params.Daemon),
+			underlyingHandler:    methods.NewGetVersionInfoHandler(params.Logger, params.LedgerEntryReader, params.LedgerReader, params.Daemon, cfg.NetworkPassphrase, cfg.FriendbotURL),
 			longName:             "get_version_info",
 			queueLimit:           cfg.RequestBacklogGetVersionInfoQueueLimit,
 			requestDurationLimit: cfg.MaxGetVersionInfoExecutionDuration,
@@ -192,14 +299,23 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 		},
 		{
 			methodName:           "getLedgerEntries",
-			underlyingHandler:    methods.NewGetLedgerEntriesHandler(params.Logger, params.LedgerEntryReader),
+			underlyingHandler:    methods.NewGetLedgerEntriesHandler(params.Logger, params.LedgerEntryReader, requestLimits),
 			longName:             "get_ledger_entries",
 			queueLimit:           cfg.RequestBacklogGetLedgerEntriesQueueLimit,
 			requestDurationLimit: cfg.MaxGetLedgerEntriesExecutionDuration,
 		},
 		{
-			methodName:           "getTransaction",
-			underlyingHandler:    methods.NewGetTransactionHandler(params.Logger, params.TransactionReader),
+			methodName: "getContractEntries",
+			underlyingHandler: methods.NewGetContractEntriesHandler(
+				params.Logger, params.LedgerEntryReader, cfg.MaxContractEntriesLimit, cfg.DefaultContractEntriesLimit),
+			longName:             "get_contract_entries",
+			queueLimit:           cfg.RequestBacklogGetContractEntriesQueueLimit,
+			requestDurationLimit: cfg.MaxGetContractEntriesExecutionDuration,
+		},
+		{
+			methodName: "getTransaction",
+			underlyingHandler: methods.NewGetTransactionHandler(
+				params.Logger, params.TransactionReader, params.TransactionHints, cfg.NetworkPassphrase),
 			longName:             "get_transaction",
 			queueLimit:           cfg.RequestBacklogGetTransactionQueueLimit,
 			requestDurationLimit: cfg.MaxGetTransactionExecutionDuration,
@@ -211,23 +327,42 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 			queueLimit:           cfg.RequestBacklogGetTransactionsQueueLimit,
 			requestDurationLimit: cfg.MaxGetTransactionsExecutionDuration,
 		},
+		{
+			methodName: "getContractTransactions",
+			underlyingHandler: methods.NewGetContractTransactionsHandler(
+				params.Logger, params.LedgerReader, params.TransactionReader,
+				cfg.MaxTransactionsLimit, cfg.DefaultTransactionsLimit, cfg.NetworkPassphrase),
+			longName:             "get_contract_transactions",
+			queueLimit:           cfg.RequestBacklogGetContractTransactionsQueueLimit,
+			requestDurationLimit: cfg.MaxGetContractTransactionsExecutionDuration,
+		},
 		{
 			methodName: "sendTransaction",
-			underlyingHandler: methods.NewSendTransactionHandler(
-				params.Daemon, params.Logger, params.TransactionReader, cfg.NetworkPassphrase),
+			underlyingHandler: methods.WithCircuitBreaker(params.NodeHealthChecker, methods.NewSendTransactionHandler(
+				params.Daemon, params.Logger, params.TransactionReader, params.TransactionHints, submittedTransactions,
+				cfg.NetworkPassphrase)),
 			longName:             "send_transaction",
 			queueLimit:           cfg.RequestBacklogSendTransactionQueueLimit,
 			requestDurationLimit: cfg.MaxSendTransactionExecutionDuration,
 		},
 		{
 			methodName: "simulateTransaction",
-			underlyingHandler: methods.NewSimulateTransactionHandler(
+			underlyingHandler: methods.WithCircuitBreaker(params.NodeHealthChecker, methods.NewSimulateTransactionHandler(
 				params.Logger, params.LedgerEntryReader, params.LedgerReader,
-				params.Daemon, params.PreflightGetter),
+				params.Daemon, params.PreflightGetter, params.PreflightChecker)),
 			longName:             "simulate_transaction",
 			queueLimit:           cfg.RequestBacklogSimulateTransactionQueueLimit,
 			requestDurationLimit: cfg.MaxSimulateTransactionExecutionDuration,
 		},
+		{
+			methodName: "getTokenMetadata",
+			underlyingHandler: methods.WithCircuitBreaker(params.NodeHealthChecker, methods.NewGetTokenMetadataHandler(
+				params.Logger, params.LedgerEntryReader, params.LedgerReader,
+				params.PreflightGetter, params.PreflightChecker)),
+			longName:             "get_token_metadata",
+			queueLimit:           cfg.RequestBacklogGetTokenMetadataQueueLimit,
+			requestDurationLimit: cfg.MaxGetTokenMetadataExecutionDuration,
+		},
 		{
 			methodName:           "getFeeStats",
 			underlyingHandler:    methods.NewGetFeeStatsHandler(params.FeeStatWindows, params.TransactionReader, params.Logger),
@@ -235,8 +370,29 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 			queueLimit:           cfg.RequestBacklogGetFeeStatsTransactionQueueLimit,
 			requestDurationLimit: cfg.MaxGetFeeStatsExecutionDuration,
 		},
+		{
+			methodName: "getFeeBump",
+			underlyingHandler: methods.NewGetFeeBumpHandler(
+				params.Logger, params.TransactionReader, params.FeeStatWindows, submittedTransactions, cfg.NetworkPassphrase),
+			longName:             "get_fee_bump",
+			queueLimit:           cfg.RequestBacklogGetFeeBumpQueueLimit,
+			requestDurationLimit: cfg.MaxGetFeeBumpExecutionDuration,
+		},
+		{
+			methodName:           "getSorobanConfig",
+			underlyingHandler:    methods.NewGetSorobanConfigHandler(params.Logger, params.LedgerEntryReader),
+			longName:             "get_soroban_config",
+			queueLimit:           cfg.RequestBacklogGetSorobanConfigQueueLimit,
+			requestDurationLimit: cfg.MaxGetSorobanConfigExecutionDuration,
+		},
 	}
+	resultTooLargeCounter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: params.Daemon.MetricsNamespace(), Subsystem: "network", Name: "result_too_large_requests",
+		Help: "The count of requests rejected due to the size of their result exceeding the maximum response size, by method",
+	}, []string{"method"})
+	params.Daemon.MetricsRegistry().MustRegister(resultTooLargeCounter)
 	handlersMap := handler.Map{}
+	ledgerRangeCache := methods.NewLedgerRangeCache(params.LedgerReader)
 	for _, handler := range handlers {
 		queueLimiterGaugeName := handler.longName + "_inflight_requests"
 		queueLimiterGaugeHelp := "Number of concurrenty in-flight " + handler.methodName + " requests"
@@ -246,8 +402,15 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 			Name: queueLimiterGaugeName,
 			Help: queueLimiterGaugeHelp,
 		})
+		underlyingHandler := handler.underlyingHandler
+		if limit, ok := paramsSizeLimits[handler.methodName]; ok {
+			underlyingHandler = methods.WithParamsSizeLimit(limit, underlyingHandler)
+		}
+		// only getEvents, getLedgerEntries and getTransactions account for the size of their results
+		underlyingHandler = methods.WithResponseSizeLimit(
+			cfg.MaxResponseSize, resultTooLargeCounter.WithLabelValues(handler.methodName), underlyingHandler)
 		queueLimiter := network.MakeJrpcBacklogQueueLimiter(
-			handler.underlyingHandler,
+			methods.WithLedgerRange(ledgerRangeCache, underlyingHandler),
 			queueLimiterGauge,
 			uint64(handler.queueLimit),
 			params.Logger)
@@ -278,11 +441,13 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 			params.Logger)
 		handlersMap[handler.methodName] = durationLimiter.Handle
 	}
-	bridge := jhttp.NewBridge(decorateHandlers(
+	decoratedHandlers := decorateHandlers(
 		params.Daemon,
 		params.Logger,
-		handlersMap),
-		&bridgeOptions)
+		cfg.NetworkPassphrase,
+		params.RequestDurationObserver,
+		handlersMap)
+	bridge := jhttp.NewBridge(decoratedHandlers, &bridgeOptions)
 
 	// globalQueueRequestBacklogLimiter is a metric for measuring the total concurrent inflight requests
 	globalQueueRequestBacklogLimiter := prometheus.NewGauge(prometheus.GaugeOpts{
@@ -304,26 +469,199 @@ func NewJSONRPCHandler(cfg *config.Config, params HandlerParams) Handler {
 		Namespace: params.Daemon.MetricsNamespace(), Subsystem: "network", Name: "global_request_execution_duration_threshold_limit",
 		Help: "The metric measures the count of requests that surpassed the limit threshold for execution time",
 	})
-	handler := network.MakeHTTPRequestDurationLimiter(
-		queueLimitedBridge,
-		cfg.RequestExecutionWarningThreshold,
-		cfg.MaxRequestExecutionDuration,
-		globalQueueRequestExecutionDurationWarningCounter,
-		globalQueueRequestExecutionDurationLimitCounter,
-		params.Logger)
+	// limitRequests applies the global request limits to the handler of an
+	// endpoint, all the endpoints sharing the same request backlog queue
+	limitRequests := func(downstream http.Handler) http.Handler {
+		return network.MakeHTTPRequestDurationLimiter(
+			queueLimitedBridge.Wrap(downstream),
+			cfg.RequestExecutionWarningThreshold,
+			cfg.MaxRequestExecutionDuration,
+			globalQueueRequestExecutionDurationWarningCounter,
+			globalQueueRequestExecutionDurationLimitCounter,
+			params.Logger)
+	}
+	handler := limitRequests(bridge)
 
-	handler = http.MaxBytesHandler(handler, maxHTTPRequestSize)
+	// the limits were validated when parsing the configuration
+	concurrencyLimits, _ := config.ParseMethodLimits(cfg.MethodConcurrencyLimits)
+	var concurrencyLimiter *network.ConcurrencyLimiter
+	if len(concurrencyLimits) > 0 {
+		queueLimits, _ := config.ParseMethodLimits(cfg.MethodQueueLimits)
+		activeCallsGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: params.Daemon.MetricsNamespace(), Subsystem: "network", Name: "method_concurrency_active_calls",
+			Help: "Number of executing calls to concurrency-limited methods, by method",
+		}, []string{"method"})
+		queuedCallsGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: params.Daemon.MetricsNamespace(), Subsystem: "network", Name: "method_concurrency_queued_calls",
+			Help: "Number of calls waiting for concurrency-limited methods, by method",
+		}, []string{"method"})
+		busyCounter := prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: params.Daemon.MetricsNamespace(), Subsystem: "network", Name: "method_concurrency_rejected_requests",
+			Help: "The count of requests rejected due to the concurrency limit of a method, by method",
+		}, []string{"method"})
+		params.Daemon.MetricsRegistry().MustRegister(activeCallsGauge, queuedCallsGauge, busyCounter)
+		methodLimits := make(map[string]network.MethodConcurrencyLimit, len(concurrencyLimits))
+		for method, limit := range concurrencyLimits {
+			methodLimits[method] = network.MethodConcurrencyLimit{
+				MaxConcurrent:   limit,
+				MaxQueued:       queueLimits[method],
+				MaxWait:         cfg.MethodQueueTimeout,
+				ActiveGauge:     activeCallsGauge.WithLabelValues(method),
+				QueuedGauge:     queuedCallsGauge.WithLabelValues(method),
+				RejectedCounter: busyCounter.WithLabelValues(method),
+			}
+		}
+		concurrencyLimiter = network.NewConcurrencyLimiter(methodLimits, params.Logger)
+		handler = concurrencyLimiter.Wrap(handler)
+	}
 
-	corsMiddleware := cors.New(cors.Options{
-		AllowedOrigins:         []string{},
-		AllowOriginRequestFunc: func(*http.Request, string) bool { return true },
-		AllowedHeaders:         []string{"*"},
-		AllowedMethods:         []string{"GET", "PUT", "POST", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-	})
+	var trustedProxies uint
+	if cfg.RateLimitTrustForwardedFor {
+		trustedProxies = cfg.RateLimitTrustedProxies
+	}
+	rateLimiterConfig := network.RateLimiterConfig{
+		GlobalRate:     cfg.RateLimitGlobalRequestsPerSecond,
+		GlobalBurst:    cfg.RateLimitGlobalBurst,
+		ClientRate:     cfg.RateLimitClientRequestsPerSecond,
+		ClientBurst:    cfg.RateLimitClientBurst,
+		TrustedProxies: trustedProxies,
+	}
+	// the weights were validated when parsing the configuration
+	rateLimiterConfig.MethodWeights, _ = config.ParseMethodWeights(cfg.RateLimitMethodWeights)
+	var rateLimiter *network.RateLimiter
+	if rateLimiterConfig.Enabled() {
+		rateLimitedCounter := prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: params.Daemon.MetricsNamespace(), Subsystem: "network", Name: "rate_limited_requests",
+			Help: "The count of requests rejected due to the rate limits, by limit",
+		}, []string{"limit"})
+		params.Daemon.MetricsRegistry().MustRegister(rateLimitedCounter)
+		rateLimiter = network.NewRateLimiter(
+			rateLimiterConfig,
+			rateLimitedCounter.WithLabelValues("global"),
+			rateLimitedCounter.WithLabelValues("client"),
+			params.Logger)
+		handler = rateLimiter.Wrap(handler)
+	}
 
-	return Handler{
+	corsMiddleware := cors.New(corsOptions(cfg))
+	// serveHTTP applies the HTTP middleware shared by all the endpoints
+	serveHTTP := func(handler http.Handler) http.Handler {
+		if params.AccessLogger != nil {
+			handler = network.MakeHTTPAccessLogger(handler, network.AccessLogConfig{
+				Logger:         params.AccessLogger,
+				SampleRatio:    cfg.AccessLogSampleRatio,
+				TrustedProxies: trustedProxies,
+			})
+		}
+		handler = http.MaxBytesHandler(handler, int64(cfg.MaxRequestSize))
+		if cfg.EnableResponseCompression {
+			handler = network.MakeHTTPCompressionHandler(handler, cfg.ResponseCompressionMinSize, cfg.EnableZstdResponseCompression, params.Logger)
+		}
+		return corsMiddleware.Handler(handler)
+	}
+
+	result := Handler{
 		bridge:  bridge,
 		logger:  params.Logger,
-		Handler: corsMiddleware.Handler(handler),
+		Handler: serveHTTP(handler),
+	}
+	if cfg.EnableGraphQL {
+		// GraphQL queries are resolved through an in-memory JSON RPC server
+		// so that they are subject to the same per-method limits
+		local := server.NewLocal(decoratedHandlers, &server.LocalOptions{Server: bridgeOptions.Server})
+		result.local = &local
+		caller := limitedCaller{
+			caller:             local.Client,
+			rateLimiter:        rateLimiter,
+			concurrencyLimiter: concurrencyLimiter,
+		}
+		graphQLHandler := limitRequests(graphql.NewHTTPHandler(graphql.NewExecutor(caller), params.Logger))
+		if rateLimiter != nil {
+			// each of the calls resolving a query is charged, rather than the query itself
+			graphQLHandler = rateLimiter.WrapCalls(graphQLHandler)
+		}
+		result.GraphQLHandler = serveHTTP(graphQLHandler)
+	}
+	if cfg.EnableHorizonAPI {
+		// the requests are charged like calls to the JSON RPC methods serving the same resources
+		limiter := limitedCaller{
+			rateLimiter:        rateLimiter,
+			concurrencyLimiter: concurrencyLimiter,
+		}
+		horizonHandler := limitRequests(horizon.NewHTTPHandler(
+			params.Logger,
+			params.LedgerReader,
+			params.LedgerEntryReader,
+			params.TransactionReader,
+			cfg.NetworkPassphrase,
+			limiter.acquireHorizon,
+		))
+		if rateLimiter != nil {
+			horizonHandler = rateLimiter.WrapCalls(horizonHandler)
+		}
+		result.HorizonHandler = serveHTTP(horizonHandler)
+	}
+	if params.SubscriptionHandler != nil {
+		// The connection upgrade is charged as a single request. The rest of the HTTP middleware
+		// doesn't apply to the (long-lived, hijacked) WebSocket connections.
+		subscriptionHandler := params.SubscriptionHandler
+		if rateLimiter != nil {
+			subscriptionHandler = rateLimiter.Wrap(subscriptionHandler)
+		}
+		result.SubscriptionHandler = subscriptionHandler
+	}
+	return result
+}
+
+// limitedCaller applies the rate limits and the method concurrency limits
+// to each of the calls resolving a GraphQL query, as if they were sent
+// through JSON RPC.
+type limitedCaller struct {
+	caller             graphql.Caller
+	rateLimiter        *network.RateLimiter
+	concurrencyLimiter *network.ConcurrencyLimiter
+}
+
+func (c limitedCaller) CallResult(ctx context.Context, method string, params, result any) error {
+	release, err := c.acquire(ctx, method)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return c.caller.CallResult(ctx, method, params, result)
+}
+
+func (c limitedCaller) acquire(ctx context.Context, method string) (func(), error) {
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.AllowCall(ctx, method); err != nil {
+			return nil, err
+		}
+	}
+	if c.concurrencyLimiter != nil {
+		return c.concurrencyLimiter.AcquireCall(ctx, method)
+	}
+	return func() {}, nil
+}
+
+// acquireHorizon is the horizon.Limiter, rendering the rejections as Horizon problems
+func (c limitedCaller) acquireHorizon(ctx context.Context, method string) (func(), error) {
+	release, err := c.acquire(ctx, method)
+	var jrpcErr *jrpc2.Error
+	if !errors.As(err, &jrpcErr) {
+		return release, err
+	}
+	if jrpcErr.Code == network.RateLimitedCode {
+		return nil, problem.P{
+			Type:   "rate_limit_exceeded",
+			Title:  "Rate Limit Exceeded",
+			Status: http.StatusTooManyRequests,
+			Detail: jrpcErr.Message,
+		}
+	}
+	return nil, problem.P{
+		Type:   "server_over_capacity",
+		Title:  "Server Over Capacity",
+		Status: http.StatusServiceUnavailable,
+		Detail: jrpcErr.Message,
 	}
 }
@@ -0,0 +1,228 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/handler"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/cors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/render/problem"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/network"
+)
+
+func corsPreflight(handler http.Handler, origin string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodOptions, "/", nil)
+	request.Header.Set("Origin", origin)
+	request.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestCORSOptions(t *testing.T) {
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	cfg := &config.Config{
+		CORSAllowedHeaders: []string{"*"},
+		CORSAllowedMethods: []string{http.MethodPost},
+	}
+
+	// any origin is echoed back without an allowlist
+	handler := cors.New(corsOptions(cfg)).Handler(next)
+	response := corsPreflight(handler, "https://anywhere.org")
+	assert.Equal(t, "https://anywhere.org", response.Header().Get("Access-Control-Allow-Origin"))
+
+	cfg.CORSAllowedOrigins = []string{"https://app.example.com", "https://*.stellar.org"}
+	cfg.CORSMaxAge = time.Hour
+	handler = cors.New(corsOptions(cfg)).Handler(next)
+	for _, origin := range []string{"https://app.example.com", "https://lab.stellar.org"} {
+		response = corsPreflight(handler, origin)
+		assert.Equal(t, origin, response.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "3600", response.Header().Get("Access-Control-Max-Age"))
+	}
+	response = corsPreflight(handler, "https://anywhere.org")
+	assert.Empty(t, response.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestJSONRPCLogFields(t *testing.T) {
+	var out bytes.Buffer
+	logger := log.New()
+	logger.SetOutput(&out)
+	logger.SetLevel(log.InfoLevel)
+	logger.UseJSONFormatter()
+
+	requests, err := jrpc2.ParseRequests([]byte(`{"jsonrpc":"2.0","id":7,"method":"getHealth"}`))
+	require.NoError(t, err)
+	request := requests[0].ToRequest()
+	logRequest(logger, "42", request)
+	logResponse(logger, "42", request, 1500*time.Millisecond, "ok", nil)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		var fields map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &fields))
+		assert.Equal(t, "jsonrpc", fields["subsys"])
+		assert.Equal(t, "42", fields["req"])
+		assert.Equal(t, "7", fields["json_req"])
+		assert.Equal(t, "getHealth", fields["method"])
+	}
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &response))
+	assert.Equal(t, 1.5, response["duration"])
+	assert.Equal(t, "ok", response["status"])
+}
+
+// registryDaemon keeps the registered metrics (unlike the no-op daemon)
+type registryDaemon struct {
+	*interfaces.NoOpDaemon
+	registry *prometheus.Registry
+}
+
+func (d registryDaemon) MetricsRegistry() *prometheus.Registry {
+	return d.registry
+}
+
+func TestDecorateHandlersMetrics(t *testing.T) {
+	daemon := registryDaemon{NoOpDaemon: interfaces.MakeNoOpDeamon(), registry: prometheus.NewRegistry()}
+	decorated := decorateHandlers(daemon, log.DefaultLogger, "passphrase", nil, handler.Map{
+		"getHealth": handler.New(func(context.Context) (string, error) { return "healthy", nil }),
+		"getEvents": handler.New(func(context.Context) (string, error) {
+			return "", &jrpc2.Error{Code: jrpc2.InvalidParams, Message: "invalid"}
+		}),
+	})
+	for _, request := range []string{
+		`{"jsonrpc":"2.0","id":1,"method":"getHealth"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"getEvents","params":{"startLedger":1}}`,
+	} {
+		requests, err := jrpc2.ParseRequests([]byte(request))
+		require.NoError(t, err)
+		_, _ = decorated[requests[0].Method](context.Background(), requests[0].ToRequest())
+	}
+
+	expected := `
+# HELP soroban_rpc_json_rpc_method_response_size_bytes JSON RPC response result size, by method and outcome
+# TYPE soroban_rpc_json_rpc_method_response_size_bytes histogram
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getEvents",status="invalid_parameters",le="64"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getEvents",status="invalid_parameters",le="256"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getEvents",status="invalid_parameters",le="1024"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getEvents",status="invalid_parameters",le="4096"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getEvents",status="invalid_parameters",le="16384"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getEvents",status="invalid_parameters",le="65536"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getEvents",status="invalid_parameters",le="262144"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getEvents",status="invalid_parameters",le="1.048576e+06"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getEvents",status="invalid_parameters",le="4.194304e+06"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getEvents",status="invalid_parameters",le="1.6777216e+07"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getEvents",status="invalid_parameters",le="+Inf"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_sum{endpoint="getEvents",status="invalid_parameters"} 0
+soroban_rpc_json_rpc_method_response_size_bytes_count{endpoint="getEvents",status="invalid_parameters"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getHealth",status="ok",le="64"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getHealth",status="ok",le="256"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getHealth",status="ok",le="1024"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getHealth",status="ok",le="4096"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getHealth",status="ok",le="16384"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getHealth",status="ok",le="65536"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getHealth",status="ok",le="262144"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getHealth",status="ok",le="1.048576e+06"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getHealth",status="ok",le="4.194304e+06"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getHealth",status="ok",le="1.6777216e+07"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_bucket{endpoint="getHealth",status="ok",le="+Inf"} 1
+soroban_rpc_json_rpc_method_response_size_bytes_sum{endpoint="getHealth",status="ok"} 9
+soroban_rpc_json_rpc_method_response_size_bytes_count{endpoint="getHealth",status="ok"} 1
+# HELP soroban_rpc_json_rpc_method_inflight_requests Number of JSON RPC requests being executed, by method
+# TYPE soroban_rpc_json_rpc_method_inflight_requests gauge
+soroban_rpc_json_rpc_method_inflight_requests{endpoint="getEvents"} 0
+soroban_rpc_json_rpc_method_inflight_requests{endpoint="getHealth"} 0
+`
+	require.NoError(t, testutil.GatherAndCompare(daemon.registry, strings.NewReader(expected),
+		"soroban_rpc_json_rpc_method_response_size_bytes", "soroban_rpc_json_rpc_method_inflight_requests"))
+	// the request size is the size of the parameters
+	assert.Equal(t, 2, testutil.CollectAndCount(daemon.registry, "soroban_rpc_json_rpc_method_request_size_bytes"))
+	assert.Equal(t, 2, testutil.CollectAndCount(daemon.registry, "soroban_rpc_json_rpc_method_request_duration_seconds"))
+}
+
+type countingCaller struct {
+	calls []string
+}
+
+func (c *countingCaller) CallResult(ctx context.Context, method string, params, result any) error {
+	c.calls = append(c.calls, method)
+	return nil
+}
+
+func TestLimitedCallerChargesEachCall(t *testing.T) {
+	downstream := &countingCaller{}
+	rateLimiter := network.NewRateLimiter(network.RateLimiterConfig{
+		ClientRate:    0.001,
+		ClientBurst:   4,
+		MethodWeights: map[string]uint{"getTransaction": 2},
+	}, nil, nil, nil)
+	caller := limitedCaller{caller: downstream, rateLimiter: rateLimiter}
+	var errs []error
+	handler := rateLimiter.WrapCalls(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, method := range []string{"getEvents", "getTransaction", "getTransaction"} {
+			errs = append(errs, caller.CallResult(r.Context(), method, nil, nil))
+		}
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/graphql", nil))
+
+	require.Len(t, errs, 3)
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	var jsonRPCErr *jrpc2.Error
+	require.ErrorAs(t, errs[2], &jsonRPCErr)
+	assert.Equal(t, jrpc2.Code(network.RateLimitedCode), jsonRPCErr.Code)
+	assert.Equal(t, []string{"getEvents", "getTransaction"}, downstream.calls)
+}
+
+func TestLimitedCallerHorizonProblems(t *testing.T) {
+	concurrencyLimiter := network.NewConcurrencyLimiter(map[string]network.MethodConcurrencyLimit{
+		"getLedgerEntries": {MaxConcurrent: 1},
+	}, nil)
+	limiter := limitedCaller{concurrencyLimiter: concurrencyLimiter}
+	release, err := limiter.acquireHorizon(context.Background(), "getLedgerEntries")
+	require.NoError(t, err)
+	_, err = limiter.acquireHorizon(context.Background(), "getLedgerEntries")
+	var busy problem.P
+	require.ErrorAs(t, err, &busy)
+	assert.Equal(t, http.StatusServiceUnavailable, busy.Status)
+	release()
+
+	rateLimiter := network.NewRateLimiter(network.RateLimiterConfig{
+		ClientRate:  0.001,
+		ClientBurst: 1,
+	}, nil, nil, nil)
+	limiter = limitedCaller{rateLimiter: rateLimiter}
+	var errs []error
+	handler := rateLimiter.WrapCalls(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 2; i++ {
+			release, err := limiter.acquireHorizon(r.Context(), "getTransaction")
+			if err == nil {
+				release()
+			}
+			errs = append(errs, err)
+		}
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/horizon/ledgers/1", nil))
+	require.Len(t, errs, 2)
+	assert.NoError(t, errs[0])
+	var rateLimited problem.P
+	require.ErrorAs(t, errs[1], &rateLimited)
+	assert.Equal(t, http.StatusTooManyRequests, rateLimited.Status)
+	assert.Equal(t, "rate_limit_exceeded", rateLimited.Type)
+}
@@ -0,0 +1,34 @@
+package methods
+
+import (
+	"context"
+
+	"github.com/creachadair/jrpc2"
+)
+
+// NodeDegradedCode is the JSON RPC error code returned by methods which
+// depend on up-to-date ledger state when the node is materially behind the network.
+const NodeDegradedCode = -32007
+
+// NodeHealthChecker reports whether the node is in sync with the network
+type NodeHealthChecker interface {
+	// CheckHealth returns an error describing why the node is degraded, if it is
+	CheckHealth() error
+}
+
+// WithCircuitBreaker decorates a handler so that it fails fast while the node is degraded.
+// A nil checker disables the circuit breaker.
+func WithCircuitBreaker(checker NodeHealthChecker, handler jrpc2.Handler) jrpc2.Handler {
+	if checker == nil {
+		return handler
+	}
+	return func(ctx context.Context, request *jrpc2.Request) (interface{}, error) {
+		if err := checker.CheckHealth(); err != nil {
+			return nil, &jrpc2.Error{
+				Code:    NodeDegradedCode,
+				Message: err.Error(),
+			}
+		}
+		return handler(ctx, request)
+	}
+}
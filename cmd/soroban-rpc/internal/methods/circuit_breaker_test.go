@@ -0,0 +1,37 @@
+package methods
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticHealthChecker struct {
+	err error
+}
+
+func (c *staticHealthChecker) CheckHealth() error {
+	return c.err
+}
+
+func TestWithCircuitBreaker(t *testing.T) {
+	checker := &staticHealthChecker{}
+	handler := WithCircuitBreaker(checker, func(context.Context, *jrpc2.Request) (interface{}, error) {
+		return "ok", nil
+	})
+
+	result, err := handler(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", result)
+
+	checker.err = errors.New("node degraded: too far behind")
+	_, err = handler(context.Background(), nil)
+	var jrpcErr *jrpc2.Error
+	require.ErrorAs(t, err, &jrpcErr)
+	assert.Equal(t, jrpc2.Code(NodeDegradedCode), jrpcErr.Code)
+	assert.Equal(t, "node degraded: too far behind", jrpcErr.Message)
+}
@@ -0,0 +1,107 @@
+package methods
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/soroban-rpc/client"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/subscriptions"
+)
+
+type jsonField struct {
+	options string
+	typ     reflect.Type
+}
+
+// jsonFields returns the JSON fields of a struct type (including the fields of embedded structs)
+func jsonFields(typ reflect.Type) map[string]jsonField {
+	fields := map[string]jsonField{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Anonymous {
+			for name, embedded := range jsonFields(field.Type) {
+				fields[name] = embedded
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		fields[name] = jsonField{options: options, typ: field.Type}
+	}
+	return fields
+}
+
+// elemStruct returns the struct type of pointers, slices or structs
+func elemStruct(typ reflect.Type) (reflect.Type, bool) {
+	for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+	}
+	return typ, typ.Kind() == reflect.Struct
+}
+
+func assertSameJSON(t *testing.T, server, clientType reflect.Type) {
+	serverFields, clientFields := jsonFields(server), jsonFields(clientType)
+	assert.Len(t, clientFields, len(serverFields), "the fields of %s and client.%s differ", server, clientType.Name())
+	for name, serverField := range serverFields {
+		clientField, ok := clientFields[name]
+		if !assert.True(t, ok, "client.%s lacks field %q", clientType.Name(), name) {
+			continue
+		}
+		assert.Equal(t, serverField.options, clientField.options, "options of field %q of %s", name, server)
+		serverStruct, ok := elemStruct(serverField.typ)
+		if !ok {
+			continue
+		}
+		if clientStruct, ok := elemStruct(clientField.typ); ok {
+			assertSameJSON(t, serverStruct, clientStruct)
+		}
+	}
+}
+
+func TestClientTypesMatchServerTypes(t *testing.T) {
+	for _, types := range []struct {
+		server any
+		client any
+	}{
+		{HealthCheckResult{}, client.GetHealthResponse{}},
+		{GetEventsRequest{}, client.GetEventsRequest{}},
+		{GetEventsResponse{}, client.GetEventsResponse{}},
+		{GetEventRequest{}, client.GetEventRequest{}},
+		{GetEventResponse{}, client.GetEventResponse{}},
+		{GetNetworkResponse{}, client.GetNetworkResponse{}},
+		{GetVersionInfoResponse{}, client.GetVersionInfoResponse{}},
+		{GetLatestLedgerRequest{}, client.GetLatestLedgerRequest{}},
+		{GetLatestLedgerResponse{}, client.GetLatestLedgerResponse{}},
+		{GetLedgerEntryRequest{}, client.GetLedgerEntryRequest{}},
+		{GetLedgerEntryResponse{}, client.GetLedgerEntryResponse{}},
+		{GetLedgerEntriesRequest{}, client.GetLedgerEntriesRequest{}},
+		{GetLedgerEntriesResponse{}, client.GetLedgerEntriesResponse{}},
+		{GetContractEntriesRequest{}, client.GetContractEntriesRequest{}},
+		{GetContractEntriesResponse{}, client.GetContractEntriesResponse{}},
+		{GetTransactionRequest{}, client.GetTransactionRequest{}},
+		{GetTransactionResponse{}, client.GetTransactionResponse{}},
+		{GetTransactionsRequest{}, client.GetTransactionsRequest{}},
+		{GetContractTransactionsRequest{}, client.GetContractTransactionsRequest{}},
+		{GetTransactionsResponse{}, client.GetTransactionsResponse{}},
+		{SendTransactionRequest{}, client.SendTransactionRequest{}},
+		{SendTransactionResponse{}, client.SendTransactionResponse{}},
+		{SimulateTransactionRequest{}, client.SimulateTransactionRequest{}},
+		{SimulateTransactionResponse{}, client.SimulateTransactionResponse{}},
+		{GetTokenMetadataRequest{}, client.GetTokenMetadataRequest{}},
+		{GetTokenMetadataResponse{}, client.GetTokenMetadataResponse{}},
+		{GetFeeStatsResult{}, client.GetFeeStatsResponse{}},
+		{GetFeeBumpRequest{}, client.GetFeeBumpRequest{}},
+		{GetFeeBumpResponse{}, client.GetFeeBumpResponse{}},
+		{GetSorobanConfigResponse{}, client.GetSorobanConfigResponse{}},
+		{subscriptions.TransactionFilter{}, client.SubscribeTransactionsRequest{}},
+		{subscriptions.TransactionNotification{}, client.TransactionNotification{}},
+		{subscriptions.LedgerNotification{}, client.LedgerNotification{}},
+	} {
+		assertSameJSON(t, reflect.TypeOf(types.server), reflect.TypeOf(types.client))
+	}
+}
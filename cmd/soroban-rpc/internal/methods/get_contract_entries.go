@@ -0,0 +1,146 @@
+package methods
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// ContractEntriesPaginationOptions defines the available options for paginating through contract entries.
+type ContractEntriesPaginationOptions struct {
+	// Cursor is the key of the last entry of the previous page
+	Cursor string `json:"cursor,omitempty"`
+	Limit  uint   `json:"limit,omitempty"`
+}
+
+type GetContractEntriesRequest struct {
+	// ContractID is the (C...) address of the contract whose storage is listed
+	ContractID string                            `json:"contractId"`
+	Pagination *ContractEntriesPaginationOptions `json:"pagination,omitempty"`
+}
+
+type GetContractEntriesResponse struct {
+	// Entries are the contract data entries of the contract (including its instance), in storage order
+	Entries []LedgerEntryResult `json:"entries"`
+	LedgerRangeResponse
+	// Cursor is the key of the last entry, to be used for fetching the next page. It is empty once all
+	// the entries have been returned.
+	Cursor string `json:"cursor,omitempty"`
+}
+
+type contractEntriesHandler struct {
+	logger            *log.Entry
+	ledgerEntryReader db.LedgerEntryReader
+	maxLimit          uint
+	defaultLimit      uint
+}
+
+func (h contractEntriesHandler) getContractEntries(ctx context.Context, request GetContractEntriesRequest) (GetContractEntriesResponse, error) {
+	contractIDBytes, err := strkey.Decode(strkey.VersionByteContract, request.ContractID)
+	if err != nil {
+		return GetContractEntriesResponse{}, invalidParamsf("invalid contract id: %v", err)
+	}
+	var contractID xdr.Hash
+	copy(contractID[:], contractIDBytes)
+	contract := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID}
+
+	limit := h.defaultLimit
+	var cursor *xdr.LedgerKey
+	if request.Pagination != nil {
+		if request.Pagination.Limit > h.maxLimit {
+			return GetContractEntriesResponse{}, invalidParamsf("limit must not exceed %d", h.maxLimit)
+		}
+		if request.Pagination.Limit > 0 {
+			limit = request.Pagination.Limit
+		}
+		if request.Pagination.Cursor != "" {
+			var key xdr.LedgerKey
+			if err := xdr.SafeUnmarshalBase64(request.Pagination.Cursor, &key); err != nil {
+				return GetContractEntriesResponse{}, invalidParamsf("invalid cursor: %v", err)
+			}
+			if key.Type != xdr.LedgerEntryTypeContractData || !key.ContractData.Contract.Equals(contract) {
+				return GetContractEntriesResponse{}, invalidParamsf("cursor is not a contract data key of contract %s", request.ContractID)
+			}
+			cursor = &key
+		}
+	}
+
+	tx, err := h.ledgerEntryReader.NewTx(ctx)
+	if err != nil {
+		return GetContractEntriesResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: "could not create read transaction",
+		}
+	}
+	defer func() {
+		_ = tx.Done()
+	}()
+
+	latestLedger, err := tx.GetLatestLedgerSequence()
+	if err != nil {
+		return GetContractEntriesResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: "could not get latest ledger",
+		}
+	}
+
+	ledgerKeysAndEntries, err := tx.GetContractDataEntries(contract, cursor, limit)
+	if err != nil {
+		h.logger.WithError(err).WithField("request", request).
+			Info("could not obtain contract entries from storage")
+		return GetContractEntriesResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: "could not obtain contract entries from storage",
+		}
+	}
+
+	response := GetContractEntriesResponse{
+		Entries:             make([]LedgerEntryResult, 0, len(ledgerKeysAndEntries)),
+		LedgerRangeResponse: LedgerRangeResponse{LatestLedger: latestLedger},
+	}
+	for _, ledgerKeyAndEntry := range ledgerKeysAndEntries {
+		keyXDR, err := xdr.MarshalBase64(ledgerKeyAndEntry.Key)
+		if err != nil {
+			return GetContractEntriesResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: fmt.Sprintf("could not serialize ledger key %v", ledgerKeyAndEntry.Key),
+			}
+		}
+		entryXDR, err := xdr.MarshalBase64(ledgerKeyAndEntry.Entry.Data)
+		if err != nil {
+			return GetContractEntriesResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: fmt.Sprintf("could not serialize ledger entry data for ledger entry %v", ledgerKeyAndEntry.Entry),
+			}
+		}
+		response.Entries = append(response.Entries, LedgerEntryResult{
+			Key:                keyXDR,
+			XDR:                entryXDR,
+			LastModifiedLedger: uint32(ledgerKeyAndEntry.Entry.LastModifiedLedgerSeq),
+			LiveUntilLedgerSeq: ledgerKeyAndEntry.LiveUntilLedgerSeq,
+		})
+	}
+	// a short page means that there are no more entries
+	if uint(len(response.Entries)) == limit {
+		response.Cursor = response.Entries[len(response.Entries)-1].Key
+	}
+	return response, nil
+}
+
+// NewGetContractEntriesHandler returns a JSON RPC handler listing the contract data entries of a contract.
+func NewGetContractEntriesHandler(logger *log.Entry, ledgerEntryReader db.LedgerEntryReader, maxLimit, defaultLimit uint) jrpc2.Handler {
+	handler := contractEntriesHandler{
+		logger:            logger,
+		ledgerEntryReader: ledgerEntryReader,
+		maxLimit:          maxLimit,
+		defaultLimit:      defaultLimit,
+	}
+	return NewHandler(handler.getContractEntries)
+}
@@ -0,0 +1,144 @@
+package methods
+
+import (
+	"context"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+type contractDataReader struct {
+	ConstantLedgerEntryReader
+	tx *contractDataReadTx
+}
+
+func (r *contractDataReader) NewTx(ctx context.Context) (db.LedgerEntryReadTx, error) {
+	return r.tx, nil
+}
+
+type contractDataReadTx struct {
+	ConstantLedgerEntryReaderTx
+	entries []xdr.LedgerEntry
+	cursor  *xdr.LedgerKey
+	limit   uint
+}
+
+func (tx *contractDataReadTx) GetContractDataEntries(
+	contract xdr.ScAddress, cursor *xdr.LedgerKey, limit uint,
+) ([]db.LedgerKeyAndEntry, error) {
+	tx.cursor = cursor
+	tx.limit = limit
+	var result []db.LedgerKeyAndEntry
+	for _, entry := range tx.entries {
+		if uint(len(result)) == limit {
+			break
+		}
+		key, err := entry.LedgerKey()
+		if err != nil {
+			return nil, err
+		}
+		liveUntil := uint32(2000)
+		result = append(result, db.LedgerKeyAndEntry{Key: key, Entry: entry, LiveUntilLedgerSeq: &liveUntil})
+	}
+	return result, nil
+}
+
+func TestGetContractEntries(t *testing.T) {
+	contractID := xdr.Hash{1, 2, 3}
+	contractAddress := strkey.MustEncode(strkey.VersionByteContract, contractID[:])
+	contract := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID}
+	var entries []xdr.LedgerEntry
+	for i := 0; i < 3; i++ {
+		value := xdr.Uint32(i)
+		entries = append(entries, xdr.LedgerEntry{
+			LastModifiedLedgerSeq: 10,
+			Data: xdr.LedgerEntryData{
+				Type: xdr.LedgerEntryTypeContractData,
+				ContractData: &xdr.ContractDataEntry{
+					Contract:   contract,
+					Key:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &value},
+					Durability: xdr.ContractDataDurabilityPersistent,
+					Val:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &value},
+				},
+			},
+		})
+	}
+	readTx := &contractDataReadTx{entries: entries}
+	handler := contractEntriesHandler{
+		logger:            log.DefaultLogger,
+		ledgerEntryReader: &contractDataReader{tx: readTx},
+		maxLimit:          10,
+		defaultLimit:      2,
+	}
+
+	t.Run("first page", func(t *testing.T) {
+		response, err := handler.getContractEntries(context.Background(), GetContractEntriesRequest{
+			ContractID: contractAddress,
+		})
+		require.NoError(t, err)
+		assert.Nil(t, readTx.cursor)
+		assert.Equal(t, uint(2), readTx.limit)
+		assert.Equal(t, uint32(expectedLatestLedgerSequence), response.LatestLedger)
+		require.Len(t, response.Entries, 2)
+		expectedKey, err := entries[1].LedgerKey()
+		require.NoError(t, err)
+		expectedKeyB64, err := xdr.MarshalBase64(expectedKey)
+		require.NoError(t, err)
+		assert.Equal(t, expectedKeyB64, response.Entries[1].Key)
+		assert.Equal(t, uint32(10), response.Entries[1].LastModifiedLedger)
+		require.NotNil(t, response.Entries[1].LiveUntilLedgerSeq)
+		assert.Equal(t, uint32(2000), *response.Entries[1].LiveUntilLedgerSeq)
+		assert.Equal(t, expectedKeyB64, response.Cursor)
+
+		// the next page is requested after the cursor
+		_, err = handler.getContractEntries(context.Background(), GetContractEntriesRequest{
+			ContractID: contractAddress,
+			Pagination: &ContractEntriesPaginationOptions{Cursor: response.Cursor},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, readTx.cursor)
+		assert.Equal(t, expectedKey, *readTx.cursor)
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		response, err := handler.getContractEntries(context.Background(), GetContractEntriesRequest{
+			ContractID: contractAddress,
+			Pagination: &ContractEntriesPaginationOptions{Limit: 5},
+		})
+		require.NoError(t, err)
+		assert.Len(t, response.Entries, 3)
+		assert.Empty(t, response.Cursor)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		otherContractID := xdr.Hash{4}
+		otherKey := xdr.LedgerKey{
+			Type: xdr.LedgerEntryTypeContractData,
+			ContractData: &xdr.LedgerKeyContractData{
+				Contract: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &otherContractID},
+				Key:      xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+			},
+		}
+		otherKeyB64, err := xdr.MarshalBase64(otherKey)
+		require.NoError(t, err)
+		for _, request := range []GetContractEntriesRequest{
+			{ContractID: "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"},
+			{ContractID: contractAddress, Pagination: &ContractEntriesPaginationOptions{Limit: 11}},
+			{ContractID: contractAddress, Pagination: &ContractEntriesPaginationOptions{Cursor: "foo"}},
+			{ContractID: contractAddress, Pagination: &ContractEntriesPaginationOptions{Cursor: otherKeyB64}},
+		} {
+			_, err := handler.getContractEntries(context.Background(), request)
+			var jrpcErr *jrpc2.Error
+			require.ErrorAs(t, err, &jrpcErr)
+			assert.Equal(t, jrpc2.InvalidParams, jrpcErr.Code)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package methods
+
+import (
+	"context"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// GetContractTransactionsRequest pages through the transactions which touched a contract,
+// through their footprint or their events.
+type GetContractTransactionsRequest struct {
+	// ContractID is the (C...) address of the contract whose transactions are listed
+	ContractID  string                         `json:"contractId"`
+	StartLedger uint32                         `json:"startLedger"`
+	Pagination  *TransactionsPaginationOptions `json:"pagination,omitempty"`
+	// XDRFields (optional) restricts the XDR fields included in the transactions
+	// (see XDRFieldEnvelope and the like). All of them are included by default.
+	XDRFields []string `json:"xdrFields,omitempty"`
+}
+
+// NewGetContractTransactionsHandler returns a handler listing the transactions of a contract,
+// which is equivalent to getTransactions with a contractId filter.
+func NewGetContractTransactionsHandler(
+	logger *log.Entry,
+	ledgerReader db.LedgerReader,
+	dbReader db.TransactionReader,
+	maxLimit, defaultLimit uint,
+	networkPassphrase string,
+) jrpc2.Handler {
+	transactionsHandler := transactionsRPCHandler{
+		ledgerReader:      ledgerReader,
+		dbReader:          dbReader,
+		maxLimit:          maxLimit,
+		defaultLimit:      defaultLimit,
+		logger:            logger,
+		networkPassphrase: networkPassphrase,
+	}
+	return NewHandler(func(ctx context.Context, request GetContractTransactionsRequest) (GetTransactionsResponse, error) {
+		if request.ContractID == "" {
+			return GetTransactionsResponse{}, invalidParamsf("contractId is required")
+		}
+		return transactionsHandler.getTransactionsByLedgerSequence(ctx, GetTransactionsRequest{
+			StartLedger: request.StartLedger,
+			Filters:     &TransactionsFilters{ContractID: request.ContractID},
+			Pagination:  request.Pagination,
+			XDRFields:   request.XDRFields,
+		})
+	})
+}
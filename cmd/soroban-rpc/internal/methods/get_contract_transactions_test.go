@@ -0,0 +1,59 @@
+package methods
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/strkey"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+func TestGetContractTransactions(t *testing.T) {
+	mockDBReader := db.NewMockTransactionStore(NetworkPassphrase)
+	mockLedgerReader := db.NewMockLedgerReader(mockDBReader)
+	require.NoError(t, mockDBReader.InsertTransactions(createTestLedger(101)))
+	// the contract emits an event in ledger 102
+	require.NoError(t, mockDBReader.InsertTransactions(txMetaWithEvents(2, true)))
+	require.NoError(t, mockDBReader.InsertTransactions(createTestLedger(103)))
+	handler := NewGetContractTransactionsHandler(nil, mockLedgerReader, mockDBReader, 100, 10, NetworkPassphrase)
+	getContractTransactions := func(params string) (GetTransactionsResponse, error) {
+		requests, err := jrpc2.ParseRequests(
+			[]byte(`{"jsonrpc":"2.0","id":1,"method":"getContractTransactions","params":` + params + `}`))
+		require.NoError(t, err)
+		result, err := handler(context.TODO(), requests[0].ToRequest())
+		if err != nil {
+			return GetTransactionsResponse{}, err
+		}
+		return result.(GetTransactionsResponse), nil
+	}
+	contractIDBytes, err := hex.DecodeString("df06d62447fd25da07c0135eed7557e5a5497ee7d15b7fe345bd47e191d8f577")
+	require.NoError(t, err)
+	contractID := strkey.MustEncode(strkey.VersionByteContract, contractIDBytes)
+	otherContractID := strkey.MustEncode(strkey.VersionByteContract, make([]byte, 32))
+
+	response, err := getContractTransactions(`{"contractId":"` + contractID + `","startLedger":101}`)
+	require.NoError(t, err)
+	require.Len(t, response.Transactions, 1)
+	assert.Equal(t, uint32(102), response.Transactions[0].Ledger)
+	assert.Equal(t, TransactionStatusSuccess, response.Transactions[0].Status)
+
+	// next page
+	response, err = getContractTransactions(`{"contractId":"` + contractID + `","pagination":{"cursor":"` + response.Cursor + `"}}`)
+	require.NoError(t, err)
+	assert.Empty(t, response.Transactions)
+
+	response, err = getContractTransactions(`{"contractId":"` + otherContractID + `","startLedger":101}`)
+	require.NoError(t, err)
+	assert.Empty(t, response.Transactions)
+
+	_, err = getContractTransactions(`{"startLedger":101}`)
+	assert.EqualError(t, err, "[-32602] contractId is required")
+	_, err = getContractTransactions(`{"contractId":"CA","startLedger":101}`)
+	assert.ErrorContains(t, err, "[-32602] invalid contract id")
+}
@@ -0,0 +1,114 @@
+package methods
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/events"
+)
+
+type GetEventRequest struct {
+	// ID is the id (or, equivalently, the paging token) of the event, as returned by getEvents
+	ID string `json:"id"`
+}
+
+type GetEventResponse struct {
+	Event EventInfo `json:"event"`
+	LedgerRangeResponse
+}
+
+type eventRPCHandler struct {
+	scanner eventScanner
+}
+
+func (h eventRPCHandler) getEvent(ctx context.Context, request GetEventRequest) (GetEventResponse, error) {
+	cursor, err := events.ParseCursor(request.ID)
+	if err != nil {
+		return GetEventResponse{}, invalidParamsf("invalid event id: %v", err)
+	}
+
+	ledgerRange, err := h.scanner.GetLedgerRange()
+	if err != nil {
+		return GetEventResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+	if cursor.Ledger < ledgerRange.FirstLedger.Sequence || cursor.Ledger > ledgerRange.LastLedger.Sequence {
+		return GetEventResponse{}, &jrpc2.Error{
+			Code: jrpc2.InvalidRequest,
+			Message: fmt.Sprintf(
+				"event ledger %d is outside of the retention window (ledgers %d to %d)",
+				cursor.Ledger, ledgerRange.FirstLedger.Sequence, ledgerRange.LastLedger.Sequence,
+			),
+		}
+	}
+
+	var (
+		found                bool
+		event                xdr.DiagnosticEvent
+		ledgerCloseTimestamp int64
+		txHash               *xdr.Hash
+		txSuccessful         bool
+	)
+	end := cursor
+	end.Event++
+	latestLedger, err := h.scanner.Scan(
+		events.Range{
+			Start:         cursor,
+			End:           end,
+			ClampEnd:      true,
+			IncludeFailed: true,
+		},
+		func(e xdr.DiagnosticEvent, c events.Cursor, timestamp int64, hash *xdr.Hash, successful bool) bool {
+			if c.Cmp(cursor) == 0 {
+				found = true
+				event, ledgerCloseTimestamp, txHash, txSuccessful = e, timestamp, hash, successful
+			}
+			return false
+		},
+	)
+	if err != nil {
+		return GetEventResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidRequest,
+			Message: err.Error(),
+		}
+	}
+	if !found {
+		return GetEventResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidRequest,
+			Message: "not found",
+		}
+	}
+
+	info, err := eventInfoForEvent(
+		event,
+		cursor,
+		time.Unix(ledgerCloseTimestamp, 0).UTC().Format(time.RFC3339),
+		txHash.HexString(),
+	)
+	if err != nil {
+		return GetEventResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: fmt.Sprintf("could not parse event: %v", err),
+		}
+	}
+	info.InSuccessfulTransaction = txSuccessful
+	return GetEventResponse{
+		Event:               info,
+		LedgerRangeResponse: LedgerRangeResponse{LatestLedger: latestLedger},
+	}, nil
+}
+
+// NewGetEventHandler returns a json rpc handler to fetch a single event by its id
+func NewGetEventHandler(eventsStore *events.MemoryStore) jrpc2.Handler {
+	eventHandler := eventRPCHandler{scanner: eventsStore}
+	return NewHandler(func(ctx context.Context, request GetEventRequest) (GetEventResponse, error) {
+		return eventHandler.getEvent(ctx, request)
+	})
+}
@@ -0,0 +1,73 @@
+package methods
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/events"
+)
+
+func TestGetEvent(t *testing.T) {
+	now := time.Now().UTC()
+	counter := xdr.ScSymbol("COUNTER")
+	value := xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &counter}
+	store := events.NewMemoryStore(interfaces.MakeNoOpDeamon(), "unit-tests", 100)
+	var txMeta []xdr.TransactionMeta
+	for i := 0; i < 3; i++ {
+		txMeta = append(txMeta, transactionMetaWithEvents(
+			contractEvent(xdr.Hash([32]byte{}), xdr.ScVec{value}, value),
+		))
+	}
+	ledgerCloseMeta := ledgerCloseMetaWithEvents(2, now.Unix(), txMeta...)
+	require.NoError(t, store.IngestEvents(ledgerCloseMeta))
+	handler := eventRPCHandler{scanner: store}
+
+	id := events.Cursor{Ledger: 2, Tx: 2}.String()
+	response, err := handler.getEvent(context.Background(), GetEventRequest{ID: id})
+	require.NoError(t, err)
+	encodedValue, err := xdr.MarshalBase64(value)
+	require.NoError(t, err)
+	assert.Equal(t, GetEventResponse{
+		Event: EventInfo{
+			EventType:                EventTypeContract,
+			Ledger:                   2,
+			LedgerClosedAt:           now.Format(time.RFC3339),
+			ContractID:               "CAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABSC4",
+			ID:                       id,
+			PagingToken:              id,
+			Topic:                    []string{encodedValue},
+			Value:                    encodedValue,
+			InSuccessfulContractCall: true,
+			TransactionHash:          ledgerCloseMeta.TransactionHash(1).HexString(),
+			InSuccessfulTransaction:  true,
+		},
+		LedgerRangeResponse: LedgerRangeResponse{LatestLedger: 2},
+	}, response)
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := handler.getEvent(context.Background(), GetEventRequest{
+			ID: events.Cursor{Ledger: 2, Tx: 2, Event: 1}.String(),
+		})
+		assert.EqualError(t, err, "[-32600] not found")
+	})
+
+	t.Run("outside of the retention window", func(t *testing.T) {
+		_, err := handler.getEvent(context.Background(), GetEventRequest{
+			ID: events.Cursor{Ledger: 1, Tx: 1}.String(),
+		})
+		assert.EqualError(t, err, "[-32600] event ledger 1 is outside of the retention window (ledgers 2 to 2)")
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		_, err := handler.getEvent(context.Background(), GetEventRequest{ID: "abc"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "[-32602] invalid event id")
+	})
+}
@@ -8,12 +8,15 @@ import (
 	"time"
 
 	"github.com/creachadair/jrpc2"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/events"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/tracing"
 )
 
 type eventTypeSet map[string]interface{}
@@ -76,15 +79,28 @@ type EventInfo struct {
 	Value                    string   `json:"value"`
 	InSuccessfulContractCall bool     `json:"inSuccessfulContractCall"`
 	TransactionHash          string   `json:"txHash"`
+	// InSuccessfulTransaction is false for the diagnostic events of failed transactions (see IncludeFailed)
+	InSuccessfulTransaction bool `json:"inSuccessfulTransaction"`
+	// MatchedFilters are the (zero-based) indexes of the request filters matched by the event.
+	// It is only present when the request has several filters, allowing to tell their results apart.
+	MatchedFilters []int `json:"matchedFilters,omitempty"`
 }
 
 type GetEventsRequest struct {
-	StartLedger uint32             `json:"startLedger,omitempty"`
-	Filters     []EventFilter      `json:"filters"`
-	Pagination  *PaginationOptions `json:"pagination,omitempty"`
+	StartLedger uint32        `json:"startLedger,omitempty"`
+	Filters     []EventFilter `json:"filters"`
+	// ContractEventsOnly restricts the results to the events of type contract emitted by successful
+	// contract calls, excluding system and diagnostic events (as well as the events of failed calls).
+	// Unlike filtering by type, the events are selected before being decoded.
+	ContractEventsOnly bool `json:"contractEventsOnly,omitempty"`
+	// IncludeFailed includes the diagnostic events of failed transactions, which explain the failure
+	// (e.g. the contract call which reverted). They are only recorded by nodes whose captive core
+	// has diagnostic events enabled.
+	IncludeFailed bool               `json:"includeFailed,omitempty"`
+	Pagination    *PaginationOptions `json:"pagination,omitempty"`
 }
 
-func (g *GetEventsRequest) Valid(maxLimit uint) error {
+func (g *GetEventsRequest) Valid(maxLimit uint, limits RequestLimits) error {
 	// Validate start
 	// Validate the paging limit (if it exists)
 	if g.Pagination != nil && g.Pagination.Cursor != nil {
@@ -99,11 +115,11 @@ func (g *GetEventsRequest) Valid(maxLimit uint) error {
 	}
 
 	// Validate filters
-	if len(g.Filters) > 5 {
-		return errors.New("maximum 5 filters per request")
+	if err := checkLimit("filter count", len(g.Filters), limits.MaxEventFilters); err != nil {
+		return err
 	}
 	for i, filter := range g.Filters {
-		if err := filter.Valid(); err != nil {
+		if err := filter.Valid(limits); err != nil {
 			return errors.Wrapf(err, "filter %d invalid", i+1)
 		}
 	}
@@ -123,6 +139,17 @@ func (g *GetEventsRequest) Matches(event xdr.DiagnosticEvent) bool {
 	return false
 }
 
+// matchedFilters returns the indexes of all the filters matched by the event
+func (g *GetEventsRequest) matchedFilters(event xdr.DiagnosticEvent) []int {
+	var matched []int
+	for i, filter := range g.Filters {
+		if filter.Matches(event) {
+			matched = append(matched, i)
+		}
+	}
+	return matched
+}
+
 const (
 	EventTypeSystem     = "system"
 	EventTypeContract   = "contract"
@@ -141,15 +168,15 @@ type EventFilter struct {
 	Topics      []TopicFilter `json:"topics,omitempty"`
 }
 
-func (e *EventFilter) Valid() error {
+func (e *EventFilter) Valid(limits RequestLimits) error {
 	if err := e.EventType.valid(); err != nil {
 		return errors.Wrap(err, "filter type invalid")
 	}
-	if len(e.ContractIDs) > 5 {
-		return errors.New("maximum 5 contract IDs per filter")
+	if err := checkLimit("contract ID count", len(e.ContractIDs), limits.MaxEventContractIDs); err != nil {
+		return err
 	}
-	if len(e.Topics) > 5 {
-		return errors.New("maximum 5 topics per filter")
+	if err := checkLimit("topic count", len(e.Topics), limits.MaxEventTopics); err != nil {
+		return err
 	}
 	for i, id := range e.ContractIDs {
 		_, err := strkey.Decode(strkey.VersionByteContract, id)
@@ -219,19 +246,30 @@ func (t *TopicFilter) Valid() error {
 		if err := segment.Valid(); err != nil {
 			return errors.Wrapf(err, "segment %d invalid", i+1)
 		}
+		if segment.isMultiSegmentWildcard() && i != len(*t)-1 {
+			return errors.Errorf("segment %d invalid: wildcard '%s' must be the last segment", i+1, multiSegmentWildcard)
+		}
 	}
 	return nil
 }
 
 // An event matches a topic filter iff:
-//   - the event has EXACTLY as many topic segments as the filter AND
+//   - the event has EXACTLY as many topic segments as the filter (or at least as many as
+//     the filter segments preceding a trailing '**' wildcard) AND
 //   - each segment either: matches exactly OR is a wildcard.
 func (t TopicFilter) Matches(event []xdr.ScVal) bool {
-	if len(event) != len(t) {
+	segments := t
+	if len(t) > 0 && t[len(t)-1].isMultiSegmentWildcard() {
+		// the trailing wildcard matches any (possibly empty) remainder of the event topics
+		segments = t[:len(t)-1]
+		if len(event) < len(segments) {
+			return false
+		}
+	} else if len(event) != len(t) {
 		return false
 	}
 
-	for i, segmentFilter := range t {
+	for i, segmentFilter := range segments {
 		if !segmentFilter.Matches(event[i]) {
 			return false
 		}
@@ -240,13 +278,25 @@ func (t TopicFilter) Matches(event []xdr.ScVal) bool {
 	return true
 }
 
+const (
+	// singleSegmentWildcard matches exactly one topic segment
+	singleSegmentWildcard = "*"
+	// multiSegmentWildcard matches zero or more topic segments, it can only be the last segment of a filter
+	multiSegmentWildcard = "**"
+)
+
 type SegmentFilter struct {
 	wildcard *string
 	scval    *xdr.ScVal
 }
 
+func (s *SegmentFilter) isMultiSegmentWildcard() bool {
+	return s.wildcard != nil && *s.wildcard == multiSegmentWildcard
+}
+
 func (s *SegmentFilter) Matches(segment xdr.ScVal) bool {
-	if s.wildcard != nil && *s.wildcard == "*" {
+	if s.wildcard != nil {
+		// both wildcards match any segment
 		return true
 	} else if s.scval != nil {
 		if !s.scval.Equals(segment) {
@@ -266,8 +316,8 @@ func (s *SegmentFilter) Valid() error {
 	if s.wildcard == nil && s.scval == nil {
 		return errors.New("must set either wildcard or scval")
 	}
-	if s.wildcard != nil && *s.wildcard != "*" {
-		return errors.New("wildcard must be '*'")
+	if s.wildcard != nil && *s.wildcard != singleSegmentWildcard && *s.wildcard != multiSegmentWildcard {
+		return errors.New("wildcard must be '*' or '**'")
 	}
 	return nil
 }
@@ -280,7 +330,7 @@ func (s *SegmentFilter) UnmarshalJSON(p []byte) error {
 	if err := json.Unmarshal(p, &tmp); err != nil {
 		return err
 	}
-	if tmp == "*" {
+	if tmp == singleSegmentWildcard || tmp == multiSegmentWildcard {
 		s.wildcard = &tmp
 	} else {
 		var out xdr.ScVal
@@ -298,22 +348,40 @@ type PaginationOptions struct {
 }
 
 type GetEventsResponse struct {
-	Events       []EventInfo `json:"events"`
-	LatestLedger uint32      `json:"latestLedger"`
+	Events []EventInfo `json:"events"`
+	// Cursor is the paging token of the last returned event, to be used as the cursor of the next page
+	Cursor string `json:"cursor,omitempty"`
+	// HasMore tells whether more events match the request after the returned ones. It's also set
+	// if no further match was found within the look-ahead bound (see maxEventsLookAhead), in
+	// which case the next page may be empty.
+	HasMore bool `json:"hasMore"`
+	// EstimatedTotalCount estimates the amount of events matching the request from the start of the
+	// page until the latest ledger (including the returned ones). It's extrapolated from the ratio of
+	// matching events among the scanned ones and exact if HasMore is false.
+	EstimatedTotalCount uint64 `json:"estimatedTotalCount"`
+	LedgerRangeResponse
 }
 
+// maxEventsLookAhead bounds the amount of events scanned after the page is full
+// while looking for a further match (to set HasMore), so that pages without
+// further matches don't scan (and decode) the rest of the retention window.
+const maxEventsLookAhead = 1000
+
 type eventScanner interface {
 	Scan(eventRange events.Range, f events.ScanFunction) (uint32, error)
+	Count(eventRange events.Range) (uint64, error)
+	GetLedgerRange() (ledgerbucketwindow.LedgerRange, error)
 }
 
 type eventsRPCHandler struct {
 	scanner      eventScanner
 	maxLimit     uint
 	defaultLimit uint
+	limits       RequestLimits
 }
 
-func (h eventsRPCHandler) getEvents(request GetEventsRequest) (GetEventsResponse, error) {
-	if err := request.Valid(h.maxLimit); err != nil {
+func (h eventsRPCHandler) getEvents(ctx context.Context, request GetEventsRequest) (GetEventsResponse, error) {
+	if err := request.Valid(h.maxLimit, h.limits); err != nil {
 		return GetEventsResponse{}, &jrpc2.Error{
 			Code:    jrpc2.InvalidParams,
 			Message: err.Error(),
@@ -334,27 +402,77 @@ func (h eventsRPCHandler) getEvents(request GetEventsRequest) (GetEventsResponse
 		}
 	}
 
+	if h.limits.MaxEventsLedgerRange > 0 {
+		ledgerRange, err := h.scanner.GetLedgerRange()
+		if err != nil {
+			return GetEventsResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		if latest := ledgerRange.LastLedger.Sequence; latest >= start.Ledger {
+			width := int(latest - start.Ledger + 1)
+			if err := checkLimit("ledger range", width, uint(h.limits.MaxEventsLedgerRange)); err != nil {
+				return GetEventsResponse{}, &jrpc2.Error{
+					Code:    jrpc2.InvalidParams,
+					Message: err.Error(),
+				}
+			}
+		}
+	}
+
 	type entry struct {
 		cursor               events.Cursor
 		ledgerCloseTimestamp int64
 		event                xdr.DiagnosticEvent
 		txHash               *xdr.Hash
+		txSuccessful         bool
+		matchedFilters       []int
 	}
 	var found []entry
+	// scanned counts all the events inspected by the scan, hasMore is set when
+	// a matching event is found beyond the limit or the look-ahead is exhausted
+	var scanned, lookAhead uint64
+	hasMore, lookAheadExhausted := false, false
+	scanRange := events.Range{
+		Start:              start,
+		ClampStart:         false,
+		End:                events.MaxCursor,
+		ClampEnd:           true,
+		ContractEventsOnly: request.ContractEventsOnly,
+		IncludeFailed:      request.IncludeFailed,
+	}
+	_, scanSpan := tracing.Tracer().Start(ctx, "events.scan")
 	latestLedger, err := h.scanner.Scan(
-		events.Range{
-			Start:      start,
-			ClampStart: false,
-			End:        events.MaxCursor,
-			ClampEnd:   true,
-		},
-		func(event xdr.DiagnosticEvent, cursor events.Cursor, ledgerCloseTimestamp int64, txHash *xdr.Hash) bool {
-			if request.Matches(event) {
-				found = append(found, entry{cursor, ledgerCloseTimestamp, event, txHash})
+		scanRange,
+		func(event xdr.DiagnosticEvent, cursor events.Cursor, ledgerCloseTimestamp int64, txHash *xdr.Hash, txSuccessful bool) bool {
+			if uint(len(found)) == limit {
+				if lookAhead++; lookAhead > maxEventsLookAhead {
+					hasMore, lookAheadExhausted = true, true
+					return false
+				}
+			}
+			scanned++
+			var matchedFilters []int
+			if len(request.Filters) > 1 {
+				// all the filters are evaluated, to report which of them each event matches
+				matchedFilters = request.matchedFilters(event)
+				if len(matchedFilters) == 0 {
+					return true
+				}
+			} else if !request.Matches(event) {
+				return true
 			}
-			return uint(len(found)) < limit
+			if uint(len(found)) == limit {
+				hasMore = true
+				return false
+			}
+			found = append(found, entry{cursor, ledgerCloseTimestamp, event, txHash, txSuccessful, matchedFilters})
+			return true
 		},
 	)
+	scanSpan.SetAttributes(attribute.Int("events", len(found)))
+	tracing.End(scanSpan, err)
 	if err != nil {
 		return GetEventsResponse{}, &jrpc2.Error{
 			Code:    jrpc2.InvalidRequest,
@@ -362,6 +480,26 @@ func (h eventsRPCHandler) getEvents(request GetEventsRequest) (GetEventsResponse
 		}
 	}
 
+	estimatedTotalCount := uint64(len(found))
+	if hasMore {
+		// extrapolate the rate of matching events among the scanned ones (including
+		// the one beyond the limit, if found) to the events which weren't scanned
+		total, err := h.scanner.Count(scanRange)
+		if err != nil {
+			return GetEventsResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidRequest,
+				Message: err.Error(),
+			}
+		}
+		matched := estimatedTotalCount
+		if !lookAheadExhausted {
+			matched++
+		}
+		estimatedTotalCount = max(matched, uint64(float64(total)*float64(matched)/float64(scanned)))
+	}
+
+	_, decodeSpan := tracing.Tracer().Start(ctx, "events.decode")
+	defer decodeSpan.End()
 	results := []EventInfo{}
 	for _, entry := range found {
 		info, err := eventInfoForEvent(
@@ -373,12 +511,23 @@ func (h eventsRPCHandler) getEvents(request GetEventsRequest) (GetEventsResponse
 		if err != nil {
 			return GetEventsResponse{}, errors.Wrap(err, "could not parse event")
 		}
+		info.InSuccessfulTransaction = entry.txSuccessful
+		info.MatchedFilters = entry.matchedFilters
+		if err := chargeResponseSize(ctx, info.responseSize()); err != nil {
+			return GetEventsResponse{}, err
+		}
 		results = append(results, info)
 	}
-	return GetEventsResponse{
-		LatestLedger: latestLedger,
-		Events:       results,
-	}, nil
+	response := GetEventsResponse{
+		LedgerRangeResponse: LedgerRangeResponse{LatestLedger: latestLedger},
+		Events:              results,
+		HasMore:             hasMore,
+		EstimatedTotalCount: estimatedTotalCount,
+	}
+	if len(results) > 0 {
+		response.Cursor = results[len(results)-1].PagingToken
+	}
+	return response, nil
 }
 
 func eventInfoForEvent(event xdr.DiagnosticEvent, cursor events.Cursor, ledgerClosedAt string, txHash string) (EventInfo, error) {
@@ -426,13 +575,14 @@ func eventInfoForEvent(event xdr.DiagnosticEvent, cursor events.Cursor, ledgerCl
 }
 
 // NewGetEventsHandler returns a json rpc handler to fetch and filter events
-func NewGetEventsHandler(eventsStore *events.MemoryStore, maxLimit, defaultLimit uint) jrpc2.Handler {
+func NewGetEventsHandler(eventsStore *events.MemoryStore, maxLimit, defaultLimit uint, limits RequestLimits) jrpc2.Handler {
 	eventsHandler := eventsRPCHandler{
 		scanner:      eventsStore,
 		maxLimit:     maxLimit,
 		defaultLimit: defaultLimit,
+		limits:       limits,
 	}
 	return NewHandler(func(ctx context.Context, request GetEventsRequest) (GetEventsResponse, error) {
-		return eventsHandler.getEvents(request)
+		return eventsHandler.getEvents(ctx, request)
 	})
 }
@@ -1,6 +1,7 @@
 package methods
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -205,6 +206,7 @@ func TestTopicFilterMatches(t *testing.T) {
 		U64:  &sixtyfour,
 	}
 	star := "*"
+	doubleStar := "**"
 	for _, tc := range []struct {
 		name     string
 		filter   TopicFilter
@@ -330,6 +332,52 @@ func TestTopicFilterMatches(t *testing.T) {
 				{transfer, number, transfer},
 			},
 		},
+
+		// Double star
+		{
+			name: "**",
+			filter: []SegmentFilter{
+				{wildcard: &doubleStar},
+			},
+			includes: []xdr.ScVec{
+				{},
+				{transfer},
+				{number, transfer, number, number},
+			},
+		},
+		{
+			name: "transfer/**",
+			filter: []SegmentFilter{
+				{scval: &transfer},
+				{wildcard: &doubleStar},
+			},
+			includes: []xdr.ScVec{
+				{transfer},
+				{transfer, number},
+				{transfer, number, number, number},
+			},
+			excludes: []xdr.ScVec{
+				{},
+				{number},
+				{number, transfer},
+			},
+		},
+		{
+			name: "transfer/*/**",
+			filter: []SegmentFilter{
+				{scval: &transfer},
+				{wildcard: &star},
+				{wildcard: &doubleStar},
+			},
+			includes: []xdr.ScVec{
+				{transfer, number},
+				{transfer, transfer, number, number},
+			},
+			excludes: []xdr.ScVec{
+				{transfer},
+				{number, transfer, number},
+			},
+		},
 	} {
 		name := tc.name
 		if name == "" {
@@ -368,6 +416,10 @@ func TestTopicFilterJSON(t *testing.T) {
 	assert.NoError(t, json.Unmarshal([]byte("[\"*\"]"), &got))
 	assert.Equal(t, TopicFilter{{wildcard: &star}}, got)
 
+	doubleStar := "**"
+	assert.NoError(t, json.Unmarshal([]byte("[\"*\", \"**\"]"), &got))
+	assert.Equal(t, TopicFilter{{wildcard: &star}, {wildcard: &doubleStar}}, got)
+
 	sixtyfour := xdr.Uint64(64)
 	scval := xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &sixtyfour}
 	scvalstr, err := xdr.MarshalBase64(scval)
@@ -376,6 +428,24 @@ func TestTopicFilterJSON(t *testing.T) {
 	assert.Equal(t, TopicFilter{{scval: &scval}}, got)
 }
 
+func TestTopicFilterValid(t *testing.T) {
+	star := "*"
+	doubleStar := "**"
+	invalid := "***"
+	transferSym := xdr.ScSymbol("transfer")
+	transfer := xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &transferSym}
+
+	assert.NoError(t, (&TopicFilter{{scval: &transfer}, {wildcard: &star}, {wildcard: &doubleStar}}).Valid())
+	assert.EqualError(t,
+		(&TopicFilter{{wildcard: &doubleStar}, {scval: &transfer}}).Valid(),
+		"segment 1 invalid: wildcard '**' must be the last segment",
+	)
+	assert.EqualError(t,
+		(&TopicFilter{{wildcard: &invalid}}).Valid(),
+		"segment 1 invalid: wildcard must be '*' or '**'",
+	)
+}
+
 func topicFilterToString(t TopicFilter) string {
 	var s []string
 	for _, segment := range t {
@@ -405,31 +475,31 @@ func TestGetEventsRequestValid(t *testing.T) {
 		&request,
 	))
 	assert.Equal(t, uint32(0), request.StartLedger)
-	assert.NoError(t, request.Valid(1000))
+	assert.NoError(t, request.Valid(1000, DefaultRequestLimits()))
 
 	assert.EqualError(t, (&GetEventsRequest{
 		StartLedger: 1,
 		Filters:     []EventFilter{},
 		Pagination:  &PaginationOptions{Cursor: &events.Cursor{}},
-	}).Valid(1000), "startLedger and cursor cannot both be set")
+	}).Valid(1000, DefaultRequestLimits()), "startLedger and cursor cannot both be set")
 
 	assert.NoError(t, (&GetEventsRequest{
 		StartLedger: 1,
 		Filters:     []EventFilter{},
 		Pagination:  nil,
-	}).Valid(1000))
+	}).Valid(1000, DefaultRequestLimits()))
 
 	assert.EqualError(t, (&GetEventsRequest{
 		StartLedger: 1,
 		Filters:     []EventFilter{},
 		Pagination:  &PaginationOptions{Limit: 1001},
-	}).Valid(1000), "limit must not exceed 1000")
+	}).Valid(1000, DefaultRequestLimits()), "limit must not exceed 1000")
 
 	assert.EqualError(t, (&GetEventsRequest{
 		StartLedger: 0,
 		Filters:     []EventFilter{},
 		Pagination:  nil,
-	}).Valid(1000), "startLedger must be positive")
+	}).Valid(1000, DefaultRequestLimits()), "startLedger must be positive")
 
 	assert.EqualError(t, (&GetEventsRequest{
 		StartLedger: 1,
@@ -437,7 +507,7 @@ func TestGetEventsRequestValid(t *testing.T) {
 			{}, {}, {}, {}, {}, {},
 		},
 		Pagination: nil,
-	}).Valid(1000), "maximum 5 filters per request")
+	}).Valid(1000, DefaultRequestLimits()), "filter count (6) exceeds the maximum allowed (5)")
 
 	assert.EqualError(t, (&GetEventsRequest{
 		StartLedger: 1,
@@ -445,7 +515,7 @@ func TestGetEventsRequestValid(t *testing.T) {
 			{EventType: map[string]interface{}{"foo": nil}},
 		},
 		Pagination: nil,
-	}).Valid(1000), "filter 1 invalid: filter type invalid: if set, type must be either 'system', 'contract' or 'diagnostic'")
+	}).Valid(1000, DefaultRequestLimits()), "filter 1 invalid: filter type invalid: if set, type must be either 'system', 'contract' or 'diagnostic'")
 
 	assert.EqualError(t, (&GetEventsRequest{
 		StartLedger: 1,
@@ -460,7 +530,7 @@ func TestGetEventsRequestValid(t *testing.T) {
 			}},
 		},
 		Pagination: nil,
-	}).Valid(1000), "filter 1 invalid: maximum 5 contract IDs per filter")
+	}).Valid(1000, DefaultRequestLimits()), "filter 1 invalid: contract ID count (6) exceeds the maximum allowed (5)")
 
 	assert.EqualError(t, (&GetEventsRequest{
 		StartLedger: 1,
@@ -468,7 +538,7 @@ func TestGetEventsRequestValid(t *testing.T) {
 			{ContractIDs: []string{"a"}},
 		},
 		Pagination: nil,
-	}).Valid(1000), "filter 1 invalid: contract ID 1 invalid")
+	}).Valid(1000, DefaultRequestLimits()), "filter 1 invalid: contract ID 1 invalid")
 
 	assert.EqualError(t, (&GetEventsRequest{
 		StartLedger: 1,
@@ -476,7 +546,7 @@ func TestGetEventsRequestValid(t *testing.T) {
 			{ContractIDs: []string{"CCVKVKVKVKVKVKVKVKVKVKVKVKVKVKVKVKVKVKVKVKVKVKVKVINVALID"}},
 		},
 		Pagination: nil,
-	}).Valid(1000), "filter 1 invalid: contract ID 1 invalid")
+	}).Valid(1000, DefaultRequestLimits()), "filter 1 invalid: contract ID 1 invalid")
 
 	assert.EqualError(t, (&GetEventsRequest{
 		StartLedger: 1,
@@ -488,7 +558,7 @@ func TestGetEventsRequestValid(t *testing.T) {
 			},
 		},
 		Pagination: nil,
-	}).Valid(1000), "filter 1 invalid: maximum 5 topics per filter")
+	}).Valid(1000, DefaultRequestLimits()), "filter 1 invalid: topic count (6) exceeds the maximum allowed (5)")
 
 	assert.EqualError(t, (&GetEventsRequest{
 		StartLedger: 1,
@@ -498,7 +568,7 @@ func TestGetEventsRequestValid(t *testing.T) {
 			}},
 		},
 		Pagination: nil,
-	}).Valid(1000), "filter 1 invalid: topic 1 invalid: topic must have at least one segment")
+	}).Valid(1000, DefaultRequestLimits()), "filter 1 invalid: topic 1 invalid: topic must have at least one segment")
 
 	assert.EqualError(t, (&GetEventsRequest{
 		StartLedger: 1,
@@ -514,7 +584,7 @@ func TestGetEventsRequestValid(t *testing.T) {
 			}},
 		},
 		Pagination: nil,
-	}).Valid(1000), "filter 1 invalid: topic 1 invalid: topic cannot have more than 4 segments")
+	}).Valid(1000, DefaultRequestLimits()), "filter 1 invalid: topic 1 invalid: topic cannot have more than 4 segments")
 }
 
 func TestGetEvents(t *testing.T) {
@@ -531,7 +601,7 @@ func TestGetEvents(t *testing.T) {
 			maxLimit:     10000,
 			defaultLimit: 100,
 		}
-		_, err = handler.getEvents(GetEventsRequest{
+		_, err = handler.getEvents(context.Background(), GetEventsRequest{
 			StartLedger: 1,
 		})
 		assert.EqualError(t, err, "[-32600] event store is empty")
@@ -561,12 +631,12 @@ func TestGetEvents(t *testing.T) {
 			maxLimit:     10000,
 			defaultLimit: 100,
 		}
-		_, err = handler.getEvents(GetEventsRequest{
+		_, err = handler.getEvents(context.Background(), GetEventsRequest{
 			StartLedger: 1,
 		})
 		assert.EqualError(t, err, "[-32600] start is before oldest ledger")
 
-		_, err = handler.getEvents(GetEventsRequest{
+		_, err = handler.getEvents(context.Background(), GetEventsRequest{
 			StartLedger: 3,
 		})
 		assert.EqualError(t, err, "[-32600] start is after newest ledger")
@@ -599,7 +669,7 @@ func TestGetEvents(t *testing.T) {
 			maxLimit:     10000,
 			defaultLimit: 100,
 		}
-		results, err := handler.getEvents(GetEventsRequest{
+		results, err := handler.getEvents(context.Background(), GetEventsRequest{
 			StartLedger: 1,
 		})
 		assert.NoError(t, err)
@@ -628,9 +698,15 @@ func TestGetEvents(t *testing.T) {
 				Value:                    value,
 				InSuccessfulContractCall: true,
 				TransactionHash:          ledgerCloseMeta.TransactionHash(i).HexString(),
+				InSuccessfulTransaction:  true,
 			})
 		}
-		assert.Equal(t, GetEventsResponse{expected, 1}, results)
+		assert.Equal(t, GetEventsResponse{
+			Events:              expected,
+			Cursor:              expected[len(expected)-1].PagingToken,
+			EstimatedTotalCount: uint64(len(expected)),
+			LedgerRangeResponse: LedgerRangeResponse{LatestLedger: 1},
+		}, results)
 	})
 
 	t.Run("filtering by contract id", func(t *testing.T) {
@@ -662,7 +738,7 @@ func TestGetEvents(t *testing.T) {
 			maxLimit:     10000,
 			defaultLimit: 100,
 		}
-		results, err := handler.getEvents(GetEventsRequest{
+		results, err := handler.getEvents(context.Background(), GetEventsRequest{
 			StartLedger: 1,
 			Filters: []EventFilter{
 				{ContractIDs: []string{strkey.MustEncode(strkey.VersionByteContract, contractIds[0][:])}},
@@ -710,7 +786,7 @@ func TestGetEvents(t *testing.T) {
 			maxLimit:     10000,
 			defaultLimit: 100,
 		}
-		results, err := handler.getEvents(GetEventsRequest{
+		results, err := handler.getEvents(context.Background(), GetEventsRequest{
 			StartLedger: 1,
 			Filters: []EventFilter{
 				{Topics: []TopicFilter{
@@ -742,9 +818,15 @@ func TestGetEvents(t *testing.T) {
 				Value:                    value,
 				InSuccessfulContractCall: true,
 				TransactionHash:          ledgerCloseMeta.TransactionHash(4).HexString(),
+				InSuccessfulTransaction:  true,
 			},
 		}
-		assert.Equal(t, GetEventsResponse{expected, 1}, results)
+		assert.Equal(t, GetEventsResponse{
+			Events:              expected,
+			Cursor:              expected[len(expected)-1].PagingToken,
+			EstimatedTotalCount: uint64(len(expected)),
+			LedgerRangeResponse: LedgerRangeResponse{LatestLedger: 1},
+		}, results)
 	})
 
 	t.Run("filtering by both contract id and topic", func(t *testing.T) {
@@ -804,7 +886,7 @@ func TestGetEvents(t *testing.T) {
 			maxLimit:     10000,
 			defaultLimit: 100,
 		}
-		results, err := handler.getEvents(GetEventsRequest{
+		results, err := handler.getEvents(context.Background(), GetEventsRequest{
 			StartLedger: 1,
 			Filters: []EventFilter{
 				{
@@ -838,9 +920,87 @@ func TestGetEvents(t *testing.T) {
 				Value:                    value,
 				InSuccessfulContractCall: true,
 				TransactionHash:          ledgerCloseMeta.TransactionHash(3).HexString(),
+				InSuccessfulTransaction:  true,
 			},
 		}
-		assert.Equal(t, GetEventsResponse{expected, 1}, results)
+		assert.Equal(t, GetEventsResponse{
+			Events:              expected,
+			Cursor:              expected[len(expected)-1].PagingToken,
+			EstimatedTotalCount: uint64(len(expected)),
+			LedgerRangeResponse: LedgerRangeResponse{LatestLedger: 1},
+		}, results)
+	})
+
+	t.Run("several filters", func(t *testing.T) {
+		store := events.NewMemoryStore(interfaces.MakeNoOpDeamon(), "unit-tests", 100)
+		contractIDs := []xdr.Hash{{}, {1}, {2}}
+		var txMeta []xdr.TransactionMeta
+		for i := 0; i < 6; i++ {
+			number := xdr.Uint64(i % 2)
+			txMeta = append(txMeta, transactionMetaWithEvents(
+				contractEvent(
+					contractIDs[i%len(contractIDs)],
+					xdr.ScVec{
+						xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &counter},
+						xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &number},
+					},
+					xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &number},
+				),
+			))
+		}
+		assert.NoError(t, store.IngestEvents(ledgerCloseMetaWithEvents(1, now.Unix(), txMeta...)))
+
+		handler := eventsRPCHandler{
+			scanner:      store,
+			maxLimit:     10000,
+			defaultLimit: 100,
+		}
+		one := xdr.Uint64(1)
+		star := "*"
+		results, err := handler.getEvents(context.Background(), GetEventsRequest{
+			StartLedger: 1,
+			Filters: []EventFilter{
+				{ContractIDs: []string{strkey.MustEncode(strkey.VersionByteContract, contractIDs[0][:])}},
+				{
+					ContractIDs: []string{strkey.MustEncode(strkey.VersionByteContract, contractIDs[1][:])},
+					Topics: []TopicFilter{
+						[]SegmentFilter{
+							{scval: &xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &counter}},
+							{scval: &xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &one}},
+						},
+					},
+				},
+				{Topics: []TopicFilter{
+					[]SegmentFilter{
+						{wildcard: &star},
+						{scval: &xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &one}},
+					},
+				}},
+			},
+		})
+		assert.NoError(t, err)
+
+		// contract ids and topics of the events, by transaction:
+		// 1: contract 0, topic 0
+		// 2: contract 1, topic 1
+		// 3: contract 2, topic 0
+		// 4: contract 0, topic 1
+		// 5: contract 1, topic 0
+		// 6: contract 2, topic 1
+		type match struct {
+			id             string
+			matchedFilters []int
+		}
+		var matches []match
+		for _, event := range results.Events {
+			matches = append(matches, match{event.ID, event.MatchedFilters})
+		}
+		assert.Equal(t, []match{
+			{events.Cursor{Ledger: 1, Tx: 1}.String(), []int{0}},
+			{events.Cursor{Ledger: 1, Tx: 2}.String(), []int{1, 2}},
+			{events.Cursor{Ledger: 1, Tx: 4}.String(), []int{0, 2}},
+			{events.Cursor{Ledger: 1, Tx: 6}.String(), []int{2}},
+		}, matches)
 	})
 
 	t.Run("filtering by event type", func(t *testing.T) {
@@ -879,7 +1039,7 @@ func TestGetEvents(t *testing.T) {
 			maxLimit:     10000,
 			defaultLimit: 100,
 		}
-		results, err := handler.getEvents(GetEventsRequest{
+		results, err := handler.getEvents(context.Background(), GetEventsRequest{
 			StartLedger: 1,
 			Filters: []EventFilter{
 				{EventType: map[string]interface{}{EventTypeSystem: nil}},
@@ -900,9 +1060,102 @@ func TestGetEvents(t *testing.T) {
 				Value:                    counterXdr,
 				InSuccessfulContractCall: true,
 				TransactionHash:          ledgerCloseMeta.TransactionHash(0).HexString(),
+				InSuccessfulTransaction:  true,
 			},
 		}
-		assert.Equal(t, GetEventsResponse{expected, 1}, results)
+		assert.Equal(t, GetEventsResponse{
+			Events:              expected,
+			Cursor:              expected[len(expected)-1].PagingToken,
+			EstimatedTotalCount: uint64(len(expected)),
+			LedgerRangeResponse: LedgerRangeResponse{LatestLedger: 1},
+		}, results)
+	})
+
+	t.Run("contract events only", func(t *testing.T) {
+		store := events.NewMemoryStore(interfaces.MakeNoOpDeamon(), "unit-tests", 100)
+		contractID := xdr.Hash([32]byte{})
+		topic := xdr.ScVec{xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &counter}}
+		body := xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &counter}
+		withFailedCall := transactionMetaWithEvents()
+		withFailedCall.V3.SorobanMeta.DiagnosticEvents = []xdr.DiagnosticEvent{
+			// emitted by a contract call which failed (and was rolled back)
+			{InSuccessfulContractCall: false, Event: contractEvent(contractID, topic, body)},
+			{InSuccessfulContractCall: true, Event: diagnosticEvent(contractID, topic, body)},
+		}
+		txMeta := []xdr.TransactionMeta{
+			transactionMetaWithEvents(
+				systemEvent(contractID, topic, body),
+				contractEvent(contractID, topic, body),
+				diagnosticEvent(contractID, topic, body),
+			),
+			withFailedCall,
+		}
+		assert.NoError(t, store.IngestEvents(ledgerCloseMetaWithEvents(1, now.Unix(), txMeta...)))
+
+		handler := eventsRPCHandler{
+			scanner:      store,
+			maxLimit:     10000,
+			defaultLimit: 100,
+		}
+		results, err := handler.getEvents(context.Background(), GetEventsRequest{
+			StartLedger:        1,
+			ContractEventsOnly: true,
+		})
+		assert.NoError(t, err)
+		if assert.Len(t, results.Events, 1) {
+			assert.Equal(t, EventTypeContract, results.Events[0].EventType)
+			assert.True(t, results.Events[0].InSuccessfulContractCall)
+			assert.Equal(t, events.Cursor{Ledger: 1, Tx: 1, Op: 0, Event: 1}.String(), results.Events[0].ID)
+		}
+		assert.Equal(t, uint64(1), results.EstimatedTotalCount)
+
+		// the other events are returned without the flag
+		results, err = handler.getEvents(context.Background(), GetEventsRequest{StartLedger: 1})
+		assert.NoError(t, err)
+		assert.Len(t, results.Events, 5)
+	})
+
+	t.Run("include failed", func(t *testing.T) {
+		store := events.NewMemoryStore(interfaces.MakeNoOpDeamon(), "unit-tests", 100)
+		contractID := xdr.Hash([32]byte{})
+		topic := xdr.ScVec{xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &counter}}
+		body := xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &counter}
+		failed := transactionMetaWithEvents()
+		failed.V3.SorobanMeta.DiagnosticEvents = []xdr.DiagnosticEvent{
+			{InSuccessfulContractCall: false, Event: diagnosticEvent(contractID, topic, body)},
+		}
+		ledger := ledgerCloseMetaWithEvents(1, now.Unix(),
+			transactionMetaWithEvents(contractEvent(contractID, topic, body)),
+			failed,
+		)
+		opResults := []xdr.OperationResult{}
+		ledger.V1.TxProcessing[1].Result.Result.Result = xdr.TransactionResultResult{
+			Code:    xdr.TransactionResultCodeTxFailed,
+			Results: &opResults,
+		}
+		assert.NoError(t, store.IngestEvents(ledger))
+
+		handler := eventsRPCHandler{
+			scanner:      store,
+			maxLimit:     10000,
+			defaultLimit: 100,
+		}
+		results, err := handler.getEvents(context.Background(), GetEventsRequest{StartLedger: 1})
+		assert.NoError(t, err)
+		if assert.Len(t, results.Events, 1) {
+			assert.True(t, results.Events[0].InSuccessfulTransaction)
+		}
+
+		results, err = handler.getEvents(context.Background(), GetEventsRequest{StartLedger: 1, IncludeFailed: true})
+		assert.NoError(t, err)
+		if assert.Len(t, results.Events, 2) {
+			assert.True(t, results.Events[0].InSuccessfulTransaction)
+			failedEvent := results.Events[1]
+			assert.False(t, failedEvent.InSuccessfulTransaction)
+			assert.Equal(t, EventTypeDiagnostic, failedEvent.EventType)
+			assert.Equal(t, ledger.TransactionHash(1).HexString(), failedEvent.TransactionHash)
+		}
+		assert.Equal(t, uint64(2), results.EstimatedTotalCount)
 	})
 
 	t.Run("with limit", func(t *testing.T) {
@@ -929,7 +1182,7 @@ func TestGetEvents(t *testing.T) {
 			maxLimit:     10000,
 			defaultLimit: 100,
 		}
-		results, err := handler.getEvents(GetEventsRequest{
+		results, err := handler.getEvents(context.Background(), GetEventsRequest{
 			StartLedger: 1,
 			Filters:     []EventFilter{},
 			Pagination:  &PaginationOptions{Limit: 10},
@@ -957,9 +1210,103 @@ func TestGetEvents(t *testing.T) {
 				Value:                    value,
 				InSuccessfulContractCall: true,
 				TransactionHash:          ledgerCloseMeta.TransactionHash(i).HexString(),
+				InSuccessfulTransaction:  true,
 			})
 		}
-		assert.Equal(t, GetEventsResponse{expected, 1}, results)
+		assert.Equal(t, GetEventsResponse{
+			Events:              expected,
+			Cursor:              expected[len(expected)-1].PagingToken,
+			HasMore:             true,
+			EstimatedTotalCount: 180,
+			LedgerRangeResponse: LedgerRangeResponse{LatestLedger: 1},
+		}, results)
+	})
+
+	t.Run("with limit and filter", func(t *testing.T) {
+		store := events.NewMemoryStore(interfaces.MakeNoOpDeamon(), "unit-tests", 100)
+		contractID := xdr.Hash([32]byte{})
+		var txMeta []xdr.TransactionMeta
+		for i := 0; i < 100; i++ {
+			number := xdr.Uint64(i % 2)
+			txMeta = append(txMeta, transactionMetaWithEvents(
+				contractEvent(
+					contractID,
+					xdr.ScVec{
+						xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &number},
+					},
+					xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &number},
+				),
+			))
+		}
+		assert.NoError(t, store.IngestEvents(ledgerCloseMetaWithEvents(1, now.Unix(), txMeta...)))
+
+		even := xdr.Uint64(0)
+		topic, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &even})
+		assert.NoError(t, err)
+		var filter TopicFilter
+		assert.NoError(t, json.Unmarshal([]byte(fmt.Sprintf("[%q]", topic)), &filter))
+		handler := eventsRPCHandler{
+			scanner:      store,
+			maxLimit:     10000,
+			defaultLimit: 100,
+		}
+		results, err := handler.getEvents(context.Background(), GetEventsRequest{
+			StartLedger: 1,
+			Filters:     []EventFilter{{Topics: []TopicFilter{filter}}},
+			Pagination:  &PaginationOptions{Limit: 10},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, results.Events, 10)
+		assert.True(t, results.HasMore)
+		// 11 out of the 21 scanned events match, extrapolated to the 100 events in the range
+		assert.Equal(t, uint64(52), results.EstimatedTotalCount)
+		assert.Equal(t, results.Events[9].PagingToken, results.Cursor)
+	})
+
+	t.Run("with limit and exhausted look-ahead", func(t *testing.T) {
+		store := events.NewMemoryStore(interfaces.MakeNoOpDeamon(), "unit-tests", 100)
+		contractID := xdr.Hash([32]byte{})
+		var txMeta []xdr.TransactionMeta
+		for i := 0; i < 1500; i++ {
+			// only the first 10 events and the last one match
+			number := xdr.Uint64(1)
+			if i < 10 || i == 1499 {
+				number = 0
+			}
+			txMeta = append(txMeta, transactionMetaWithEvents(
+				contractEvent(
+					contractID,
+					xdr.ScVec{
+						xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &number},
+					},
+					xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &number},
+				),
+			))
+		}
+		assert.NoError(t, store.IngestEvents(ledgerCloseMetaWithEvents(1, now.Unix(), txMeta...)))
+
+		zero := xdr.Uint64(0)
+		topic, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &zero})
+		assert.NoError(t, err)
+		var filter TopicFilter
+		assert.NoError(t, json.Unmarshal([]byte(fmt.Sprintf("[%q]", topic)), &filter))
+		handler := eventsRPCHandler{
+			scanner:      store,
+			maxLimit:     10000,
+			defaultLimit: 100,
+		}
+		results, err := handler.getEvents(context.Background(), GetEventsRequest{
+			StartLedger: 1,
+			Filters:     []EventFilter{{Topics: []TopicFilter{filter}}},
+			Pagination:  &PaginationOptions{Limit: 10},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, results.Events, 10)
+		// the last matching event is beyond the look-ahead, so there may be more
+		assert.True(t, results.HasMore)
+		// 10 out of the 1010 scanned events match, extrapolated to the 1500 events in the range
+		assert.Equal(t, uint64(14), results.EstimatedTotalCount)
+		assert.Equal(t, results.Events[9].PagingToken, results.Cursor)
 	})
 
 	t.Run("with cursor", func(t *testing.T) {
@@ -1015,7 +1362,7 @@ func TestGetEvents(t *testing.T) {
 			maxLimit:     10000,
 			defaultLimit: 100,
 		}
-		results, err := handler.getEvents(GetEventsRequest{
+		results, err := handler.getEvents(context.Background(), GetEventsRequest{
 			Pagination: &PaginationOptions{
 				Cursor: id,
 				Limit:  2,
@@ -1043,18 +1390,25 @@ func TestGetEvents(t *testing.T) {
 				Value:                    expectedXdr,
 				InSuccessfulContractCall: true,
 				TransactionHash:          ledgerCloseMeta.TransactionHash(i).HexString(),
+				InSuccessfulTransaction:  true,
 			})
 		}
-		assert.Equal(t, GetEventsResponse{expected, 5}, results)
-
-		results, err = handler.getEvents(GetEventsRequest{
+		assert.Equal(t, GetEventsResponse{
+			Events:              expected,
+			Cursor:              expected[len(expected)-1].PagingToken,
+			HasMore:             true,
+			EstimatedTotalCount: 3,
+			LedgerRangeResponse: LedgerRangeResponse{LatestLedger: 5},
+		}, results)
+
+		results, err = handler.getEvents(context.Background(), GetEventsRequest{
 			Pagination: &PaginationOptions{
 				Cursor: &events.Cursor{Ledger: 5, Tx: 2, Op: 0, Event: 1},
 				Limit:  2,
 			},
 		})
 		assert.NoError(t, err)
-		assert.Equal(t, GetEventsResponse{[]EventInfo{}, 5}, results)
+		assert.Equal(t, GetEventsResponse{Events: []EventInfo{}, LedgerRangeResponse: LedgerRangeResponse{LatestLedger: 5}}, results)
 	})
 }
 
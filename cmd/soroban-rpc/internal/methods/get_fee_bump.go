@@ -0,0 +1,299 @@
+package methods
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/feewindow"
+)
+
+const (
+	// maxSubmittedTransactionsSize bounds the amount of submitted envelopes remembered for getFeeBump
+	maxSubmittedTransactionsSize = 10000
+	// defaultFeeBumpPercentile is the fee stats percentile used when the request doesn't provide one
+	defaultFeeBumpPercentile = 90
+	// queueReplacementFeeMultiplier is how many times the fee rate of a transaction queued by stellar-core
+	// must be exceeded by a fee-bump replacing it
+	queueReplacementFeeMultiplier = 10
+)
+
+// SubmittedTransactions remembers the envelopes of the transactions accepted by sendTransaction,
+// so that getFeeBump can refer to them by hash.
+type SubmittedTransactions struct {
+	lock      sync.Mutex
+	envelopes map[string]string
+}
+
+func NewSubmittedTransactions() *SubmittedTransactions {
+	return &SubmittedTransactions{envelopes: map[string]string{}}
+}
+
+func (s *SubmittedTransactions) add(hash string, envelope string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.envelopes[hash]; !ok && len(s.envelopes) >= maxSubmittedTransactionsSize {
+		// evict an arbitrary entry
+		for k := range s.envelopes {
+			delete(s.envelopes, k)
+			break
+		}
+	}
+	s.envelopes[hash] = envelope
+}
+
+func (s *SubmittedTransactions) get(hash string) (string, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	envelope, ok := s.envelopes[hash]
+	return envelope, ok
+}
+
+type GetFeeBumpRequest struct {
+	// Hash is the hash of a transaction previously submitted through this node with sendTransaction.
+	// Either Hash or Transaction must be provided.
+	Hash string `json:"hash,omitempty"`
+	// Transaction is the base64 encoded envelope of the transaction to fee-bump
+	Transaction string `json:"transaction,omitempty"`
+	// FeeSource is the account paying the fee. When present, the (unsigned) fee-bump
+	// envelope is included in the response.
+	FeeSource string `json:"feeSource,omitempty"`
+	// Percentile of the recent inclusion fees to match (10, 20, ..., 90, 95 or 99), 90 by default
+	Percentile uint `json:"percentile,omitempty"`
+}
+
+type GetFeeBumpResponse struct {
+	// Hash is the hash of the inner transaction
+	Hash string `json:"hash"`
+	// InnerInclusionFee is the inclusion fee per operation currently offered by the transaction
+	InnerInclusionFee int64 `json:"innerInclusionFee,string"`
+	// InclusionFee is the recommended inclusion fee per operation of the fee-bump,
+	// which counts as an extra operation
+	InclusionFee int64 `json:"inclusionFee,string"`
+	// ResourceFee is the resource fee of Soroban transactions
+	ResourceFee int64 `json:"resourceFee,string,omitempty"`
+	// Fee is the recommended fee of the fee-bump transaction
+	Fee int64 `json:"fee,string"`
+	// ReplacementFee is the minimum fee of a fee-bump replacing the transaction while it's still
+	// queued by stellar-core. It can be higher than Fee, in which case the fee-bump is rejected
+	// until the transaction is dropped from the queue.
+	ReplacementFee int64 `json:"replacementFee,string"`
+	// FeeBumpTransactionXDR is the unsigned fee-bump envelope, present if FeeSource was provided
+	FeeBumpTransactionXDR string `json:"feeBumpTransactionXdr,omitempty"`
+	LedgerRangeResponse
+}
+
+type feeBumpHandler struct {
+	logger     *log.Entry
+	reader     db.TransactionReader
+	windows    *feewindow.FeeWindows
+	submitted  *SubmittedTransactions
+	passphrase string
+}
+
+func (h feeBumpHandler) getFeeBump(ctx context.Context, request GetFeeBumpRequest) (GetFeeBumpResponse, error) {
+	percentile := request.Percentile
+	if percentile == 0 {
+		percentile = defaultFeeBumpPercentile
+	}
+	if _, ok := feeDistributionPercentile(feewindow.FeeDistribution{}, percentile); !ok {
+		return GetFeeBumpResponse{}, invalidParamsf("unsupported percentile (%d)", percentile)
+	}
+
+	envelopeXDR := request.Transaction
+	switch {
+	case request.Hash != "" && envelopeXDR != "":
+		return GetFeeBumpResponse{}, invalidParamsf("hash and transaction cannot be provided at the same time")
+	case request.Hash != "":
+		var ok bool
+		if envelopeXDR, ok = h.submitted.get(request.Hash); !ok {
+			return GetFeeBumpResponse{}, invalidParamsf(
+				"transaction %s wasn't submitted through this node, provide its envelope instead", request.Hash)
+		}
+	case envelopeXDR == "":
+		return GetFeeBumpResponse{}, invalidParamsf("either hash or transaction must be provided")
+	}
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXDR, &envelope); err != nil {
+		return GetFeeBumpResponse{}, invalidParamsf("invalid_xdr")
+	}
+
+	innerEnvelope, currentInclusionFee, err := feeBumpInnerTransaction(envelope)
+	if err != nil {
+		return GetFeeBumpResponse{}, invalidParamsf("%v", err)
+	}
+	innerHash, err := network.HashTransaction(innerEnvelope.Tx, h.passphrase)
+	if err != nil {
+		return GetFeeBumpResponse{}, invalidParamsf("invalid_hash")
+	}
+
+	tx, ledgerRange, err := h.reader.GetTransaction(ctx, innerHash)
+	if err == nil {
+		return GetFeeBumpResponse{}, invalidParamsf(
+			"transaction %s was already included in ledger %d", hex.EncodeToString(innerHash[:]), tx.Ledger.Sequence)
+	} else if !errors.Is(err, db.ErrNoTransaction) {
+		h.logger.WithError(err).Error("could not fetch transaction")
+		return GetFeeBumpResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+
+	var resourceFee int64
+	window := h.windows.ClassicFeeWindow
+	if innerEnvelope.Tx.Ext.V == 1 {
+		resourceFee = int64(innerEnvelope.Tx.Ext.SorobanData.ResourceFee)
+		window = h.windows.SorobanInclusionFeeWindow
+	}
+	recentFee, _ := feeDistributionPercentile(window.GetFeeDistribution(), percentile)
+	inclusionFee := max(int64(recentFee), currentInclusionFee, txnbuild.MinBaseFee)
+	// the fee-bump counts as an extra operation
+	operationCount := int64(len(innerEnvelope.Tx.Operations) + 1)
+
+	response := GetFeeBumpResponse{
+		Hash:                hex.EncodeToString(innerHash[:]),
+		InnerInclusionFee:   currentInclusionFee,
+		InclusionFee:        inclusionFee,
+		ResourceFee:         resourceFee,
+		Fee:                 resourceFee + inclusionFee*operationCount,
+		ReplacementFee:      resourceFee + queueReplacementFeeMultiplier*currentInclusionFee*operationCount,
+		LedgerRangeResponse: NewLedgerRangeResponse(ledgerRange),
+	}
+	if request.FeeSource != "" {
+		var feeSource xdr.MuxedAccount
+		if err := feeSource.SetAddress(request.FeeSource); err != nil {
+			return GetFeeBumpResponse{}, invalidParamsf("invalid fee source: %v", err)
+		}
+		feeBump := xdr.TransactionEnvelope{
+			Type: xdr.EnvelopeTypeEnvelopeTypeTxFeeBump,
+			FeeBump: &xdr.FeeBumpTransactionEnvelope{
+				Tx: xdr.FeeBumpTransaction{
+					FeeSource: feeSource,
+					Fee:       xdr.Int64(response.Fee),
+					InnerTx: xdr.FeeBumpTransactionInnerTx{
+						Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+						V1:   &innerEnvelope,
+					},
+				},
+			},
+		}
+		if response.FeeBumpTransactionXDR, err = xdr.MarshalBase64(feeBump); err != nil {
+			return GetFeeBumpResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: fmt.Sprintf("could not encode the fee-bump transaction: %v", err),
+			}
+		}
+	}
+	return response, nil
+}
+
+// feeBumpInnerTransaction returns the transaction to be wrapped by a fee-bump and the
+// inclusion fee per operation currently offered for it. Fee-bump envelopes are unwrapped,
+// so that their inner transaction can be bumped again.
+func feeBumpInnerTransaction(envelope xdr.TransactionEnvelope) (xdr.TransactionV1Envelope, int64, error) {
+	var inner xdr.TransactionV1Envelope
+	switch envelope.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTxV0:
+		v0 := envelope.MustV0()
+		sourceAccount := v0.Tx.SourceAccountEd25519
+		inner = xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: xdr.MuxedAccount{Type: xdr.CryptoKeyTypeKeyTypeEd25519, Ed25519: &sourceAccount},
+				Fee:           v0.Tx.Fee,
+				SeqNum:        v0.Tx.SeqNum,
+				Memo:          v0.Tx.Memo,
+				Operations:    v0.Tx.Operations,
+			},
+			Signatures: v0.Signatures,
+		}
+		if v0.Tx.TimeBounds != nil {
+			inner.Tx.Cond = xdr.Preconditions{Type: xdr.PreconditionTypePrecondTime, TimeBounds: v0.Tx.TimeBounds}
+		}
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		inner = envelope.MustV1()
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		feeBump := envelope.MustFeeBump().Tx
+		inner = feeBump.InnerTx.MustV1()
+		inclusionFee := int64(feeBump.Fee) - innerResourceFee(inner)
+		return inner, inclusionFee / int64(len(inner.Tx.Operations)+1), nil
+	default:
+		return inner, 0, fmt.Errorf("unsupported envelope type %s", envelope.Type)
+	}
+	if len(inner.Tx.Operations) == 0 {
+		return inner, 0, errors.New("the transaction has no operations")
+	}
+	inclusionFee := int64(inner.Tx.Fee) - innerResourceFee(inner)
+	return inner, inclusionFee / int64(len(inner.Tx.Operations)), nil
+}
+
+func innerResourceFee(inner xdr.TransactionV1Envelope) int64 {
+	if inner.Tx.Ext.V != 1 {
+		return 0
+	}
+	return int64(inner.Tx.Ext.SorobanData.ResourceFee)
+}
+
+func feeDistributionPercentile(distribution feewindow.FeeDistribution, percentile uint) (uint64, bool) {
+	switch percentile {
+	case 10:
+		return distribution.P10, true
+	case 20:
+		return distribution.P20, true
+	case 30:
+		return distribution.P30, true
+	case 40:
+		return distribution.P40, true
+	case 50:
+		return distribution.P50, true
+	case 60:
+		return distribution.P60, true
+	case 70:
+		return distribution.P70, true
+	case 80:
+		return distribution.P80, true
+	case 90:
+		return distribution.P90, true
+	case 95:
+		return distribution.P95, true
+	case 99:
+		return distribution.P99, true
+	default:
+		return 0, false
+	}
+}
+
+func invalidParamsf(format string, args ...any) *jrpc2.Error {
+	return &jrpc2.Error{
+		Code:    jrpc2.InvalidParams,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// NewGetFeeBumpHandler returns a handler recommending the fee of a fee-bump transaction
+// (based on the recent fee stats) and optionally building it
+func NewGetFeeBumpHandler(
+	logger *log.Entry,
+	reader db.TransactionReader,
+	windows *feewindow.FeeWindows,
+	submitted *SubmittedTransactions,
+	passphrase string,
+) jrpc2.Handler {
+	handler := feeBumpHandler{
+		logger:     logger,
+		reader:     reader,
+		windows:    windows,
+		submitted:  submitted,
+		passphrase: passphrase,
+	}
+	return NewHandler(handler.getFeeBump)
+}
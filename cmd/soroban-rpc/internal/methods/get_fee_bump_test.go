@@ -0,0 +1,166 @@
+package methods
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/feewindow"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+)
+
+func feeBumpTestEnvelope(t *testing.T, fee uint32, resourceFee int64, operationCount int) (xdr.TransactionEnvelope, string) {
+	source := xdr.MustMuxedAddress(keypair.MustRandom().Address())
+	tx := xdr.Transaction{
+		SourceAccount: source,
+		Fee:           xdr.Uint32(fee),
+		SeqNum:        1,
+	}
+	for range operationCount {
+		tx.Operations = append(tx.Operations, xdr.Operation{
+			Body: xdr.OperationBody{
+				Type: xdr.OperationTypeBumpSequence,
+				BumpSequenceOp: &xdr.BumpSequenceOp{
+					BumpTo: 2,
+				},
+			},
+		})
+	}
+	if resourceFee > 0 {
+		tx.Ext = xdr.TransactionExt{
+			V:           1,
+			SorobanData: &xdr.SorobanTransactionData{ResourceFee: xdr.Int64(resourceFee)},
+		}
+	}
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1:   &xdr.TransactionV1Envelope{Tx: tx},
+	}
+	encoded, err := xdr.MarshalBase64(envelope)
+	require.NoError(t, err)
+	return envelope, encoded
+}
+
+func newTestFeeBumpHandler(t *testing.T) (feeBumpHandler, *SubmittedTransactions) {
+	windows := feewindow.NewFeeWindows(10, 10, "passphrase")
+	require.NoError(t, windows.ClassicFeeWindow.AppendLedgerFees(ledgerbucketwindow.LedgerBucket[[]uint64]{
+		LedgerSeq:     1,
+		BucketContent: []uint64{100, 100, 100, 100, 200, 200, 200, 300, 300, 1000},
+	}))
+	require.NoError(t, windows.SorobanInclusionFeeWindow.AppendLedgerFees(ledgerbucketwindow.LedgerBucket[[]uint64]{
+		LedgerSeq:     1,
+		BucketContent: []uint64{100, 5000},
+	}))
+	submitted := NewSubmittedTransactions()
+	return feeBumpHandler{
+		logger:     log.DefaultLogger,
+		reader:     db.NewMockTransactionStore("passphrase"),
+		windows:    windows,
+		submitted:  submitted,
+		passphrase: "passphrase",
+	}, submitted
+}
+
+func TestGetFeeBump(t *testing.T) {
+	handler, _ := newTestFeeBumpHandler(t)
+	envelope, encoded := feeBumpTestEnvelope(t, 200, 0, 2)
+	hash, err := network.HashTransactionInEnvelope(envelope, "passphrase")
+	require.NoError(t, err)
+
+	response, err := handler.getFeeBump(context.Background(), GetFeeBumpRequest{Transaction: encoded})
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(hash[:]), response.Hash)
+	assert.Equal(t, int64(100), response.InnerInclusionFee)
+	// p90 of the classic fees
+	assert.Equal(t, int64(300), response.InclusionFee)
+	assert.Equal(t, int64(900), response.Fee)
+	assert.Equal(t, int64(3000), response.ReplacementFee)
+	assert.Empty(t, response.FeeBumpTransactionXDR)
+
+	response, err = handler.getFeeBump(context.Background(), GetFeeBumpRequest{Transaction: encoded, Percentile: 99})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), response.InclusionFee)
+	assert.Equal(t, int64(3000), response.Fee)
+
+	// the transaction already offers more than the recent fees
+	_, encoded = feeBumpTestEnvelope(t, 4000, 0, 2)
+	response, err = handler.getFeeBump(context.Background(), GetFeeBumpRequest{Transaction: encoded})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2000), response.InclusionFee)
+	assert.Equal(t, int64(6000), response.Fee)
+}
+
+func TestGetFeeBumpSoroban(t *testing.T) {
+	handler, _ := newTestFeeBumpHandler(t)
+	_, encoded := feeBumpTestEnvelope(t, 10100, 10000, 1)
+
+	response, err := handler.getFeeBump(context.Background(), GetFeeBumpRequest{Transaction: encoded})
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), response.InnerInclusionFee)
+	assert.Equal(t, int64(5000), response.InclusionFee)
+	assert.Equal(t, int64(10000), response.ResourceFee)
+	assert.Equal(t, int64(20000), response.Fee)
+}
+
+func TestGetFeeBumpEnvelope(t *testing.T) {
+	handler, submitted := newTestFeeBumpHandler(t)
+	envelope, encoded := feeBumpTestEnvelope(t, 200, 0, 2)
+	hash, err := network.HashTransactionInEnvelope(envelope, "passphrase")
+	require.NoError(t, err)
+	submitted.add(hex.EncodeToString(hash[:]), encoded)
+	feeSource := keypair.MustRandom().Address()
+
+	response, err := handler.getFeeBump(context.Background(), GetFeeBumpRequest{
+		Hash:      hex.EncodeToString(hash[:]),
+		FeeSource: feeSource,
+	})
+	require.NoError(t, err)
+	var feeBump xdr.TransactionEnvelope
+	require.NoError(t, xdr.SafeUnmarshalBase64(response.FeeBumpTransactionXDR, &feeBump))
+	require.Equal(t, xdr.EnvelopeTypeEnvelopeTypeTxFeeBump, feeBump.Type)
+	assert.Equal(t, feeSource, feeBump.FeeBumpAccount().ToAccountId().Address())
+	assert.Equal(t, int64(900), feeBump.FeeBumpFee())
+	assert.Equal(t, *envelope.V1, feeBump.FeeBump.Tx.InnerTx.MustV1())
+
+	// the fee-bump can be bumped again
+	response, err = handler.getFeeBump(context.Background(), GetFeeBumpRequest{
+		Transaction: response.FeeBumpTransactionXDR,
+		Percentile:  99,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(hash[:]), response.Hash)
+	assert.Equal(t, int64(300), response.InnerInclusionFee)
+	assert.Equal(t, int64(3000), response.Fee)
+}
+
+func TestGetFeeBumpErrors(t *testing.T) {
+	handler, _ := newTestFeeBumpHandler(t)
+	_, encoded := feeBumpTestEnvelope(t, 200, 0, 2)
+	_, noOps := feeBumpTestEnvelope(t, 200, 0, 0)
+	hash := "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+
+	for _, testCase := range []struct {
+		request     GetFeeBumpRequest
+		expectedErr string
+	}{
+		{GetFeeBumpRequest{}, "either hash or transaction must be provided"},
+		{GetFeeBumpRequest{Hash: hash, Transaction: encoded}, "hash and transaction cannot be provided at the same time"},
+		{GetFeeBumpRequest{Hash: hash}, "wasn't submitted through this node"},
+		{GetFeeBumpRequest{Transaction: "invalid"}, "invalid_xdr"},
+		{GetFeeBumpRequest{Transaction: noOps}, "the transaction has no operations"},
+		{GetFeeBumpRequest{Transaction: encoded, Percentile: 42}, "unsupported percentile (42)"},
+		{GetFeeBumpRequest{Transaction: encoded, FeeSource: "invalid"}, "invalid fee source"},
+	} {
+		_, err := handler.getFeeBump(context.Background(), testCase.request)
+		assert.ErrorContains(t, err, testCase.expectedErr, testCase.request)
+	}
+}
@@ -54,7 +54,7 @@ func convertFeeDistribution(distribution feewindow.FeeDistribution) FeeDistribut
 type GetFeeStatsResult struct {
 	SorobanInclusionFee FeeDistribution `json:"sorobanInclusionFee"`
 	InclusionFee        FeeDistribution `json:"inclusionFee"`
-	LatestLedger        uint32          `json:"latestLedger"`
+	LedgerRangeResponse
 }
 
 // NewGetFeeStatsHandler returns a handler obtaining fee statistics
@@ -69,7 +69,7 @@ func NewGetFeeStatsHandler(windows *feewindow.FeeWindows, reader db.TransactionR
 		result := GetFeeStatsResult{
 			SorobanInclusionFee: convertFeeDistribution(windows.SorobanInclusionFeeWindow.GetFeeDistribution()),
 			InclusionFee:        convertFeeDistribution(windows.ClassicFeeWindow.GetFeeDistribution()),
-			LatestLedger:        ledgerInfo.LastLedger.Sequence,
+			LedgerRangeResponse: NewLedgerRangeResponse(ledgerInfo),
 		}
 		return result, nil
 	})
@@ -5,9 +5,16 @@ import (
 
 	"github.com/creachadair/jrpc2"
 
+	"github.com/stellar/go/xdr"
+
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
 )
 
+type GetLatestLedgerRequest struct {
+	// IncludeHeader adds the details of the ledger header to the response.
+	IncludeHeader bool `json:"includeHeader,omitempty"`
+}
+
 type GetLatestLedgerResponse struct {
 	// Hash of the latest ledger as a hex-encoded string
 	Hash string `json:"id"`
@@ -15,11 +22,25 @@ type GetLatestLedgerResponse struct {
 	ProtocolVersion uint32 `json:"protocolVersion"`
 	// Sequence number of the latest ledger.
 	Sequence uint32 `json:"sequence"`
+	LedgerRangeResponse
+
+	// The fields below are only present if IncludeHeader is set in the request.
+
+	// CloseTime is the unix timestamp of when the ledger was closed.
+	CloseTime int64 `json:"closeTime,string,omitempty"`
+	// BaseFee is the base fee (in stroops) of the ledger.
+	BaseFee uint32 `json:"baseFee,omitempty"`
+	// BaseReserve is the base reserve (in stroops) of the ledger.
+	BaseReserve uint32 `json:"baseReserve,omitempty"`
+	// TotalCoins is the total number of stroops in existence.
+	TotalCoins int64 `json:"totalCoins,string,omitempty"`
+	// HeaderXDR is the LedgerHeader XDR value.
+	HeaderXDR string `json:"headerXdr,omitempty"`
 }
 
 // NewGetLatestLedgerHandler returns a JSON RPC handler to retrieve the latest ledger entry from Stellar core.
 func NewGetLatestLedgerHandler(ledgerEntryReader db.LedgerEntryReader, ledgerReader db.LedgerReader) jrpc2.Handler {
-	return NewHandler(func(ctx context.Context) (GetLatestLedgerResponse, error) {
+	return NewHandler(func(ctx context.Context, request GetLatestLedgerRequest) (GetLatestLedgerResponse, error) {
 		tx, err := ledgerEntryReader.NewTx(ctx)
 		if err != nil {
 			return GetLatestLedgerResponse{}, &jrpc2.Error{
@@ -51,6 +72,24 @@ func NewGetLatestLedgerHandler(ledgerEntryReader db.LedgerEntryReader, ledgerRea
 			Hash:            latestLedger.LedgerHash().HexString(),
 			ProtocolVersion: latestLedger.ProtocolVersion(),
 			Sequence:        latestSequence,
+			LedgerRangeResponse: LedgerRangeResponse{
+				LatestLedger:          latestSequence,
+				LatestLedgerCloseTime: latestLedger.LedgerCloseTime(),
+			},
+		}
+		if request.IncludeHeader {
+			header := latestLedger.LedgerHeaderHistoryEntry().Header
+			response.CloseTime = int64(header.ScpValue.CloseTime)
+			response.BaseFee = uint32(header.BaseFee)
+			response.BaseReserve = uint32(header.BaseReserve)
+			response.TotalCoins = int64(header.TotalCoins)
+			response.HeaderXDR, err = xdr.MarshalBase64(header)
+			if err != nil {
+				return GetLatestLedgerResponse{}, &jrpc2.Error{
+					Code:    jrpc2.InternalError,
+					Message: "could not encode latest ledger header",
+				}
+			}
 		}
 		return response, nil
 	})
@@ -6,10 +6,12 @@ import (
 
 	"github.com/creachadair/jrpc2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/stellar/go/xdr"
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
 )
 
 const (
@@ -44,6 +46,14 @@ func (entryReaderTx ConstantLedgerEntryReaderTx) GetLedgerEntries(keys ...xdr.Le
 	return nil, nil
 }
 
+func (entryReaderTx ConstantLedgerEntryReaderTx) GetContractDataEntries(xdr.ScAddress, *xdr.LedgerKey, uint) ([]db.LedgerKeyAndEntry, error) {
+	return nil, nil
+}
+
+func (entryReaderTx ConstantLedgerEntryReaderTx) GetArchivedLedgerEntries(...xdr.LedgerKey) ([]db.LedgerKeyAndEntry, error) {
+	return nil, nil
+}
+
 func (entryReaderTx ConstantLedgerEntryReaderTx) Done() error {
 	return nil
 }
@@ -56,6 +66,17 @@ func (ledgerReader *ConstantLedgerReader) StreamAllLedgers(ctx context.Context,
 	return nil
 }
 
+func (ledgerReader *ConstantLedgerReader) StreamLedgerRange(ctx context.Context, start, end uint32, f db.StreamLedgerFn) error {
+	return nil
+}
+
+func (ledgerReader *ConstantLedgerReader) GetLedgerRange(ctx context.Context) (ledgerbucketwindow.LedgerRange, error) {
+	return ledgerbucketwindow.LedgerRange{
+		FirstLedger: ledgerbucketwindow.LedgerInfo{Sequence: 1, CloseTime: 5},
+		LastLedger:  ledgerbucketwindow.LedgerInfo{Sequence: expectedLatestLedgerSequence, CloseTime: 4800},
+	}, nil
+}
+
 func createLedger(ledgerSequence uint32, protocolVersion uint32, hash byte) xdr.LedgerCloseMeta {
 	return xdr.LedgerCloseMeta{
 		V: 1,
@@ -65,6 +86,10 @@ func createLedger(ledgerSequence uint32, protocolVersion uint32, hash byte) xdr.
 				Header: xdr.LedgerHeader{
 					LedgerSeq:     xdr.Uint32(ledgerSequence),
 					LedgerVersion: xdr.Uint32(protocolVersion),
+					ScpValue:      xdr.StellarValue{CloseTime: 4800},
+					BaseFee:       100,
+					BaseReserve:   5000000,
+					TotalCoins:    1000000000000000000,
 				},
 			},
 		},
@@ -82,4 +107,28 @@ func TestGetLatestLedger(t *testing.T) {
 
 	assert.Equal(t, expectedLatestLedgerProtocolVersion, latestLedgerResp.ProtocolVersion)
 	assert.Equal(t, expectedLatestLedgerSequence, latestLedgerResp.Sequence)
+	assert.Zero(t, latestLedgerResp.CloseTime)
+	assert.Empty(t, latestLedgerResp.HeaderXDR)
+}
+
+func TestGetLatestLedgerWithHeader(t *testing.T) {
+	getLatestLedgerHandler := NewGetLatestLedgerHandler(&ConstantLedgerEntryReader{}, &ConstantLedgerReader{})
+	requests, err := jrpc2.ParseRequests([]byte(
+		`{"jsonrpc": "2.0", "id": 1, "method": "getLatestLedger", "params": {"includeHeader": true}}`,
+	))
+	require.NoError(t, err)
+	latestLedgerRespI, err := getLatestLedgerHandler(context.Background(), requests[0].ToRequest())
+	require.NoError(t, err)
+	latestLedgerResp := latestLedgerRespI.(GetLatestLedgerResponse)
+
+	assert.Equal(t, expectedLatestLedgerSequence, latestLedgerResp.Sequence)
+	assert.Equal(t, int64(4800), latestLedgerResp.CloseTime)
+	assert.Equal(t, uint32(100), latestLedgerResp.BaseFee)
+	assert.Equal(t, uint32(5000000), latestLedgerResp.BaseReserve)
+	assert.Equal(t, int64(1000000000000000000), latestLedgerResp.TotalCoins)
+
+	var header xdr.LedgerHeader
+	require.NoError(t, xdr.SafeUnmarshalBase64(latestLedgerResp.HeaderXDR, &header))
+	assert.Equal(t, createLedger(expectedLatestLedgerSequence, expectedLatestLedgerProtocolVersion,
+		expectedLatestLedgerHashBytes).LedgerHeaderHistoryEntry().Header, header)
 }
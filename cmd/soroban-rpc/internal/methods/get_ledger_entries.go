@@ -15,8 +15,23 @@ import (
 //nolint:gochecknoglobals
 var ErrLedgerTTLEntriesCannotBeQueriedDirectly = "ledger ttl entries cannot be queried directly"
 
+// The archival statuses of the ledger entries
+const (
+	// LedgerEntryStatusLive is the status of the entries which can be accessed by transactions
+	LedgerEntryStatusLive = "live"
+	// LedgerEntryStatusArchived is the status of the entries whose TTL has expired. Persistent entries must be
+	// restored before being accessed, while temporary entries are about to be deleted.
+	LedgerEntryStatusArchived = "archived"
+	// LedgerEntryStatusEvicted is the status of the archived entries evicted into the hot archive, which must be restored
+	// before being accessed
+	LedgerEntryStatusEvicted = "evicted"
+)
+
 type GetLedgerEntriesRequest struct {
 	Keys []string `json:"keys"`
+	// IncludeArchived (optional) includes the entries evicted into the hot archive and
+	// the archival status of all the entries
+	IncludeArchived bool `json:"includeArchived,omitempty"`
 }
 
 type LedgerEntryResult struct {
@@ -28,24 +43,56 @@ type LedgerEntryResult struct {
 	LastModifiedLedger uint32 `json:"lastModifiedLedgerSeq"`
 	// The ledger sequence until the entry is live, available for entries that have associated ttl ledger entries.
 	LiveUntilLedgerSeq *uint32 `json:"liveUntilLedgerSeq,omitempty"`
+	// ArchivalStatus is one of LedgerEntryStatusLive, LedgerEntryStatusArchived or LedgerEntryStatusEvicted,
+	// only present if IncludeArchived was requested.
+	ArchivalStatus string `json:"archivalStatus,omitempty"`
+}
+
+// ledgerEntryArchivalStatus tells whether a ledger entry found in the live state is archived
+func ledgerEntryArchivalStatus(entry db.LedgerKeyAndEntry, latestLedger uint32) string {
+	if entry.LiveUntilLedgerSeq != nil && *entry.LiveUntilLedgerSeq < latestLedger {
+		return LedgerEntryStatusArchived
+	}
+	return LedgerEntryStatusLive
+}
+
+// getArchivedLedgerEntries returns the entries of the keys missing from the live state which were evicted
+func getArchivedLedgerEntries(tx db.LedgerEntryReadTx, keys []xdr.LedgerKey, liveEntries []db.LedgerKeyAndEntry) ([]db.LedgerKeyAndEntry, error) {
+	found := make(map[string]bool, len(liveEntries))
+	for _, entry := range liveEntries {
+		keyXDR, err := xdr.MarshalBase64(entry.Key)
+		if err != nil {
+			return nil, err
+		}
+		found[keyXDR] = true
+	}
+	var missingKeys []xdr.LedgerKey
+	for _, key := range keys {
+		keyXDR, err := xdr.MarshalBase64(key)
+		if err != nil {
+			return nil, err
+		}
+		if !found[keyXDR] && (key.Type == xdr.LedgerEntryTypeContractData || key.Type == xdr.LedgerEntryTypeContractCode) {
+			missingKeys = append(missingKeys, key)
+		}
+	}
+	return tx.GetArchivedLedgerEntries(missingKeys...)
 }
 
 type GetLedgerEntriesResponse struct {
 	// All found ledger entries.
 	Entries []LedgerEntryResult `json:"entries"`
-	// Sequence number of the latest ledger at time of request.
-	LatestLedger uint32 `json:"latestLedger"`
+	// LatestLedger is the sequence number of the latest ledger at time of request.
+	LedgerRangeResponse
 }
 
-const getLedgerEntriesMaxKeys = 200
-
 // NewGetLedgerEntriesHandler returns a JSON RPC handler to retrieve the specified ledger entries from Stellar Core.
-func NewGetLedgerEntriesHandler(logger *log.Entry, ledgerEntryReader db.LedgerEntryReader) jrpc2.Handler {
+func NewGetLedgerEntriesHandler(logger *log.Entry, ledgerEntryReader db.LedgerEntryReader, limits RequestLimits) jrpc2.Handler {
 	return NewHandler(func(ctx context.Context, request GetLedgerEntriesRequest) (GetLedgerEntriesResponse, error) {
-		if len(request.Keys) > getLedgerEntriesMaxKeys {
+		if err := checkLimit("key count", len(request.Keys), limits.MaxLedgerEntriesKeys); err != nil {
 			return GetLedgerEntriesResponse{}, &jrpc2.Error{
 				Code:    jrpc2.InvalidParams,
-				Message: fmt.Sprintf("key count (%d) exceeds maximum supported (%d)", len(request.Keys), getLedgerEntriesMaxKeys),
+				Message: err.Error(),
 			}
 		}
 		var ledgerKeys []xdr.LedgerKey
@@ -100,7 +147,27 @@ func NewGetLedgerEntriesHandler(logger *log.Entry, ledgerEntryReader db.LedgerEn
 			}
 		}
 
-		for _, ledgerKeyAndEntry := range ledgerKeysAndEntries {
+		archivalStatuses := make([]string, len(ledgerKeysAndEntries))
+		if request.IncludeArchived {
+			for i, ledgerKeyAndEntry := range ledgerKeysAndEntries {
+				archivalStatuses[i] = ledgerEntryArchivalStatus(ledgerKeyAndEntry, latestLedger)
+			}
+			archivedKeysAndEntries, err := getArchivedLedgerEntries(tx, ledgerKeys, ledgerKeysAndEntries)
+			if err != nil {
+				logger.WithError(err).WithField("request", request).
+					Info("could not obtain archived ledger entries from storage")
+				return GetLedgerEntriesResponse{}, &jrpc2.Error{
+					Code:    jrpc2.InternalError,
+					Message: "could not obtain archived ledger entries from storage",
+				}
+			}
+			for _, archivedKeyAndEntry := range archivedKeysAndEntries {
+				ledgerKeysAndEntries = append(ledgerKeysAndEntries, archivedKeyAndEntry)
+				archivalStatuses = append(archivalStatuses, LedgerEntryStatusEvicted)
+			}
+		}
+
+		for i, ledgerKeyAndEntry := range ledgerKeysAndEntries {
 			keyXDR, err := xdr.MarshalBase64(ledgerKeyAndEntry.Key)
 			if err != nil {
 				logger.WithError(err).WithField("request", request).
@@ -121,17 +188,22 @@ func NewGetLedgerEntriesHandler(logger *log.Entry, ledgerEntryReader db.LedgerEn
 				}
 			}
 
-			ledgerEntryResults = append(ledgerEntryResults, LedgerEntryResult{
+			result := LedgerEntryResult{
 				Key:                keyXDR,
 				XDR:                entryXDR,
 				LastModifiedLedger: uint32(ledgerKeyAndEntry.Entry.LastModifiedLedgerSeq),
 				LiveUntilLedgerSeq: ledgerKeyAndEntry.LiveUntilLedgerSeq,
-			})
+				ArchivalStatus:     archivalStatuses[i],
+			}
+			if err := chargeResponseSize(ctx, result.responseSize()); err != nil {
+				return GetLedgerEntriesResponse{}, err
+			}
+			ledgerEntryResults = append(ledgerEntryResults, result)
 		}
 
 		response := GetLedgerEntriesResponse{
-			Entries:      ledgerEntryResults,
-			LatestLedger: latestLedger,
+			Entries:             ledgerEntryResults,
+			LedgerRangeResponse: LedgerRangeResponse{LatestLedger: latestLedger},
 		}
 		return response, nil
 	})
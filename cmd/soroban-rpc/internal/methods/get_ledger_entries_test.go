@@ -0,0 +1,120 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+type archivingLedgerEntryReader struct {
+	ConstantLedgerEntryReader
+	tx archivingLedgerEntryReadTx
+}
+
+func (r *archivingLedgerEntryReader) NewTx(ctx context.Context) (db.LedgerEntryReadTx, error) {
+	return r.tx, nil
+}
+
+type archivingLedgerEntryReadTx struct {
+	ConstantLedgerEntryReaderTx
+	live     []db.LedgerKeyAndEntry
+	archived []db.LedgerKeyAndEntry
+}
+
+func findLedgerEntries(entries []db.LedgerKeyAndEntry, keys []xdr.LedgerKey) []db.LedgerKeyAndEntry {
+	var result []db.LedgerKeyAndEntry
+	for _, key := range keys {
+		for _, entry := range entries {
+			if entry.Key.Equals(key) {
+				result = append(result, entry)
+			}
+		}
+	}
+	return result
+}
+
+func (tx archivingLedgerEntryReadTx) GetLedgerEntries(keys ...xdr.LedgerKey) ([]db.LedgerKeyAndEntry, error) {
+	return findLedgerEntries(tx.live, keys), nil
+}
+
+func (tx archivingLedgerEntryReadTx) GetArchivedLedgerEntries(keys ...xdr.LedgerKey) ([]db.LedgerKeyAndEntry, error) {
+	return findLedgerEntries(tx.archived, keys), nil
+}
+
+func contractDataKeyAndEntry(t *testing.T, value uint32, liveUntilLedgerSeq *uint32) db.LedgerKeyAndEntry {
+	contractID := xdr.Hash{1}
+	scValue := xdr.Uint32(value)
+	entry := xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeContractData,
+			ContractData: &xdr.ContractDataEntry{
+				Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+				Key:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &scValue},
+				Durability: xdr.ContractDataDurabilityPersistent,
+				Val:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &scValue},
+			},
+		},
+	}
+	key, err := entry.LedgerKey()
+	require.NoError(t, err)
+	return db.LedgerKeyAndEntry{Key: key, Entry: entry, LiveUntilLedgerSeq: liveUntilLedgerSeq}
+}
+
+func getLedgerEntries(t *testing.T, handler jrpc2.Handler, request GetLedgerEntriesRequest) GetLedgerEntriesResponse {
+	params, err := json.Marshal(request)
+	require.NoError(t, err)
+	requests, err := jrpc2.ParseRequests([]byte(
+		`{"jsonrpc": "2.0", "id": 1, "method": "getLedgerEntries", "params": ` + string(params) + `}`))
+	require.NoError(t, err)
+	result, err := handler(context.Background(), requests[0].ToRequest())
+	require.NoError(t, err)
+	return result.(GetLedgerEntriesResponse)
+}
+
+func TestGetLedgerEntriesIncludeArchived(t *testing.T) {
+	live := uint32(expectedLatestLedgerSequence + 10)
+	expired := uint32(expectedLatestLedgerSequence - 10)
+	liveEntry := contractDataKeyAndEntry(t, 1, &live)
+	expiredEntry := contractDataKeyAndEntry(t, 2, &expired)
+	evictedEntry := contractDataKeyAndEntry(t, 3, nil)
+	missingEntry := contractDataKeyAndEntry(t, 4, nil)
+	reader := &archivingLedgerEntryReader{tx: archivingLedgerEntryReadTx{
+		live:     []db.LedgerKeyAndEntry{liveEntry, expiredEntry},
+		archived: []db.LedgerKeyAndEntry{evictedEntry},
+	}}
+	handler := NewGetLedgerEntriesHandler(log.DefaultLogger, reader, RequestLimits{MaxLedgerEntriesKeys: 10})
+
+	var keys []string
+	for _, entry := range []db.LedgerKeyAndEntry{liveEntry, expiredEntry, evictedEntry, missingEntry} {
+		keyXDR, err := xdr.MarshalBase64(entry.Key)
+		require.NoError(t, err)
+		keys = append(keys, keyXDR)
+	}
+
+	request := GetLedgerEntriesRequest{Keys: keys}
+	response := getLedgerEntries(t, handler, request)
+	require.Len(t, response.Entries, 2)
+	for _, entry := range response.Entries {
+		assert.Empty(t, entry.ArchivalStatus)
+	}
+
+	request.IncludeArchived = true
+	response = getLedgerEntries(t, handler, request)
+	require.Len(t, response.Entries, 3)
+	assert.Equal(t, keys[0], response.Entries[0].Key)
+	assert.Equal(t, LedgerEntryStatusLive, response.Entries[0].ArchivalStatus)
+	assert.Equal(t, keys[1], response.Entries[1].Key)
+	assert.Equal(t, LedgerEntryStatusArchived, response.Entries[1].ArchivalStatus)
+	assert.Equal(t, keys[2], response.Entries[2].Key)
+	assert.Equal(t, LedgerEntryStatusEvicted, response.Entries[2].ArchivalStatus)
+	assert.Nil(t, response.Entries[2].LiveUntilLedgerSeq)
+}
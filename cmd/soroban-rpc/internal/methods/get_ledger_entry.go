@@ -23,7 +23,7 @@ type GetLedgerEntryRequest struct {
 type GetLedgerEntryResponse struct {
 	XDR                string `json:"xdr"`
 	LastModifiedLedger uint32 `json:"lastModifiedLedgerSeq"`
-	LatestLedger       uint32 `json:"latestLedger"`
+	LedgerRangeResponse
 	// The ledger sequence until the entry is live, available for entries that have associated ttl ledger entries.
 	// TODO: it should had been `liveUntilLedgerSeq` :(
 	LiveUntilLedgerSeq *uint32 `json:"LiveUntilLedgerSeq,omitempty"` //nolint:tagliatelle
@@ -88,9 +88,9 @@ func NewGetLedgerEntryHandler(logger *log.Entry, ledgerEntryReader db.LedgerEntr
 		}
 
 		response := GetLedgerEntryResponse{
-			LastModifiedLedger: uint32(ledgerEntry.LastModifiedLedgerSeq),
-			LatestLedger:       latestLedger,
-			LiveUntilLedgerSeq: liveUntilLedgerSeq,
+			LastModifiedLedger:  uint32(ledgerEntry.LastModifiedLedgerSeq),
+			LedgerRangeResponse: LedgerRangeResponse{LatestLedger: latestLedger},
+			LiveUntilLedgerSeq:  liveUntilLedgerSeq,
 		}
 		if response.XDR, err = xdr.MarshalBase64(ledgerEntry.Data); err != nil {
 			logger.WithError(err).WithField("request", request).
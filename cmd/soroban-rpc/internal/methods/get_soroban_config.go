@@ -0,0 +1,242 @@
+package methods
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+type GetSorobanConfigRequest struct{}
+
+// SorobanComputeConfig are the CPU and memory limits and fees
+type SorobanComputeConfig struct {
+	LedgerMaxInstructions           int64  `json:"ledgerMaxInstructions,string"`
+	TxMaxInstructions               int64  `json:"txMaxInstructions,string"`
+	FeeRatePerInstructionsIncrement int64  `json:"feeRatePerInstructionsIncrement,string"`
+	TxMemoryLimit                   uint32 `json:"txMemoryLimit"`
+}
+
+// SorobanLedgerCostConfig are the ledger access limits and fees
+type SorobanLedgerCostConfig struct {
+	LedgerMaxReadLedgerEntries     uint32 `json:"ledgerMaxReadLedgerEntries"`
+	LedgerMaxReadBytes             uint32 `json:"ledgerMaxReadBytes"`
+	LedgerMaxWriteLedgerEntries    uint32 `json:"ledgerMaxWriteLedgerEntries"`
+	LedgerMaxWriteBytes            uint32 `json:"ledgerMaxWriteBytes"`
+	TxMaxReadLedgerEntries         uint32 `json:"txMaxReadLedgerEntries"`
+	TxMaxReadBytes                 uint32 `json:"txMaxReadBytes"`
+	TxMaxWriteLedgerEntries        uint32 `json:"txMaxWriteLedgerEntries"`
+	TxMaxWriteBytes                uint32 `json:"txMaxWriteBytes"`
+	FeeReadLedgerEntry             int64  `json:"feeReadLedgerEntry,string"`
+	FeeWriteLedgerEntry            int64  `json:"feeWriteLedgerEntry,string"`
+	FeeRead1Kb                     int64  `json:"feeRead1Kb,string"`
+	BucketListTargetSizeBytes      int64  `json:"bucketListTargetSizeBytes,string"`
+	WriteFee1KbBucketListLow       int64  `json:"writeFee1KbBucketListLow,string"`
+	WriteFee1KbBucketListHigh      int64  `json:"writeFee1KbBucketListHigh,string"`
+	BucketListWriteFeeGrowthFactor uint32 `json:"bucketListWriteFeeGrowthFactor"`
+}
+
+// SorobanEventsConfig are the contract events limits and fees
+type SorobanEventsConfig struct {
+	TxMaxContractEventsSizeBytes uint32 `json:"txMaxContractEventsSizeBytes"`
+	FeeContractEvents1Kb         int64  `json:"feeContractEvents1Kb,string"`
+}
+
+// SorobanBandwidthConfig are the transaction size limits and fees
+type SorobanBandwidthConfig struct {
+	LedgerMaxTxsSizeBytes uint32 `json:"ledgerMaxTxsSizeBytes"`
+	TxMaxSizeBytes        uint32 `json:"txMaxSizeBytes"`
+	FeeTxSize1Kb          int64  `json:"feeTxSize1Kb,string"`
+}
+
+// SorobanStateArchivalConfig are the TTL and rent settings
+type SorobanStateArchivalConfig struct {
+	MaxEntryTTL                    uint32 `json:"maxEntryTtl"`
+	MinTemporaryTTL                uint32 `json:"minTemporaryTtl"`
+	MinPersistentTTL               uint32 `json:"minPersistentTtl"`
+	PersistentRentRateDenominator  int64  `json:"persistentRentRateDenominator,string"`
+	TempRentRateDenominator        int64  `json:"tempRentRateDenominator,string"`
+	MaxEntriesToArchive            uint32 `json:"maxEntriesToArchive"`
+	BucketListSizeWindowSampleSize uint32 `json:"bucketListSizeWindowSampleSize"`
+	BucketListWindowSamplePeriod   uint32 `json:"bucketListWindowSamplePeriod"`
+	EvictionScanSize               uint32 `json:"evictionScanSize"`
+	StartingEvictionScanLevel      uint32 `json:"startingEvictionScanLevel"`
+}
+
+type GetSorobanConfigResponse struct {
+	// ContractMaxSizeBytes is the maximum size of the contract code
+	ContractMaxSizeBytes       uint32                     `json:"contractMaxSizeBytes"`
+	ContractDataKeySizeBytes   uint32                     `json:"contractDataKeySizeBytes"`
+	ContractDataEntrySizeBytes uint32                     `json:"contractDataEntrySizeBytes"`
+	Compute                    SorobanComputeConfig       `json:"compute"`
+	LedgerCost                 SorobanLedgerCostConfig    `json:"ledgerCost"`
+	FeeHistorical1Kb           int64                      `json:"feeHistorical1Kb,string"`
+	Events                     SorobanEventsConfig        `json:"events"`
+	Bandwidth                  SorobanBandwidthConfig     `json:"bandwidth"`
+	StateArchival              SorobanStateArchivalConfig `json:"stateArchival"`
+	// LedgerMaxTxCount is the maximum number of Soroban transactions per ledger
+	LedgerMaxTxCount uint32 `json:"ledgerMaxTxCount"`
+	// AverageBucketListSize is the average of the bucket list size window, which the write fees are based on
+	AverageBucketListSize uint64 `json:"averageBucketListSize,string"`
+	// the settings were read at the latest ledger of the envelope
+	LedgerRangeResponse
+}
+
+var sorobanConfigSettingIDs = []xdr.ConfigSettingId{
+	xdr.ConfigSettingIdConfigSettingContractMaxSizeBytes,
+	xdr.ConfigSettingIdConfigSettingContractComputeV0,
+	xdr.ConfigSettingIdConfigSettingContractLedgerCostV0,
+	xdr.ConfigSettingIdConfigSettingContractHistoricalDataV0,
+	xdr.ConfigSettingIdConfigSettingContractEventsV0,
+	xdr.ConfigSettingIdConfigSettingContractBandwidthV0,
+	xdr.ConfigSettingIdConfigSettingContractDataKeySizeBytes,
+	xdr.ConfigSettingIdConfigSettingContractDataEntrySizeBytes,
+	xdr.ConfigSettingIdConfigSettingStateArchival,
+	xdr.ConfigSettingIdConfigSettingContractExecutionLanes,
+	xdr.ConfigSettingIdConfigSettingBucketlistSizeWindow,
+}
+
+// setSorobanConfigSetting decodes a config setting entry into the response
+func (r *GetSorobanConfigResponse) setSorobanConfigSetting(setting xdr.ConfigSettingEntry) {
+	switch setting.ConfigSettingId {
+	case xdr.ConfigSettingIdConfigSettingContractMaxSizeBytes:
+		r.ContractMaxSizeBytes = uint32(*setting.ContractMaxSizeBytes)
+	case xdr.ConfigSettingIdConfigSettingContractComputeV0:
+		compute := setting.ContractCompute
+		r.Compute = SorobanComputeConfig{
+			LedgerMaxInstructions:           int64(compute.LedgerMaxInstructions),
+			TxMaxInstructions:               int64(compute.TxMaxInstructions),
+			FeeRatePerInstructionsIncrement: int64(compute.FeeRatePerInstructionsIncrement),
+			TxMemoryLimit:                   uint32(compute.TxMemoryLimit),
+		}
+	case xdr.ConfigSettingIdConfigSettingContractLedgerCostV0:
+		cost := setting.ContractLedgerCost
+		r.LedgerCost = SorobanLedgerCostConfig{
+			LedgerMaxReadLedgerEntries:     uint32(cost.LedgerMaxReadLedgerEntries),
+			LedgerMaxReadBytes:             uint32(cost.LedgerMaxReadBytes),
+			LedgerMaxWriteLedgerEntries:    uint32(cost.LedgerMaxWriteLedgerEntries),
+			LedgerMaxWriteBytes:            uint32(cost.LedgerMaxWriteBytes),
+			TxMaxReadLedgerEntries:         uint32(cost.TxMaxReadLedgerEntries),
+			TxMaxReadBytes:                 uint32(cost.TxMaxReadBytes),
+			TxMaxWriteLedgerEntries:        uint32(cost.TxMaxWriteLedgerEntries),
+			TxMaxWriteBytes:                uint32(cost.TxMaxWriteBytes),
+			FeeReadLedgerEntry:             int64(cost.FeeReadLedgerEntry),
+			FeeWriteLedgerEntry:            int64(cost.FeeWriteLedgerEntry),
+			FeeRead1Kb:                     int64(cost.FeeRead1Kb),
+			BucketListTargetSizeBytes:      int64(cost.BucketListTargetSizeBytes),
+			WriteFee1KbBucketListLow:       int64(cost.WriteFee1KbBucketListLow),
+			WriteFee1KbBucketListHigh:      int64(cost.WriteFee1KbBucketListHigh),
+			BucketListWriteFeeGrowthFactor: uint32(cost.BucketListWriteFeeGrowthFactor),
+		}
+	case xdr.ConfigSettingIdConfigSettingContractHistoricalDataV0:
+		r.FeeHistorical1Kb = int64(setting.ContractHistoricalData.FeeHistorical1Kb)
+	case xdr.ConfigSettingIdConfigSettingContractEventsV0:
+		r.Events = SorobanEventsConfig{
+			TxMaxContractEventsSizeBytes: uint32(setting.ContractEvents.TxMaxContractEventsSizeBytes),
+			FeeContractEvents1Kb:         int64(setting.ContractEvents.FeeContractEvents1Kb),
+		}
+	case xdr.ConfigSettingIdConfigSettingContractBandwidthV0:
+		r.Bandwidth = SorobanBandwidthConfig{
+			LedgerMaxTxsSizeBytes: uint32(setting.ContractBandwidth.LedgerMaxTxsSizeBytes),
+			TxMaxSizeBytes:        uint32(setting.ContractBandwidth.TxMaxSizeBytes),
+			FeeTxSize1Kb:          int64(setting.ContractBandwidth.FeeTxSize1Kb),
+		}
+	case xdr.ConfigSettingIdConfigSettingContractDataKeySizeBytes:
+		r.ContractDataKeySizeBytes = uint32(*setting.ContractDataKeySizeBytes)
+	case xdr.ConfigSettingIdConfigSettingContractDataEntrySizeBytes:
+		r.ContractDataEntrySizeBytes = uint32(*setting.ContractDataEntrySizeBytes)
+	case xdr.ConfigSettingIdConfigSettingStateArchival:
+		archival := setting.StateArchivalSettings
+		r.StateArchival = SorobanStateArchivalConfig{
+			MaxEntryTTL:                    uint32(archival.MaxEntryTtl),
+			MinTemporaryTTL:                uint32(archival.MinTemporaryTtl),
+			MinPersistentTTL:               uint32(archival.MinPersistentTtl),
+			PersistentRentRateDenominator:  int64(archival.PersistentRentRateDenominator),
+			TempRentRateDenominator:        int64(archival.TempRentRateDenominator),
+			MaxEntriesToArchive:            uint32(archival.MaxEntriesToArchive),
+			BucketListSizeWindowSampleSize: uint32(archival.BucketListSizeWindowSampleSize),
+			BucketListWindowSamplePeriod:   uint32(archival.BucketListWindowSamplePeriod),
+			EvictionScanSize:               uint32(archival.EvictionScanSize),
+			StartingEvictionScanLevel:      uint32(archival.StartingEvictionScanLevel),
+		}
+	case xdr.ConfigSettingIdConfigSettingContractExecutionLanes:
+		r.LedgerMaxTxCount = uint32(setting.ContractExecutionLanes.LedgerMaxTxCount)
+	case xdr.ConfigSettingIdConfigSettingBucketlistSizeWindow:
+		window := *setting.BucketListSizeWindow
+		if len(window) == 0 {
+			return
+		}
+		var sum uint64
+		for _, size := range window {
+			sum += uint64(size)
+		}
+		r.AverageBucketListSize = sum / uint64(len(window))
+	}
+}
+
+// NewGetSorobanConfigHandler returns a JSON RPC handler decoding the Soroban network settings from the ledger state.
+func NewGetSorobanConfigHandler(logger *log.Entry, ledgerEntryReader db.LedgerEntryReader) jrpc2.Handler {
+	return NewHandler(func(ctx context.Context, _ GetSorobanConfigRequest) (GetSorobanConfigResponse, error) {
+		tx, err := ledgerEntryReader.NewTx(ctx)
+		if err != nil {
+			return GetSorobanConfigResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: "could not create read transaction",
+			}
+		}
+		defer func() {
+			_ = tx.Done()
+		}()
+
+		latestLedger, err := tx.GetLatestLedgerSequence()
+		if err != nil {
+			return GetSorobanConfigResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: "could not get latest ledger",
+			}
+		}
+
+		keys := make([]xdr.LedgerKey, 0, len(sorobanConfigSettingIDs))
+		for _, id := range sorobanConfigSettingIDs {
+			keys = append(keys, xdr.LedgerKey{
+				Type:          xdr.LedgerEntryTypeConfigSetting,
+				ConfigSetting: &xdr.LedgerKeyConfigSetting{ConfigSettingId: id},
+			})
+		}
+		entries, err := tx.GetLedgerEntries(keys...)
+		if err != nil {
+			logger.WithError(err).Info("could not obtain the config setting entries from storage")
+			return GetSorobanConfigResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: "could not obtain the config setting entries from storage",
+			}
+		}
+
+		response := GetSorobanConfigResponse{
+			LedgerRangeResponse: LedgerRangeResponse{LatestLedger: latestLedger},
+		}
+		found := map[xdr.ConfigSettingId]bool{}
+		for _, entry := range entries {
+			setting, ok := entry.Entry.Data.GetConfigSetting()
+			if !ok {
+				continue
+			}
+			found[setting.ConfigSettingId] = true
+			response.setSorobanConfigSetting(setting)
+		}
+		for _, id := range sorobanConfigSettingIDs {
+			if !found[id] {
+				return GetSorobanConfigResponse{}, &jrpc2.Error{
+					Code:    jrpc2.InternalError,
+					Message: fmt.Sprintf("could not find the %s config setting", id),
+				}
+			}
+		}
+		return response, nil
+	})
+}
@@ -0,0 +1,157 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+type configSettingReader struct {
+	ConstantLedgerEntryReader
+	settings map[xdr.ConfigSettingId]xdr.ConfigSettingEntry
+}
+
+func (r *configSettingReader) NewTx(ctx context.Context) (db.LedgerEntryReadTx, error) {
+	return configSettingReadTx{settings: r.settings}, nil
+}
+
+type configSettingReadTx struct {
+	ConstantLedgerEntryReaderTx
+	settings map[xdr.ConfigSettingId]xdr.ConfigSettingEntry
+}
+
+func (tx configSettingReadTx) GetLedgerEntries(keys ...xdr.LedgerKey) ([]db.LedgerKeyAndEntry, error) {
+	var result []db.LedgerKeyAndEntry
+	for _, key := range keys {
+		setting, ok := tx.settings[key.ConfigSetting.ConfigSettingId]
+		if !ok {
+			continue
+		}
+		result = append(result, db.LedgerKeyAndEntry{
+			Key: key,
+			Entry: xdr.LedgerEntry{
+				Data: xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeConfigSetting, ConfigSetting: &setting},
+			},
+		})
+	}
+	return result, nil
+}
+
+func testConfigSettings() map[xdr.ConfigSettingId]xdr.ConfigSettingEntry {
+	maxSize := xdr.Uint32(65536)
+	keySize := xdr.Uint32(250)
+	entrySize := xdr.Uint32(65536)
+	window := []xdr.Uint64{100, 200, 300}
+	return map[xdr.ConfigSettingId]xdr.ConfigSettingEntry{
+		xdr.ConfigSettingIdConfigSettingContractMaxSizeBytes: {
+			ConfigSettingId:      xdr.ConfigSettingIdConfigSettingContractMaxSizeBytes,
+			ContractMaxSizeBytes: &maxSize,
+		},
+		xdr.ConfigSettingIdConfigSettingContractComputeV0: {
+			ConfigSettingId: xdr.ConfigSettingIdConfigSettingContractComputeV0,
+			ContractCompute: &xdr.ConfigSettingContractComputeV0{
+				LedgerMaxInstructions:           100_000_000,
+				TxMaxInstructions:               100_000_000,
+				FeeRatePerInstructionsIncrement: 25,
+				TxMemoryLimit:                   41943040,
+			},
+		},
+		xdr.ConfigSettingIdConfigSettingContractLedgerCostV0: {
+			ConfigSettingId: xdr.ConfigSettingIdConfigSettingContractLedgerCostV0,
+			ContractLedgerCost: &xdr.ConfigSettingContractLedgerCostV0{
+				TxMaxReadLedgerEntries: 40,
+				TxMaxReadBytes:         200_000,
+				FeeReadLedgerEntry:     6250,
+			},
+		},
+		xdr.ConfigSettingIdConfigSettingContractHistoricalDataV0: {
+			ConfigSettingId:        xdr.ConfigSettingIdConfigSettingContractHistoricalDataV0,
+			ContractHistoricalData: &xdr.ConfigSettingContractHistoricalDataV0{FeeHistorical1Kb: 16235},
+		},
+		xdr.ConfigSettingIdConfigSettingContractEventsV0: {
+			ConfigSettingId: xdr.ConfigSettingIdConfigSettingContractEventsV0,
+			ContractEvents:  &xdr.ConfigSettingContractEventsV0{TxMaxContractEventsSizeBytes: 8198, FeeContractEvents1Kb: 10000},
+		},
+		xdr.ConfigSettingIdConfigSettingContractBandwidthV0: {
+			ConfigSettingId:   xdr.ConfigSettingIdConfigSettingContractBandwidthV0,
+			ContractBandwidth: &xdr.ConfigSettingContractBandwidthV0{TxMaxSizeBytes: 70_000, FeeTxSize1Kb: 1624},
+		},
+		xdr.ConfigSettingIdConfigSettingContractDataKeySizeBytes: {
+			ConfigSettingId:          xdr.ConfigSettingIdConfigSettingContractDataKeySizeBytes,
+			ContractDataKeySizeBytes: &keySize,
+		},
+		xdr.ConfigSettingIdConfigSettingContractDataEntrySizeBytes: {
+			ConfigSettingId:            xdr.ConfigSettingIdConfigSettingContractDataEntrySizeBytes,
+			ContractDataEntrySizeBytes: &entrySize,
+		},
+		xdr.ConfigSettingIdConfigSettingStateArchival: {
+			ConfigSettingId:       xdr.ConfigSettingIdConfigSettingStateArchival,
+			StateArchivalSettings: &xdr.StateArchivalSettings{MaxEntryTtl: 3_110_400, MinPersistentTtl: 120_960},
+		},
+		xdr.ConfigSettingIdConfigSettingContractExecutionLanes: {
+			ConfigSettingId:        xdr.ConfigSettingIdConfigSettingContractExecutionLanes,
+			ContractExecutionLanes: &xdr.ConfigSettingContractExecutionLanesV0{LedgerMaxTxCount: 100},
+		},
+		xdr.ConfigSettingIdConfigSettingBucketlistSizeWindow: {
+			ConfigSettingId:      xdr.ConfigSettingIdConfigSettingBucketlistSizeWindow,
+			BucketListSizeWindow: &window,
+		},
+	}
+}
+
+func TestGetSorobanConfig(t *testing.T) {
+	reader := &configSettingReader{settings: testConfigSettings()}
+	handler := WithLedgerRange(
+		NewLedgerRangeCache(&ConstantLedgerReader{}),
+		NewGetSorobanConfigHandler(log.DefaultLogger, reader),
+	)
+	requests, err := jrpc2.ParseRequests([]byte(`{"jsonrpc": "2.0", "id": 1, "method": "getSorobanConfig"}`))
+	require.NoError(t, err)
+	result, err := handler(context.Background(), requests[0].ToRequest())
+	require.NoError(t, err)
+	response := result.(GetSorobanConfigResponse)
+
+	assert.Equal(t, LedgerRangeResponse{
+		LatestLedger:          expectedLatestLedgerSequence,
+		LatestLedgerCloseTime: 4800,
+		OldestLedger:          1,
+		OldestLedgerCloseTime: 5,
+	}, response.LedgerRangeResponse)
+	assert.Equal(t, uint32(65536), response.ContractMaxSizeBytes)
+	assert.Equal(t, uint32(250), response.ContractDataKeySizeBytes)
+	assert.Equal(t, int64(100_000_000), response.Compute.TxMaxInstructions)
+	assert.Equal(t, uint32(41943040), response.Compute.TxMemoryLimit)
+	assert.Equal(t, uint32(40), response.LedgerCost.TxMaxReadLedgerEntries)
+	assert.Equal(t, int64(6250), response.LedgerCost.FeeReadLedgerEntry)
+	assert.Equal(t, int64(16235), response.FeeHistorical1Kb)
+	assert.Equal(t, uint32(8198), response.Events.TxMaxContractEventsSizeBytes)
+	assert.Equal(t, uint32(70_000), response.Bandwidth.TxMaxSizeBytes)
+	assert.Equal(t, uint32(120_960), response.StateArchival.MinPersistentTTL)
+	assert.Equal(t, uint32(100), response.LedgerMaxTxCount)
+	assert.Equal(t, uint64(200), response.AverageBucketListSize)
+
+	encoded, err := json.Marshal(response)
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"txMaxInstructions":"100000000"`)
+	assert.Contains(t, string(encoded), `"maxEntryTtl":3110400`)
+}
+
+func TestGetSorobanConfigMissingSetting(t *testing.T) {
+	settings := testConfigSettings()
+	delete(settings, xdr.ConfigSettingIdConfigSettingStateArchival)
+	handler := NewGetSorobanConfigHandler(log.DefaultLogger, &configSettingReader{settings: settings})
+	requests, err := jrpc2.ParseRequests([]byte(`{"jsonrpc": "2.0", "id": 1, "method": "getSorobanConfig"}`))
+	require.NoError(t, err)
+	_, err = handler(context.Background(), requests[0].ToRequest())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ConfigSettingIdConfigSettingStateArchival")
+}
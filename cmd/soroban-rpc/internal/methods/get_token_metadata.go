@@ -0,0 +1,306 @@
+package methods
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/preflight"
+)
+
+const (
+	// maxTokenMetadataCacheSize bounds the amount of tokens whose metadata is cached
+	maxTokenMetadataCacheSize = 10000
+	// keypairZeroAddress is the account id with an all-zeros public key
+	keypairZeroAddress = "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"
+)
+
+type GetTokenMetadataRequest struct {
+	ContractID string `json:"contractId"`
+}
+
+type GetTokenMetadataResponse struct {
+	ContractID string `json:"contractId"`
+	Name       string `json:"name"`
+	Symbol     string `json:"symbol"`
+	Decimals   uint32 `json:"decimals"`
+	LedgerRangeResponse
+}
+
+type tokenMetadata struct {
+	name     string
+	symbol   string
+	decimals uint32
+}
+
+type cachedTokenMetadata struct {
+	// executable is the XDR of the contract executable the metadata was
+	// obtained from, it changes when the contract is upgraded.
+	executable string
+	metadata   tokenMetadata
+}
+
+// tokenMetadataCache caches the metadata of tokens, keyed by contract id.
+type tokenMetadataCache struct {
+	lock    sync.Mutex
+	entries map[xdr.Hash]cachedTokenMetadata
+}
+
+func (c *tokenMetadataCache) get(contractID xdr.Hash, executable string) (tokenMetadata, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry, ok := c.entries[contractID]
+	if !ok {
+		return tokenMetadata{}, false
+	}
+	if entry.executable != executable {
+		// the contract was upgraded
+		delete(c.entries, contractID)
+		return tokenMetadata{}, false
+	}
+	return entry.metadata, true
+}
+
+func (c *tokenMetadataCache) set(contractID xdr.Hash, executable string, metadata tokenMetadata) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if _, ok := c.entries[contractID]; !ok && len(c.entries) >= maxTokenMetadataCacheSize {
+		// evict an arbitrary entry
+		for k := range c.entries {
+			delete(c.entries, k)
+			break
+		}
+	}
+	c.entries[contractID] = cachedTokenMetadata{executable: executable, metadata: metadata}
+}
+
+type tokenMetadataHandler struct {
+	logger            *log.Entry
+	ledgerEntryReader db.LedgerEntryReader
+	ledgerReader      db.LedgerReader
+	preflightGetter   PreflightGetter
+	preflightChecker  PreflightCompatibilityChecker
+	cache             *tokenMetadataCache
+}
+
+func (h tokenMetadataHandler) getTokenMetadata(ctx context.Context, request GetTokenMetadataRequest) (GetTokenMetadataResponse, error) {
+	contractIDBytes, err := strkey.Decode(strkey.VersionByteContract, request.ContractID)
+	if err != nil {
+		return GetTokenMetadataResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: fmt.Sprintf("invalid contract id: %v", err),
+		}
+	}
+	var contractID xdr.Hash
+	copy(contractID[:], contractIDBytes)
+
+	readTx, err := h.ledgerEntryReader.NewCachedTx(ctx)
+	if err != nil {
+		return GetTokenMetadataResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: "could not create read transaction",
+		}
+	}
+	defer func() {
+		_ = readTx.Done()
+	}()
+	latestLedger, err := readTx.GetLatestLedgerSequence()
+	if err != nil {
+		return GetTokenMetadataResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: "could not get latest ledger",
+		}
+	}
+
+	executable, err := getContractExecutable(readTx, contractID)
+	if err != nil {
+		return GetTokenMetadataResponse{}, err
+	}
+
+	metadata, ok := h.cache.get(contractID, executable)
+	if !ok {
+		metadata, err = h.fetchTokenMetadata(ctx, readTx, contractID, latestLedger)
+		if err != nil {
+			return GetTokenMetadataResponse{}, err
+		}
+		h.cache.set(contractID, executable, metadata)
+	}
+
+	return GetTokenMetadataResponse{
+		ContractID:          request.ContractID,
+		Name:                metadata.name,
+		Symbol:              metadata.symbol,
+		Decimals:            metadata.decimals,
+		LedgerRangeResponse: LedgerRangeResponse{LatestLedger: latestLedger},
+	}, nil
+}
+
+// getContractExecutable returns the (base64 XDR encoded) executable of the
+// contract, as stored in its instance ledger entry.
+func getContractExecutable(readTx db.LedgerEntryReadTx, contractID xdr.Hash) (string, error) {
+	contractIDCopy := contractID
+	instanceKey := xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.LedgerKeyContractData{
+			Contract: xdr.ScAddress{
+				Type:       xdr.ScAddressTypeScAddressTypeContract,
+				ContractId: &contractIDCopy,
+			},
+			Key:        xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+			Durability: xdr.ContractDataDurabilityPersistent,
+		},
+	}
+	entries, err := readTx.GetLedgerEntries(instanceKey)
+	if err != nil {
+		return "", &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: "could not obtain contract instance",
+		}
+	}
+	if len(entries) == 0 {
+		return "", &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: "contract not found",
+		}
+	}
+	instance, ok := entries[0].Entry.Data.ContractData.Val.GetInstance()
+	if !ok {
+		return "", &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: "unexpected contract instance entry",
+		}
+	}
+	executable, err := xdr.MarshalBase64(instance.Executable)
+	if err != nil {
+		return "", &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: "could not encode contract executable",
+		}
+	}
+	return executable, nil
+}
+
+func (h tokenMetadataHandler) fetchTokenMetadata(
+	ctx context.Context, readTx db.LedgerEntryReadTx, contractID xdr.Hash, latestLedger uint32,
+) (tokenMetadata, error) {
+	bucketListSize, protocolVersion, err := getBucketListSizeAndProtocolVersion(ctx, h.ledgerReader, latestLedger)
+	if err != nil {
+		return tokenMetadata{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+	if err := h.preflightChecker.CheckCompatibility(protocolVersion); err != nil {
+		// refuse to simulate rather than producing wrong results
+		return tokenMetadata{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: "simulation is unavailable: " + err.Error(),
+		}
+	}
+
+	var metadata tokenMetadata
+	for _, function := range []string{"name", "symbol", "decimals"} {
+		result, err := h.simulateViewCall(ctx, readTx, contractID, function, bucketListSize, protocolVersion)
+		if err != nil {
+			return tokenMetadata{}, err
+		}
+		var ok bool
+		switch function {
+		case "name":
+			var name xdr.ScString
+			name, ok = result.GetStr()
+			metadata.name = string(name)
+		case "symbol":
+			var symbol xdr.ScString
+			symbol, ok = result.GetStr()
+			metadata.symbol = string(symbol)
+		case "decimals":
+			var decimals xdr.Uint32
+			decimals, ok = result.GetU32()
+			metadata.decimals = uint32(decimals)
+		}
+		if !ok {
+			return tokenMetadata{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: fmt.Sprintf("contract is not a token: unexpected %s() result type %s", function, result.Type),
+			}
+		}
+	}
+	return metadata, nil
+}
+
+func (h tokenMetadataHandler) simulateViewCall(
+	ctx context.Context, readTx db.LedgerEntryReadTx, contractID xdr.Hash, function string, bucketListSize uint64, protocolVersion uint32,
+) (xdr.ScVal, error) {
+	contractIDCopy := contractID
+	params := preflight.GetterParameters{
+		LedgerEntryReadTx: readTx,
+		BucketListSize:    bucketListSize,
+		// view calls don't require authorization, so any source account will do
+		SourceAccount: xdr.MustAddress(keypairZeroAddress),
+		OperationBody: xdr.OperationBody{
+			Type: xdr.OperationTypeInvokeHostFunction,
+			InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+				HostFunction: xdr.HostFunction{
+					Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+					InvokeContract: &xdr.InvokeContractArgs{
+						ContractAddress: xdr.ScAddress{
+							Type:       xdr.ScAddressTypeScAddressTypeContract,
+							ContractId: &contractIDCopy,
+						},
+						FunctionName: xdr.ScSymbol(function),
+						Args:         xdr.ScVec{},
+					},
+				},
+			},
+		},
+		ResourceConfig:  preflight.DefaultResourceConfig(),
+		ProtocolVersion: protocolVersion,
+	}
+	result, err := h.preflightGetter.GetPreflight(ctx, params)
+	if err != nil {
+		return xdr.ScVal{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+	if result.Error != "" {
+		h.logger.WithField("function", function).Debugf("token metadata simulation failed: %s", result.Error)
+		return xdr.ScVal{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: fmt.Sprintf("could not invoke %s(): %s", function, result.Error),
+		}
+	}
+	var value xdr.ScVal
+	if err := xdr.SafeUnmarshal(result.Result, &value); err != nil {
+		return xdr.ScVal{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: fmt.Sprintf("could not decode %s() result", function),
+		}
+	}
+	return value, nil
+}
+
+// NewGetTokenMetadataHandler returns a json rpc handler providing the name, symbol and decimals of tokens.
+// The metadata is obtained by simulating calls to the token contract and cached until the contract is upgraded.
+func NewGetTokenMetadataHandler(
+	logger *log.Entry, ledgerEntryReader db.LedgerEntryReader, ledgerReader db.LedgerReader,
+	getter PreflightGetter, checker PreflightCompatibilityChecker,
+) jrpc2.Handler {
+	handler := tokenMetadataHandler{
+		logger:            logger,
+		ledgerEntryReader: ledgerEntryReader,
+		ledgerReader:      ledgerReader,
+		preflightGetter:   getter,
+		preflightChecker:  checker,
+		cache:             &tokenMetadataCache{entries: map[xdr.Hash]cachedTokenMetadata{}},
+	}
+	return NewHandler(handler.getTokenMetadata)
+}
@@ -0,0 +1,148 @@
+package methods
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/preflight"
+)
+
+type contractInstanceReader struct {
+	ConstantLedgerEntryReader
+	wasmHash xdr.Hash
+}
+
+func (r *contractInstanceReader) NewCachedTx(ctx context.Context) (db.LedgerEntryReadTx, error) {
+	return contractInstanceReadTx{wasmHash: r.wasmHash}, nil
+}
+
+type contractInstanceReadTx struct {
+	ConstantLedgerEntryReaderTx
+	wasmHash xdr.Hash
+}
+
+func (tx contractInstanceReadTx) GetLedgerEntries(keys ...xdr.LedgerKey) ([]db.LedgerKeyAndEntry, error) {
+	wasmHash := tx.wasmHash
+	return []db.LedgerKeyAndEntry{{
+		Key: keys[0],
+		Entry: xdr.LedgerEntry{
+			Data: xdr.LedgerEntryData{
+				Type: xdr.LedgerEntryTypeContractData,
+				ContractData: &xdr.ContractDataEntry{
+					Contract:   keys[0].ContractData.Contract,
+					Key:        keys[0].ContractData.Key,
+					Durability: keys[0].ContractData.Durability,
+					Val: xdr.ScVal{
+						Type: xdr.ScValTypeScvContractInstance,
+						Instance: &xdr.ScContractInstance{
+							Executable: xdr.ContractExecutable{
+								Type:     xdr.ContractExecutableTypeContractExecutableWasm,
+								WasmHash: &wasmHash,
+							},
+						},
+					},
+				},
+			},
+		},
+	}}, nil
+}
+
+type tokenPreflightGetter struct {
+	calls []string
+}
+
+func (g *tokenPreflightGetter) GetPreflight(ctx context.Context, params preflight.GetterParameters) (preflight.Preflight, error) {
+	function := string(params.OperationBody.InvokeHostFunctionOp.HostFunction.InvokeContract.FunctionName)
+	g.calls = append(g.calls, function)
+	var result xdr.ScVal
+	switch function {
+	case "name":
+		name := xdr.ScString("Token")
+		result = xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &name}
+	case "symbol":
+		symbol := xdr.ScString("TKN")
+		result = xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &symbol}
+	case "decimals":
+		decimals := xdr.Uint32(7)
+		result = xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &decimals}
+	default:
+		return preflight.Preflight{Error: "unknown function"}, nil
+	}
+	resultXDR, err := result.MarshalBinary()
+	if err != nil {
+		return preflight.Preflight{}, err
+	}
+	return preflight.Preflight{Result: resultXDR}, nil
+}
+
+func TestGetTokenMetadata(t *testing.T) {
+	reader := &contractInstanceReader{wasmHash: xdr.Hash{1}}
+	getter := &tokenPreflightGetter{}
+	handler := tokenMetadataHandler{
+		logger:            log.DefaultLogger,
+		ledgerEntryReader: reader,
+		ledgerReader:      &ConstantLedgerReader{},
+		preflightGetter:   getter,
+		preflightChecker:  compatiblePreflightChecker{},
+		cache:             &tokenMetadataCache{entries: map[xdr.Hash]cachedTokenMetadata{}},
+	}
+	contractID := strkey.MustEncode(strkey.VersionByteContract, make([]byte, 32))
+	request := GetTokenMetadataRequest{ContractID: contractID}
+
+	expected := GetTokenMetadataResponse{
+		ContractID:          contractID,
+		Name:                "Token",
+		Symbol:              "TKN",
+		Decimals:            7,
+		LedgerRangeResponse: LedgerRangeResponse{LatestLedger: expectedLatestLedgerSequence},
+	}
+	response, err := handler.getTokenMetadata(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, expected, response)
+	assert.Equal(t, []string{"name", "symbol", "decimals"}, getter.calls)
+
+	// the metadata is cached
+	response, err = handler.getTokenMetadata(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, expected, response)
+	assert.Len(t, getter.calls, 3)
+
+	// until the contract is upgraded
+	reader.wasmHash = xdr.Hash{2}
+	response, err = handler.getTokenMetadata(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, expected, response)
+	assert.Len(t, getter.calls, 6)
+}
+
+func TestGetTokenMetadataIncompatibleProtocol(t *testing.T) {
+	getter := &tokenPreflightGetter{}
+	handler := tokenMetadataHandler{
+		logger:            log.DefaultLogger,
+		ledgerEntryReader: &contractInstanceReader{wasmHash: xdr.Hash{1}},
+		ledgerReader:      &ConstantLedgerReader{},
+		preflightGetter:   getter,
+		preflightChecker:  maxProtocolPreflightChecker(expectedLatestLedgerProtocolVersion - 1),
+		cache:             &tokenMetadataCache{entries: map[xdr.Hash]cachedTokenMetadata{}},
+	}
+	contractID := strkey.MustEncode(strkey.VersionByteContract, make([]byte, 32))
+	_, err := handler.getTokenMetadata(context.Background(), GetTokenMetadataRequest{ContractID: contractID})
+	require.ErrorContains(t, err, "simulation is unavailable")
+	assert.Empty(t, getter.calls)
+}
+
+func TestGetTokenMetadataInvalidContractID(t *testing.T) {
+	handler := tokenMetadataHandler{
+		cache: &tokenMetadataCache{entries: map[xdr.Hash]cachedTokenMetadata{}},
+	}
+	_, err := handler.getTokenMetadata(context.Background(), GetTokenMetadataRequest{ContractID: "foo"})
+	require.ErrorContains(t, err, "invalid contract id")
+}
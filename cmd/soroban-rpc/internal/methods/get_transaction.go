@@ -9,10 +9,12 @@ import (
 
 	"github.com/creachadair/jrpc2"
 
+	"github.com/stellar/go/network"
 	"github.com/stellar/go/support/log"
 	"github.com/stellar/go/xdr"
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/gossip"
 )
 
 const (
@@ -31,14 +33,7 @@ const (
 type GetTransactionResponse struct {
 	// Status is one of: TransactionSuccess, TransactionNotFound, or TransactionFailed.
 	Status string `json:"status"`
-	// LatestLedger is the latest ledger stored in Soroban-RPC.
-	LatestLedger uint32 `json:"latestLedger"`
-	// LatestLedgerCloseTime is the unix timestamp of when the latest ledger was closed.
-	LatestLedgerCloseTime int64 `json:"latestLedgerCloseTime,string"`
-	// LatestLedger is the oldest ledger stored in Soroban-RPC.
-	OldestLedger uint32 `json:"oldestLedger"`
-	// LatestLedgerCloseTime is the unix timestamp of when the oldest ledger was closed.
-	OldestLedgerCloseTime int64 `json:"oldestLedgerCloseTime,string"`
+	LedgerRangeResponse
 
 	// The fields below are only present if Status is not TransactionNotFound.
 
@@ -47,6 +42,8 @@ type GetTransactionResponse struct {
 	ApplicationOrder int32 `json:"applicationOrder,omitempty"`
 	// FeeBump indicates whether the transaction is a feebump transaction
 	FeeBump bool `json:"feeBump,omitempty"`
+	// FeeBumpDetails describes the inner transaction, only present if FeeBump is true.
+	FeeBumpDetails *FeeBumpDetails `json:"feeBumpDetails,omitempty"`
 	// EnvelopeXdr is the TransactionEnvelope XDR value.
 	EnvelopeXdr string `json:"envelopeXdr,omitempty"`
 	// ResultXdr is the TransactionResult XDR value.
@@ -62,16 +59,144 @@ type GetTransactionResponse struct {
 	// DiagnosticEventsXDR is present only if Status is equal to TransactionFailed.
 	// DiagnosticEventsXDR is a base64-encoded slice of xdr.DiagnosticEvent
 	DiagnosticEventsXDR []string `json:"diagnosticEventsXdr,omitempty"`
+	// Events are the events (and the return value) of Soroban transactions, decoded from ResultMetaXdr.
+	Events *TransactionEvents `json:"events,omitempty"`
+
+	// PeerHint is the (unverified) inclusion of the transaction reported by a peer node.
+	// It is only present if Status is TransactionNotFound.
+	PeerHint *PeerTransactionHint `json:"peerHint,omitempty"`
+	// PeerSubmissionStatus is the sendTransaction status reported by a peer node
+	// which submitted the transaction. It is only present if Status is TransactionNotFound.
+	PeerSubmissionStatus string `json:"peerSubmissionStatus,omitempty"`
+}
+
+// PeerTransactionHint is the inclusion of a transaction, as ingested by a peer node
+type PeerTransactionHint struct {
+	// Status is either TransactionSuccess or TransactionFailed.
+	Status string `json:"status"`
+	// Ledger is the sequence of the ledger which included the transaction.
+	Ledger uint32 `json:"ledger"`
+	// LedgerCloseTime is the unix timestamp of when the transaction was included in the ledger.
+	LedgerCloseTime int64 `json:"createdAt,string"`
+}
+
+// TransactionEvents are the outputs of a Soroban transaction, separated from its TransactionMeta
+type TransactionEvents struct {
+	// ContractEventsXDR is a base64-encoded slice of the xdr.ContractEvent emitted by the transaction
+	ContractEventsXDR []string `json:"contractEventsXdr"`
+	// DiagnosticEventsXDR is a base64-encoded slice of the xdr.DiagnosticEvent emitted by the
+	// transaction (only if diagnostic events were enabled in captive core)
+	DiagnosticEventsXDR []string `json:"diagnosticEventsXdr"`
+	// ReturnValueXDR is the xdr.ScVal returned by the invoked contract function
+	ReturnValueXDR string `json:"returnValueXdr,omitempty"`
+}
+
+// newTransactionEvents decodes the events of a transaction out of its meta,
+// returning nil if it isn't a Soroban transaction.
+func newTransactionEvents(encodedMeta []byte) (*TransactionEvents, error) {
+	var meta xdr.TransactionMeta
+	if err := xdr.SafeUnmarshal(encodedMeta, &meta); err != nil {
+		return nil, err
+	}
+	if meta.V != 3 || meta.V3.SorobanMeta == nil {
+		return nil, nil
+	}
+	sorobanMeta := meta.V3.SorobanMeta
+	events := TransactionEvents{
+		ContractEventsXDR:   make([]string, 0, len(sorobanMeta.Events)),
+		DiagnosticEventsXDR: make([]string, 0, len(sorobanMeta.DiagnosticEvents)),
+	}
+	for _, event := range sorobanMeta.Events {
+		encoded, err := xdr.MarshalBase64(event)
+		if err != nil {
+			return nil, err
+		}
+		events.ContractEventsXDR = append(events.ContractEventsXDR, encoded)
+	}
+	for _, event := range sorobanMeta.DiagnosticEvents {
+		encoded, err := xdr.MarshalBase64(event)
+		if err != nil {
+			return nil, err
+		}
+		events.DiagnosticEventsXDR = append(events.DiagnosticEventsXDR, encoded)
+	}
+	if sorobanMeta.ReturnValue.Type != xdr.ScValTypeScvVoid {
+		encoded, err := xdr.MarshalBase64(sorobanMeta.ReturnValue)
+		if err != nil {
+			return nil, err
+		}
+		events.ReturnValueXDR = encoded
+	}
+	return &events, nil
+}
+
+// FeeBumpDetails are the details of the inner transaction of a fee-bump transaction
+type FeeBumpDetails struct {
+	// FeeAccount is the account paying the fee of the fee-bump transaction.
+	FeeAccount string `json:"feeAccount"`
+	// InnerTransactionHash is the hex-encoded hash of the inner transaction.
+	InnerTransactionHash string `json:"innerTransactionHash"`
+	// InnerEnvelopeXdr is the TransactionEnvelope XDR value of the inner transaction.
+	InnerEnvelopeXdr string `json:"innerEnvelopeXdr,omitempty"`
+	// InnerResultXdr is the InnerTransactionResult XDR value.
+	// It is absent if the fee-bump failed before applying the inner transaction.
+	InnerResultXdr string `json:"innerResultXdr,omitempty"`
+}
+
+// newFeeBumpDetails extracts the inner transaction out of the envelope and the result
+// of a fee-bump transaction. The inner hash is derived from the inner envelope, since
+// the result doesn't include it when the fee-bump failed before applying the inner transaction.
+func newFeeBumpDetails(encodedEnvelope []byte, encodedResult []byte, networkPassphrase string) (*FeeBumpDetails, error) {
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshal(encodedEnvelope, &envelope); err != nil {
+		return nil, err
+	}
+	var result xdr.TransactionResult
+	if err := xdr.SafeUnmarshal(encodedResult, &result); err != nil {
+		return nil, err
+	}
+	feeAccount := envelope.FeeBumpAccount()
+	feeAccountAddress, err := feeAccount.GetAddress()
+	if err != nil {
+		return nil, err
+	}
+	innerTx := envelope.FeeBump.Tx.InnerTx.MustV1()
+	innerHash, err := network.HashTransaction(innerTx.Tx, networkPassphrase)
+	if err != nil {
+		return nil, err
+	}
+	details := FeeBumpDetails{
+		FeeAccount:           feeAccountAddress,
+		InnerTransactionHash: hex.EncodeToString(innerHash[:]),
+	}
+	details.InnerEnvelopeXdr, err = xdr.MarshalBase64(xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1:   &innerTx,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if innerResultPair, ok := result.Result.GetInnerResultPair(); ok {
+		details.InnerResultXdr, err = xdr.MarshalBase64(innerResultPair.Result)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &details, nil
 }
 
 type GetTransactionRequest struct {
 	Hash string `json:"hash"`
+	// XDRFields (optional) restricts the XDR fields included in the response
+	// (see XDRFieldEnvelope and the like). All of them are included by default.
+	XDRFields []string `json:"xdrFields,omitempty"`
 }
 
 func GetTransaction(
 	ctx context.Context,
 	log *log.Entry,
 	reader db.TransactionReader,
+	networkPassphrase string,
 	request GetTransactionRequest,
 ) (GetTransactionResponse, error) {
 	// parse hash
@@ -91,13 +216,18 @@ func GetTransaction(
 		}
 	}
 
+	xdrFields, err := newXDRFieldSelection(request.XDRFields)
+	if err != nil {
+		return GetTransactionResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: err.Error(),
+		}
+	}
+
 	tx, storeRange, err := reader.GetTransaction(ctx, txHash)
 
 	response := GetTransactionResponse{
-		LatestLedger:          storeRange.LastLedger.Sequence,
-		LatestLedgerCloseTime: storeRange.LastLedger.CloseTime,
-		OldestLedger:          storeRange.FirstLedger.Sequence,
-		OldestLedgerCloseTime: storeRange.FirstLedger.CloseTime,
+		LedgerRangeResponse: NewLedgerRangeResponse(storeRange),
 	}
 	if errors.Is(err, db.ErrNoTransaction) {
 		response.Status = TransactionStatusNotFound
@@ -117,10 +247,48 @@ func GetTransaction(
 	response.Ledger = tx.Ledger.Sequence
 	response.LedgerCloseTime = tx.Ledger.CloseTime
 
-	response.ResultXdr = base64.StdEncoding.EncodeToString(tx.Result)
-	response.EnvelopeXdr = base64.StdEncoding.EncodeToString(tx.Envelope)
-	response.ResultMetaXdr = base64.StdEncoding.EncodeToString(tx.Meta)
-	response.DiagnosticEventsXDR = base64EncodeSlice(tx.Events)
+	if xdrFields[XDRFieldResult] {
+		response.ResultXdr = base64.StdEncoding.EncodeToString(tx.Result)
+	}
+	if xdrFields[XDRFieldEnvelope] {
+		response.EnvelopeXdr = base64.StdEncoding.EncodeToString(tx.Envelope)
+	}
+	if xdrFields[XDRFieldResultMeta] {
+		response.ResultMetaXdr = base64.StdEncoding.EncodeToString(tx.Meta)
+	}
+	if xdrFields[XDRFieldDiagnosticEvents] {
+		response.DiagnosticEventsXDR = base64EncodeSlice(tx.Events)
+	}
+	if xdrFields[XDRFieldEvents] {
+		response.Events, err = newTransactionEvents(tx.Meta)
+		if err != nil {
+			log.WithError(err).
+				WithField("hash", txHash).
+				Errorf("failed to decode transaction events")
+			return response, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+	}
+	if tx.FeeBump {
+		response.FeeBumpDetails, err = newFeeBumpDetails(tx.Envelope, tx.Result, networkPassphrase)
+		if err != nil {
+			log.WithError(err).
+				WithField("hash", txHash).
+				Errorf("failed to decode fee-bump transaction")
+			return response, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		if !xdrFields[XDRFieldEnvelope] {
+			response.FeeBumpDetails.InnerEnvelopeXdr = ""
+		}
+		if !xdrFields[XDRFieldResult] {
+			response.FeeBumpDetails.InnerResultXdr = ""
+		}
+	}
 
 	response.Status = TransactionStatusFailed
 	if tx.Successful {
@@ -129,9 +297,49 @@ func GetTransaction(
 	return response, nil
 }
 
+// TransactionHints shares information about transactions with other nodes.
+type TransactionHints interface {
+	Lookup(hash string) (gossip.Hint, bool)
+	Publish(hints ...gossip.Hint)
+}
+
+// getTransactionWithPeerHints completes the response of transactions which
+// weren't found with the information obtained from peer nodes.
+func getTransactionWithPeerHints(
+	ctx context.Context,
+	log *log.Entry,
+	reader db.TransactionReader,
+	hints TransactionHints,
+	networkPassphrase string,
+	request GetTransactionRequest,
+) (GetTransactionResponse, error) {
+	response, err := GetTransaction(ctx, log, reader, networkPassphrase, request)
+	if err != nil || response.Status != TransactionStatusNotFound {
+		return response, err
+	}
+	hint, ok := hints.Lookup(request.Hash)
+	if !ok {
+		return response, nil
+	}
+	switch hint.Kind {
+	case gossip.HintKindInclusion:
+		// the transaction can't be confirmed (nor its XDR provided) until it's ingested locally
+		response.PeerHint = &PeerTransactionHint{
+			Status:          hint.Status,
+			Ledger:          hint.Ledger,
+			LedgerCloseTime: hint.LedgerCloseTime,
+		}
+	case gossip.HintKindSubmission:
+		response.PeerSubmissionStatus = hint.Status
+	}
+	return response, nil
+}
+
 // NewGetTransactionHandler returns a get transaction json rpc handler
-func NewGetTransactionHandler(logger *log.Entry, getter db.TransactionReader) jrpc2.Handler {
+func NewGetTransactionHandler(
+	logger *log.Entry, getter db.TransactionReader, hints TransactionHints, networkPassphrase string,
+) jrpc2.Handler {
 	return NewHandler(func(ctx context.Context, request GetTransactionRequest) (GetTransactionResponse, error) {
-		return GetTransaction(ctx, logger, getter, request)
+		return getTransactionWithPeerHints(ctx, logger, getter, hints, networkPassphrase, request)
 	})
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/stellar/go/xdr"
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/gossip"
 )
 
 func TestGetTransaction(t *testing.T) {
@@ -23,13 +24,13 @@ func TestGetTransaction(t *testing.T) {
 	)
 	log.SetLevel(logrus.DebugLevel)
 
-	_, err := GetTransaction(ctx, log, store, GetTransactionRequest{"ab"})
+	_, err := GetTransaction(ctx, log, store, "passphrase", GetTransactionRequest{Hash: "ab"})
 	require.EqualError(t, err, "[-32602] unexpected hash length (2)")
-	_, err = GetTransaction(ctx, log, store, GetTransactionRequest{"foo                                                              "})
+	_, err = GetTransaction(ctx, log, store, "passphrase", GetTransactionRequest{Hash: "foo                                                              "})
 	require.EqualError(t, err, "[-32602] incorrect hash: encoding/hex: invalid byte: U+006F 'o'")
 
 	hash := "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
-	tx, err := GetTransaction(ctx, log, store, GetTransactionRequest{hash})
+	tx, err := GetTransaction(ctx, log, store, "passphrase", GetTransactionRequest{Hash: hash})
 	require.NoError(t, err)
 	require.Equal(t, GetTransactionResponse{Status: TransactionStatusNotFound}, tx)
 
@@ -38,7 +39,7 @@ func TestGetTransaction(t *testing.T) {
 
 	xdrHash := txHash(1)
 	hash = hex.EncodeToString(xdrHash[:])
-	tx, err = GetTransaction(ctx, log, store, GetTransactionRequest{hash})
+	tx, err = GetTransaction(ctx, log, store, "passphrase", GetTransactionRequest{Hash: hash})
 	require.NoError(t, err)
 
 	expectedTxResult, err := xdr.MarshalBase64(meta.V1.TxProcessing[0].Result.Result)
@@ -48,19 +49,21 @@ func TestGetTransaction(t *testing.T) {
 	expectedTxMeta, err := xdr.MarshalBase64(meta.V1.TxProcessing[0].TxApplyProcessing)
 	require.NoError(t, err)
 	require.Equal(t, GetTransactionResponse{
-		Status:                TransactionStatusSuccess,
-		LatestLedger:          101,
-		LatestLedgerCloseTime: 2625,
-		OldestLedger:          101,
-		OldestLedgerCloseTime: 2625,
-		ApplicationOrder:      1,
-		FeeBump:               false,
-		EnvelopeXdr:           expectedEnvelope,
-		ResultXdr:             expectedTxResult,
-		ResultMetaXdr:         expectedTxMeta,
-		Ledger:                101,
-		LedgerCloseTime:       2625,
-		DiagnosticEventsXDR:   []string{},
+		Status: TransactionStatusSuccess,
+		LedgerRangeResponse: LedgerRangeResponse{
+			LatestLedger:          101,
+			LatestLedgerCloseTime: 2625,
+			OldestLedger:          101,
+			OldestLedgerCloseTime: 2625,
+		},
+		ApplicationOrder:    1,
+		FeeBump:             false,
+		EnvelopeXdr:         expectedEnvelope,
+		ResultXdr:           expectedTxResult,
+		ResultMetaXdr:       expectedTxMeta,
+		Ledger:              101,
+		LedgerCloseTime:     2625,
+		DiagnosticEventsXDR: []string{},
 	}, tx)
 
 	// ingest another (failed) transaction
@@ -68,22 +71,24 @@ func TestGetTransaction(t *testing.T) {
 	require.NoError(t, store.InsertTransactions(meta))
 
 	// the first transaction should still be there
-	tx, err = GetTransaction(ctx, log, store, GetTransactionRequest{hash})
+	tx, err = GetTransaction(ctx, log, store, "passphrase", GetTransactionRequest{Hash: hash})
 	require.NoError(t, err)
 	require.Equal(t, GetTransactionResponse{
-		Status:                TransactionStatusSuccess,
-		LatestLedger:          102,
-		LatestLedgerCloseTime: 2650,
-		OldestLedger:          101,
-		OldestLedgerCloseTime: 2625,
-		ApplicationOrder:      1,
-		FeeBump:               false,
-		EnvelopeXdr:           expectedEnvelope,
-		ResultXdr:             expectedTxResult,
-		ResultMetaXdr:         expectedTxMeta,
-		Ledger:                101,
-		LedgerCloseTime:       2625,
-		DiagnosticEventsXDR:   []string{},
+		Status: TransactionStatusSuccess,
+		LedgerRangeResponse: LedgerRangeResponse{
+			LatestLedger:          102,
+			LatestLedgerCloseTime: 2650,
+			OldestLedger:          101,
+			OldestLedgerCloseTime: 2625,
+		},
+		ApplicationOrder:    1,
+		FeeBump:             false,
+		EnvelopeXdr:         expectedEnvelope,
+		ResultXdr:           expectedTxResult,
+		ResultMetaXdr:       expectedTxMeta,
+		Ledger:              101,
+		LedgerCloseTime:     2625,
+		DiagnosticEventsXDR: []string{},
 	}, tx)
 
 	// the new transaction should also be there
@@ -97,22 +102,24 @@ func TestGetTransaction(t *testing.T) {
 	expectedTxMeta, err = xdr.MarshalBase64(meta.V1.TxProcessing[0].TxApplyProcessing)
 	require.NoError(t, err)
 
-	tx, err = GetTransaction(ctx, log, store, GetTransactionRequest{hash})
+	tx, err = GetTransaction(ctx, log, store, "passphrase", GetTransactionRequest{Hash: hash})
 	require.NoError(t, err)
 	require.Equal(t, GetTransactionResponse{
-		Status:                TransactionStatusFailed,
-		LatestLedger:          102,
-		LatestLedgerCloseTime: 2650,
-		OldestLedger:          101,
-		OldestLedgerCloseTime: 2625,
-		ApplicationOrder:      1,
-		FeeBump:               false,
-		EnvelopeXdr:           expectedEnvelope,
-		ResultXdr:             expectedTxResult,
-		ResultMetaXdr:         expectedTxMeta,
-		Ledger:                102,
-		LedgerCloseTime:       2650,
-		DiagnosticEventsXDR:   []string{},
+		Status: TransactionStatusFailed,
+		LedgerRangeResponse: LedgerRangeResponse{
+			LatestLedger:          102,
+			LatestLedgerCloseTime: 2650,
+			OldestLedger:          101,
+			OldestLedgerCloseTime: 2625,
+		},
+		ApplicationOrder:    1,
+		FeeBump:             false,
+		EnvelopeXdr:         expectedEnvelope,
+		ResultXdr:           expectedTxResult,
+		ResultMetaXdr:       expectedTxMeta,
+		Ledger:              102,
+		LedgerCloseTime:     2650,
+		DiagnosticEventsXDR: []string{},
 	}, tx)
 
 	// Test Txn with events
@@ -133,24 +140,174 @@ func TestGetTransaction(t *testing.T) {
 	require.NoError(t, err)
 	expectedEventsMeta, err := xdr.MarshalBase64(diagnosticEvents[0])
 	require.NoError(t, err)
+	sorobanMeta := meta.V1.TxProcessing[0].TxApplyProcessing.V3.SorobanMeta
+	expectedContractEvent, err := xdr.MarshalBase64(sorobanMeta.Events[0])
+	require.NoError(t, err)
+	expectedReturnValue, err := xdr.MarshalBase64(sorobanMeta.ReturnValue)
+	require.NoError(t, err)
+
+	tx, err = GetTransaction(ctx, log, store, "passphrase", GetTransactionRequest{Hash: hash})
+	require.NoError(t, err)
+	require.Equal(t, GetTransactionResponse{
+		Status: TransactionStatusSuccess,
+		LedgerRangeResponse: LedgerRangeResponse{
+			LatestLedger:          103,
+			LatestLedgerCloseTime: 2675,
+			OldestLedger:          101,
+			OldestLedgerCloseTime: 2625,
+		},
+		ApplicationOrder:    1,
+		FeeBump:             false,
+		EnvelopeXdr:         expectedEnvelope,
+		ResultXdr:           expectedTxResult,
+		ResultMetaXdr:       expectedTxMeta,
+		Ledger:              103,
+		LedgerCloseTime:     2675,
+		DiagnosticEventsXDR: []string{expectedEventsMeta},
+		Events: &TransactionEvents{
+			ContractEventsXDR:   []string{expectedContractEvent},
+			DiagnosticEventsXDR: []string{},
+			ReturnValueXDR:      expectedReturnValue,
+		},
+	}, tx)
+}
+
+func TestGetTransactionFeeBump(t *testing.T) {
+	ctx := context.TODO()
+	store := db.NewMockTransactionStore("passphrase")
+	meta := txMetaFeeBump(1)
+	require.NoError(t, store.InsertTransactions(meta))
+
+	outerHash := meta.V1.TxProcessing[0].Result.TransactionHash.HexString()
+	innerHash := txHash(1).HexString()
+	expectedInnerEnvelope, err := xdr.MarshalBase64(txEnvelope(1))
+	require.NoError(t, err)
+	expectedInnerResult, err := xdr.MarshalBase64(
+		meta.V1.TxProcessing[0].Result.Result.Result.MustInnerResultPair().Result,
+	)
+	require.NoError(t, err)
+	expectedDetails := &FeeBumpDetails{
+		FeeAccount:           "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H",
+		InnerTransactionHash: innerHash,
+		InnerEnvelopeXdr:     expectedInnerEnvelope,
+		InnerResultXdr:       expectedInnerResult,
+	}
+
+	// the transaction can be found by either its outer or its inner hash
+	for _, hash := range []string{outerHash, innerHash} {
+		tx, err := GetTransaction(ctx, log.DefaultLogger, store, "passphrase", GetTransactionRequest{Hash: hash})
+		require.NoError(t, err)
+		require.Equal(t, TransactionStatusSuccess, tx.Status)
+		require.True(t, tx.FeeBump)
+		require.Equal(t, expectedDetails, tx.FeeBumpDetails)
+	}
+}
+
+func TestGetTransactionFeeBumpFailed(t *testing.T) {
+	ctx := context.TODO()
+	store := db.NewMockTransactionStore("passphrase")
+	meta := txMetaFeeBump(1)
+	// the fee-bump failed before applying the inner transaction, so its result has no inner pair
+	meta.V1.TxProcessing[0].Result.Result.Result = xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxBadAuth}
+	require.NoError(t, store.InsertTransactions(meta))
+
+	hash := meta.V1.TxProcessing[0].Result.TransactionHash.HexString()
+	tx, err := GetTransaction(ctx, log.DefaultLogger, store, "passphrase", GetTransactionRequest{Hash: hash})
+	require.NoError(t, err)
+	require.Equal(t, TransactionStatusFailed, tx.Status)
+	require.NotNil(t, tx.FeeBumpDetails)
+	require.Equal(t, txHash(1).HexString(), tx.FeeBumpDetails.InnerTransactionHash)
+	require.NotEmpty(t, tx.FeeBumpDetails.InnerEnvelopeXdr)
+	require.Empty(t, tx.FeeBumpDetails.InnerResultXdr)
+}
+
+func TestGetTransactionXDRFields(t *testing.T) {
+	ctx := context.TODO()
+	store := db.NewMockTransactionStore("passphrase")
+	meta := txMetaFeeBump(1)
+	require.NoError(t, store.InsertTransactions(meta))
+	hash := meta.V1.TxProcessing[0].Result.TransactionHash.HexString()
+
+	tx, err := GetTransaction(ctx, log.DefaultLogger, store, "passphrase", GetTransactionRequest{
+		Hash:      hash,
+		XDRFields: []string{XDRFieldEnvelope},
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransactionStatusSuccess, tx.Status)
+	require.NotEmpty(t, tx.EnvelopeXdr)
+	require.Empty(t, tx.ResultXdr)
+	require.Empty(t, tx.ResultMetaXdr)
+	require.Nil(t, tx.Events)
+	require.NotNil(t, tx.FeeBumpDetails)
+	require.NotEmpty(t, tx.FeeBumpDetails.InnerEnvelopeXdr)
+	require.Empty(t, tx.FeeBumpDetails.InnerResultXdr)
+	require.Equal(t, txHash(1).HexString(), tx.FeeBumpDetails.InnerTransactionHash)
+
+	// an empty selection only leaves out the XDR fields
+	tx, err = GetTransaction(ctx, log.DefaultLogger, store, "passphrase", GetTransactionRequest{
+		Hash:      hash,
+		XDRFields: []string{},
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransactionStatusSuccess, tx.Status)
+	require.Equal(t, uint32(101), tx.Ledger)
+	require.Empty(t, tx.EnvelopeXdr)
+	require.Empty(t, tx.FeeBumpDetails.InnerEnvelopeXdr)
+
+	_, err = GetTransaction(ctx, log.DefaultLogger, store, "passphrase", GetTransactionRequest{
+		Hash:      hash,
+		XDRFields: []string{"meta"},
+	})
+	require.ErrorContains(t, err, `[-32602] unknown xdr field "meta"`)
+}
+
+type staticHints map[string]gossip.Hint
+
+func (h staticHints) Lookup(hash string) (gossip.Hint, bool) {
+	hint, ok := h[hash]
+	return hint, ok
+}
+
+func (h staticHints) Publish(...gossip.Hint) {}
+
+func TestGetTransactionWithPeerHints(t *testing.T) {
+	ctx := context.TODO()
+	store := db.NewMockTransactionStore("passphrase")
+	included := "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	submitted := "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	hints := staticHints{
+		included:  {Hash: included, Kind: gossip.HintKindInclusion, Status: TransactionStatusSuccess, Ledger: 7, LedgerCloseTime: 70},
+		submitted: {Hash: submitted, Kind: gossip.HintKindSubmission, Status: "PENDING"},
+	}
+
+	tx, err := getTransactionWithPeerHints(ctx, log.DefaultLogger, store, hints, "passphrase", GetTransactionRequest{Hash: included})
+	require.NoError(t, err)
+	require.Equal(t, GetTransactionResponse{
+		Status: TransactionStatusNotFound,
+		PeerHint: &PeerTransactionHint{
+			Status:          TransactionStatusSuccess,
+			Ledger:          7,
+			LedgerCloseTime: 70,
+		},
+	}, tx)
 
-	tx, err = GetTransaction(ctx, log, store, GetTransactionRequest{hash})
+	tx, err = getTransactionWithPeerHints(ctx, log.DefaultLogger, store, hints, "passphrase", GetTransactionRequest{Hash: submitted})
 	require.NoError(t, err)
 	require.Equal(t, GetTransactionResponse{
-		Status:                TransactionStatusSuccess,
-		LatestLedger:          103,
-		LatestLedgerCloseTime: 2675,
-		OldestLedger:          101,
-		OldestLedgerCloseTime: 2625,
-		ApplicationOrder:      1,
-		FeeBump:               false,
-		EnvelopeXdr:           expectedEnvelope,
-		ResultXdr:             expectedTxResult,
-		ResultMetaXdr:         expectedTxMeta,
-		Ledger:                103,
-		LedgerCloseTime:       2675,
-		DiagnosticEventsXDR:   []string{expectedEventsMeta},
+		Status:               TransactionStatusNotFound,
+		PeerSubmissionStatus: "PENDING",
 	}, tx)
+
+	// locally ingested transactions take precedence over hints
+	meta := txMeta(1, false)
+	require.NoError(t, store.InsertTransactions(meta))
+	xdrHash := txHash(1)
+	hash := hex.EncodeToString(xdrHash[:])
+	hints[hash] = gossip.Hint{Hash: hash, Kind: gossip.HintKindInclusion, Status: TransactionStatusSuccess}
+	tx, err = getTransactionWithPeerHints(ctx, log.DefaultLogger, store, hints, "passphrase", GetTransactionRequest{Hash: hash})
+	require.NoError(t, err)
+	require.Equal(t, TransactionStatusFailed, tx.Status)
+	require.Nil(t, tx.PeerHint)
 }
 
 func ledgerCloseTime(ledgerSequence uint32) int64 {
@@ -288,3 +445,48 @@ func txMetaWithEvents(acctSeq uint32, successful bool) xdr.LedgerCloseMeta {
 
 	return meta
 }
+
+func txMetaFeeBump(acctSeq uint32) xdr.LedgerCloseMeta {
+	meta := txMeta(acctSeq, true)
+	innerEnvelope := txEnvelope(acctSeq)
+	envelope, err := xdr.NewTransactionEnvelope(xdr.EnvelopeTypeEnvelopeTypeTxFeeBump, xdr.FeeBumpTransactionEnvelope{
+		Tx: xdr.FeeBumpTransaction{
+			FeeSource: xdr.MustMuxedAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"),
+			Fee:       200,
+			InnerTx: xdr.FeeBumpTransactionInnerTx{
+				Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+				V1:   innerEnvelope.V1,
+			},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	hash, err := network.HashTransactionInEnvelope(envelope, "passphrase")
+	if err != nil {
+		panic(err)
+	}
+
+	opResults := []xdr.OperationResult{}
+	meta.V1.TxProcessing[0].Result = xdr.TransactionResultPair{
+		TransactionHash: hash,
+		Result: xdr.TransactionResult{
+			FeeCharged: 200,
+			Result: xdr.TransactionResultResult{
+				Code: xdr.TransactionResultCodeTxFeeBumpInnerSuccess,
+				InnerResultPair: &xdr.InnerTransactionResultPair{
+					TransactionHash: txHash(acctSeq),
+					Result: xdr.InnerTransactionResult{
+						FeeCharged: 100,
+						Result: xdr.InnerTransactionResultResult{
+							Code:    xdr.TransactionResultCodeTxSuccess,
+							Results: &opResults,
+						},
+					},
+				},
+			},
+		},
+	}
+	(*meta.V1.TxSet.V1TxSet.Phases[0].V0Components)[0].TxsMaybeDiscountedFee.Txs[0] = envelope
+	return meta
+}
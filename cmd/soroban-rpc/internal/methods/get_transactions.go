@@ -12,8 +12,10 @@ import (
 	"github.com/creachadair/jrpc2/handler"
 
 	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/support/log"
 	"github.com/stellar/go/toid"
+	"github.com/stellar/go/xdr"
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
@@ -25,10 +27,56 @@ type TransactionsPaginationOptions struct {
 	Limit  uint   `json:"limit,omitempty"`
 }
 
+// TransactionsFilters restricts the transactions returned by getTransactions. Unset filters match all transactions.
+type TransactionsFilters struct {
+	// Status matches the transactions with this status, one of TransactionStatusSuccess or TransactionStatusFailed.
+	Status string `json:"status,omitempty"`
+	// Soroban matches the Soroban transactions (if true) or the classic ones (if false).
+	Soroban *bool `json:"soroban,omitempty"`
+	// SourceAccount matches the transactions (or, for fee-bumps, the inner transactions) whose source is this (G...) account.
+	SourceAccount string `json:"sourceAccount,omitempty"`
+	// ContractID matches the transactions which touched this (C...) contract, through their footprint or their events.
+	ContractID string `json:"contractId,omitempty"`
+}
+
+func (f *TransactionsFilters) dbFilter() (db.TransactionFilter, error) {
+	var filter db.TransactionFilter
+	switch f.Status {
+	case "":
+	case TransactionStatusSuccess, TransactionStatusFailed:
+		successful := f.Status == TransactionStatusSuccess
+		filter.Successful = &successful
+	default:
+		return filter, fmt.Errorf("status must be either %s or %s", TransactionStatusSuccess, TransactionStatusFailed)
+	}
+	filter.Soroban = f.Soroban
+	if f.SourceAccount != "" {
+		accountID, err := xdr.AddressToAccountId(f.SourceAccount)
+		if err != nil {
+			return filter, fmt.Errorf("invalid source account: %w", err)
+		}
+		filter.SourceAccount = &accountID
+	}
+	if f.ContractID != "" {
+		contractIDBytes, err := strkey.Decode(strkey.VersionByteContract, f.ContractID)
+		if err != nil {
+			return filter, fmt.Errorf("invalid contract id: %w", err)
+		}
+		var contractID xdr.Hash
+		copy(contractID[:], contractIDBytes)
+		filter.ContractID = &contractID
+	}
+	return filter, nil
+}
+
 // GetTransactionsRequest represents the request parameters for fetching transactions within a range of ledgers.
 type GetTransactionsRequest struct {
 	StartLedger uint32                         `json:"startLedger"`
+	Filters     *TransactionsFilters           `json:"filters,omitempty"`
 	Pagination  *TransactionsPaginationOptions `json:"pagination,omitempty"`
+	// XDRFields (optional) restricts the XDR fields included in the transactions
+	// (see XDRFieldEnvelope and the like). All of them are included by default.
+	XDRFields []string `json:"xdrFields,omitempty"`
 }
 
 // isValid checks the validity of the request parameters.
@@ -60,12 +108,12 @@ type TransactionInfo struct {
 	ApplicationOrder int32 `json:"applicationOrder"`
 	// FeeBump indicates whether the transaction is a feebump transaction
 	FeeBump bool `json:"feeBump"`
-	// EnvelopeXdr is the TransactionEnvelope XDR value.
-	EnvelopeXdr string `json:"envelopeXdr"`
-	// ResultXdr is the TransactionResult XDR value.
-	ResultXdr string `json:"resultXdr"`
-	// ResultMetaXdr is the TransactionMeta XDR value.
-	ResultMetaXdr string `json:"resultMetaXdr"`
+	// EnvelopeXdr is the TransactionEnvelope XDR value, omitted if not selected through xdrFields.
+	EnvelopeXdr string `json:"envelopeXdr,omitempty"`
+	// ResultXdr is the TransactionResult XDR value, omitted if not selected through xdrFields.
+	ResultXdr string `json:"resultXdr,omitempty"`
+	// ResultMetaXdr is the TransactionMeta XDR value, omitted if not selected through xdrFields.
+	ResultMetaXdr string `json:"resultMetaXdr,omitempty"`
 	// DiagnosticEventsXDR is present only if transaction was not successful.
 	// DiagnosticEventsXDR is a base64-encoded slice of xdr.DiagnosticEvent
 	DiagnosticEventsXDR []string `json:"diagnosticEventsXdr,omitempty"`
@@ -77,12 +125,27 @@ type TransactionInfo struct {
 
 // GetTransactionsResponse encapsulates the response structure for getTransactions queries.
 type GetTransactionsResponse struct {
-	Transactions          []TransactionInfo `json:"transactions"`
-	LatestLedger          uint32            `json:"latestLedger"`
-	LatestLedgerCloseTime int64             `json:"latestLedgerCloseTimestamp"`
-	OldestLedger          uint32            `json:"oldestLedger"`
-	OldestLedgerCloseTime int64             `json:"oldestLedgerCloseTimestamp"`
-	Cursor                string            `json:"cursor"`
+	Transactions []TransactionInfo `json:"transactions"`
+	LedgerRangeResponse
+	// LatestLedgerCloseTimestamp is the unix timestamp of when the latest ledger was closed.
+	//
+	// Deprecated: use LatestLedgerCloseTime, this field will be removed in the next release.
+	LatestLedgerCloseTimestamp int64 `json:"latestLedgerCloseTimestamp"`
+	// OldestLedgerCloseTimestamp is the unix timestamp of when the oldest ledger was closed.
+	//
+	// Deprecated: use OldestLedgerCloseTime, this field will be removed in the next release.
+	OldestLedgerCloseTimestamp int64  `json:"oldestLedgerCloseTimestamp"`
+	Cursor                     string `json:"cursor"`
+}
+
+func newGetTransactionsResponse(txns []TransactionInfo, ledgerRange ledgerbucketwindow.LedgerRange, cursor *toid.ID) GetTransactionsResponse {
+	return GetTransactionsResponse{
+		Transactions:               txns,
+		LedgerRangeResponse:        NewLedgerRangeResponse(ledgerRange),
+		LatestLedgerCloseTimestamp: ledgerRange.LastLedger.CloseTime,
+		OldestLedgerCloseTimestamp: ledgerRange.FirstLedger.CloseTime,
+		Cursor:                     cursor.String(),
+	}
 }
 
 type transactionsRPCHandler struct {
@@ -113,6 +176,14 @@ func (h transactionsRPCHandler) getTransactionsByLedgerSequence(ctx context.Cont
 		}
 	}
 
+	xdrFields, err := newXDRFieldSelection(request.XDRFields)
+	if err != nil {
+		return GetTransactionsResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InvalidParams,
+			Message: err.Error(),
+		}
+	}
+
 	// Move start to pagination cursor
 	start := toid.New(int32(request.StartLedger), 1, 1)
 	limit := h.defaultLimit
@@ -136,6 +207,17 @@ func (h transactionsRPCHandler) getTransactionsByLedgerSequence(ctx context.Cont
 		}
 	}
 
+	if request.Filters != nil {
+		filter, err := request.Filters.dbFilter()
+		if err != nil {
+			return GetTransactionsResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: err.Error(),
+			}
+		}
+		return h.getFilteredTransactions(ctx, filter, start, limit, ledgerRange, xdrFields)
+	}
+
 	// Iterate through each ledger and its transactions until limit or end range is reached.
 	// The latest ledger acts as the end ledger range for the request.
 	var txns []TransactionInfo
@@ -194,7 +276,7 @@ LedgerLoop:
 				}
 			}
 
-			tx, err := db.ParseTransaction(ledger, ingestTx)
+			txInfo, err := transactionInfo(ledger, ingestTx, xdrFields)
 			if err != nil {
 				return GetTransactionsResponse{}, &jrpc2.Error{
 					Code:    jrpc2.InternalError,
@@ -202,21 +284,9 @@ LedgerLoop:
 				}
 			}
 
-			txInfo := TransactionInfo{
-				ApplicationOrder:    tx.ApplicationOrder,
-				FeeBump:             tx.FeeBump,
-				ResultXdr:           base64.StdEncoding.EncodeToString(tx.Result),
-				ResultMetaXdr:       base64.StdEncoding.EncodeToString(tx.Meta),
-				EnvelopeXdr:         base64.StdEncoding.EncodeToString(tx.Envelope),
-				DiagnosticEventsXDR: base64EncodeSlice(tx.Events),
-				Ledger:              tx.Ledger.Sequence,
-				LedgerCloseTime:     tx.Ledger.CloseTime,
+			if err := chargeResponseSize(ctx, txInfo.responseSize()); err != nil {
+				return GetTransactionsResponse{}, err
 			}
-			txInfo.Status = TransactionStatusFailed
-			if tx.Successful {
-				txInfo.Status = TransactionStatusSuccess
-			}
-
 			txns = append(txns, txInfo)
 			if len(txns) >= int(limit) {
 				break LedgerLoop
@@ -224,14 +294,115 @@ LedgerLoop:
 		}
 	}
 
-	return GetTransactionsResponse{
-		Transactions:          txns,
-		LatestLedger:          ledgerRange.LastLedger.Sequence,
-		LatestLedgerCloseTime: ledgerRange.LastLedger.CloseTime,
-		OldestLedger:          ledgerRange.FirstLedger.Sequence,
-		OldestLedgerCloseTime: ledgerRange.FirstLedger.CloseTime,
-		Cursor:                cursor.String(),
-	}, nil
+	return newGetTransactionsResponse(txns, ledgerRange, cursor), nil
+}
+
+func transactionInfo(ledger xdr.LedgerCloseMeta, ingestTx ingest.LedgerTransaction, xdrFields xdrFieldSelection) (TransactionInfo, error) {
+	tx, err := db.ParseTransaction(ledger, ingestTx)
+	if err != nil {
+		return TransactionInfo{}, err
+	}
+	txInfo := TransactionInfo{
+		ApplicationOrder: tx.ApplicationOrder,
+		FeeBump:          tx.FeeBump,
+		Ledger:           tx.Ledger.Sequence,
+		LedgerCloseTime:  tx.Ledger.CloseTime,
+	}
+	if xdrFields[XDRFieldResult] {
+		txInfo.ResultXdr = base64.StdEncoding.EncodeToString(tx.Result)
+	}
+	if xdrFields[XDRFieldResultMeta] {
+		txInfo.ResultMetaXdr = base64.StdEncoding.EncodeToString(tx.Meta)
+	}
+	if xdrFields[XDRFieldEnvelope] {
+		txInfo.EnvelopeXdr = base64.StdEncoding.EncodeToString(tx.Envelope)
+	}
+	if xdrFields[XDRFieldDiagnosticEvents] {
+		txInfo.DiagnosticEventsXDR = base64EncodeSlice(tx.Events)
+	}
+	txInfo.Status = TransactionStatusFailed
+	if tx.Successful {
+		txInfo.Status = TransactionStatusSuccess
+	}
+	return txInfo, nil
+}
+
+// getFilteredTransactions looks up the matching transactions in the transactions table
+// and only decodes the ledgers which include them.
+func (h transactionsRPCHandler) getFilteredTransactions(
+	ctx context.Context,
+	filter db.TransactionFilter,
+	start *toid.ID,
+	limit uint,
+	ledgerRange ledgerbucketwindow.LedgerRange,
+	xdrFields xdrFieldSelection,
+) (GetTransactionsResponse, error) {
+	positions, err := h.dbReader.GetTransactionPositions(ctx, filter, db.TransactionPosition{
+		LedgerSequence:   uint32(start.LedgerSequence),
+		ApplicationOrder: start.TransactionOrder,
+	}, limit)
+	if err != nil {
+		return GetTransactionsResponse{}, &jrpc2.Error{
+			Code:    jrpc2.InternalError,
+			Message: err.Error(),
+		}
+	}
+
+	// when nothing matches, the next page starts at the same position
+	cursor := toid.New(start.LedgerSequence, start.TransactionOrder-1, 1)
+	var txns []TransactionInfo
+	var ledger xdr.LedgerCloseMeta
+	for _, position := range positions {
+		if len(txns) == 0 || ledger.LedgerSequence() != position.LedgerSequence {
+			var found bool
+			ledger, found, err = h.ledgerReader.GetLedger(ctx, position.LedgerSequence)
+			if err != nil {
+				return GetTransactionsResponse{}, &jrpc2.Error{
+					Code:    jrpc2.InternalError,
+					Message: err.Error(),
+				}
+			} else if !found {
+				return GetTransactionsResponse{}, &jrpc2.Error{
+					Code:    jrpc2.InvalidParams,
+					Message: fmt.Sprintf("ledger close meta not found: %d", position.LedgerSequence),
+				}
+			}
+		}
+		reader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(h.networkPassphrase, ledger)
+		if err != nil {
+			return GetTransactionsResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		if err := reader.Seek(int(position.ApplicationOrder) - 1); err != nil {
+			return GetTransactionsResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		ingestTx, err := reader.Read()
+		if err != nil {
+			return GetTransactionsResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		txInfo, err := transactionInfo(ledger, ingestTx, xdrFields)
+		if err != nil {
+			return GetTransactionsResponse{}, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: err.Error(),
+			}
+		}
+		if err := chargeResponseSize(ctx, txInfo.responseSize()); err != nil {
+			return GetTransactionsResponse{}, err
+		}
+		txns = append(txns, txInfo)
+		cursor = toid.New(int32(position.LedgerSequence), position.ApplicationOrder, 1)
+	}
+
+	return newGetTransactionsResponse(txns, ledgerRange, cursor), nil
 }
 
 func NewGetTransactionsHandler(logger *log.Entry, ledgerReader db.LedgerReader, dbReader db.TransactionReader, maxLimit, defaultLimit uint, networkPassphrase string) jrpc2.Handler {
@@ -63,6 +63,9 @@ func TestGetTransactions_DefaultLimit(t *testing.T) {
 	// assert latest ledger details
 	assert.Equal(t, uint32(10), response.LatestLedger)
 	assert.Equal(t, int64(350), response.LatestLedgerCloseTime)
+	// the deprecated fields are still populated
+	assert.Equal(t, response.LatestLedgerCloseTime, response.LatestLedgerCloseTimestamp)
+	assert.Equal(t, response.OldestLedgerCloseTime, response.OldestLedgerCloseTimestamp)
 
 	// assert pagination
 	assert.Equal(t, toid.New(5, 2, 1).String(), response.Cursor)
@@ -304,3 +307,121 @@ func TestGetTransactions_InvalidCursorString(t *testing.T) {
 	expectedErr := fmt.Errorf("[%d] strconv.ParseInt: parsing \"abc\": invalid syntax", jrpc2.InvalidParams)
 	assert.Equal(t, expectedErr.Error(), err.Error())
 }
+
+func TestGetTransactions_Filters(t *testing.T) {
+	mockDbReader := db.NewMockTransactionStore(NetworkPassphrase)
+	mockLedgerReader := db.NewMockLedgerReader(mockDbReader)
+	for i := 1; i <= 10; i++ {
+		meta := createTestLedger(uint32(i))
+		err := mockDbReader.InsertTransactions(meta)
+		assert.NoError(t, err)
+	}
+
+	handler := transactionsRPCHandler{
+		ledgerReader:      mockLedgerReader,
+		dbReader:          mockDbReader,
+		maxLimit:          100,
+		defaultLimit:      10,
+		networkPassphrase: NetworkPassphrase,
+	}
+
+	request := GetTransactionsRequest{
+		StartLedger: 1,
+		Filters:     &TransactionsFilters{Status: TransactionStatusFailed},
+		Pagination:  &TransactionsPaginationOptions{Limit: 3},
+	}
+	response, err := handler.getTransactionsByLedgerSequence(context.TODO(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(10), response.LatestLedger)
+	assert.Equal(t, toid.New(3, 2, 1).String(), response.Cursor)
+	assert.Len(t, response.Transactions, 3)
+	for i, tx := range response.Transactions {
+		assert.Equal(t, TransactionStatusFailed, tx.Status)
+		assert.Equal(t, uint32(i+1), tx.Ledger)
+		assert.Equal(t, int32(2), tx.ApplicationOrder)
+	}
+
+	// next page
+	request = GetTransactionsRequest{
+		Filters:    &TransactionsFilters{Status: TransactionStatusFailed},
+		Pagination: &TransactionsPaginationOptions{Cursor: response.Cursor, Limit: 10},
+	}
+	response, err = handler.getTransactionsByLedgerSequence(context.TODO(), request)
+	assert.NoError(t, err)
+	assert.Len(t, response.Transactions, 7)
+	assert.Equal(t, uint32(4), response.Transactions[0].Ledger)
+	assert.Equal(t, toid.New(10, 2, 1).String(), response.Cursor)
+
+	// no matches, the cursor stays put
+	request = GetTransactionsRequest{
+		StartLedger: 5,
+		Filters:     &TransactionsFilters{SourceAccount: "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"},
+	}
+	response, err = handler.getTransactionsByLedgerSequence(context.TODO(), request)
+	assert.NoError(t, err)
+	assert.Empty(t, response.Transactions)
+	assert.Equal(t, toid.New(5, 0, 1).String(), response.Cursor)
+}
+
+func TestGetTransactions_InvalidFilters(t *testing.T) {
+	mockDbReader := db.NewMockTransactionStore(NetworkPassphrase)
+	mockLedgerReader := db.NewMockLedgerReader(mockDbReader)
+	assert.NoError(t, mockDbReader.InsertTransactions(createTestLedger(1)))
+
+	handler := transactionsRPCHandler{
+		ledgerReader:      mockLedgerReader,
+		dbReader:          mockDbReader,
+		maxLimit:          100,
+		defaultLimit:      10,
+		networkPassphrase: NetworkPassphrase,
+	}
+
+	for filters, expectedErr := range map[TransactionsFilters]string{
+		{Status: "PENDING"}:   "[-32602] status must be either SUCCESS or FAILED",
+		{SourceAccount: "GA"}: "[-32602] invalid source account",
+	} {
+		_, err := handler.getTransactionsByLedgerSequence(context.TODO(), GetTransactionsRequest{
+			StartLedger: 1,
+			Filters:     &filters,
+		})
+		assert.ErrorContains(t, err, expectedErr)
+	}
+}
+
+func TestGetTransactions_XDRFields(t *testing.T) {
+	mockDbReader := db.NewMockTransactionStore(NetworkPassphrase)
+	mockLedgerReader := db.NewMockLedgerReader(mockDbReader)
+	for i := 1; i <= 3; i++ {
+		assert.NoError(t, mockDbReader.InsertTransactions(createTestLedger(uint32(i))))
+	}
+
+	handler := transactionsRPCHandler{
+		ledgerReader:      mockLedgerReader,
+		dbReader:          mockDbReader,
+		maxLimit:          100,
+		defaultLimit:      10,
+		networkPassphrase: NetworkPassphrase,
+	}
+
+	for _, filters := range []*TransactionsFilters{nil, {Status: TransactionStatusSuccess}} {
+		response, err := handler.getTransactionsByLedgerSequence(context.TODO(), GetTransactionsRequest{
+			StartLedger: 1,
+			Filters:     filters,
+			XDRFields:   []string{XDRFieldResult},
+		})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, response.Transactions)
+		for _, tx := range response.Transactions {
+			assert.NotEmpty(t, tx.ResultXdr)
+			assert.Empty(t, tx.EnvelopeXdr)
+			assert.Empty(t, tx.ResultMetaXdr)
+			assert.Empty(t, tx.DiagnosticEventsXDR)
+		}
+	}
+
+	_, err := handler.getTransactionsByLedgerSequence(context.TODO(), GetTransactionsRequest{
+		StartLedger: 1,
+		XDRFields:   []string{"resultMetaXdr"},
+	})
+	assert.ErrorContains(t, err, `[-32602] unknown xdr field "resultMetaXdr"`)
+}
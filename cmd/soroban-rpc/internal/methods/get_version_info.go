@@ -20,9 +20,19 @@ type GetVersionInfoResponse struct {
 	BuildTimestamp     string `json:"build_time_stamp"`     //nolint:tagliatelle
 	CaptiveCoreVersion string `json:"captive_core_version"` //nolint:tagliatelle
 	ProtocolVersion    uint32 `json:"protocol_version"`     //nolint:tagliatelle
+	// Passphrase and FriendbotURL describe the network, like in getNetwork
+	Passphrase   string `json:"passphrase"`
+	FriendbotURL string `json:"friendbotUrl,omitempty"`
 }
 
-func NewGetVersionInfoHandler(logger *log.Entry, ledgerEntryReader db.LedgerEntryReader, ledgerReader db.LedgerReader, daemon interfaces.Daemon) jrpc2.Handler {
+func NewGetVersionInfoHandler(
+	logger *log.Entry,
+	ledgerEntryReader db.LedgerEntryReader,
+	ledgerReader db.LedgerReader,
+	daemon interfaces.Daemon,
+	networkPassphrase string,
+	friendbotURL string,
+) jrpc2.Handler {
 	coreClient := daemon.CoreClient()
 	return handler.New(func(ctx context.Context) (GetVersionInfoResponse, error) {
 		var captiveCoreVersion string
@@ -59,6 +69,8 @@ func NewGetVersionInfoHandler(logger *log.Entry, ledgerEntryReader db.LedgerEntr
 			BuildTimestamp:     config.BuildTimestamp,
 			CaptiveCoreVersion: captiveCoreVersion,
 			ProtocolVersion:    protocolVersion,
+			Passphrase:         networkPassphrase,
+			FriendbotURL:       friendbotURL,
 		}, nil
 	})
 }
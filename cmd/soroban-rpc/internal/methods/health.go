@@ -7,21 +7,69 @@ import (
 
 	"github.com/creachadair/jrpc2"
 
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/events"
 )
 
+// healthCoreInfoTimeout bounds the time spent obtaining the captive core state
+const healthCoreInfoTimeout = 2 * time.Second
+
+// DatabaseSizer reports the size of the database
+type DatabaseSizer interface {
+	Size(ctx context.Context) (uint64, error)
+}
+
 type HealthCheckResult struct {
-	Status                string `json:"status"`
-	LatestLedger          uint32 `json:"latestLedger"`
-	OldestLedger          uint32 `json:"oldestLedger"`
+	Status string `json:"status"`
+	LedgerRangeResponse
 	LedgerRetentionWindow uint32 `json:"ledgerRetentionWindow"`
+	// PreflightError is present when simulateTransaction is unavailable because the
+	// preflight library failed its self-test or doesn't support the network protocol.
+	PreflightError string `json:"preflightError,omitempty"`
+	// DatabaseSize is the size (in bytes) of the database
+	DatabaseSize uint64 `json:"databaseSize,omitempty"`
+	// IngestionLatency is the time (in seconds) elapsed since the latest ingested ledger closed
+	IngestionLatency int64 `json:"ingestionLatency"`
+	// CoreState is the state reported by captive core (e.g. "Synced!" or "Catching up")
+	CoreState string `json:"coreState,omitempty"`
+	// ProtocolVersion is the protocol version of the latest ledger
+	ProtocolVersion uint32 `json:"protocolVersion,omitempty"`
+	// ProtocolUpgrades reports the scheduled upgrades above the protocol version of the latest ledger
+	ProtocolUpgrades []ProtocolUpgradeStatus `json:"protocolUpgrades,omitempty"`
+	// Stores reports the ledgers held by each of the stores
+	Stores HealthCheckStores `json:"stores"`
+}
+
+// ProtocolUpgradeStatus is a scheduled protocol upgrade
+type ProtocolUpgradeStatus struct {
+	Version uint32 `json:"version"`
+	// Time is the scheduled time (in unix seconds) of the upgrade
+	Time int64 `json:"time"`
+	// Supported tells whether both the preflight library and captive core support the protocol,
+	// i.e. whether the node will keep working after the upgrade without being redeployed
+	Supported bool `json:"supported"`
+	// Error explains why the protocol isn't supported
+	Error string `json:"error,omitempty"`
+}
+
+type HealthCheckStores struct {
+	Ledgers LedgerRangeResponse  `json:"ledgers"`
+	Events  *LedgerRangeResponse `json:"events,omitempty"`
 }
 
 // NewHealthCheck returns a health check json rpc handler
 func NewHealthCheck(
 	retentionWindow uint32,
 	reader db.TransactionReader,
+	ledgerReader db.LedgerReader,
+	checker PreflightCompatibilityChecker,
 	maxHealthyLedgerLatency time.Duration,
+	daemon interfaces.Daemon,
+	eventStore *events.MemoryStore,
+	dbSizer DatabaseSizer,
+	protocolUpgrades []config.ProtocolUpgrade,
 ) jrpc2.Handler {
 	return NewHandler(func(ctx context.Context) (HealthCheckResult, error) {
 		ledgerRange, err := reader.GetLedgerRange(ctx)
@@ -49,10 +97,69 @@ func NewHealthCheck(
 		}
 		result := HealthCheckResult{
 			Status:                "healthy",
-			LatestLedger:          ledgerRange.LastLedger.Sequence,
-			OldestLedger:          ledgerRange.FirstLedger.Sequence,
+			LedgerRangeResponse:   NewLedgerRangeResponse(ledgerRange),
 			LedgerRetentionWindow: retentionWindow,
+			IngestionLatency:      int64(lastKnownLedgerLatency.Seconds()),
+			Stores: HealthCheckStores{
+				Ledgers: NewLedgerRangeResponse(ledgerRange),
+			},
+		}
+		_, protocolVersion, err := getBucketListSizeAndProtocolVersion(ctx, ledgerReader, ledgerRange.LastLedger.Sequence)
+		if err == nil {
+			result.ProtocolVersion = protocolVersion
+			if err := checker.CheckCompatibility(protocolVersion); err != nil {
+				result.PreflightError = err.Error()
+			}
+		}
+		if eventStore != nil {
+			if eventRange, err := eventStore.GetLedgerRange(); err == nil {
+				eventRangeResponse := NewLedgerRangeResponse(eventRange)
+				result.Stores.Events = &eventRangeResponse
+			}
+		}
+		if dbSizer != nil {
+			if size, err := dbSizer.Size(ctx); err == nil {
+				result.DatabaseSize = size
+			}
+		}
+		// the maximum protocol version supported by captive core, 0 if unknown
+		var coreMaxProtocolVersion uint32
+		if daemon != nil {
+			coreCtx, cancel := context.WithTimeout(ctx, healthCoreInfoTimeout)
+			defer cancel()
+			if info, err := daemon.CoreClient().Info(coreCtx); err == nil {
+				result.CoreState = info.Info.State
+				if info.Info.ProtocolVersion > 0 {
+					coreMaxProtocolVersion = uint32(info.Info.ProtocolVersion)
+				}
+			}
+		}
+		for _, upgrade := range protocolUpgrades {
+			if upgrade.Version <= result.ProtocolVersion {
+				// the upgrade already took place
+				continue
+			}
+			result.ProtocolUpgrades = append(result.ProtocolUpgrades,
+				newProtocolUpgradeStatus(upgrade, checker, coreMaxProtocolVersion))
 		}
 		return result, nil
 	})
 }
+
+func newProtocolUpgradeStatus(
+	upgrade config.ProtocolUpgrade, checker PreflightCompatibilityChecker, coreMaxProtocolVersion uint32,
+) ProtocolUpgradeStatus {
+	status := ProtocolUpgradeStatus{
+		Version:   upgrade.Version,
+		Time:      upgrade.Time.Unix(),
+		Supported: true,
+	}
+	if err := checker.CheckCompatibility(upgrade.Version); err != nil {
+		status.Supported = false
+		status.Error = err.Error()
+	} else if coreMaxProtocolVersion > 0 && upgrade.Version > coreMaxProtocolVersion {
+		status.Supported = false
+		status.Error = fmt.Sprintf("captive core only supports protocol versions up to %d", coreMaxProtocolVersion)
+	}
+	return status
+}
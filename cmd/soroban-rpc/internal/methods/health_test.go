@@ -0,0 +1,145 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	proto "github.com/stellar/go/protocols/stellarcore"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/events"
+)
+
+type staticDatabaseSizer uint64
+
+func (s staticDatabaseSizer) Size(context.Context) (uint64, error) {
+	return uint64(s), nil
+}
+
+type compatiblePreflightChecker struct{}
+
+func (compatiblePreflightChecker) CheckCompatibility(uint32) error {
+	return nil
+}
+
+// maxProtocolPreflightChecker only supports protocol versions up to its value
+type maxProtocolPreflightChecker uint32
+
+func (c maxProtocolPreflightChecker) CheckCompatibility(protocolVersion uint32) error {
+	if protocolVersion > uint32(c) {
+		return fmt.Errorf("unsupported protocol version %d", protocolVersion)
+	}
+	return nil
+}
+
+type syncedCoreClient struct {
+	interfaces.CoreClient
+}
+
+func (syncedCoreClient) Info(context.Context) (*proto.InfoResponse, error) {
+	var info proto.InfoResponse
+	info.Info.State = "Synced!"
+	info.Info.ProtocolVersion = 22
+	return &info, nil
+}
+
+type syncedCoreDaemon struct {
+	*interfaces.NoOpDaemon
+}
+
+func (syncedCoreDaemon) CoreClient() interfaces.CoreClient {
+	return syncedCoreClient{}
+}
+
+func TestGetHealthDiagnostics(t *testing.T) {
+	daemon := syncedCoreDaemon{NoOpDaemon: interfaces.MakeNoOpDeamon()}
+	store := db.NewMockTransactionStore("passphrase")
+	eventStore := events.NewMemoryStore(daemon, "passphrase", 10)
+	closeTime := time.Now().Add(-3 * time.Second).Unix()
+	for seq := uint32(10); seq <= 12; seq++ {
+		lcm := ledgerCloseMetaWithEvents(seq, closeTime)
+		lcm.V1.LedgerHeader.Header.LedgerVersion = 21
+		require.NoError(t, store.InsertTransactions(lcm))
+		if seq > 10 {
+			require.NoError(t, eventStore.IngestEvents(lcm))
+		}
+	}
+
+	handler := NewHealthCheck(
+		100, store, db.NewMockLedgerReader(store), compatiblePreflightChecker{}, time.Minute,
+		daemon, eventStore, staticDatabaseSizer(4096), nil,
+	)
+	request, err := jrpc2.ParseRequests([]byte(`{"jsonrpc": "2.0", "id": 1, "method": "getHealth"}`))
+	require.NoError(t, err)
+	response, err := handler(context.Background(), request[0].ToRequest())
+	require.NoError(t, err)
+	result, ok := response.(HealthCheckResult)
+	require.True(t, ok)
+
+	assert.Equal(t, "healthy", result.Status)
+	assert.Equal(t, uint64(4096), result.DatabaseSize)
+	assert.GreaterOrEqual(t, result.IngestionLatency, int64(3))
+	assert.Equal(t, "Synced!", result.CoreState)
+	assert.Equal(t, uint32(21), result.ProtocolVersion)
+	assert.Equal(t, uint32(10), result.Stores.Ledgers.OldestLedger)
+	assert.Equal(t, uint32(12), result.Stores.Ledgers.LatestLedger)
+	require.NotNil(t, result.Stores.Events)
+	assert.Equal(t, uint32(11), result.Stores.Events.OldestLedger)
+	assert.Equal(t, uint32(12), result.Stores.Events.LatestLedger)
+
+	encoded, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"stores":{"ledgers":{"latestLedger":12`)
+}
+
+func TestGetHealthProtocolUpgrades(t *testing.T) {
+	daemon := syncedCoreDaemon{NoOpDaemon: interfaces.MakeNoOpDeamon()}
+	store := db.NewMockTransactionStore("passphrase")
+	lcm := ledgerCloseMetaWithEvents(10, time.Now().Unix())
+	lcm.V1.LedgerHeader.Header.LedgerVersion = 21
+	require.NoError(t, store.InsertTransactions(lcm))
+
+	upgradeTime := time.Date(2024, 9, 1, 17, 0, 0, 0, time.UTC)
+	upgrades := []config.ProtocolUpgrade{
+		{Version: 21, Time: upgradeTime.Add(-90 * 24 * time.Hour)},
+		{Version: 22, Time: upgradeTime},
+		{Version: 23, Time: upgradeTime.Add(90 * 24 * time.Hour)},
+		{Version: 24, Time: upgradeTime.Add(180 * 24 * time.Hour)},
+	}
+	handler := NewHealthCheck(
+		100, store, db.NewMockLedgerReader(store), maxProtocolPreflightChecker(23), time.Minute,
+		daemon, nil, nil, upgrades,
+	)
+	request, err := jrpc2.ParseRequests([]byte(`{"jsonrpc": "2.0", "id": 1, "method": "getHealth"}`))
+	require.NoError(t, err)
+	response, err := handler(context.Background(), request[0].ToRequest())
+	require.NoError(t, err)
+	result, ok := response.(HealthCheckResult)
+	require.True(t, ok)
+
+	assert.Equal(t, uint32(21), result.ProtocolVersion)
+	assert.Empty(t, result.PreflightError)
+	// the upgrade to protocol 21 already took place
+	assert.Equal(t, []ProtocolUpgradeStatus{
+		{Version: 22, Time: upgradeTime.Unix(), Supported: true},
+		{
+			Version: 23,
+			Time:    upgrades[2].Time.Unix(),
+			Error:   "captive core only supports protocol versions up to 22",
+		},
+		{
+			Version: 24,
+			Time:    upgrades[3].Time.Unix(),
+			Error:   "unsupported protocol version 24",
+		},
+	}, result.ProtocolUpgrades)
+}
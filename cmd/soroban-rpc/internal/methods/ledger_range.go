@@ -0,0 +1,156 @@
+package methods
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+)
+
+// LedgerRangeResponse is the envelope shared by all the read methods. It
+// reports the range of ledgers known to Soroban-RPC at the time the request
+// was handled, so that clients can uniformly detect stale data.
+type LedgerRangeResponse struct {
+	// LatestLedger is the latest ledger stored in Soroban-RPC.
+	LatestLedger uint32 `json:"latestLedger"`
+	// LatestLedgerCloseTime is the unix timestamp of when the latest ledger was closed.
+	LatestLedgerCloseTime int64 `json:"latestLedgerCloseTime,string"`
+	// OldestLedger is the oldest ledger stored in Soroban-RPC.
+	OldestLedger uint32 `json:"oldestLedger"`
+	// OldestLedgerCloseTime is the unix timestamp of when the oldest ledger was closed.
+	OldestLedgerCloseTime int64 `json:"oldestLedgerCloseTime,string"`
+}
+
+// NewLedgerRangeResponse builds the envelope out of a ledger range.
+func NewLedgerRangeResponse(ledgerRange ledgerbucketwindow.LedgerRange) LedgerRangeResponse {
+	return LedgerRangeResponse{
+		LatestLedger:          ledgerRange.LastLedger.Sequence,
+		LatestLedgerCloseTime: ledgerRange.LastLedger.CloseTime,
+		OldestLedger:          ledgerRange.FirstLedger.Sequence,
+		OldestLedgerCloseTime: ledgerRange.FirstLedger.CloseTime,
+	}
+}
+
+func (r *LedgerRangeResponse) ledgerRangeResponse() *LedgerRangeResponse {
+	return r
+}
+
+func (r *LedgerRangeResponse) complete() bool {
+	return r.LatestLedger != 0 && r.LatestLedgerCloseTime != 0 &&
+		r.OldestLedger != 0 && r.OldestLedgerCloseTime != 0
+}
+
+// fill populates the fields which weren't already set by the handler.
+func (r *LedgerRangeResponse) fill(ledgerRange ledgerbucketwindow.LedgerRange) {
+	if r.LatestLedger == 0 {
+		r.LatestLedger = ledgerRange.LastLedger.Sequence
+	}
+	if r.LatestLedgerCloseTime == 0 && r.LatestLedger == ledgerRange.LastLedger.Sequence {
+		r.LatestLedgerCloseTime = ledgerRange.LastLedger.CloseTime
+	}
+	if r.OldestLedger == 0 {
+		r.OldestLedger = ledgerRange.FirstLedger.Sequence
+	}
+	if r.OldestLedgerCloseTime == 0 && r.OldestLedger == ledgerRange.FirstLedger.Sequence {
+		r.OldestLedgerCloseTime = ledgerRange.FirstLedger.CloseTime
+	}
+}
+
+type ledgerRangeEnvelope interface {
+	ledgerRangeResponse() *LedgerRangeResponse
+}
+
+// LedgerRangeCache caches the ledger range stored in the database, so that
+// filling in the envelope of a response doesn't require decoding the oldest
+// and latest ledgers on every request. The cached range is refreshed once the
+// handlers report a newer latest ledger.
+type LedgerRangeCache struct {
+	reader      db.LedgerReader
+	lock        sync.RWMutex
+	ledgerRange ledgerbucketwindow.LedgerRange
+}
+
+// NewLedgerRangeCache creates a LedgerRangeCache on top of the given reader.
+func NewLedgerRangeCache(reader db.LedgerReader) *LedgerRangeCache {
+	return &LedgerRangeCache{reader: reader}
+}
+
+// rangeEndingAt returns the ledger range whose latest ledger is latestLedger
+// (or the current ledger range if latestLedger is 0).
+func (c *LedgerRangeCache) rangeEndingAt(ctx context.Context, latestLedger uint32) (ledgerbucketwindow.LedgerRange, error) {
+	c.lock.RLock()
+	ledgerRange := c.ledgerRange
+	c.lock.RUnlock()
+	if latestLedger != 0 && latestLedger == ledgerRange.LastLedger.Sequence {
+		return ledgerRange, nil
+	}
+
+	if latestLedger == 0 || latestLedger > ledgerRange.LastLedger.Sequence {
+		freshRange, err := c.reader.GetLedgerRange(ctx)
+		if err != nil {
+			return ledgerbucketwindow.LedgerRange{}, err
+		}
+		c.lock.Lock()
+		if freshRange.LastLedger.Sequence > c.ledgerRange.LastLedger.Sequence {
+			c.ledgerRange = freshRange
+		}
+		c.lock.Unlock()
+		ledgerRange = freshRange
+	}
+	if latestLedger == 0 || latestLedger == ledgerRange.LastLedger.Sequence {
+		return ledgerRange, nil
+	}
+
+	// Ingestion moved on between the handler reading its latest ledger and
+	// the range being read, so we fetch the close time of the handler's ledger
+	// to keep the envelope consistent.
+	ledger, found, err := c.reader.GetLedger(ctx, latestLedger)
+	if err != nil {
+		return ledgerbucketwindow.LedgerRange{}, err
+	}
+	ledgerRange.LastLedger = ledgerbucketwindow.LedgerInfo{Sequence: latestLedger}
+	if found {
+		ledgerRange.LastLedger.CloseTime = ledger.LedgerCloseTime()
+	}
+	return ledgerRange, nil
+}
+
+// WithLedgerRange decorates a handler so that, if its response embeds
+// LedgerRangeResponse, any of the envelope fields which the handler didn't
+// populate itself are filled in from the (cached) ledger range stored in the
+// database.
+func WithLedgerRange(cache *LedgerRangeCache, handler jrpc2.Handler) jrpc2.Handler {
+	return func(ctx context.Context, request *jrpc2.Request) (interface{}, error) {
+		result, err := handler(ctx, request)
+		if err != nil || result == nil {
+			return result, err
+		}
+
+		// Responses are returned by value, so we need an addressable copy in
+		// order to reach the (pointer) methods of the embedded envelope.
+		value := reflect.New(reflect.TypeOf(result))
+		value.Elem().Set(reflect.ValueOf(result))
+		envelope, ok := value.Interface().(ledgerRangeEnvelope)
+		if !ok {
+			return result, nil
+		}
+		response := envelope.ledgerRangeResponse()
+		if response.complete() {
+			return result, nil
+		}
+
+		ledgerRange, err := cache.rangeEndingAt(ctx, response.LatestLedger)
+		if err != nil {
+			return nil, &jrpc2.Error{
+				Code:    jrpc2.InternalError,
+				Message: "could not get ledger range",
+			}
+		}
+		response.fill(ledgerRange)
+		return value.Elem().Interface(), nil
+	}
+}
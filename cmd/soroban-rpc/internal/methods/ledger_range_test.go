@@ -0,0 +1,121 @@
+package methods
+
+import (
+	"context"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/ledgerbucketwindow"
+)
+
+func TestWithLedgerRangeFillsMissingFields(t *testing.T) {
+	handler := WithLedgerRange(NewLedgerRangeCache(&ConstantLedgerReader{}), NewGetLatestLedgerHandler(&ConstantLedgerEntryReader{}, &ConstantLedgerReader{}))
+	resultI, err := handler(context.Background(), &jrpc2.Request{})
+	require.NoError(t, err)
+	result := resultI.(GetLatestLedgerResponse)
+
+	assert.Equal(t, LedgerRangeResponse{
+		LatestLedger:          expectedLatestLedgerSequence,
+		LatestLedgerCloseTime: 4800,
+		OldestLedger:          1,
+		OldestLedgerCloseTime: 5,
+	}, result.LedgerRangeResponse)
+	assert.Equal(t, expectedLatestLedgerSequence, result.Sequence)
+}
+
+func TestWithLedgerRangeKeepsHandlerFields(t *testing.T) {
+	handler := WithLedgerRange(NewLedgerRangeCache(&ConstantLedgerReader{}), NewHandler(func(ctx context.Context) (GetEventsResponse, error) {
+		return GetEventsResponse{
+			Events:              []EventInfo{},
+			LedgerRangeResponse: LedgerRangeResponse{LatestLedger: 42},
+		}, nil
+	}))
+	resultI, err := handler(context.Background(), &jrpc2.Request{})
+	require.NoError(t, err)
+	result := resultI.(GetEventsResponse)
+
+	// the latest ledger reported by the handler isn't the latest stored
+	// ledger, so its close time is read from the ledger itself
+	assert.Equal(t, LedgerRangeResponse{
+		LatestLedger:          42,
+		LatestLedgerCloseTime: 4800,
+		OldestLedger:          1,
+		OldestLedgerCloseTime: 5,
+	}, result.LedgerRangeResponse)
+}
+
+type countingLedgerReader struct {
+	ConstantLedgerReader
+	ledgerRange      ledgerbucketwindow.LedgerRange
+	ledgerRangeReads int
+	ledgerReads      int
+}
+
+func (r *countingLedgerReader) GetLedger(ctx context.Context, sequence uint32) (xdr.LedgerCloseMeta, bool, error) {
+	r.ledgerReads++
+	return r.ConstantLedgerReader.GetLedger(ctx, sequence)
+}
+
+func (r *countingLedgerReader) GetLedgerRange(ctx context.Context) (ledgerbucketwindow.LedgerRange, error) {
+	r.ledgerRangeReads++
+	return r.ledgerRange, nil
+}
+
+func TestWithLedgerRangeCachesLedgerRange(t *testing.T) {
+	reader := &countingLedgerReader{
+		ledgerRange: ledgerbucketwindow.LedgerRange{
+			FirstLedger: ledgerbucketwindow.LedgerInfo{Sequence: 1, CloseTime: 5},
+			LastLedger:  ledgerbucketwindow.LedgerInfo{Sequence: 10, CloseTime: 50},
+		},
+	}
+	latestLedger := uint32(10)
+	handler := WithLedgerRange(NewLedgerRangeCache(reader), NewHandler(func(ctx context.Context) (GetEventsResponse, error) {
+		return GetEventsResponse{LedgerRangeResponse: LedgerRangeResponse{LatestLedger: latestLedger}}, nil
+	}))
+	call := func() LedgerRangeResponse {
+		resultI, err := handler(context.Background(), &jrpc2.Request{})
+		require.NoError(t, err)
+		return resultI.(GetEventsResponse).LedgerRangeResponse
+	}
+
+	expected := LedgerRangeResponse{
+		LatestLedger:          10,
+		LatestLedgerCloseTime: 50,
+		OldestLedger:          1,
+		OldestLedgerCloseTime: 5,
+	}
+	assert.Equal(t, expected, call())
+	assert.Equal(t, expected, call())
+	assert.Equal(t, 1, reader.ledgerRangeReads)
+
+	// a new ledger refreshes the cached range
+	reader.ledgerRange.LastLedger = ledgerbucketwindow.LedgerInfo{Sequence: 11, CloseTime: 55}
+	latestLedger = 11
+	expected.LatestLedger, expected.LatestLedgerCloseTime = 11, 55
+	assert.Equal(t, expected, call())
+	assert.Equal(t, expected, call())
+	assert.Equal(t, 2, reader.ledgerRangeReads)
+	assert.Zero(t, reader.ledgerReads)
+
+	// ingestion moved on after the handler read its latest ledger
+	reader.ledgerRange.LastLedger = ledgerbucketwindow.LedgerInfo{Sequence: 13, CloseTime: 65}
+	latestLedger = 12
+	expected.LatestLedger, expected.LatestLedgerCloseTime = 12, 4800
+	assert.Equal(t, expected, call())
+	assert.Equal(t, 3, reader.ledgerRangeReads)
+	assert.Equal(t, 1, reader.ledgerReads)
+}
+
+func TestWithLedgerRangeIgnoresOtherResponses(t *testing.T) {
+	handler := WithLedgerRange(NewLedgerRangeCache(&ConstantLedgerReader{}), NewHandler(func(ctx context.Context) (GetNetworkResponse, error) {
+		return GetNetworkResponse{Passphrase: "foo"}, nil
+	}))
+	resultI, err := handler(context.Background(), &jrpc2.Request{})
+	require.NoError(t, err)
+	assert.Equal(t, GetNetworkResponse{Passphrase: "foo"}, resultI)
+}
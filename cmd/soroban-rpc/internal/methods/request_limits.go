@@ -0,0 +1,56 @@
+package methods
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/creachadair/jrpc2"
+)
+
+// RequestLimits are the configurable limits on the parameters of requests.
+// A zero limit means unlimited.
+type RequestLimits struct {
+	// MaxEventsLedgerRange is the maximum width of the ledger range scanned by getEvents
+	MaxEventsLedgerRange uint32
+	// MaxEventFilters is the maximum number of filters in a getEvents request
+	MaxEventFilters uint
+	// MaxEventContractIDs is the maximum number of contract ids in a getEvents filter
+	MaxEventContractIDs uint
+	// MaxEventTopics is the maximum number of topics in a getEvents filter
+	MaxEventTopics uint
+	// MaxLedgerEntriesKeys is the maximum number of keys in a getLedgerEntries request
+	MaxLedgerEntriesKeys uint
+}
+
+// DefaultRequestLimits returns the limits historically enforced by Soroban-RPC.
+func DefaultRequestLimits() RequestLimits {
+	return RequestLimits{
+		MaxEventFilters:      5,
+		MaxEventContractIDs:  5,
+		MaxEventTopics:       5,
+		MaxLedgerEntriesKeys: 200,
+	}
+}
+
+// checkLimit verifies that count doesn't exceed limit, so that all the
+// request limits are reported consistently.
+func checkLimit(name string, count int, limit uint) error {
+	if limit == 0 || count <= int(limit) {
+		return nil
+	}
+	return fmt.Errorf("%s (%d) exceeds the maximum allowed (%d)", name, count, limit)
+}
+
+// WithParamsSizeLimit decorates a handler so that requests whose (JSON
+// encoded) parameters are larger than maxSize bytes are rejected.
+func WithParamsSizeLimit(maxSize uint, handler jrpc2.Handler) jrpc2.Handler {
+	return func(ctx context.Context, request *jrpc2.Request) (interface{}, error) {
+		if err := checkLimit("params size", len(request.ParamString()), maxSize); err != nil {
+			return nil, &jrpc2.Error{
+				Code:    jrpc2.InvalidParams,
+				Message: err.Error(),
+			}
+		}
+		return handler(ctx, request)
+	}
+}
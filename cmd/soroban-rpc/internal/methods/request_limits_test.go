@@ -0,0 +1,58 @@
+package methods
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/events"
+)
+
+func TestCheckLimit(t *testing.T) {
+	require.NoError(t, checkLimit("key count", 10, 0))
+	require.NoError(t, checkLimit("key count", 10, 10))
+	require.EqualError(t, checkLimit("key count", 11, 10), "key count (11) exceeds the maximum allowed (10)")
+}
+
+func TestWithParamsSizeLimit(t *testing.T) {
+	handler := WithParamsSizeLimit(20, func(context.Context, *jrpc2.Request) (interface{}, error) {
+		return "ok", nil
+	})
+	parse := func(msg string) *jrpc2.Request {
+		requests, err := jrpc2.ParseRequests([]byte(msg))
+		require.NoError(t, err)
+		return requests[0].ToRequest()
+	}
+
+	result, err := handler(context.Background(), parse(`{"jsonrpc":"2.0","id":1,"method":"m","params":{"a":1}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "ok", result)
+
+	_, err = handler(context.Background(), parse(`{"jsonrpc":"2.0","id":1,"method":"m","params":{"a":"0123456789abc"}}`))
+	require.EqualError(t, err, "[-32602] params size (21) exceeds the maximum allowed (20)")
+}
+
+func TestGetEventsLedgerRangeLimit(t *testing.T) {
+	store := events.NewMemoryStore(interfaces.MakeNoOpDeamon(), "unit-tests", 100)
+	for ledger := uint32(1); ledger <= 10; ledger++ {
+		require.NoError(t, store.IngestEvents(ledgerCloseMetaWithEvents(ledger, time.Now().Unix())))
+	}
+	limits := DefaultRequestLimits()
+	limits.MaxEventsLedgerRange = 5
+	handler := eventsRPCHandler{
+		scanner:      store,
+		maxLimit:     10000,
+		defaultLimit: 100,
+		limits:       limits,
+	}
+
+	_, err := handler.getEvents(context.Background(), GetEventsRequest{StartLedger: 6})
+	require.NoError(t, err)
+	_, err = handler.getEvents(context.Background(), GetEventsRequest{StartLedger: 5})
+	require.EqualError(t, err, "[-32602] ledger range (6) exceeds the maximum allowed (5)")
+}
@@ -0,0 +1,92 @@
+package methods
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ResultTooLargeCode is the JSON RPC error code returned when the result of a request
+// exceeds the maximum response size.
+const ResultTooLargeCode = -32008
+
+// resultItemOverhead approximates the size taken by the fixed-size fields of a result item
+// (numbers, flags, JSON field names), in addition to its variable-size strings
+const resultItemOverhead = 256
+
+type responseBudgetKey struct{}
+
+// responseBudget accounts for the size of the result materialized by a request
+type responseBudget struct {
+	maxSize uint
+	size    uint
+}
+
+// ResultTooLargeError is returned by the handlers whose result exceeds the maximum response size
+type ResultTooLargeError struct {
+	MaxSize uint
+}
+
+func (e ResultTooLargeError) Error() string {
+	return fmt.Sprintf("result too large (exceeds %d bytes), narrow your query", e.MaxSize)
+}
+
+// WithResponseSizeLimit decorates a handler so that the result it materializes is capped to
+// maxSize bytes. The handlers account for the size of their results as they build them (see
+// chargeResponseSize), failing as soon as the limit is exceeded. A zero maxSize means unlimited.
+func WithResponseSizeLimit(maxSize uint, tooLargeCounter prometheus.Counter, handler jrpc2.Handler) jrpc2.Handler {
+	if maxSize == 0 {
+		return handler
+	}
+	return func(ctx context.Context, request *jrpc2.Request) (interface{}, error) {
+		ctx = context.WithValue(ctx, responseBudgetKey{}, &responseBudget{maxSize: maxSize})
+		result, err := handler(ctx, request)
+		var tooLarge ResultTooLargeError
+		if errors.As(err, &tooLarge) {
+			tooLargeCounter.Inc()
+			return nil, &jrpc2.Error{
+				Code:    ResultTooLargeCode,
+				Message: tooLarge.Error(),
+			}
+		}
+		return result, err
+	}
+}
+
+// chargeResponseSize adds size bytes to the result of the request, returning a ResultTooLargeError
+// once the maximum response size of the request is exceeded
+func chargeResponseSize(ctx context.Context, size int) error {
+	budget, ok := ctx.Value(responseBudgetKey{}).(*responseBudget)
+	if !ok {
+		return nil
+	}
+	budget.size += uint(size)
+	if budget.size > budget.maxSize {
+		return ResultTooLargeError{MaxSize: budget.maxSize}
+	}
+	return nil
+}
+
+func (e EventInfo) responseSize() int {
+	size := resultItemOverhead + len(e.Value) + len(e.ContractID) + len(e.ID) + len(e.PagingToken) +
+		len(e.TransactionHash) + len(e.LedgerClosedAt)
+	for _, topic := range e.Topic {
+		size += len(topic)
+	}
+	return size
+}
+
+func (r LedgerEntryResult) responseSize() int {
+	return resultItemOverhead + len(r.Key) + len(r.XDR) + len(r.ArchivalStatus)
+}
+
+func (t TransactionInfo) responseSize() int {
+	size := resultItemOverhead + len(t.EnvelopeXdr) + len(t.ResultXdr) + len(t.ResultMetaXdr)
+	for _, event := range t.DiagnosticEventsXDR {
+		size += len(event)
+	}
+	return size
+}
@@ -0,0 +1,75 @@
+package methods
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/events"
+)
+
+func TestChargeResponseSize(t *testing.T) {
+	// without a limit, nothing is accounted
+	require.NoError(t, chargeResponseSize(context.Background(), 1<<40))
+
+	ctx := context.WithValue(context.Background(), responseBudgetKey{}, &responseBudget{maxSize: 100})
+	require.NoError(t, chargeResponseSize(ctx, 60))
+	require.NoError(t, chargeResponseSize(ctx, 40))
+	require.Equal(t, ResultTooLargeError{MaxSize: 100}, chargeResponseSize(ctx, 1))
+}
+
+func TestWithResponseSizeLimit(t *testing.T) {
+	store := events.NewMemoryStore(interfaces.MakeNoOpDeamon(), "unit-tests", 100)
+	counter := xdr.ScSymbol("COUNTER")
+	for ledger := uint32(1); ledger <= 10; ledger++ {
+		txMeta := transactionMetaWithEvents(contractEvent(
+			xdr.Hash{},
+			xdr.ScVec{xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &counter}},
+			xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &counter},
+		))
+		require.NoError(t, store.IngestEvents(ledgerCloseMetaWithEvents(ledger, time.Now().Unix(), txMeta)))
+	}
+	tooLargeCounter := prometheus.NewCounter(prometheus.CounterOpts{Name: "result_too_large_requests"})
+	handler := WithResponseSizeLimit(
+		5*resultItemOverhead,
+		tooLargeCounter,
+		NewGetEventsHandler(store, 10000, 100, DefaultRequestLimits()),
+	)
+	getEvents := func(params string) (interface{}, error) {
+		requests, err := jrpc2.ParseRequests([]byte(`{"jsonrpc":"2.0","id":1,"method":"getEvents","params":` + params + `}`))
+		require.NoError(t, err)
+		return handler(context.Background(), requests[0].ToRequest())
+	}
+
+	result, err := getEvents(`{"startLedger":1,"pagination":{"limit":2}}`)
+	require.NoError(t, err)
+	assert.Len(t, result.(GetEventsResponse).Events, 2)
+	assert.Equal(t, 0.0, testutil.ToFloat64(tooLargeCounter))
+
+	_, err = getEvents(`{"startLedger":1}`)
+	require.Error(t, err)
+	jsonRPCErr, ok := err.(*jrpc2.Error)
+	require.True(t, ok)
+	assert.Equal(t, jrpc2.Code(ResultTooLargeCode), jsonRPCErr.Code)
+	assert.Equal(t, "result too large (exceeds 1280 bytes), narrow your query", jsonRPCErr.Message)
+	assert.Equal(t, 1.0, testutil.ToFloat64(tooLargeCounter))
+
+	// the budget is per request
+	_, err = getEvents(`{"startLedger":1,"pagination":{"limit":2}}`)
+	require.NoError(t, err)
+
+	// a zero limit means unlimited
+	handler = WithResponseSizeLimit(0, tooLargeCounter, NewGetEventsHandler(store, 10000, 100, DefaultRequestLimits()))
+	result, err = getEvents(`{"startLedger":1}`)
+	require.NoError(t, err)
+	assert.Len(t, result.(GetEventsResponse).Events, 10)
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/gossip"
 )
 
 // SendTransactionResponse represents the transaction submission response returned Soroban-RPC
@@ -24,6 +25,9 @@ type SendTransactionResponse struct {
 	// DiagnosticEventsXDR is present only if Status is equal to proto.TXStatusError.
 	// DiagnosticEventsXDR is a base64-encoded slice of xdr.DiagnosticEvent
 	DiagnosticEventsXDR []string `json:"diagnosticEventsXdr,omitempty"`
+	// ErrorDiagnostics is present only if Status is equal to proto.TXStatusError.
+	// ErrorDiagnostics is a human-readable explanation of ErrorResultXDR and DiagnosticEventsXDR.
+	ErrorDiagnostics *TransactionErrorDiagnostics `json:"errorDiagnostics,omitempty"`
 	// Status represents the status of the transaction submission returned by stellar-core.
 	// Status can be one of: proto.TXStatusPending, proto.TXStatusDuplicate,
 	// proto.TXStatusTryAgainLater, or proto.TXStatusError.
@@ -50,6 +54,8 @@ func NewSendTransactionHandler(
 	daemon interfaces.Daemon,
 	logger *log.Entry,
 	reader db.TransactionReader,
+	hints TransactionHints,
+	submitted *SubmittedTransactions,
 	passphrase string,
 ) jrpc2.Handler {
 	submitter := daemon.CoreClient()
@@ -104,6 +110,7 @@ func NewSendTransactionHandler(
 
 		switch resp.Status {
 		case proto.TXStatusError:
+			hints.Publish(submissionHint(txHash, resp.Status))
 			events, err := proto.DiagnosticEventsToSlice(resp.DiagnosticEvents)
 			if err != nil {
 				logger.WithField("tx", request.Transaction).Error("Cannot decode diagnostic events:", err)
@@ -115,12 +122,17 @@ func NewSendTransactionHandler(
 			return SendTransactionResponse{
 				ErrorResultXDR:        resp.Error,
 				DiagnosticEventsXDR:   events,
+				ErrorDiagnostics:      errorDiagnostics(logger, resp),
 				Status:                resp.Status,
 				Hash:                  txHash,
 				LatestLedger:          latestLedgerInfo.Sequence,
 				LatestLedgerCloseTime: latestLedgerInfo.CloseTime,
 			}, nil
 		case proto.TXStatusPending, proto.TXStatusDuplicate, proto.TXStatusTryAgainLater:
+			hints.Publish(submissionHint(txHash, resp.Status))
+			if submitted != nil {
+				submitted.add(txHash, request.Transaction)
+			}
 			return SendTransactionResponse{
 				Status:                resp.Status,
 				Hash:                  txHash,
@@ -137,3 +149,28 @@ func NewSendTransactionHandler(
 		}
 	})
 }
+
+// errorDiagnostics explains the error of a rejected submission. It's best effort,
+// the raw XDR is returned regardless.
+func errorDiagnostics(logger *log.Entry, resp *proto.TXResponse) *TransactionErrorDiagnostics {
+	var result xdr.TransactionResult
+	if err := xdr.SafeUnmarshalBase64(resp.Error, &result); err != nil {
+		logger.WithError(err).WithField("result", resp.Error).Warn("cannot decode the error result of a submission")
+		return nil
+	}
+	events, err := proto.DecodeDiagnosticEvents(resp.DiagnosticEvents)
+	if err != nil {
+		logger.WithError(err).Warn("cannot decode the diagnostic events of a submission")
+		events = nil
+	}
+	diagnostics := NewTransactionErrorDiagnostics(result, events)
+	return &diagnostics
+}
+
+func submissionHint(txHash string, status string) gossip.Hint {
+	return gossip.Hint{
+		Hash:   txHash,
+		Kind:   gossip.HintKindSubmission,
+		Status: status,
+	}
+}
@@ -6,11 +6,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/creachadair/jrpc2"
 
 	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/txnbuild"
 	"github.com/stellar/go/xdr"
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
@@ -21,6 +23,13 @@ import (
 type SimulateTransactionRequest struct {
 	Transaction    string                    `json:"transaction"`
 	ResourceConfig *preflight.ResourceConfig `json:"resourceConfig,omitempty"`
+	// AuthMode (optional) tells whether the simulation of InvokeHostFunction operations records the
+	// required authorizations or enforces the ones of the operation (see preflight.AuthModeEnforce and the like).
+	// By default, the authorizations are recorded if the operation has none, and enforced otherwise.
+	AuthMode preflight.AuthMode `json:"authMode,omitempty"`
+	// AutoRestore (optional) includes a ready-to-sign RestoreFootprint transaction in the
+	// restore preamble (if any), for the archived entries required by the simulated transaction.
+	AutoRestore bool `json:"autoRestore,omitempty"`
 }
 
 type SimulateTransactionCost struct {
@@ -37,7 +46,57 @@ type SimulateHostFunctionResult struct {
 type RestorePreamble struct {
 	TransactionData string `json:"transactionData"` // SorobanTransactionData XDR in base64
 	MinResourceFee  int64  `json:"minResourceFee,string"`
+	// Transaction is the unsigned RestoreFootprint TransactionEnvelope XDR in base64, only present if
+	// AutoRestore was requested. It uses the source account and the sequence number of the simulated
+	// transaction, which must be submitted afterwards with the next sequence number.
+	Transaction string `json:"transaction,omitempty"`
 }
+
+// newRestoreTransaction builds the RestoreFootprint transaction to submit before the simulated one,
+// paying the same inclusion fee.
+func newRestoreTransaction(txEnvelope xdr.TransactionEnvelope, restoreTransactionData []byte, minResourceFee int64) (string, error) {
+	var sorobanData xdr.SorobanTransactionData
+	if err := xdr.SafeUnmarshal(restoreTransactionData, &sorobanData); err != nil {
+		return "", err
+	}
+	var ext xdr.TransactionExt
+	switch txEnvelope.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		ext = txEnvelope.V1.Tx.Ext
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		ext = txEnvelope.FeeBump.Tx.InnerTx.V1.Tx.Ext
+	}
+	inclusionFee := int64(txEnvelope.Fee())
+	if ext.V == 1 {
+		inclusionFee -= int64(ext.SorobanData.ResourceFee)
+	}
+	inclusionFee = max(inclusionFee, txnbuild.MinBaseFee)
+	fee := inclusionFee + minResourceFee
+	if fee > math.MaxUint32 {
+		return "", fmt.Errorf("restore transaction fee (%d) is too high", fee)
+	}
+	return xdr.MarshalBase64(xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: txEnvelope.SourceAccount(),
+				Fee:           xdr.Uint32(fee),
+				SeqNum:        xdr.SequenceNumber(txEnvelope.SeqNum()),
+				Cond:          xdr.Preconditions{Type: xdr.PreconditionTypePrecondNone},
+				Operations: []xdr.Operation{
+					{
+						Body: xdr.OperationBody{
+							Type:               xdr.OperationTypeRestoreFootprint,
+							RestoreFootprintOp: &xdr.RestoreFootprintOp{},
+						},
+					},
+				},
+				Ext: xdr.TransactionExt{V: 1, SorobanData: &sorobanData},
+			},
+		},
+	})
+}
+
 type LedgerEntryChangeType int
 
 const (
@@ -142,23 +201,32 @@ type LedgerEntryChange struct {
 }
 
 type SimulateTransactionResponse struct {
-	Error           string                       `json:"error,omitempty"`
-	TransactionData string                       `json:"transactionData,omitempty"` // SorobanTransactionData XDR in base64
-	MinResourceFee  int64                        `json:"minResourceFee,string,omitempty"`
-	Events          []string                     `json:"events,omitempty"`          // DiagnosticEvent XDR in base64
-	Results         []SimulateHostFunctionResult `json:"results,omitempty"`         // an array of the individual host function call results
-	Cost            SimulateTransactionCost      `json:"cost,omitempty"`            // the effective cpu and memory cost of the invoked transaction execution.
-	RestorePreamble *RestorePreamble             `json:"restorePreamble,omitempty"` // If present, it indicates that a prior RestoreFootprint is required
-	StateChanges    []LedgerEntryChange          `json:"stateChanges,omitempty"`    // If present, it indicates how the state (ledger entries) will change as a result of the transaction execution.
-	LatestLedger    uint32                       `json:"latestLedger"`
+	Error           string                        `json:"error,omitempty"`
+	TransactionData string                        `json:"transactionData,omitempty"` // SorobanTransactionData XDR in base64
+	MinResourceFee  int64                         `json:"minResourceFee,string,omitempty"`
+	Events          []string                      `json:"events,omitempty"`          // DiagnosticEvent XDR in base64
+	Results         []SimulateHostFunctionResult  `json:"results,omitempty"`         // an array of the individual host function call results
+	Cost            SimulateTransactionCost       `json:"cost,omitempty"`            // the effective cpu and memory cost of the invoked transaction execution.
+	RestorePreamble *RestorePreamble              `json:"restorePreamble,omitempty"` // If present, it indicates that a prior RestoreFootprint is required
+	StateChanges    []LedgerEntryChange           `json:"stateChanges,omitempty"`    // If present, it indicates how the state (ledger entries) will change as a result of the transaction execution.
+	Resources       *SimulateTransactionResources `json:"resources,omitempty"`       // If present, it breaks down the resources used by the transaction.
+	LatestLedger    uint32                        `json:"latestLedger"`
 }
 
 type PreflightGetter interface {
 	GetPreflight(ctx context.Context, params preflight.GetterParameters) (preflight.Preflight, error)
 }
 
+// PreflightCompatibilityChecker verifies that the simulations at a protocol version can be trusted
+type PreflightCompatibilityChecker interface {
+	CheckCompatibility(protocolVersion uint32) error
+}
+
 // NewSimulateTransactionHandler returns a json rpc handler to run preflight simulations
-func NewSimulateTransactionHandler(logger *log.Entry, ledgerEntryReader db.LedgerEntryReader, ledgerReader db.LedgerReader, daemon interfaces.Daemon, getter PreflightGetter) jrpc2.Handler {
+func NewSimulateTransactionHandler(
+	logger *log.Entry, ledgerEntryReader db.LedgerEntryReader, ledgerReader db.LedgerReader, daemon interfaces.Daemon,
+	getter PreflightGetter, checker PreflightCompatibilityChecker,
+) jrpc2.Handler {
 	return NewHandler(func(ctx context.Context, request SimulateTransactionRequest) SimulateTransactionResponse {
 		var txEnvelope xdr.TransactionEnvelope
 		if err := xdr.SafeUnmarshalBase64(request.Transaction, &txEnvelope); err != nil {
@@ -174,6 +242,11 @@ func NewSimulateTransactionHandler(logger *log.Entry, ledgerEntryReader db.Ledge
 			}
 		}
 		op := txEnvelope.Operations()[0]
+		if err := request.AuthMode.Valid(); err != nil {
+			return SimulateTransactionResponse{
+				Error: err.Error(),
+			}
+		}
 
 		var sourceAccount xdr.AccountId
 		if opSourceAccount := op.SourceAccount; opSourceAccount != nil {
@@ -219,6 +292,13 @@ func NewSimulateTransactionHandler(logger *log.Entry, ledgerEntryReader db.Ledge
 				Error: err.Error(),
 			}
 		}
+		if err := checker.CheckCompatibility(protocolVersion); err != nil {
+			// refuse to simulate rather than producing wrong results (e.g. fees)
+			return SimulateTransactionResponse{
+				Error:        "simulation is unavailable: " + err.Error(),
+				LatestLedger: latestLedger,
+			}
+		}
 
 		resourceConfig := preflight.DefaultResourceConfig()
 		if request.ResourceConfig != nil {
@@ -231,6 +311,7 @@ func NewSimulateTransactionHandler(logger *log.Entry, ledgerEntryReader db.Ledge
 			OperationBody:     op.Body,
 			Footprint:         footprint,
 			ResourceConfig:    resourceConfig,
+			AuthMode:          request.AuthMode,
 			ProtocolVersion:   protocolVersion,
 		}
 		result, err := getter.GetPreflight(ctx, params)
@@ -254,6 +335,17 @@ func NewSimulateTransactionHandler(logger *log.Entry, ledgerEntryReader db.Ledge
 				TransactionData: base64.StdEncoding.EncodeToString(result.PreRestoreTransactionData),
 				MinResourceFee:  result.PreRestoreMinFee,
 			}
+			if request.AutoRestore {
+				restorePreamble.Transaction, err = newRestoreTransaction(
+					txEnvelope, result.PreRestoreTransactionData, result.PreRestoreMinFee,
+				)
+				if err != nil {
+					return SimulateTransactionResponse{
+						Error:        "could not build the restore transaction: " + err.Error(),
+						LatestLedger: latestLedger,
+					}
+				}
+			}
 		}
 
 		stateChanges := make([]LedgerEntryChange, len(result.LedgerEntryDiff))
@@ -261,6 +353,14 @@ func NewSimulateTransactionHandler(logger *log.Entry, ledgerEntryReader db.Ledge
 			stateChanges[i].FromXDRDiff(result.LedgerEntryDiff[i])
 		}
 
+		var resources *SimulateTransactionResources
+		if result.Error == "" && len(result.TransactionData) != 0 {
+			// the breakdown is informative, don't fail the simulation because of it
+			if resources, err = newSimulateTransactionResources(readTx, result); err != nil {
+				logger.WithError(err).Warn("could not compute the resources of the simulated transaction")
+			}
+		}
+
 		return SimulateTransactionResponse{
 			Error:           result.Error,
 			Results:         results,
@@ -274,6 +374,7 @@ func NewSimulateTransactionHandler(logger *log.Entry, ledgerEntryReader db.Ledge
 			LatestLedger:    latestLedger,
 			RestorePreamble: restorePreamble,
 			StateChanges:    stateChanges,
+			Resources:       resources,
 		}
 	})
 }
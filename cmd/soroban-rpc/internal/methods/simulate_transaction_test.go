@@ -93,3 +93,60 @@ func TestLedgerEntryChange(t *testing.T) {
 		assert.Equal(t, change, change2, test.name)
 	}
 }
+
+func TestNewRestoreTransaction(t *testing.T) {
+	source := xdr.MustMuxedAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")
+	txEnvelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: source,
+				Fee:           1100,
+				SeqNum:        42,
+				Operations: []xdr.Operation{
+					{
+						Body: xdr.OperationBody{
+							Type:                 xdr.OperationTypeInvokeHostFunction,
+							InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{},
+						},
+					},
+				},
+				Ext: xdr.TransactionExt{
+					V:           1,
+					SorobanData: &xdr.SorobanTransactionData{ResourceFee: 1000},
+				},
+			},
+		},
+	}
+	restoreData := xdr.SorobanTransactionData{
+		Resources: xdr.SorobanResources{
+			Footprint: xdr.LedgerFootprint{
+				ReadWrite: []xdr.LedgerKey{
+					{
+						Type:         xdr.LedgerEntryTypeContractCode,
+						ContractCode: &xdr.LedgerKeyContractCode{Hash: xdr.Hash{1}},
+					},
+				},
+			},
+		},
+		ResourceFee: 500,
+	}
+	restoreDataXDR, err := restoreData.MarshalBinary()
+	require.NoError(t, err)
+
+	restoreTxB64, err := newRestoreTransaction(txEnvelope, restoreDataXDR, 500)
+	require.NoError(t, err)
+	var restoreTx xdr.TransactionEnvelope
+	require.NoError(t, xdr.SafeUnmarshalBase64(restoreTxB64, &restoreTx))
+	assert.Equal(t, xdr.EnvelopeTypeEnvelopeTypeTx, restoreTx.Type)
+	assert.Equal(t, source, restoreTx.SourceAccount())
+	assert.Equal(t, int64(42), restoreTx.SeqNum())
+	// the inclusion fee of the simulated transaction plus the restore resource fee
+	assert.Equal(t, uint32(600), restoreTx.Fee())
+	require.Len(t, restoreTx.Operations(), 1)
+	assert.Equal(t, xdr.OperationTypeRestoreFootprint, restoreTx.Operations()[0].Body.Type)
+	assert.Equal(t, restoreData, *restoreTx.V1.Tx.Ext.SorobanData)
+
+	_, err = newRestoreTransaction(txEnvelope, []byte{1, 2}, 500)
+	assert.Error(t, err)
+}
@@ -0,0 +1,130 @@
+package methods
+
+import (
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/preflight"
+)
+
+const (
+	FootprintAccessReadOnly  = "readOnly"
+	FootprintAccessReadWrite = "readWrite"
+)
+
+// SimulateTransactionResources breaks down the resources used by a simulated transaction,
+// helping to find out which of them drive its fee.
+type SimulateTransactionResources struct {
+	// Instructions is the number of CPU instructions reserved by the transaction data (including any leeway)
+	Instructions uint32 `json:"instructions"`
+	// ReadBytes is the number of bytes read from the ledger (including the written entries)
+	ReadBytes uint32 `json:"readBytes"`
+	// WriteBytes is the number of bytes written to the ledger
+	WriteBytes uint32 `json:"writeBytes"`
+	// Footprint describes the ledger entries read and written by the transaction
+	Footprint SimulateFootprintResources `json:"footprint"`
+	// ContractEvents are the events emitted by successful contract calls, which are part of the transaction meta
+	ContractEvents SimulateEventsResources `json:"contractEvents"`
+	// DiagnosticEvents are the remaining events, which are only used for debugging
+	DiagnosticEvents SimulateEventsResources `json:"diagnosticEvents"`
+	// ReturnValueBytes is the size of the value returned by the invoked host function
+	ReturnValueBytes int `json:"returnValueBytes"`
+}
+
+type SimulateFootprintResources struct {
+	ReadOnlyEntries  int `json:"readOnlyEntries"`
+	ReadWriteEntries int `json:"readWriteEntries"`
+	// Entries lists all the footprint entries, read-only entries first
+	Entries []FootprintEntryResources `json:"entries"`
+}
+
+type FootprintEntryResources struct {
+	// Key is the LedgerKey XDR in base64
+	Key string `json:"key"`
+	// Type is the type of the ledger entry (e.g. contract_data)
+	Type string `json:"type"`
+	// Access is either FootprintAccessReadOnly or FootprintAccessReadWrite
+	Access string `json:"access"`
+	// Bytes is the size of the ledger entry before the transaction, 0 if it doesn't exist yet
+	Bytes int `json:"bytes"`
+}
+
+type SimulateEventsResources struct {
+	Count int `json:"count"`
+	// Bytes is the total size of the events
+	Bytes int `json:"bytes"`
+}
+
+// newSimulateTransactionResources computes the resources of a successful simulation out of
+// its transaction data, events and result, looking up the size of the footprint entries.
+func newSimulateTransactionResources(readTx db.LedgerEntryReadTx, result preflight.Preflight) (*SimulateTransactionResources, error) {
+	var transactionData xdr.SorobanTransactionData
+	if err := xdr.SafeUnmarshal(result.TransactionData, &transactionData); err != nil {
+		return nil, err
+	}
+	footprint := transactionData.Resources.Footprint
+	resources := SimulateTransactionResources{
+		Instructions:     uint32(transactionData.Resources.Instructions),
+		ReadBytes:        uint32(transactionData.Resources.ReadBytes),
+		WriteBytes:       uint32(transactionData.Resources.WriteBytes),
+		ReturnValueBytes: len(result.Result),
+		Footprint: SimulateFootprintResources{
+			ReadOnlyEntries:  len(footprint.ReadOnly),
+			ReadWriteEntries: len(footprint.ReadWrite),
+			Entries:          make([]FootprintEntryResources, 0, len(footprint.ReadOnly)+len(footprint.ReadWrite)),
+		},
+	}
+
+	keys := append(append([]xdr.LedgerKey{}, footprint.ReadOnly...), footprint.ReadWrite...)
+	entries, err := readTx.GetLedgerEntries(keys...)
+	if err != nil {
+		return nil, err
+	}
+	entrySizes := make(map[string]int, len(entries))
+	for _, entry := range entries {
+		keyB64, err := xdr.MarshalBase64(entry.Key)
+		if err != nil {
+			return nil, err
+		}
+		entryXDR, err := entry.Entry.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		entrySizes[keyB64] = len(entryXDR)
+	}
+	for i, key := range keys {
+		keyB64, err := xdr.MarshalBase64(key)
+		if err != nil {
+			return nil, err
+		}
+		access := FootprintAccessReadOnly
+		if i >= len(footprint.ReadOnly) {
+			access = FootprintAccessReadWrite
+		}
+		resources.Footprint.Entries = append(resources.Footprint.Entries, FootprintEntryResources{
+			Key:    keyB64,
+			Type:   codeName(key.Type.String(), "LedgerEntryType"),
+			Access: access,
+			Bytes:  entrySizes[keyB64],
+		})
+	}
+
+	for _, eventXDR := range result.Events {
+		var event xdr.DiagnosticEvent
+		if err := xdr.SafeUnmarshal(eventXDR, &event); err != nil {
+			return nil, err
+		}
+		if event.InSuccessfulContractCall && event.Event.Type == xdr.ContractEventTypeContract {
+			contractEventXDR, err := event.Event.MarshalBinary()
+			if err != nil {
+				return nil, err
+			}
+			resources.ContractEvents.Count++
+			resources.ContractEvents.Bytes += len(contractEventXDR)
+		} else {
+			resources.DiagnosticEvents.Count++
+			resources.DiagnosticEvents.Bytes += len(eventXDR)
+		}
+	}
+	return &resources, nil
+}
@@ -0,0 +1,121 @@
+package methods
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/preflight"
+)
+
+type ledgerEntriesReadTx struct {
+	ConstantLedgerEntryReaderTx
+	entries []xdr.LedgerEntry
+}
+
+func (tx ledgerEntriesReadTx) GetLedgerEntries(keys ...xdr.LedgerKey) ([]db.LedgerKeyAndEntry, error) {
+	var result []db.LedgerKeyAndEntry
+	for _, key := range keys {
+		for _, entry := range tx.entries {
+			if entryKey, err := entry.LedgerKey(); err == nil && entryKey.Equals(key) {
+				result = append(result, db.LedgerKeyAndEntry{Key: key, Entry: entry})
+			}
+		}
+	}
+	return result, nil
+}
+
+func TestSimulateTransactionResources(t *testing.T) {
+	contractID := xdr.Hash{1}
+	contract := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID}
+	counter := xdr.ScSymbol("COUNTER")
+	value := xdr.Uint32(7)
+	counterEntry := xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeContractData,
+			ContractData: &xdr.ContractDataEntry{
+				Contract:   contract,
+				Key:        xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &counter},
+				Durability: xdr.ContractDataDurabilityPersistent,
+				Val:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &value},
+			},
+		},
+	}
+	counterKey, err := counterEntry.LedgerKey()
+	require.NoError(t, err)
+	codeKey := xdr.LedgerKey{
+		Type:         xdr.LedgerEntryTypeContractCode,
+		ContractCode: &xdr.LedgerKeyContractCode{Hash: xdr.Hash{2}},
+	}
+
+	transactionData, err := xdr.SorobanTransactionData{
+		Resources: xdr.SorobanResources{
+			Footprint: xdr.LedgerFootprint{
+				ReadOnly:  []xdr.LedgerKey{codeKey},
+				ReadWrite: []xdr.LedgerKey{counterKey},
+			},
+			Instructions: 1000,
+			ReadBytes:    200,
+			WriteBytes:   100,
+		},
+	}.MarshalBinary()
+	require.NoError(t, err)
+
+	contractEvent := xdr.DiagnosticEvent{
+		InSuccessfulContractCall: true,
+		Event: xdr.ContractEvent{
+			ContractId: &contractID,
+			Type:       xdr.ContractEventTypeContract,
+			Body: xdr.ContractEventBody{
+				V0: &xdr.ContractEventV0{Data: xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &value}},
+			},
+		},
+	}
+	contractEventXDR, err := contractEvent.MarshalBinary()
+	require.NoError(t, err)
+	contractEventBodyXDR, err := contractEvent.Event.MarshalBinary()
+	require.NoError(t, err)
+	diagnosticEvent := contractEvent
+	diagnosticEvent.Event.Type = xdr.ContractEventTypeDiagnostic
+	diagnosticEventXDR, err := diagnosticEvent.MarshalBinary()
+	require.NoError(t, err)
+	returnValueXDR, err := xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &value}.MarshalBinary()
+	require.NoError(t, err)
+
+	resources, err := newSimulateTransactionResources(
+		ledgerEntriesReadTx{entries: []xdr.LedgerEntry{counterEntry}},
+		preflight.Preflight{
+			TransactionData: transactionData,
+			Events:          [][]byte{diagnosticEventXDR, contractEventXDR, diagnosticEventXDR},
+			Result:          returnValueXDR,
+		},
+	)
+	require.NoError(t, err)
+
+	codeKeyB64, err := xdr.MarshalBase64(codeKey)
+	require.NoError(t, err)
+	counterKeyB64, err := xdr.MarshalBase64(counterKey)
+	require.NoError(t, err)
+	counterEntryXDR, err := counterEntry.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, &SimulateTransactionResources{
+		Instructions: 1000,
+		ReadBytes:    200,
+		WriteBytes:   100,
+		Footprint: SimulateFootprintResources{
+			ReadOnlyEntries:  1,
+			ReadWriteEntries: 1,
+			Entries: []FootprintEntryResources{
+				{Key: codeKeyB64, Type: "contract_code", Access: FootprintAccessReadOnly, Bytes: 0},
+				{Key: counterKeyB64, Type: "contract_data", Access: FootprintAccessReadWrite, Bytes: len(counterEntryXDR)},
+			},
+		},
+		ContractEvents:   SimulateEventsResources{Count: 1, Bytes: len(contractEventBodyXDR)},
+		DiagnosticEvents: SimulateEventsResources{Count: 2, Bytes: 2 * len(diagnosticEventXDR)},
+		ReturnValueBytes: len(returnValueXDR),
+	}, resources)
+}
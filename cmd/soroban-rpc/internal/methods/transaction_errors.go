@@ -0,0 +1,229 @@
+package methods
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+// TransactionErrorDiagnostics is a human-readable explanation of why stellar-core rejected a transaction,
+// decoded from the error result and the diagnostic events of the submission.
+type TransactionErrorDiagnostics struct {
+	// ResultCode is the name of the transaction result code (e.g. tx_bad_seq)
+	ResultCode string `json:"resultCode"`
+	// InnerResultCode is the result code of the inner transaction, present only for fee bump transactions
+	InnerResultCode string `json:"innerResultCode,omitempty"`
+	// FailedOperationIndex is the index of the first failing operation, present only if an operation failed
+	FailedOperationIndex *int `json:"failedOperationIndex,omitempty"`
+	// OperationResultCode is the result code of the failing operation (e.g. invoke_host_function_trapped)
+	OperationResultCode string `json:"operationResultCode,omitempty"`
+	// ContractErrors are the errors reported by the contracts (or the host) in the diagnostic events
+	ContractErrors []ContractErrorDiagnostic `json:"contractErrors,omitempty"`
+	// Message summarizes all the above in a sentence
+	Message string `json:"message"`
+}
+
+// ContractErrorDiagnostic is an error reported in a diagnostic event
+type ContractErrorDiagnostic struct {
+	// ContractID is the contract which reported the error, if any
+	ContractID string `json:"contractId,omitempty"`
+	// Error is the error value, formatted like the Soroban host does (e.g. Error(Contract, #3))
+	Error string `json:"error,omitempty"`
+	// Message is the error message, followed by its arguments
+	Message string `json:"message,omitempty"`
+}
+
+var transactionResultExplanations = map[xdr.TransactionResultCode]string{
+	xdr.TransactionResultCodeTxFailed:              "one of the operations failed (none were applied)",
+	xdr.TransactionResultCodeTxTooEarly:            "the ledger close time is before the transaction minimum time",
+	xdr.TransactionResultCodeTxTooLate:             "the ledger close time is after the transaction maximum time",
+	xdr.TransactionResultCodeTxMissingOperation:    "the transaction has no operations",
+	xdr.TransactionResultCodeTxBadSeq:              "the sequence number does not match the source account",
+	xdr.TransactionResultCodeTxBadAuth:             "too few valid signatures, or the transaction was signed for another network",
+	xdr.TransactionResultCodeTxInsufficientBalance: "the fee would bring the source account below its reserve",
+	xdr.TransactionResultCodeTxNoAccount:           "the source account was not found",
+	xdr.TransactionResultCodeTxInsufficientFee:     "the fee is too small",
+	xdr.TransactionResultCodeTxBadAuthExtra:        "unused signatures are attached to the transaction",
+	xdr.TransactionResultCodeTxInternalError:       "an unknown error occurred",
+	xdr.TransactionResultCodeTxNotSupported:        "the transaction type is not supported",
+	xdr.TransactionResultCodeTxFeeBumpInnerFailed:  "the inner transaction of the fee bump failed",
+	xdr.TransactionResultCodeTxBadSponsorship:      "a sponsorship is not confirmed",
+	xdr.TransactionResultCodeTxBadMinSeqAgeOrGap:   "the minimum sequence age or ledger gap preconditions are not met",
+	xdr.TransactionResultCodeTxMalformed:           "a precondition is invalid",
+	xdr.TransactionResultCodeTxSorobanInvalid:      "the Soroban resources or footprint are invalid, or the fee does not cover them",
+}
+
+// codeName converts the name of a generated XDR enum value into snake case, dropping the
+// enum type prefix (e.g. TransactionResultCodeTxBadSeq is converted into tx_bad_seq)
+func codeName(name string, prefix string) string {
+	name = strings.TrimPrefix(name, prefix)
+	var result strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				result.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		result.WriteRune(r)
+	}
+	return result.String()
+}
+
+func transactionResultCodeName(code xdr.TransactionResultCode) string {
+	return codeName(code.String(), "TransactionResultCode")
+}
+
+// operationResultCode returns the name of the result code of an operation and whether it failed
+func operationResultCode(result xdr.OperationResult) (string, bool) {
+	if result.Code != xdr.OperationResultCodeOpInner || result.Tr == nil {
+		return codeName(result.Code.String(), "OperationResultCode"), result.Code != xdr.OperationResultCodeOpInner
+	}
+	// The operation results are a union with an arm per operation type, all of which
+	// carry a result code whose successful value is 0
+	tr := reflect.ValueOf(*result.Tr)
+	for i := 0; i < tr.NumField(); i++ {
+		arm := tr.Field(i)
+		if arm.Kind() != reflect.Pointer || arm.IsNil() {
+			continue
+		}
+		code := arm.Elem().FieldByName("Code")
+		if !code.IsValid() || code.Kind() != reflect.Int32 {
+			continue
+		}
+		stringer, ok := code.Interface().(fmt.Stringer)
+		if !ok {
+			continue
+		}
+		return codeName(stringer.String(), code.Type().Name()), code.Int() != 0
+	}
+	return "", false
+}
+
+// formatScError formats a Soroban error the way the host does
+func formatScError(scError xdr.ScError) string {
+	errorType := strings.TrimPrefix(scError.Type.String(), "ScErrorTypeSce")
+	if scError.Type == xdr.ScErrorTypeSceContract {
+		if scError.ContractCode == nil {
+			return fmt.Sprintf("Error(%s)", errorType)
+		}
+		return fmt.Sprintf("Error(%s, #%d)", errorType, *scError.ContractCode)
+	}
+	if scError.Code == nil {
+		return fmt.Sprintf("Error(%s)", errorType)
+	}
+	return fmt.Sprintf("Error(%s, %s)", errorType, strings.TrimPrefix(scError.Code.String(), "ScErrorCodeScec"))
+}
+
+// formatScVal formats the values commonly found in error events, falling back to their base64 XDR
+func formatScVal(value xdr.ScVal) string {
+	switch value.Type {
+	case xdr.ScValTypeScvBool:
+		return fmt.Sprint(*value.B)
+	case xdr.ScValTypeScvVoid:
+		return "void"
+	case xdr.ScValTypeScvError:
+		return formatScError(*value.Error)
+	case xdr.ScValTypeScvU32:
+		return fmt.Sprint(*value.U32)
+	case xdr.ScValTypeScvI32:
+		return fmt.Sprint(*value.I32)
+	case xdr.ScValTypeScvU64:
+		return fmt.Sprint(*value.U64)
+	case xdr.ScValTypeScvI64:
+		return fmt.Sprint(*value.I64)
+	case xdr.ScValTypeScvString:
+		return string(*value.Str)
+	case xdr.ScValTypeScvSymbol:
+		return string(*value.Sym)
+	case xdr.ScValTypeScvAddress:
+		if address, err := value.Address.String(); err == nil {
+			return address
+		}
+	}
+	encoded, err := xdr.MarshalBase64(value)
+	if err != nil {
+		return value.Type.String()
+	}
+	return encoded
+}
+
+// contractErrorDiagnostic decodes the error events emitted by the host, whose first topic is
+// the "error" symbol, followed by the error value. Their data is either the error message or a
+// vector containing the message followed by its arguments.
+func contractErrorDiagnostic(event xdr.DiagnosticEvent) (ContractErrorDiagnostic, bool) {
+	v0, ok := event.Event.Body.GetV0()
+	if !ok || len(v0.Topics) == 0 {
+		return ContractErrorDiagnostic{}, false
+	}
+	if sym, ok := v0.Topics[0].GetSym(); !ok || sym != "error" {
+		return ContractErrorDiagnostic{}, false
+	}
+	var result ContractErrorDiagnostic
+	if event.Event.ContractId != nil {
+		result.ContractID = strkey.MustEncode(strkey.VersionByteContract, (*event.Event.ContractId)[:])
+	}
+	if len(v0.Topics) > 1 {
+		result.Error = formatScVal(v0.Topics[1])
+	}
+	data := []xdr.ScVal{v0.Data}
+	if vec, ok := v0.Data.GetVec(); ok && vec != nil {
+		data = *vec
+	}
+	var parts []string
+	for _, value := range data {
+		parts = append(parts, formatScVal(value))
+	}
+	if len(parts) > 0 {
+		result.Message = parts[0]
+		if len(parts) > 1 {
+			result.Message += ": " + strings.Join(parts[1:], ", ")
+		}
+	}
+	return result, true
+}
+
+// NewTransactionErrorDiagnostics explains why a transaction was rejected
+func NewTransactionErrorDiagnostics(result xdr.TransactionResult, events []xdr.DiagnosticEvent) TransactionErrorDiagnostics {
+	code := result.Result.Code
+	diagnostics := TransactionErrorDiagnostics{ResultCode: transactionResultCodeName(code)}
+	if innerPair, ok := result.Result.GetInnerResultPair(); ok {
+		code = innerPair.Result.Result.Code
+		diagnostics.InnerResultCode = transactionResultCodeName(code)
+	}
+
+	explanation, ok := transactionResultExplanations[code]
+	if !ok {
+		explanation = "the transaction was rejected"
+	}
+	message := fmt.Sprintf("%s (%s)", explanation, transactionResultCodeName(code))
+
+	if opResults, ok := result.OperationResults(); ok {
+		for i, opResult := range opResults {
+			if name, failed := operationResultCode(opResult); failed {
+				index := i
+				diagnostics.FailedOperationIndex = &index
+				diagnostics.OperationResultCode = name
+				message += fmt.Sprintf(": operation %d failed with %s", i, name)
+				break
+			}
+		}
+	}
+
+	for _, event := range events {
+		if contractError, ok := contractErrorDiagnostic(event); ok {
+			diagnostics.ContractErrors = append(diagnostics.ContractErrors, contractError)
+		}
+	}
+	if len(diagnostics.ContractErrors) > 0 {
+		// the first error is the root cause, the following ones are its propagation up the call stack
+		first := diagnostics.ContractErrors[0]
+		message += ": " + strings.TrimSpace(first.Error+" "+first.Message)
+	}
+	diagnostics.Message = message
+	return diagnostics
+}
@@ -0,0 +1,135 @@
+package methods
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/xdr"
+)
+
+func TestCodeName(t *testing.T) {
+	assert.Equal(t, "tx_bad_seq", transactionResultCodeName(xdr.TransactionResultCodeTxBadSeq))
+	assert.Equal(t, "tx_bad_min_seq_age_or_gap", transactionResultCodeName(xdr.TransactionResultCodeTxBadMinSeqAgeOrGap))
+	assert.Equal(t, "op_no_account", codeName(xdr.OperationResultCodeOpNoAccount.String(), "OperationResultCode"))
+}
+
+func errorEvent(contractID *xdr.Hash, scError xdr.ScError, data xdr.ScVal) xdr.DiagnosticEvent {
+	errorSym := xdr.ScSymbol("error")
+	return xdr.DiagnosticEvent{
+		InSuccessfulContractCall: false,
+		Event: xdr.ContractEvent{
+			ContractId: contractID,
+			Type:       xdr.ContractEventTypeDiagnostic,
+			Body: xdr.ContractEventBody{
+				V: 0,
+				V0: &xdr.ContractEventV0{
+					Topics: []xdr.ScVal{
+						{Type: xdr.ScValTypeScvSymbol, Sym: &errorSym},
+						{Type: xdr.ScValTypeScvError, Error: &scError},
+					},
+					Data: data,
+				},
+			},
+		},
+	}
+}
+
+func TestTransactionErrorDiagnostics(t *testing.T) {
+	t.Run("transaction error", func(t *testing.T) {
+		result := xdr.TransactionResult{
+			FeeCharged: 100,
+			Result:     xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxBadSeq},
+		}
+		assert.Equal(t, TransactionErrorDiagnostics{
+			ResultCode: "tx_bad_seq",
+			Message:    "the sequence number does not match the source account (tx_bad_seq)",
+		}, NewTransactionErrorDiagnostics(result, nil))
+	})
+
+	t.Run("failed contract invocation", func(t *testing.T) {
+		opResults := []xdr.OperationResult{
+			{
+				Code: xdr.OperationResultCodeOpInner,
+				Tr: &xdr.OperationResultTr{
+					Type:          xdr.OperationTypePayment,
+					PaymentResult: &xdr.PaymentResult{Code: xdr.PaymentResultCodePaymentSuccess},
+				},
+			},
+			{
+				Code: xdr.OperationResultCodeOpInner,
+				Tr: &xdr.OperationResultTr{
+					Type: xdr.OperationTypeInvokeHostFunction,
+					InvokeHostFunctionResult: &xdr.InvokeHostFunctionResult{
+						Code: xdr.InvokeHostFunctionResultCodeInvokeHostFunctionTrapped,
+					},
+				},
+			},
+		}
+		result := xdr.TransactionResult{
+			Result: xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxFailed, Results: &opResults},
+		}
+		contractID := xdr.Hash([32]byte{})
+		contractCode := xdr.Uint32(3)
+		message := xdr.ScString("balance is too low")
+		amount := xdr.Uint32(7)
+		args := &xdr.ScVec{
+			{Type: xdr.ScValTypeScvString, Str: &message},
+			{Type: xdr.ScValTypeScvU32, U32: &amount},
+		}
+		propagated := xdr.ScString("escalating error to VM trap")
+		code := xdr.ScErrorCodeScecInvalidAction
+		events := []xdr.DiagnosticEvent{
+			errorEvent(
+				&contractID,
+				xdr.ScError{Type: xdr.ScErrorTypeSceContract, ContractCode: &contractCode},
+				xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &args},
+			),
+			errorEvent(
+				nil,
+				xdr.ScError{Type: xdr.ScErrorTypeSceWasmVm, Code: &code},
+				xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &propagated},
+			),
+		}
+		diagnostics := NewTransactionErrorDiagnostics(result, events)
+		require.NotNil(t, diagnostics.FailedOperationIndex)
+		assert.Equal(t, 1, *diagnostics.FailedOperationIndex)
+		assert.Equal(t, "tx_failed", diagnostics.ResultCode)
+		assert.Equal(t, "invoke_host_function_trapped", diagnostics.OperationResultCode)
+		assert.Equal(t, []ContractErrorDiagnostic{
+			{
+				ContractID: "CAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABSC4",
+				Error:      "Error(Contract, #3)",
+				Message:    "balance is too low: 7",
+			},
+			{
+				Error:   "Error(WasmVm, InvalidAction)",
+				Message: "escalating error to VM trap",
+			},
+		}, diagnostics.ContractErrors)
+		assert.Equal(t,
+			"one of the operations failed (none were applied) (tx_failed): operation 1 failed with "+
+				"invoke_host_function_trapped: Error(Contract, #3) balance is too low: 7",
+			diagnostics.Message,
+		)
+	})
+
+	t.Run("fee bump", func(t *testing.T) {
+		result := xdr.TransactionResult{
+			Result: xdr.TransactionResultResult{
+				Code: xdr.TransactionResultCodeTxFeeBumpInnerFailed,
+				InnerResultPair: &xdr.InnerTransactionResultPair{
+					Result: xdr.InnerTransactionResult{
+						Result: xdr.InnerTransactionResultResult{Code: xdr.TransactionResultCodeTxBadAuth},
+					},
+				},
+			},
+		}
+		diagnostics := NewTransactionErrorDiagnostics(result, nil)
+		assert.Equal(t, "tx_fee_bump_inner_failed", diagnostics.ResultCode)
+		assert.Equal(t, "tx_bad_auth", diagnostics.InnerResultCode)
+		assert.Nil(t, diagnostics.FailedOperationIndex)
+		assert.Contains(t, diagnostics.Message, "(tx_bad_auth)")
+	})
+}
@@ -0,0 +1,53 @@
+package methods
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The XDR fields of transactions which can be selected through the xdrFields request parameter
+const (
+	// XDRFieldEnvelope selects envelopeXdr (and the innerEnvelopeXdr of fee-bump transactions)
+	XDRFieldEnvelope = "envelope"
+	// XDRFieldResult selects resultXdr (and the innerResultXdr of fee-bump transactions)
+	XDRFieldResult = "result"
+	// XDRFieldResultMeta selects resultMetaXdr
+	XDRFieldResultMeta = "resultMeta"
+	// XDRFieldDiagnosticEvents selects diagnosticEventsXdr
+	XDRFieldDiagnosticEvents = "diagnosticEvents"
+	// XDRFieldEvents selects the events decoded from the meta of Soroban transactions (getTransaction only)
+	XDRFieldEvents = "events"
+)
+
+var allXDRFields = []string{
+	XDRFieldEnvelope,
+	XDRFieldResult,
+	XDRFieldResultMeta,
+	XDRFieldDiagnosticEvents,
+	XDRFieldEvents,
+}
+
+// xdrFieldSelection tells which XDR fields of a transaction must be included in a response
+type xdrFieldSelection map[string]bool
+
+// newXDRFieldSelection parses the xdrFields request parameter, selecting all the fields if it is nil
+func newXDRFieldSelection(fields []string) (xdrFieldSelection, error) {
+	selection := xdrFieldSelection{}
+	if fields == nil {
+		fields = allXDRFields
+	}
+	for _, field := range fields {
+		known := false
+		for _, xdrField := range allXDRFields {
+			if field == xdrField {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown xdr field %q, must be one of: %s", field, strings.Join(allXDRFields, ", "))
+		}
+		selection[field] = true
+	}
+	return selection, nil
+}
@@ -0,0 +1,98 @@
+package network
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/stellar/go/support/log"
+)
+
+// AccessLogConfig configures the access log
+type AccessLogConfig struct {
+	// Logger is where the access log entries are written to
+	Logger *log.Entry
+	// SampleRatio is the fraction of requests which are logged
+	SampleRatio float64
+	// TrustedProxies is the amount of trusted proxies in front of the server, used
+	// to identify clients by the X-Forwarded-For header (0 ignores the header)
+	TrustedProxies uint
+}
+
+type countingResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	size       int
+}
+
+func (w *countingResponseWriter) Write(buf []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(buf)
+	w.size += n
+	return n, err
+}
+
+func (w *countingResponseWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// MakeHTTPAccessLogger returns a handler which logs an entry for each of the
+// JSON RPC calls served by the downstream handler.
+func MakeHTTPAccessLogger(downstream http.Handler, cfg AccessLogConfig) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if cfg.SampleRatio < 1 && rand.Float64() >= cfg.SampleRatio { //nolint:gosec
+			downstream.ServeHTTP(res, req)
+			return
+		}
+		var body []byte
+		if req.Body != nil {
+			var err error
+			body, err = io.ReadAll(req.Body)
+			if err != nil {
+				http.Error(res, err.Error(), http.StatusBadRequest)
+				return
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		calls := parseCalls(body)
+
+		writer := &countingResponseWriter{ResponseWriter: res}
+		startTime := time.Now()
+		downstream.ServeHTTP(writer, req)
+		duration := time.Since(startTime)
+		if writer.statusCode == 0 {
+			writer.statusCode = http.StatusOK
+		}
+
+		logger := cfg.Logger.WithFields(log.F{
+			"client_ip":     ClientIP(req, cfg.TrustedProxies),
+			"path":          req.URL.Path,
+			"request_size":  len(body),
+			"response_size": writer.size,
+			"status":        writer.statusCode,
+			"duration":      duration.Seconds(),
+		})
+		if len(calls) == 0 || (len(calls) == 1 && calls[0].Method == "") {
+			// not a (valid) JSON RPC request, e.g. a GraphQL query
+			logger.Info("access")
+			return
+		}
+		if len(calls) > 1 {
+			logger = logger.WithField("batch_size", len(calls))
+		}
+		for _, call := range calls {
+			logger.WithFields(log.F{
+				"method":      call.Method,
+				"json_req":    string(call.ID),
+				"params_size": len(call.Params),
+			}).Info("access")
+		}
+	})
+}
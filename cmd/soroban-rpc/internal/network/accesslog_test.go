@@ -0,0 +1,75 @@
+package network
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+)
+
+func accessLogEntries(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	out.Reset()
+	return entries
+}
+
+func TestAccessLogger(t *testing.T) {
+	var out bytes.Buffer
+	logger := log.New()
+	logger.SetOutput(&out)
+	logger.SetLevel(log.InfoLevel)
+	logger.UseJSONFormatter()
+
+	downstream := &TestingHandlerWrapper{f: func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusTeapot)
+		_, _ = res.Write([]byte("0123456789"))
+	}}
+	handler := MakeHTTPAccessLogger(downstream, AccessLogConfig{Logger: logger, SampleRatio: 1, TrustedProxies: 1})
+
+	body := `[{"jsonrpc": "2.0", "id": 1, "method": "getHealth"}, {"jsonrpc": "2.0", "id": "a", "method": "getLedgerEntries", "params": {"keys": []}}]`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	// the leftmost entry is forged by the client, the proxy appended the actual client address
+	req.Header.Set("X-Forwarded-For", "1.2.3.4, 10.0.0.1")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusTeapot, res.Code)
+	assert.Equal(t, "0123456789", res.Body.String())
+
+	entries := accessLogEntries(t, &out)
+	require.Len(t, entries, 2)
+	for _, entry := range entries {
+		assert.Equal(t, "access", entry["msg"])
+		assert.Equal(t, "10.0.0.1", entry["client_ip"])
+		assert.Equal(t, float64(len(body)), entry["request_size"])
+		assert.Equal(t, float64(10), entry["response_size"])
+		assert.Equal(t, float64(http.StatusTeapot), entry["status"])
+		assert.Equal(t, float64(2), entry["batch_size"])
+		assert.Contains(t, entry, "duration")
+	}
+	assert.Equal(t, "getHealth", entries[0]["method"])
+	assert.Equal(t, "1", entries[0]["json_req"])
+	assert.Equal(t, float64(0), entries[0]["params_size"])
+	assert.Equal(t, "getLedgerEntries", entries[1]["method"])
+	assert.Equal(t, `"a"`, entries[1]["json_req"])
+	assert.Equal(t, float64(len(`{"keys": []}`)), entries[1]["params_size"])
+
+	// nothing is logged when sampling out all the requests
+	handler = MakeHTTPAccessLogger(downstream, AccessLogConfig{Logger: logger, SampleRatio: 0})
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	assert.Empty(t, accessLogEntries(t, &out))
+}
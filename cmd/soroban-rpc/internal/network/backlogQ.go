@@ -32,13 +32,13 @@ type backlogQLimiter struct {
 
 type backlogHTTPQLimiter struct {
 	httpDownstreamHandler http.Handler
-	backlogQLimiter
+	*backlogQLimiter
 }
 
 func MakeHTTPBacklogQueueLimiter(downstream http.Handler, gauge gauge, limit uint64, logger *log.Entry) *backlogHTTPQLimiter {
 	return &backlogHTTPQLimiter{
 		httpDownstreamHandler: downstream,
-		backlogQLimiter: backlogQLimiter{
+		backlogQLimiter: &backlogQLimiter{
 			limit:  limit,
 			gauge:  gauge,
 			logger: logger,
@@ -46,6 +46,15 @@ func MakeHTTPBacklogQueueLimiter(downstream http.Handler, gauge gauge, limit uin
 	}
 }
 
+// Wrap returns a handler limited by the same queue as q, so that the requests
+// of both handlers count towards the same limit.
+func (q *backlogHTTPQLimiter) Wrap(downstream http.Handler) http.Handler {
+	return &backlogHTTPQLimiter{
+		httpDownstreamHandler: downstream,
+		backlogQLimiter:       q.backlogQLimiter,
+	}
+}
+
 type backlogJrpcQLimiter struct {
 	jrpcDownstreamHandler jrpc2.Handler
 	backlogQLimiter
@@ -164,6 +164,38 @@ func TestBacklogQueueLimiter_HttpBlocking(t *testing.T) {
 	}
 }
 
+func TestBacklogQueueLimiter_HttpSharedQueue(t *testing.T) {
+	blockedCh := make(chan interface{})
+	started := make(chan interface{})
+	blockedHandler := &TestingHandlerWrapper{f: func(res http.ResponseWriter, req *http.Request) {
+		close(started)
+		<-blockedCh
+	}}
+	testGauge := &TestingGauge{}
+	limiter := MakeHTTPBacklogQueueLimiter(blockedHandler, testGauge, 1, nil)
+	otherHandler := limiter.Wrap(&TestingHandlerWrapper{f: func(res http.ResponseWriter, req *http.Request) {}})
+
+	done := make(chan interface{})
+	go func() {
+		limiter.ServeHTTP(nil, nil)
+		close(done)
+	}()
+	<-started
+
+	// the requests of the wrapped handler count towards the same limit
+	var res TestingResponseWriter
+	otherHandler.ServeHTTP(&res, nil)
+	require.Equal(t, http.StatusServiceUnavailable, res.statusCode)
+	require.Equal(t, 1, int(testGauge.count))
+
+	close(blockedCh)
+	<-done
+	res = TestingResponseWriter{}
+	otherHandler.ServeHTTP(&res, nil)
+	require.Equal(t, 0, res.statusCode)
+	require.Zero(t, int(testGauge.count))
+}
+
 // The goal of the TestBacklogQueueLimiter_JrpcBlocking is to set
 // up a queue that already reached it's limit and see that
 // additional requests are being rejected. Then, unblock the queue
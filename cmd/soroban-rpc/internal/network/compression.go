@@ -0,0 +1,126 @@
+package network
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/stellar/go/support/log"
+)
+
+const (
+	encodingGzip = "gzip"
+	encodingZstd = "zstd"
+)
+
+type httpCompressionHandler struct {
+	downstream  http.Handler
+	minSize     int
+	enableZstd  bool
+	gzipWriters sync.Pool
+	zstdEncoder *zstd.Encoder
+	logger      *log.Entry
+}
+
+// MakeHTTPCompressionHandler compresses the responses of the downstream handler
+// which are at least minSize bytes long, using the best encoding (gzip or,
+// if enabled, zstd) accepted by the client.
+func MakeHTTPCompressionHandler(downstream http.Handler, minSize uint, enableZstd bool, logger *log.Entry) http.Handler {
+	handler := &httpCompressionHandler{
+		downstream: downstream,
+		minSize:    int(minSize),
+		enableZstd: enableZstd,
+		gzipWriters: sync.Pool{New: func() any {
+			return gzip.NewWriter(nil)
+		}},
+		logger: logger,
+	}
+	if enableZstd {
+		// EncodeAll can be invoked concurrently, no need to pool encoders
+		encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+		if err != nil {
+			// this can only happen with invalid options
+			panic(err)
+		}
+		handler.zstdEncoder = encoder
+	}
+	return handler
+}
+
+// negotiateEncoding picks the encoding preferred by the client out of an Accept-Encoding header,
+// returning an empty string if the client doesn't accept any of the supported encodings.
+func negotiateEncoding(acceptEncoding string, enableZstd bool) string {
+	best, bestQuality := "", 0.0
+	for _, entry := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(entry, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		switch name {
+		case encodingGzip, "*":
+			name = encodingGzip
+		case encodingZstd:
+			if !enableZstd {
+				continue
+			}
+		default:
+			continue
+		}
+		// zstd is preferred over gzip when the client has no preference
+		if quality > bestQuality || (quality == bestQuality && name == encodingZstd) {
+			best, bestQuality = name, quality
+		}
+	}
+	return best
+}
+
+func (c *httpCompressionHandler) compress(encoding string, data []byte) ([]byte, error) {
+	if encoding == encodingZstd {
+		return c.zstdEncoder.EncodeAll(data, make([]byte, 0, len(data)/4)), nil
+	}
+	var compressed bytes.Buffer
+	writer := c.gzipWriters.Get().(*gzip.Writer) //nolint:forcetypeassert
+	defer c.gzipWriters.Put(writer)
+	writer.Reset(&compressed)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return compressed.Bytes(), nil
+}
+
+func (c *httpCompressionHandler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	res.Header().Add("Vary", "Accept-Encoding")
+	encoding := negotiateEncoding(req.Header.Get("Accept-Encoding"), c.enableZstd)
+	if encoding == "" {
+		c.downstream.ServeHTTP(res, req)
+		return
+	}
+
+	responseBuffer := makeBufferedResponseWriter(res)
+	c.downstream.ServeHTTP(responseBuffer, req)
+	if len(responseBuffer.buffer) >= c.minSize && responseBuffer.header.Get("Content-Encoding") == "" {
+		compressed, err := c.compress(encoding, responseBuffer.buffer)
+		if err != nil {
+			c.logger.WithError(err).Warnf("could not compress response using %s", encoding)
+		} else {
+			responseBuffer.buffer = compressed
+			responseBuffer.header.Set("Content-Encoding", encoding)
+			responseBuffer.header.Del("Content-Length")
+		}
+	}
+	responseBuffer.WriteOut(req.Context(), res)
+}
@@ -0,0 +1,89 @@
+package network
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	for _, testCase := range []struct {
+		acceptEncoding string
+		enableZstd     bool
+		expected       string
+	}{
+		{"", true, ""},
+		{"br", true, ""},
+		{"gzip", false, "gzip"},
+		{"gzip, deflate, br", false, "gzip"},
+		{"zstd", false, ""},
+		{"gzip, zstd", false, "gzip"},
+		{"gzip, zstd", true, "zstd"},
+		{"zstd;q=0.5, gzip", true, "gzip"},
+		{"gzip;q=0", true, ""},
+		{"*", true, "gzip"},
+		{"GZIP;q=0.8", false, "gzip"},
+	} {
+		assert.Equal(t, testCase.expected, negotiateEncoding(testCase.acceptEncoding, testCase.enableZstd), testCase.acceptEncoding)
+	}
+}
+
+func TestHTTPCompressionHandler(t *testing.T) {
+	body := strings.Repeat(`{"xdr":"AAAAAgAAAAA="}`, 100)
+	downstream := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, r.URL.Query().Get("prefix")+body)
+	})
+	handler := MakeHTTPCompressionHandler(downstream, 100, true, log.DefaultLogger)
+
+	serve := func(acceptEncoding string, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	// gzip
+	response := serve("gzip", "/")
+	require.Equal(t, "gzip", response.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", response.Header().Get("Vary"))
+	assert.Less(t, response.Body.Len(), len(body))
+	reader, err := gzip.NewReader(response.Body)
+	require.NoError(t, err)
+	decompressed, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(decompressed))
+
+	// zstd
+	response = serve("gzip, zstd", "/")
+	require.Equal(t, "zstd", response.Header().Get("Content-Encoding"))
+	decoder, err := zstd.NewReader(bytes.NewReader(response.Body.Bytes()))
+	require.NoError(t, err)
+	decompressed, err = io.ReadAll(decoder)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(decompressed))
+
+	// no encoding supported by the client
+	response = serve("", "/")
+	assert.Empty(t, response.Header().Get("Content-Encoding"))
+	assert.Equal(t, body, response.Body.String())
+
+	// small responses aren't compressed
+	handler = MakeHTTPCompressionHandler(downstream, uint(len(body)+10), true, log.DefaultLogger)
+	response = serve("gzip", "/")
+	assert.Empty(t, response.Header().Get("Content-Encoding"))
+	assert.Equal(t, body, response.Body.String())
+	response = serve("gzip", "/?prefix=0123456789")
+	assert.Equal(t, "gzip", response.Header().Get("Content-Encoding"))
+}
@@ -0,0 +1,239 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+
+	"github.com/stellar/go/support/log"
+)
+
+// ServerBusyCode is the JSON RPC error code returned to requests rejected
+// because the concurrency limit of a method was reached. Clients should
+// retry them later on.
+const ServerBusyCode = -32006
+
+// MethodConcurrencyLimit configures the concurrency limit of a JSON RPC method
+type MethodConcurrencyLimit struct {
+	// MaxConcurrent is the maximum number of calls to the method executing concurrently
+	MaxConcurrent uint
+	// MaxQueued is the maximum number of calls waiting for an execution slot
+	// once MaxConcurrent is reached. Calls beyond it are rejected right away.
+	MaxQueued uint
+	// MaxWait is the maximum amount of time a call waits for an execution slot
+	MaxWait time.Duration
+	// ActiveGauge and QueuedGauge (optional) track the executing and waiting calls
+	ActiveGauge gauge
+	QueuedGauge gauge
+	// RejectedCounter (optional) is incremented whenever a request is rejected
+	RejectedCounter increasingCounter
+}
+
+type methodConcurrencyLimiter struct {
+	MethodConcurrencyLimit
+	slots  chan struct{}
+	queued int64
+}
+
+func (l *methodConcurrencyLimiter) retryAfter() time.Duration {
+	if l.MaxWait < time.Second {
+		return time.Second
+	}
+	return l.MaxWait
+}
+
+// acquire waits (within the limits of the queue) for n execution slots
+func (l *methodConcurrencyLimiter) acquire(ctx context.Context, n int) bool {
+	if n > cap(l.slots) {
+		// batches larger than the limit would never go through
+		n = cap(l.slots)
+	}
+	acquired := 0
+	for ; acquired < n; acquired++ {
+		select {
+		case l.slots <- struct{}{}:
+			continue
+		default:
+		}
+		if !l.wait(ctx) {
+			l.release(acquired)
+			return false
+		}
+	}
+	if l.ActiveGauge != nil {
+		for i := 0; i < acquired; i++ {
+			l.ActiveGauge.Inc()
+		}
+	}
+	return true
+}
+
+func (l *methodConcurrencyLimiter) wait(ctx context.Context) bool {
+	if l.MaxQueued == 0 {
+		return false
+	}
+	if queued := atomic.AddInt64(&l.queued, 1); queued > int64(l.MaxQueued) {
+		atomic.AddInt64(&l.queued, -1)
+		return false
+	}
+	if l.QueuedGauge != nil {
+		l.QueuedGauge.Inc()
+	}
+	defer func() {
+		atomic.AddInt64(&l.queued, -1)
+		if l.QueuedGauge != nil {
+			l.QueuedGauge.Dec()
+		}
+	}()
+	timer := time.NewTimer(l.MaxWait)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (l *methodConcurrencyLimiter) release(n int) {
+	for i := 0; i < n; i++ {
+		<-l.slots
+	}
+}
+
+// ConcurrencyLimiter caps the amount of concurrent calls to each JSON RPC
+// method, so that bursts of expensive calls can't starve the cheap ones.
+// Calls exceeding the cap wait in a bounded queue and are eventually rejected
+// with ServerBusyCode and a Retry-After hint.
+type ConcurrencyLimiter struct {
+	methods map[string]*methodConcurrencyLimiter
+	logger  *log.Entry
+}
+
+// NewConcurrencyLimiter creates a concurrency limiter for the given methods.
+// Methods without a limit (or with a zero MaxConcurrent) aren't limited.
+func NewConcurrencyLimiter(limits map[string]MethodConcurrencyLimit, logger *log.Entry) *ConcurrencyLimiter {
+	limiter := &ConcurrencyLimiter{
+		methods: make(map[string]*methodConcurrencyLimiter, len(limits)),
+		logger:  logger,
+	}
+	for method, limit := range limits {
+		if limit.MaxConcurrent == 0 {
+			continue
+		}
+		limiter.methods[method] = &methodConcurrencyLimiter{
+			MethodConcurrencyLimit: limit,
+			slots:                  make(chan struct{}, limit.MaxConcurrent),
+		}
+	}
+	return limiter
+}
+
+// Wrap returns a handler which only passes requests to the downstream handler
+// once execution slots are available for all their calls.
+func (l *ConcurrencyLimiter) Wrap(downstream http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if len(l.methods) == 0 {
+			downstream.ServeHTTP(res, req)
+			return
+		}
+		var body []byte
+		if req.Body != nil {
+			var err error
+			body, err = io.ReadAll(req.Body)
+			if err != nil {
+				http.Error(res, err.Error(), http.StatusBadRequest)
+				return
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		calls := parseCalls(body)
+
+		release, rejectedBy := l.acquire(req.Context(), calls)
+		if rejectedBy != nil {
+			if rejectedBy.RejectedCounter != nil {
+				rejectedBy.RejectedCounter.Inc()
+			}
+			if l.logger != nil {
+				l.logger.Debugf("rejected request, the concurrency limit of %d concurrent calls was reached", rejectedBy.MaxConcurrent)
+			}
+			writeErrorResponse(res, calls, http.StatusServiceUnavailable, rejectedBy.retryAfter(),
+				ServerBusyCode, "server busy, try again later")
+			return
+		}
+		defer release()
+		downstream.ServeHTTP(res, req)
+	})
+}
+
+// AcquireCall waits (within the limits of the queue) for an execution slot
+// for a call to the given method, for calls which aren't performed through
+// HTTP requests of their own. The returned function releases the slot.
+func (l *ConcurrencyLimiter) AcquireCall(ctx context.Context, method string) (func(), error) {
+	release, rejectedBy := l.acquire(ctx, []jsonRPCCall{{Method: method}})
+	if rejectedBy == nil {
+		return release, nil
+	}
+	if rejectedBy.RejectedCounter != nil {
+		rejectedBy.RejectedCounter.Inc()
+	}
+	if l.logger != nil {
+		l.logger.Debugf("rejected %s call, the concurrency limit of %d concurrent calls was reached", method, rejectedBy.MaxConcurrent)
+	}
+	return nil, &jrpc2.Error{
+		Code:    ServerBusyCode,
+		Message: "server busy, try again later",
+	}
+}
+
+// acquire obtains the execution slots required by the calls, returning the
+// limiter of the saturated method if they couldn't be obtained.
+func (l *ConcurrencyLimiter) acquire(ctx context.Context, calls []jsonRPCCall) (func(), *methodConcurrencyLimiter) {
+	counts := map[string]int{}
+	for _, call := range calls {
+		if _, ok := l.methods[call.Method]; ok {
+			counts[call.Method]++
+		}
+	}
+	// acquire the slots in the same order across requests,
+	// to avoid batches holding slots needed by each other
+	methods := make([]string, 0, len(counts))
+	for method := range counts {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	var acquired []string
+	release := func() {
+		for _, method := range acquired {
+			limiter := l.methods[method]
+			n := counts[method]
+			if n > cap(limiter.slots) {
+				n = cap(limiter.slots)
+			}
+			if limiter.ActiveGauge != nil {
+				for i := 0; i < n; i++ {
+					limiter.ActiveGauge.Dec()
+				}
+			}
+			limiter.release(n)
+		}
+	}
+	for _, method := range methods {
+		limiter := l.methods[method]
+		if !limiter.acquire(ctx, counts[method]) {
+			release()
+			return nil, limiter
+		}
+		acquired = append(acquired, method)
+	}
+	return release, nil
+}
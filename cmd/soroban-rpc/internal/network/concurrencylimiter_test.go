@@ -0,0 +1,141 @@
+package network
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func concurrencyLimitedRequest(handler http.Handler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	return res
+}
+
+func TestConcurrencyLimiter(t *testing.T) {
+	unblock := make(chan struct{})
+	var executing int64
+	downstream := &TestingHandlerWrapper{f: func(res http.ResponseWriter, req *http.Request) {
+		atomic.AddInt64(&executing, 1)
+		if strings.Contains(req.URL.RawQuery, "block") {
+			<-unblock
+		}
+	}}
+	activeGauge, queuedGauge := &TestingGauge{}, &TestingGauge{}
+	rejectedCounter := &TestingCounter{}
+	limiter := NewConcurrencyLimiter(map[string]MethodConcurrencyLimit{
+		"simulateTransaction": {
+			MaxConcurrent:   1,
+			MaxQueued:       1,
+			MaxWait:         time.Minute,
+			ActiveGauge:     activeGauge,
+			QueuedGauge:     queuedGauge,
+			RejectedCounter: rejectedCounter,
+		},
+	}, nil)
+	handler := limiter.Wrap(downstream)
+
+	simulate := `{"jsonrpc": "2.0", "id": 1, "method": "simulateTransaction"}`
+	var wg sync.WaitGroup
+	results := make([]int, 2)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/?block", strings.NewReader(simulate))
+			res := httptest.NewRecorder()
+			handler.ServeHTTP(res, req)
+			results[i] = res.Code
+		}(i)
+		// wait for the request to execute or to be queued
+		require.Eventually(t, func() bool {
+			return atomic.LoadInt64(&executing)+atomic.LoadInt64(&queuedGauge.count) == int64(i+1)
+		}, time.Second, time.Millisecond)
+	}
+	assert.Equal(t, int64(1), atomic.LoadInt64(&activeGauge.count))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&queuedGauge.count))
+
+	// the queue is full
+	res := concurrencyLimitedRequest(handler, simulate)
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+	assert.Equal(t, "60", res.Header().Get("Retry-After"))
+	assert.JSONEq(t,
+		`{"jsonrpc": "2.0", "id": 1, "error": {"code": -32006, "message": "server busy, try again later"}}`,
+		res.Body.String())
+	assert.Equal(t, int64(1), atomic.LoadInt64(&rejectedCounter.count))
+
+	// other methods aren't affected
+	health := `{"jsonrpc": "2.0", "id": 2, "method": "getHealth"}`
+	assert.Equal(t, http.StatusOK, concurrencyLimitedRequest(handler, health).Code)
+
+	close(unblock)
+	wg.Wait()
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK}, results)
+	assert.Equal(t, int64(0), atomic.LoadInt64(&activeGauge.count))
+	assert.Equal(t, int64(0), atomic.LoadInt64(&queuedGauge.count))
+
+	// batches larger than the limit still go through
+	batch := `[{"jsonrpc": "2.0", "id": 3, "method": "simulateTransaction"}, {"jsonrpc": "2.0", "id": 4, "method": "simulateTransaction"}]`
+	assert.Equal(t, http.StatusOK, concurrencyLimitedRequest(handler, batch).Code)
+}
+
+func TestConcurrencyLimiterQueueTimeout(t *testing.T) {
+	unblock := make(chan struct{})
+	started := make(chan struct{})
+	downstream := &TestingHandlerWrapper{f: func(res http.ResponseWriter, req *http.Request) {
+		close(started)
+		<-unblock
+	}}
+	limiter := NewConcurrencyLimiter(map[string]MethodConcurrencyLimit{
+		"simulateTransaction": {MaxConcurrent: 1, MaxQueued: 1, MaxWait: 10 * time.Millisecond},
+	}, nil)
+	handler := limiter.Wrap(downstream)
+
+	simulate := `{"jsonrpc": "2.0", "id": 1, "method": "simulateTransaction"}`
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		concurrencyLimitedRequest(handler, simulate)
+	}()
+	<-started
+
+	res := concurrencyLimitedRequest(handler, simulate)
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+	// the retry hint is at least a second
+	assert.Equal(t, "1", res.Header().Get("Retry-After"))
+
+	close(unblock)
+	<-done
+}
+
+func TestConcurrencyLimiterAcquireCall(t *testing.T) {
+	rejectedCounter := &TestingCounter{}
+	limiter := NewConcurrencyLimiter(map[string]MethodConcurrencyLimit{
+		"simulateTransaction": {MaxConcurrent: 1, RejectedCounter: rejectedCounter},
+	}, nil)
+
+	release, err := limiter.AcquireCall(context.Background(), "simulateTransaction")
+	require.NoError(t, err)
+	_, err = limiter.AcquireCall(context.Background(), "simulateTransaction")
+	assert.EqualError(t, err, "[-32006] server busy, try again later")
+	assert.Equal(t, int64(1), rejectedCounter.count)
+
+	// other methods aren't limited
+	releaseOther, err := limiter.AcquireCall(context.Background(), "getHealth")
+	require.NoError(t, err)
+	releaseOther()
+
+	release()
+	release, err = limiter.AcquireCall(context.Background(), "simulateTransaction")
+	require.NoError(t, err)
+	release()
+}
@@ -0,0 +1,331 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"golang.org/x/time/rate"
+
+	"github.com/stellar/go/support/log"
+)
+
+// RateLimitedCode is the JSON RPC error code returned to rate limited requests
+const RateLimitedCode = -32005
+
+// idle clients are forgotten after this period of time
+const rateLimiterClientExpiration = 10 * time.Minute
+
+// RateLimiterConfig configures the token buckets of the rate limiter. A zero
+// rate disables the corresponding bucket.
+type RateLimiterConfig struct {
+	// GlobalRate is the amount of requests per second allowed across all clients
+	GlobalRate  float64
+	GlobalBurst uint
+	// ClientRate is the amount of requests per second allowed for each client IP
+	ClientRate  float64
+	ClientBurst uint
+	// MethodWeights is the amount of tokens consumed by each call to a method (1 by default)
+	MethodWeights map[string]uint
+	// TrustedProxies is the amount of trusted proxies in front of the server, used
+	// to identify clients by the X-Forwarded-For header (0 ignores the header)
+	TrustedProxies uint
+}
+
+// Enabled indicates whether any limit is configured
+func (c RateLimiterConfig) Enabled() bool {
+	return c.GlobalRate > 0 || c.ClientRate > 0
+}
+
+type clientRateLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimiter applies token-bucket rate limiting to HTTP requests carrying
+// JSON RPC calls, both globally and per client IP.
+type RateLimiter struct {
+	cfg           RateLimiterConfig
+	global        *rate.Limiter
+	globalCounter increasingCounter
+	clientCounter increasingCounter
+	logger        *log.Entry
+
+	clientsLock sync.Mutex
+	clients     map[string]*clientRateLimiter
+	lastSweep   time.Time
+}
+
+func burst(r float64, b uint) int {
+	if b == 0 {
+		b = uint(math.Max(1, math.Ceil(r)))
+	}
+	return int(b)
+}
+
+// NewRateLimiter creates a rate limiter, the counters are incremented whenever a
+// request is rejected due to the global or the per-client limits respectively.
+func NewRateLimiter(cfg RateLimiterConfig, globalCounter increasingCounter, clientCounter increasingCounter, logger *log.Entry) *RateLimiter {
+	limiter := &RateLimiter{
+		cfg:           cfg,
+		globalCounter: globalCounter,
+		clientCounter: clientCounter,
+		logger:        logger,
+		clients:       map[string]*clientRateLimiter{},
+		lastSweep:     time.Now(),
+	}
+	if cfg.GlobalRate > 0 {
+		limiter.global = rate.NewLimiter(rate.Limit(cfg.GlobalRate), burst(cfg.GlobalRate, cfg.GlobalBurst))
+	}
+	return limiter
+}
+
+// Wrap returns a handler which rate-limits the requests before passing them
+// to the downstream handler. All the handlers wrapped by the same RateLimiter
+// share the token buckets.
+func (l *RateLimiter) Wrap(downstream http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		var body []byte
+		if req.Body != nil {
+			var err error
+			body, err = io.ReadAll(req.Body)
+			if err != nil {
+				http.Error(res, err.Error(), http.StatusBadRequest)
+				return
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		calls := parseCalls(body)
+
+		ok, delay, scope := l.allow(l.clientKey(req), l.weight(calls), time.Now())
+		if ok {
+			downstream.ServeHTTP(res, req)
+			return
+		}
+		if l.logger != nil {
+			l.logger.WithField("client", l.clientKey(req)).Debugf("rate limited request (%s limit)", scope)
+		}
+		writeRateLimitedResponse(res, calls, delay, scope)
+	})
+}
+
+type rateLimiterClientKey struct{}
+
+// WrapCalls returns a handler which, rather than rate-limiting whole requests,
+// lets the downstream handler rate-limit each of the calls it performs on
+// behalf of a request through AllowCall.
+func (l *RateLimiter) WrapCalls(downstream http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		ctx := context.WithValue(req.Context(), rateLimiterClientKey{}, l.clientKey(req))
+		downstream.ServeHTTP(res, req.WithContext(ctx))
+	})
+}
+
+// AllowCall takes the tokens of a call to the given method from the buckets
+// of the client of the request (see WrapCalls), returning a RateLimitedCode
+// error if they are exhausted.
+func (l *RateLimiter) AllowCall(ctx context.Context, method string) error {
+	clientKey, _ := ctx.Value(rateLimiterClientKey{}).(string)
+	ok, _, scope := l.allow(clientKey, l.weight([]jsonRPCCall{{Method: method}}), time.Now())
+	if ok {
+		return nil
+	}
+	if l.logger != nil {
+		l.logger.WithField("client", clientKey).Debugf("rate limited %s call (%s limit)", method, scope)
+	}
+	return &jrpc2.Error{
+		Code:    RateLimitedCode,
+		Message: "rate limit exceeded (" + scope + " limit)",
+	}
+}
+
+func (l *RateLimiter) weight(calls []jsonRPCCall) int {
+	weight := 0
+	for _, call := range calls {
+		if w, ok := l.cfg.MethodWeights[call.Method]; ok {
+			weight += int(w)
+		} else {
+			weight++
+		}
+	}
+	if weight == 0 {
+		weight = 1
+	}
+	return weight
+}
+
+func (l *RateLimiter) clientKey(req *http.Request) string {
+	return ClientIP(req, l.cfg.TrustedProxies)
+}
+
+// ClientIP returns the IP of the client originating the request. Behind
+// trustedProxies proxies, the client is the X-Forwarded-For entry appended by
+// the outermost of them: the entries to its left are provided by the client
+// itself, so they can be forged.
+func ClientIP(req *http.Request, trustedProxies uint) string {
+	if forwarded := req.Header.Values("X-Forwarded-For"); trustedProxies > 0 && len(forwarded) > 0 {
+		entries := strings.Split(strings.Join(forwarded, ","), ",")
+		i := len(entries) - int(trustedProxies)
+		if i < 0 {
+			// the request went through fewer proxies than expected
+			i = 0
+		}
+		if entry := strings.TrimSpace(entries[i]); entry != "" {
+			return entry
+		}
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+func (l *RateLimiter) clientLimiter(key string, now time.Time) *rate.Limiter {
+	l.clientsLock.Lock()
+	defer l.clientsLock.Unlock()
+	if now.Sub(l.lastSweep) > rateLimiterClientExpiration {
+		for k, client := range l.clients {
+			if now.Sub(client.lastSeen) > rateLimiterClientExpiration {
+				delete(l.clients, k)
+			}
+		}
+		l.lastSweep = now
+	}
+	client, ok := l.clients[key]
+	if !ok {
+		client = &clientRateLimiter{
+			limiter: rate.NewLimiter(rate.Limit(l.cfg.ClientRate), burst(l.cfg.ClientRate, l.cfg.ClientBurst)),
+		}
+		l.clients[key] = client
+	}
+	client.lastSeen = now
+	return client.limiter
+}
+
+// reserve tries to take n tokens from the bucket right away
+func reserve(limiter *rate.Limiter, n int, now time.Time) (*rate.Reservation, time.Duration, bool) {
+	if n > limiter.Burst() {
+		// requests heavier than the bucket size would never go through
+		n = limiter.Burst()
+	}
+	reservation := limiter.ReserveN(now, n)
+	if !reservation.OK() {
+		return nil, time.Second, false
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return nil, delay, false
+	}
+	return reservation, 0, true
+}
+
+func (l *RateLimiter) allow(clientKey string, weight int, now time.Time) (bool, time.Duration, string) {
+	var clientReservation *rate.Reservation
+	if l.cfg.ClientRate > 0 {
+		var (
+			delay time.Duration
+			ok    bool
+		)
+		clientReservation, delay, ok = reserve(l.clientLimiter(clientKey, now), weight, now)
+		if !ok {
+			if l.clientCounter != nil {
+				l.clientCounter.Inc()
+			}
+			return false, delay, "per-client"
+		}
+	}
+	if l.global != nil {
+		if _, delay, ok := reserve(l.global, weight, now); !ok {
+			// give the tokens back to the client, since the request is rejected anyways
+			if clientReservation != nil {
+				clientReservation.CancelAt(now)
+			}
+			if l.globalCounter != nil {
+				l.globalCounter.Inc()
+			}
+			return false, delay, "global"
+		}
+	}
+	return true, 0, ""
+}
+
+type jsonRPCCall struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// parseCalls makes a best-effort attempt at extracting the JSON RPC calls of
+// a request, leaving proper validation to the downstream handler.
+func parseCalls(body []byte) []jsonRPCCall {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var calls []jsonRPCCall
+		if err := json.Unmarshal(trimmed, &calls); err == nil {
+			return calls
+		}
+		return nil
+	}
+	var call jsonRPCCall
+	if err := json.Unmarshal(trimmed, &call); err != nil {
+		return nil
+	}
+	return []jsonRPCCall{call}
+}
+
+type jsonRPCErrorResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Error   struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func writeRateLimitedResponse(res http.ResponseWriter, calls []jsonRPCCall, delay time.Duration, scope string) {
+	writeErrorResponse(res, calls, http.StatusTooManyRequests, delay, RateLimitedCode, "rate limit exceeded ("+scope+" limit)")
+}
+
+// writeErrorResponse replies to all the calls of a request with the same
+// error, hinting the client to retry after the given delay.
+func writeErrorResponse(res http.ResponseWriter, calls []jsonRPCCall, status int, delay time.Duration, code int, message string) {
+	responses := make([]jsonRPCErrorResponse, 0, len(calls))
+	for _, call := range calls {
+		if len(call.ID) == 0 {
+			// notifications don't get responses
+			continue
+		}
+		responses = append(responses, newErrorResponse(call.ID, code, message))
+	}
+	var body []byte
+	if len(calls) == 1 || len(responses) == 0 {
+		id := json.RawMessage("null")
+		if len(responses) > 0 {
+			id = responses[0].ID
+		}
+		body, _ = json.Marshal(newErrorResponse(id, code, message))
+	} else {
+		body, _ = json.Marshal(responses)
+	}
+	res.Header().Set("Content-Type", "application/json")
+	res.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+	res.WriteHeader(status)
+	_, _ = res.Write(body)
+}
+
+func newErrorResponse(id json.RawMessage, code int, message string) jsonRPCErrorResponse {
+	response := jsonRPCErrorResponse{JSONRPC: "2.0", ID: id}
+	response.Error.Code = code
+	response.Error.Message = message
+	return response
+}
@@ -0,0 +1,147 @@
+package network
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rateLimitedRequest(t *testing.T, handler http.Handler, remoteAddr string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.RemoteAddr = remoteAddr
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	return res
+}
+
+func TestRateLimiterPerClient(t *testing.T) {
+	var received []string
+	downstream := &TestingHandlerWrapper{f: func(res http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		received = append(received, string(body))
+	}}
+	clientCounter := &TestingCounter{}
+	limiter := NewRateLimiter(RateLimiterConfig{ClientRate: 0.001, ClientBurst: 2}, nil, clientCounter, nil)
+	handler := limiter.Wrap(downstream)
+
+	body := `{"jsonrpc": "2.0", "id": 1, "method": "getHealth"}`
+	assert.Equal(t, http.StatusOK, rateLimitedRequest(t, handler, "10.0.0.1:1234", body).Code)
+	assert.Equal(t, http.StatusOK, rateLimitedRequest(t, handler, "10.0.0.1:1235", body).Code)
+	res := rateLimitedRequest(t, handler, "10.0.0.1:1236", body)
+	assert.Equal(t, http.StatusTooManyRequests, res.Code)
+	assert.NotEmpty(t, res.Header().Get("Retry-After"))
+	assert.JSONEq(t,
+		`{"jsonrpc": "2.0", "id": 1, "error": {"code": -32005, "message": "rate limit exceeded (per-client limit)"}}`,
+		res.Body.String())
+	assert.Equal(t, int64(1), clientCounter.count)
+
+	// other clients have their own bucket
+	assert.Equal(t, http.StatusOK, rateLimitedRequest(t, handler, "10.0.0.2:1234", body).Code)
+
+	// the body is passed on unmodified
+	assert.Equal(t, []string{body, body, body}, received)
+}
+
+func TestRateLimiterGlobalWithWeights(t *testing.T) {
+	downstream := &TestingHandlerWrapper{f: func(res http.ResponseWriter, req *http.Request) {}}
+	globalCounter := &TestingCounter{}
+	limiter := NewRateLimiter(RateLimiterConfig{
+		GlobalRate:    0.001,
+		GlobalBurst:   10,
+		MethodWeights: map[string]uint{"simulateTransaction": 6},
+	}, globalCounter, nil, nil)
+	handler := limiter.Wrap(downstream)
+
+	simulate := `{"jsonrpc": "2.0", "id": 1, "method": "simulateTransaction"}`
+	assert.Equal(t, http.StatusOK, rateLimitedRequest(t, handler, "10.0.0.1:1234", simulate).Code)
+
+	// a batch accounts for all of its calls
+	batch := `[{"jsonrpc": "2.0", "id": 2, "method": "simulateTransaction"}, {"jsonrpc": "2.0", "id": 3, "method": "getHealth"}]`
+	res := rateLimitedRequest(t, handler, "10.0.0.2:1234", batch)
+	assert.Equal(t, http.StatusTooManyRequests, res.Code)
+	assert.JSONEq(t, `[
+		{"jsonrpc": "2.0", "id": 2, "error": {"code": -32005, "message": "rate limit exceeded (global limit)"}},
+		{"jsonrpc": "2.0", "id": 3, "error": {"code": -32005, "message": "rate limit exceeded (global limit)"}}
+	]`, res.Body.String())
+	assert.Equal(t, int64(1), globalCounter.count)
+
+	// the remaining tokens can still be used by lighter requests
+	health := `{"jsonrpc": "2.0", "id": 4, "method": "getHealth"}`
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, http.StatusOK, rateLimitedRequest(t, handler, "10.0.0.3:1234", health).Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, rateLimitedRequest(t, handler, "10.0.0.3:1234", health).Code)
+}
+
+func TestRateLimiterForwardedFor(t *testing.T) {
+	limiter := NewRateLimiter(RateLimiterConfig{ClientRate: 1, TrustedProxies: 1}, nil, nil, nil)
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	assert.Equal(t, "10.0.0.1", limiter.clientKey(req))
+	req.Header.Set("X-Forwarded-For", "192.168.1.1")
+	assert.Equal(t, "192.168.1.1", limiter.clientKey(req))
+
+	// the header is ignored unless there are trusted proxies
+	untrusting := NewRateLimiter(RateLimiterConfig{ClientRate: 1}, nil, nil, nil)
+	assert.Equal(t, "10.0.0.1", untrusting.clientKey(req))
+
+	// behind two proxies, the client is the entry appended by the outermost one
+	twoProxies := NewRateLimiter(RateLimiterConfig{ClientRate: 1, TrustedProxies: 2}, nil, nil, nil)
+	req.Header.Set("X-Forwarded-For", "1.2.3.4, 192.168.1.1, 10.0.0.2")
+	assert.Equal(t, "192.168.1.1", twoProxies.clientKey(req))
+	// the request went through fewer proxies than expected
+	req.Header.Set("X-Forwarded-For", "192.168.1.1")
+	assert.Equal(t, "192.168.1.1", twoProxies.clientKey(req))
+}
+
+func TestRateLimiterForgedForwardedFor(t *testing.T) {
+	downstream := &TestingHandlerWrapper{f: func(res http.ResponseWriter, req *http.Request) {}}
+	limiter := NewRateLimiter(RateLimiterConfig{ClientRate: 0.001, ClientBurst: 1, TrustedProxies: 1}, nil, nil, nil)
+	handler := limiter.Wrap(downstream)
+
+	// the client forges an X-Forwarded-For header, to which the proxy appends the actual client address
+	forgedRequest := func(forged string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"jsonrpc": "2.0", "id": 1, "method": "getHealth"}`))
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-Forwarded-For", forged+", 203.0.113.7")
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res
+	}
+	assert.Equal(t, http.StatusOK, forgedRequest("1.1.1.1").Code)
+	// forging a different address doesn't give the client a new bucket
+	assert.Equal(t, http.StatusTooManyRequests, forgedRequest("2.2.2.2").Code)
+}
+
+func TestRateLimiterAllowCall(t *testing.T) {
+	limiter := NewRateLimiter(RateLimiterConfig{
+		ClientRate:    0.001,
+		ClientBurst:   10,
+		MethodWeights: map[string]uint{"getTransaction": 4},
+	}, nil, nil, nil)
+	var errs []error
+	downstream := &TestingHandlerWrapper{f: func(res http.ResponseWriter, req *http.Request) {
+		// every call performed on behalf of the request is charged
+		for i := 0; i < 3; i++ {
+			errs = append(errs, limiter.AllowCall(req.Context(), "getTransaction"))
+		}
+	}}
+	handler := limiter.WrapCalls(downstream)
+
+	assert.Equal(t, http.StatusOK, rateLimitedRequest(t, handler, "10.0.0.1:1234", `{"query": "{ health { status } }"}`).Code)
+	require.Len(t, errs, 3)
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	assert.EqualError(t, errs[2], "[-32005] rate limit exceeded (per-client limit)")
+
+	// other clients have their own bucket
+	errs = nil
+	rateLimitedRequest(t, handler, "10.0.0.2:1234", `{"query": "{ health { status } }"}`)
+	assert.NoError(t, errs[0])
+}
@@ -3,17 +3,21 @@ package preflight
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/stellar/go/support/log"
 	"github.com/stellar/go/xdr"
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/tracing"
 )
 
 type workerResult struct {
@@ -38,6 +42,7 @@ type WorkerPool struct {
 	errorFullCounter           prometheus.Counter
 	durationMetric             *prometheus.SummaryVec
 	ledgerEntriesFetchedMetric prometheus.Summary
+	selfTestErr                error
 	wg                         sync.WaitGroup
 }
 
@@ -100,6 +105,10 @@ func NewPreflightWorkerPool(cfg WorkerPoolConfig) *WorkerPool {
 		preflightWP.durationMetric,
 		preflightWP.ledgerEntriesFetchedMetric,
 	)
+	if err := SelfTest(cfg.Logger, cfg.NetworkPassphrase); err != nil {
+		cfg.Logger.WithError(err).Error("preflight self-test failed, simulations will be refused")
+		preflightWP.selfTestErr = err
+	}
 	for range cfg.WorkerCount {
 		preflightWP.wg.Add(1)
 		go preflightWP.work()
@@ -107,12 +116,24 @@ func NewPreflightWorkerPool(cfg WorkerPoolConfig) *WorkerPool {
 	return &preflightWP
 }
 
+// CheckCompatibility returns an error if simulations at the given protocol
+// version can't be trusted, either because the preflight library failed its
+// startup self-test or because it doesn't support the protocol.
+func (pwp *WorkerPool) CheckCompatibility(protocolVersion uint32) error {
+	if pwp.selfTestErr != nil {
+		return fmt.Errorf("preflight self-test failed: %w", pwp.selfTestErr)
+	}
+	return CheckProtocolVersion(protocolVersion)
+}
+
 func (pwp *WorkerPool) work() {
 	defer pwp.wg.Done()
 	for request := range pwp.requestChan {
 		pwp.concurrentRequestsMetric.Inc()
 		startTime := time.Now()
+		_, span := tracing.Tracer().Start(request.ctx, "preflight.simulate")
 		preflight, err := GetPreflight(request.ctx, request.params)
+		tracing.End(span, err)
 		status := "ok"
 		if err != nil {
 			status = "error"
@@ -150,7 +171,10 @@ func (m *metricsLedgerEntryWrapper) GetLedgerEntries(keys ...xdr.LedgerKey) ([]d
 	return entries, err
 }
 
-func (pwp *WorkerPool) GetPreflight(ctx context.Context, params GetterParameters) (Preflight, error) {
+func (pwp *WorkerPool) GetPreflight(ctx context.Context, params GetterParameters) (_ Preflight, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "preflight.GetPreflight",
+		trace.WithAttributes(attribute.String("operation", params.OperationBody.Type.String())))
+	defer func() { tracing.End(span, err) }()
 	if pwp.isClosed.Load() {
 		return Preflight{}, errors.New("preflight worker pool is closed")
 	}
@@ -166,6 +190,7 @@ func (pwp *WorkerPool) GetPreflight(ctx context.Context, params GetterParameters
 		BucketListSize:    params.BucketListSize,
 		Footprint:         params.Footprint,
 		ResourceConfig:    params.ResourceConfig,
+		AuthMode:          params.AuthMode,
 		EnableDebug:       pwp.enableDebug,
 		ProtocolVersion:   params.ProtocolVersion,
 	}
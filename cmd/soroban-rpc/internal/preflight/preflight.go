@@ -77,6 +77,11 @@ func FreeGoXDR(xdr C.xdr_t) {
 	C.free(unsafe.Pointer(xdr.xdr))
 }
 
+// MaxSupportedProtocolVersion returns the latest ledger protocol version supported by the preflight library.
+func MaxSupportedProtocolVersion() uint32 {
+	return uint32(C.preflight_max_supported_protocol_version())
+}
+
 type ResourceConfig struct {
 	InstructionLeeway uint64 `json:"instructionLeeway"`
 }
@@ -87,6 +92,38 @@ func DefaultResourceConfig() ResourceConfig {
 	}
 }
 
+// AuthMode tells how the authorization of invoked host functions is simulated
+type AuthMode string
+
+const (
+	// AuthModeDefault records the required authorizations if the operation has
+	// no authorization entries, and enforces its authorization entries otherwise
+	AuthModeDefault AuthMode = ""
+	// AuthModeEnforce enforces the authorization entries (and thus the signatures) of the operation
+	AuthModeEnforce AuthMode = "enforce"
+	// AuthModeRecord records the required authorizations, the operation must have no authorization entries
+	AuthModeRecord AuthMode = "record"
+)
+
+// Valid checks that the auth mode is known
+func (m AuthMode) Valid() error {
+	_, err := m.cAuthMode()
+	return err
+}
+
+func (m AuthMode) cAuthMode() (C.auth_mode_t, error) {
+	switch m {
+	case AuthModeDefault:
+		return C.AUTH_MODE_DEFAULT, nil
+	case AuthModeEnforce:
+		return C.AUTH_MODE_ENFORCE, nil
+	case AuthModeRecord:
+		return C.AUTH_MODE_RECORD, nil
+	default:
+		return 0, fmt.Errorf("unknown auth mode %q, must be one of: %s or %s", m, AuthModeEnforce, AuthModeRecord)
+	}
+}
+
 type GetterParameters struct {
 	LedgerEntryReadTx db.LedgerEntryReadTx
 	BucketListSize    uint64
@@ -94,6 +131,7 @@ type GetterParameters struct {
 	OperationBody     xdr.OperationBody
 	Footprint         xdr.LedgerFootprint
 	ResourceConfig    ResourceConfig
+	AuthMode          AuthMode
 	ProtocolVersion   uint32
 }
 
@@ -106,6 +144,7 @@ type Parameters struct {
 	LedgerEntryReadTx db.LedgerEntryReadTx
 	BucketListSize    uint64
 	ResourceConfig    ResourceConfig
+	AuthMode          AuthMode
 	EnableDebug       bool
 	ProtocolVersion   uint32
 }
@@ -246,6 +285,10 @@ func getInvokeHostFunctionPreflight(params Parameters) (Preflight, error) {
 	if err != nil {
 		return Preflight{}, err
 	}
+	authMode, err := params.AuthMode.cAuthMode()
+	if err != nil {
+		return Preflight{}, err
+	}
 
 	handle := cgo.NewHandle(snapshotSourceHandle{params.LedgerEntryReadTx, params.Logger})
 	defer handle.Delete()
@@ -258,6 +301,7 @@ func getInvokeHostFunctionPreflight(params Parameters) (Preflight, error) {
 		sourceAccountCXDR,
 		ledgerInfo,
 		resourceConfig,
+		authMode,
 		C.bool(params.EnableDebug),
 	)
 	FreeGoXDR(invokeHostFunctionCXDR)
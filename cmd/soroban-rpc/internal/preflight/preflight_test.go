@@ -248,52 +248,6 @@ var helloWorldContract = func() []byte {
 	return ret
 }()
 
-type inMemoryLedgerEntryReadTx map[string]xdr.LedgerEntry
-
-func (m inMemoryLedgerEntryReadTx) GetLedgerEntries(keys ...xdr.LedgerKey) ([]db.LedgerKeyAndEntry, error) {
-	result := make([]db.LedgerKeyAndEntry, 0, len(keys))
-	for _, key := range keys {
-		serializedKey, err := key.MarshalBinaryBase64()
-		if err != nil {
-			return nil, err
-		}
-		entry, ok := m[serializedKey]
-		if !ok {
-			continue
-		}
-		// We don't check the TTL but that's ok for the test
-		result = append(result, db.LedgerKeyAndEntry{
-			Key:   key,
-			Entry: entry,
-		})
-	}
-	return result, nil
-}
-
-func newInMemoryLedgerEntryReadTx(entries []xdr.LedgerEntry) (inMemoryLedgerEntryReadTx, error) {
-	result := make(map[string]xdr.LedgerEntry, len(entries))
-	for _, entry := range entries {
-		key, err := entry.LedgerKey()
-		if err != nil {
-			return inMemoryLedgerEntryReadTx{}, err
-		}
-		serialized, err := key.MarshalBinaryBase64()
-		if err != nil {
-			return inMemoryLedgerEntryReadTx{}, err
-		}
-		result[serialized] = entry
-	}
-	return result, nil
-}
-
-func (m inMemoryLedgerEntryReadTx) GetLatestLedgerSequence() (uint32, error) {
-	return 2, nil
-}
-
-func (m inMemoryLedgerEntryReadTx) Done() error {
-	return nil
-}
-
 func getDB(t testing.TB, restartDB bool) *db.DB {
 	dbPath := path.Join(t.TempDir(), "soroban_rpc.sqlite")
 	dbInstance, err := db.OpenSQLiteDB(dbPath)
@@ -419,6 +373,46 @@ func TestGetPreflightDebug(t *testing.T) {
 	require.Contains(t, resultWithoutDebug.Error, "DebugInfo not available")
 }
 
+func TestGetPreflightAuthMode(t *testing.T) {
+	for _, authMode := range []AuthMode{AuthModeDefault, AuthModeEnforce, AuthModeRecord} {
+		params := getPreflightParameters(t, nil)
+		params.AuthMode = authMode
+		result, err := GetPreflight(context.Background(), params)
+		require.NoError(t, err)
+		require.Empty(t, result.Error, "auth mode %q", authMode)
+	}
+
+	// recording requires the operation to have no authorization entries
+	params := getPreflightParameters(t, nil)
+	params.AuthMode = AuthModeRecord
+	params.OpBody.InvokeHostFunctionOp.Auth = []xdr.SorobanAuthorizationEntry{
+		{
+			Credentials: xdr.SorobanCredentials{Type: xdr.SorobanCredentialsTypeSorobanCredentialsSourceAccount},
+			RootInvocation: xdr.SorobanAuthorizedInvocation{
+				Function: xdr.SorobanAuthorizedFunction{
+					Type:       xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+					ContractFn: params.OpBody.InvokeHostFunctionOp.HostFunction.InvokeContract,
+				},
+			},
+		},
+	}
+	result, err := GetPreflight(context.Background(), params)
+	require.NoError(t, err)
+	require.Contains(t, result.Error, "authorization entries must be empty")
+
+	params.AuthMode = "sign"
+	_, err = GetPreflight(context.Background(), params)
+	require.ErrorContains(t, err, `unknown auth mode "sign"`)
+}
+
+func TestAuthModeValid(t *testing.T) {
+	for _, authMode := range []AuthMode{AuthModeDefault, AuthModeEnforce, AuthModeRecord} {
+		require.NoError(t, authMode.Valid())
+	}
+	// the simulation library doesn't support recording non-root authorizations
+	require.Error(t, AuthMode("record_allow_nonroot").Valid())
+}
+
 type benchmarkDBConfig struct {
 	restart      bool
 	disableCache bool
@@ -0,0 +1,295 @@
+package preflight
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+)
+
+// MinSupportedProtocolVersion is the first protocol version supporting Soroban
+const MinSupportedProtocolVersion = 20
+
+// CheckProtocolVersion verifies that the preflight library supports the given (network) protocol version.
+func CheckProtocolVersion(protocolVersion uint32) error {
+	maxSupported := MaxSupportedProtocolVersion()
+	if protocolVersion < MinSupportedProtocolVersion || protocolVersion > maxSupported {
+		return fmt.Errorf(
+			"network protocol version %d is outside of the range supported by the preflight library [%d, %d]",
+			protocolVersion, MinSupportedProtocolVersion, maxSupported,
+		)
+	}
+	return nil
+}
+
+// selfTestSnapshot is a synthetic ledger snapshot containing the network
+// configuration required by simulations and a persistent contract data entry.
+func selfTestSnapshot() ([]xdr.LedgerEntry, xdr.LedgerKey, error) {
+	contractID := xdr.Hash{0x1}
+	keySymbol := xdr.ScSymbol("self_test")
+	dataEntry := xdr.LedgerEntry{
+		LastModifiedLedgerSeq: 1,
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeContractData,
+			ContractData: &xdr.ContractDataEntry{
+				Contract: xdr.ScAddress{
+					Type:       xdr.ScAddressTypeScAddressTypeContract,
+					ContractId: &contractID,
+				},
+				Key:        xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &keySymbol},
+				Durability: xdr.ContractDataDurabilityPersistent,
+				Val:        xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+			},
+		},
+	}
+	dataKey, err := dataEntry.LedgerKey()
+	if err != nil {
+		return nil, xdr.LedgerKey{}, err
+	}
+	dataKeyBin, err := dataKey.MarshalBinary()
+	if err != nil {
+		return nil, xdr.LedgerKey{}, err
+	}
+
+	// one entry per cost type
+	costTypes := int(xdr.ContractCostTypeVerifyEcdsaSecp256r1Sig) + 1
+	costParams := make(xdr.ContractCostParams, 0, costTypes)
+	for range costTypes {
+		costParams = append(costParams, xdr.ContractCostParamEntry{ConstTerm: 10, LinearTerm: 1})
+	}
+	maxSize := xdr.Uint32(64 * 1024)
+	configSettings := []xdr.ConfigSettingEntry{
+		{
+			ConfigSettingId:      xdr.ConfigSettingIdConfigSettingContractMaxSizeBytes,
+			ContractMaxSizeBytes: &maxSize,
+		},
+		{
+			ConfigSettingId: xdr.ConfigSettingIdConfigSettingContractComputeV0,
+			ContractCompute: &xdr.ConfigSettingContractComputeV0{
+				LedgerMaxInstructions:           100_000_000,
+				TxMaxInstructions:               100_000_000,
+				FeeRatePerInstructionsIncrement: 1,
+				TxMemoryLimit:                   100_000_000,
+			},
+		},
+		{
+			ConfigSettingId: xdr.ConfigSettingIdConfigSettingContractLedgerCostV0,
+			ContractLedgerCost: &xdr.ConfigSettingContractLedgerCostV0{
+				LedgerMaxReadLedgerEntries:     100,
+				LedgerMaxReadBytes:             100_000,
+				LedgerMaxWriteLedgerEntries:    100,
+				LedgerMaxWriteBytes:            100_000,
+				TxMaxReadLedgerEntries:         100,
+				TxMaxReadBytes:                 100_000,
+				TxMaxWriteLedgerEntries:        100,
+				TxMaxWriteBytes:                100_000,
+				FeeReadLedgerEntry:             100,
+				FeeWriteLedgerEntry:            100,
+				FeeRead1Kb:                     100,
+				BucketListTargetSizeBytes:      100_000,
+				WriteFee1KbBucketListLow:       1,
+				WriteFee1KbBucketListHigh:      10,
+				BucketListWriteFeeGrowthFactor: 1,
+			},
+		},
+		{
+			ConfigSettingId:        xdr.ConfigSettingIdConfigSettingContractHistoricalDataV0,
+			ContractHistoricalData: &xdr.ConfigSettingContractHistoricalDataV0{FeeHistorical1Kb: 100},
+		},
+		{
+			ConfigSettingId: xdr.ConfigSettingIdConfigSettingContractEventsV0,
+			ContractEvents: &xdr.ConfigSettingContractEventsV0{
+				TxMaxContractEventsSizeBytes: 10_000,
+				FeeContractEvents1Kb:         1,
+			},
+		},
+		{
+			ConfigSettingId: xdr.ConfigSettingIdConfigSettingContractBandwidthV0,
+			ContractBandwidth: &xdr.ConfigSettingContractBandwidthV0{
+				LedgerMaxTxsSizeBytes: 100_000,
+				TxMaxSizeBytes:        10_000,
+				FeeTxSize1Kb:          1,
+			},
+		},
+		{
+			ConfigSettingId:            xdr.ConfigSettingIdConfigSettingContractCostParamsCpuInstructions,
+			ContractCostParamsCpuInsns: &costParams,
+		},
+		{
+			ConfigSettingId:            xdr.ConfigSettingIdConfigSettingContractCostParamsMemoryBytes,
+			ContractCostParamsMemBytes: &costParams,
+		},
+		{
+			ConfigSettingId:          xdr.ConfigSettingIdConfigSettingContractDataKeySizeBytes,
+			ContractDataKeySizeBytes: &maxSize,
+		},
+		{
+			ConfigSettingId:            xdr.ConfigSettingIdConfigSettingContractDataEntrySizeBytes,
+			ContractDataEntrySizeBytes: &maxSize,
+		},
+		{
+			ConfigSettingId: xdr.ConfigSettingIdConfigSettingStateArchival,
+			StateArchivalSettings: &xdr.StateArchivalSettings{
+				MaxEntryTtl:                    1_000_000,
+				MinTemporaryTtl:                16,
+				MinPersistentTtl:               4096,
+				PersistentRentRateDenominator:  1000,
+				TempRentRateDenominator:        1000,
+				MaxEntriesToArchive:            100,
+				BucketListSizeWindowSampleSize: 2,
+				EvictionScanSize:               100,
+				StartingEvictionScanLevel:      1,
+			},
+		},
+		{
+			ConfigSettingId:        xdr.ConfigSettingIdConfigSettingContractExecutionLanes,
+			ContractExecutionLanes: &xdr.ConfigSettingContractExecutionLanesV0{LedgerMaxTxCount: 100},
+		},
+		{
+			ConfigSettingId:      xdr.ConfigSettingIdConfigSettingBucketlistSizeWindow,
+			BucketListSizeWindow: &[]xdr.Uint64{100_000, 100_000},
+		},
+		{
+			ConfigSettingId:  xdr.ConfigSettingIdConfigSettingEvictionIterator,
+			EvictionIterator: &xdr.EvictionIterator{BucketListLevel: 1},
+		},
+	}
+
+	entries := []xdr.LedgerEntry{
+		dataEntry,
+		{
+			LastModifiedLedgerSeq: 1,
+			Data: xdr.LedgerEntryData{
+				Type: xdr.LedgerEntryTypeTtl,
+				Ttl: &xdr.TtlEntry{
+					KeyHash:            sha256.Sum256(dataKeyBin),
+					LiveUntilLedgerSeq: 1000,
+				},
+			},
+		},
+	}
+	for i := range configSettings {
+		entries = append(entries, xdr.LedgerEntry{
+			LastModifiedLedgerSeq: 1,
+			Data: xdr.LedgerEntryData{
+				Type:          xdr.LedgerEntryTypeConfigSetting,
+				ConfigSetting: &configSettings[i],
+			},
+		})
+	}
+	return entries, dataKey, nil
+}
+
+// SelfTest simulates a known transaction (extending the TTL of a contract
+// data entry) against a synthetic snapshot and verifies its outcome. It is
+// meant to detect a broken preflight library before serving simulations.
+func SelfTest(logger *log.Entry, networkPassphrase string) error {
+	entries, dataKey, err := selfTestSnapshot()
+	if err != nil {
+		return err
+	}
+	readTx, err := newInMemoryLedgerEntryReadTx(entries)
+	if err != nil {
+		return err
+	}
+	footprint := xdr.LedgerFootprint{ReadOnly: []xdr.LedgerKey{dataKey}}
+	result, err := GetPreflight(context.Background(), Parameters{
+		Logger: logger,
+		OpBody: xdr.OperationBody{
+			Type:                 xdr.OperationTypeExtendFootprintTtl,
+			ExtendFootprintTtlOp: &xdr.ExtendFootprintTtlOp{ExtendTo: 2000},
+		},
+		Footprint:         footprint,
+		NetworkPassphrase: networkPassphrase,
+		LedgerEntryReadTx: readTx,
+		BucketListSize:    100_000,
+		ResourceConfig:    DefaultResourceConfig(),
+		ProtocolVersion:   MaxSupportedProtocolVersion(),
+	})
+	if err != nil {
+		return err
+	}
+	if result.Error != "" {
+		return errors.New(result.Error)
+	}
+	var transactionData xdr.SorobanTransactionData
+	if err := xdr.SafeUnmarshal(result.TransactionData, &transactionData); err != nil {
+		return fmt.Errorf("could not decode simulated transaction data: %w", err)
+	}
+	expectedFootprint, err := footprint.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	simulatedFootprint, err := transactionData.Resources.Footprint.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(expectedFootprint, simulatedFootprint) {
+		return errors.New("unexpected simulated footprint")
+	}
+	if result.MinFee <= 0 {
+		return fmt.Errorf("unexpected simulated minimum fee (%d)", result.MinFee)
+	}
+	return nil
+}
+
+type inMemoryLedgerEntryReadTx map[string]xdr.LedgerEntry
+
+func (m inMemoryLedgerEntryReadTx) GetLedgerEntries(keys ...xdr.LedgerKey) ([]db.LedgerKeyAndEntry, error) {
+	result := make([]db.LedgerKeyAndEntry, 0, len(keys))
+	for _, key := range keys {
+		serializedKey, err := key.MarshalBinaryBase64()
+		if err != nil {
+			return nil, err
+		}
+		entry, ok := m[serializedKey]
+		if !ok {
+			continue
+		}
+		// We don't check the TTL but that's ok for synthetic snapshots
+		result = append(result, db.LedgerKeyAndEntry{
+			Key:   key,
+			Entry: entry,
+		})
+	}
+	return result, nil
+}
+
+func (m inMemoryLedgerEntryReadTx) GetContractDataEntries(xdr.ScAddress, *xdr.LedgerKey, uint) ([]db.LedgerKeyAndEntry, error) {
+	return nil, errors.New("contract data entries cannot be listed in synthetic snapshots")
+}
+
+func (m inMemoryLedgerEntryReadTx) GetArchivedLedgerEntries(...xdr.LedgerKey) ([]db.LedgerKeyAndEntry, error) {
+	// synthetic snapshots have no archived entries
+	return nil, nil
+}
+
+func newInMemoryLedgerEntryReadTx(entries []xdr.LedgerEntry) (inMemoryLedgerEntryReadTx, error) {
+	result := make(map[string]xdr.LedgerEntry, len(entries))
+	for _, entry := range entries {
+		key, err := entry.LedgerKey()
+		if err != nil {
+			return inMemoryLedgerEntryReadTx{}, err
+		}
+		serialized, err := key.MarshalBinaryBase64()
+		if err != nil {
+			return inMemoryLedgerEntryReadTx{}, err
+		}
+		result[serialized] = entry
+	}
+	return result, nil
+}
+
+func (m inMemoryLedgerEntryReadTx) GetLatestLedgerSequence() (uint32, error) {
+	return 2, nil
+}
+
+func (m inMemoryLedgerEntryReadTx) Done() error {
+	return nil
+}
@@ -0,0 +1,23 @@
+package preflight
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/support/log"
+)
+
+func TestSelfTest(t *testing.T) {
+	require.NoError(t, SelfTest(log.DefaultLogger, network.FutureNetworkPassphrase))
+}
+
+func TestCheckProtocolVersion(t *testing.T) {
+	maxSupported := MaxSupportedProtocolVersion()
+	require.GreaterOrEqual(t, maxSupported, uint32(MinSupportedProtocolVersion))
+	require.NoError(t, CheckProtocolVersion(MinSupportedProtocolVersion))
+	require.NoError(t, CheckProtocolVersion(maxSupported))
+	require.ErrorContains(t, CheckProtocolVersion(MinSupportedProtocolVersion-1), "outside of the range supported")
+	require.ErrorContains(t, CheckProtocolVersion(maxSupported+1), "outside of the range supported")
+}
@@ -0,0 +1,270 @@
+package subscriptions
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	maxMessageSize              = 64 * 1024
+	maxSubscriptionsPerConn     = 10
+	outgoingQueueCapacity       = 1024
+	writeTimeout                = 10 * time.Second
+	jsonRPCVersion              = "2.0"
+	transactionNotificationName = "transactionNotification"
+	ledgerNotificationName      = "ledgerNotification"
+)
+
+// request is a JSON RPC 2.0 request received through the WebSocket
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *jrpc2.Error    `json:"error,omitempty"`
+}
+
+// notification is pushed to the subscribers by the server
+type notification struct {
+	JSONRPC string             `json:"jsonrpc"`
+	Method  string             `json:"method"`
+	Params  notificationParams `json:"params"`
+}
+
+type notificationParams struct {
+	Subscription string `json:"subscription"`
+	Result       any    `json:"result"`
+}
+
+// UnsubscribeRequest cancels a subscription
+type UnsubscribeRequest struct {
+	Subscription string `json:"subscription"`
+}
+
+type subscription struct {
+	id string
+	// transactions is set for subscribeTransactions subscriptions
+	transactions *TransactionFilter
+	ledgers      bool
+}
+
+// connection is a WebSocket connection, which can hold several subscriptions
+type connection struct {
+	hub      *Hub
+	ws       *websocket.Conn
+	client   string
+	outgoing chan any
+	done     chan struct{}
+
+	closeOnce     sync.Once
+	lock          sync.Mutex
+	subscriptions map[string]subscription
+}
+
+func newConnection(hub *Hub, ws *websocket.Conn, client string) *connection {
+	ws.MaxPayloadBytes = maxMessageSize
+	return &connection{
+		hub:           hub,
+		ws:            ws,
+		client:        client,
+		outgoing:      make(chan any, outgoingQueueCapacity),
+		done:          make(chan struct{}),
+		subscriptions: map[string]subscription{},
+	}
+}
+
+func (c *connection) serve() {
+	// The connection was hijacked from the HTTP server, which may have set a read deadline
+	_ = c.ws.SetReadDeadline(time.Time{})
+	go c.writeLoop()
+	defer c.close()
+	for {
+		var message []byte
+		if err := websocket.Message.Receive(c.ws, &message); err != nil {
+			return
+		}
+		var req request
+		if err := json.Unmarshal(message, &req); err != nil {
+			c.send(response{
+				JSONRPC: jsonRPCVersion,
+				ID:      json.RawMessage("null"),
+				Error:   &jrpc2.Error{Code: jrpc2.ParseError, Message: err.Error()},
+			})
+			continue
+		}
+		result, err := c.handle(req)
+		if len(req.ID) == 0 {
+			// JSON RPC notifications don't get a response
+			continue
+		}
+		resp := response{JSONRPC: jsonRPCVersion, ID: req.ID, Error: err}
+		if err == nil {
+			encoded, marshalErr := json.Marshal(result)
+			if marshalErr != nil {
+				resp.Error = &jrpc2.Error{Code: jrpc2.InternalError, Message: marshalErr.Error()}
+			}
+			resp.Result = encoded
+		}
+		c.send(resp)
+	}
+}
+
+func (c *connection) handle(req request) (any, *jrpc2.Error) {
+	switch req.Method {
+	case "subscribeTransactions":
+		var filter TransactionFilter
+		if err := unmarshalParams(req.Params, &filter); err != nil {
+			return nil, err
+		}
+		if err := filter.Valid(); err != nil {
+			return nil, &jrpc2.Error{Code: jrpc2.InvalidParams, Message: err.Error()}
+		}
+		return c.subscribe(subscription{transactions: &filter})
+	case "subscribeLedgers":
+		return c.subscribe(subscription{ledgers: true})
+	case "unsubscribe":
+		var params UnsubscribeRequest
+		if err := unmarshalParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		_, ok := c.subscriptions[params.Subscription]
+		delete(c.subscriptions, params.Subscription)
+		return ok, nil
+	default:
+		return nil, &jrpc2.Error{Code: jrpc2.MethodNotFound, Message: fmt.Sprintf("method %q not found", req.Method)}
+	}
+}
+
+func unmarshalParams(params json.RawMessage, v any) *jrpc2.Error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &jrpc2.Error{Code: jrpc2.InvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+func (c *connection) subscribe(sub subscription) (string, *jrpc2.Error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(c.subscriptions) >= maxSubscriptionsPerConn {
+		return "", &jrpc2.Error{
+			Code:    jrpc2.InvalidRequest,
+			Message: fmt.Sprintf("too many subscriptions (the maximum is %d per connection)", maxSubscriptionsPerConn),
+		}
+	}
+	sub.id = c.hub.newSubscriptionID()
+	c.subscriptions[sub.id] = sub
+	return sub.id, nil
+}
+
+func (c *connection) hasSubscriptions() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.subscriptions) > 0
+}
+
+// subscribed tells whether the connection has transaction and ledger subscriptions
+func (c *connection) subscribed() (transactions bool, ledgers bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, sub := range c.subscriptions {
+		transactions = transactions || sub.transactions != nil
+		ledgers = ledgers || sub.ledgers
+	}
+	return transactions, ledgers
+}
+
+func (c *connection) notifyLedger(ledger LedgerNotification) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, sub := range c.subscriptions {
+		if !sub.ledgers {
+			continue
+		}
+		if !c.send(notification{
+			JSONRPC: jsonRPCVersion,
+			Method:  ledgerNotificationName,
+			Params:  notificationParams{Subscription: sub.id, Result: ledger},
+		}) {
+			return
+		}
+	}
+}
+
+func (c *connection) notifyTransactions(transactions []ledgerTransaction) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, sub := range c.subscriptions {
+		if sub.transactions == nil {
+			continue
+		}
+		for _, tx := range transactions {
+			if !sub.transactions.matches(tx) {
+				continue
+			}
+			if !c.send(notification{
+				JSONRPC: jsonRPCVersion,
+				Method:  transactionNotificationName,
+				Params:  notificationParams{Subscription: sub.id, Result: tx.TransactionNotification},
+			}) {
+				return
+			}
+		}
+	}
+}
+
+// send enqueues a message, closing the connection if the client isn't keeping up
+func (c *connection) send(message any) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+	select {
+	case c.outgoing <- message:
+		return true
+	default:
+		c.hub.logger.WithField("remote_addr", c.ws.Request().RemoteAddr).
+			Warn("closing subscription connection which isn't keeping up with the notifications")
+		c.hub.dropsCounter.Inc()
+		c.close()
+		return false
+	}
+}
+
+func (c *connection) writeLoop() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case message := <-c.outgoing:
+			_ = c.ws.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := websocket.JSON.Send(c.ws, message); err != nil {
+				c.close()
+				return
+			}
+		}
+	}
+}
+
+func (c *connection) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		_ = c.ws.Close()
+	})
+}
@@ -0,0 +1,224 @@
+package subscriptions
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/net/websocket"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/network"
+)
+
+// Path is where the WebSocket subscriptions endpoint is served
+const Path = "/ws"
+
+// Config configures the subscriptions hub.
+type Config struct {
+	NetworkPassphrase string
+	// MaxConnections is the maximum number of concurrent WebSocket connections
+	MaxConnections uint
+	// MaxConnectionsPerClient is the maximum number of concurrent WebSocket connections per client IP
+	MaxConnectionsPerClient uint
+	// AllowedOrigins are the origins (which can contain a "*" wildcard) allowed to open
+	// connections from a browser. All origins are allowed when empty.
+	AllowedOrigins []string
+	// TrustedProxies is the amount of trusted proxies in front of the server, used
+	// to identify clients by the X-Forwarded-For header (0 ignores the header)
+	TrustedProxies uint
+	Daemon         interfaces.Daemon
+	Logger         *log.Entry
+}
+
+// Hub serves the WebSocket subscriptions endpoint and pushes the ingested
+// ledgers (and transactions) to the subscribers.
+type Hub struct {
+	networkPassphrase       string
+	maxConnections          int
+	maxConnectionsPerClient int
+	allowedOrigins          []string
+	trustedProxies          uint
+	logger                  *log.Entry
+	connectionsGauge        prometheus.Gauge
+	dropsCounter            prometheus.Counter
+
+	lock               sync.Mutex
+	connections        map[*connection]struct{}
+	clientConnections  map[string]int
+	nextSubscriptionID uint64
+	closed             bool
+}
+
+// NewHub creates a subscriptions hub.
+func NewHub(cfg Config) *Hub {
+	connectionsGauge := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: cfg.Daemon.MetricsNamespace(), Subsystem: "subscriptions", Name: "connections",
+		Help: "number of open WebSocket subscription connections",
+	})
+	dropsCounter := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: cfg.Daemon.MetricsNamespace(), Subsystem: "subscriptions", Name: "slow_connections_dropped_total",
+		Help: "number of WebSocket subscription connections closed because they didn't keep up with the notifications",
+	})
+	cfg.Daemon.MetricsRegistry().MustRegister(connectionsGauge, dropsCounter)
+	return &Hub{
+		networkPassphrase:       cfg.NetworkPassphrase,
+		maxConnections:          int(cfg.MaxConnections),
+		maxConnectionsPerClient: int(cfg.MaxConnectionsPerClient),
+		allowedOrigins:          cfg.AllowedOrigins,
+		trustedProxies:          cfg.TrustedProxies,
+		logger:                  cfg.Logger,
+		connectionsGauge:        connectionsGauge,
+		dropsCounter:            dropsCounter,
+		connections:             map[*connection]struct{}{},
+		clientConnections:       map[string]int{},
+	}
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection, unless its origin
+// isn't allowed or the maximum amount of connections (in total or for the client)
+// is reached.
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Requests without an Origin don't come from a browser, so (as with CORS) they aren't restricted
+	if origin := r.Header.Get("Origin"); origin != "" && !h.originAllowed(origin) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	client := network.ClientIP(r, h.trustedProxies)
+	h.lock.Lock()
+	full := h.closed || len(h.connections) >= h.maxConnections
+	clientFull := h.clientFull(client)
+	h.lock.Unlock()
+	if full {
+		http.Error(w, "too many subscription connections", http.StatusServiceUnavailable)
+		return
+	}
+	if clientFull {
+		http.Error(w, "too many subscription connections from the client", http.StatusTooManyRequests)
+		return
+	}
+	// websocket.Server doesn't check the Origin (unlike websocket.Handler), it was checked above
+	websocket.Server{Handler: func(ws *websocket.Conn) {
+		h.serveConnection(ws, client)
+	}}.ServeHTTP(w, r)
+}
+
+// originAllowed tells whether the origin matches the allowed origins, following
+// the same rules as the CORS policy of the HTTP endpoints.
+func (h *Hub) originAllowed(origin string) bool {
+	if len(h.allowedOrigins) == 0 {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	for _, allowed := range h.allowedOrigins {
+		allowed = strings.ToLower(allowed)
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+		if prefix, suffix, ok := strings.Cut(allowed, "*"); ok &&
+			len(origin) >= len(prefix)+len(suffix) &&
+			strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *Hub) serveConnection(ws *websocket.Conn, client string) {
+	conn := newConnection(h, ws, client)
+	if !h.register(conn) {
+		_ = ws.Close()
+		return
+	}
+	defer h.unregister(conn)
+	conn.serve()
+}
+
+// clientFull tells whether the client reached its maximum amount of connections, it must
+// be called with the lock held.
+func (h *Hub) clientFull(client string) bool {
+	return h.maxConnectionsPerClient > 0 && h.clientConnections[client] >= h.maxConnectionsPerClient
+}
+
+func (h *Hub) register(conn *connection) bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.closed || len(h.connections) >= h.maxConnections || h.clientFull(conn.client) {
+		return false
+	}
+	h.connections[conn] = struct{}{}
+	h.clientConnections[conn.client]++
+	h.connectionsGauge.Set(float64(len(h.connections)))
+	return true
+}
+
+func (h *Hub) unregister(conn *connection) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.connections, conn)
+	if h.clientConnections[conn.client]--; h.clientConnections[conn.client] <= 0 {
+		delete(h.clientConnections, conn.client)
+	}
+	h.connectionsGauge.Set(float64(len(h.connections)))
+}
+
+func (h *Hub) newSubscriptionID() string {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.nextSubscriptionID++
+	return strconv.FormatUint(h.nextSubscriptionID, 10)
+}
+
+func (h *Hub) snapshotConnections() []*connection {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	connections := make([]*connection, 0, len(h.connections))
+	for conn := range h.connections {
+		connections = append(connections, conn)
+	}
+	return connections
+}
+
+// OnLedgerIngested pushes an ingested ledger (and its transactions) to the subscribers.
+func (h *Hub) OnLedgerIngested(lcm xdr.LedgerCloseMeta) {
+	connections := h.snapshotConnections()
+	var transactionsSubscribed, ledgersSubscribed bool
+	for _, conn := range connections {
+		transactions, ledgers := conn.subscribed()
+		transactionsSubscribed = transactionsSubscribed || transactions
+		ledgersSubscribed = ledgersSubscribed || ledgers
+	}
+	if ledgersSubscribed {
+		ledger := newLedgerNotification(lcm)
+		for _, conn := range connections {
+			conn.notifyLedger(ledger)
+		}
+	}
+	if transactionsSubscribed {
+		transactions, err := ledgerTransactions(h.networkPassphrase, lcm)
+		if err != nil {
+			h.logger.WithError(err).
+				WithField("ledger", lcm.LedgerSequence()).
+				Warn("could not build transaction notifications")
+		} else {
+			for _, conn := range connections {
+				conn.notifyTransactions(transactions)
+			}
+		}
+	}
+}
+
+// Close closes all the connections (which, being hijacked, aren't closed when shutting down the HTTP server).
+func (h *Hub) Close() {
+	h.lock.Lock()
+	h.closed = true
+	h.lock.Unlock()
+	for _, conn := range h.snapshotConnections() {
+		conn.close()
+	}
+}
@@ -0,0 +1,226 @@
+package subscriptions
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/log"
+
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon/interfaces"
+)
+
+func newTestHub(t *testing.T, maxConnections uint) (*Hub, string) {
+	return newTestHubWithConfig(t, Config{MaxConnections: maxConnections})
+}
+
+func newTestHubWithConfig(t *testing.T, cfg Config) (*Hub, string) {
+	cfg.NetworkPassphrase = testPassphrase
+	cfg.Daemon = interfaces.MakeNoOpDeamon()
+	cfg.Logger = log.DefaultLogger
+	hub := NewHub(cfg)
+	server := httptest.NewServer(hub)
+	t.Cleanup(func() {
+		hub.Close()
+		server.Close()
+	})
+	return hub, "ws" + strings.TrimPrefix(server.URL, "http") + Path
+}
+
+func dial(t *testing.T, url string) *websocket.Conn {
+	ws, err := websocket.Dial(url, "", "http://localhost/")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ws.Close() })
+	return ws
+}
+
+func call(t *testing.T, ws *websocket.Conn, message string) map[string]any {
+	require.NoError(t, websocket.Message.Send(ws, message))
+	return receive(t, ws)
+}
+
+func receive(t *testing.T, ws *websocket.Conn) map[string]any {
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var message map[string]any
+	require.NoError(t, websocket.JSON.Receive(ws, &message))
+	return message
+}
+
+// waitForSubscriptions waits until the hub has registered a subscription for all its connections
+func waitForSubscriptions(t *testing.T, hub *Hub) {
+	require.Eventually(t, func() bool {
+		for _, conn := range hub.snapshotConnections() {
+			if !conn.hasSubscriptions() {
+				return false
+			}
+		}
+		return true
+	}, time.Second, time.Millisecond)
+}
+
+func TestSubscribeTransactions(t *testing.T) {
+	hub, url := newTestHub(t, 10)
+	source := keypair.MustRandom().Address()
+	all := dial(t, url)
+	filtered := dial(t, url)
+
+	response := call(t, all, `{"jsonrpc": "2.0", "id": 1, "method": "subscribeTransactions"}`)
+	assert.Equal(t, float64(1), response["id"])
+	allID := response["result"]
+	require.NotEmpty(t, allID)
+	response = call(t, filtered, `{"jsonrpc": "2.0", "id": 2, "method": "subscribeTransactions", "params": {"accounts": ["`+source+`"]}}`)
+	filteredID := response["result"]
+	require.NotEqual(t, allID, filteredID)
+	waitForSubscriptions(t, hub)
+
+	hub.OnLedgerIngested(testLedger(t, 7, keypair.MustRandom().Address()))
+	hub.OnLedgerIngested(testLedger(t, 8, source))
+
+	for _, expectedLedger := range []float64{7, 8} {
+		message := receive(t, all)
+		assert.Equal(t, "transactionNotification", message["method"])
+		params := message["params"].(map[string]any)
+		assert.Equal(t, allID, params["subscription"])
+		assert.Equal(t, expectedLedger, params["result"].(map[string]any)["ledger"])
+	}
+	// the filtered subscription only gets the transaction of its account
+	message := receive(t, filtered)
+	params := message["params"].(map[string]any)
+	assert.Equal(t, filteredID, params["subscription"])
+	notification := params["result"].(map[string]any)
+	assert.Equal(t, float64(8), notification["ledger"])
+	assert.Equal(t, "SUCCESS", notification["status"])
+
+	response = call(t, filtered, `{"jsonrpc": "2.0", "id": 3, "method": "unsubscribe", "params": {"subscription": "`+filteredID.(string)+`"}}`)
+	assert.Equal(t, true, response["result"])
+	response = call(t, filtered, `{"jsonrpc": "2.0", "id": 4, "method": "unsubscribe", "params": {"subscription": "`+filteredID.(string)+`"}}`)
+	assert.Equal(t, false, response["result"])
+}
+
+func TestSubscribeLedgers(t *testing.T) {
+	hub, url := newTestHub(t, 10)
+	ws := dial(t, url)
+	response := call(t, ws, `{"jsonrpc": "2.0", "id": 1, "method": "subscribeLedgers"}`)
+	subscriptionID := response["result"]
+	require.NotEmpty(t, subscriptionID)
+	waitForSubscriptions(t, hub)
+
+	hub.OnLedgerIngested(testLedger(t, 7, keypair.MustRandom().Address()))
+	message := receive(t, ws)
+	assert.Equal(t, "ledgerNotification", message["method"])
+	params := message["params"].(map[string]any)
+	assert.Equal(t, subscriptionID, params["subscription"])
+	ledger := params["result"].(map[string]any)
+	assert.Equal(t, float64(7), ledger["sequence"])
+	assert.Equal(t, "35", ledger["closeTime"])
+	assert.Equal(t, float64(1), ledger["transactionCount"])
+}
+
+func TestSubscriptionErrors(t *testing.T) {
+	_, url := newTestHub(t, 1)
+	ws := dial(t, url)
+
+	for _, testCase := range []struct {
+		message      string
+		expectedCode float64
+	}{
+		{`not json`, -32700},
+		{`{"jsonrpc": "2.0", "id": 1, "method": "subscribeEverything"}`, -32601},
+		{`{"jsonrpc": "2.0", "id": 1, "method": "subscribeTransactions", "params": {"accounts": ["invalid"]}}`, -32602},
+		{`{"jsonrpc": "2.0", "id": 1, "method": "subscribeTransactions", "params": []}`, -32602},
+	} {
+		response := call(t, ws, testCase.message)
+		require.Contains(t, response, "error", testCase.message)
+		assert.Equal(t, testCase.expectedCode, response["error"].(map[string]any)["code"], testCase.message)
+		assert.NotContains(t, response, "result")
+	}
+
+	for range maxSubscriptionsPerConn {
+		response := call(t, ws, `{"jsonrpc": "2.0", "id": 1, "method": "subscribeTransactions"}`)
+		require.Contains(t, response, "result")
+	}
+	response := call(t, ws, `{"jsonrpc": "2.0", "id": 1, "method": "subscribeTransactions"}`)
+	assert.Contains(t, response["error"].(map[string]any)["message"], "too many subscriptions")
+
+	// the maximum amount of connections is reached
+	httpResponse, err := http.Get("http" + strings.TrimPrefix(url, "ws"))
+	require.NoError(t, err)
+	defer httpResponse.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, httpResponse.StatusCode)
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	hub, url := newTestHub(t, 10)
+	ws := dial(t, url)
+	call(t, ws, `{"jsonrpc": "2.0", "id": 1, "method": "subscribeTransactions"}`)
+	waitForSubscriptions(t, hub)
+
+	conn := hub.snapshotConnections()[0]
+	// fill the queue without writing it to the client
+	for len(conn.outgoing) < cap(conn.outgoing) {
+		conn.outgoing <- json.RawMessage(`{}`)
+	}
+	hub.OnLedgerIngested(testLedger(t, 7, keypair.MustRandom().Address()))
+	select {
+	case <-conn.done:
+	case <-time.After(time.Second):
+		t.Fatal("the slow connection wasn't closed")
+	}
+	require.Eventually(t, func() bool { return len(hub.snapshotConnections()) == 0 }, time.Second, time.Millisecond)
+}
+
+func TestAllowedOrigins(t *testing.T) {
+	_, url := newTestHubWithConfig(t, Config{
+		MaxConnections: 10,
+		AllowedOrigins: []string{"https://app.example.com", "https://*.example.org"},
+	})
+	for _, origin := range []string{"https://app.example.com", "https://APP.example.com", "https://wallet.example.org"} {
+		ws, err := websocket.Dial(url, "", origin)
+		require.NoError(t, err, origin)
+		require.NoError(t, ws.Close())
+	}
+	for _, origin := range []string{"https://evil.com", "https://example.org", "http://app.example.com"} {
+		_, err := websocket.Dial(url, "", origin)
+		require.Error(t, err, origin)
+	}
+
+	// only the requests with an Origin (i.e. from a browser) are restricted
+	request, err := http.NewRequest(http.MethodGet, "http"+strings.TrimPrefix(url, "ws"), nil)
+	require.NoError(t, err)
+	request.Header.Set("Origin", "https://evil.com")
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	request.Header.Del("Origin")
+	response, err = http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	// it isn't a WebSocket handshake, but it gets past the origin check
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
+
+func TestMaxConnectionsPerClient(t *testing.T) {
+	hub, url := newTestHubWithConfig(t, Config{MaxConnections: 10, MaxConnectionsPerClient: 2})
+	first := dial(t, url)
+	dial(t, url)
+	require.Eventually(t, func() bool { return len(hub.snapshotConnections()) == 2 }, time.Second, time.Millisecond)
+
+	httpResponse, err := http.Get("http" + strings.TrimPrefix(url, "ws"))
+	require.NoError(t, err)
+	defer httpResponse.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, httpResponse.StatusCode)
+
+	// closing a connection frees a slot for the client
+	require.NoError(t, first.Close())
+	require.Eventually(t, func() bool { return len(hub.snapshotConnections()) == 1 }, time.Second, time.Millisecond)
+	dial(t, url)
+}
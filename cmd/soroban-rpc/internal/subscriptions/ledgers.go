@@ -0,0 +1,40 @@
+package subscriptions
+
+import (
+	"github.com/stellar/go/xdr"
+)
+
+// LedgerNotification is pushed to subscribeLedgers subscribers for every ingested ledger.
+type LedgerNotification struct {
+	// Sequence number of the ledger.
+	Sequence uint32 `json:"sequence"`
+	// Hash of the ledger as a hex-encoded string
+	Hash string `json:"hash"`
+	// LedgerCloseTime is the unix timestamp of when the ledger was closed.
+	LedgerCloseTime int64 `json:"closeTime,string"`
+	// TransactionCount is the number of transactions applied in the ledger
+	TransactionCount uint32 `json:"transactionCount"`
+	// OperationCount is the number of operations of the transactions applied in the ledger
+	OperationCount uint32 `json:"operationCount"`
+	// EventCount is the number of contract (not diagnostic) events emitted in the ledger
+	EventCount uint32 `json:"eventCount"`
+}
+
+func newLedgerNotification(lcm xdr.LedgerCloseMeta) LedgerNotification {
+	notification := LedgerNotification{
+		Sequence:         lcm.LedgerSequence(),
+		Hash:             lcm.LedgerHash().HexString(),
+		LedgerCloseTime:  lcm.LedgerCloseTime(),
+		TransactionCount: uint32(lcm.CountTransactions()),
+	}
+	for _, envelope := range lcm.TransactionEnvelopes() {
+		notification.OperationCount += uint32(len(envelope.Operations()))
+	}
+	for i := 0; i < lcm.CountTransactions(); i++ {
+		meta := lcm.TxApplyProcessing(i)
+		if meta.V == 3 && meta.V3.SorobanMeta != nil {
+			notification.EventCount += uint32(len(meta.V3.SorobanMeta.Events))
+		}
+	}
+	return notification
+}
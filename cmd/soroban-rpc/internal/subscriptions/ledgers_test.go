@@ -0,0 +1,27 @@
+package subscriptions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+)
+
+func TestNewLedgerNotification(t *testing.T) {
+	lcm := testLedger(t, 7, keypair.MustRandom().Address())
+	lcm.V1.LedgerHeader.Hash = xdr.Hash{0xab}
+	lcm.V1.TxProcessing[0].TxApplyProcessing.V3.SorobanMeta = &xdr.SorobanTransactionMeta{
+		Events: []xdr.ContractEvent{{Type: xdr.ContractEventTypeContract}, {Type: xdr.ContractEventTypeSystem}},
+	}
+
+	assert.Equal(t, LedgerNotification{
+		Sequence:         7,
+		Hash:             xdr.Hash{0xab}.HexString(),
+		LedgerCloseTime:  35,
+		TransactionCount: 1,
+		OperationCount:   1,
+		EventCount:       2,
+	}, newLedgerNotification(lcm))
+}
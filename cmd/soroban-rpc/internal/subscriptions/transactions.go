@@ -0,0 +1,166 @@
+package subscriptions
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+const maxFilterEntries = 100
+
+// TransactionFilter selects the transactions pushed by subscribeTransactions.
+// Empty filters select all the transactions.
+type TransactionFilter struct {
+	// Accounts matches the transactions with any of these (G...) accounts as the source
+	// of the transaction, of one of its operations or of the fee-bump.
+	Accounts []string `json:"accounts,omitempty"`
+	// Contracts matches the transactions invoking or emitting events from any of these (C...) contracts.
+	Contracts []string `json:"contracts,omitempty"`
+}
+
+// Valid checks the addresses of the filter
+func (f TransactionFilter) Valid() error {
+	if len(f.Accounts)+len(f.Contracts) > maxFilterEntries {
+		return fmt.Errorf("filter exceeds maximum of %d accounts and contracts", maxFilterEntries)
+	}
+	for i, account := range f.Accounts {
+		if _, err := strkey.Decode(strkey.VersionByteAccountID, account); err != nil {
+			return fmt.Errorf("account %d invalid: %w", i+1, err)
+		}
+	}
+	for i, contract := range f.Contracts {
+		if _, err := strkey.Decode(strkey.VersionByteContract, contract); err != nil {
+			return fmt.Errorf("contract %d invalid: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (f TransactionFilter) matches(tx ledgerTransaction) bool {
+	if len(f.Accounts) == 0 && len(f.Contracts) == 0 {
+		return true
+	}
+	for _, account := range f.Accounts {
+		if _, ok := tx.accounts[account]; ok {
+			return true
+		}
+	}
+	for _, contract := range f.Contracts {
+		if _, ok := tx.contracts[contract]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// TransactionNotification is pushed to subscribeTransactions subscribers for every applied transaction.
+type TransactionNotification struct {
+	// Hash is the hex-encoded hash of the transaction
+	Hash string `json:"hash"`
+	// Status is one of: SUCCESS or FAILED
+	Status string `json:"status"`
+	// ApplicationOrder is the index of the transaction among all the transactions
+	// for that ledger.
+	ApplicationOrder int32 `json:"applicationOrder"`
+	// FeeBump indicates whether the transaction is a feebump transaction
+	FeeBump bool `json:"feeBump"`
+	// EnvelopeXdr is the TransactionEnvelope XDR value.
+	EnvelopeXdr string `json:"envelopeXdr"`
+	// ResultXdr is the TransactionResult XDR value.
+	ResultXdr string `json:"resultXdr"`
+	// Ledger is the sequence of the ledger which included the transaction.
+	Ledger uint32 `json:"ledger"`
+	// LedgerCloseTime is the unix timestamp of when the transaction was included in the ledger.
+	LedgerCloseTime int64 `json:"createdAt,string"`
+}
+
+// ledgerTransaction is a notification along with the addresses used for filtering
+type ledgerTransaction struct {
+	TransactionNotification
+	accounts  map[string]struct{}
+	contracts map[string]struct{}
+}
+
+func ledgerTransactions(networkPassphrase string, lcm xdr.LedgerCloseMeta) ([]ledgerTransaction, error) {
+	reader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(networkPassphrase, lcm)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var transactions []ledgerTransaction
+	for {
+		tx, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		transaction, err := newLedgerTransaction(lcm, tx)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, transaction)
+	}
+	return transactions, nil
+}
+
+func newLedgerTransaction(lcm xdr.LedgerCloseMeta, tx ingest.LedgerTransaction) (ledgerTransaction, error) {
+	result := ledgerTransaction{
+		TransactionNotification: TransactionNotification{
+			Hash:             tx.Result.TransactionHash.HexString(),
+			Status:           "FAILED",
+			ApplicationOrder: int32(tx.Index),
+			FeeBump:          tx.Envelope.IsFeeBump(),
+			Ledger:           lcm.LedgerSequence(),
+			LedgerCloseTime:  lcm.LedgerCloseTime(),
+		},
+		accounts:  map[string]struct{}{},
+		contracts: map[string]struct{}{},
+	}
+	if tx.Result.Successful() {
+		result.Status = "SUCCESS"
+	}
+	var err error
+	if result.EnvelopeXdr, err = xdr.MarshalBase64(tx.Envelope); err != nil {
+		return result, err
+	}
+	if result.ResultXdr, err = xdr.MarshalBase64(tx.Result.Result); err != nil {
+		return result, err
+	}
+
+	sourceAccount := tx.Envelope.SourceAccount()
+	result.accounts[sourceAccount.ToAccountId().Address()] = struct{}{}
+	if result.FeeBump {
+		feeAccount := tx.Envelope.FeeBumpAccount()
+		result.accounts[feeAccount.ToAccountId().Address()] = struct{}{}
+	}
+	for _, op := range tx.Envelope.Operations() {
+		if op.SourceAccount != nil {
+			result.accounts[op.SourceAccount.ToAccountId().Address()] = struct{}{}
+		}
+		if invoke, ok := op.Body.GetInvokeHostFunctionOp(); ok && invoke.HostFunction.Type == xdr.HostFunctionTypeHostFunctionTypeInvokeContract {
+			address, err := invoke.HostFunction.MustInvokeContract().ContractAddress.String()
+			if err != nil {
+				return result, err
+			}
+			result.contracts[address] = struct{}{}
+		}
+	}
+	if tx.UnsafeMeta.V == 3 && tx.UnsafeMeta.V3.SorobanMeta != nil {
+		for _, event := range tx.UnsafeMeta.V3.SorobanMeta.Events {
+			if event.ContractId == nil {
+				continue
+			}
+			address, err := strkey.Encode(strkey.VersionByteContract, event.ContractId[:])
+			if err != nil {
+				return result, err
+			}
+			result.contracts[address] = struct{}{}
+		}
+	}
+	return result, nil
+}
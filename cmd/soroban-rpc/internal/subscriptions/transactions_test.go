@@ -0,0 +1,132 @@
+package subscriptions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+const testPassphrase = "passphrase"
+
+var testContractID = xdr.Hash{0xca, 0xfe}
+
+func testContractAddress(t *testing.T) string {
+	address, err := strkey.Encode(strkey.VersionByteContract, testContractID[:])
+	require.NoError(t, err)
+	return address
+}
+
+// testLedger builds a ledger with a successful contract invocation by source
+func testLedger(t *testing.T, sequence uint32, source string) xdr.LedgerCloseMeta {
+	contractAddress := xdr.ScAddress{
+		Type:       xdr.ScAddressTypeScAddressTypeContract,
+		ContractId: &testContractID,
+	}
+	envelope, err := xdr.NewTransactionEnvelope(xdr.EnvelopeTypeEnvelopeTypeTx, xdr.TransactionV1Envelope{
+		Tx: xdr.Transaction{
+			Fee:           100,
+			SeqNum:        xdr.SequenceNumber(sequence),
+			SourceAccount: xdr.MustMuxedAddress(source),
+			Operations: []xdr.Operation{{
+				Body: xdr.OperationBody{
+					Type: xdr.OperationTypeInvokeHostFunction,
+					InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+						HostFunction: xdr.HostFunction{
+							Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+							InvokeContract: &xdr.InvokeContractArgs{
+								ContractAddress: contractAddress,
+								FunctionName:    "hello",
+							},
+						},
+					},
+				},
+			}},
+		},
+	})
+	require.NoError(t, err)
+	hash, err := network.HashTransactionInEnvelope(envelope, testPassphrase)
+	require.NoError(t, err)
+
+	opResults := []xdr.OperationResult{}
+	components := []xdr.TxSetComponent{{
+		Type: xdr.TxSetComponentTypeTxsetCompTxsMaybeDiscountedFee,
+		TxsMaybeDiscountedFee: &xdr.TxSetComponentTxsMaybeDiscountedFee{
+			Txs: []xdr.TransactionEnvelope{envelope},
+		},
+	}}
+	return xdr.LedgerCloseMeta{
+		V: 1,
+		V1: &xdr.LedgerCloseMetaV1{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+				Header: xdr.LedgerHeader{
+					ScpValue:  xdr.StellarValue{CloseTime: xdr.TimePoint(sequence * 5)},
+					LedgerSeq: xdr.Uint32(sequence),
+				},
+			},
+			TxProcessing: []xdr.TransactionResultMeta{{
+				TxApplyProcessing: xdr.TransactionMeta{
+					V:          3,
+					Operations: &[]xdr.OperationMeta{},
+					V3:         &xdr.TransactionMetaV3{},
+				},
+				Result: xdr.TransactionResultPair{
+					TransactionHash: hash,
+					Result: xdr.TransactionResult{
+						FeeCharged: 100,
+						Result: xdr.TransactionResultResult{
+							Code:    xdr.TransactionResultCodeTxSuccess,
+							Results: &opResults,
+						},
+					},
+				},
+			}},
+			TxSet: xdr.GeneralizedTransactionSet{
+				V: 1,
+				V1TxSet: &xdr.TransactionSetV1{
+					Phases: []xdr.TransactionPhase{{V: 0, V0Components: &components}},
+				},
+			},
+		},
+	}
+}
+
+func TestLedgerTransactions(t *testing.T) {
+	source := keypair.MustRandom().Address()
+	lcm := testLedger(t, 7, source)
+
+	transactions, err := ledgerTransactions(testPassphrase, lcm)
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	tx := transactions[0]
+	assert.Equal(t, lcm.TransactionHash(0).HexString(), tx.Hash)
+	assert.Equal(t, "SUCCESS", tx.Status)
+	assert.Equal(t, int32(1), tx.ApplicationOrder)
+	assert.False(t, tx.FeeBump)
+	assert.Equal(t, uint32(7), tx.Ledger)
+	assert.Equal(t, int64(35), tx.LedgerCloseTime)
+	expectedEnvelope, err := xdr.MarshalBase64(lcm.TransactionEnvelopes()[0])
+	require.NoError(t, err)
+	assert.Equal(t, expectedEnvelope, tx.EnvelopeXdr)
+
+	assert.True(t, TransactionFilter{}.matches(tx))
+	assert.True(t, TransactionFilter{Accounts: []string{source}}.matches(tx))
+	assert.True(t, TransactionFilter{Contracts: []string{testContractAddress(t)}}.matches(tx))
+	assert.False(t, TransactionFilter{Accounts: []string{keypair.MustRandom().Address()}}.matches(tx))
+}
+
+func TestTransactionFilterValid(t *testing.T) {
+	assert.NoError(t, TransactionFilter{}.Valid())
+	assert.NoError(t, TransactionFilter{
+		Accounts:  []string{keypair.MustRandom().Address()},
+		Contracts: []string{testContractAddress(t)},
+	}.Valid())
+	assert.ErrorContains(t, TransactionFilter{Accounts: []string{testContractAddress(t)}}.Valid(), "account 1 invalid")
+	assert.ErrorContains(t, TransactionFilter{Contracts: []string{"C"}}.Valid(), "contract 1 invalid")
+	assert.ErrorContains(t, TransactionFilter{Contracts: make([]string, maxFilterEntries+1)}.Valid(), "filter exceeds maximum")
+}
@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "github.com/stellar/soroban-rpc"
+	serviceName         = "soroban-rpc"
+)
+
+// Config configures the export of OpenTelemetry spans
+type Config struct {
+	// OTLPEndpointURL is the URL of the OTLP/HTTP collector the spans are
+	// exported to (e.g. http://localhost:4318). Tracing is disabled when empty.
+	OTLPEndpointURL string
+	// OTLPHeaders are sent along with every export request (e.g. for authentication)
+	OTLPHeaders map[string]string
+	// SampleRatio is the fraction of traces which are sampled
+	SampleRatio float64
+	// Version is the soroban-rpc version reported in the spans
+	Version string
+}
+
+// Tracer returns the tracer used to instrument soroban-rpc. The spans are
+// dropped unless Setup was invoked.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Setup installs a global tracer provider exporting the spans to the
+// configured OTLP endpoint. The returned function flushes the pending spans
+// and stops the exporter.
+func Setup(cfg Config) (func(context.Context) error, error) {
+	if cfg.OTLPEndpointURL == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpointURL(cfg.OTLPEndpointURL)}
+	if len(cfg.OTLPHeaders) > 0 {
+		options = append(options, otlptracehttp.WithHeaders(cfg.OTLPHeaders))
+	}
+	exporter, err := otlptracehttp.New(context.Background(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("could not create OTLP exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(cfg.Version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("could not create tracing resource: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// NetworkAttribute identifies the network of the spans, since a single
+// process can serve several networks (sharing the tracer provider)
+func NetworkAttribute(networkPassphrase string) attribute.KeyValue {
+	return attribute.String("stellar.network_passphrase", networkPassphrase)
+}
+
+// End records the error (if any) in the span and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// ParseHeaders parses a list of `key=value` OTLP header entries
+func ParseHeaders(entries []string) (map[string]string, error) {
+	headers := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		key, value, found := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid OTLP header %q, expected <key>=<value>", entry)
+		}
+		headers[key] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetup(t *testing.T) {
+	shutdown, err := Setup(Config{})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+
+	shutdown, err = Setup(Config{
+		OTLPEndpointURL: "http://localhost:4318",
+		OTLPHeaders:     map[string]string{"authorization": "secret"},
+		SampleRatio:     0.5,
+		Version:         "test",
+	})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+}
+
+func TestEnd(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := provider.Tracer("test")
+
+	_, span := tracer.Start(context.Background(), "ok")
+	End(span, nil)
+	_, span = tracer.Start(context.Background(), "failed")
+	End(span, errors.New("boom"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "boom", spans[1].Status().Description)
+	require.Len(t, spans[1].Events(), 1)
+}
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := ParseHeaders([]string{"authorization=Bearer abc", " x-tenant = foo "})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"authorization": "Bearer abc", "x-tenant": "foo"}, headers)
+
+	_, err = ParseHeaders([]string{"authorization"})
+	require.EqualError(t, err, `invalid OTLP header "authorization", expected <key>=<value>`)
+}
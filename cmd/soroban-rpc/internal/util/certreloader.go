@@ -0,0 +1,102 @@
+package util
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/support/log"
+)
+
+// certificateCheckInterval is how often the certificate files are checked for changes
+const certificateCheckInterval = 10 * time.Second
+
+// CertificateReloader serves a TLS certificate loaded from disk, reloading it
+// whenever the certificate or key files change (e.g. after a rotation).
+type CertificateReloader struct {
+	certFile  string
+	keyFile   string
+	logger    *log.Entry
+	lock      sync.Mutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	lastCheck time.Time
+}
+
+// NewCertificateReloader loads the certificate, failing if it is invalid.
+func NewCertificateReloader(certFile string, keyFile string, logger *log.Entry) (*CertificateReloader, error) {
+	reloader := &CertificateReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		logger:   logger,
+	}
+	modTime, err := reloader.latestModTime()
+	if err != nil {
+		return nil, err
+	}
+	if err := reloader.load(time.Now(), modTime); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+// TLSConfig returns a server TLS configuration serving the reloaded certificate.
+func (r *CertificateReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetCertificate: r.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+}
+
+// GetCertificate implements tls.Config.GetCertificate
+func (r *CertificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	now := time.Now()
+	if now.Sub(r.lastCheck) < certificateCheckInterval {
+		return r.cert, nil
+	}
+	r.lastCheck = now
+	modTime, err := r.latestModTime()
+	if err != nil {
+		r.logger.WithError(err).Warn("could not check TLS certificate files, serving the previous certificate")
+		return r.cert, nil
+	}
+	if !modTime.After(r.modTime) {
+		return r.cert, nil
+	}
+	if err := r.load(now, modTime); err != nil {
+		// keep serving the previous certificate, files may be mid-rotation
+		r.logger.WithError(err).Warn("could not reload TLS certificate, serving the previous certificate")
+		return r.cert, nil
+	}
+	r.logger.Info("reloaded TLS certificate")
+	return r.cert, nil
+}
+
+func (r *CertificateReloader) load(now time.Time, modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("could not load TLS certificate: %w", err)
+	}
+	r.cert = &cert
+	r.modTime = modTime
+	r.lastCheck = now
+	return nil
+}
+
+func (r *CertificateReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, file := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
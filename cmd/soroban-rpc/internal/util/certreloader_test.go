@@ -0,0 +1,75 @@
+package util
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+)
+
+func writeSelfSignedCertificate(t *testing.T, certFile string, keyFile string, name string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+}
+
+func commonName(t *testing.T, reloader *CertificateReloader) string {
+	cert, err := reloader.GetCertificate(nil)
+	require.NoError(t, err)
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	return parsed.Subject.CommonName
+}
+
+func TestCertificateReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	_, err := NewCertificateReloader(certFile, keyFile, log.DefaultLogger)
+	require.Error(t, err)
+
+	writeSelfSignedCertificate(t, certFile, keyFile, "first")
+	reloader, err := NewCertificateReloader(certFile, keyFile, log.DefaultLogger)
+	require.NoError(t, err)
+	assert.Equal(t, "first", commonName(t, reloader))
+
+	// rotate the certificate
+	writeSelfSignedCertificate(t, certFile, keyFile, "second")
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, future, future))
+	// changes are only checked periodically
+	assert.Equal(t, "first", commonName(t, reloader))
+	reloader.lastCheck = time.Time{}
+	assert.Equal(t, "second", commonName(t, reloader))
+
+	// invalid certificates are ignored
+	require.NoError(t, os.WriteFile(certFile, []byte("garbage"), 0o600))
+	future = future.Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, future, future))
+	reloader.lastCheck = time.Time{}
+	assert.Equal(t, "second", commonName(t, reloader))
+}
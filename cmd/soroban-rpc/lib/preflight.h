@@ -37,6 +37,13 @@ typedef struct resource_config_t {
     uint64_t instruction_leeway; // Allow this many extra instructions when budgeting
 } resource_config_t;
 
+// How the authorization of the invoked host function is simulated
+typedef enum auth_mode_t {
+    AUTH_MODE_DEFAULT = 0, // record if the operation has no authorization entries, enforce them otherwise
+    AUTH_MODE_ENFORCE = 1, // enforce the authorization entries of the operation
+    AUTH_MODE_RECORD = 2, // record the required authorizations (the operation must have no authorization entries)
+} auth_mode_t;
+
 typedef struct preflight_result_t {
     char             *error; // Error string in case of error, otherwise null
     xdr_vector_t      auth; // array of SorobanAuthorizationEntries
@@ -56,6 +63,7 @@ preflight_result_t *preflight_invoke_hf_op(uintptr_t handle, // Go Handle to for
                                            const xdr_t source_account, // AccountId XDR
                                            const ledger_info_t ledger_info,
                                            const resource_config_t resource_config,
+                                           const auth_mode_t auth_mode,
                                            bool enable_debug);
 
 preflight_result_t *preflight_footprint_ttl_op(uintptr_t   handle, // Go Handle to forward to SnapshotSourceGet
@@ -64,6 +72,9 @@ preflight_result_t *preflight_footprint_ttl_op(uintptr_t   handle, // Go Handle
                                                const ledger_info_t ledger_info);
 
 
+// Latest ledger protocol version supported by the library
+uint32_t preflight_max_supported_protocol_version();
+
 // LedgerKey XDR to LedgerEntry XDR
 extern xdr_t SnapshotSourceGet(uintptr_t handle, xdr_t ledger_key);
 
@@ -208,6 +208,11 @@ impl CPreflightResult {
     }
 }
 
+// Values of auth_mode_t
+const AUTH_MODE_DEFAULT: u32 = 0;
+const AUTH_MODE_ENFORCE: u32 = 1;
+const AUTH_MODE_RECORD: u32 = 2;
+
 #[no_mangle]
 pub extern "C" fn preflight_invoke_hf_op(
     handle: libc::uintptr_t, // Go Handle to forward to SnapshotSourceGet and SnapshotSourceHas
@@ -215,6 +220,7 @@ pub extern "C" fn preflight_invoke_hf_op(
     source_account: CXDR,    // AccountId XDR in base64
     ledger_info: CLedgerInfo,
     resource_config: CResourceConfig,
+    auth_mode: u32, // auth_mode_t
     enable_debug: bool,
 ) -> *mut CPreflightResult {
     catch_preflight_panic(Box::new(move || {
@@ -224,6 +230,7 @@ pub extern "C" fn preflight_invoke_hf_op(
             source_account,
             ledger_info,
             resource_config,
+            auth_mode,
             enable_debug,
         )
     }))
@@ -235,6 +242,7 @@ fn preflight_invoke_hf_op_or_maybe_panic(
     source_account: CXDR, // AccountId XDR in base64
     c_ledger_info: CLedgerInfo,
     resource_config: CResourceConfig,
+    auth_mode: u32,
     enable_debug: bool,
 ) -> Result<CPreflightResult> {
     let invoke_hf_op =
@@ -259,11 +267,18 @@ fn preflight_invoke_hf_op_or_maybe_panic(
         .instructions
         .additive_factor
         .max(instruction_leeway);
-    // Here we assume that no input auth means that the user requests the recording auth.
-    let auth_entries = if invoke_hf_op.auth.is_empty() {
-        None
-    } else {
-        Some(invoke_hf_op.auth.to_vec())
+    // `None` auth entries make the simulation record the required authorizations
+    let auth_entries = match auth_mode {
+        // Here we assume that no input auth means that the user requests the recording auth.
+        AUTH_MODE_DEFAULT if invoke_hf_op.auth.is_empty() => None,
+        AUTH_MODE_DEFAULT | AUTH_MODE_ENFORCE => Some(invoke_hf_op.auth.to_vec()),
+        AUTH_MODE_RECORD => {
+            if !invoke_hf_op.auth.is_empty() {
+                bail!("authorization entries must be empty in the record auth mode");
+            }
+            None
+        }
+        _ => bail!("unknown auth mode {auth_mode}"),
     };
     // Invoke the host function. The user errors should normally be captured in `invoke_hf_result.invoke_result` and
     // this should return Err result for misconfigured ledger.
@@ -294,6 +309,12 @@ fn preflight_invoke_hf_op_or_maybe_panic(
     ))
 }
 
+/// Returns the latest ledger protocol version supported by the embedded Soroban host.
+#[no_mangle]
+pub extern "C" fn preflight_max_supported_protocol_version() -> u32 {
+    soroban_env_host::meta::get_ledger_protocol_version(soroban_env_host::meta::INTERFACE_VERSION)
+}
+
 #[no_mangle]
 pub extern "C" fn preflight_footprint_ttl_op(
     handle: libc::uintptr_t, // Go Handle to forward to SnapshotSourceGet and SnapshotSourceHas
@@ -1,16 +1,25 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	supportlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/storage"
 	goxdr "github.com/stellar/go/xdr"
 
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/config"
 	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/daemon"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/db"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/export"
+	"github.com/stellar/soroban-rpc/cmd/soroban-rpc/internal/methods"
 )
 
 func main() {
@@ -75,8 +84,290 @@ func main() {
 		},
 	}
 
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the configuration",
+	}
+	validateConfigCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the configuration (config file, environment variables and flags) and check the resources it references, without starting the server",
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := cfg.SetValues(os.LookupEnv); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			issues := daemon.CheckConfig(context.Background(), &cfg)
+			for _, issue := range issues {
+				fmt.Fprintln(os.Stderr, issue.String())
+			}
+			if config.HasErrors(issues) {
+				fmt.Fprintln(os.Stderr, "the configuration is invalid")
+				os.Exit(1)
+			}
+			//nolint:forbidigo
+			fmt.Println("the configuration is valid")
+		},
+	}
+	configCmd.AddCommand(validateConfigCmd)
+
+	dbCmd := &cobra.Command{
+		Use:   "db",
+		Short: "Inspect and back up the database",
+	}
+	verifyDBCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check the integrity of the database, exiting with status 1 when problems are found (and 2 when the check cannot run)",
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := cfg.SetValues(os.LookupEnv); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(2)
+			}
+			// the database may be in use (by a daemon of another version), it must be left untouched
+			dbConn, err := db.OpenSQLiteDBReadOnly(cfg.SQLiteDBPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "could not open database: %v\n", err)
+				os.Exit(2)
+			}
+			report, err := db.Verify(context.Background(), dbConn, cfg.NetworkPassphrase)
+			_ = dbConn.Close()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "could not verify database: %v\n", err)
+				os.Exit(2)
+			}
+			//nolint:forbidigo
+			fmt.Printf("ledgers: %d [%d, %d]\ntransactions: %d\nproblems: %d\n",
+				report.Ledgers, report.FirstLedger, report.LastLedger, report.Transactions, report.ProblemCount)
+			for _, problem := range report.Problems {
+				//nolint:forbidigo
+				fmt.Printf("* %s\n", problem)
+			}
+			if report.ProblemCount > len(report.Problems) {
+				//nolint:forbidigo
+				fmt.Printf("* ... and %d more\n", report.ProblemCount-len(report.Problems))
+			}
+			if report.ProblemCount > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+	backupDBCmd := &cobra.Command{
+		Use:   "backup <target>",
+		Short: "Back up the database (while it's in use) to a file path or an object storage URL (e.g. s3://bucket/soroban-rpc.sqlite)",
+		Args:  cobra.ExactArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			if err := cfg.SetValues(os.LookupEnv); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			// backing up must not modify the database (which may be in use)
+			dbConn, err := db.OpenSQLiteDBReadOnly(cfg.SQLiteDBPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "could not open database: %v\n", err)
+				os.Exit(1)
+			}
+			err = db.BackupTo(context.Background(), dbConn, args[0], storage.ConnectOptions{UserAgent: cfg.HistoryArchiveUserAgent})
+			_ = dbConn.Close()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "could not back up database: %v\n", err)
+				os.Exit(1)
+			}
+			//nolint:forbidigo
+			fmt.Printf("backed up the database to %s\n", args[0])
+		},
+	}
+	dbCmd.AddCommand(verifyDBCmd)
+	dbCmd.AddCommand(backupDBCmd)
+
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the events or the transactions of a ledger range to a CSV or Parquet file",
+	}
+	var (
+		exportOptions     export.Options
+		exportOutput      string
+		exportEventType   string
+		exportContractIDs []string
+		exportTopics      []string
+	)
+	addExportCommand := func(use, short string, run func(context.Context, db.LedgerReader, string, export.Options, io.Writer) (int, error)) {
+		cmd := &cobra.Command{
+			Use:   use,
+			Short: short,
+			Run: func(_ *cobra.Command, _ []string) {
+				if err := cfg.SetValues(os.LookupEnv); err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+				if len(exportContractIDs) > 0 || len(exportTopics) > 0 || exportEventType != "" {
+					filter, err := export.NewFilter(exportEventType, exportContractIDs, exportTopics)
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "invalid filter: %v\n", err)
+						os.Exit(1)
+					}
+					exportOptions.Filters = []methods.EventFilter{filter}
+				}
+				// the database is read as is, its schema must be the one this version reads
+				dbConn, err := db.OpenSQLiteDBReadOnly(cfg.SQLiteDBPath)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "could not open database: %v\n", err)
+					os.Exit(1)
+				}
+				if err := db.CheckSchema(context.Background(), dbConn); err != nil {
+					_ = dbConn.Close()
+					fmt.Fprintf(os.Stderr, "cannot export from the database: %v\n", err)
+					os.Exit(1)
+				}
+				out := os.Stdout
+				if exportOutput != "-" {
+					if out, err = os.Create(exportOutput); err != nil {
+						_ = dbConn.Close()
+						fmt.Fprintf(os.Stderr, "could not create output file: %v\n", err)
+						os.Exit(1)
+					}
+				}
+				count, err := run(context.Background(), db.NewLedgerReader(dbConn), cfg.NetworkPassphrase, exportOptions, out)
+				_ = dbConn.Close()
+				if closeErr := out.Close(); err == nil {
+					err = closeErr
+				}
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "could not export %s: %v\n", use, err)
+					os.Exit(1)
+				}
+				fmt.Fprintf(os.Stderr, "exported %d %s\n", count, use)
+			},
+		}
+		cmd.Flags().Uint32Var(&exportOptions.StartLedger, "start-ledger", 0, "first ledger of the exported range")
+		cmd.Flags().Uint32Var(&exportOptions.EndLedger, "end-ledger", 0, "last ledger of the exported range (included)")
+		cmd.Flags().StringVar(&exportOptions.Format, "format", export.FormatCSV, "output format (csv or parquet)")
+		cmd.Flags().StringVar(&exportOutput, "output", "-", "output file (- for the standard output)")
+		cmd.Flags().StringVar(&exportEventType, "event-type", "", "only select the events of the (comma-separated) types: system, contract or diagnostic")
+		cmd.Flags().StringSliceVar(&exportContractIDs, "contract-id", nil, "only select the events of the contracts (can be repeated)")
+		cmd.Flags().StringArrayVar(&exportTopics, "topic", nil,
+			"only select the events matching the topic, made of comma-separated segments which are either * or base64-encoded ScVals (can be repeated)")
+		_ = cmd.MarkFlagRequired("start-ledger")
+		_ = cmd.MarkFlagRequired("end-ledger")
+		exportCmd.AddCommand(cmd)
+	}
+	addExportCommand("events", "Export the events (matching the filters, if any) of the successful transactions", export.Events)
+	addExportCommand("transactions", "Export the transactions (which must have events matching the filters, if any)", export.Transactions)
+
+	var replayStart, replayEnd uint32
+	replayCmd := &cobra.Command{
+		Use: "replay",
+		Short: "Ingest a ledger range into the database (empty, or whose ledgers are continued by the range) " +
+			"and exit, without serving requests",
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := cfg.SetValues(os.LookupEnv); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			if err := cfg.Validate(); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			err := daemon.Replay(ctx, &cfg, supportlog.New(), replayStart, replayEnd)
+			stop()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "could not replay ledgers: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+	replayCmd.Flags().Uint32Var(&replayStart, "start-ledger", 0, "first ledger of the replayed range")
+	replayCmd.Flags().Uint32Var(&replayEnd, "end-ledger", 0, "last ledger of the replayed range (included)")
+	_ = replayCmd.MarkFlagRequired("start-ledger")
+	_ = replayCmd.MarkFlagRequired("end-ledger")
+
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Inspect, apply and undo the database schema and data migrations",
+	}
+	// openMigrationDB opens the database without applying the pending migrations
+	openMigrationDB := func() *db.DB {
+		if err := cfg.SetValues(os.LookupEnv); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		dbConn, err := db.OpenSQLiteDBWithoutMigrations(cfg.SQLiteDBPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not open database: %v\n", err)
+			os.Exit(1)
+		}
+		return dbConn
+	}
+	migrateStatusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Print the status of the migrations",
+		Run: func(_ *cobra.Command, _ []string) {
+			dbConn := openMigrationDB()
+			statuses, err := db.MigrationStatuses(context.Background(), dbConn)
+			_ = dbConn.Close()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "could not obtain the migration statuses: %v\n", err)
+				os.Exit(1)
+			}
+			for _, status := range statuses {
+				state := "pending"
+				switch {
+				case status.AppliedAt != nil:
+					state = "applied at " + status.AppliedAt.UTC().Format(time.RFC3339)
+				case status.Applied:
+					state = "applied"
+				case status.LastMigratedLedger != 0:
+					state = fmt.Sprintf("interrupted after ledger %d", status.LastMigratedLedger)
+				}
+				//nolint:forbidigo
+				fmt.Printf("%-6s %-24s %s\n", status.Kind, status.ID, state)
+			}
+		},
+	}
+	migrateUpCmd := &cobra.Command{
+		Use:   "up",
+		Short: "Apply the pending migrations (resuming the interrupted data migrations)",
+		Run: func(_ *cobra.Command, _ []string) {
+			dbConn := openMigrationDB()
+			ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+			applied, err := db.MigrateUp(ctx, supportlog.New(), dbConn, &cfg)
+			stop()
+			_ = dbConn.Close()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "could not apply migrations: %v\n", err)
+				os.Exit(1)
+			}
+			//nolint:forbidigo
+			fmt.Printf("applied %d schema migrations and the pending data migrations\n", applied)
+		},
+	}
+	var migrateDownCount int
+	migrateDownCmd := &cobra.Command{
+		Use:   "down",
+		Short: "Undo the latest schema migrations (together with the data migrations depending on them)",
+		Run: func(_ *cobra.Command, _ []string) {
+			dbConn := openMigrationDB()
+			undone, err := db.MigrateDown(context.Background(), dbConn, migrateDownCount)
+			_ = dbConn.Close()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "could not undo migrations: %v\n", err)
+				os.Exit(1)
+			}
+			//nolint:forbidigo
+			fmt.Printf("undid %d schema migrations\n", undone)
+		},
+	}
+	migrateDownCmd.Flags().IntVar(&migrateDownCount, "count", 1, "number of schema migrations to undo")
+	migrateCmd.AddCommand(migrateStatusCmd)
+	migrateCmd.AddCommand(migrateUpCmd)
+	migrateCmd.AddCommand(migrateDownCmd)
+
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(genConfigFileCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(dbCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(replayCmd)
+	rootCmd.AddCommand(migrateCmd)
 
 	if err := cfg.AddFlags(rootCmd); err != nil {
 		fmt.Fprintf(os.Stderr, "could not parse config options: %v\n", err)
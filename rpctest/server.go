@@ -0,0 +1,460 @@
+// Package rpctest runs an in-process fake soroban-rpc server with programmable
+// responses, for unit-testing SDKs and applications without running captive core.
+//
+// The server answers every method with canned results (set through the Set* and Add*
+// methods) and records the requests it receives. Any method can also be programmed
+// with a custom handler (see Handle).
+package rpctest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/jhttp"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+
+	"github.com/stellar/soroban-rpc/client"
+)
+
+const (
+	// StandaloneNetworkPassphrase is the passphrase of the network of the server (unless set otherwise)
+	StandaloneNetworkPassphrase = "Standalone Network ; February 2017"
+
+	defaultEventsLimit = 100
+)
+
+// HandlerFunc handles the (JSON-encoded) params of a request. Returning a *jrpc2.Error
+// sets the code of the JSON-RPC error sent to the client.
+type HandlerFunc func(ctx context.Context, params json.RawMessage) (any, error)
+
+// Server is a fake soroban-rpc server. It's safe for concurrent use.
+type Server struct {
+	httpServer *httptest.Server
+	bridge     jhttp.Bridge
+
+	defaults map[string]HandlerFunc
+
+	mu                  sync.Mutex
+	handlers            map[string]HandlerFunc
+	requests            map[string][]json.RawMessage
+	ledgerRange         client.LedgerRangeResponse
+	health              client.GetHealthResponse
+	network             client.GetNetworkResponse
+	versionInfo         client.GetVersionInfoResponse
+	latestLedger        client.GetLatestLedgerResponse
+	feeStats            client.GetFeeStatsResponse
+	ledgerEntries       map[string]client.LedgerEntryResult
+	transactions        map[string]client.GetTransactionResponse
+	events              []client.EventInfo
+	sendTransaction     *client.SendTransactionResponse
+	simulateTransaction *client.SimulateTransactionResponse
+}
+
+// NewServer starts a server of the standalone network, whose ledger range is [1, 1]
+func NewServer() *Server {
+	s := &Server{
+		handlers:      map[string]HandlerFunc{},
+		requests:      map[string][]json.RawMessage{},
+		ledgerEntries: map[string]client.LedgerEntryResult{},
+		transactions:  map[string]client.GetTransactionResponse{},
+		health:        client.GetHealthResponse{Status: "healthy"},
+		network: client.GetNetworkResponse{
+			Passphrase:      StandaloneNetworkPassphrase,
+			ProtocolVersion: 21,
+		},
+		versionInfo: client.GetVersionInfoResponse{
+			Version:         "rpctest",
+			ProtocolVersion: 21,
+			Passphrase:      StandaloneNetworkPassphrase,
+		},
+		latestLedger: client.GetLatestLedgerResponse{
+			ProtocolVersion: 21,
+			Sequence:        1,
+		},
+	}
+	s.SetLedgerRange(client.LedgerRangeResponse{LatestLedger: 1, OldestLedger: 1})
+	s.defaults = s.defaultHandlers()
+	s.bridge = jhttp.NewBridge(s, nil)
+	s.httpServer = httptest.NewServer(s.bridge)
+	return s
+}
+
+// URL is the endpoint of the server, to be used by clients
+func (s *Server) URL() string {
+	return s.httpServer.URL
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.httpServer.Close()
+	_ = s.bridge.Close()
+}
+
+// Assign implements jrpc2.Assigner, recording the requests
+func (s *Server) Assign(_ context.Context, method string) jrpc2.Handler {
+	s.mu.Lock()
+	custom, ok := s.handlers[method]
+	s.mu.Unlock()
+	if !ok {
+		if custom, ok = s.defaults[method]; !ok {
+			return nil
+		}
+	}
+	return func(ctx context.Context, request *jrpc2.Request) (any, error) {
+		params := json.RawMessage(request.ParamString())
+		s.mu.Lock()
+		s.requests[method] = append(s.requests[method], params)
+		s.mu.Unlock()
+		return custom(ctx, params)
+	}
+}
+
+// Handle programs the method with a custom handler, replacing its canned responses
+func (s *Server) Handle(method string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = handler
+}
+
+// Requests returns the params of the requests received by the method, in arrival order
+func (s *Server) Requests(method string) []json.RawMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]json.RawMessage(nil), s.requests[method]...)
+}
+
+// SetLedgerRange sets the ledgers held by the server, reported by all the responses
+// with a ledger range (unless the canned response sets its own)
+func (s *Server) SetLedgerRange(ledgerRange client.LedgerRangeResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ledgerRange = ledgerRange
+	s.latestLedger.Sequence = ledgerRange.LatestLedger
+}
+
+func (s *Server) SetHealth(response client.GetHealthResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.health = response
+}
+
+func (s *Server) SetNetwork(response client.GetNetworkResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.network = response
+}
+
+func (s *Server) SetVersionInfo(response client.GetVersionInfoResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.versionInfo = response
+}
+
+func (s *Server) SetLatestLedger(response client.GetLatestLedgerResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latestLedger = response
+}
+
+func (s *Server) SetFeeStats(response client.GetFeeStatsResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.feeStats = response
+}
+
+// AddLedgerEntry makes the entry available through getLedgerEntries (and getLedgerEntry),
+// looked up by entry.Key
+func (s *Server) AddLedgerEntry(entry client.LedgerEntryResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ledgerEntries[entry.Key] = entry
+}
+
+// AddTransaction makes the transaction available through getTransaction and getTransactions
+// (in ledger and application order), other transactions are reported as not found.
+// Fee-bump transactions can also be looked up by response.FeeBumpDetails.InnerTransactionHash.
+func (s *Server) AddTransaction(hash string, response client.GetTransactionResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transactions[hash] = response
+}
+
+// AddEvents appends events to the stream served by getEvents (which only applies
+// the start ledger, the event types, the contract IDs and the pagination).
+// The events are served in ID order.
+func (s *Server) AddEvents(events ...client.EventInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, events...)
+	sort.SliceStable(s.events, func(i, j int) bool {
+		return s.events[i].ID < s.events[j].ID
+	})
+}
+
+// SetSendTransactionResponse sets the response of sendTransaction. By default the
+// transactions are reported as PENDING, with the hash of their envelope.
+func (s *Server) SetSendTransactionResponse(response client.SendTransactionResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sendTransaction = &response
+}
+
+// SetSimulateTransactionResponse sets the response of simulateTransaction,
+// which reports an error until set.
+func (s *Server) SetSimulateTransactionResponse(response client.SimulateTransactionResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.simulateTransaction = &response
+}
+
+func invalidParams(err error) error {
+	return &jrpc2.Error{Code: jrpc2.InvalidParams, Message: err.Error()}
+}
+
+// rangeUnlessSet returns the ledger range of the server, unless the ledger range of a response was set
+func (s *Server) rangeUnlessSet(ledgerRange client.LedgerRangeResponse) client.LedgerRangeResponse {
+	if ledgerRange == (client.LedgerRangeResponse{}) {
+		return s.ledgerRange
+	}
+	return ledgerRange
+}
+
+// cannedResponse returns a handler responding with a copy of the value obtained under the lock
+func (s *Server) cannedResponse(get func() any) HandlerFunc {
+	return func(context.Context, json.RawMessage) (any, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return get(), nil
+	}
+}
+
+func (s *Server) defaultHandlers() map[string]HandlerFunc {
+	return map[string]HandlerFunc{
+		"getHealth": s.cannedResponse(func() any {
+			response := s.health
+			response.LedgerRangeResponse = s.rangeUnlessSet(response.LedgerRangeResponse)
+			return response
+		}),
+		"getNetwork":     s.cannedResponse(func() any { return s.network }),
+		"getVersionInfo": s.cannedResponse(func() any { return s.versionInfo }),
+		"getLatestLedger": s.cannedResponse(func() any {
+			response := s.latestLedger
+			response.LedgerRangeResponse = s.rangeUnlessSet(response.LedgerRangeResponse)
+			return response
+		}),
+		"getFeeStats": s.cannedResponse(func() any {
+			response := s.feeStats
+			response.LedgerRangeResponse = s.rangeUnlessSet(response.LedgerRangeResponse)
+			return response
+		}),
+		"getLedgerEntries":    s.getLedgerEntries,
+		"getLedgerEntry":      s.getLedgerEntry,
+		"getTransaction":      s.getTransaction,
+		"getTransactions":     s.getTransactions,
+		"getEvents":           s.getEvents,
+		"sendTransaction":     s.sendTransactionHandler,
+		"simulateTransaction": s.simulateTransactionHandler,
+	}
+}
+
+func (s *Server) getLedgerEntries(_ context.Context, params json.RawMessage) (any, error) {
+	var request client.GetLedgerEntriesRequest
+	if err := json.Unmarshal(params, &request); err != nil {
+		return nil, invalidParams(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	response := client.GetLedgerEntriesResponse{
+		Entries:             []client.LedgerEntryResult{},
+		LedgerRangeResponse: s.ledgerRange,
+	}
+	for _, key := range request.Keys {
+		if entry, ok := s.ledgerEntries[key]; ok {
+			response.Entries = append(response.Entries, entry)
+		}
+	}
+	return response, nil
+}
+
+func (s *Server) getLedgerEntry(_ context.Context, params json.RawMessage) (any, error) {
+	var request client.GetLedgerEntryRequest
+	if err := json.Unmarshal(params, &request); err != nil {
+		return nil, invalidParams(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.ledgerEntries[request.Key]
+	if !ok {
+		return nil, &jrpc2.Error{Code: jrpc2.InvalidRequest, Message: "not found"}
+	}
+	return client.GetLedgerEntryResponse{
+		XDR:                 entry.XDR,
+		LastModifiedLedger:  entry.LastModifiedLedger,
+		LedgerRangeResponse: s.ledgerRange,
+		LiveUntilLedgerSeq:  entry.LiveUntilLedgerSeq,
+	}, nil
+}
+
+func (s *Server) getTransaction(_ context.Context, params json.RawMessage) (any, error) {
+	var request client.GetTransactionRequest
+	if err := json.Unmarshal(params, &request); err != nil {
+		return nil, invalidParams(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	response, ok := s.transactions[request.Hash]
+	if !ok {
+		response = client.GetTransactionResponse{Status: client.TransactionStatusNotFound}
+		for _, tx := range s.transactions {
+			if tx.FeeBumpDetails != nil && tx.FeeBumpDetails.InnerTransactionHash == request.Hash {
+				response = tx
+				break
+			}
+		}
+	}
+	response.LedgerRangeResponse = s.rangeUnlessSet(response.LedgerRangeResponse)
+	return response, nil
+}
+
+func (s *Server) getTransactions(_ context.Context, params json.RawMessage) (any, error) {
+	var request client.GetTransactionsRequest
+	if err := json.Unmarshal(params, &request); err != nil {
+		return nil, invalidParams(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var transactions []client.TransactionInfo
+	for _, tx := range s.transactions {
+		if tx.Ledger < request.StartLedger {
+			continue
+		}
+		transactions = append(transactions, client.TransactionInfo{
+			Status:              tx.Status,
+			ApplicationOrder:    tx.ApplicationOrder,
+			FeeBump:             tx.FeeBump,
+			EnvelopeXdr:         tx.EnvelopeXdr,
+			ResultXdr:           tx.ResultXdr,
+			ResultMetaXdr:       tx.ResultMetaXdr,
+			DiagnosticEventsXDR: tx.DiagnosticEventsXDR,
+			Ledger:              tx.Ledger,
+			LedgerCloseTime:     tx.LedgerCloseTime,
+		})
+	}
+	sort.Slice(transactions, func(i, j int) bool {
+		if transactions[i].Ledger != transactions[j].Ledger {
+			return transactions[i].Ledger < transactions[j].Ledger
+		}
+		return transactions[i].ApplicationOrder < transactions[j].ApplicationOrder
+	})
+	return client.GetTransactionsResponse{
+		Transactions:               append([]client.TransactionInfo{}, transactions...),
+		LedgerRangeResponse:        s.ledgerRange,
+		LatestLedgerCloseTimestamp: s.ledgerRange.LatestLedgerCloseTime,
+		OldestLedgerCloseTimestamp: s.ledgerRange.OldestLedgerCloseTime,
+	}, nil
+}
+
+func eventMatches(event client.EventInfo, filters []client.EventFilter) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, filter := range filters {
+		if filter.EventType != "" && !slices.Contains(strings.Split(filter.EventType, ","), event.EventType) {
+			continue
+		}
+		if len(filter.ContractIDs) > 0 && !slices.Contains(filter.ContractIDs, event.ContractID) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func (s *Server) getEvents(_ context.Context, params json.RawMessage) (any, error) {
+	var request client.GetEventsRequest
+	if err := json.Unmarshal(params, &request); err != nil {
+		return nil, invalidParams(err)
+	}
+	limit := uint(defaultEventsLimit)
+	cursor := ""
+	if request.Pagination != nil {
+		cursor = request.Pagination.Cursor
+		if request.Pagination.Limit != 0 {
+			limit = request.Pagination.Limit
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	response := client.GetEventsResponse{
+		Events:              []client.EventInfo{},
+		LedgerRangeResponse: s.ledgerRange,
+	}
+	for _, event := range s.events {
+		if (cursor != "" && event.ID <= cursor) || uint32(event.Ledger) < request.StartLedger {
+			continue
+		}
+		if request.ContractEventsOnly &&
+			(event.EventType != client.EventTypeContract || !event.InSuccessfulContractCall) {
+			continue
+		}
+		if !eventMatches(event, request.Filters) {
+			continue
+		}
+		// the total count is exact, since all the events are held in memory
+		response.EstimatedTotalCount++
+		if uint(len(response.Events)) == limit {
+			response.HasMore = true
+			continue
+		}
+		response.Events = append(response.Events, event)
+		response.Cursor = event.ID
+	}
+	return response, nil
+}
+
+func (s *Server) sendTransactionHandler(_ context.Context, params json.RawMessage) (any, error) {
+	var request client.SendTransactionRequest
+	if err := json.Unmarshal(params, &request); err != nil {
+		return nil, invalidParams(err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sendTransaction != nil {
+		return *s.sendTransaction, nil
+	}
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(request.Transaction, &envelope); err != nil {
+		return nil, invalidParams(fmt.Errorf("invalid transaction envelope: %w", err))
+	}
+	hash, err := network.HashTransactionInEnvelope(envelope, s.network.Passphrase)
+	if err != nil {
+		return nil, invalidParams(err)
+	}
+	return client.SendTransactionResponse{
+		Status:                "PENDING",
+		Hash:                  fmt.Sprintf("%x", hash),
+		LatestLedger:          s.ledgerRange.LatestLedger,
+		LatestLedgerCloseTime: s.ledgerRange.LatestLedgerCloseTime,
+	}, nil
+}
+
+func (s *Server) simulateTransactionHandler(context.Context, json.RawMessage) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.simulateTransaction == nil {
+		return client.SimulateTransactionResponse{
+			Error:        "rpctest: no simulation response was set",
+			LatestLedger: s.ledgerRange.LatestLedger,
+		}, nil
+	}
+	return *s.simulateTransaction, nil
+}
@@ -0,0 +1,174 @@
+package rpctest
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+
+	"github.com/stellar/soroban-rpc/client"
+)
+
+func newTestClient(t *testing.T) (*Server, *client.Client) {
+	server := NewServer()
+	t.Cleanup(server.Close)
+	c := client.NewClient(server.URL(), &client.Options{MaxRetries: -1})
+	t.Cleanup(func() { _ = c.Close() })
+	return server, c
+}
+
+func TestServerDefaults(t *testing.T) {
+	server, c := newTestClient(t)
+	ctx := context.Background()
+	server.SetLedgerRange(client.LedgerRangeResponse{LatestLedger: 10, OldestLedger: 2})
+
+	health, err := c.GetHealth(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "healthy", health.Status)
+	assert.Equal(t, uint32(10), health.LatestLedger)
+
+	networkResponse, err := c.GetNetwork(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, StandaloneNetworkPassphrase, networkResponse.Passphrase)
+
+	latestLedger, err := c.GetLatestLedger(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(10), latestLedger.Sequence)
+	assert.Equal(t, uint32(2), latestLedger.OldestLedger)
+
+	simulation, err := c.SimulateTransaction(ctx, client.SimulateTransactionRequest{Transaction: "AAAA"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, simulation.Error)
+}
+
+func TestServerTransactions(t *testing.T) {
+	server, c := newTestClient(t)
+	ctx := context.Background()
+
+	source := keypair.MustRandom()
+	account := txnbuild.NewSimpleAccount(source.Address(), 1)
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount: &account,
+		Operations:    []txnbuild.Operation{&txnbuild.BumpSequence{BumpTo: 10}},
+		BaseFee:       txnbuild.MinBaseFee,
+		Preconditions: txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+	})
+	require.NoError(t, err)
+	tx, err = tx.Sign(StandaloneNetworkPassphrase, source)
+	require.NoError(t, err)
+	envelope, err := tx.Base64()
+	require.NoError(t, err)
+	hash, err := tx.HashHex(StandaloneNetworkPassphrase)
+	require.NoError(t, err)
+
+	sent, err := c.SendTransaction(ctx, client.SendTransactionRequest{Transaction: envelope})
+	require.NoError(t, err)
+	assert.Equal(t, "PENDING", sent.Status)
+	assert.Equal(t, hash, sent.Hash)
+	require.Len(t, server.Requests("sendTransaction"), 1)
+	var request client.SendTransactionRequest
+	require.NoError(t, json.Unmarshal(server.Requests("sendTransaction")[0], &request))
+	assert.Equal(t, envelope, request.Transaction)
+
+	// the transaction is included after a while
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		server.AddTransaction(hash, client.GetTransactionResponse{
+			Status:      client.TransactionStatusSuccess,
+			Ledger:      5,
+			EnvelopeXdr: envelope,
+		})
+	}()
+	found, err := c.PollTransaction(ctx, hash, 5*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, client.TransactionStatusSuccess, found.Status)
+	assert.Equal(t, uint32(5), found.Ledger)
+	assert.Equal(t, uint32(1), found.LatestLedger)
+
+	transactions, err := c.GetTransactions(ctx, client.GetTransactionsRequest{StartLedger: 1})
+	require.NoError(t, err)
+	require.Len(t, transactions.Transactions, 1)
+	assert.Equal(t, envelope, transactions.Transactions[0].EnvelopeXdr)
+
+	// fee-bumps can be looked up by their inner hash
+	innerHash := strings.Repeat("ab", 32)
+	server.AddTransaction(strings.Repeat("cd", 32), client.GetTransactionResponse{
+		Status:         client.TransactionStatusSuccess,
+		FeeBump:        true,
+		FeeBumpDetails: &client.FeeBumpDetails{InnerTransactionHash: innerHash},
+	})
+	found, err = c.GetTransaction(ctx, client.GetTransactionRequest{Hash: innerHash})
+	require.NoError(t, err)
+	assert.True(t, found.FeeBump)
+
+	_, err = c.SendTransaction(ctx, client.SendTransactionRequest{Transaction: "invalid"})
+	var rpcErr *jrpc2.Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, jrpc2.InvalidParams, rpcErr.Code)
+
+	// the hash depends on the passphrase of the network
+	server.SetNetwork(client.GetNetworkResponse{Passphrase: network.TestNetworkPassphrase})
+	sent, err = c.SendTransaction(ctx, client.SendTransactionRequest{Transaction: envelope})
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, sent.Hash)
+}
+
+func TestServerEvents(t *testing.T) {
+	server, c := newTestClient(t)
+	ctx := context.Background()
+	server.AddEvents(
+		client.EventInfo{ID: "0000000004294971392-0000000000", Ledger: 1, EventType: client.EventTypeContract, ContractID: "C1"},
+		client.EventInfo{ID: "0000000008589938688-0000000000", Ledger: 2, EventType: client.EventTypeSystem, ContractID: "C1"},
+		client.EventInfo{ID: "0000000008589938688-0000000001", Ledger: 2, EventType: client.EventTypeContract, ContractID: "C2"},
+	)
+
+	response, err := c.GetEvents(ctx, client.GetEventsRequest{
+		StartLedger: 1,
+		Filters:     []client.EventFilter{{EventType: "contract"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, response.Events, 2)
+	assert.Equal(t, "C1", response.Events[0].ContractID)
+	assert.Equal(t, "C2", response.Events[1].ContractID)
+	assert.False(t, response.HasMore)
+
+	response, err = c.GetEvents(ctx, client.GetEventsRequest{
+		StartLedger: 1,
+		Filters:     []client.EventFilter{{EventType: "contract"}},
+		Pagination:  &client.PaginationOptions{Limit: 1},
+	})
+	require.NoError(t, err)
+	require.Len(t, response.Events, 1)
+	assert.True(t, response.HasMore)
+	assert.Equal(t, uint64(2), response.EstimatedTotalCount)
+	assert.Equal(t, "0000000004294971392-0000000000", response.Cursor)
+
+	response, err = c.GetEvents(ctx, client.GetEventsRequest{
+		Filters:    []client.EventFilter{{ContractIDs: []string{"C1"}}},
+		Pagination: &client.PaginationOptions{Cursor: "0000000004294971392-0000000000", Limit: 1},
+	})
+	require.NoError(t, err)
+	require.Len(t, response.Events, 1)
+	assert.Equal(t, client.EventTypeSystem, response.Events[0].EventType)
+}
+
+func TestServerCustomHandler(t *testing.T) {
+	server, c := newTestClient(t)
+	server.Handle("getFeeStats", func(context.Context, json.RawMessage) (any, error) {
+		return nil, &jrpc2.Error{Code: client.NodeDegradedCode, Message: "degraded"}
+	})
+	_, err := c.GetFeeStats(context.Background())
+	var rpcErr *jrpc2.Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, jrpc2.Code(client.NodeDegradedCode), rpcErr.Code)
+	assert.Len(t, server.Requests("getFeeStats"), 1)
+}